@@ -0,0 +1,157 @@
+// Command debug-mouse drives the stealth mouse over a local test page with the configured
+// mouse settings and dumps the path the page recorded to JSON, to plot and eyeball:
+//
+//	go run ./cmd/debug-mouse --clicks 12 --out mouse_path.json
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+	"github.com/Tanukumar01/linkedin-automation/pkg/browser"
+)
+
+//go:embed testpage.html
+var testPage []byte
+
+// targets is how many buttons the test page has to click
+const targets = 6
+
+// Recording is what the test page saw, with the settings that produced it
+type Recording struct {
+	Settings config.MouseConfig   `json:"settings"`
+	Moves    []Move               `json:"moves"`
+	Clicks   []Click              `json:"clicks"`
+	Summary  stealth.MouseSummary `json:"summary"`
+}
+
+// Move is one mousemove event, t milliseconds after the page loaded
+type Move struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	T int64   `json:"t"`
+}
+
+// Click is one click event and the id of the element it landed on
+type Click struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	T      int64   `json:"t"`
+	Target string  `json:"target"`
+}
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	configFlag := flag.String("config", "configs/config.yaml", "config file to take the stealth.mouse settings from")
+	clicksFlag := flag.Int("clicks", 12, "buttons to click")
+	idleFlag := flag.Int("idle", 3, "idle movements between clicks")
+	outFlag := flag.String("out", "mouse_path.json", "file to write the recorded path to")
+	headlessFlag := flag.Bool("headless", true, "run the browser headless")
+	flag.Parse()
+
+	logger.InitLogger("info", "console", "stdout", logger.Rotation{})
+
+	cfg, err := config.LoadConfig(*configFlag)
+	if err != nil {
+		return err
+	}
+	settings := cfg.Stealth.Mouse
+
+	dir, err := os.MkdirTemp("", "linkedin-bot-debug-mouse-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	pagePath := filepath.Join(dir, "testpage.html")
+	if err := os.WriteFile(pagePath, testPage, 0644); err != nil {
+		return fmt.Errorf("failed to write test page: %w", err)
+	}
+
+	b, err := browser.NewBrowser(*headlessFlag, filepath.Join(dir, "browser-data"), cfg.Browser.TimeoutSeconds, browser.LaunchOptions{})
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	var userAgent string
+	if len(cfg.Browser.UserAgents) > 0 {
+		userAgent = cfg.Browser.UserAgents[0]
+	}
+	page, err := b.NewPage(userAgent)
+	if err != nil {
+		return err
+	}
+	if err := page.SetViewport(&proto.EmulationSetDeviceMetricsOverride{Width: 1280, Height: 800, DeviceScaleFactor: 1}); err != nil {
+		return fmt.Errorf("failed to set viewport: %w", err)
+	}
+	if err := page.Navigate("file://" + filepath.ToSlash(pagePath)); err != nil {
+		return fmt.Errorf("failed to open test page: %w", err)
+	}
+	if err := page.WaitLoad(); err != nil {
+		return fmt.Errorf("failed to load test page: %w", err)
+	}
+
+	mouse := stealth.NewMouseMover(page, settings.BezierPoints, settings.SpeedVariation, settings.OvershootProbability, settings.MicroCorrectionProbability)
+	metrics := stealth.NewSessionMetrics()
+	mouse.SetMetrics(metrics)
+
+	for i := 0; i < *clicksFlag; i++ {
+		for j := 0; j < *idleFlag; j++ {
+			if err := mouse.RandomIdleMovement(); err != nil {
+				return fmt.Errorf("failed to move idly: %w", err)
+			}
+		}
+		button, err := page.Element(fmt.Sprintf("#target-%d", i%targets))
+		if err != nil {
+			return fmt.Errorf("failed to find button: %w", err)
+		}
+		if err := mouse.ClickElement(button); err != nil {
+			return fmt.Errorf("failed to click button: %w", err)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	recorded, err := page.Eval(`() => JSON.stringify(window.recorded)`)
+	if err != nil {
+		return fmt.Errorf("failed to read recorded path: %w", err)
+	}
+	rec := Recording{Settings: settings, Summary: metrics.Summary().Mouse}
+	if err := json.Unmarshal([]byte(recorded.Value.Str()), &rec); err != nil {
+		return fmt.Errorf("failed to parse recorded path: %w", err)
+	}
+
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode recorded path: %w", err)
+	}
+	if err := os.WriteFile(*outFlag, data, 0644); err != nil {
+		return fmt.Errorf("failed to write recorded path: %w", err)
+	}
+
+	missed := 0
+	for _, c := range rec.Clicks {
+		if c.Target == "" {
+			missed++
+		}
+	}
+	fmt.Printf("Recorded %d moves and %d clicks (%d missed their button); mean curvature %.2f, mean move %.0fms\n",
+		len(rec.Moves), len(rec.Clicks), missed, rec.Summary.MeanCurvature, rec.Summary.MeanDurationMs)
+	fmt.Printf("Path written to %s\n", *outFlag)
+	return nil
+}
@@ -1,3 +1,7 @@
+// Command debug-profile launches the browser headless with a persistent profile directory
+// and prints its debug URL, to check that Chrome starts in this environment at all:
+//
+//	go run ./cmd/debug-profile
 package main
 
 import (
@@ -27,8 +31,8 @@ func main() {
 	fmt.Printf("Starting with user data dir: %s\n", userDataDir)
 	url, err := l.Launch()
 	if err != nil {
-		fmt.Printf("Launch failed: %v\n", err)
-		return
+		fmt.Fprintf(os.Stderr, "Launch failed: %v\n", err)
+		os.Exit(1)
 	}
 	fmt.Printf("Debug URL: %s\n", url)
 }
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/auth"
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/instance"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/notify"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
+	"github.com/Tanukumar01/linkedin-automation/internal/session"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+	"github.com/Tanukumar01/linkedin-automation/pkg/linkedin"
+)
+
+// initCommandLogger sets up logging as configured, for commands that drive the browser
+func initCommandLogger(cfg *config.Config) bool {
+	if err := logger.InitLogger(cfg.Logging.Level, cfg.Logging.Format, cfg.Logging.Output, logger.Rotation{
+		MaxSizeMB:  cfg.Logging.Rotation.MaxSizeMB,
+		MaxAgeDays: cfg.Logging.Rotation.MaxAgeDays,
+		MaxBackups: cfg.Logging.Rotation.MaxBackups,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
+		return false
+	}
+	return true
+}
+
+// accountSession is the account's browser, logged in, for a command other than the bot run
+type accountSession struct {
+	*session.Session
+	client        *linkedin.Client
+	lock          *instance.Lock
+	authenticator *auth.Authenticator
+}
+
+// openAccountSession logs in the way the bot does, unless the bot is in safe mode or
+// another instance has the account; doing names the command in those errors, e.g.
+// "pruning". On failure it returns the exit code to use.
+func openAccountSession(cfg *config.Config, db *storage.DB, doing string) (*accountSession, int) {
+	if mode, err := db.GetSafeMode(); err != nil {
+		logger.Errorf("%v", err)
+		return nil, 1
+	} else if mode != nil {
+		logger.Errorf("Not %s: %v", doing, safeModeError(mode))
+		return nil, exitSafeMode
+	}
+
+	creds, err := config.LoadCredentials()
+	if err != nil {
+		logger.Errorf("Failed to load credentials: %v", err)
+		return nil, 1
+	}
+	// The bot and this command on the same account would fight over the one session
+	lock, err := instance.Acquire(instance.Path(getDBPath(), creds.Email), creds.Email)
+	if errors.Is(err, instance.ErrLocked) {
+		logger.Errorf("Not %s: %v", doing, err)
+		return nil, exitLocked
+	}
+	if err != nil {
+		logger.Errorf("Failed to take the instance lock: %v", err)
+		return nil, 1
+	}
+	a := &accountSession{lock: lock}
+
+	secret, err := auth.CookieSecretFromEnv()
+	if err != nil {
+		logger.Errorf("Failed to load cookie encryption secret: %v", err)
+		a.Close()
+		return nil, 1
+	}
+
+	if err := selectors.Load(filepath.Join(filepath.Dir(getConfigPath()), "selectors.yaml")); err != nil {
+		logger.Errorf("Failed to load selectors: %v", err)
+		a.Close()
+		return nil, 1
+	}
+	selectors.SetRecorder(db)
+
+	b := &bot{cfg: cfg, creds: creds, lock: lock, secret: secret, db: db, notifier: notify.Nop{}}
+	if a.client, err = b.openClient(); err != nil {
+		logger.Errorf("%v", err)
+		a.Close()
+		return nil, 1
+	}
+	a.Session = a.client.Session()
+
+	if a.authenticator, err = b.login(a.client); err != nil {
+		logger.Errorf("%v", err)
+		a.Close()
+		return nil, 1
+	}
+	return a, 0
+}
+
+// Close saves the extended login, closes the browser and releases the account
+func (a *accountSession) Close() {
+	if a.authenticator != nil {
+		if err := a.authenticator.RefreshSavedSession(); err != nil {
+			logger.Warnf("%v", err)
+		}
+	}
+	if a.client != nil {
+		a.client.Close()
+	}
+	a.lock.Release()
+}
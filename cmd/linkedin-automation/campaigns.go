@@ -0,0 +1,661 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"runtime/debug"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/artifacts"
+	"github.com/Tanukumar01/linkedin-automation/internal/breaker"
+	"github.com/Tanukumar01/linkedin-automation/internal/budget"
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/connections"
+	"github.com/Tanukumar01/linkedin-automation/internal/engagement"
+	"github.com/Tanukumar01/linkedin-automation/internal/humanize"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/messaging"
+	"github.com/Tanukumar01/linkedin-automation/internal/notify"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/phase"
+	"github.com/Tanukumar01/linkedin-automation/internal/profiles"
+	"github.com/Tanukumar01/linkedin-automation/internal/profileurl"
+	"github.com/Tanukumar01/linkedin-automation/internal/report"
+	"github.com/Tanukumar01/linkedin-automation/internal/search"
+	"github.com/Tanukumar01/linkedin-automation/internal/session"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+	"github.com/Tanukumar01/linkedin-automation/pkg/browser"
+	"go.uber.org/zap"
+)
+
+// selectCampaigns returns the campaigns to run; an empty name selects all of them
+func selectCampaigns(cfg *config.Config, name string) ([]config.CampaignConfig, error) {
+	campaigns := cfg.EffectiveCampaigns()
+	if name == "" {
+		return campaigns, nil
+	}
+
+	for _, campaign := range campaigns {
+		if campaign.Name == name {
+			return []config.CampaignConfig{campaign}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown campaign: %s", name)
+}
+
+// campaignRun is what a session hands each campaign it runs: its tabs, the services the
+// campaigns share and the day's budgets
+type campaignRun struct {
+	cfg        *config.Config
+	searchTab  *session.Tab // where searches run
+	profileTab *session.Tab // where profiles are visited
+	db         *storage.DB
+	timing     *stealth.TimingController
+	scheduler  *stealth.Scheduler
+	plan       *dayPlan // one connection request per planned slot; nil sends without a plan
+	phases     *phase.Tracker
+	idle       *humanize.Humanizer
+	notifier   notify.Notifier
+	collector  *artifacts.Collector
+	safety     *breaker.Breaker
+	reviewer   *inviteReviewer // approves the first invites at the terminal; nil without --review
+
+	ensureLoggedIn func() error        // signs in again when LinkedIn puts a profile behind its sign-in wall
+	actions        *budget.DailyBudget // the day's action budget; nil for none
+	report         *report.RunReport
+}
+
+// runCampaign searches for a campaign's audience and sends up to invites connection
+// requests. It reports whether the run should stop contacting profiles altogether.
+func runCampaign(run *campaignRun, campaign *config.CampaignConfig, invites int) bool {
+	cfg, db, timing, scheduler, plan := run.cfg, run.db, run.timing, run.scheduler, run.plan
+	searchTab, profileTab, phases, safety, actions, runReport := run.searchTab, run.profileTab, run.phases, run.safety, run.actions, run.report
+	logger.Infof("Running campaign %s (budget %d)", campaign.Name, invites)
+
+	searchCfg := cfg.Search
+	searchCfg.Filters = campaign.Filters
+	searchCfg.MaxResults = campaign.MaxResults
+
+	connCfg := cfg.Connections
+	connCfg.NoteTemplates = campaign.NoteTemplates
+
+	searcher := search.NewSearcher(searchTab.Pages, &searchCfg, db, timing, searchTab.Scroller, searchTab.Clicker)
+	searcher.SetCampaign(campaign.Name)
+	searcher.SetArtifacts(run.collector)
+
+	searchBudget := phases.Get(config.PhaseSearch)
+	searcher.SetCheckpoint(func() bool { return searchBudget.Exceeded() || scheduler.SessionOver() })
+
+	connManager := connections.NewConnectionManager(profileTab.Pages, &connCfg, db, timing, profileTab.Typer, profileTab.Clicker, profileTab.Scroller)
+	connManager.SetNotifier(run.notifier)
+	connManager.SetCampaign(campaign.Name)
+	connManager.SetDailyLimitFunc(cfg.DailyConnectLimit)
+	connManager.SetLocale(campaign.Locale)
+	connManager.SetProfileReading(cfg.Stealth.ProfileReading)
+	connManager.SetArtifacts(run.collector)
+	connManager.SetSessionCheck(run.ensureLoggedIn)
+	connManager.SetActionBudget(actions)
+
+	// Profiles that can't be invited are sent an InMail instead, when enabled
+	var inMailer *messaging.MessageManager
+	if cfg.Messaging.InMail.Enabled {
+		inMailer = messaging.NewMessageManager(profileTab.Pages, &cfg.Messaging, db, timing, profileTab.Typer, profileTab.Clicker, profileTab.Scroller)
+		inMailer.SetLocale(campaign.Locale)
+		inMailer.SetArtifacts(run.collector)
+		inMailer.SetActionBudget(actions)
+	}
+
+	// Step 1: Search for profiles
+	logger.Info("Step 1: Searching for profiles...")
+	searchBudget.Start()
+	summary, err := searcher.Search()
+	searchBudget.Stop()
+	if err != nil {
+		if browser.NeedsRelaunch(err) {
+			logger.Errorf("Lost the browser during search, stopping: %v", err)
+			runReport.RecordFailure("search", "", "", err)
+			return true
+		}
+		logger.Errorf("Search failed: %v", err)
+	} else {
+		runReport.RecordSearch(campaign.Name, len(summary.Results), summary.NewProfiles)
+		logger.Infof("Search complete. Found %d total unique profiles in this session.", len(summary.Results))
+	}
+
+	if len(campaign.Sources) > 0 {
+		harvester := search.NewHarvester(searchTab.Pages, db, timing, searchTab.Scroller, searchTab.Clicker)
+		harvester.SetCampaign(campaign.Name)
+		harvester.SetArtifacts(run.collector)
+		harvester.SetCheckpoint(func() bool { return searchBudget.Exceeded() || scheduler.SessionOver() })
+		if harvest(harvester, campaign, searchBudget, runReport) {
+			return true
+		}
+	}
+
+	// Step 2: Send connection requests
+	logger.Info("Step 2: Sending connection requests...")
+	sentToday, err := db.GetCampaignRequestsCountByDate(campaign.Name, time.Now())
+	if err != nil {
+		logger.Errorf("Failed to get campaign request count: %v", err)
+		return false
+	}
+
+	remaining := invites - sentToday
+	if remaining <= 0 {
+		logger.Infof("Campaign %s has used its budget for today (%d/%d)", campaign.Name, sentToday, invites)
+		return false
+	}
+
+	connectBudget := phases.Get(config.PhaseConnect)
+	connectBudget.Start()
+	defer connectBudget.Stop()
+
+	// Waiting on the operator counts neither against the connect budget nor as active time
+	if run.reviewer != nil {
+		connManager.SetReviewer(run.reviewer, func() func() {
+			connectBudget.Stop()
+			resume := scheduler.Hold()
+			return func() {
+				resume()
+				connectBudget.Start()
+			}
+		})
+	}
+
+	// Warm prospects up first; they are invited once their like has settled
+	if cfg.Connections.PreEngage == config.PreEngageLike {
+		engager := engagement.NewEngager(cfg.Engagement, profileTab.Pages, profileTab.Scroller, profileTab.Clicker, timing, db)
+		if preEngage(engager, searcher, db, campaign.Name, remaining, prospectPolicy(cfg, campaign), connectBudget, scheduler, actions, runReport) {
+			return true
+		}
+	}
+
+	// Fetch extra prospects when some may be passed over; sending stops once the budget is used
+	fetch := remaining
+	if cfg.Connections.SkipProbability > 0 || cfg.Connections.OrganicSkipProbability > 0 {
+		fetch = remaining * 2
+	}
+
+	uncontactedProfiles, err := db.GetUncontactedProfiles(campaign.Name, fetch, prospectPolicy(cfg, campaign))
+	if err != nil {
+		logger.Errorf("Failed to get uncontacted profiles: %v", err)
+		return false
+	}
+
+	logger.Infof("Retrieved %d uncontacted profiles from database", len(uncontactedProfiles))
+
+	// Read the prospects' profiles first, so notes can use what they say
+	if cfg.Enrich.Enabled {
+		scraper := profiles.NewScraper(cfg.Enrich, profileTab.Pages, profileTab.Scroller, profileTab.Clicker, timing, db)
+		var stop bool
+		if uncontactedProfiles, stop = enrich(scraper, searcher, db, uncontactedProfiles, connectBudget, scheduler, safety, actions, runReport); stop {
+			return true
+		}
+	}
+
+	forgetful := rand.New(rand.NewSource(time.Now().UnixNano()))
+	sent := 0
+	for _, profile := range uncontactedProfiles {
+		if sent >= remaining {
+			break
+		}
+		if connectBudget.Exceeded() {
+			logger.Info("Connect time budget used, stopping connection requests")
+			return true
+		}
+		if err := scheduler.CheckSession(); err != nil {
+			logger.Infof("Stopping connection requests: %v", err)
+			return true
+		}
+
+		if forget(&cfg.Connections, db, forgetful, profile) {
+			runReport.RecordConnectionSkipped(campaign.Name, profile.ProfileURL, profile.ProfileName, "passed over at random")
+			continue
+		}
+
+		// Sales Navigator leads are contacted through their public profile
+		if ok, stop := resolveLead(searcher, db, &profile, runReport); stop {
+			return true
+		} else if !ok {
+			continue
+		}
+
+		// Wait for the next planned slot; the plan already leaves gaps between sessions
+		if plan != nil {
+			if !plan.wait() {
+				logger.Info("No connection slots left today, stopping")
+				return true
+			}
+			// The wait may have outlasted the budget
+			if connectBudget.Exceeded() {
+				logger.Info("Connect time budget used, stopping connection requests")
+				return true
+			}
+			if err := scheduler.CheckSession(); err != nil {
+				logger.Infof("Stopping connection requests: %v", err)
+				return true
+			}
+		} else if scheduler.ShouldTakeBreak() {
+			logger.Info("Taking a break...")
+			scheduler.TakeBreak()
+		}
+
+		// Every line about this profile says which one it was
+		log := logger.With(logger.ProfileFields(profile.ProfileURL, profile.ProfileName, "connect")...)
+
+		// The item stays in progress until the outcome is settled; a crash leaves it to be requeued
+		if err := db.ClaimQueueItem(profile.QueueID); err != nil {
+			log.Warnf("%v", err)
+		}
+		result, err := contact(connManager, profile, notePriority(&cfg.Connections, campaign, profile), log)
+		settleQueueItem(db, profile, result, err, retryPolicy(cfg), log)
+		if err != nil {
+			// Check if the daily, weekly or hourly limit was reached
+			if errors.Is(err, connections.ErrDailyLimitReached) || errors.Is(err, connections.ErrWeeklyLimitReached) ||
+				errors.Is(err, connections.ErrHourlyLimitReached) {
+				log.Infof("Connection limit reached, stopping: %v", err)
+				runReport.RecordRestriction(err.Error())
+				return true
+			}
+
+			// Follow-ups ahead in priority may hold back what is left of the day's actions
+			if errors.Is(err, budget.ErrExhausted) {
+				log.Infof("Stopping connection requests: %v", err)
+				runReport.RecordRestriction(err.Error())
+				return true
+			}
+
+			if errors.Is(err, pageops.ErrNavigationLimit) {
+				log.Warnf("Navigation limit reached, stopping: %v", err)
+				runReport.RecordRestriction(err.Error())
+				return true
+			}
+
+			if errors.Is(err, connections.ErrRestricted) {
+				log.Warnf("LinkedIn restricted invitations, stopping: %v", err)
+				runReport.RecordRestriction(err.Error())
+				return true
+			}
+
+			// An invite flow we can't drive shows up for every profile of the session
+			if errors.Is(err, connections.ErrUnsupportedFlow) {
+				log.Errorf("LinkedIn showed an invite flow we can't drive, stopping: %v", err)
+				runReport.RecordFailure("connect", profile.ProfileURL, profile.ProfileName, err)
+				return true
+			}
+
+			// Signing in again didn't get past the sign-in wall, so every profile would land on it
+			if errors.Is(err, pageops.ErrAuthwall) {
+				log.Errorf("LinkedIn signed the session out, stopping: %v", err)
+				runReport.RecordFailure("session", "", "", err)
+				return true
+			}
+
+			log.Errorf("Failed to send connection request: %v", err)
+			runReport.RecordConnectionFailed(campaign.Name, profile.ProfileURL, profile.ProfileName, err)
+			if plan != nil {
+				plan.use()
+			}
+
+			// Every remaining profile would fail the same way without a browser
+			if browser.NeedsRelaunch(err) {
+				log.Errorf("Lost the browser connection, stopping")
+				return true
+			}
+			if safety.Record(err) {
+				return true
+			}
+			continue
+		}
+		safety.Record(nil)
+
+		if result.Outcome == connections.OutcomeSkipped {
+			runReport.RecordConnectionSkipped(campaign.Name, result.ProfileURL, result.ProfileName, result.Reason)
+
+			if result.Reason == connections.ReasonFollowOnly && inMailer != nil {
+				more, stop := inMail(inMailer, profile, log, runReport)
+				if stop {
+					return true
+				}
+				if !more {
+					inMailer = nil
+				}
+			}
+		} else {
+			runReport.RecordConnectionSent(campaign.Name)
+			sent++
+			if plan != nil {
+				plan.use()
+			}
+
+			// Browse a little before heading to the next profile
+			run.idle.MaybeIdle()
+		}
+	}
+
+	return false
+}
+
+// enrich scrapes the profiles of the prospects about to be invited. Sales Navigator leads
+// are resolved to their public profile on the way; those that can't be are left out of the
+// returned prospects. stop reports whether the run should stop contacting profiles altogether.
+func enrich(scraper *profiles.Scraper, searcher *search.Searcher, db *storage.DB, prospects []storage.SearchResult, connectBudget *phase.Budget, scheduler *stealth.Scheduler, safety *breaker.Breaker, actions *budget.DailyBudget, runReport *report.RunReport) (kept []storage.SearchResult, stop bool) {
+	logger.Infof("Enriching %d prospects before inviting them", len(prospects))
+
+	for i := range prospects {
+		// The rest are left for the connect loop, which stops on the same checks
+		if connectBudget.Exceeded() || scheduler.SessionOver() {
+			return append(kept, prospects[i:]...), false
+		}
+
+		profile := &prospects[i]
+		if ok, stop := resolveLead(searcher, db, profile, runReport); stop {
+			return kept, true
+		} else if !ok {
+			continue
+		}
+		kept = append(kept, *profile)
+
+		if err := actions.Take(config.ActionVisit); err != nil {
+			logger.Infof("Stopping enrichment: %v", err)
+			return append(kept, prospects[i+1:]...), false
+		}
+		_, err := scraper.Scrape(profile.ProfileURL)
+		switch {
+		case errors.Is(err, profiles.ErrDailyLimit):
+			logger.Infof("Stopping enrichment: %v", err)
+			return append(kept, prospects[i+1:]...), false
+		case errors.Is(err, pageops.ErrNavigationLimit):
+			logger.Warnf("Navigation limit reached, stopping: %v", err)
+			runReport.RecordRestriction(err.Error())
+			return kept, true
+		case browser.NeedsRelaunch(err):
+			logger.Errorf("Lost the browser connection, stopping: %v", err)
+			runReport.RecordFailure("enrich", profile.ProfileURL, profile.ProfileName, err)
+			return kept, true
+		case err != nil:
+			logger.Warnf("Failed to enrich %s: %v", profile.ProfileName, err)
+			if safety.Record(err) {
+				return kept, true
+			}
+		default:
+			safety.Record(nil)
+		}
+	}
+
+	return kept, false
+}
+
+// resolveLead swaps a Sales Navigator lead URL for the public profile URL, doing
+// nothing for public profiles. ok is false when the profile should be passed over for
+// now; stop reports whether the run should stop contacting profiles altogether.
+func resolveLead(searcher *search.Searcher, db *storage.DB, profile *storage.SearchResult, runReport *report.RunReport) (ok, stop bool) {
+	if !profileurl.IsSalesLead(profile.ProfileURL) {
+		return true, false
+	}
+
+	resolved, err := searcher.ResolveLead(profile)
+	switch {
+	case errors.Is(err, pageops.ErrNavigationLimit):
+		logger.Warnf("Navigation limit reached, stopping: %v", err)
+		runReport.RecordRestriction(err.Error())
+		return false, true
+	case browser.NeedsRelaunch(err):
+		logger.Errorf("Lost the browser connection, stopping: %v", err)
+		runReport.RecordFailure("resolve_lead", profile.ProfileURL, profile.ProfileName, err)
+		return false, true
+	case err != nil:
+		logger.Warnf("Failed to find the public profile of %s, trying again another day: %v", profile.ProfileName, err)
+		if err := db.RecordProspectSkip(profile.ProfileURL); err != nil {
+			logger.Warnf("Failed to record skip: %v", err)
+		}
+		return false, false
+	}
+	return resolved, false
+}
+
+// preEngage likes a recent post of the campaign's next prospects, so that once the likes
+// have settled there are enough engaged prospects to fill the remaining invite budget.
+// It reports whether the run should stop contacting profiles altogether.
+func preEngage(engager *engagement.Engager, searcher *search.Searcher, db *storage.DB, campaign string, remaining int, policy storage.ProspectPolicy, connectBudget *phase.Budget, scheduler *stealth.Scheduler, actions *budget.DailyBudget, runReport *report.RunReport) bool {
+	// Prospects engaged with already, whether or not their like has settled
+	engaged := policy
+	engaged.EngagedBefore = time.Now()
+	pipeline, err := db.GetUncontactedProfiles(campaign, remaining, engaged)
+	if err != nil {
+		logger.Errorf("Failed to get engaged prospects: %v", err)
+		return false
+	}
+	if len(pipeline) >= remaining {
+		return false
+	}
+
+	fresh := policy
+	fresh.EngagedBefore = time.Time{}
+	fresh.Unengaged = true
+	prospects, err := db.GetUncontactedProfiles(campaign, remaining-len(pipeline), fresh)
+	if err != nil {
+		logger.Errorf("Failed to get prospects to engage: %v", err)
+		return false
+	}
+
+	logger.Infof("Engaging with %d prospects before inviting them", len(prospects))
+	for _, profile := range prospects {
+		if connectBudget.Exceeded() {
+			logger.Info("Connect time budget used, stopping engagement")
+			return false
+		}
+		if err := scheduler.CheckSession(); err != nil {
+			logger.Infof("Stopping engagement: %v", err)
+			return false
+		}
+
+		if ok, stop := resolveLead(searcher, db, &profile, runReport); stop {
+			return true
+		} else if !ok {
+			continue
+		}
+
+		if err := actions.Take(config.ActionLike); err != nil {
+			logger.Infof("Stopping engagement: %v", err)
+			return false
+		}
+		outcome, err := engager.LikeRecentPost(profile.ProfileURL)
+		switch {
+		case errors.Is(err, engagement.ErrDailyLikeLimit):
+			logger.Infof("Stopping engagement: %v", err)
+			return false
+		case errors.Is(err, pageops.ErrNavigationLimit):
+			logger.Warnf("Navigation limit reached, stopping: %v", err)
+			runReport.RecordRestriction(err.Error())
+			return true
+		case browser.NeedsRelaunch(err):
+			logger.Errorf("Lost the browser connection, stopping: %v", err)
+			runReport.RecordFailure("engage", profile.ProfileURL, profile.ProfileName, err)
+			return true
+		case err != nil:
+			logger.Warnf("Failed to engage with %s: %v", profile.ProfileName, err)
+			continue
+		}
+
+		logger.Infof("Engaged with %s: %s", profile.ProfileName, outcome)
+	}
+
+	return false
+}
+
+// harvest collects prospects from the campaign's groups and events within the search
+// budget. It reports whether the run should stop contacting profiles altogether.
+func harvest(harvester *search.Harvester, campaign *config.CampaignConfig, searchBudget *phase.Budget, runReport *report.RunReport) bool {
+	searchBudget.Start()
+	defer searchBudget.Stop()
+
+	for _, source := range campaign.Sources {
+		if searchBudget.Exceeded() {
+			logger.Info("Search time budget used, skipping remaining sources")
+			return false
+		}
+
+		var summary *search.SearchSummary
+		var err error
+		switch {
+		case source.IsCompany():
+			summary, err = harvester.FromCompany(source.URL, campaign.Filters, source.Max)
+		case source.IsGroup():
+			summary, err = harvester.FromGroup(source.URL, source.Max)
+		default:
+			summary, err = harvester.FromEvent(source.URL, source.Max)
+		}
+
+		switch {
+		case errors.Is(err, pageops.ErrNavigationLimit):
+			logger.Warnf("Navigation limit reached, stopping: %v", err)
+			runReport.RecordRestriction(err.Error())
+			return true
+		case browser.NeedsRelaunch(err):
+			logger.Errorf("Lost the browser during harvest, stopping: %v", err)
+			runReport.RecordFailure("harvest", "", "", err)
+			return true
+		case err != nil:
+			logger.Errorf("Failed to harvest %s: %v", source.URL, err)
+			continue
+		}
+
+		runReport.RecordSearch(campaign.Name, len(summary.Results), summary.NewProfiles)
+	}
+
+	return false
+}
+
+// contact sends a connection request to a profile, turning a panic anywhere below
+// into an error so one bad profile is logged and skipped instead of ending the run
+func contact(connManager *connections.ConnectionManager, profile storage.SearchResult, priority bool, log *zap.SugaredLogger) (result *connections.RequestResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("Recovered from panic while contacting %s: %v\n%s", profile.ProfileURL, r, debug.Stack())
+			err = fmt.Errorf("panic while contacting %s: %v", profile.ProfileURL, r)
+		}
+	}()
+
+	jobTitle, company := profile.TemplateFields()
+	return connManager.SendConnectionRequest(profile.ProfileURL, profile.ProfileName, jobTitle, company, priority)
+}
+
+// settleQueueItem moves a prospect's queue item on by how contacting it went. Limits, lost
+// sessions, lost browsers and invite flows we can't drive put it back in line untouched,
+// other failures are retried under policy.
+func settleQueueItem(db *storage.DB, profile storage.SearchResult, result *connections.RequestResult, err error, policy storage.RetryPolicy, log *zap.SugaredLogger) {
+	var qerr error
+	switch {
+	case errors.Is(err, connections.ErrDailyLimitReached), errors.Is(err, connections.ErrWeeklyLimitReached), errors.Is(err, connections.ErrHourlyLimitReached),
+		errors.Is(err, connections.ErrRestricted), errors.Is(err, connections.ErrUnsupportedFlow), errors.Is(err, pageops.ErrNavigationLimit),
+		errors.Is(err, pageops.ErrAuthwall), errors.Is(err, budget.ErrExhausted), browser.NeedsRelaunch(err):
+		qerr = db.ReleaseQueueItem(profile.QueueID)
+	case err != nil:
+		var retry bool
+		if retry, qerr = db.FailQueueItem(profile.QueueID, err, policy); qerr == nil && !retry {
+			log.Warnf("Giving up on %s after %d attempts; `queue requeue %d` tries again", profile.ProfileName, policy.MaxAttempts, profile.QueueID)
+		}
+	case result.Outcome == connections.OutcomeSkipped &&
+		(result.Reason == connections.ReasonSkippedOrganic || result.Reason == connections.ReasonReviewUnanswered):
+		// Organic skips come back after their cooldown, unanswered reviews at once
+		qerr = db.ReleaseQueueItem(profile.QueueID)
+	case result.Outcome == connections.OutcomeSkipped:
+		qerr = db.CompleteQueueItem(profile.QueueID, storage.QueueSkipped, result.Reason)
+	default:
+		qerr = db.CompleteQueueItem(profile.QueueID, storage.QueueDone, "")
+	}
+	if qerr != nil {
+		log.Warnf("%v", qerr)
+	}
+}
+
+// retryPolicy returns how failed queue items are retried
+func retryPolicy(cfg *config.Config) storage.RetryPolicy {
+	return storage.RetryPolicy{
+		MaxAttempts: cfg.Queue.MaxAttempts,
+		Delay:       time.Duration(cfg.Queue.RetryDelayMinutes) * time.Minute,
+	}
+}
+
+// inMail sends an InMail to a profile that can't be invited. It reports whether InMail
+// can go on for the rest of the campaign, and whether the run should stop altogether.
+func inMail(inMailer *messaging.MessageManager, profile storage.SearchResult, log *zap.SugaredLogger, runReport *report.RunReport) (more, stop bool) {
+	jobTitle, company := profile.TemplateFields()
+	_, err := inMailer.SendTemplatedInMail(profile.ProfileURL, profile.ProfileName, jobTitle, company)
+	switch {
+	case err == nil:
+		runReport.RecordMessageSent()
+		return true, false
+	case errors.Is(err, messaging.ErrNoInMailCredits), errors.Is(err, messaging.ErrInMailLimitReached),
+		errors.Is(err, messaging.ErrDailyLimitReached), errors.Is(err, messaging.ErrHourlyLimitReached), errors.Is(err, budget.ErrExhausted):
+		log.Infof("Stopping InMail for this campaign: %v", err)
+		runReport.RecordRestriction(err.Error())
+		return false, false
+	case errors.Is(err, pageops.ErrNavigationLimit), browser.NeedsRelaunch(err):
+		log.Warnf("Stopping after a failed InMail: %v", err)
+		runReport.RecordFailure("inmail", profile.ProfileURL, profile.ProfileName, err)
+		return false, true
+	case errors.Is(err, messaging.ErrInMailUnavailable):
+		log.Infof("Could not send InMail: %v", err)
+		return true, false
+	default:
+		log.Errorf("Failed to send InMail: %v", err)
+		runReport.RecordFailure("inmail", profile.ProfileURL, profile.ProfileName, err)
+		return true, false
+	}
+}
+
+// notePriority reports whether a prospect is worth a note under note_mode priority: every
+// prospect of a priority_notes campaign, or one with enough mutual connections
+func notePriority(cfg *config.ConnectionsConfig, campaign *config.CampaignConfig, profile storage.SearchResult) bool {
+	if campaign.PriorityNotes {
+		return true
+	}
+	return cfg.NotePriorityMinMutual > 0 && profile.MutualConnections >= cfg.NotePriorityMinMutual
+}
+
+// prospectPolicy returns the order and filters a campaign's uncontacted prospects are taken in
+func prospectPolicy(cfg *config.Config, campaign *config.CampaignConfig) storage.ProspectPolicy {
+	policy := storage.ProspectPolicy{
+		Order:          cfg.Connections.TargetOrder,
+		SkipOpenToWork: cfg.Connections.Prioritization.SkipOpenToWork,
+		MaxDegree:      campaign.Filters.MaxDegree(),
+		BoostTags:      cfg.Connections.Prioritization.BoostTags,
+	}
+
+	if days := cfg.Connections.MaxResultAgeDays; days > 0 {
+		policy.FoundAfter = time.Now().AddDate(0, 0, -days)
+	}
+
+	if cfg.Connections.OrganicSkipProbability > 0 {
+		policy.OrganicSkippedBefore = time.Now().AddDate(0, 0, -cfg.Connections.OrganicSkipCooldownDays)
+	}
+
+	// With pre-engagement only prospects whose like has settled are invited
+	if cfg.Connections.PreEngage == config.PreEngageLike {
+		policy.EngagedBefore = time.Now().Add(-time.Duration(cfg.Engagement.HoursBeforeInvite) * time.Hour)
+	}
+	return policy
+}
+
+// forget reports whether to pass over an eligible prospect this time, as a person now and then would.
+// The prospect stays queued for a later day and is never passed over more than max_skips times.
+func forget(cfg *config.ConnectionsConfig, db *storage.DB, rng *rand.Rand, profile storage.SearchResult) bool {
+	if profile.SkipCount >= cfg.MaxSkips || rng.Float64() >= cfg.SkipProbability {
+		return false
+	}
+
+	if err := db.RecordProspectSkip(profile.ProfileURL); err != nil {
+		logger.Warnf("Failed to record skip, contacting the prospect instead: %v", err)
+		return false
+	}
+
+	logger.Infof("Passing over %s for today (%d/%d)", profile.ProfileName, profile.SkipCount+1, cfg.MaxSkips)
+	db.Audit(storage.AuditEntry{Action: "prospect_skipped", ProfileURL: profile.ProfileURL,
+		Details: fmt.Sprintf("Passed over %s (%d/%d)", profile.ProfileURL, profile.SkipCount+1, cfg.MaxSkips), Result: storage.AuditSkipped})
+	return true
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/connections"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+func TestSettleQueueItem(t *testing.T) {
+	policy := storage.RetryPolicy{MaxAttempts: 3, Delay: time.Hour}
+
+	for _, tt := range []struct {
+		name     string
+		result   *connections.RequestResult
+		err      error
+		state    string
+		attempts int
+	}{
+		{"unsupported invite flow", nil, fmt.Errorf("%w: bottom_sheet", connections.ErrUnsupportedFlow), storage.QueueQueued, 0},
+		{"restriction", nil, connections.ErrRestricted, storage.QueueQueued, 0},
+		{"other failure", nil, fmt.Errorf("failed to click send button"), storage.QueueScheduled, 1},
+		{"organic skip", &connections.RequestResult{Outcome: connections.OutcomeSkipped, Reason: connections.ReasonSkippedOrganic}, nil, storage.QueueQueued, 0},
+		{"follow only", &connections.RequestResult{Outcome: connections.OutcomeSkipped, Reason: connections.ReasonFollowOnly}, nil, storage.QueueSkipped, 0},
+		{"sent", &connections.RequestResult{Outcome: connections.OutcomeSent}, nil, storage.QueueDone, 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := storage.NewDB(filepath.Join(t.TempDir(), "test.db"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+
+			if _, err := db.SaveSearchResults([]*storage.SearchResult{{ProfileURL: "https://www.linkedin.com/in/ada-lovelace/",
+				ProfileName: "Ada Lovelace", FoundAt: time.Now()}}); err != nil {
+				t.Fatal(err)
+			}
+			profiles, err := db.GetUncontactedProfiles("", 1, storage.ProspectPolicy{})
+			if err != nil || len(profiles) != 1 {
+				t.Fatalf("uncontacted profiles = %+v, %v", profiles, err)
+			}
+			if err := db.ClaimQueueItem(profiles[0].QueueID); err != nil {
+				t.Fatal(err)
+			}
+
+			settleQueueItem(db, profiles[0], tt.result, tt.err, policy, logger.With())
+
+			items, err := db.ListQueue("", 10)
+			if err != nil || len(items) != 1 {
+				t.Fatalf("queue = %+v, %v", items, err)
+			}
+			if items[0].State != tt.state || items[0].Attempts != tt.attempts {
+				t.Fatalf("queue item is %s after %d attempts, want %s after %d", items[0].State, items[0].Attempts, tt.state, tt.attempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/notify"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// runCommand runs a CLI subcommand and returns the process exit code
+func runCommand(name string, args []string) int {
+	switch name {
+	case "stats":
+		return runStats(args)
+	case "export":
+		return runExport(args)
+	case "run":
+		return runRun(args)
+	case "plan":
+		return runPlan(args)
+	case "timeline":
+		return runTimeline(args)
+	case "config":
+		return runConfig(args)
+	case "fingerprint":
+		return runFingerprint(args)
+	case "history":
+		return runHistory(args)
+	case "resume":
+		return runResume(args)
+	case "prune":
+		return runPrune(args)
+	case "queue":
+		return runQueue(args)
+	case "diagnose":
+		return runDiagnose(args)
+	case "db":
+		return runDB(args)
+	case "monitor":
+		return runMonitor(args)
+	case "ctl":
+		return runCtl(args)
+	case "profiles":
+		return runProfiles(args)
+	case "help", "-h", "--help":
+		printUsage()
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", name)
+		printUsage()
+		return 2
+	}
+}
+
+// runOptions holds the flags accepted when running the automation workflow
+type runOptions struct {
+	campaign      string
+	daemon        bool
+	review        int           // approve the first N invites at the terminal
+	reviewTimeout time.Duration // how long each review waits for an answer
+}
+
+// parseRunFlags parses the flags given without a subcommand
+func parseRunFlags(args []string) (*runOptions, error) {
+	opts := &runOptions{}
+
+	fs := flag.NewFlagSet("linkedin-bot", flag.ContinueOnError)
+	fs.Usage = func() {}
+	fs.StringVar(&opts.campaign, "campaign", "", "run only the named campaign")
+	fs.BoolVar(&opts.daemon, "daemon", false, "keep running, session after session and day after day")
+	fs.IntVar(&opts.review, "review", 0, "approve the first N invites at the terminal before they are sent")
+	fs.DurationVar(&opts.reviewTimeout, "review-timeout", defaultReviewTimeout, "how long a review waits for an answer")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if opts.review < 0 || opts.reviewTimeout <= 0 {
+		fmt.Fprintln(os.Stderr, "--review and --review-timeout must be positive")
+		return nil, fmt.Errorf("invalid review flags")
+	}
+
+	return opts, nil
+}
+
+// printUsage prints the available subcommands
+func printUsage() {
+	fmt.Println("Usage: linkedin-bot [command] [flags]")
+	fmt.Println()
+	fmt.Println("Without a command the automation workflow runs.")
+	fmt.Println("  --campaign NAME   Run only the named campaign")
+	fmt.Println("  --daemon          Keep running: sessions follow each other through the day,")
+	fmt.Println("                    the bot sleeps overnight and SIGTERM stops it after the")
+	fmt.Println("                    current profile (also run --daemon)")
+	fmt.Println("  --review N        Show the first N invites with their rendered note and wait")
+	fmt.Println("                    for y (send), n (skip) or e (edit the note); unanswered")
+	fmt.Println("                    ones are left for later (--review-timeout, default 5m)")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  stats    Show acceptance rate, funnel and per-template statistics")
+	fmt.Println("           (stats stealth shows realized stealth metrics of the last run)")
+	fmt.Println("  export   Export outreach data (export graph --format dot|graphml)")
+	fmt.Println("  plan     Preview today's budgets, slots, queues and blockers without")
+	fmt.Println("           opening the browser (plan --campaign NAME for one campaign)")
+	fmt.Println("  timeline Show a day's runs, phases, activity and waits in order, with long")
+	fmt.Println("           gaps and their cause (timeline --date YYYY-MM-DD [--format json])")
+	fmt.Println("  run      Inspect past runs (run show [RUN_ID] diffs its config snapshot")
+	fmt.Println("           against the previous run's); run --daemon runs the bot as a daemon")
+	fmt.Println("  config   Check a config file without running (config validate [PATH]")
+	fmt.Println("           lists every problem with its YAML path), or write the")
+	fmt.Println("           commented default config (config init [--force] [PATH])")
+	fmt.Println("  fingerprint  Show the browser fingerprint an account presents, or rotate it")
+	fmt.Println("           to a new one (fingerprint show|rotate [ACCOUNT], LINKEDIN_EMAIL")
+	fmt.Println("           by default)")
+	fmt.Println("  history  Show everything the bot did to one profile, with each action's")
+	fmt.Println("           result, timing and page (history --profile URL [--format json])")
+	fmt.Println("  resume   Clear safe mode, entered when too many profile actions failed,")
+	fmt.Println("           so that runs start again")
+	fmt.Println("  prune    Remove connections, or unfollow profiles with --unfollow, listed in")
+	fmt.Println("           a CSV (--csv FILE) or accepted long ago without a reply")
+	fmt.Println("           (--accepted-before DAYS); lists them unless --confirm is given")
+	fmt.Println("  queue    Show the outreach queue (queue list [--state STATE] [--limit N]),")
+	fmt.Println("           or put an item back in line (queue requeue ID [--priority N])")
+	fmt.Println("  diagnose Sum up the failed API calls and console errors the browser recorded,")
+	fmt.Println("           e.g. a spike of 429s, and suggest backing off (diagnose [--hours N])")
+	fmt.Println("  db       Delete search results, activity logs and diagnostics past their")
+	fmt.Println("           database.* retention and vacuum (db prune --dry-run|--apply),")
+	fmt.Println("           or show each table's rows and size (db size)")
+	fmt.Println("  monitor  Run the monitors' saved searches and report the profiles new since")
+	fmt.Println("           their last run, never contacting anyone (monitor [--name NAME]")
+	fmt.Println("           [--export FILE])")
+	fmt.Println("  ctl      Pause the run in progress after the profile in hand, keeping its")
+	fmt.Println("           session open, resume it or show its state (ctl pause|resume|status")
+	fmt.Println("           [ACCOUNT], LINKEDIN_EMAIL by default)")
+	fmt.Println("  profiles Tag found profiles and note on them by hand for triage (profiles")
+	fmt.Println("           tag|untag URL TAG..., profiles note URL TEXT), or list them")
+	fmt.Println("           (profiles list [--tag TAG])")
+	fmt.Println("  help     Show this help")
+}
+
+// getConfigPath returns the configuration file path from the environment
+func getConfigPath() string {
+	configPath := os.Getenv("CONFIG_PATH")
+	if configPath == "" {
+		configPath = "configs/config.yaml"
+	}
+	return configPath
+}
+
+// getDBPath returns the database file path from the environment
+func getDBPath() string {
+	dbPath := os.Getenv("DB_PATH")
+	if dbPath == "" {
+		dbPath = "data/linkedin_bot.db"
+	}
+	return dbPath
+}
+
+// openDB opens the database at DB_PATH, creating its directory if needed
+func openDB() (*storage.DB, error) {
+	dbPath := getDBPath()
+
+	// Create data directory if it doesn't exist
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	return storage.NewDB(dbPath)
+}
+
+// newScheduler creates the scheduler with the configured business hours, quiet days and active-time caps
+func newScheduler(cfg *config.Config, db *storage.DB, notifier notify.Notifier) (*stealth.Scheduler, error) {
+	scheduling := cfg.Stealth.Scheduling
+	scheduler, err := stealth.NewScheduler(
+		scheduling.BusinessHoursStart,
+		scheduling.BusinessHoursEnd,
+		scheduling.Timezone,
+		scheduling.WeekendActivity,
+		scheduling.BreakDurationMin,
+		scheduling.BreakDurationMax,
+		scheduling.BreakProbability,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduler.SetNotifier(notifier)
+	scheduler.SetActivityLog(db)
+	scheduler.SetDayOverrides(scheduling.ActiveDays())
+	scheduler.SetSessionLimits(db,
+		time.Duration(scheduling.MaxSessionMinutes)*time.Minute,
+		time.Duration(scheduling.MaxDailyActiveMinutes)*time.Minute)
+	return scheduler, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Tanukumar01/linkedin-automation/configs"
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+)
+
+// runConfig dispatches the config subcommands
+func runConfig(args []string) int {
+	usage := "Usage: linkedin-bot config validate [PATH] | config init [--force] [PATH]"
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	switch args[0] {
+	case "validate":
+		if len(args) > 2 {
+			fmt.Fprintln(os.Stderr, usage)
+			return 2
+		}
+		path := getConfigPath()
+		if len(args) == 2 {
+			path = args[1]
+		}
+		return runConfigValidate(path)
+	case "init":
+		return runConfigInit(args[1:])
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+}
+
+// runConfigInit writes the commented default config, refusing to replace an existing
+// file unless --force is given
+func runConfigInit(args []string) int {
+	fs := flag.NewFlagSet("config init", flag.ContinueOnError)
+	force := fs.Bool("force", false, "overwrite an existing config file")
+	if err := fs.Parse(args); err != nil || fs.NArg() > 1 {
+		return 2
+	}
+
+	path := getConfigPath()
+	if fs.NArg() == 1 {
+		path = fs.Arg(0)
+	}
+
+	if _, err := os.Stat(path); err == nil && !*force {
+		fmt.Fprintf(os.Stderr, "%s already exists; use --force to overwrite it\n", path)
+		return 1
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create config directory: %v\n", err)
+		return 1
+	}
+	if err := os.WriteFile(path, configs.Default, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write config: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Wrote %s\n", path)
+	return 0
+}
+
+// runConfigValidate loads and validates a config file without opening the browser or
+// the database, printing OK or every problem found
+func runConfigValidate(path string) int {
+	_, err := config.LoadConfig(path)
+	if err == nil {
+		fmt.Println("OK")
+		return 0
+	}
+
+	var invalid *config.ValidationError
+	if !errors.As(err, &invalid) {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "%s: %d problems found\n", path, len(invalid.Problems))
+	for _, problem := range invalid.Problems {
+		fmt.Fprintf(os.Stderr, "  - %s\n", problem)
+	}
+	return 1
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/control"
+	"github.com/Tanukumar01/linkedin-automation/internal/instance"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+)
+
+// ctlUsage is printed when the ctl command is misused
+const ctlUsage = "Usage: linkedin-bot ctl pause|resume|status [ACCOUNT]"
+
+// runCtl pauses, resumes or shows the run in progress for an account, LINKEDIN_EMAIL by default
+func runCtl(args []string) int {
+	if len(args) == 0 || len(args) > 2 {
+		fmt.Fprintln(os.Stderr, ctlUsage)
+		return 2
+	}
+	command := args[0]
+	if command != control.Pause && command != control.Resume && command != "status" {
+		fmt.Fprintln(os.Stderr, ctlUsage)
+		return 2
+	}
+
+	account := os.Getenv("LINKEDIN_EMAIL")
+	if len(args) > 1 {
+		account = args[1]
+	}
+	if account == "" {
+		fmt.Fprintln(os.Stderr, "Name the account, or set LINKEDIN_EMAIL")
+		return 2
+	}
+
+	lockPath := instance.Path(getDBPath(), account)
+	owner, err := instance.Holder(lockPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if owner == nil {
+		fmt.Printf("No run in progress for %s\n", account)
+		if command == "status" {
+			return 0
+		}
+		return 1
+	}
+
+	if command == "status" {
+		return printRunStatus(lockPath, owner)
+	}
+
+	if err := control.Send(lockPath, command); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if command == control.Pause {
+		fmt.Println("Pause requested; the run pauses once the profile in hand is done (ctl status shows when)")
+	} else {
+		fmt.Println("Resume requested")
+	}
+	return 0
+}
+
+// printRunStatus prints whether the run holding the lock is running or paused
+func printRunStatus(lockPath string, owner *instance.Owner) int {
+	fmt.Printf("Run pid %d on %s, started %s\n", owner.PID, owner.Host, owner.Started.Format("2006-01-02 15:04:05"))
+
+	status, err := control.ReadStatus(lockPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if status == nil || status.PID != owner.PID {
+		fmt.Println("State:   unknown (the run has not reported one yet)")
+		return 0
+	}
+
+	paused := time.Duration(status.PausedSeconds * float64(time.Second))
+	switch status.State {
+	case stealth.PausePaused:
+		fmt.Printf("State:   paused since %s\n", status.Since.Format("15:04:05"))
+		paused += time.Since(status.UpdatedAt)
+	case stealth.PausePausing:
+		fmt.Printf("State:   pausing since %s, finishing the profile in hand\n", status.Since.Format("15:04:05"))
+	default:
+		fmt.Println("State:   running")
+	}
+	fmt.Printf("Paused:  %s in total\n", paused.Round(time.Second))
+	return 0
+}
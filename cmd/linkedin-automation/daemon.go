@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/instance"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+)
+
+// maxFailedSessions is how many sessions in a row may fail, e.g. to log in, before the
+// daemon gives up instead of retrying against an account that needs attention
+const maxFailedSessions = 3
+
+// daemonCycle decides when the daemon's next session starts from how the last one ended
+type daemonCycle struct {
+	scheduler *stealth.Scheduler
+	failures  int // sessions failed in a row
+}
+
+// next returns when to start the session after one that ended with err at now, and false
+// when the daemon should exit instead. A session cut short by its cap is followed after a
+// break-length gap; once the day's work is done or its active time used up, the next
+// session waits for the next active window.
+func (c *daemonCycle) next(err error, now time.Time) (time.Time, bool) {
+	switch {
+	case errors.Is(err, stealth.ErrStopped), errors.Is(err, errSafeMode):
+		return time.Time{}, false
+	case errors.Is(err, stealth.ErrSessionEnded):
+		c.failures = 0
+		return c.scheduler.NextSessionStart(now), true
+	case err == nil, errors.Is(err, stealth.ErrDailyActiveCapReached):
+		c.failures = 0
+		return c.scheduler.NextActiveStart(now), true
+	default:
+		c.failures++
+		if c.failures >= maxFailedSessions {
+			return time.Time{}, false
+		}
+		return c.scheduler.NextSessionStart(now), true
+	}
+}
+
+// runDaemon runs session after session, sleeping between them and overnight, until a
+// shutdown is requested. Each session plans its own day, so limits, slots and queues
+// are read afresh once the date rolls over. It returns the process exit code.
+func runDaemon(b *bot) int {
+	logger.Info("Running as a daemon; send SIGTERM to stop after the current profile")
+
+	if _, ok := b.cfg.Notifications.Email.DigestTime(time.Now()); ok && b.email != nil {
+		b.digestOnSchedule = true
+		stop := make(chan struct{})
+		defer close(stop)
+		go b.mailDigests(stop)
+		logger.Infof("Mailing the daily digest at %s", b.cfg.Notifications.Email.DigestAt)
+	}
+
+	cycle := &daemonCycle{scheduler: b.scheduler}
+	for {
+		err := b.runSession()
+		switch {
+		case err == nil:
+			logger.Info("Today's work is done")
+		case errors.Is(err, stealth.ErrStopped):
+		case errors.Is(err, errSafeMode):
+			logger.Errorf("Not running: %v", err)
+			return exitSafeMode
+		case errors.Is(err, stealth.ErrSessionEnded), errors.Is(err, stealth.ErrDailyActiveCapReached):
+			logger.Infof("Session ended: %v", err)
+		default:
+			logger.Errorf("Session failed (%d in a row): %v", cycle.failures+1, err)
+		}
+
+		next, ok := cycle.next(err, time.Now())
+		if !ok {
+			break
+		}
+
+		logger.Infof("Next session at %s", next.Format(time.RFC1123))
+		b.scheduler.WaitForNextSession(next)
+	}
+
+	if cycle.failures >= maxFailedSessions {
+		logger.Errorf("%d sessions failed in a row, stopping the daemon", cycle.failures)
+		return 1
+	}
+
+	logger.Info("Daemon shut down")
+	return 0
+}
+
+// stopOnSignal stops the scheduler on the first SIGTERM or interrupt so the run winds
+// down after the profile in hand, and exits at once on the second, releasing the lock
+func stopOnSignal(scheduler *stealth.Scheduler, lock *instance.Lock) {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+
+	go func() {
+		sig := <-signals
+		logger.Infof("Received %s, finishing the current profile before shutting down", sig)
+		scheduler.Stop()
+
+		sig = <-signals
+		logger.Warnf("Received %s again, exiting immediately", sig)
+		if err := lock.Release(); err != nil {
+			logger.Warnf("%v", err)
+		}
+		logger.Sync()
+		os.Exit(1)
+	}()
+}
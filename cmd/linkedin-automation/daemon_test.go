@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+)
+
+func TestDaemonCycle(t *testing.T) {
+	scheduler, err := stealth.NewScheduler(9, 17, "America/New_York", false, 5, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ny, _ := time.LoadLocation("America/New_York")
+	tuesday := time.Date(2026, time.March, 10, 14, 0, 0, 0, ny)
+	wednesday := time.Date(2026, time.March, 11, 9, 0, 0, 0, ny)
+	failed := errors.New("failed to log in")
+
+	for _, tt := range []struct {
+		name  string
+		errs  []error // what each session in turn ended with
+		going bool    // whether the daemon carries on after the last
+		gap   bool    // the next session follows after a break instead of the next active window
+	}{
+		{"day's work done", []error{nil}, true, false},
+		{"daily cap used", []error{fmt.Errorf("phase stopped: %w", stealth.ErrDailyActiveCapReached)}, true, false},
+		{"session cap hit", []error{stealth.ErrSessionEnded}, true, true},
+		{"shutdown", []error{stealth.ErrStopped}, false, false},
+		{"safe mode", []error{errSafeMode}, false, false},
+		{"one failure", []error{failed}, true, true},
+		{"failures in a row", []error{failed, failed, failed}, false, false},
+		{"failures with a success between", []error{failed, failed, nil, failed, failed}, true, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cycle := &daemonCycle{scheduler: scheduler}
+
+			var next time.Time
+			var going bool
+			for _, err := range tt.errs {
+				next, going = cycle.next(err, tuesday)
+			}
+			if going != tt.going {
+				t.Fatalf("carries on: %v, want %v", going, tt.going)
+			}
+			if !going {
+				return
+			}
+
+			if tt.gap {
+				if gap := next.Sub(tuesday); gap < 5*time.Minute || gap > 10*time.Minute {
+					t.Fatalf("next session in %s, want after a 5-10 minute break", gap)
+				}
+			} else if !next.Equal(wednesday) {
+				t.Fatalf("next session at %s, want the next active window at %s", next, wednesday)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// dbUsage is printed when the db command is misused
+const dbUsage = "Usage: linkedin-bot db prune (--dry-run | --apply) | db size"
+
+// runDB maintains the database: prunes rows past their retention, or shows its size
+func runDB(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, dbUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "prune":
+		return runDBPrune(args[1:])
+	case "size":
+		return runDBSize(args[1:])
+	default:
+		fmt.Fprintln(os.Stderr, dbUsage)
+		return 2
+	}
+}
+
+// runDBPrune deletes the rows older than the database.* retention windows and vacuums
+// the database, or with --dry-run only counts them
+func runDBPrune(args []string) int {
+	fs := flag.NewFlagSet("db prune", flag.ContinueOnError)
+	dryRunFlag := fs.Bool("dry-run", false, "count the rows that would be deleted")
+	applyFlag := fs.Bool("apply", false, "delete the rows and vacuum the database")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *dryRunFlag == *applyFlag || fs.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, dbUsage)
+		return 2
+	}
+
+	cfg, err := config.LoadConfig(getConfigPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+
+	db, err := openDB()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	retention := cfg.Database
+	result, err := db.Prune(storage.PrunePolicy{
+		SearchResultDays: retention.SearchResultRetentionDays,
+		ActivityLogDays:  retention.ActivityLogRetentionDays,
+		DiagnosticDays:   retention.DiagnosticRetentionDays,
+		DryRun:           *dryRunFlag,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Search results never contacted:\t%d\t%s\n", result.SearchResults, retentionLabel(retention.SearchResultRetentionDays))
+	fmt.Fprintf(w, "Their queue items:\t%d\t\n", result.QueueItems)
+	fmt.Fprintf(w, "Activity logs:\t%d\t%s\n", result.ActivityLogs, retentionLabel(retention.ActivityLogRetentionDays))
+	fmt.Fprintf(w, "Diagnostics:\t%d\t%s\n", result.Diagnostics, retentionLabel(retention.DiagnosticRetentionDays))
+	w.Flush()
+
+	if *dryRunFlag {
+		fmt.Printf("Nothing changed; run again with --apply to delete these %d rows\n", result.Total())
+		return 0
+	}
+	fmt.Printf("Deleted %d rows, vacuuming freed %s\n", result.Total(), formatSize(result.FreedBytes))
+	return 0
+}
+
+// runDBSize prints every table's row count and size
+func runDBSize(args []string) int {
+	if len(args) > 0 {
+		fmt.Fprintln(os.Stderr, dbUsage)
+		return 2
+	}
+
+	db, err := openDB()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	tables, total, err := db.SizeStats()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Database %s: %s\n\n", getDBPath(), formatSize(total))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TABLE\tROWS\tSIZE")
+	for _, t := range tables {
+		fmt.Fprintf(w, "%s\t%d\t%s\n", t.Name, t.Rows, formatSize(t.Bytes))
+	}
+	w.Flush()
+	return 0
+}
+
+// retentionLabel describes a retention window
+func retentionLabel(days int) string {
+	if days <= 0 {
+		return "(kept)"
+	}
+	return fmt.Sprintf("(older than %d days)", days)
+}
+
+// formatSize prints a size in bytes readably
+func formatSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
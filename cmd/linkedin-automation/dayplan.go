@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// dayPlan hands out the connection slots planned for today
+type dayPlan struct {
+	db        *storage.DB
+	scheduler *stealth.Scheduler
+	pending   []storage.ActionSlot
+}
+
+// loadDayPlan resumes today's stored plan, or stores the slots today's plan drew.
+// Slots whose time passed while the bot wasn't running are marked missed.
+func loadDayPlan(db *storage.DB, scheduler *stealth.Scheduler, today *todayPlan) (*dayPlan, error) {
+	now := time.Now()
+	date := now.Format("2006-01-02")
+
+	if !today.SlotsStored {
+		if err := db.SaveActionSlots(date, today.Slots); err != nil {
+			return nil, err
+		}
+	}
+
+	slots, err := db.GetActionSlots(date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get planned slots: %w", err)
+	}
+
+	if today.SlotsStored {
+		logger.Infof("Resuming today's plan of %d connection slots", len(slots))
+	} else {
+		logger.Infof("Planned %d connection slots for today: %s", len(slots), formatSlots(slots))
+	}
+
+	plan := &dayPlan{db: db, scheduler: scheduler}
+	for _, slot := range slots {
+		if slot.Status != "pending" {
+			continue
+		}
+		if slot.SlotAt.Before(now) {
+			if err := db.UpdateActionSlotStatus(slot.ID, "missed"); err != nil {
+				logger.Warnf("Failed to mark missed slot: %v", err)
+			}
+			continue
+		}
+		plan.pending = append(plan.pending, slot)
+	}
+
+	logger.Infof("%d connection slots remaining today: %s", len(plan.pending), formatSlots(plan.pending))
+	return plan, nil
+}
+
+// wait blocks until the next planned slot; it returns false when no slots are left
+func (p *dayPlan) wait() bool {
+	if len(p.pending) == 0 {
+		return false
+	}
+
+	next := p.pending[0].SlotAt
+	if d := time.Until(next); d > 0 {
+		logger.Infof("Next connection slot at %s (in %s)", next.Local().Format("15:04"), d.Round(time.Second))
+		p.scheduler.WaitUntil(next)
+	}
+	return true
+}
+
+// use marks the current slot as spent; skipped profiles don't call it and reuse the slot
+func (p *dayPlan) use() {
+	if len(p.pending) == 0 {
+		return
+	}
+
+	if err := p.db.UpdateActionSlotStatus(p.pending[0].ID, "used"); err != nil {
+		logger.Warnf("Failed to mark slot used: %v", err)
+	}
+	p.pending = p.pending[1:]
+}
+
+// formatSlots lists slot times for the log
+func formatSlots(slots []storage.ActionSlot) string {
+	times := make([]string, len(slots))
+	for i, slot := range slots {
+		times[i] = slot.SlotAt.Local().Format("15:04")
+	}
+	return strings.Join(times, ", ")
+}
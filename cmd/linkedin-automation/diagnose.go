@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/diagnostics"
+)
+
+// runDiagnose sums up the failed API calls and console errors the browser recorded
+// recently, with what to do about them
+func runDiagnose(args []string) int {
+	fs := flag.NewFlagSet("diagnose", flag.ContinueOnError)
+	hoursFlag := fs.Int("hours", 24, "look back this many hours")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *hoursFlag <= 0 || fs.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "Usage: linkedin-bot diagnose [--hours N]")
+		return 2
+	}
+
+	db, err := openDB()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	now := time.Now()
+	since := now.Add(-time.Duration(*hoursFlag) * time.Hour)
+	recorded, err := db.GetDiagnostics(since)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	s := diagnostics.Summarize(recorded, since, now)
+
+	fmt.Printf("Browser diagnostics of the last %d hours\n\n", *hoursFlag)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	statuses := make([]int, 0, len(s.Failed))
+	for status := range s.Failed {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	for _, status := range statuses {
+		fmt.Fprintf(w, "Failed API calls (%d):\t%d\n", status, s.Failed[status])
+	}
+	if len(statuses) == 0 {
+		fmt.Fprintf(w, "Failed API calls:\t0\n")
+	}
+	fmt.Fprintf(w, "Throttled (429/999):\t%d, %d in the last hour\n", s.Throttled, s.ThrottledLastHour)
+	fmt.Fprintf(w, "Console errors:\t%d\n", s.Console)
+	fmt.Fprintf(w, "Uncaught exceptions:\t%d\n", s.Exceptions)
+	w.Flush()
+
+	printCounts("Failing endpoints", s.Endpoints)
+	printCounts("Frequent console errors", s.Messages)
+
+	fmt.Println("\nAdvice:")
+	for _, advice := range s.Advice {
+		fmt.Printf("  - %s\n", advice)
+	}
+	return 0
+}
+
+// printCounts prints a titled list of counts, if there are any
+func printCounts(title string, counts []diagnostics.Count) {
+	if len(counts) == 0 {
+		return
+	}
+	fmt.Printf("\n%s:\n", title)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, c := range counts {
+		fmt.Fprintf(w, "  %d\t%s\n", c.N, c.Key)
+	}
+	w.Flush()
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/connections"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/notify"
+	"github.com/Tanukumar01/linkedin-automation/internal/report"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// emailConfig returns the SMTP settings of notifications.email, with the login from the
+// environment
+func emailConfig(cfg *config.Config) notify.EmailConfig {
+	email := cfg.Notifications.Email
+	return notify.EmailConfigFromEnv(notify.EmailConfig{
+		Host:   email.Host,
+		Port:   email.Port,
+		TLS:    email.TLS,
+		From:   email.From,
+		To:     email.To,
+		Events: email.Events,
+	})
+}
+
+// buildDigest gathers the results of the day of now for the email digest: the totals and
+// limits from the database, and the errors and restrictions from the day's run reports
+func buildDigest(cfg *config.Config, db *storage.DB, now time.Time) (*notify.Digest, error) {
+	stats, err := db.GetDailyStats(now)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &notify.Digest{
+		Date:         stats.Date,
+		InvitesSent:  stats.ConnectionsSent,
+		Acceptances:  stats.ConnectionsAccepted,
+		Replies:      stats.RepliesReceived,
+		MessagesSent: stats.MessagesSent,
+		Searches:     stats.SearchesPerformed,
+		Errors:       []notify.DigestError{},
+		Restrictions: []string{},
+	}
+
+	d.Limits = []notify.DigestLimit{
+		{Name: "Invites today", Used: stats.ConnectionsSent, Limit: cfg.DailyConnectLimit(now)},
+		{Name: "Messages today", Used: stats.MessagesSent, Limit: cfg.Messaging.DailyLimit},
+	}
+	weekly, err := connections.GetWeeklyAllowance(db, cfg.Connections.WeeklyLimit, now)
+	if err != nil {
+		return nil, err
+	}
+	if weekly.Limit > 0 {
+		d.Limits = append(d.Limits, notify.DigestLimit{Name: "Invites this week", Used: weekly.Sent, Limit: weekly.Limit})
+	}
+	if cfg.ActionBudget.Enabled {
+		used, err := db.ActionBudgetUsed(stats.Date)
+		if err != nil {
+			return nil, err
+		}
+		total := 0
+		for _, n := range used {
+			total += n
+		}
+		d.Limits = append(d.Limits, notify.DigestLimit{Name: "Action budget today", Used: total, Limit: cfg.ActionBudget.DailyTotal})
+	}
+
+	ids, err := report.List(cfg.Reporting.Dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if !strings.HasPrefix(id, stats.Date) {
+			continue
+		}
+		r, _, err := report.Load(cfg.Reporting.Dir, id)
+		if err != nil {
+			logger.Warnf("Leaving run %s out of the digest: %v", id, err)
+			continue
+		}
+
+		d.Runs++
+		if r.SafeMode != "" {
+			d.SafeMode = r.SafeMode
+		}
+		for _, f := range r.Failures {
+			d.Errors = append(d.Errors, notify.DigestError{Action: f.Action, Profile: f.ProfileURL, Reason: f.Reason})
+		}
+		for _, restriction := range r.RestrictionsHit {
+			if !slices.Contains(d.Restrictions, restriction) {
+				d.Restrictions = append(d.Restrictions, restriction)
+			}
+		}
+	}
+	return d, nil
+}
+
+// sendDigest mails the digest of the day of now
+func (b *bot) sendDigest(now time.Time) {
+	d, err := buildDigest(b.cfg, b.db, now)
+	if err != nil {
+		logger.Warnf("Failed to build the daily digest: %v", err)
+		return
+	}
+	if err := b.email.SendDigest(d); err != nil {
+		logger.Warnf("Failed to mail the daily digest: %v", err)
+		return
+	}
+	logger.Infof("Daily digest for %s mailed to %s", d.Date, strings.Join(b.cfg.Notifications.Email.To, ", "))
+}
+
+// mailDigests mails the daily digest at notifications.email.digest_at each day until
+// stop is closed
+func (b *bot) mailDigests(stop <-chan struct{}) {
+	for {
+		now := time.Now()
+		at, _ := b.cfg.Notifications.Email.DigestTime(now)
+		if !at.After(now) {
+			at, _ = b.cfg.Notifications.Email.DigestTime(now.AddDate(0, 0, 1))
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(time.Until(at)):
+		}
+		b.sendDigest(at)
+	}
+}
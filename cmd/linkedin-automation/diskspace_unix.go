@@ -0,0 +1,14 @@
+//go:build !windows
+
+package main
+
+import "golang.org/x/sys/unix"
+
+// freeDiskSpace returns the bytes available to the bot on the filesystem holding path
+func freeDiskSpace(path string) (uint64, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+	return st.Bavail * uint64(st.Bsize), nil
+}
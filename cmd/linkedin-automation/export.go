@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/graph"
+)
+
+// runExport exports outreach data for use in other tools
+func runExport(args []string) int {
+	if len(args) == 0 || args[0] != "graph" {
+		fmt.Fprintln(os.Stderr, "Usage: linkedin-bot export graph [--format dot|graphml] [--status STATUS] [--max-nodes N] [--out FILE]")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("export graph", flag.ContinueOnError)
+	format := fs.String("format", "dot", "output format: dot or graphml")
+	status := fs.String("status", "", "only include prospects with this status, e.g. accepted")
+	campaign := fs.String("campaign", "", "only include prospects found by this campaign")
+	maxNodes := fs.Int("max-nodes", 500, "maximum number of prospect nodes (0 for no cap)")
+	out := fs.String("out", "", "output file (default outreach.dot or outreach.graphml)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	if *format != "dot" && *format != "graphml" {
+		fmt.Fprintf(os.Stderr, "Unsupported format: %s\n", *format)
+		return 2
+	}
+
+	path := *out
+	if path == "" {
+		path = "outreach." + *format
+	}
+
+	db, err := openDB()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	records, err := db.GetOutreachRecords(*status, *campaign, *maxNodes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load outreach records: %v\n", err)
+		return 1
+	}
+
+	g := graph.Build(records, *maxNodes)
+
+	f, err := os.Create(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create %s: %v\n", path, err)
+		return 1
+	}
+	defer f.Close()
+
+	if *format == "graphml" {
+		err = g.WriteGraphML(f)
+	} else {
+		err = g.WriteDOT(f)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write graph: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Wrote %d nodes and %d edges to %s\n", len(g.Nodes), len(g.Edges), path)
+	return 0
+}
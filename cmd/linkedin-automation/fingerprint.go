@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/session"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// runFingerprint shows or rotates the browser fingerprint an account presents
+func runFingerprint(args []string) int {
+	usage := "Usage: linkedin-bot fingerprint show|rotate [ACCOUNT]"
+	if len(args) == 0 || len(args) > 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	account := os.Getenv("LINKEDIN_EMAIL")
+	if len(args) == 2 {
+		account = args[1]
+	}
+	if account == "" {
+		fmt.Fprintln(os.Stderr, "Name the account, or set LINKEDIN_EMAIL")
+		return 2
+	}
+
+	db, err := openDB()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	switch args[0] {
+	case "show":
+		stored, err := db.GetFingerprint(account)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		if stored == nil {
+			fmt.Printf("No fingerprint stored for %s yet; one is chosen on its first session\n", account)
+			return 0
+		}
+		printFingerprint(account, fromStored(stored))
+		return 0
+	case "rotate":
+		cfg, err := config.LoadConfig(getConfigPath())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+			return 1
+		}
+		masker := stealth.NewFingerprintMasker(cfg.Browser.UserAgents, session.Viewports(cfg.Browser.Viewports), session.Hardware(cfg.Browser.Hardware))
+		fp, err := rotateFingerprint(db, cfg, account, masker)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		fmt.Println("New fingerprint, used from the next session on:")
+		printFingerprint(account, fp)
+		return 0
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+}
+
+// printFingerprint prints a fingerprint one part per line
+func printFingerprint(account string, fp stealth.Fingerprint) {
+	fmt.Printf("Account:    %s\n", account)
+	fmt.Printf("User-Agent: %s\n", fp.UserAgent)
+	fmt.Printf("Viewport:   %s (%dx%d @%gx)\n", fp.Viewport.Label, fp.Viewport.Width, fp.Viewport.Height, fp.Viewport.DeviceScaleFactor)
+	fmt.Printf("Timezone:   %s\n", fp.Timezone)
+	fmt.Printf("Languages:  %s\n", strings.Join(fp.Languages, ", "))
+	fmt.Printf("Platform:   %s\n", fp.Platform)
+	fmt.Printf("WebGL:      %s / %s\n", fp.Hardware.WebGLVendor, fp.Hardware.WebGLRenderer)
+	fmt.Printf("Hardware:   %d cores, %g GB memory\n", fp.Hardware.HardwareConcurrency, fp.Hardware.DeviceMemory)
+	fmt.Printf("Canvas:     seed %d\n", fp.CanvasSeed)
+}
+
+// accountFingerprint returns the fingerprint the account presented before. On its first
+// session one is chosen and stored; a stored one is completed, or given a new user agent
+// or viewport once its old one is no longer configured.
+func accountFingerprint(db *storage.DB, cfg *config.Config, account string, masker *stealth.FingerprintMasker) stealth.Fingerprint {
+	timezone, locale := cfg.Stealth.Scheduling.Timezone, cfg.Locale
+
+	stored, err := db.GetFingerprint(account)
+	if err != nil {
+		logger.Warnf("Failed to load the account's fingerprint, using a new one for this session: %v", err)
+		return masker.NewFingerprint(timezone, locale)
+	}
+
+	if stored == nil {
+		fp, err := rotateFingerprint(db, cfg, account, masker)
+		if err != nil {
+			logger.Warnf("Failed to store the account's fingerprint: %v", err)
+		}
+		return fp
+	}
+
+	fp, changed := masker.Complete(fromStored(stored), timezone, locale)
+	if changed {
+		logger.Infof("Updated the account's stored fingerprint to the configured user agents and viewports")
+		if err := db.SaveFingerprint(toStored(account, fp)); err != nil {
+			logger.Warnf("Failed to store the account's fingerprint: %v", err)
+		}
+	}
+	return fp
+}
+
+// rotateFingerprint chooses a new fingerprint for the account and stores it
+func rotateFingerprint(db *storage.DB, cfg *config.Config, account string, masker *stealth.FingerprintMasker) (stealth.Fingerprint, error) {
+	fp := masker.NewFingerprint(cfg.Stealth.Scheduling.Timezone, cfg.Locale)
+	if err := db.SaveFingerprint(toStored(account, fp)); err != nil {
+		return fp, err
+	}
+	db.LogActivity("fingerprint_rotated", fmt.Sprintf("%s: %s, %s", account, fp.Viewport.Label, fp.UserAgent))
+	return fp, nil
+}
+
+// fromStored converts a stored fingerprint for the fingerprint masker
+func fromStored(stored *storage.Fingerprint) stealth.Fingerprint {
+	return stealth.Fingerprint{
+		UserAgent: stored.UserAgent,
+		Viewport: stealth.Viewport{
+			Width:             stored.ViewportWidth,
+			Height:            stored.ViewportHeight,
+			DeviceScaleFactor: stored.DeviceScaleFactor,
+			Label:             stored.ViewportLabel,
+		},
+		Timezone:  stored.Timezone,
+		Languages: stored.Languages,
+		Platform:  stored.Platform,
+		Hardware: stealth.Hardware{
+			Platform:            stored.HardwarePlatform,
+			WebGLVendor:         stored.WebGLVendor,
+			WebGLRenderer:       stored.WebGLRenderer,
+			HardwareConcurrency: stored.HardwareConcurrency,
+			DeviceMemory:        stored.DeviceMemory,
+		},
+		CanvasSeed: stored.CanvasSeed,
+	}
+}
+
+// toStored converts a fingerprint for storage under the account
+func toStored(account string, fp stealth.Fingerprint) *storage.Fingerprint {
+	return &storage.Fingerprint{
+		Account:             account,
+		UserAgent:           fp.UserAgent,
+		ViewportLabel:       fp.Viewport.Label,
+		ViewportWidth:       fp.Viewport.Width,
+		ViewportHeight:      fp.Viewport.Height,
+		DeviceScaleFactor:   fp.Viewport.DeviceScaleFactor,
+		Timezone:            fp.Timezone,
+		Languages:           fp.Languages,
+		Platform:            fp.Platform,
+		HardwarePlatform:    fp.Hardware.Platform,
+		WebGLVendor:         fp.Hardware.WebGLVendor,
+		WebGLRenderer:       fp.Hardware.WebGLRenderer,
+		HardwareConcurrency: fp.Hardware.HardwareConcurrency,
+		DeviceMemory:        fp.Hardware.DeviceMemory,
+		CanvasSeed:          fp.CanvasSeed,
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger("error", "console", "stdout", logger.Rotation{})
+	os.Exit(m.Run())
+}
+
+const (
+	windowsUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+	macUA     = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+)
+
+var (
+	laptop  = stealth.Viewport{Width: 1440, Height: 900, DeviceScaleFactor: 2, Label: "laptop"}
+	desktop = stealth.Viewport{Width: 1920, Height: 1080, DeviceScaleFactor: 1, Label: "desktop"}
+)
+
+func TestAccountFingerprintPersists(t *testing.T) {
+	db, err := storage.NewDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	cfg := &config.Config{Locale: "de-DE"}
+	cfg.Stealth.Scheduling.Timezone = "Europe/Berlin"
+	masker := func(userAgents ...string) *stealth.FingerprintMasker {
+		return stealth.NewFingerprintMasker(userAgents, []stealth.Viewport{laptop, desktop}, nil)
+	}
+
+	first := accountFingerprint(db, cfg, "ada@example.com", masker(windowsUA, macUA))
+	if first.Timezone != "Europe/Berlin" || !reflect.DeepEqual(first.Languages, []string{"de-DE", "de", "en-US", "en"}) || first.CanvasSeed == 0 {
+		t.Fatalf("first fingerprint = %+v", first)
+	}
+
+	// Later sessions, on new maskers, present the same browser; the login's case doesn't matter
+	for i := 0; i < 5; i++ {
+		if again := accountFingerprint(db, cfg, " Ada@Example.com", masker(windowsUA, macUA)); !reflect.DeepEqual(again, first) {
+			t.Fatalf("session %d presented %+v, want %+v", i+2, again, first)
+		}
+	}
+
+	// A user agent dropped from the config is replaced, the rest kept
+	kept := windowsUA
+	if first.UserAgent == windowsUA {
+		kept = macUA
+	}
+	replaced := accountFingerprint(db, cfg, "ada@example.com", masker(kept))
+	if replaced.UserAgent != kept || replaced.Viewport != first.Viewport || replaced.CanvasSeed != first.CanvasSeed {
+		t.Fatalf("after dropping the user agent: %+v, was %+v", replaced, first)
+	}
+	if stored, _ := db.GetFingerprint("ada@example.com"); stored.UserAgent != kept {
+		t.Fatalf("stored user agent = %s, want the replacement stored", stored.UserAgent)
+	}
+
+	// Another account gets its own
+	if other := accountFingerprint(db, cfg, "grace@example.com", masker(windowsUA, macUA)); other.CanvasSeed == first.CanvasSeed {
+		t.Fatal("two accounts share a canvas seed")
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+)
+
+// historyEntry is one audited action in a profile's history
+type historyEntry struct {
+	At         time.Time `json:"at"`
+	Action     string    `json:"action"`
+	Result     string    `json:"result,omitempty"`
+	DurationMs int64     `json:"duration_ms,omitempty"`
+	Details    string    `json:"details,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	PageURL    string    `json:"page_url,omitempty"`
+	RunID      string    `json:"run_id,omitempty"`
+}
+
+// runHistory prints everything the bot did to one profile, oldest first
+func runHistory(args []string) int {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	profileFlag := fs.String("profile", "", "profile URL to show the history of")
+	formatFlag := fs.String("format", "text", "output format: text or json")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *profileFlag == "" {
+		fmt.Fprintln(os.Stderr, "Usage: linkedin-bot history --profile URL [--format text|json]")
+		return 2
+	}
+	if *formatFlag != "text" && *formatFlag != "json" {
+		fmt.Fprintf(os.Stderr, "Unknown --format %q (use text or json)\n", *formatFlag)
+		return 2
+	}
+
+	db, err := openDB()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	activities, err := db.ProfileHistory(*profileFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load history: %v\n", err)
+		return 1
+	}
+
+	entries := make([]historyEntry, len(activities))
+	for i, a := range activities {
+		entries[i] = historyEntry{
+			At:         a.Timestamp,
+			Action:     a.Action,
+			Result:     a.Result,
+			DurationMs: a.Duration.Milliseconds(),
+			Details:    a.Details,
+			Error:      a.Error,
+			PageURL:    a.PageURL,
+			RunID:      a.RunID,
+		}
+	}
+
+	if *formatFlag == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entries); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write history: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Printf("History of %s\n\n", *profileFlag)
+	db.SetReinviteCooldown(reinviteCooldownDays())
+	eligibleAt, cooling, err := db.ReinviteEligibleAt(*profileFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if len(entries) == 0 {
+		fmt.Println("Nothing recorded.")
+		printReinvite(eligibleAt, cooling)
+		return 0
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tACTION\tRESULT\tTOOK\tDETAILS\tPAGE\tRUN")
+	for _, e := range entries {
+		details := e.Details
+		if e.Error != "" {
+			details = e.Error
+		}
+		took := "-"
+		if e.DurationMs > 0 {
+			took = (time.Duration(e.DurationMs) * time.Millisecond).Round(100 * time.Millisecond).String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", e.At.Local().Format("2006-01-02 15:04:05"), e.Action,
+			orDash(e.Result), took, orDash(details), orDash(e.PageURL), orDash(e.RunID))
+	}
+	w.Flush()
+	printReinvite(eligibleAt, cooling)
+	return 0
+}
+
+// printReinvite tells when a profile whose invite was withdrawn or left pending may be
+// invited again, if it may
+func printReinvite(eligibleAt time.Time, ok bool) {
+	if !ok {
+		return
+	}
+	if eligibleAt.After(time.Now()) {
+		fmt.Printf("\nInvite withdrawn or pending: eligible again on %s\n", eligibleAt.Local().Format("2006-01-02"))
+	} else {
+		fmt.Printf("\nInvite withdrawn or pending: eligible again since %s\n", eligibleAt.Local().Format("2006-01-02"))
+	}
+}
+
+// reinviteCooldownDays returns connections.reinvite_cooldown_days of the current config,
+// 0 when it can't be loaded
+func reinviteCooldownDays() int {
+	cfg, err := config.LoadConfig(getConfigPath())
+	if err != nil {
+		return 0
+	}
+	return cfg.Connections.ReinviteCooldownDays
+}
@@ -0,0 +1,655 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/artifacts"
+	"github.com/Tanukumar01/linkedin-automation/internal/auth"
+	"github.com/Tanukumar01/linkedin-automation/internal/breaker"
+	"github.com/Tanukumar01/linkedin-automation/internal/budget"
+	"github.com/Tanukumar01/linkedin-automation/internal/captcha"
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/connections"
+	"github.com/Tanukumar01/linkedin-automation/internal/control"
+	"github.com/Tanukumar01/linkedin-automation/internal/humanize"
+	"github.com/Tanukumar01/linkedin-automation/internal/instance"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/messaging"
+	"github.com/Tanukumar01/linkedin-automation/internal/notify"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/phase"
+	"github.com/Tanukumar01/linkedin-automation/internal/planner"
+	"github.com/Tanukumar01/linkedin-automation/internal/report"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
+	"github.com/Tanukumar01/linkedin-automation/internal/session"
+	"github.com/Tanukumar01/linkedin-automation/internal/snapshot"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+	"github.com/Tanukumar01/linkedin-automation/pkg/linkedin"
+)
+
+// Exit codes for runs that stop at a session boundary, so a supervisor can tell them apart
+const (
+	exitSessionEnded    = 3 // the session cap was hit; the next session may start later today
+	exitDailyCapReached = 4 // today's active time is used up; start again tomorrow
+	exitSafeMode        = 5 // the bot is in safe mode; nothing runs until it is resumed
+	exitLocked          = 6 // another instance is running for the account
+)
+
+// cookieFile keeps the login session between runs
+const cookieFile = "cookies.json"
+
+func main() {
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		fmt.Println("Warning: .env file not found, using system environment variables")
+	}
+
+	// "run" followed by flags, e.g. run --daemon, runs the workflow; run show inspects past runs
+	args := os.Args[1:]
+	if len(args) > 1 && args[0] == "run" && strings.HasPrefix(args[1], "-") {
+		args = args[1:]
+	}
+
+	// Run a subcommand instead of the bot if one was given
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		os.Exit(runCommand(args[0], args[1:]))
+	}
+
+	opts, err := parseRunFlags(args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			printUsage()
+			os.Exit(0)
+		}
+		os.Exit(2)
+	}
+
+	// Load configuration
+	cfg, err := config.LoadConfig(getConfigPath())
+	if err != nil {
+		fmt.Printf("Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	campaigns, err := selectCampaigns(cfg, opts.campaign)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(2)
+	}
+	if err := checkReviewTimeout(opts, cfg); err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(2)
+	}
+
+	// Deferred first so it runs after the database and logger are closed
+	exitCode := 0
+	defer func() {
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	}()
+
+	// Initialize logger
+	if err := logger.InitLogger(cfg.Logging.Level, cfg.Logging.Format, cfg.Logging.Output, logger.Rotation{
+		MaxSizeMB:  cfg.Logging.Rotation.MaxSizeMB,
+		MaxAgeDays: cfg.Logging.Rotation.MaxAgeDays,
+		MaxBackups: cfg.Logging.Rotation.MaxBackups,
+	}); err != nil {
+		fmt.Printf("Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer logger.Sync()
+
+	logger.Info("Starting LinkedIn Automation Bot")
+
+	for _, clamp := range cfg.Safety.Clamped {
+		logger.Warnf("SAFETY CEILING: %s; set safety.i_know_what_im_doing and a safety.overrides value to lift it", clamp)
+	}
+
+	// Initialize notifications
+	var notifier notify.Notifier = notify.Nop{}
+	if cfg.Notifications.WebhookURL != "" {
+		notifier = notify.NewWebhookNotifier(cfg.Notifications.WebhookURL, cfg.Notifications.Format, cfg.Notifications.Events)
+		logger.Info("Webhook notifications enabled")
+	}
+	var email *notify.EmailNotifier
+	if cfg.Notifications.Email.Enabled {
+		email = notify.NewEmailNotifier(emailConfig(cfg))
+		notifier = notify.Multi{notifier, email}
+		logger.Infof("Email digest enabled for %s", strings.Join(cfg.Notifications.Email.To, ", "))
+	}
+
+	// Load credentials
+	creds, err := config.LoadCredentials()
+	if err != nil {
+		logger.Fatalf("Failed to load credentials: %v", err)
+	}
+	// One copy of the bot per account; a second would send duplicate invites
+	lockPath := instance.Path(getDBPath(), creds.Email)
+	lock, err := instance.Acquire(lockPath, creds.Email)
+	if errors.Is(err, instance.ErrLocked) {
+		logger.Errorf("Not running: %v", err)
+		exitCode = exitLocked
+		return
+	}
+	if err != nil {
+		logger.Fatalf("Failed to take the instance lock: %v", err)
+	}
+	defer lock.Release()
+
+	cookieSecret, err := auth.CookieSecretFromEnv()
+	if err != nil {
+		logger.Fatalf("Failed to load cookie encryption secret: %v", err)
+	}
+
+	// Initialize database
+	db, err := openDB()
+	if err != nil {
+		logger.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	for _, m := range db.Migrated() {
+		logger.Infof("Applied database migration %s", m)
+	}
+	logger.Infof("Database initialized (schema version %d)", storage.SchemaVersion())
+	db.SetReinviteCooldown(cfg.Connections.ReinviteCooldownDays)
+
+	// Load selector overrides kept next to the config file
+	if err := selectors.Load(filepath.Join(filepath.Dir(getConfigPath()), "selectors.yaml")); err != nil {
+		logger.Fatalf("Failed to load selectors: %v", err)
+	}
+	selectors.SetRecorder(db)
+
+	scheduler, err := newScheduler(cfg, db, notifier)
+	if err != nil {
+		logger.Fatalf("Failed to initialize scheduler: %v", err)
+	}
+
+	// The first SIGTERM or Ctrl+C lets the profile in hand finish; a second one exits at once
+	stopOnSignal(scheduler, lock)
+
+	// ctl pause and ctl resume from another terminal
+	stopControl := control.Watch(lockPath, scheduler)
+	defer stopControl()
+
+	b := &bot{
+		cfg:       cfg,
+		campaigns: campaigns,
+		creds:     creds,
+		lock:      lock,
+		secret:    cookieSecret,
+		db:        db,
+		scheduler: scheduler,
+		notifier:  notifier,
+		email:     email,
+	}
+	if opts.review > 0 {
+		b.reviewer = newInviteReviewer(opts.review, opts.reviewTimeout)
+	}
+
+	if opts.daemon {
+		exitCode = runDaemon(b)
+		return
+	}
+
+	sessionErr := b.runSession()
+	switch {
+	case errors.Is(sessionErr, stealth.ErrDailyActiveCapReached):
+		logger.Infof("Daily active time used, exiting until tomorrow: %v", sessionErr)
+		exitCode = exitDailyCapReached
+		return
+	case errors.Is(sessionErr, stealth.ErrSessionEnded):
+		// Idle through a break-length gap so a supervisor restarting on exit begins a fresh session
+		next := scheduler.NextSessionStart(time.Now())
+		logger.Infof("Next session may start at %s", next.Format(time.RFC1123))
+		if next.Before(scheduler.ActiveWindowEnd(time.Now())) {
+			scheduler.WaitUntil(next)
+		}
+		exitCode = exitSessionEnded
+		return
+	case errors.Is(sessionErr, stealth.ErrStopped):
+		logger.Info("Shut down on request")
+	case errors.Is(sessionErr, errSafeMode):
+		logger.Errorf("Not running: %v", sessionErr)
+		exitCode = exitSafeMode
+		return
+	case sessionErr != nil:
+		logger.Errorf("Session failed: %v", sessionErr)
+		exitCode = 1
+		return
+	}
+
+	logger.Info("LinkedIn Automation Bot finished")
+}
+
+// bot holds what outlives a session: the configuration, database and scheduler
+type bot struct {
+	cfg       *config.Config
+	campaigns []config.CampaignConfig
+	creds     *config.Credentials
+	lock      *instance.Lock     // held for the account while the bot runs
+	secret    *auth.CookieSecret // encrypts the cookie file; nil keeps it in plaintext
+	db        *storage.DB
+	scheduler *stealth.Scheduler
+	notifier  notify.Notifier
+	reviewer  *inviteReviewer // approves the first invites at the terminal; nil without --review
+
+	email            *notify.EmailNotifier // mails the daily digest; nil unless notifications.email is enabled
+	digestOnSchedule bool                  // the daemon mails the digest at digest_at instead of after each run
+}
+
+// runSession opens a browser, logs in and works through the day's campaigns, invites
+// and messages until they are done or a time cap is hit. It returns the cap that ended
+// the session, ErrStopped after a shutdown request, or why the session couldn't run;
+// nil means the work was done.
+func (b *bot) runSession() error {
+	cfg, db, scheduler, notifier := b.cfg, b.db, b.scheduler, b.notifier
+
+	// Every log line and activity row of this session carries its ID, the same one that names its report
+	runReport := report.NewRunReport()
+	logger.Bind("run_id", runReport.ID())
+	db.SetRunID(runReport.ID())
+
+	// A run that stopped itself stays stopped until someone has looked into why
+	if mode, err := db.GetSafeMode(); err != nil {
+		return err
+	} else if mode != nil {
+		return safeModeError(mode)
+	}
+
+	// Check if within business hours
+	if !scheduler.IsBusinessHours() {
+		logger.Info("Outside business hours, waiting...")
+		scheduler.WaitForBusinessHours()
+	}
+
+	// Active time counts from here; refuse to start once today's cap is used
+	if err := scheduler.StartSession(); err != nil {
+		return err
+	}
+	ended := false
+	defer func() {
+		if !ended {
+			scheduler.EndSession()
+		}
+	}()
+
+	client, err := b.openClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	sess := client.Session()
+	scheduler.SetFatigue(sess.Fatigue)
+	scheduler.SetMetrics(sess.Metrics)
+	fp := sess.Fingerprint
+
+	// Kept with the run, to compare paces against how their invites fared
+	if p := sess.Persona; p != nil {
+		runReport.Pacing = p
+		if err := db.SaveRunPersona(&storage.RunPersona{RunID: runReport.ID(), Name: p.Name, Tempo: p.Tempo, Care: p.Care,
+			Delays: p.Delays, Typing: p.Typing, Typos: p.Typos, Scroll: p.Scroll, StartedAt: runReport.StartedAt}); err != nil {
+			logger.Warnf("%v", err)
+		}
+	}
+
+	// Record the settings and persona in effect, for postmortems
+	if snap, err := snapshot.Take(cfg, snapshot.Persona{UserAgent: fp.UserAgent, ViewportWidth: fp.Viewport.Width, ViewportHeight: fp.Viewport.Height}); err != nil {
+		logger.Warnf("Failed to snapshot config: %v", err)
+	} else {
+		runReport.Snapshot = snap
+		if err := snap.Save(db); err != nil {
+			logger.Warnf("Failed to store config snapshot: %v", err)
+		}
+		logger.Infof("Config snapshot %s", snap.Hash)
+	}
+
+	// Each kind of work keeps to its own tab
+	timing := sess.Timing
+	searchTab, profileTab, messagingTab := sess.Tab(session.TabSearch), sess.Tab(session.TabProfile), sess.Tab(session.TabMessaging)
+
+	authenticator, err := b.login(client)
+	if err != nil {
+		return err
+	}
+
+	// LinkedIn extends the session as it is used; keep the saved cookies up to date once the work is done
+	defer func() {
+		if err := authenticator.RefreshSavedSession(); err != nil {
+			logger.Warnf("%v", err)
+		}
+	}()
+
+	// Initialize message manager
+	// Failure artifacts are only collected when debug.capture_on_error is set
+	collector := artifacts.NewCollector(cfg.Debug)
+	if collector != nil {
+		logger.Infof("Capturing failure artifacts into %s", cfg.Debug.ArtifactsDir)
+	}
+
+	msgManager := messaging.NewMessageManager(messagingTab.Pages, &cfg.Messaging, db, timing, messagingTab.Typer, messagingTab.Clicker, messagingTab.Scroller)
+	msgManager.SetArtifacts(collector)
+	msgManager.SetLocale(cfg.Locale)
+	msgManager.SetProfileTimeout(cfg.Connections.ProfileTimeout())
+
+	// Profile actions that keep failing put the bot in safe mode
+	safety := breaker.New(cfg.Safety.Breaker)
+	msgManager.SetBreaker(safety)
+
+	// Each phase wraps up once it has used its share of the session
+	phases := phase.NewTracker()
+	for _, name := range config.Phases {
+		phases.Add(name, cfg.PhaseBudget(name))
+	}
+	messagingBudget := phases.Get(config.PhaseMessaging)
+	msgManager.SetCheckpoint(func() bool { return messagingBudget.Exceeded() || scheduler.SessionOver() })
+
+	// Main automation loop
+	logger.Info("Starting automation workflow")
+
+	// Verify requests an interrupted run may have sent without recording
+	reconciler := connections.NewConnectionManager(profileTab.Pages, &cfg.Connections, db, timing, profileTab.Typer, profileTab.Clicker, profileTab.Scroller)
+	if reconciled, err := reconciler.ReconcileInFlight(); err != nil {
+		logger.Errorf("Failed to reconcile in-flight requests: %v", err)
+	} else if reconciled.Confirmed+reconciled.Discarded+reconciled.Unresolved > 0 {
+		logger.Infof("Reconciled in-flight requests: %d confirmed, %d discarded, %d unresolved",
+			reconciled.Confirmed, reconciled.Discarded, reconciled.Unresolved)
+	}
+	// Prospects an interrupted run left in progress go back in line
+	if requeued, err := db.RequeueInProgress(); err != nil {
+		logger.Errorf("%v", err)
+	} else if requeued > 0 {
+		logger.Infof("Requeued %d prospects left in progress by an interrupted run", requeued)
+	}
+
+	// Work out today's budgets, slots and queues; the plan command previews exactly this
+	now := time.Now()
+	today := planToday(cfg, db, scheduler, b.campaigns, now)
+	for _, line := range today.Lines() {
+		logger.Info(line)
+	}
+	planner.NewPlanner(cfg, db).Record(today.Decision, now)
+	runReport.Planner = today.Decision
+
+	// Messages, invites, visits and likes all take from the day's action budget, if any
+	actions := today.Actions
+	msgManager.SetActionBudget(actions)
+
+	// Spread today's remaining connection requests across business hours
+	var plan *dayPlan
+	if cfg.Stealth.Scheduling.SpreadActions {
+		if plan, err = loadDayPlan(db, scheduler, today); err != nil {
+			logger.Errorf("Failed to plan the day, sending without a plan: %v", err)
+		}
+	}
+
+	// Idle browsing between requests, only when stealth.humanize is enabled
+	idle := humanize.NewHumanizer(cfg.Stealth.Humanize, profileTab.Pages, profileTab.Scroller, profileTab.Mouse, profileTab.Clicker, timing, db)
+
+	// A run paused with ctl pause stops the phase clocks and, unless told not to, browses now and then
+	var keepAlive func()
+	if cfg.Session.PauseKeepAlive == config.PauseKeepAliveIdle {
+		humanizeCfg := cfg.Stealth.Humanize
+		humanizeCfg.Enabled = true
+		keepAlive = humanize.NewHumanizer(humanizeCfg, profileTab.Pages, profileTab.Scroller, profileTab.Mouse, profileTab.Clicker, timing, db).Idle
+	}
+	scheduler.SetPauseHooks(phases.Hold, keepAlive, time.Duration(cfg.Session.PauseKeepAliveMinutes)*time.Minute)
+
+	// A profile behind LinkedIn's sign-in wall means the session was signed out, not that the profile is gone
+	ensureLoggedIn := func() error { return authenticator.EnsureLoggedIn(b.creds.Email, b.creds.Password) }
+
+	// Steps 1 and 2: Search for profiles and send connection requests, campaign by campaign
+	run := &campaignRun{cfg: cfg, searchTab: searchTab, profileTab: profileTab, db: db, timing: timing, scheduler: scheduler,
+		plan: plan, phases: phases, idle: idle, notifier: notifier, collector: collector, safety: safety, reviewer: b.reviewer,
+		ensureLoggedIn: ensureLoggedIn, actions: actions, report: runReport}
+	budgets := today.Budgets
+	for i := range b.campaigns {
+		campaign := &b.campaigns[i]
+		runReport.RecordCampaignBudget(campaign.Name, budgets[campaign.Name])
+
+		if stop := runCampaign(run, campaign, budgets[campaign.Name]); stop {
+			break
+		}
+	}
+
+	// Step 3: Process incoming invitations
+	if cfg.Invites.Enabled && !scheduler.SessionOver() && !safety.Tripped() {
+		logger.Info("Step 3: Processing incoming invitations...")
+		invitesBudget := phases.Get(config.PhaseInvites)
+		invitesBudget.Start()
+		processInvites(cfg, messagingTab.Pages, db, timing, messagingTab.Clicker, messagingTab.Scroller, msgManager, func() bool { return invitesBudget.Exceeded() || scheduler.SessionOver() }, safety, runReport)
+		invitesBudget.Stop()
+	}
+
+	// Step 4: Detect accepted requests and send follow-ups and sequence steps that are due
+	messagingBudget.Start()
+	sequences := len(cfg.Messaging.Sequences) > 0
+	if (cfg.Messaging.AcceptanceMessage.Enabled || sequences) && !scheduler.SessionOver() && !safety.Tripped() {
+		logger.Info("Step 4: Checking for accepted connections...")
+		poller := connections.NewAcceptancePoller(messagingTab.Pages, db, timing, messagingTab.Scroller)
+		accepted, err := poller.Poll()
+		if err != nil {
+			logger.Errorf("Failed to check for accepted connections: %v", err)
+		} else if len(accepted) > 0 {
+			// Connections with a sequence get it instead of the single acceptance message
+			enrolled, rest := msgManager.EnrollInSequences(accepted)
+			scheduled := 0
+			if cfg.Messaging.AcceptanceMessage.Enabled {
+				scheduled = msgManager.ScheduleAcceptanceMessages(rest, scheduler)
+			}
+			logger.Infof("%d newly accepted connections, %d enrolled in sequences, %d follow-ups scheduled", len(accepted), enrolled, scheduled)
+		}
+	}
+
+	if !safety.Tripped() {
+		logger.Info("Sending scheduled follow-up messages...")
+		sent, err := msgManager.SendDueMessages()
+		if err == nil && sequences {
+			var steps []*messaging.MessageResult
+			steps, err = msgManager.ProcessSequences()
+			sent = append(sent, steps...)
+		}
+		for range sent {
+			runReport.RecordMessageSent()
+		}
+		switch {
+		case err == nil:
+		case errors.Is(err, messaging.ErrDailyLimitReached) || errors.Is(err, messaging.ErrHourlyLimitReached) || errors.Is(err, budget.ErrExhausted):
+			logger.Infof("Message limit reached, remaining follow-ups stay queued: %v", err)
+			runReport.RecordRestriction(err.Error())
+		case errors.Is(err, breaker.ErrTripped):
+			logger.Errorf("Stopping messages: %v", err)
+		default:
+			logger.Errorf("Failed to send scheduled messages: %v", err)
+		}
+	}
+
+	messagingBudget.Stop()
+
+	// The tab in front still shows the last failure
+	var safeModeErr error
+	if safety.Tripped() {
+		safeModeErr = b.enterSafeMode(safety, sess.Active().Pages, runReport)
+	}
+
+	logger.Info("Automation workflow completed")
+
+	// The browser closes when the session returns; today's plan and queues are already stored
+	ended = true
+	sessionErr := scheduler.EndSession()
+	if sessionErr != nil {
+		logger.Infof("Ending session: %v", sessionErr)
+	}
+
+	// Write run report
+	runReport.Stealth = sess.Metrics.Summary()
+	if runReport.ActionBudget, err = actions.Allocations(); err != nil {
+		logger.Warnf("%v", err)
+	}
+	for _, budget := range phases.Budgets() {
+		started, finished := budget.Span()
+		runReport.RecordPhase(budget.Name(), budget.Allowed(), budget.Elapsed(), budget.Overrun(), started, finished)
+	}
+	runReport.Finish()
+	runReport.LogSummary()
+	if path, err := runReport.WriteJSON(cfg.Reporting.Dir); err != nil {
+		logger.Warnf("Failed to write run report: %v", err)
+	} else {
+		logger.Infof("Run report saved to %s", path)
+	}
+
+	// Print daily stats
+	stats, err := db.GetDailyStats(time.Now())
+	if err == nil {
+		logger.Infof("Daily Stats:")
+		logger.Infof("  Connections Sent: %d", stats.ConnectionsSent)
+		logger.Infof("  Connections Accepted: %d", stats.ConnectionsAccepted)
+		logger.Infof("  Messages Sent: %d", stats.MessagesSent)
+		logger.Infof("  Searches Performed: %d", stats.SearchesPerformed)
+
+		event := notify.NewEvent(notify.EventDailySummary, fmt.Sprintf("Daily summary for %s", stats.Date))
+		event.Fields = map[string]interface{}{
+			"connections_sent":     stats.ConnectionsSent,
+			"connections_accepted": stats.ConnectionsAccepted,
+			"messages_sent":        stats.MessagesSent,
+			"searches_performed":   stats.SearchesPerformed,
+		}
+		if err := notifier.Notify(event); err != nil {
+			logger.Warnf("Failed to send daily summary notification: %v", err)
+		}
+	}
+
+	if b.email != nil && !b.digestOnSchedule {
+		b.sendDigest(time.Now())
+	}
+
+	if safeModeErr != nil {
+		return safeModeErr
+	}
+	return sessionErr
+}
+
+// openClient launches the account's browser as a linkedin.Client on the bot's configuration,
+// database and instance lock
+func (b *bot) openClient() (*linkedin.Client, error) {
+	cfg, db := b.cfg, b.db
+	return linkedin.New(
+		linkedin.WithConfig(cfg),
+		linkedin.WithDB(db),
+		linkedin.WithInstanceLock(b.lock),
+		linkedin.WithCookieFile(cookieFile),
+		// The account presents the same browser every session; a new one each time would stand out
+		linkedin.WithSessionOptions(session.Options{
+			Fingerprint: func(masker *stealth.FingerprintMasker) stealth.Fingerprint {
+				return accountFingerprint(db, cfg, b.creds.Email, masker)
+			},
+			Navigations: db,
+			Diagnostics: db,
+		}),
+		// A checkpoint LinkedIn redirects to mid-session is waited on instead of failing as a missing element
+		linkedin.WithCheckpointHandler(func(url string) {
+			event := notify.NewEvent(notify.EventChallenge, "LinkedIn security checkpoint mid-session, resolve it in the browser: "+url)
+			if err := b.notifier.Notify(event); err != nil {
+				logger.Warnf("Failed to send notification: %v", err)
+			}
+		}),
+	)
+}
+
+// login signs in to LinkedIn with client, reusing saved cookies until they expire. The
+// client then verifies the masking, matches texts in the account's UI language and has
+// the pages wait on checkpoints LinkedIn redirects to later on.
+func (b *bot) login(client *linkedin.Client) (*auth.Authenticator, error) {
+	authenticator := client.Authenticator()
+	authenticator.SetNotifier(b.notifier)
+	authenticator.GetCookieManager().SetSecret(b.secret)
+	if solver := captcha.NewSolver(b.cfg.Captcha, b.db); solver != nil {
+		logger.Info("CAPTCHA solving service enabled")
+		authenticator.SetCaptchaSolver(solver)
+	}
+
+	// Login; saved cookies are reused until they expire
+	logger.Info("Attempting to login...")
+	if err := client.Login(context.Background(), b.creds.Email, b.creds.Password); err != nil {
+		// Take screenshot on failure
+		screenshotPath := "login_failure.png"
+		if data, sErr := client.Session().Page.Screenshot(true, nil); sErr == nil {
+			os.WriteFile(screenshotPath, data, 0644)
+			logger.Errorf("Login failed: %v. Screenshot saved to %s", err, screenshotPath)
+		} else {
+			logger.Errorf("Login failed: %v. Also failed to take screenshot: %v", err, sErr)
+		}
+		return nil, err
+	}
+
+	logger.Info("Successfully logged in")
+	if ttl, ok := authenticator.SessionTTL(); ok {
+		logger.Infof("LinkedIn session valid until %s (%s left)", time.Now().Add(ttl).Format("2006-01-02 15:04"), ttl.Round(time.Hour))
+	} else {
+		logger.Info("LinkedIn session lasts until the browser closes")
+	}
+
+	return authenticator, nil
+}
+
+// processInvites accepts matching incoming invitations and welcomes the people we accepted
+func processInvites(cfg *config.Config, page pageops.Page, db *storage.DB, timing *stealth.TimingController, clicker pageops.Clicker, scroller pageops.Scroller, msgManager *messaging.MessageManager, checkpoint func() bool, safety *breaker.Breaker, runReport *report.RunReport) {
+	processor, err := connections.NewIncomingInvitesProcessor(page, &cfg.Invites, db, timing, clicker, scroller)
+	if err != nil {
+		logger.Errorf("Failed to initialize invites processor: %v", err)
+		return
+	}
+	processor.SetCheckpoint(checkpoint)
+
+	result, err := processor.ProcessInvites()
+	if err != nil {
+		logger.Errorf("Failed to process invitations: %v", err)
+	} else {
+		logger.Infof("Invitations: %d accepted, %d ignored, %d left", result.Accepted, result.Ignored, result.Left)
+	}
+
+	invites, err := db.GetInvitesAwaitingWelcome(cfg.Messaging.DailyLimit)
+	if err != nil {
+		logger.Errorf("Failed to get invites awaiting welcome: %v", err)
+		return
+	}
+
+	for _, invite := range invites {
+		if checkpoint() {
+			logger.Info("Invitations time budget or session used, remaining welcome messages wait for the next run")
+			return
+		}
+
+		if _, err := msgManager.SendWelcomeMessage(invite.ProfileURL, invite.InviterName, invite.Headline); err != nil {
+			if errors.Is(err, messaging.ErrDailyLimitReached) || errors.Is(err, messaging.ErrHourlyLimitReached) || errors.Is(err, budget.ErrExhausted) {
+				logger.Infof("Stopping welcome messages: %v", err)
+				runReport.RecordRestriction(err.Error())
+				return
+			}
+
+			logger.With(logger.ProfileFields(invite.ProfileURL, invite.InviterName, "welcome_message")...).Errorf("Failed to send welcome message: %v", err)
+			runReport.RecordFailure("welcome_message", invite.ProfileURL, invite.InviterName, err)
+			if safety.Record(err) {
+				return
+			}
+			continue
+		}
+		safety.Record(nil)
+
+		runReport.RecordMessageSent()
+		if err := db.MarkInviteWelcomed(invite.ProfileURL); err != nil {
+			logger.Errorf("Failed to mark invite as welcomed: %v", err)
+		}
+	}
+}
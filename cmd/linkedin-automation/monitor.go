@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/notify"
+	"github.com/Tanukumar01/linkedin-automation/internal/search"
+	"github.com/Tanukumar01/linkedin-automation/internal/session"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// monitorUsage is printed when the monitor command is misused
+const monitorUsage = "Usage: linkedin-bot monitor [--name NAME] [--export FILE]"
+
+// monitorMatch is a profile a monitor found for the first time
+type monitorMatch struct {
+	Monitor  string `json:"monitor"`
+	Name     string `json:"name"`
+	Title    string `json:"title"`
+	Company  string `json:"company"`
+	Location string `json:"location"`
+	URL      string `json:"url"`
+}
+
+// runMonitor runs the configured monitors' searches and reports the profiles that
+// appeared since their last run, without contacting anyone
+func runMonitor(args []string) int {
+	fs := flag.NewFlagSet("monitor", flag.ContinueOnError)
+	nameFlag := fs.String("name", "", "run only this monitor")
+	exportFlag := fs.String("export", "", "also write the new matches to this CSV file")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, monitorUsage)
+		return 2
+	}
+
+	cfg, err := config.LoadConfig(getConfigPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+	monitors, err := selectMonitors(cfg, *nameFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 2
+	}
+
+	db, err := openDB()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	if !initCommandLogger(cfg) {
+		return 1
+	}
+	defer logger.Sync()
+
+	var notifier notify.Notifier = notify.Nop{}
+	if cfg.Notifications.WebhookURL != "" {
+		notifier = notify.NewWebhookNotifier(cfg.Notifications.WebhookURL, cfg.Notifications.Format, cfg.Notifications.Events)
+	}
+
+	sess, code := openAccountSession(cfg, db, "monitoring")
+	if sess == nil {
+		return code
+	}
+	defer sess.Close()
+
+	tab := sess.Tab(session.TabSearch)
+	var matches []monitorMatch
+	failed := false
+	for i, monitor := range monitors {
+		if i > 0 {
+			sess.Timing.Wait(sess.Timing.ActionDelay())
+		}
+
+		searchCfg := cfg.Search
+		searchCfg.Filters = monitor.Filters
+		searcher := search.NewSearcher(tab.Pages, &searchCfg, db, sess.Timing, tab.Scroller, tab.Clicker)
+		searcher.SetCampaign(monitor.Name)
+		searcher.SetMonitor(monitor.MaxPages)
+
+		// The last run is looked up first; this one is recorded below
+		last, err := db.GetLastMonitorRun(monitor.Name)
+		if err != nil {
+			logger.Errorf("%v", err)
+			failed = true
+			continue
+		}
+
+		logger.Infof("Running monitor %s (%d pages)", monitor.Name, monitor.MaxPages)
+		summary, err := searcher.Search()
+		if err != nil {
+			logger.Errorf("Monitor %s failed: %v", monitor.Name, err)
+			failed = true
+			continue
+		}
+
+		run := &storage.MonitorRun{Monitor: monitor.Name, RanAt: time.Now(), Seen: len(summary.Results), New: len(summary.New)}
+		if err := db.SaveMonitorRun(run); err != nil {
+			logger.Errorf("%v", err)
+		}
+
+		// Everything is new to a monitor's first run; it only sets what later runs compare against
+		if last == nil {
+			logger.Infof("Monitor %s: first run, %d profiles recorded; new ones are reported from the next run on", monitor.Name, len(summary.New))
+			continue
+		}
+
+		found := monitorMatches(monitor.Name, summary.New)
+		matches = append(matches, found...)
+		digest(monitor.Name, last.RanAt, len(summary.Results), found, notifier)
+	}
+
+	if *exportFlag != "" && len(matches) > 0 {
+		if err := exportMatches(*exportFlag, matches); err != nil {
+			logger.Errorf("%v", err)
+			return 1
+		}
+		logger.Infof("Wrote %d new matches to %s", len(matches), *exportFlag)
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// selectMonitors returns the monitors to run; an empty name selects all of them
+func selectMonitors(cfg *config.Config, name string) ([]config.MonitorConfig, error) {
+	if len(cfg.Monitors) == 0 {
+		return nil, fmt.Errorf("no monitors configured; add them under monitors: in the config")
+	}
+	if name == "" {
+		return cfg.Monitors, nil
+	}
+
+	for _, monitor := range cfg.Monitors {
+		if monitor.Name == name {
+			return []config.MonitorConfig{monitor}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown monitor: %s", name)
+}
+
+// monitorMatches describes a monitor's new profiles, preferring the role and
+// organization parsed from the headline
+func monitorMatches(monitor string, results []search.ProfileResult) []monitorMatch {
+	matches := make([]monitorMatch, 0, len(results))
+	for _, r := range results {
+		title, company := r.Headline.Title, r.Company
+		if title == "" {
+			title = r.JobTitle
+		}
+		if company == "" {
+			company = r.Headline.Company
+		}
+		matches = append(matches, monitorMatch{Monitor: monitor, Name: r.Name, Title: title, Company: company, Location: r.Location, URL: r.URL})
+	}
+	return matches
+}
+
+// digest logs a monitor's new matches and sends them as a notification
+func digest(monitor string, since time.Time, seen int, matches []monitorMatch, notifier notify.Notifier) {
+	logger.Infof("Monitor %s: %d new of %d profiles since %s", monitor, len(matches), seen, since.Format("2006-01-02 15:04"))
+	if len(matches) == 0 {
+		return
+	}
+
+	lines := make([]string, len(matches))
+	for i, m := range matches {
+		lines[i] = describeMatch(m)
+		logger.Infof("  %s", lines[i])
+	}
+
+	event := notify.NewEvent(notify.EventMonitorNew, fmt.Sprintf("%d new matches for %s:\n%s", len(matches), monitor, strings.Join(lines, "\n")))
+	event.Fields = map[string]interface{}{
+		"monitor": monitor,
+		"matches": matches,
+	}
+	if err := notifier.Notify(event); err != nil {
+		logger.Warnf("Failed to send monitor notification: %v", err)
+	}
+}
+
+// describeMatch renders a match as one line: name, title at company, URL
+func describeMatch(m monitorMatch) string {
+	line := m.Name
+	switch {
+	case m.Title != "" && m.Company != "":
+		line += fmt.Sprintf(", %s at %s", m.Title, m.Company)
+	case m.Title != "":
+		line += ", " + m.Title
+	case m.Company != "":
+		line += ", " + m.Company
+	}
+	return line + " " + m.URL
+}
+
+// exportMatches writes the matches to a CSV file
+func exportMatches(path string, matches []monitorMatch) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"monitor", "name", "title", "company", "location", "url"})
+	for _, m := range matches {
+		w.Write([]string{m.Monitor, m.Name, m.Title, m.Company, m.Location, m.URL})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,422 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/auth"
+	"github.com/Tanukumar01/linkedin-automation/internal/budget"
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/connections"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/notify"
+	"github.com/Tanukumar01/linkedin-automation/internal/planner"
+	"github.com/Tanukumar01/linkedin-automation/internal/report"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// planQueuePreview is how many queued items per phase the plan lists
+const planQueuePreview = 5
+
+// minFreeDisk is the free space below which the database and reports may fail to write
+const minFreeDisk = 200 << 20
+
+// campaignPlan is one campaign's share of today's connect budget
+type campaignPlan struct {
+	Name   string
+	Budget int
+	Sent   int
+	Queue  []storage.SearchResult
+}
+
+// todayPlan is what a run would do today, worked out from the config and database
+// alone. The run logs it before contacting anyone and the plan command prints it,
+// so the preview and the run share every limit calculation.
+type todayPlan struct {
+	Now       time.Time
+	ActiveDay bool
+	Window    [2]time.Time
+
+	SessionLimit time.Duration
+	ActiveUsed   time.Duration
+	ActiveCap    time.Duration
+
+	Decision         *planner.Decision
+	SentToday        int
+	Weekly           *connections.WeeklyAllowance
+	ConnectRemaining int
+	Budgets          map[string]int
+	Campaigns        []campaignPlan
+
+	// Slots are the connection slots still ahead today; SlotsStored is set when
+	// an earlier run already stored today's plan, otherwise they are freshly drawn
+	Slots       []time.Time
+	SlotsStored bool
+
+	MessagesSent int
+	DueMessages  []storage.ScheduledMessage
+
+	InvitesAccepted int
+	Welcomes        []storage.IncomingInvite
+
+	Navigations     int
+	NavigationLimit int
+	Clamped         []config.Clamp
+
+	// Actions is the day's action budget, nil when disabled, and Allocations its shares
+	Actions     *budget.DailyBudget
+	Allocations []budget.Allocation
+
+	Blockers []string
+}
+
+// planToday works out today's plan without touching the browser or writing anything.
+// Lookups that fail are logged and left out, as the run would carry on without them.
+func planToday(cfg *config.Config, db *storage.DB, scheduler *stealth.Scheduler, campaigns []config.CampaignConfig, now time.Time) *todayPlan {
+	p := &todayPlan{
+		Now:          now,
+		ActiveDay:    scheduler.IsActiveDay(now),
+		Window:       [2]time.Time{scheduler.ActiveWindowStart(now), scheduler.ActiveWindowEnd(now)},
+		SessionLimit: scheduler.SessionLimit(),
+	}
+	p.ActiveUsed, p.ActiveCap = scheduler.ActiveTime(now)
+
+	// Shrink the connect budget while accepted connections are waiting for a message
+	decision, err := planner.NewPlanner(cfg, db).Decide(now)
+	if err != nil {
+		logger.Errorf("Planning failed, using the full connect budget: %v", err)
+		limit := cfg.DailyConnectLimit(now)
+		decision = &planner.Decision{ConnectBudget: limit, FullBudget: limit, Reason: "planning failed"}
+	}
+	p.Decision = decision
+
+	if p.SentToday, err = db.GetConnectionRequestsCountByDate(now); err != nil {
+		logger.Errorf("Failed to get today's connection count: %v", err)
+	}
+	p.ConnectRemaining = decision.ConnectBudget - p.SentToday
+
+	// LinkedIn's own count, when it showed one this week, can lower the weekly limit
+	if p.Weekly, err = connections.GetWeeklyAllowance(db, cfg.Connections.WeeklyLimit, now); err != nil {
+		logger.Errorf("%v", err)
+	} else if p.Weekly.Limit > 0 {
+		if p.Weekly.Remaining < p.ConnectRemaining {
+			p.ConnectRemaining = p.Weekly.Remaining
+		}
+		if p.Weekly.Remaining == 0 {
+			p.block("weekly connection limit reached (%s)", p.Weekly)
+		}
+	}
+	if p.ConnectRemaining < 0 {
+		p.ConnectRemaining = 0
+	}
+
+	p.Budgets = cfg.CampaignBudgets(cfg.EffectiveCampaigns(), decision.ConnectBudget)
+	for _, campaign := range campaigns {
+		cp := campaignPlan{Name: campaign.Name, Budget: p.Budgets[campaign.Name]}
+		if cp.Sent, err = db.GetCampaignRequestsCountByDate(campaign.Name, now); err != nil {
+			logger.Errorf("Failed to get campaign request count: %v", err)
+		}
+		if left := cp.Budget - cp.Sent; left > 0 {
+			if cp.Queue, err = db.GetUncontactedProfiles(campaign.Name, min(left, planQueuePreview), prospectPolicy(cfg, &campaign)); err != nil {
+				logger.Errorf("Failed to get uncontacted profiles: %v", err)
+			}
+		}
+		p.Campaigns = append(p.Campaigns, cp)
+	}
+
+	if cfg.Stealth.Scheduling.SpreadActions {
+		p.planSlots(db, scheduler)
+	}
+
+	if p.MessagesSent, err = db.GetMessagesCountByDate(now); err != nil {
+		logger.Errorf("Failed to get today's message count: %v", err)
+	}
+	if p.DueMessages, err = db.GetDueScheduledMessages(now); err != nil {
+		logger.Errorf("Failed to get due follow-ups: %v", err)
+	}
+
+	if cfg.Invites.Enabled {
+		if p.InvitesAccepted, err = db.GetAcceptedInvitesCountByDate(now); err != nil {
+			logger.Errorf("Failed to get today's accepted invitations: %v", err)
+		}
+		if p.Welcomes, err = db.GetInvitesAwaitingWelcome(cfg.Messaging.DailyLimit); err != nil {
+			logger.Errorf("Failed to get invites awaiting welcome: %v", err)
+		}
+	}
+
+	// Every kind of action takes from one daily budget; the follow-ups due hold back their share
+	p.Actions = budget.New(cfg, db)
+	p.Actions.SetDemand(config.ActionFollowUp, len(p.DueMessages)+len(p.Welcomes))
+	if p.Allocations, err = p.Actions.Allocations(); err != nil {
+		logger.Errorf("%v", err)
+	}
+
+	p.NavigationLimit = cfg.Browser.DailyNavigationLimit
+	p.Clamped = cfg.Safety.Clamped
+	if p.Navigations, err = db.GetNavigationCount(now.Format("2006-01-02")); err != nil {
+		logger.Errorf("Failed to get today's navigation count: %v", err)
+	}
+
+	if mode, err := db.GetSafeMode(); err != nil {
+		logger.Errorf("%v", err)
+	} else if mode != nil {
+		p.block("%v", safeModeError(mode))
+	}
+
+	p.checkBlockers(cfg, scheduler)
+	return p
+}
+
+// followUpsDue counts the messages waiting to go out today, which is what follow-ups hold
+// back of the day's action budget
+func followUpsDue(cfg *config.Config, db *storage.DB, now time.Time) (int, error) {
+	due, err := db.GetDueScheduledMessages(now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get due follow-ups: %w", err)
+	}
+	n := len(due)
+	if cfg.Invites.Enabled {
+		welcomes, err := db.GetInvitesAwaitingWelcome(cfg.Messaging.DailyLimit)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get invites awaiting welcome: %w", err)
+		}
+		n += len(welcomes)
+	}
+	return n, nil
+}
+
+// planSlots resumes today's stored slots, or draws them the way the run would
+func (p *todayPlan) planSlots(db *storage.DB, scheduler *stealth.Scheduler) {
+	stored, err := db.GetActionSlots(p.Now.Format("2006-01-02"))
+	if err != nil {
+		logger.Errorf("Failed to get planned slots: %v", err)
+		return
+	}
+
+	if len(stored) == 0 {
+		p.Slots = scheduler.PlanDay(p.Now, p.ConnectRemaining)
+		return
+	}
+
+	p.SlotsStored = true
+	for _, slot := range stored {
+		if slot.Status == "pending" && !slot.SlotAt.Before(p.Now) {
+			p.Slots = append(p.Slots, slot.SlotAt)
+		}
+	}
+}
+
+// checkBlockers records anything that would stop or hold up today's run
+func (p *todayPlan) checkBlockers(cfg *config.Config, scheduler *stealth.Scheduler) {
+	switch {
+	case !p.ActiveDay:
+		p.block("today is a quiet day, the run would wait until %s", scheduler.NextActiveStart(p.Now).Format("Mon 15:04"))
+	case !p.Now.Before(p.Window[1]):
+		p.block("today's window closed at %s, the run would wait until %s",
+			p.Window[1].Format("15:04"), scheduler.NextActiveStart(p.Now).Format("Mon 15:04"))
+	}
+
+	if err := scheduler.CheckDailyCap(p.Now); err != nil {
+		p.block("%v", err)
+	}
+
+	if p.NavigationLimit > 0 && p.Navigations >= p.NavigationLimit {
+		p.block("daily navigation limit reached (%d/%d)", p.Navigations, p.NavigationLimit)
+	}
+
+	// Restrictions LinkedIn showed earlier today
+	if latest, _, err := report.LoadLatest(cfg.Reporting.Dir); err == nil && sameDay(latest.StartedAt, p.Now) {
+		for _, restriction := range latest.RestrictionsHit {
+			p.block("restriction in run %s: %s", latest.ID(), restriction)
+		}
+	}
+
+	if free, err := freeDiskSpace(filepath.Dir(getDBPath())); err != nil {
+		logger.Warnf("Failed to check free disk space: %v", err)
+	} else if free < minFreeDisk {
+		p.block("low disk space: %d MB free", free>>20)
+	}
+
+	cookies := auth.NewCookieManager(cookieFile)
+	secret, err := auth.CookieSecretFromEnv()
+	if err != nil {
+		p.block("cookie encryption misconfigured: %v", err)
+	}
+	cookies.SetSecret(secret)
+
+	expires, sessionOnly, err := cookies.SessionExpiry()
+	switch {
+	case err != nil:
+		p.block("saved session unreadable, the run will log in with credentials: %v", err)
+	case sessionOnly:
+		p.block("saved session ends with the browser, the run will log in with credentials")
+	case expires.IsZero():
+		p.block("no saved session, the run will log in with credentials")
+	case expires.Before(p.Now):
+		p.block("saved session expired %s, the run will log in with credentials", expires.Local().Format("2006-01-02 15:04"))
+	}
+}
+
+// block records a blocker
+func (p *todayPlan) block(format string, args ...interface{}) {
+	p.Blockers = append(p.Blockers, fmt.Sprintf(format, args...))
+}
+
+// Lines describes the plan, one line per item
+func (p *todayPlan) Lines() []string {
+	var lines []string
+	add := func(format string, args ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	}
+
+	add("Plan for %s: window %s-%s, %s",
+		p.Now.Format("Mon 2006-01-02"), p.Window[0].Format("15:04"), p.Window[1].Format("15:04"), p.activeTime())
+
+	d := p.Decision
+	add("Connect budget %d of %d (%s), %d sent today, %d remaining", d.ConnectBudget, d.FullBudget, d.Reason, p.SentToday, p.ConnectRemaining)
+	if w := p.Weekly; w != nil && (w.Sent > 0 || w.Limit > 0) {
+		add("  Last 7 days: %s", w)
+	}
+
+	switch {
+	case p.SlotsStored:
+		add("Slots (stored): %s", formatTimes(p.Slots))
+	case p.Slots != nil:
+		add("Slots (drawn): %s", formatTimes(p.Slots))
+	}
+
+	for _, c := range p.Campaigns {
+		add("Campaign %s: budget %d, %d sent today", c.Name, c.Budget, c.Sent)
+		for _, profile := range c.Queue {
+			jobTitle, company := profile.TemplateFields()
+			add("  next: %s", describeProfile(profile.ProfileName, jobTitle, company))
+		}
+	}
+
+	add("Messages: %d sent today, %d follow-ups due", p.MessagesSent, len(p.DueMessages))
+	for i, msg := range p.DueMessages {
+		if i == planQueuePreview {
+			break
+		}
+		add("  next: %s (due %s)", describeProfile(msg.ProfileName, msg.JobTitle, msg.Company), msg.DueAt.Local().Format("01-02 15:04"))
+	}
+
+	if p.Welcomes != nil || p.InvitesAccepted > 0 {
+		add("Invites: %d accepted today, %d awaiting a welcome message", p.InvitesAccepted, len(p.Welcomes))
+		for i, invite := range p.Welcomes {
+			if i == planQueuePreview {
+				break
+			}
+			add("  next: %s", describeProfile(invite.InviterName, invite.Headline, ""))
+		}
+	}
+
+	add("Navigations: %d of %d today", p.Navigations, p.NavigationLimit)
+	for _, a := range p.Allocations {
+		add("Action budget: %s may take %d (%d used of its %d, %d held back)", a.Action, a.Left, a.Used, a.Cap, a.Reserved)
+	}
+	for _, clamp := range p.Clamped {
+		add("Safety: %s", clamp)
+	}
+
+	if len(p.Blockers) == 0 {
+		add("No blockers")
+	}
+	for _, blocker := range p.Blockers {
+		add("Blocker: %s", blocker)
+	}
+
+	return lines
+}
+
+// activeTime describes the session and daily active-time caps
+func (p *todayPlan) activeTime() string {
+	parts := []string{"no session cap"}
+	if p.SessionLimit > 0 {
+		parts[0] = fmt.Sprintf("sessions of %s", p.SessionLimit)
+	}
+	if p.ActiveCap > 0 {
+		parts = append(parts, fmt.Sprintf("%s of %s active time used", p.ActiveUsed.Round(time.Minute), p.ActiveCap))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeProfile formats a name with its title and company when known
+func describeProfile(name, title, company string) string {
+	switch {
+	case title != "" && company != "":
+		return fmt.Sprintf("%s, %s at %s", name, title, company)
+	case title != "":
+		return fmt.Sprintf("%s, %s", name, title)
+	}
+	return name
+}
+
+// formatTimes lists clock times
+func formatTimes(times []time.Time) string {
+	if len(times) == 0 {
+		return "none"
+	}
+
+	formatted := make([]string, len(times))
+	for i, t := range times {
+		formatted[i] = t.Local().Format("15:04")
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// sameDay reports whether a and b fall on the same local date
+func sameDay(a, b time.Time) bool {
+	return a.Local().Format("2006-01-02") == b.Local().Format("2006-01-02")
+}
+
+// runPlan previews today's plan using the same calculations as a run, with the browser untouched
+func runPlan(args []string) int {
+	fs := flag.NewFlagSet("plan", flag.ContinueOnError)
+	campaignFlag := fs.String("campaign", "", "preview only the named campaign")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := config.LoadConfig(getConfigPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+
+	campaigns, err := selectCampaigns(cfg, *campaignFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 2
+	}
+
+	// Lookups that fail are logged; keep the log out of the way of the plan
+	if err := logger.InitLogger("warn", cfg.Logging.Format, logger.OutputStdout, logger.Rotation{}); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
+		return 1
+	}
+	defer logger.Sync()
+
+	db, err := openDB()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	scheduler, err := newScheduler(cfg, db, notify.Nop{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize scheduler: %v\n", err)
+		return 1
+	}
+
+	for _, line := range planToday(cfg, db, scheduler, campaigns, time.Now()).Lines() {
+		fmt.Println(line)
+	}
+	return 0
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// profilesUsage is printed when the profiles command is misused
+const profilesUsage = `Usage: linkedin-bot profiles tag URL TAG... | profiles untag URL TAG... |
+       profiles note URL "TEXT" (an empty TEXT clears it) | profiles list [--tag TAG]`
+
+// runProfiles tags found profiles and notes on them by hand, or lists the curated ones
+func runProfiles(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, profilesUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "tag", "untag":
+		return runProfilesTag(args[0] == "tag", args[1:])
+	case "note":
+		return runProfilesNote(args[1:])
+	case "list":
+		return runProfilesList(args[1:])
+	default:
+		fmt.Fprintln(os.Stderr, profilesUsage)
+		return 2
+	}
+}
+
+// runProfilesTag adds tags to a profile, or removes them
+func runProfilesTag(add bool, args []string) int {
+	if len(args) < 2 {
+		fmt.Fprintln(os.Stderr, profilesUsage)
+		return 2
+	}
+	profileURL, tags := args[0], args[1:]
+
+	db, err := openDB()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	for _, tag := range tags {
+		tag = storage.NormalizeTag(tag)
+		if add {
+			added, err := db.AddProfileTag(profileURL, tag)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				return profileExitCode(err)
+			}
+			if !added {
+				fmt.Printf("Already tagged %s\n", tag)
+				continue
+			}
+			fmt.Printf("Tagged %s\n", tag)
+		} else {
+			removed, err := db.RemoveProfileTag(profileURL, tag)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				return 1
+			}
+			if !removed {
+				fmt.Printf("Not tagged %s\n", tag)
+				continue
+			}
+			fmt.Printf("Removed tag %s\n", tag)
+		}
+	}
+	return printProfileTags(db, profileURL)
+}
+
+// runProfilesNote sets or clears the note on a profile
+func runProfilesNote(args []string) int {
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, profilesUsage)
+		return 2
+	}
+	profileURL, note := args[0], args[1]
+
+	db, err := openDB()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	if err := db.SetCurationNote(profileURL, note); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return profileExitCode(err)
+	}
+	if strings.TrimSpace(note) == "" {
+		fmt.Println("Note cleared")
+	} else {
+		fmt.Println("Note saved; templates can use it as {{.CurationNote}}")
+	}
+	return 0
+}
+
+// runProfilesList prints the profiles with a tag, or every tagged or noted profile
+func runProfilesList(args []string) int {
+	fs := flag.NewFlagSet("profiles list", flag.ContinueOnError)
+	tagFlag := fs.String("tag", "", "list only profiles with TAG")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, profilesUsage)
+		return 2
+	}
+
+	db, err := openDB()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	profiles, err := db.ListCuratedProfiles(*tagFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if len(profiles) == 0 {
+		fmt.Println("No profiles.")
+		return 0
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROFILE\tNAME\tCAMPAIGN\tTAGS\tNOTE")
+	for _, p := range profiles {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", p.ProfileURL, orDash(p.ProfileName), orDash(p.Campaign),
+			orDash(strings.Join(p.Tags, ", ")), orDash(p.Note))
+	}
+	w.Flush()
+	return 0
+}
+
+// printProfileTags prints the tags a profile has now
+func printProfileTags(db *storage.DB, profileURL string) int {
+	tags, err := db.ProfileTags(profileURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	fmt.Printf("Tags of %s: %s\n", profileURL, orDash(strings.Join(tags, ", ")))
+	return 0
+}
+
+// profileExitCode is 2 for a profile no search found, a usage mistake, and 1 otherwise
+func profileExitCode(err error) int {
+	if errors.Is(err, storage.ErrUnknownProfile) {
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/connections"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/session"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// pruneUsage is printed when the prune command is misused
+const pruneUsage = "Usage: linkedin-bot prune (--csv FILE | --accepted-before DAYS) [--unfollow] [--limit N] [--confirm]"
+
+// runPrune removes connections, or unfollows profiles, listed in a CSV or picked from the
+// database. Without --confirm it only lists the profiles it would act on.
+func runPrune(args []string) int {
+	fs := flag.NewFlagSet("prune", flag.ContinueOnError)
+	csvFlag := fs.String("csv", "", "CSV file whose first column holds the profile URLs")
+	daysFlag := fs.Int("accepted-before", 0, "pick connections accepted more than DAYS ago that never replied")
+	unfollowFlag := fs.Bool("unfollow", false, "unfollow the profiles instead of removing the connections")
+	limitFlag := fs.Int("limit", 0, "act on at most N profiles, below prune.max_per_run")
+	confirmFlag := fs.Bool("confirm", false, "act on the profiles; without it they are only listed")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if (*csvFlag == "") == (*daysFlag <= 0) || *limitFlag < 0 || fs.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, pruneUsage)
+		return 2
+	}
+
+	cfg, err := config.LoadConfig(getConfigPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+
+	// The config caps every run; --limit can only lower it
+	limit := cfg.Prune.MaxPerRun
+	if *limitFlag > 0 && *limitFlag < limit {
+		limit = *limitFlag
+	}
+
+	db, err := openDB()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	var targets []string
+	if *csvFlag != "" {
+		targets, err = readProfileCSV(*csvFlag)
+	} else {
+		targets, err = staleConnections(db, time.Now().AddDate(0, 0, -*daysFlag), limit)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if len(targets) == 0 {
+		fmt.Println("No profiles to prune")
+		return 0
+	}
+	if len(targets) > limit {
+		fmt.Printf("%d profiles listed, acting on the first %d (prune.max_per_run)\n", len(targets), limit)
+		targets = targets[:limit]
+	}
+
+	verb := "Remove connection"
+	if *unfollowFlag {
+		verb = "Unfollow"
+	}
+	if !*confirmFlag {
+		for _, url := range targets {
+			fmt.Printf("%s: %s\n", verb, url)
+		}
+		fmt.Printf("Nothing changed; run again with --confirm to act on these %d profiles\n", len(targets))
+		return 0
+	}
+
+	return prune(cfg, db, targets, *unfollowFlag)
+}
+
+// readProfileCSV returns the profile URLs in the first column of a CSV file, skipping a
+// header and any other row that holds no profile URL
+func readProfileCSV(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CSV: %w", err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	var urls []string
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		if url := strings.TrimSpace(record[0]); strings.Contains(url, "/in/") {
+			urls = append(urls, url)
+		}
+	}
+	return urls, nil
+}
+
+// staleConnections returns the profiles of up to limit connections accepted before t
+// that never replied
+func staleConnections(db *storage.DB, before time.Time, limit int) ([]string, error) {
+	requests, err := db.GetStaleConnections(before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stale connections: %w", err)
+	}
+	urls := make([]string, len(requests))
+	for i, r := range requests {
+		urls[i] = r.ProfileURL
+	}
+	return urls, nil
+}
+
+// prune logs in and removes, or unfollows, each target in turn. Each action is audited
+// in the activity log.
+func prune(cfg *config.Config, db *storage.DB, targets []string, unfollow bool) int {
+	if !initCommandLogger(cfg) {
+		return 1
+	}
+	defer logger.Sync()
+
+	sess, code := openAccountSession(cfg, db, "pruning")
+	if sess == nil {
+		return code
+	}
+	defer sess.Close()
+
+	tab := sess.Tab(session.TabProfile)
+	manager := connections.NewConnectionManager(tab.Pages, &cfg.Connections, db, sess.Timing, tab.Typer, tab.Clicker, tab.Scroller)
+	action := manager.RemoveConnection
+	if unfollow {
+		action = manager.Unfollow
+	}
+
+	var done, skipped, failed int
+	for i, url := range targets {
+		if i > 0 {
+			sess.Timing.Wait(sess.Timing.ActionDelay())
+		}
+		err := action(url)
+		switch {
+		case errors.Is(err, connections.ErrNotConnected), errors.Is(err, connections.ErrNotFollowing):
+			skipped++
+		case err != nil:
+			logger.Errorf("Failed to prune %s: %v", url, err)
+			failed++
+		default:
+			done++
+		}
+	}
+
+	logger.Infof("Pruned %d profiles, %d skipped, %d failed", done, skipped, failed)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
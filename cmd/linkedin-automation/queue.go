@@ -0,0 +1,144 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// queueUsage is printed when the queue command is misused
+const queueUsage = "Usage: linkedin-bot queue list [--state STATE] [--limit N] | queue requeue ID [--priority N]"
+
+// runQueue inspects the outreach queue, or puts an item back in line
+func runQueue(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, queueUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "list":
+		return runQueueList(args[1:])
+	case "requeue":
+		return runQueueRequeue(args[1:])
+	default:
+		fmt.Fprintln(os.Stderr, queueUsage)
+		return 2
+	}
+}
+
+// runQueueList prints the queue's items, with a count per state
+func runQueueList(args []string) int {
+	fs := flag.NewFlagSet("queue list", flag.ContinueOnError)
+	stateFlag := fs.String("state", "", "list only items in STATE ("+strings.Join(storage.QueueStates, ", ")+")")
+	limitFlag := fs.Int("limit", 50, "list at most N items")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *stateFlag != "" && !isQueueState(*stateFlag) {
+		fmt.Fprintf(os.Stderr, "Unknown state %q; use one of %s\n", *stateFlag, strings.Join(storage.QueueStates, ", "))
+		return 2
+	}
+	if *limitFlag <= 0 || fs.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, queueUsage)
+		return 2
+	}
+
+	db, err := openDB()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	counts, err := db.CountQueue()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to count queue items: %v\n", err)
+		return 1
+	}
+	items, err := db.ListQueue(*stateFlag, *limitFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list queue items: %v\n", err)
+		return 1
+	}
+
+	parts := make([]string, len(storage.QueueStates))
+	for i, state := range storage.QueueStates {
+		parts[i] = fmt.Sprintf("%d %s", counts[state], state)
+	}
+	fmt.Printf("Queue: %s\n\n", strings.Join(parts, ", "))
+	if len(items) == 0 {
+		fmt.Println("No items.")
+		return 0
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATE\tPRIORITY\tATTEMPTS\tNEXT TRY\tCAMPAIGN\tPROFILE\tLAST ERROR")
+	for _, item := range items {
+		next := "-"
+		if item.State == storage.QueueScheduled && !item.ScheduledFor.IsZero() {
+			next = item.ScheduledFor.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%s\t%s\t%s\t%s\n", item.ID, item.State, item.Priority, item.Attempts, next,
+			orDash(item.Campaign), item.ProfileURL, orDash(item.LastError))
+	}
+	w.Flush()
+	return 0
+}
+
+// runQueueRequeue puts a queue item back in line, typically one dead-lettered as failed
+func runQueueRequeue(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, queueUsage)
+		return 2
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid queue item ID %q\n", args[0])
+		return 2
+	}
+
+	fs := flag.NewFlagSet("queue requeue", flag.ContinueOnError)
+	priorityFlag := fs.Int("priority", 0, "items with a higher priority are contacted first")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, queueUsage)
+		return 2
+	}
+
+	db, err := openDB()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	found, err := db.Requeue(id, *priorityFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if !found {
+		fmt.Fprintf(os.Stderr, "No queue item %d\n", id)
+		return 1
+	}
+	fmt.Printf("Requeued item %d with priority %d\n", id, *priorityFlag)
+	return 0
+}
+
+// isQueueState reports whether state is one of the queue's states
+func isQueueState(state string) bool {
+	for _, s := range storage.QueueStates {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/connections"
+)
+
+// defaultReviewTimeout is how long a review waits for an answer before the profile is left for later
+const defaultReviewTimeout = 5 * time.Minute
+
+// inviteReviewer asks the operator at the terminal to approve the first invites of a run,
+// so targeting and note rendering can be checked before the bot goes on unattended
+type inviteReviewer struct {
+	left    int // approvals still needed
+	total   int
+	timeout time.Duration
+	lines   <-chan string // the operator's input, closed at EOF
+	out     io.Writer
+}
+
+// newInviteReviewer creates a reviewer for the first n approved invites, reading answers
+// from stdin
+func newInviteReviewer(n int, timeout time.Duration) *inviteReviewer {
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+	return &inviteReviewer{left: n, total: n, timeout: timeout, lines: lines, out: os.Stdout}
+}
+
+// Review shows the invite and waits for y (send), n (skip) or e (edit the note). The
+// timeout bounds the whole review, edits included. Once enough invites are approved
+// every invite is approved unseen.
+func (r *inviteReviewer) Review(invite connections.InviteReview) (string, connections.ReviewVerdict) {
+	if r.left <= 0 {
+		return invite.Note, connections.ReviewApproved
+	}
+
+	deadline := time.Now().Add(r.timeout)
+	note := invite.Note
+	fmt.Fprintf(r.out, "\nReview %d/%d: %s\n", r.total-r.left+1, r.total, invite.ProfileName)
+	fmt.Fprintf(r.out, "  Profile:  %s\n", invite.ProfileURL)
+	fmt.Fprintf(r.out, "  Headline: %s\n", orDash(joinNonEmpty(" at ", invite.Headline, invite.Company)))
+	for {
+		fmt.Fprintf(r.out, "  Note:     %s\n", orDash(note))
+		options := "[y]es, send / [n]o, skip"
+		if invite.NoteEditable {
+			options += " / [e]dit note"
+		}
+		fmt.Fprintf(r.out, "%s (%s to answer): ", options, time.Until(deadline).Round(time.Second))
+
+		answer, ok := r.readLine(deadline)
+		if !ok {
+			fmt.Fprintln(r.out, "\nNo answer, leaving the profile for later")
+			return "", connections.ReviewUnanswered
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			r.left--
+			if r.left == 0 {
+				fmt.Fprintln(r.out, "Review done, continuing unattended")
+			}
+			return note, connections.ReviewApproved
+		case "n", "no":
+			return "", connections.ReviewRejected
+		case "e", "edit":
+			if !invite.NoteEditable {
+				fmt.Fprintln(r.out, "This invite goes out without a note")
+				continue
+			}
+			fmt.Fprint(r.out, "New note on one line, empty for none: ")
+			edited, ok := r.readLine(deadline)
+			if !ok {
+				fmt.Fprintln(r.out, "\nNo answer, leaving the profile for later")
+				return "", connections.ReviewUnanswered
+			}
+			edited = strings.TrimSpace(edited)
+			if invite.Check != nil {
+				if err := invite.Check(edited); err != nil {
+					fmt.Fprintf(r.out, "Keeping the note: %v\n", err)
+					continue
+				}
+			}
+			note = edited
+		}
+	}
+}
+
+// readLine waits until deadline for a line of input; ok is false past the deadline or at EOF
+func (r *inviteReviewer) readLine(deadline time.Time) (line string, ok bool) {
+	select {
+	case line, ok = <-r.lines:
+		return line, ok
+	case <-time.After(time.Until(deadline)):
+		return "", false
+	}
+}
+
+// checkReviewTimeout rejects a review timeout longer than a profile's time budget. The
+// budget is paused while the operator is asked, but a review that can outlast it would
+// hold the profile's page open past what connections.per_profile_timeout_seconds allows.
+func checkReviewTimeout(opts *runOptions, cfg *config.Config) error {
+	budget := cfg.Connections.ProfileTimeout()
+	if opts.review == 0 || budget == 0 || opts.reviewTimeout <= budget {
+		return nil
+	}
+	return fmt.Errorf("--review-timeout %s is longer than a profile's time budget of %s; lower it or raise connections.per_profile_timeout_seconds",
+		opts.reviewTimeout, budget)
+}
+
+// joinNonEmpty joins the parts that aren't empty with sep
+func joinNonEmpty(sep string, parts ...string) string {
+	var kept []string
+	for _, p := range parts {
+		if p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, sep)
+}
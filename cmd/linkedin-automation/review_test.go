@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/connections"
+)
+
+func TestInviteReviewerAnswers(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		answers []string
+		note    string
+		verdict connections.ReviewVerdict
+	}{
+		{"approved", []string{"y"}, "Hi Ada", connections.ReviewApproved},
+		{"rejected", []string{"no"}, "", connections.ReviewRejected},
+		{"edited", []string{"e", "Hello Ada", "y"}, "Hello Ada", connections.ReviewApproved},
+		{"edit failing the check", []string{"e", "{{oops}}", "y"}, "Hi Ada", connections.ReviewApproved},
+		{"end of input", nil, "", connections.ReviewUnanswered},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := make(chan string, len(tt.answers))
+			for _, a := range tt.answers {
+				lines <- a
+			}
+			close(lines)
+
+			r := &inviteReviewer{left: 1, total: 1, timeout: time.Minute, lines: lines, out: io.Discard}
+			note, verdict := r.Review(connections.InviteReview{ProfileName: "Ada Lovelace", Note: "Hi Ada", NoteEditable: true,
+				Check: func(note string) error {
+					if note == "{{oops}}" {
+						return errTest
+					}
+					return nil
+				}})
+			if note != tt.note || verdict != tt.verdict {
+				t.Fatalf("Review = %q, %v; want %q, %v", note, verdict, tt.note, tt.verdict)
+			}
+		})
+	}
+}
+
+// errTest is the error of a note the check rejects
+var errTest = errors.New("note rejected")
+
+func TestInviteReviewerTimeoutBoundsWholeReview(t *testing.T) {
+	// An operator who keeps answering without deciding still runs out of time
+	lines := make(chan string)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			select {
+			case lines <- "?":
+			case <-stop:
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	r := &inviteReviewer{left: 1, total: 1, timeout: 100 * time.Millisecond, lines: lines, out: io.Discard}
+	done := make(chan connections.ReviewVerdict)
+	go func() {
+		_, verdict := r.Review(connections.InviteReview{ProfileName: "Ada Lovelace"})
+		done <- verdict
+	}()
+
+	select {
+	case verdict := <-done:
+		if verdict != connections.ReviewUnanswered {
+			t.Fatalf("verdict = %v, want unanswered", verdict)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the review outlasted its timeout")
+	}
+}
+
+func TestCheckReviewTimeout(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		review        int
+		reviewTimeout time.Duration
+		budgetSeconds int
+		ok            bool
+	}{
+		{"defaults", 10, defaultReviewTimeout, 300, true},
+		{"longer than the budget", 10, 10 * time.Minute, 300, false},
+		{"no review", 0, 10 * time.Minute, 300, true},
+		{"no budget", 10, time.Hour, -1, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Connections: config.ConnectionsConfig{PerProfileTimeoutSeconds: tt.budgetSeconds}}
+			err := checkReviewTimeout(&runOptions{review: tt.review, reviewTimeout: tt.reviewTimeout}, cfg)
+			if (err == nil) != tt.ok {
+				t.Fatalf("checkReviewTimeout = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/report"
+	"github.com/Tanukumar01/linkedin-automation/internal/snapshot"
+)
+
+// runRun dispatches the run subcommands
+func runRun(args []string) int {
+	if len(args) == 0 || args[0] != "show" {
+		fmt.Fprintln(os.Stderr, "Usage: linkedin-bot run show [RUN_ID] | run --daemon [--campaign NAME]")
+		return 2
+	}
+
+	id := ""
+	if len(args) > 1 {
+		id = args[1]
+	}
+	return runShow(id)
+}
+
+// runShow prints a run's config snapshot and how it differs from the previous run's.
+// Without an ID the latest run is shown.
+func runShow(id string) int {
+	cfg, err := config.LoadConfig(getConfigPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+
+	ids, err := report.List(cfg.Reporting.Dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list run reports: %v\n", err)
+		return 1
+	}
+	if len(ids) == 0 {
+		fmt.Fprintf(os.Stderr, "No run reports found in %s\n", cfg.Reporting.Dir)
+		return 1
+	}
+
+	index := len(ids) - 1
+	if id != "" {
+		index = -1
+		for i, candidate := range ids {
+			if candidate == id {
+				index = i
+			}
+		}
+		if index < 0 {
+			fmt.Fprintf(os.Stderr, "No run %s in %s\n", id, cfg.Reporting.Dir)
+			return 1
+		}
+	}
+
+	r, path, err := report.Load(cfg.Reporting.Dir, ids[index])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Run %s (%s)\n", ids[index], path)
+	fmt.Printf("Started %s, %d connections sent, %d failed, %d restrictions\n\n",
+		r.StartedAt.Format("2006-01-02 15:04"), r.Connections.Sent, r.Connections.Failed, len(r.RestrictionsHit))
+	if p := r.Pacing; p != nil {
+		fmt.Printf("Pace:      %s (delays x%.2f, typing x%.2f, typos x%.2f, scrolling x%.2f)\n\n", p.Name, p.Delays, p.Typing, p.Typos, p.Scroll)
+	}
+	if len(r.ActionBudget) > 0 {
+		fmt.Println("Action budget at the end of the run:")
+		printActionBudget(r.ActionBudget)
+		fmt.Println()
+	}
+
+	if r.Snapshot == nil {
+		fmt.Println("This run has no config snapshot")
+		return 0
+	}
+
+	fmt.Printf("Snapshot:  %s\n", r.Snapshot.Hash)
+	fmt.Printf("Persona:   %s\n", r.Snapshot.Persona.UserAgent)
+	fmt.Printf("Viewport:  %dx%d\n\n", r.Snapshot.Persona.ViewportWidth, r.Snapshot.Persona.ViewportHeight)
+
+	// Find the closest earlier run that recorded a snapshot
+	var prev *report.RunReport
+	var prevID string
+	for i := index - 1; i >= 0 && prev == nil; i-- {
+		if candidate, _, err := report.Load(cfg.Reporting.Dir, ids[i]); err == nil && candidate.Snapshot != nil {
+			prev, prevID = candidate, ids[i]
+		}
+	}
+
+	if prev == nil {
+		fmt.Println("No earlier run with a snapshot to compare against")
+		return 0
+	}
+
+	if prev.Snapshot.Hash == r.Snapshot.Hash {
+		fmt.Printf("Same config and persona as run %s\n", prevID)
+		return 0
+	}
+
+	changes, err := snapshot.Diff(prev.Snapshot, r.Snapshot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to compare snapshots: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Changes since run %s (snapshot %s):\n\n", prevID, prev.Snapshot.Hash)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SETTING\tBEFORE\tAFTER")
+	for _, c := range changes {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", c.Path, orDash(c.Old), orDash(c.New))
+	}
+	w.Flush()
+
+	return 0
+}
+
+// orDash prints a missing value as "-"
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return truncate(s, 60)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/artifacts"
+	"github.com/Tanukumar01/linkedin-automation/internal/breaker"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/notify"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/report"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// errSafeMode is returned by a session that put the bot in safe mode, or found it there
+var errSafeMode = errors.New("in safe mode")
+
+// safeModeError explains the safe mode flag and how to clear it
+func safeModeError(mode *storage.SafeMode) error {
+	return fmt.Errorf("%w since %s: %s; run `linkedin-bot resume` once it is fixed", errSafeMode,
+		mode.EnteredAt.Local().Format("2006-01-02 15:04"), mode.Reason)
+}
+
+// enterSafeMode stops the bot after its breaker tripped: the page of the last failure
+// is saved, whatever debug.capture_on_error says, the flag is set so no run starts
+// until it is cleared, and the webhook is told
+func (b *bot) enterSafeMode(safety *breaker.Breaker, page pageops.Page, runReport *report.RunReport) error {
+	reason := safety.Reason()
+	logger.Errorf("Entering safe mode: %s", reason)
+
+	debug := b.cfg.Debug
+	debug.CaptureOnError = true
+	artifacts.NewCollector(debug).Capture(page, "safe_mode", safety.LastErr())
+
+	runReport.RecordSafeMode(reason)
+	if err := b.db.EnterSafeMode(reason); err != nil {
+		logger.Errorf("%v", err)
+	}
+
+	event := notify.NewEvent(notify.EventSafeMode, fmt.Sprintf("Entered safe mode: %s", reason))
+	if err := b.notifier.Notify(event); err != nil {
+		logger.Warnf("Failed to send safe mode notification: %v", err)
+	}
+
+	mode, err := b.db.GetSafeMode()
+	if err != nil || mode == nil {
+		return fmt.Errorf("%w: %s", errSafeMode, reason)
+	}
+	return safeModeError(mode)
+}
+
+// runResume clears the safe mode flag so runs start again
+func runResume(args []string) int {
+	if len(args) > 0 {
+		fmt.Fprintln(os.Stderr, "Usage: linkedin-bot resume")
+		return 2
+	}
+
+	db, err := openDB()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	mode, err := db.ClearSafeMode()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if mode == nil {
+		fmt.Println("Not in safe mode; nothing to resume")
+		return 0
+	}
+
+	fmt.Printf("Cleared safe mode entered %s", mode.EnteredAt.Local().Format("2006-01-02 15:04"))
+	if mode.RunID != "" {
+		fmt.Printf(" by run %s", mode.RunID)
+	}
+	fmt.Printf(": %s\n", mode.Reason)
+	fmt.Println("The next run starts as usual")
+	return 0
+}
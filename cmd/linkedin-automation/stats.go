@@ -0,0 +1,263 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/budget"
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/connections"
+	"github.com/Tanukumar01/linkedin-automation/internal/report"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+	"github.com/Tanukumar01/linkedin-automation/internal/templates"
+)
+
+// runStats prints outreach analytics
+func runStats(args []string) int {
+	if len(args) > 0 && args[0] == "stealth" {
+		return runStealthStats()
+	}
+
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	sinceFlag := fs.String("since", "30d", "time window to report on, e.g. 7d, 30d or 12h")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	window, err := parseSince(*sinceFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --since: %v\n", err)
+		return 2
+	}
+	since := time.Now().Add(-window)
+
+	db, err := openDB()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	rate, err := db.GetAcceptanceRate(since)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get acceptance rate: %v\n", err)
+		return 1
+	}
+
+	funnel, err := db.GetFunnelStats(since)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get funnel stats: %v\n", err)
+		return 1
+	}
+
+	byTemplate, err := db.GetStatsByTemplate(since)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get template stats: %v\n", err)
+		return 1
+	}
+
+	byMessageTemplate, err := db.GetMessageStatsByTemplate(since)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get message template stats: %v\n", err)
+		return 1
+	}
+
+	byCampaign, err := db.GetStatsByCampaign(since)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get campaign stats: %v\n", err)
+		return 1
+	}
+
+	weekly, err := connections.GetWeeklyAllowance(db, weeklyLimit(), time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get weekly allowance: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Stats since %s\n\n", since.Format("2006-01-02 15:04"))
+	fmt.Printf("Acceptance rate: %.1f%%\n", rate)
+	fmt.Printf("Weekly invitations: %s\n\n", weekly)
+
+	// The budget is today's, whatever the window
+	if cfg, err := config.LoadConfig(getConfigPath()); err == nil && cfg.ActionBudget.Enabled {
+		actions := budget.New(cfg, db)
+		due, err := followUpsDue(cfg, db, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		actions.SetDemand(config.ActionFollowUp, due)
+
+		allocations, err := actions.Allocations()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to get action budget: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Action budget today (%d actions):\n", cfg.ActionBudget.DailyTotal)
+		printActionBudget(allocations)
+		fmt.Println()
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STAGE\tCOUNT\tOF PREVIOUS")
+	fmt.Fprintf(w, "Found\t%d\t-\n", funnel.Found)
+	fmt.Fprintf(w, "Contacted\t%d\t%.1f%%\n", funnel.Contacted, storage.Rate(funnel.Contacted, funnel.Found))
+	fmt.Fprintf(w, "Accepted\t%d\t%.1f%%\n", funnel.Accepted, storage.Rate(funnel.Accepted, funnel.Contacted))
+	fmt.Fprintf(w, "Replied\t%d\t%.1f%%\n", funnel.Replied, storage.Rate(funnel.Replied, funnel.Accepted))
+	w.Flush()
+	fmt.Println()
+
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CAMPAIGN\tFOUND\tCONTACTED\tACCEPTED\tREPLIED\tACCEPT %")
+	for _, cs := range byCampaign {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%.1f%%\n", cs.Campaign, cs.Found, cs.Contacted, cs.Accepted, cs.Replied, storage.Rate(cs.Accepted, cs.Contacted))
+	}
+	w.Flush()
+	fmt.Println()
+
+	texts := templateTexts()
+
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NOTE TEMPLATE\tSENT\tACCEPTED\tREPLIED\tACCEPT %\tTEXT")
+	for _, ts := range byTemplate {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.1f%%\t%s\n", templateLabel(ts.TemplateID), ts.Sent, ts.Accepted, ts.Replied, storage.Rate(ts.Accepted, ts.Sent), truncate(texts[ts.TemplateID], 50))
+	}
+	w.Flush()
+	fmt.Println()
+
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MESSAGE TEMPLATE\tSENT\tREPLIED\tREPLY %\tTEXT")
+	for _, ts := range byMessageTemplate {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1f%%\t%s\n", templateLabel(ts.TemplateID), ts.Sent, ts.Replied, storage.Rate(ts.Replied, ts.Sent), truncate(texts[ts.TemplateID], 50))
+	}
+	w.Flush()
+
+	return 0
+}
+
+// printActionBudget prints each action type's share of the day's action budget
+func printActionBudget(allocations []budget.Allocation) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACTION\tPRIORITY\tCAP\tUSED\tLEFT")
+	for _, a := range allocations {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\n", a.Action, a.Priority, a.Cap, a.Used, a.Left)
+	}
+	w.Flush()
+}
+
+// weeklyLimit returns connections.weekly_limit of the current config, 0 when it can't be loaded
+func weeklyLimit() int {
+	cfg, err := config.LoadConfig(getConfigPath())
+	if err != nil {
+		return 0
+	}
+	return cfg.Connections.WeeklyLimit
+}
+
+// templateLabel returns a printable template ID
+func templateLabel(id string) string {
+	if id == "" {
+		return "(none)"
+	}
+	return id
+}
+
+// templateTexts maps template IDs back to the note templates in the current config
+func templateTexts() map[string]string {
+	texts := make(map[string]string)
+
+	cfg, err := config.LoadConfig(getConfigPath())
+	if err != nil {
+		return texts
+	}
+
+	for _, t := range cfg.Connections.NoteTemplates {
+		texts[templates.ID(t.Text)] = t.Text
+	}
+	for _, campaign := range cfg.Campaigns {
+		for _, t := range campaign.NoteTemplates {
+			texts[templates.ID(t.Text)] = t.Text
+		}
+	}
+	for _, t := range cfg.Messaging.Templates {
+		texts[templates.ID(t.Text)] = t.Text
+	}
+	for _, t := range cfg.Messaging.WelcomeTemplates {
+		texts[templates.ID(t.Text)] = t.Text
+	}
+
+	return texts
+}
+
+// parseSince parses a window like "30d" or any time.ParseDuration value
+func parseSince(value string) (time.Duration, error) {
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	return time.ParseDuration(value)
+}
+
+// truncate shortens s to at most n runes
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// runStealthStats prints the realized stealth metrics from the latest run report
+func runStealthStats() int {
+	cfg, err := config.LoadConfig(getConfigPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+
+	r, path, err := report.LoadLatest(cfg.Reporting.Dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	if r.Stealth == nil {
+		fmt.Printf("%s has no stealth metrics\n", path)
+		return 0
+	}
+
+	m := r.Stealth
+	fmt.Printf("Stealth metrics from %s\n\n", path)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METRIC\tVALUE")
+	fmt.Fprintf(w, "Typed fields\t%d\n", m.Typing.Fields)
+	fmt.Fprintf(w, "Typing WPM (mean / max)\t%.1f / %.1f\n", m.Typing.MeanWPM, m.Typing.MaxWPM)
+	fmt.Fprintf(w, "Typing pauses per char\t%.3f\n", m.Typing.PauseRate)
+	fmt.Fprintf(w, "Typos per char\t%.3f\n", m.Typing.TypoRate)
+	fmt.Fprintf(w, "Mouse moves\t%d\n", m.Mouse.Moves)
+	fmt.Fprintf(w, "Mouse path curvature\t%.3f\n", m.Mouse.MeanCurvature)
+	fmt.Fprintf(w, "Mouse move duration\t%.0f ms\n", m.Mouse.MeanDurationMs)
+	fmt.Fprintf(w, "Scrolls\t%d\n", m.Scrolling.Scrolls)
+	fmt.Fprintf(w, "Scroll velocity (p50 / p90 / max)\t%.0f / %.0f / %.0f px/s\n", m.Scrolling.P50Velocity, m.Scrolling.P90Velocity, m.Scrolling.MaxVelocity)
+	fmt.Fprintf(w, "Scroll pauses per chunk\t%.3f\n", m.Scrolling.PauseRate)
+	w.Flush()
+
+	if len(m.Flags) > 0 {
+		fmt.Println("\nImplausible behavior:")
+		for _, flag := range m.Flags {
+			fmt.Printf("  ! %s\n", flag)
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/report"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// Timeline entry kinds
+const (
+	entryRun      = "run"
+	entryPhase    = "phase"
+	entryActivity = "activity"
+	entryWait     = "wait"
+	entryGap      = "gap"
+)
+
+// waitCauses names the logged waits that explain a quiet stretch of the day
+var waitCauses = map[string]string{
+	stealth.ActivityBreak:             "break",
+	stealth.ActivityBusinessHoursWait: "business-hours wait",
+	stealth.ActivitySlotWait:          "waiting for the next slot",
+	stealth.ActivitySessionWait:       "waiting for the next session",
+	stealth.ActivityCooldown:          "cooldown",
+}
+
+// timelineEntry is one line of a day's timeline: a point event or a span
+type timelineEntry struct {
+	At       time.Time `json:"at"`
+	Seconds  float64   `json:"duration_seconds,omitempty"` // zero for point events
+	Kind     string    `json:"kind"`
+	Name     string    `json:"name"`
+	Details  string    `json:"details,omitempty"`
+	Cause    string    `json:"cause,omitempty"` // what a gap was spent on, when known
+	Depth    int       `json:"depth"`           // runs and phases the entry falls within
+	Overrun  bool      `json:"overrun,omitempty"`
+	duration time.Duration
+}
+
+// end returns when the entry finished
+func (e timelineEntry) end() time.Time {
+	return e.At.Add(e.duration)
+}
+
+// runTimeline prints one day's runs, phases, activity and waits in order
+func runTimeline(args []string) int {
+	fs := flag.NewFlagSet("timeline", flag.ContinueOnError)
+	dateFlag := fs.String("date", time.Now().Format("2006-01-02"), "day to show, as YYYY-MM-DD")
+	formatFlag := fs.String("format", "text", "output format: text or json")
+	gapFlag := fs.Duration("gap", 5*time.Minute, "mark quiet stretches longer than this")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", *dateFlag, time.Local)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --date: %v\n", err)
+		return 2
+	}
+	if *formatFlag != "text" && *formatFlag != "json" {
+		fmt.Fprintf(os.Stderr, "Unknown --format %q (use text or json)\n", *formatFlag)
+		return 2
+	}
+
+	cfg, err := config.LoadConfig(getConfigPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+
+	db, err := openDB()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	end := day.AddDate(0, 0, 1)
+	activities, err := db.GetActivitiesBetween(day, end)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load activity: %v\n", err)
+		return 1
+	}
+
+	runs, err := runsBetween(cfg.Reporting.Dir, day, end)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load run reports: %v\n", err)
+		return 1
+	}
+
+	entries := buildTimeline(activities, runs, *gapFlag)
+
+	if *formatFlag == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entries); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write timeline: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Printf("Timeline for %s (gaps over %s marked)\n\n", day.Format("Mon 2006-01-02"), *gapFlag)
+	if len(entries) == 0 {
+		fmt.Println("Nothing recorded.")
+		return 0
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, e := range entries {
+		fmt.Fprintln(w, e.line())
+	}
+	w.Flush()
+	return 0
+}
+
+// runsBetween loads the run reports of runs that started in [start, end)
+func runsBetween(dir string, start, end time.Time) ([]*report.RunReport, error) {
+	ids, err := report.List(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []*report.RunReport
+	for _, id := range ids {
+		r, _, err := report.Load(dir, id)
+		if err != nil {
+			return nil, err
+		}
+		if !r.StartedAt.Before(start) && r.StartedAt.Before(end) {
+			runs = append(runs, r)
+		}
+	}
+	return runs, nil
+}
+
+// buildTimeline merges runs, their phases and the activity log into one chronological
+// list, nests entries under the runs and phases they fall within, and marks stretches
+// longer than gap between logged events, with the wait that filled them when known
+func buildTimeline(activities []storage.ActivityLog, runs []*report.RunReport, gap time.Duration) []timelineEntry {
+	var entries []timelineEntry
+
+	for _, r := range runs {
+		entries = append(entries, span(entryRun, r.ID(), r.StartedAt, r.FinishedAt, ""))
+		for _, p := range r.Phases {
+			if p.StartedAt.IsZero() {
+				continue
+			}
+			details := fmt.Sprintf("used %s", secondsDuration(p.ActualSeconds))
+			if p.BudgetSeconds > 0 {
+				details += fmt.Sprintf(" of %s budget", secondsDuration(p.BudgetSeconds))
+			}
+			e := span(entryPhase, p.Phase, p.StartedAt, p.FinishedAt, details)
+			e.Overrun = p.Overrun
+			entries = append(entries, e)
+		}
+	}
+
+	for _, a := range activities {
+		if _, ok := waitCauses[a.Action]; ok {
+			d, err := time.ParseDuration(a.Details)
+			if err == nil {
+				entries = append(entries, span(entryWait, a.Action, a.Timestamp, a.Timestamp.Add(d), ""))
+				continue
+			}
+		}
+		details := a.Details
+		if details == "" {
+			details = a.ProfileURL
+		}
+		if a.Error != "" {
+			details = strings.TrimSpace(details + " (" + a.Error + ")")
+		}
+		entries = append(entries, timelineEntry{At: a.Timestamp, Kind: entryActivity, Name: a.Action, Details: details})
+	}
+
+	entries = append(entries, findGaps(entries, runs, gap)...)
+
+	// Enclosing spans first when they start together, gaps after the event they follow
+	rank := map[string]int{entryRun: 0, entryPhase: 1, entryActivity: 2, entryWait: 3, entryGap: 4}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].At.Equal(entries[j].At) {
+			return entries[i].At.Before(entries[j].At)
+		}
+		return rank[entries[i].Kind] < rank[entries[j].Kind]
+	})
+
+	for i := range entries {
+		for j, outer := range entries {
+			if i != j && encloses(outer.Kind, entries[i].Kind) && !entries[i].At.Before(outer.At) && entries[i].At.Before(outer.end()) {
+				entries[i].Depth++
+			}
+		}
+	}
+
+	return entries
+}
+
+// encloses reports whether an entry of kind outer can contain one of kind inner:
+// runs hold everything else, phases hold activity, waits and gaps
+func encloses(outer, inner string) bool {
+	switch outer {
+	case entryRun:
+		return inner != entryRun
+	case entryPhase:
+		return inner != entryRun && inner != entryPhase
+	}
+	return false
+}
+
+// findGaps returns a gap entry for every stretch longer than gap between consecutive
+// logged events, blamed on the wait that covers most of it, or on no run being in progress
+func findGaps(entries []timelineEntry, runs []*report.RunReport, gap time.Duration) []timelineEntry {
+	var events, waits []timelineEntry
+	for _, e := range entries {
+		switch e.Kind {
+		case entryActivity:
+			events = append(events, e)
+		case entryWait:
+			waits = append(waits, e)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At.Before(events[j].At) })
+
+	var gaps []timelineEntry
+	for i := 1; i < len(events); i++ {
+		from, to := events[i-1].At, events[i].At
+		if to.Sub(from) <= gap {
+			continue
+		}
+
+		cause, covered := "", time.Duration(0)
+		for _, w := range waits {
+			if d := overlap(from, to, w.At, w.end()); d > covered {
+				cause, covered = waitCauses[w.Name], d
+			}
+		}
+		if idle := idleBetweenRuns(from, to, runs); idle > covered {
+			cause = "no run in progress"
+		}
+
+		gaps = append(gaps, timelineEntry{At: from, Seconds: to.Sub(from).Seconds(), Kind: entryGap, Name: entryGap, Cause: cause, duration: to.Sub(from)})
+	}
+	return gaps
+}
+
+// overlap returns how long [aStart, aEnd) and [bStart, bEnd) overlap
+func overlap(aStart, aEnd, bStart, bEnd time.Time) time.Duration {
+	start, end := aStart, aEnd
+	if bStart.After(start) {
+		start = bStart
+	}
+	if bEnd.Before(end) {
+		end = bEnd
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// idleBetweenRuns returns how much of [from, to) no run was in progress; zero when
+// there are no run reports to tell
+func idleBetweenRuns(from, to time.Time, runs []*report.RunReport) time.Duration {
+	if len(runs) == 0 {
+		return 0
+	}
+
+	idle := to.Sub(from)
+	for _, r := range runs {
+		idle -= overlap(from, to, r.StartedAt, r.FinishedAt)
+	}
+	return idle
+}
+
+// span builds a timeline entry running from start to end
+func span(kind, name string, start, end time.Time, details string) timelineEntry {
+	d := end.Sub(start)
+	if d < 0 {
+		d = 0
+	}
+	return timelineEntry{At: start, Seconds: d.Seconds(), Kind: kind, Name: name, Details: details, duration: d}
+}
+
+// secondsDuration formats a number of seconds as a rounded duration
+func secondsDuration(seconds float64) time.Duration {
+	return (time.Duration(seconds * float64(time.Second))).Round(time.Second)
+}
+
+// line renders the entry for the text timeline: times, duration, then the indented event
+func (e timelineEntry) line() string {
+	when := e.At.Local().Format("15:04:05")
+	if e.duration > 0 && e.Kind != entryGap {
+		when += "–" + e.end().Local().Format("15:04:05")
+	}
+
+	duration := ""
+	if e.duration > 0 {
+		duration = e.duration.Round(time.Second).String()
+	}
+
+	indent := strings.Repeat("  ", e.Depth)
+	var text string
+	switch e.Kind {
+	case entryRun:
+		text = "run " + e.Name
+	case entryPhase:
+		text = fmt.Sprintf("phase %s (%s)", e.Name, e.Details)
+		if e.Overrun {
+			text += " OVERRUN"
+		}
+	case entryWait:
+		text = waitCauses[e.Name]
+	case entryGap:
+		cause := e.Cause
+		if cause == "" {
+			cause = "cause unknown"
+		}
+		text = fmt.Sprintf(">> gap of %s: %s", duration, cause)
+	default:
+		text = e.Name
+		if e.Details != "" {
+			text += ": " + truncate(e.Details, 80)
+		}
+	}
+
+	return fmt.Sprintf("%s\t%s\t%s%s", when, duration, indent, text)
+}
@@ -0,0 +1,9 @@
+// Package configs holds the example files shipped with the repository.
+package configs
+
+import _ "embed"
+
+// Default is the commented default config that config init writes
+//
+//go:embed config.yaml
+var Default []byte
@@ -0,0 +1,73 @@
+// Command embed shows the bot used as a library: it searches for a few profiles and sends
+// each a connection request with a note, through the pkg/linkedin client.
+//
+//	LINKEDIN_EMAIL=... LINKEDIN_PASSWORD=... go run ./examples/embed
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"go.uber.org/zap"
+
+	"github.com/Tanukumar01/linkedin-automation/pkg/linkedin"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	// Ctrl+C stops between steps
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	log, err := zap.NewDevelopment()
+	if err != nil {
+		return err
+	}
+	defer log.Sync()
+
+	client, err := linkedin.New(
+		linkedin.WithStorage("data/embed.db"),
+		linkedin.WithStealthProfile(linkedin.StealthCautious),
+		linkedin.WithLogger(log),
+	)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := client.Login(ctx, os.Getenv("LINKEDIN_EMAIL"), os.Getenv("LINKEDIN_PASSWORD")); err != nil {
+		return err
+	}
+
+	profiles, err := client.Search(ctx, linkedin.Filters{
+		JobTitles:  []string{"Platform Engineer"},
+		Locations:  []string{"Germany"},
+		MaxResults: 5,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, profile := range profiles {
+		result, err := client.Connect(ctx, profile, linkedin.ConnectOptions{
+			Note: "Hi {{firstName}}, I'm working on platform tooling too and would like to connect.",
+		})
+		if err != nil {
+			return fmt.Errorf("connecting to %s: %w", profile.URL, err)
+		}
+		if result.Sent {
+			fmt.Printf("Invited %s (%s)\n", profile.Name, profile.URL)
+		} else {
+			fmt.Printf("Skipped %s: %s\n", profile.Name, result.Reason)
+		}
+	}
+	return nil
+}
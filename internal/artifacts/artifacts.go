@@ -0,0 +1,189 @@
+// Package artifacts captures what the page looked like when an action failed:
+// a screenshot, the current URL and the page HTML, one directory per failure.
+package artifacts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+)
+
+// Index describes one captured artifact; it is written as index.json next to the files
+type Index struct {
+	Action     string    `json:"action"`
+	Error      string    `json:"error"`
+	URL        string    `json:"url"`
+	CapturedAt time.Time `json:"captured_at"`
+	Files      []string  `json:"files"`
+}
+
+// Collector writes failure artifacts under a directory and keeps it within its caps.
+// A nil *Collector is valid and captures nothing.
+type Collector struct {
+	dir          string
+	maxArtifacts int
+	maxBytes     int64
+
+	mu sync.Mutex
+}
+
+// NewCollector creates a collector from the debug settings, or returns nil when capturing is off
+func NewCollector(cfg config.DebugConfig) *Collector {
+	if !cfg.CaptureOnError {
+		return nil
+	}
+
+	return &Collector{
+		dir:          cfg.ArtifactsDir,
+		maxArtifacts: cfg.MaxArtifacts,
+		maxBytes:     int64(cfg.MaxTotalMB) * 1024 * 1024,
+	}
+}
+
+// Capture saves a screenshot, the URL and the HTML of page into <dir>/<timestamp>-<action>/.
+// Failures to capture are logged and never returned, so callers can report their own error.
+func (c *Collector) Capture(page pageops.Page, action string, cause error) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path, err := c.write(page, action, cause)
+	if err != nil {
+		logger.Warnf("Failed to capture %s artifact: %v", action, err)
+		return
+	}
+
+	logger.Infof("Saved %s failure artifact to %s", action, path)
+
+	if err := c.evict(); err != nil {
+		logger.Warnf("Failed to evict old artifacts: %v", err)
+	}
+}
+
+// write stores the artifact files and returns the artifact directory
+func (c *Collector) write(page pageops.Page, action string, cause error) (string, error) {
+	now := time.Now()
+	path := filepath.Join(c.dir, fmt.Sprintf("%s-%s", now.Format("2006-01-02T15-04-05.000"), action))
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return "", fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+
+	index := Index{
+		Action:     action,
+		CapturedAt: now,
+	}
+	if cause != nil {
+		index.Error = cause.Error()
+	}
+
+	if url, err := page.URL(); err == nil {
+		index.URL = url
+	} else {
+		logger.Debugf("Failed to read page URL for artifact: %v", err)
+	}
+
+	if shooter, ok := page.(pageops.Screenshotter); ok {
+		if data, err := shooter.Screenshot(); err != nil {
+			logger.Debugf("Failed to take artifact screenshot: %v", err)
+		} else if err := os.WriteFile(filepath.Join(path, "screenshot.png"), data, 0644); err != nil {
+			return "", fmt.Errorf("failed to write screenshot: %w", err)
+		} else {
+			index.Files = append(index.Files, "screenshot.png")
+		}
+	}
+
+	if snapshotter, ok := page.(pageops.DOMSnapshotter); ok {
+		if html, err := snapshotter.HTML(); err != nil {
+			logger.Debugf("Failed to read page HTML for artifact: %v", err)
+		} else if err := os.WriteFile(filepath.Join(path, "page.html"), []byte(html), 0644); err != nil {
+			return "", fmt.Errorf("failed to write page HTML: %w", err)
+		} else {
+			index.Files = append(index.Files, "page.html")
+		}
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal artifact index: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(path, "index.json"), data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write artifact index: %w", err)
+	}
+
+	return path, nil
+}
+
+// evict removes the oldest artifacts until both the count and the size caps are met.
+// The newest artifact is always kept.
+func (c *Collector) evict() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to list artifacts: %w", err)
+	}
+
+	type artifact struct {
+		name string
+		size int64
+	}
+
+	var artifacts []artifact
+	var total int64
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		size, err := dirSize(filepath.Join(c.dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		artifacts = append(artifacts, artifact{name: entry.Name(), size: size})
+		total += size
+	}
+
+	// Names start with the capture timestamp, so they sort oldest first
+	sort.Slice(artifacts, func(i, j int) bool {
+		return artifacts[i].name < artifacts[j].name
+	})
+
+	for len(artifacts) > 1 && (len(artifacts) > c.maxArtifacts || total > c.maxBytes) {
+		oldest := artifacts[0]
+		if err := os.RemoveAll(filepath.Join(c.dir, oldest.name)); err != nil {
+			return fmt.Errorf("failed to remove artifact %s: %w", oldest.name, err)
+		}
+		logger.Debugf("Evicted artifact %s", oldest.name)
+		total -= oldest.size
+		artifacts = artifacts[1:]
+	}
+
+	return nil
+}
+
+// dirSize returns the total size of the files in dir
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to measure %s: %w", dir, err)
+	}
+	return size, nil
+}
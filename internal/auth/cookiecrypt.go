@@ -0,0 +1,194 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Environment variables holding the secret the cookie file is encrypted with
+const (
+	CookieKeyEnv        = "COOKIE_ENCRYPTION_KEY"        // 32 bytes, hex or base64 encoded
+	CookiePassphraseEnv = "COOKIE_ENCRYPTION_PASSPHRASE" // the key is derived from it with scrypt
+)
+
+// ErrWrongCookieSecret is returned when the cookie file doesn't decrypt with the secret set
+var ErrWrongCookieSecret = errors.New("cookie file can't be decrypted: wrong " + CookieKeyEnv + " or " + CookiePassphraseEnv + ", or the file is corrupt")
+
+// ErrNoCookieSecret is returned when the cookie file is encrypted but no secret is set
+var ErrNoCookieSecret = errors.New("cookie file is encrypted but neither " + CookieKeyEnv + " nor " + CookiePassphraseEnv + " is set")
+
+// An encrypted cookie file starts with the magic and a header, which the cipher
+// authenticates along with the cookies:
+//
+//	magic | version | kdf | [log2 N | r | p | salt] | nonce | AES-256-GCM ciphertext
+//
+// The scrypt fields are only there when the key was derived from a passphrase.
+const (
+	cookieMagic   = "LACOOKIE"
+	cookieVersion = 1
+
+	kdfNone   = 0 // the key was given as is
+	kdfScrypt = 1
+
+	scryptLogN    = 15
+	scryptR       = 8
+	scryptP       = 1
+	scryptSaltLen = 16
+)
+
+// CookieSecret is what the cookie file is encrypted with: a key, or a passphrase a key
+// is derived from for each file
+type CookieSecret struct {
+	key        []byte
+	passphrase string
+}
+
+// CookieSecretFromEnv reads the cookie file's secret from the environment. It returns
+// nil when neither variable is set, so the file stays in plaintext.
+func CookieSecretFromEnv() (*CookieSecret, error) {
+	return NewCookieSecret(os.Getenv(CookieKeyEnv), os.Getenv(CookiePassphraseEnv))
+}
+
+// NewCookieSecret creates a secret from a hex or base64 encoded 32-byte key or from a
+// passphrase; only one may be given. It returns nil when neither is.
+func NewCookieSecret(key, passphrase string) (*CookieSecret, error) {
+	key = strings.TrimSpace(key)
+	switch {
+	case key != "" && passphrase != "":
+		return nil, fmt.Errorf("set %s or %s, not both", CookieKeyEnv, CookiePassphraseEnv)
+	case passphrase != "":
+		return &CookieSecret{passphrase: passphrase}, nil
+	case key == "":
+		return nil, nil
+	}
+
+	decoded, err := hex.DecodeString(key)
+	if err != nil {
+		decoded, err = base64.StdEncoding.DecodeString(key)
+	}
+	if err != nil || len(decoded) != 32 {
+		return nil, fmt.Errorf("%s must be 32 bytes, hex or base64 encoded", CookieKeyEnv)
+	}
+	return &CookieSecret{key: decoded}, nil
+}
+
+// isEncrypted reports whether data is an encrypted cookie file
+func isEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(cookieMagic))
+}
+
+// encrypt seals plaintext into an encrypted cookie file
+func (s *CookieSecret) encrypt(plaintext []byte) ([]byte, error) {
+	header := []byte(cookieMagic)
+	header = append(header, cookieVersion)
+
+	key := s.key
+	if s.passphrase != "" {
+		salt := make([]byte, scryptSaltLen)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("failed to generate salt: %w", err)
+		}
+		header = append(header, kdfScrypt, scryptLogN, scryptR, scryptP)
+		header = append(header, salt...)
+
+		var err error
+		if key, err = scrypt.Key([]byte(s.passphrase), salt, 1<<scryptLogN, scryptR, scryptP, 32); err != nil {
+			return nil, fmt.Errorf("failed to derive cookie key: %w", err)
+		}
+	} else {
+		header = append(header, kdfNone)
+	}
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	out := append(header, nonce...)
+	return aead.Seal(out, nonce, plaintext, header), nil
+}
+
+// decrypt opens an encrypted cookie file
+func (s *CookieSecret) decrypt(data []byte) ([]byte, error) {
+	if s == nil {
+		return nil, ErrNoCookieSecret
+	}
+
+	rest := data[len(cookieMagic):]
+	if len(rest) < 2 {
+		return nil, ErrWrongCookieSecret
+	}
+	if version := rest[0]; version != cookieVersion {
+		return nil, fmt.Errorf("cookie file format version %d is not supported by this build", version)
+	}
+
+	key := s.key
+	kdf := rest[1]
+	rest = rest[2:]
+	switch kdf {
+	case kdfNone:
+		if s.passphrase != "" {
+			return nil, fmt.Errorf("cookie file was encrypted with a key; set %s instead of %s", CookieKeyEnv, CookiePassphraseEnv)
+		}
+	case kdfScrypt:
+		if s.passphrase == "" {
+			return nil, fmt.Errorf("cookie file was encrypted with a passphrase; set %s instead of %s", CookiePassphraseEnv, CookieKeyEnv)
+		}
+		if len(rest) < 3+scryptSaltLen {
+			return nil, ErrWrongCookieSecret
+		}
+		// Only the parameters encrypt writes are accepted, so a tampered header can't
+		// make the key derivation arbitrarily slow or weak
+		if logN, r, p := rest[0], rest[1], rest[2]; logN != scryptLogN || r != scryptR || p != scryptP {
+			return nil, fmt.Errorf("%w (unexpected scrypt parameters N=2^%d r=%d p=%d)", ErrWrongCookieSecret, logN, r, p)
+		}
+		salt := rest[3 : 3+scryptSaltLen]
+		rest = rest[3+scryptSaltLen:]
+
+		var err error
+		if key, err = scrypt.Key([]byte(s.passphrase), salt, 1<<scryptLogN, scryptR, scryptP, 32); err != nil {
+			return nil, fmt.Errorf("failed to derive cookie key: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("cookie file uses unknown key derivation %d", kdf)
+	}
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < aead.NonceSize()+aead.Overhead() {
+		return nil, ErrWrongCookieSecret
+	}
+
+	header := data[:len(data)-len(rest)]
+	nonce, ciphertext := rest[:aead.NonceSize()], rest[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, header)
+	if err != nil {
+		return nil, ErrWrongCookieSecret
+	}
+	return plaintext, nil
+}
+
+// newGCM creates the AES-256-GCM cipher for key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cookie cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
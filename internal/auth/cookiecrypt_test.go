@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger("error", "console", "stdout", logger.Rotation{})
+	os.Exit(m.Run())
+}
+
+// fakeJar is a browser's cookie store
+type fakeJar struct {
+	cookies []*proto.NetworkCookie
+}
+
+func (j *fakeJar) Cookies(urls []string) ([]*proto.NetworkCookie, error) {
+	return j.cookies, nil
+}
+
+func (j *fakeJar) SetCookies(params []*proto.NetworkCookieParam) error {
+	j.cookies = nil
+	for _, p := range params {
+		j.cookies = append(j.cookies, &proto.NetworkCookie{Name: p.Name, Value: p.Value, Domain: p.Domain, Path: p.Path, Expires: p.Expires})
+	}
+	return nil
+}
+
+const testKey = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
+func TestCookieSecretRoundTrip(t *testing.T) {
+	plaintext := []byte(`[{"name":"li_at","value":"secret"}]`)
+
+	for _, tt := range []struct {
+		name            string
+		key, passphrase string
+	}{
+		{name: "key", key: testKey},
+		{name: "passphrase", passphrase: "correct horse battery staple"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			secret, err := NewCookieSecret(tt.key, tt.passphrase)
+			if err != nil {
+				t.Fatalf("NewCookieSecret: %v", err)
+			}
+
+			sealed, err := secret.encrypt(plaintext)
+			if err != nil {
+				t.Fatalf("encrypt: %v", err)
+			}
+			if !isEncrypted(sealed) {
+				t.Fatal("sealed data doesn't start with the magic")
+			}
+			if bytes.Contains(sealed, []byte("secret")) {
+				t.Fatal("sealed data holds the plaintext")
+			}
+
+			opened, err := secret.decrypt(sealed)
+			if err != nil {
+				t.Fatalf("decrypt: %v", err)
+			}
+			if !bytes.Equal(opened, plaintext) {
+				t.Fatalf("decrypt = %q, want %q", opened, plaintext)
+			}
+
+			// Each file gets its own nonce, and its own salt with a passphrase
+			again, _ := secret.encrypt(plaintext)
+			if bytes.Equal(again, sealed) {
+				t.Fatal("encrypting twice gave the same file")
+			}
+		})
+	}
+}
+
+func TestCookieSecretWrongSecret(t *testing.T) {
+	secret, _ := NewCookieSecret("", "passphrase one")
+	sealed, err := secret.encrypt([]byte("[]"))
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+
+	other, _ := NewCookieSecret("", "passphrase two")
+	if _, err := other.decrypt(sealed); !errors.Is(err, ErrWrongCookieSecret) {
+		t.Fatalf("decrypt with another passphrase = %v, want ErrWrongCookieSecret", err)
+	}
+
+	var none *CookieSecret
+	if _, err := none.decrypt(sealed); !errors.Is(err, ErrNoCookieSecret) {
+		t.Fatalf("decrypt without a secret = %v, want ErrNoCookieSecret", err)
+	}
+
+	key, _ := NewCookieSecret(testKey, "")
+	if _, err := key.decrypt(sealed); err == nil {
+		t.Fatal("a key opened a file encrypted with a passphrase")
+	}
+}
+
+func TestCookieSecretRejectsOtherScryptParameters(t *testing.T) {
+	secret, _ := NewCookieSecret("", "passphrase")
+	sealed, err := secret.encrypt([]byte("[]"))
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+
+	// The scrypt parameters follow the magic, the version and the kdf byte
+	params := len(cookieMagic) + 2
+	for _, tt := range []struct {
+		name   string
+		offset int
+		value  byte
+	}{
+		{"cost raised", 0, 30},
+		{"cost lowered", 0, 1},
+		{"block size", 1, 1},
+		{"parallelism", 2, 200},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := bytes.Clone(sealed)
+			tampered[params+tt.offset] = tt.value
+			if _, err := secret.decrypt(tampered); !errors.Is(err, ErrWrongCookieSecret) {
+				t.Fatalf("decrypt = %v, want ErrWrongCookieSecret", err)
+			}
+		})
+	}
+}
+
+func TestCookieManagerEncryptsPlaintextFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cookies.json")
+	jar := &fakeJar{cookies: []*proto.NetworkCookie{{Name: "li_at", Value: "session", Domain: ".linkedin.com", Path: "/"}}}
+
+	// Saved before a secret was set
+	plain := NewCookieManager(path)
+	if err := plain.SaveCookies(jar); err != nil {
+		t.Fatalf("SaveCookies: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if isEncrypted(data) {
+		t.Fatal("file saved without a secret is encrypted")
+	}
+
+	secret, _ := NewCookieSecret(testKey, "")
+	cm := NewCookieManager(path)
+	cm.SetSecret(secret)
+
+	loaded := &fakeJar{}
+	if err := cm.LoadCookies(loaded); err != nil {
+		t.Fatalf("LoadCookies of the plaintext file: %v", err)
+	}
+	if len(loaded.cookies) != 1 || loaded.cookies[0].Value != "session" {
+		t.Fatalf("loaded cookies = %+v", loaded.cookies)
+	}
+	if !cm.NeedsEncryption() {
+		t.Fatal("NeedsEncryption = false after reading a plaintext file with a secret set")
+	}
+
+	if err := cm.SaveCookies(loaded); err != nil {
+		t.Fatalf("SaveCookies: %v", err)
+	}
+	if cm.NeedsEncryption() {
+		t.Fatal("NeedsEncryption = true after saving")
+	}
+	data, _ = os.ReadFile(path)
+	if !isEncrypted(data) || bytes.Contains(data, []byte("session")) {
+		t.Fatal("file isn't encrypted after saving with a secret")
+	}
+
+	// The next run reads it back with the secret, and not without
+	reread := &fakeJar{}
+	again := NewCookieManager(path)
+	again.SetSecret(secret)
+	if err := again.LoadCookies(reread); err != nil {
+		t.Fatalf("LoadCookies of the encrypted file: %v", err)
+	}
+	if len(reread.cookies) != 1 || reread.cookies[0].Value != "session" {
+		t.Fatalf("reloaded cookies = %+v", reread.cookies)
+	}
+	if err := NewCookieManager(path).LoadCookies(&fakeJar{}); !errors.Is(err, ErrNoCookieSecret) {
+		t.Fatalf("LoadCookies without a secret = %v, want ErrNoCookieSecret", err)
+	}
+}
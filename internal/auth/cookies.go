@@ -6,13 +6,17 @@ import (
 	"os"
 	"time"
 
-	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
 )
 
 // CookieManager handles cookie persistence
 type CookieManager struct {
-	cookieFile string
+	cookieFile      string
+	secret          *CookieSecret // encrypts the cookie file when set
+	needsEncryption bool          // the file was read in plaintext although a secret is set
 }
 
 // NewCookieManager creates a new cookie manager
@@ -22,9 +26,21 @@ func NewCookieManager(cookieFile string) *CookieManager {
 	}
 }
 
+// SetSecret encrypts the cookie file with secret from the next save on. Without one
+// cookies are saved in plaintext.
+func (cm *CookieManager) SetSecret(secret *CookieSecret) {
+	cm.secret = secret
+}
+
+// NeedsEncryption reports whether the cookies were loaded from a plaintext file although
+// a secret is set, so saving them again encrypts the file
+func (cm *CookieManager) NeedsEncryption() bool {
+	return cm.needsEncryption
+}
+
 // SaveCookies saves cookies to file
-func (cm *CookieManager) SaveCookies(page *rod.Page) error {
-	cookies, err := page.Cookies([]string{})
+func (cm *CookieManager) SaveCookies(jar pageops.CookieJar) error {
+	cookies, err := jar.Cookies([]string{})
 	if err != nil {
 		return fmt.Errorf("failed to get cookies: %w", err)
 	}
@@ -34,28 +50,30 @@ func (cm *CookieManager) SaveCookies(page *rod.Page) error {
 		return fmt.Errorf("failed to marshal cookies: %w", err)
 	}
 
+	if cm.secret != nil {
+		if data, err = cm.secret.encrypt(data); err != nil {
+			return fmt.Errorf("failed to encrypt cookies: %w", err)
+		}
+	}
+
 	if err := os.WriteFile(cm.cookieFile, data, 0600); err != nil {
 		return fmt.Errorf("failed to write cookies file: %w", err)
 	}
+	cm.needsEncryption = false
 
 	return nil
 }
 
 // LoadCookies loads cookies from file
-func (cm *CookieManager) LoadCookies(page *rod.Page) error {
+func (cm *CookieManager) LoadCookies(jar pageops.CookieJar) error {
 	// Check if cookie file exists
 	if _, err := os.Stat(cm.cookieFile); os.IsNotExist(err) {
 		return nil // No cookies to load
 	}
 
-	data, err := os.ReadFile(cm.cookieFile)
+	cookies, err := cm.readCookies()
 	if err != nil {
-		return fmt.Errorf("failed to read cookies file: %w", err)
-	}
-
-	var cookies []*proto.NetworkCookie
-	if err := json.Unmarshal(data, &cookies); err != nil {
-		return fmt.Errorf("failed to unmarshal cookies: %w", err)
+		return err
 	}
 
 	var params []*proto.NetworkCookieParam
@@ -73,13 +91,80 @@ func (cm *CookieManager) LoadCookies(page *rod.Page) error {
 	}
 
 	// Set cookies
-	if err := page.SetCookies(params); err != nil {
+	if err := jar.SetCookies(params); err != nil {
 		return fmt.Errorf("failed to set cookies: %w", err)
 	}
 
 	return nil
 }
 
+// readCookies reads the cookie file, decrypting it when it is encrypted. A plaintext
+// file is still read when a secret is set, as it was saved before the secret was.
+func (cm *CookieManager) readCookies() ([]*proto.NetworkCookie, error) {
+	data, err := os.ReadFile(cm.cookieFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cookies file: %w", err)
+	}
+
+	if isEncrypted(data) {
+		if data, err = cm.secret.decrypt(data); err != nil {
+			return nil, fmt.Errorf("failed to load cookies from %s: %w", cm.cookieFile, err)
+		}
+	} else if cm.secret != nil && !cm.needsEncryption {
+		logger.Infof("Cookie file %s is in plaintext; it will be encrypted when the session is next saved", cm.cookieFile)
+		cm.needsEncryption = true
+	}
+
+	var cookies []*proto.NetworkCookie
+	if err := json.Unmarshal(data, &cookies); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cookies: %w", err)
+	}
+	return cookies, nil
+}
+
+// sessionCookie is the cookie that keeps a LinkedIn login alive
+const sessionCookie = "li_at"
+
+// SessionExpiry returns when the saved login session expires. It returns a zero
+// time when no session cookie is saved, and reports whether the cookie is
+// session-only, which does not survive a browser restart.
+func (cm *CookieManager) SessionExpiry() (time.Time, bool, error) {
+	if _, err := os.Stat(cm.cookieFile); os.IsNotExist(err) {
+		return time.Time{}, false, nil
+	}
+	cookies, err := cm.readCookies()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	expires, sessionOnly := sessionExpiry(cookies)
+	return expires, sessionOnly, nil
+}
+
+// SavedAt returns when the cookie file was last written, or a zero time when there is none
+func (cm *CookieManager) SavedAt() time.Time {
+	info, err := os.Stat(cm.cookieFile)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// sessionExpiry returns when the session cookie among cookies expires, with a zero time
+// when there is none, and reports whether it is session-only
+func sessionExpiry(cookies []*proto.NetworkCookie) (time.Time, bool) {
+	for _, c := range cookies {
+		if c.Name != sessionCookie {
+			continue
+		}
+		if c.Session || c.Expires <= 0 {
+			return time.Time{}, true
+		}
+		return c.Expires.Time(), false
+	}
+	return time.Time{}, false
+}
+
 // ClearCookies removes the cookie file
 func (cm *CookieManager) ClearCookies() error {
 	if _, err := os.Stat(cm.cookieFile); os.IsNotExist(err) {
@@ -89,24 +174,14 @@ func (cm *CookieManager) ClearCookies() error {
 	return os.Remove(cm.cookieFile)
 }
 
-// AreCookiesValid checks if cookies are still valid
-func (cm *CookieManager) AreCookiesValid(page *rod.Page) bool {
-	cookies, err := page.Cookies([]string{})
+// AreCookiesValid reports whether the browser holds a LinkedIn session cookie that hasn't
+// expired. A session-only cookie counts, as it lasts as long as the browser.
+func (cm *CookieManager) AreCookiesValid(jar pageops.CookieJar) bool {
+	cookies, err := jar.Cookies([]string{})
 	if err != nil {
 		return false
 	}
 
-	// Check if we have LinkedIn session cookies
-	hasSessionCookie := false
-	for _, cookie := range cookies {
-		if cookie.Name == "li_at" || cookie.Name == "JSESSIONID" {
-			// Check if cookie is not expired
-			if cookie.Expires > 0 && time.Unix(int64(cookie.Expires), 0).After(time.Now()) {
-				hasSessionCookie = true
-				break
-			}
-		}
-	}
-
-	return hasSessionCookie
+	expires, sessionOnly := sessionExpiry(cookies)
+	return sessionOnly || expires.After(time.Now())
 }
@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func TestAreCookiesValid(t *testing.T) {
+	at := func(d time.Duration) proto.TimeSinceEpoch { return proto.TimeSinceEpoch(time.Now().Add(d).Unix()) }
+
+	for _, tt := range []struct {
+		name    string
+		cookies []*proto.NetworkCookie
+		want    bool
+	}{
+		{"unexpired", []*proto.NetworkCookie{{Name: "li_at", Expires: at(time.Hour)}}, true},
+		{"expired", []*proto.NetworkCookie{{Name: "li_at", Expires: at(-time.Hour)}}, false},
+		{"session-only", []*proto.NetworkCookie{{Name: "li_at", Session: true}}, true},
+		{"no expiry", []*proto.NetworkCookie{{Name: "li_at"}}, true},
+		{"only JSESSIONID", []*proto.NetworkCookie{{Name: "JSESSIONID", Expires: at(time.Hour)}}, false},
+		{"none", nil, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewCookieManager("").AreCookiesValid(&fakeJar{cookies: tt.cookies}); got != tt.want {
+				t.Fatalf("AreCookiesValid = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSavedAt(t *testing.T) {
+	cm := NewCookieManager(t.TempDir() + "/cookies.json")
+	if saved := cm.SavedAt(); !saved.IsZero() {
+		t.Fatalf("SavedAt = %v without a file", saved)
+	}
+
+	if err := cm.SaveCookies(&fakeJar{}); err != nil {
+		t.Fatal(err)
+	}
+	if saved := cm.SavedAt(); time.Since(saved) > time.Minute {
+		t.Fatalf("SavedAt = %v right after saving", saved)
+	}
+}
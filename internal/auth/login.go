@@ -1,42 +1,114 @@
 package auth
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
-	"github.com/go-rod/rod"
-
+	"github.com/Tanukumar01/linkedin-automation/internal/captcha"
 	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/notify"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
 	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
-	"github.com/go-rod/rod/lib/proto"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
 )
 
 // Authenticator handles LinkedIn authentication
 type Authenticator struct {
-	page          *rod.Page
-	typer         *stealth.Typer
+	page          pageops.Page
+	typer         pageops.TextTyper
+	clicker       pageops.Clicker
 	timing        *stealth.TimingController
 	cookieManager *CookieManager
+	notifier      notify.Notifier
+	solver        *captcha.Solver
+	auditLog      AuditLog
+}
+
+// AuditLog records the actions taken; *storage.DB satisfies it
+type AuditLog interface {
+	Audit(entry storage.AuditEntry) error
 }
 
+// ErrCaptchaChallenge is returned when the login page shows a CAPTCHA
+var ErrCaptchaChallenge = errors.New("CAPTCHA challenge detected")
+
+// injectRecaptchaToken fills the reCAPTCHA response fields with the token and submits their form
+const injectRecaptchaToken = `(token) => {
+	const fields = document.querySelectorAll("textarea[name='g-recaptcha-response'], #g-recaptcha-response");
+	if (fields.length === 0) {
+		return false;
+	}
+	fields.forEach((field) => {
+		field.value = token;
+		field.innerHTML = token;
+	});
+	const form = fields[0].closest("form");
+	if (form) {
+		form.submit();
+	}
+	return true;
+}`
+
 // NewAuthenticator creates a new authenticator
-func NewAuthenticator(page *rod.Page, typer *stealth.Typer, timing *stealth.TimingController, cookieFile string) *Authenticator {
+func NewAuthenticator(page pageops.Page, typer pageops.TextTyper, clicker pageops.Clicker, timing *stealth.TimingController, cookieFile string) *Authenticator {
 	return &Authenticator{
 		page:          page,
 		typer:         typer,
+		clicker:       clicker,
 		timing:        timing,
 		cookieManager: NewCookieManager(cookieFile),
+		notifier:      notify.Nop{},
 	}
 }
 
+// SetNotifier sets the notifier used to report login challenges
+func (a *Authenticator) SetNotifier(n notify.Notifier) {
+	a.notifier = n
+}
+
+// SetCaptchaSolver sets the optional service used to solve CAPTCHAs before falling back to manual solving
+func (a *Authenticator) SetCaptchaSolver(s *captcha.Solver) {
+	a.solver = s
+}
+
+// SetAuditLog sets where logins are audited
+func (a *Authenticator) SetAuditLog(l AuditLog) {
+	a.auditLog = l
+}
+
+// audit records a login attempt and how it went
+func (a *Authenticator) audit(method string, err error, started time.Time) {
+	if a.auditLog == nil {
+		return
+	}
+	result := storage.AuditSuccess
+	if err != nil {
+		result = storage.AuditFailed
+	}
+	a.auditLog.Audit(storage.AuditEntry{Action: "login", Details: method, Result: result, Err: err,
+		PageURL: pageops.CurrentURL(a.page), Started: started})
+}
+
 // Login performs LinkedIn login
-func (a *Authenticator) Login(email, password string) error {
+func (a *Authenticator) Login(email, password string) (err error) {
+	started, method := time.Now(), "password"
+	defer func() { a.audit(method, err, started) }()
+
 	logger.Info("Starting LinkedIn login process")
 
-	// Try to load existing cookies
-	if err := a.cookieManager.LoadCookies(a.page); err != nil {
-		logger.Warnf("Failed to load cookies: %v", err)
+	// Try to load existing cookies; without a live session cookie there's no point visiting the homepage
+	if jar, ok := a.page.(pageops.CookieJar); ok {
+		if err := a.cookieManager.LoadCookies(jar); err != nil {
+			logger.Warnf("Failed to load cookies: %v", err)
+		}
+		if !a.cookieManager.AreCookiesValid(jar) {
+			logger.Info("No unexpired session cookie, performing login")
+			return a.loginWithPassword(email, password)
+		}
 	}
 
 	// Navigate to LinkedIn
@@ -55,11 +127,21 @@ func (a *Authenticator) Login(email, password string) error {
 	// Check if already logged in
 	if a.IsLoggedIn() {
 		logger.Info("Already logged in using saved session")
+		method = "saved session"
+		if jar, ok := a.page.(pageops.CookieJar); ok && a.cookieManager.NeedsEncryption() {
+			if err := a.cookieManager.SaveCookies(jar); err != nil {
+				logger.Warnf("Failed to encrypt saved cookies: %v", err)
+			}
+		}
 		return nil
 	}
 
 	logger.Info("No valid session found, performing login")
+	return a.loginWithPassword(email, password)
+}
 
+// loginWithPassword signs in through the login form and saves the new session's cookies
+func (a *Authenticator) loginWithPassword(email, password string) error {
 	// Navigate to login page
 	if err := a.page.Navigate("https://www.linkedin.com/login"); err != nil {
 		return fmt.Errorf("failed to navigate to login page: %w", err)
@@ -72,28 +154,28 @@ func (a *Authenticator) Login(email, password string) error {
 	a.timing.Wait(a.timing.ThinkTime())
 
 	// Find email input
-	emailInput, err := a.page.Element("#username")
+	emailInput, err := selectors.WaitFirst(a.page, selectors.LoginEmail, 30*time.Second)
 	if err != nil {
 		return fmt.Errorf("failed to find email input: %w", err)
 	}
 
 	// Type email
 	logger.Info("Entering email")
-	if err := a.typer.TypeText(a.page, emailInput, email); err != nil {
+	if err := a.typer.TypeText(emailInput, email); err != nil {
 		return fmt.Errorf("failed to type email: %w", err)
 	}
 
 	a.timing.Wait(a.timing.ShortPause())
 
 	// Find password input
-	passwordInput, err := a.page.Element("#password")
+	passwordInput, err := selectors.WaitFirst(a.page, selectors.LoginPassword, 10*time.Second)
 	if err != nil {
 		return fmt.Errorf("failed to find password input: %w", err)
 	}
 
 	// Type password
 	logger.Info("Entering password")
-	if err := a.typer.TypeText(a.page, passwordInput, password); err != nil {
+	if err := a.typer.TypeText(passwordInput, password); err != nil {
 		return fmt.Errorf("failed to type password: %w", err)
 	}
 
@@ -101,12 +183,12 @@ func (a *Authenticator) Login(email, password string) error {
 
 	// Click sign in button
 	logger.Info("Clicking sign in button")
-	signInButton, err := a.page.Element("button[type='submit']")
+	signInButton, err := selectors.FindFirst(a.page, selectors.LoginSubmit)
 	if err != nil {
 		return fmt.Errorf("failed to find sign in button: %w", err)
 	}
 
-	if err := signInButton.Click(proto.InputMouseButtonLeft, 1); err != nil {
+	if err := a.clicker.Click(signInButton); err != nil {
 		return fmt.Errorf("failed to click sign in button: %w", err)
 	}
 
@@ -122,33 +204,44 @@ func (a *Authenticator) Login(email, password string) error {
 	success := make(chan bool)
 
 	go func() {
-		// Use a page without a strict timeout for the polling loop
-		// to avoid "context deadline exceeded" while waiting for user interaction
-		pollPage := a.page.CancelTimeout()
+		challengeNotified := false
+		solveAttempted := false
 
 		for i := 0; i < 600; i++ { // Wait up to 10 minutes
 			// Check URL and indicators
-			if info, err := pollPage.Info(); err == nil {
-				if strings.Contains(info.URL, "/feed") ||
-					strings.Contains(info.URL, "/mynetwork") ||
-					strings.Contains(info.URL, "/messaging") {
+			if url, err := a.page.URL(); err == nil {
+				if strings.Contains(url, "/feed") ||
+					strings.Contains(url, "/mynetwork") ||
+					strings.Contains(url, "/messaging") {
 					success <- true
 					return
 				}
 			}
 
 			// Check for multiple indicators of logged-in state
-			indicators := []string{
-				"nav.global-nav",
-				"#global-nav",
-				".global-nav",
-				"img.global-nav__me-photo",
+			if selectors.Has(a.page, selectors.LoggedInIndicator) {
+				success <- true
+				return
 			}
 
-			for _, selector := range indicators {
-				if has, _, _ := pollPage.Has(selector); has {
-					success <- true
-					return
+			// Try the solving service once, then let someone know a challenge needs solving by hand
+			if !challengeNotified {
+				err := a.checkForSecurityChallenges()
+				if errors.Is(err, ErrCaptchaChallenge) && a.solver != nil && !solveAttempted {
+					solveAttempted = true
+					if sErr := a.solveCaptcha(); sErr != nil {
+						logger.Warnf("Automatic CAPTCHA solving failed, waiting for a manual solve: %v", sErr)
+					} else {
+						logger.Info("CAPTCHA token submitted, waiting for login to complete")
+						err = nil
+					}
+				}
+
+				if err != nil {
+					challengeNotified = true
+					if nErr := a.notifier.Notify(notify.NewEvent(notify.EventChallenge, err.Error())); nErr != nil {
+						logger.Warnf("Failed to send notification: %v", nErr)
+					}
 				}
 			}
 
@@ -164,8 +257,6 @@ func (a *Authenticator) Login(email, password string) error {
 
 	if <-success {
 		logger.Info("Login success detected! Proceeding...")
-		// Important: reset page to a fresh state (with timeout) for next operations
-		a.page = a.page.CancelTimeout()
 	} else {
 		return fmt.Errorf("timeout waiting for login. Please try again")
 	}
@@ -178,75 +269,123 @@ func (a *Authenticator) Login(email, password string) error {
 	logger.Info("Login successful")
 
 	// Save cookies
-	if err := a.cookieManager.SaveCookies(a.page); err != nil {
-		logger.Warnf("Failed to save cookies: %v", err)
+	if jar, ok := a.page.(pageops.CookieJar); ok {
+		if err := a.cookieManager.SaveCookies(jar); err != nil {
+			logger.Warnf("Failed to save cookies: %v", err)
+		}
 	}
 
 	return nil
 }
 
+// sessionRefreshInterval is how old the saved cookies may get before a session re-saves them
+const sessionRefreshInterval = 24 * time.Hour
+
+// SessionTTL returns how long the browser's LinkedIn session cookie remains valid. It
+// reports false when there is no such cookie or it only lasts as long as the browser.
+func (a *Authenticator) SessionTTL() (time.Duration, bool) {
+	jar, ok := a.page.(pageops.CookieJar)
+	if !ok {
+		return 0, false
+	}
+	cookies, err := jar.Cookies([]string{})
+	if err != nil {
+		return 0, false
+	}
+
+	expires, _ := sessionExpiry(cookies)
+	if expires.IsZero() {
+		return 0, false
+	}
+	return time.Until(expires), true
+}
+
+// RefreshSavedSession re-saves the browser's cookies once the saved ones are a day old,
+// so the file keeps up with the expiry LinkedIn extends as the account is used. Call it
+// after the session's activity; it does nothing when the browser's session is gone.
+func (a *Authenticator) RefreshSavedSession() error {
+	jar, ok := a.page.(pageops.CookieJar)
+	if !ok || !a.cookieManager.AreCookiesValid(jar) {
+		return nil
+	}
+	if time.Since(a.cookieManager.SavedAt()) < sessionRefreshInterval {
+		return nil
+	}
+
+	if err := a.cookieManager.SaveCookies(jar); err != nil {
+		return fmt.Errorf("failed to refresh saved session: %w", err)
+	}
+	if ttl, ok := a.SessionTTL(); ok {
+		logger.Infof("Saved refreshed session cookies, valid until %s", time.Now().Add(ttl).Format("2006-01-02 15:04"))
+	}
+	return nil
+}
+
 // IsLoggedIn checks if user is logged in
 func (a *Authenticator) IsLoggedIn() bool {
 	// 1. Check URL
-	if info, err := a.page.Info(); err == nil {
-		if strings.Contains(info.URL, "/feed") || strings.Contains(info.URL, "/mynetwork") {
+	if url, err := a.page.URL(); err == nil {
+		if strings.Contains(url, "/feed") || strings.Contains(url, "/mynetwork") {
 			return true
 		}
 	}
 
 	// 2. Check for multiple indicators of logged-in state
-	indicators := []string{
-		"nav.global-nav",
-		"#global-nav",
-		".global-nav",
-		"button.global-nav__primary-link--active",
-		"div.authentication-outlet", // Container for the logged in app
-		"img.global-nav__me-photo",  // Profile photo in nav
+	return selectors.Has(a.page, selectors.LoggedInIndicator)
+}
+
+// EnsureLoggedIn checks that the session is still signed in, as it may not be once LinkedIn
+// puts a page behind its sign-in wall, and signs in again when it isn't
+func (a *Authenticator) EnsureLoggedIn(email, password string) (err error) {
+	if err := a.page.Navigate("https://www.linkedin.com/feed/"); err != nil {
+		return fmt.Errorf("failed to navigate to LinkedIn: %w", err)
+	}
+	if err := a.page.WaitLoad(); err != nil {
+		logger.Warnf("Feed load wait timed out/failed: %v. Checking status anyway...", err)
 	}
+	a.timing.Wait(a.timing.ThinkTime())
 
-	for _, selector := range indicators {
-		if has, _, _ := a.page.Has(selector); has {
-			return true
-		}
+	if !pageops.IsAuthwallURL(pageops.CurrentURL(a.page)) && a.IsLoggedIn() {
+		logger.Info("Session still signed in")
+		return nil
 	}
 
-	return false
+	started := time.Now()
+	defer func() { a.audit("re-login", err, started) }()
+	logger.Warn("LinkedIn signed the session out, signing in again")
+	return a.loginWithPassword(email, password)
 }
 
 // checkForSecurityChallenges detects security challenges
 func (a *Authenticator) checkForSecurityChallenges() error {
 	// Check for 2FA
-	has2FA, _, _ := a.page.Has("input[id*='verification']")
-	if has2FA {
+	if selectors.Has(a.page, selectors.Challenge2FA) {
 		logger.Warn("2FA detected - manual intervention required")
 		return fmt.Errorf("2FA challenge detected - please complete manually")
 	}
 
 	// Check for CAPTCHA
-	hasCaptcha, _, _ := a.page.Has("iframe[title*='recaptcha']")
-	if hasCaptcha {
-		logger.Warn("CAPTCHA detected - manual intervention required")
-		return fmt.Errorf("CAPTCHA challenge detected - please complete manually")
+	if selectors.Has(a.page, selectors.ChallengeCaptcha) {
+		logger.Warn("CAPTCHA detected")
+		return fmt.Errorf("%w - please complete manually", ErrCaptchaChallenge)
 	}
 
 	// Check for unusual login alert
-	hasAlert, _, _ := a.page.Has("div[data-test-id='unusual-activity']")
-	if hasAlert {
+	if selectors.Has(a.page, selectors.ChallengeUnusual) {
 		logger.Warn("Unusual login activity alert detected")
 		return fmt.Errorf("unusual login activity detected - please verify manually")
 	}
 
 	// Check for email verification
-	hasEmailVerification, _, _ := a.page.Has("input[name='pin']")
-	if hasEmailVerification {
+	if selectors.Has(a.page, selectors.ChallengeEmailPIN) {
 		logger.Warn("Email verification required - manual intervention needed")
 		return fmt.Errorf("email verification required - please complete manually")
 	}
 
 	// Check for mobile app verification (Check your phone)
-	info, err := a.page.Info()
-	if err == nil && info.URL != "" {
-		if hasChallenge, _, _ := a.page.Has("button[id*='resend']"); hasChallenge {
+	url, err := a.page.URL()
+	if err == nil && url != "" {
+		if selectors.Has(a.page, selectors.ChallengeApproval) {
 			logger.Warn("Mobile app verification detected - please approve on your phone")
 			return fmt.Errorf("mobile app verification required - please approve on your phone")
 		}
@@ -255,6 +394,59 @@ func (a *Authenticator) checkForSecurityChallenges() error {
 	return nil
 }
 
+// solveCaptcha sends the reCAPTCHA on the page to the solving service and submits the returned token
+func (a *Authenticator) solveCaptcha() error {
+	frame, err := selectors.FindFirst(a.page, selectors.ChallengeCaptcha)
+	if err != nil {
+		return err
+	}
+
+	src, err := frame.Property("src")
+	if err != nil {
+		return fmt.Errorf("failed to read captcha frame: %w", err)
+	}
+
+	// The widget frame carries the site key in its k parameter
+	frameURL, err := url.Parse(src)
+	if err != nil {
+		return fmt.Errorf("failed to parse captcha frame URL: %w", err)
+	}
+	siteKey := frameURL.Query().Get("k")
+	if siteKey == "" {
+		return fmt.Errorf("captcha frame has no site key")
+	}
+
+	pageURL, err := a.page.URL()
+	if err != nil {
+		return fmt.Errorf("failed to get page URL: %w", err)
+	}
+
+	evaluator, ok := a.page.(pageops.Evaluator)
+	if !ok {
+		return fmt.Errorf("page can't run scripts to submit a captcha token")
+	}
+
+	logger.Info("Sending CAPTCHA to the solving service")
+	token, err := a.solver.Solve(captcha.Task{
+		Type:    captcha.TypeRecaptchaV2,
+		SiteKey: siteKey,
+		PageURL: pageURL,
+	})
+	if err != nil {
+		return err
+	}
+
+	injected, err := evaluator.Eval(injectRecaptchaToken, token)
+	if err != nil {
+		return fmt.Errorf("failed to submit captcha token: %w", err)
+	}
+	if injected != "true" {
+		return fmt.Errorf("no captcha response field found on the page")
+	}
+
+	return nil
+}
+
 // Logout performs logout
 func (a *Authenticator) Logout() error {
 	logger.Info("Logging out")
@@ -0,0 +1,234 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/clock"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops/pagetest"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+const (
+	homeURL  = "https://www.linkedin.com"
+	loginURL = "https://www.linkedin.com/login"
+	feedURL  = "https://www.linkedin.com/feed/"
+
+	loginForm = `<html><body><form class="login__form">
+		<input id="username" name="session_key" type="text">
+		<input id="password" name="session_password" type="password">
+		<button type="submit" aria-label="Sign in">Sign in</button>
+	</form></body></html>`
+	feedPage = `<html><body><nav class="global-nav"></nav><main class="scaffold-layout"></main></body></html>`
+)
+
+// auditLog collects the audited actions
+type auditLog struct {
+	entries []storage.AuditEntry
+}
+
+func (l *auditLog) Audit(entry storage.AuditEntry) error {
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+// newLoginHarness returns an authenticator on a page where signing in through the login
+// form opens the feed
+func newLoginHarness(t *testing.T) (*Authenticator, *pagetest.Page, *pagetest.Typer, *auditLog) {
+	t.Helper()
+
+	page := pagetest.New("about:blank", "")
+	page.Serve(homeURL, `<html><body><a href="/login">Sign in</a></body></html>`)
+	page.Serve(loginURL, loginForm)
+	page.Serve(feedURL, feedPage)
+	page.OnClick("button[type='submit']", func(*pagetest.Element) {
+		page.Navigate(feedURL)
+	})
+
+	timing := stealth.NewTimingController(1, 2, 1, 2, 200)
+	timing.SetClock(clock.NewFake(time.Now()))
+
+	typer := &pagetest.Typer{}
+	log := &auditLog{}
+	a := NewAuthenticator(page, typer, &pagetest.Clicker{}, timing, filepath.Join(t.TempDir(), "cookies.json"))
+	a.SetAuditLog(log)
+	return a, page, typer, log
+}
+
+func TestLoginWithPassword(t *testing.T) {
+	a, page, typer, log := newLoginHarness(t)
+
+	if err := a.Login("ada@example.com", "hunter2"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	if len(typer.Typed) != 2 || typer.Typed[0] != "ada@example.com" || typer.Typed[1] != "hunter2" {
+		t.Fatalf("typed %q, want the email and the password", typer.Typed)
+	}
+	if u, _ := page.URL(); u != feedURL {
+		t.Fatalf("URL = %q after signing in", u)
+	}
+	if len(log.entries) != 1 || log.entries[0].Details != "password" || log.entries[0].Result != storage.AuditSuccess {
+		t.Fatalf("audit = %+v, want a successful password login", log.entries)
+	}
+}
+
+func TestLoginWithSavedSession(t *testing.T) {
+	a, page, typer, log := newLoginHarness(t)
+	page.Redirect(homeURL, feedURL)
+
+	if err := a.Login("ada@example.com", "hunter2"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	if len(typer.Typed) != 0 {
+		t.Fatalf("typed %q with a saved session", typer.Typed)
+	}
+	if len(page.Navigations) != 1 {
+		t.Fatalf("navigations = %v, want the homepage only", page.Navigations)
+	}
+	if len(log.entries) != 1 || log.entries[0].Details != "saved session" {
+		t.Fatalf("audit = %+v, want a saved session login", log.entries)
+	}
+}
+
+func TestEnsureLoggedIn(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		authwall bool
+		typed    int
+	}{
+		{"signed in", false, 0},
+		{"signed out", true, 2},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			a, page, typer, log := newLoginHarness(t)
+			if tt.authwall {
+				// The first visit of the feed lands on the sign-in wall; after signing in it opens
+				wall := "https://www.linkedin.com/authwall?trk=feed"
+				page.Redirect(feedURL, wall)
+				page.Serve(wall, loginForm)
+				page.OnClick("button[type='submit']", func(*pagetest.Element) {
+					page.SetURL(feedURL)
+					page.SetHTML(feedPage)
+				})
+			}
+
+			if err := a.EnsureLoggedIn("ada@example.com", "hunter2"); err != nil {
+				t.Fatalf("EnsureLoggedIn: %v", err)
+			}
+			if len(typer.Typed) != tt.typed {
+				t.Fatalf("typed %q, want %d fields", typer.Typed, tt.typed)
+			}
+			if tt.authwall && (len(log.entries) != 1 || log.entries[0].Details != "re-login") {
+				t.Fatalf("audit = %+v, want a re-login", log.entries)
+			}
+			if !tt.authwall && len(log.entries) != 0 {
+				t.Fatalf("audit = %+v, want nothing while signed in", log.entries)
+			}
+		})
+	}
+}
+
+// cookiePage is a page whose browser holds cookies
+type cookiePage struct {
+	*pagetest.Page
+	*fakeJar
+}
+
+// sessionCookies returns a li_at session cookie expiring at expires
+func sessionCookies(expires time.Time) []*proto.NetworkCookie {
+	return []*proto.NetworkCookie{{Name: "li_at", Value: "session", Domain: ".linkedin.com", Path: "/",
+		Expires: proto.TimeSinceEpoch(expires.Unix())}}
+}
+
+func TestLoginSkipsHomepageWithExpiredSession(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		expires time.Time
+		first   string // the first page visited
+		typed   int
+	}{
+		{"expired", time.Now().Add(-time.Hour), loginURL, 2},
+		{"unexpired", time.Now().Add(30 * 24 * time.Hour), homeURL, 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			a, page, typer, _ := newLoginHarness(t)
+			page.Redirect(homeURL, feedURL)
+			a.page = cookiePage{page, &fakeJar{cookies: sessionCookies(tt.expires)}}
+
+			if err := a.Login("ada@example.com", "hunter2"); err != nil {
+				t.Fatalf("Login: %v", err)
+			}
+			if len(page.Navigations) == 0 || page.Navigations[0] != tt.first {
+				t.Fatalf("navigations = %v, want %s first", page.Navigations, tt.first)
+			}
+			if len(typer.Typed) != tt.typed {
+				t.Fatalf("typed %q, want %d fields", typer.Typed, tt.typed)
+			}
+		})
+	}
+}
+
+func TestSessionTTL(t *testing.T) {
+	a, page, _, _ := newLoginHarness(t)
+	jar := &fakeJar{cookies: sessionCookies(time.Now().Add(48 * time.Hour))}
+	a.page = cookiePage{page, jar}
+
+	if ttl, ok := a.SessionTTL(); !ok || ttl < 47*time.Hour || ttl > 48*time.Hour {
+		t.Fatalf("SessionTTL = %v, %v; want about two days", ttl, ok)
+	}
+
+	jar.cookies[0].Expires = 0
+	if ttl, ok := a.SessionTTL(); ok {
+		t.Fatalf("SessionTTL = %v for a session-only cookie", ttl)
+	}
+	if ttl, ok := NewAuthenticator(page, nil, nil, nil, "").SessionTTL(); ok {
+		t.Fatalf("SessionTTL = %v without cookies", ttl)
+	}
+}
+
+func TestRefreshSavedSession(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		age     time.Duration // of the saved cookie file
+		expires time.Time     // of the browser's session
+		saved   bool
+	}{
+		{"fresh file", time.Hour, time.Now().Add(30 * 24 * time.Hour), false},
+		{"day-old file", 25 * time.Hour, time.Now().Add(30 * 24 * time.Hour), true},
+		{"session gone", 25 * time.Hour, time.Now().Add(-time.Hour), false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			a, page, _, _ := newLoginHarness(t)
+			path := a.cookieManager.cookieFile
+			if err := os.WriteFile(path, []byte("[]"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			old := time.Now().Add(-tt.age)
+			if err := os.Chtimes(path, old, old); err != nil {
+				t.Fatal(err)
+			}
+			a.page = cookiePage{page, &fakeJar{cookies: sessionCookies(tt.expires)}}
+
+			if err := a.RefreshSavedSession(); err != nil {
+				t.Fatalf("RefreshSavedSession: %v", err)
+			}
+			if saved := a.cookieManager.SavedAt().After(old.Add(time.Minute)); saved != tt.saved {
+				t.Fatalf("saved = %v, want %v", saved, tt.saved)
+			}
+			expires, _, err := a.cookieManager.SessionExpiry()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.saved != !expires.IsZero() {
+				t.Fatalf("saved session expires %v", expires)
+			}
+		})
+	}
+}
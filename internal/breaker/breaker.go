@@ -0,0 +1,116 @@
+// Package breaker stops a run whose profile actions keep failing, as they do when a
+// LinkedIn redesign breaks the selectors: carrying on would only add suspicious traffic.
+package breaker
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+)
+
+// ErrTripped is returned once too many profile actions have failed
+var ErrTripped = errors.New("too many profile actions failed")
+
+// Breaker watches the outcomes of profile actions and trips when too many fail in a
+// row, or too many of the last ones fail. Once tripped it stays tripped.
+// A nil *Breaker is valid and never trips.
+type Breaker struct {
+	maxConsecutive int     // 0 or less never trips on a streak
+	window         int     // outcomes the failure rate is taken over
+	maxRate        float64 // below 0 never trips on the rate
+
+	outcomes    []bool // the last window outcomes, true for a failure
+	consecutive int    // failures in a row
+	reason      string // why it tripped; empty until it has
+	lastErr     error
+}
+
+// New creates a breaker with the configured thresholds
+func New(cfg config.BreakerConfig) *Breaker {
+	return &Breaker{
+		maxConsecutive: cfg.MaxConsecutiveFailures,
+		window:         cfg.Window,
+		maxRate:        cfg.MaxFailureRate,
+	}
+}
+
+// Record counts the outcome of a profile action, nil for a success, and reports whether
+// the breaker has tripped. Callers leave out errors that say nothing about the page,
+// such as a limit being used up.
+func (b *Breaker) Record(err error) bool {
+	if b == nil {
+		return false
+	}
+	if b.reason != "" {
+		return true
+	}
+
+	failed := err != nil
+	if failed {
+		b.consecutive++
+		b.lastErr = err
+	} else {
+		b.consecutive = 0
+	}
+
+	if b.window > 0 {
+		b.outcomes = append(b.outcomes, failed)
+		if len(b.outcomes) > b.window {
+			b.outcomes = b.outcomes[1:]
+		}
+	}
+
+	switch {
+	case b.maxConsecutive > 0 && b.consecutive >= b.maxConsecutive:
+		b.reason = fmt.Sprintf("%d profile actions failed in a row", b.consecutive)
+	case b.maxRate >= 0 && b.window > 0 && len(b.outcomes) == b.window:
+		if failures := b.failures(); float64(failures)/float64(b.window) > b.maxRate {
+			b.reason = fmt.Sprintf("%d of the last %d profile actions failed", failures, b.window)
+		}
+	}
+	return b.reason != ""
+}
+
+// failures counts the failures among the recorded outcomes
+func (b *Breaker) failures() int {
+	n := 0
+	for _, failed := range b.outcomes {
+		if failed {
+			n++
+		}
+	}
+	return n
+}
+
+// Tripped reports whether the breaker has tripped
+func (b *Breaker) Tripped() bool {
+	return b != nil && b.reason != ""
+}
+
+// Reason says why the breaker tripped, with the last failure; empty until it has
+func (b *Breaker) Reason() string {
+	if !b.Tripped() {
+		return ""
+	}
+	if b.lastErr == nil {
+		return b.reason
+	}
+	return fmt.Sprintf("%s (last: %v)", b.reason, b.lastErr)
+}
+
+// LastErr returns the most recent failure recorded
+func (b *Breaker) LastErr() error {
+	if b == nil {
+		return nil
+	}
+	return b.lastErr
+}
+
+// Err returns ErrTripped with the reason once the breaker has tripped, and nil before
+func (b *Breaker) Err() error {
+	if !b.Tripped() {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrTripped, b.Reason())
+}
@@ -0,0 +1,70 @@
+package breaker
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+)
+
+func TestBreakerTrips(t *testing.T) {
+	failed := errors.New("connect button not found")
+
+	for _, tt := range []struct {
+		name     string
+		cfg      config.BreakerConfig
+		outcomes string // f for a failure, . for a success
+		trips    int    // outcome the breaker trips on, 1-based; 0 for never
+		reason   string
+	}{
+		{"failures in a row", config.BreakerConfig{MaxConsecutiveFailures: 3, MaxFailureRate: -1}, ".ff.fff..", 7, "3 profile actions failed in a row"},
+		{"streak broken in time", config.BreakerConfig{MaxConsecutiveFailures: 3, MaxFailureRate: -1}, "ff.ff.ff.", 0, ""},
+		{"failure rate", config.BreakerConfig{Window: 5, MaxFailureRate: 0.5}, "f.f.f..", 5, "3 of the last 5 profile actions failed"},
+		{"rate at the limit", config.BreakerConfig{Window: 4, MaxFailureRate: 0.5}, "f.f.f.f.", 0, ""},
+		{"rate waits for a full window", config.BreakerConfig{Window: 10, MaxFailureRate: 0.2}, "fff", 0, ""},
+		{"old failures leave the window", config.BreakerConfig{Window: 3, MaxFailureRate: 0.5}, "f..f..f..", 0, ""},
+		{"disabled", config.BreakerConfig{MaxFailureRate: -1}, "ffffffffff", 0, ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(tt.cfg)
+			tripped := 0
+			for i, o := range tt.outcomes {
+				var err error
+				if o == 'f' {
+					err = failed
+				}
+				if b.Record(err) && tripped == 0 {
+					tripped = i + 1
+				}
+			}
+			if tripped != tt.trips {
+				t.Fatalf("tripped on outcome %d, want %d", tripped, tt.trips)
+			}
+			if tt.trips == 0 {
+				if b.Tripped() || b.Err() != nil {
+					t.Fatalf("tripped: %v", b.Err())
+				}
+				return
+			}
+
+			// Once tripped it stays tripped, with the reason and the last failure
+			if !b.Record(nil) || !b.Tripped() {
+				t.Fatal("a success reset the breaker")
+			}
+			if want := tt.reason + " (last: connect button not found)"; b.Reason() != want {
+				t.Fatalf("Reason = %q, want %q", b.Reason(), want)
+			}
+			if err := b.Err(); !errors.Is(err, ErrTripped) || !strings.Contains(err.Error(), tt.reason) {
+				t.Fatalf("Err = %v", err)
+			}
+		})
+	}
+}
+
+func TestNilBreaker(t *testing.T) {
+	var b *Breaker
+	if b.Record(errors.New("boom")) || b.Tripped() || b.Err() != nil || b.LastErr() != nil || b.Reason() != "" {
+		t.Fatal("a nil breaker tripped")
+	}
+}
@@ -0,0 +1,192 @@
+// Package budget shares one daily budget of actions between the kinds of work a run does.
+// Invites, messages, profile visits and likes each keep their own limit, but together they
+// are what a person could plausibly do in a day, so the total is capped as well and shared
+// out in priority order: the types served first hold back what their waiting work needs.
+package budget
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+)
+
+// ErrExhausted is returned once an action type has nothing left of the day's budget
+var ErrExhausted = errors.New("daily action budget used")
+
+// Store keeps what each day's budget has spent; *storage.DB satisfies it
+type Store interface {
+	ActionBudgetUsed(date string) (map[string]int, error)
+	TakeActionBudget(date, action string, limit, total int) (bool, error)
+	RefundActionBudget(date, action string) error
+}
+
+// Allocation is one action type's share of the day's budget
+type Allocation struct {
+	Action   string `json:"action"`
+	Priority int    `json:"priority"` // 1 is served first
+	Cap      int    `json:"cap"`      // the type's own daily limit
+	Used     int    `json:"used"`
+	Left     int    `json:"left"`     // what the type may still spend today
+	Reserved int    `json:"reserved"` // of Left, held back from lower priorities for waiting work
+}
+
+// Allocate shares what total leaves after used out between the action types in priority
+// order. Each type may spend up to its cap, out of what the types before it hold back; a
+// type holds back its demand, or everything it may spend when its demand isn't known.
+func Allocate(total int, priority []string, caps, demand, used map[string]int) []Allocation {
+	remain := total
+	for _, n := range used {
+		remain -= n
+	}
+	remain = max(remain, 0)
+
+	allocations := make([]Allocation, 0, len(priority))
+	for i, action := range priority {
+		a := Allocation{Action: action, Priority: i + 1, Cap: caps[action], Used: used[action]}
+		a.Left = min(max(a.Cap-a.Used, 0), remain)
+		a.Reserved = a.Left
+		if d, ok := demand[action]; ok {
+			a.Reserved = min(a.Left, max(d, 0))
+		}
+		remain -= a.Reserved
+		allocations = append(allocations, a)
+	}
+	return allocations
+}
+
+// Caps returns each action type's own daily limit on the day of now. Types the config
+// leaves off, such as visits without enrich, get nothing.
+func Caps(cfg *config.Config, now time.Time) map[string]int {
+	caps := map[string]int{
+		config.ActionFollowUp: cfg.Messaging.DailyLimit,
+		config.ActionInvite:   cfg.DailyConnectLimit(now),
+	}
+	if cfg.Enrich.Enabled {
+		caps[config.ActionVisit] = cfg.Enrich.DailyLimit
+	}
+	if cfg.Connections.PreEngage == config.PreEngageLike {
+		caps[config.ActionLike] = cfg.Engagement.DailyLikeLimit
+	}
+	return caps
+}
+
+// DailyBudget hands out the day's action budget as the run works. A nil *DailyBudget is
+// valid and never runs out.
+type DailyBudget struct {
+	total    int
+	priority []string
+	caps     map[string]int
+	demand   map[string]int // work waiting per action type, where known
+	store    Store
+	now      func() time.Time
+}
+
+// New creates the budget that action_budget configures, or nil when it is disabled
+func New(cfg *config.Config, store Store) *DailyBudget {
+	if !cfg.ActionBudget.Enabled {
+		return nil
+	}
+
+	// Types the priority leaves out are served last, in their default order
+	priority := slices.Clone(cfg.ActionBudget.Priority)
+	for _, action := range config.ActionTypes {
+		if !slices.Contains(priority, action) {
+			priority = append(priority, action)
+		}
+	}
+
+	return &DailyBudget{
+		total:    cfg.ActionBudget.DailyTotal,
+		priority: priority,
+		caps:     Caps(cfg, time.Now()),
+		demand:   make(map[string]int),
+		store:    store,
+		now:      time.Now,
+	}
+}
+
+// SetDemand sets how many actions of a type are waiting today, which is what the type
+// holds back from lower priorities. Each action taken counts against it.
+func (b *DailyBudget) SetDemand(action string, n int) {
+	if b != nil {
+		b.demand[action] = n
+	}
+}
+
+// Allocations returns today's allocation of the budget, nil when there is no budget
+func (b *DailyBudget) Allocations() ([]Allocation, error) {
+	if b == nil {
+		return nil, nil
+	}
+	used, err := b.store.ActionBudgetUsed(b.today())
+	if err != nil {
+		return nil, err
+	}
+	return Allocate(b.total, b.priority, b.caps, b.demand, used), nil
+}
+
+// Take spends one action of a type before it is done, and returns ErrExhausted when the
+// type has nothing left today
+func (b *DailyBudget) Take(action string) error {
+	if b == nil {
+		return nil
+	}
+
+	allocations, err := b.Allocations()
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(allocations, func(a Allocation) bool { return a.Action == action })
+	if i < 0 {
+		return fmt.Errorf("unknown action type %q", action)
+	}
+	a := allocations[i]
+	if a.Left <= 0 {
+		return fmt.Errorf("%w for %s (%d used of its %d, %d of %d today)", ErrExhausted, action, a.Used, a.Cap, b.spent(allocations), b.total)
+	}
+
+	taken, err := b.store.TakeActionBudget(b.today(), action, a.Used+a.Left, b.total)
+	if err != nil {
+		return err
+	}
+	if !taken {
+		return fmt.Errorf("%w for %s", ErrExhausted, action)
+	}
+	if n, ok := b.demand[action]; ok && n > 0 {
+		b.demand[action] = n - 1
+	}
+	return nil
+}
+
+// Refund gives back an action taken for work that then failed, so a run of failures
+// doesn't spend what the other types could use
+func (b *DailyBudget) Refund(action string) error {
+	if b == nil {
+		return nil
+	}
+
+	if err := b.store.RefundActionBudget(b.today(), action); err != nil {
+		return err
+	}
+	if n, ok := b.demand[action]; ok {
+		b.demand[action] = n + 1
+	}
+	return nil
+}
+
+// today returns the date the budget is kept under
+func (b *DailyBudget) today() string {
+	return b.now().Format("2006-01-02")
+}
+
+// spent sums what the allocated types have used
+func (b *DailyBudget) spent(allocations []Allocation) int {
+	n := 0
+	for _, a := range allocations {
+		n += a.Used
+	}
+	return n
+}
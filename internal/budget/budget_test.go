@@ -0,0 +1,204 @@
+package budget
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+)
+
+// memStore keeps the budget's spending in memory, enforcing the limits as the database does
+type memStore struct {
+	used map[string]map[string]int
+}
+
+func newMemStore() *memStore {
+	return &memStore{used: make(map[string]map[string]int)}
+}
+
+func (s *memStore) ActionBudgetUsed(date string) (map[string]int, error) {
+	used := make(map[string]int)
+	for action, n := range s.used[date] {
+		used[action] = n
+	}
+	return used, nil
+}
+
+func (s *memStore) TakeActionBudget(date, action string, limit, total int) (bool, error) {
+	day := s.used[date]
+	if day == nil {
+		day = make(map[string]int)
+		s.used[date] = day
+	}
+	spent := 0
+	for _, n := range day {
+		spent += n
+	}
+	if day[action] >= limit || spent >= total {
+		return false, nil
+	}
+	day[action]++
+	return true, nil
+}
+
+func (s *memStore) RefundActionBudget(date, action string) error {
+	if s.used[date][action] > 0 {
+		s.used[date][action]--
+	}
+	return nil
+}
+
+func TestAllocate(t *testing.T) {
+	priority := []string{config.ActionFollowUp, config.ActionInvite, config.ActionVisit}
+	caps := map[string]int{config.ActionFollowUp: 20, config.ActionInvite: 15, config.ActionVisit: 30}
+
+	for _, tt := range []struct {
+		name   string
+		total  int
+		demand map[string]int
+		used   map[string]int
+		left   []int // per type, in priority order
+		held   []int
+	}{
+		{
+			name:  "unknown demand holds back everything",
+			total: 30,
+			left:  []int{20, 10, 0},
+			held:  []int{20, 10, 0},
+		},
+		{
+			name:   "known demand holds back only what is waiting",
+			total:  30,
+			demand: map[string]int{config.ActionFollowUp: 4},
+			left:   []int{20, 15, 11},
+			held:   []int{4, 15, 11},
+		},
+		{
+			name:   "spending counts against the total and each cap",
+			total:  30,
+			demand: map[string]int{config.ActionFollowUp: 0, config.ActionInvite: 2},
+			used:   map[string]int{config.ActionFollowUp: 5, config.ActionInvite: 14},
+			left:   []int{11, 1, 10},
+			held:   []int{0, 1, 10},
+		},
+		{
+			name:   "a day spent in full leaves nothing",
+			total:  10,
+			demand: map[string]int{config.ActionFollowUp: 0},
+			used:   map[string]int{config.ActionVisit: 12},
+			left:   []int{0, 0, 0},
+			held:   []int{0, 0, 0},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			allocations := Allocate(tt.total, priority, caps, tt.demand, tt.used)
+			var left, held []int
+			for i, a := range allocations {
+				if a.Action != priority[i] || a.Priority != i+1 {
+					t.Fatalf("allocation %d = %s at %d, want %s at %d", i, a.Action, a.Priority, priority[i], i+1)
+				}
+				left = append(left, a.Left)
+				held = append(held, a.Reserved)
+			}
+			if !reflect.DeepEqual(left, tt.left) || !reflect.DeepEqual(held, tt.held) {
+				t.Fatalf("left %v, reserved %v; want %v, %v", left, held, tt.left, tt.held)
+			}
+		})
+	}
+}
+
+// newTestBudget returns a budget of total shared out in the default priority
+func newTestBudget(total int, caps map[string]int) *DailyBudget {
+	day := time.Date(2025, time.March, 3, 10, 0, 0, 0, time.UTC)
+	return &DailyBudget{total: total, priority: config.ActionTypes, caps: caps, demand: make(map[string]int),
+		store: newMemStore(), now: func() time.Time { return day }}
+}
+
+func TestTakeExhaustionOrder(t *testing.T) {
+	b := newTestBudget(10, map[string]int{config.ActionFollowUp: 10, config.ActionInvite: 10, config.ActionVisit: 10})
+
+	// Three follow-ups are waiting, so invites get what the other seven leave
+	b.SetDemand(config.ActionFollowUp, 3)
+	for i := 0; i < 7; i++ {
+		if err := b.Take(config.ActionInvite); err != nil {
+			t.Fatalf("invite %d: %v", i+1, err)
+		}
+	}
+	if err := b.Take(config.ActionInvite); !errors.Is(err, ErrExhausted) {
+		t.Fatalf("eighth invite = %v, want ErrExhausted while follow-ups hold the rest", err)
+	}
+	if err := b.Take(config.ActionVisit); !errors.Is(err, ErrExhausted) {
+		t.Fatalf("visit = %v, want ErrExhausted behind the invites", err)
+	}
+
+	// The follow-ups spend what they held back, and then the day is done for everyone
+	for i := 0; i < 3; i++ {
+		if err := b.Take(config.ActionFollowUp); err != nil {
+			t.Fatalf("follow-up %d: %v", i+1, err)
+		}
+	}
+	for _, action := range []string{config.ActionFollowUp, config.ActionInvite, config.ActionVisit} {
+		if err := b.Take(action); !errors.Is(err, ErrExhausted) {
+			t.Fatalf("%s after the day's total = %v, want ErrExhausted", action, err)
+		}
+	}
+
+	allocations, _ := b.Allocations()
+	if got := b.spent(allocations); got != 10 {
+		t.Fatalf("spent %d, want the total of 10", got)
+	}
+}
+
+func TestTakeCapsEachType(t *testing.T) {
+	b := newTestBudget(100, map[string]int{config.ActionFollowUp: 2, config.ActionInvite: 1})
+
+	if err := b.Take(config.ActionInvite); err != nil {
+		t.Fatalf("invite: %v", err)
+	}
+	if err := b.Take(config.ActionInvite); !errors.Is(err, ErrExhausted) {
+		t.Fatalf("invite past its cap = %v, want ErrExhausted", err)
+	}
+	if err := b.Take(config.ActionLike); !errors.Is(err, ErrExhausted) {
+		t.Fatalf("like without a cap = %v, want ErrExhausted", err)
+	}
+	if err := b.Take("poke"); err == nil || errors.Is(err, ErrExhausted) {
+		t.Fatalf("unknown action = %v, want an error other than ErrExhausted", err)
+	}
+
+	var none *DailyBudget
+	if err := none.Take(config.ActionInvite); err != nil {
+		t.Fatalf("nil budget = %v, want nil", err)
+	}
+}
+
+func TestRefundGivesTheActionBack(t *testing.T) {
+	b := newTestBudget(2, map[string]int{config.ActionInvite: 5})
+
+	for i := 0; i < 2; i++ {
+		if err := b.Take(config.ActionInvite); err != nil {
+			t.Fatalf("invite %d: %v", i+1, err)
+		}
+	}
+	if err := b.Take(config.ActionInvite); !errors.Is(err, ErrExhausted) {
+		t.Fatalf("third invite = %v, want ErrExhausted", err)
+	}
+
+	// An invite that failed gives its action back to the day
+	if err := b.Refund(config.ActionInvite); err != nil {
+		t.Fatalf("Refund: %v", err)
+	}
+	allocations, _ := b.Allocations()
+	if got := b.spent(allocations); got != 1 {
+		t.Fatalf("spent %d after the refund, want 1", got)
+	}
+	if err := b.Take(config.ActionInvite); err != nil {
+		t.Fatalf("invite after the refund: %v", err)
+	}
+
+	var none *DailyBudget
+	if err := none.Refund(config.ActionInvite); err != nil {
+		t.Fatalf("nil budget = %v, want nil", err)
+	}
+}
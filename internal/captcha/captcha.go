@@ -0,0 +1,206 @@
+// Package captcha talks to an external CAPTCHA solving service.
+//
+// The service contract is a small generic HTTP API:
+//
+//	POST {api_url}/tasks       {"type", "site_key", "page_url", "image"} -> {"id"}
+//	GET  {api_url}/tasks/{id}  -> {"status": "pending|ready|failed", "token", "error"}
+//
+// Requests carry the API key as a bearer token.
+package captcha
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// Challenge types the service can solve
+const (
+	TypeRecaptchaV2 = "recaptcha_v2"
+	TypeImage       = "image"
+)
+
+// Solve outcomes recorded for every invocation
+const (
+	OutcomeSolved          = "solved"
+	OutcomeFailed          = "failed"
+	OutcomeTimeout         = "timeout"
+	OutcomeBudgetExhausted = "budget_exhausted"
+)
+
+// ErrBudgetExhausted is returned when another solve would exceed the daily cost cap
+var ErrBudgetExhausted = errors.New("daily captcha budget exhausted")
+
+// Task describes a challenge to solve: a site key and page URL, or an image
+type Task struct {
+	Type    string `json:"type"`
+	SiteKey string `json:"site_key,omitempty"`
+	PageURL string `json:"page_url,omitempty"`
+	Image   []byte `json:"image,omitempty"` // encoded as base64
+}
+
+// taskStatus is the service's answer when polling a task
+type taskStatus struct {
+	Status string `json:"status"`
+	Token  string `json:"token"`
+	Error  string `json:"error"`
+}
+
+// Solver submits challenges to the service within a timeout and a daily cost cap
+type Solver struct {
+	cfg    config.CaptchaConfig
+	db     *storage.DB
+	client *http.Client
+}
+
+// NewSolver creates a solver, or returns nil when CAPTCHA solving is disabled
+func NewSolver(cfg config.CaptchaConfig, db *storage.DB) *Solver {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	return &Solver{
+		cfg:    cfg,
+		db:     db,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Solve submits task and waits for its token. Every call is recorded with its outcome and latency.
+func (s *Solver) Solve(task Task) (string, error) {
+	started := time.Now()
+
+	spent, err := s.db.GetCaptchaCostByDate(started)
+	if err != nil {
+		return "", fmt.Errorf("failed to get captcha spend: %w", err)
+	}
+	if spent+s.cfg.CostPerSolve > s.cfg.MaxDailyCost {
+		err := fmt.Errorf("%w (%.3f of %.3f spent)", ErrBudgetExhausted, spent, s.cfg.MaxDailyCost)
+		s.record(task.Type, OutcomeBudgetExhausted, started, 0, err)
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(s.cfg.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	// Calls that reached the service count against the cap whatever their outcome
+	token, err := s.solve(ctx, task)
+	switch {
+	case err == nil:
+		s.record(task.Type, OutcomeSolved, started, s.cfg.CostPerSolve, nil)
+	case errors.Is(err, context.DeadlineExceeded):
+		s.record(task.Type, OutcomeTimeout, started, s.cfg.CostPerSolve, err)
+	default:
+		s.record(task.Type, OutcomeFailed, started, s.cfg.CostPerSolve, err)
+	}
+
+	return token, err
+}
+
+// solve submits the task and polls until it is ready, fails or ctx ends
+func (s *Solver) solve(ctx context.Context, task Task) (string, error) {
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := s.do(ctx, http.MethodPost, "/tasks", task, &created); err != nil {
+		return "", fmt.Errorf("failed to submit captcha task: %w", err)
+	}
+	if created.ID == "" {
+		return "", fmt.Errorf("captcha service returned no task id")
+	}
+
+	poll := time.Duration(s.cfg.PollIntervalSeconds) * time.Second
+	for {
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("captcha task %s not solved in time: %w", created.ID, ctx.Err())
+		case <-time.After(poll):
+		}
+
+		var status taskStatus
+		if err := s.do(ctx, http.MethodGet, "/tasks/"+url.PathEscape(created.ID), nil, &status); err != nil {
+			return "", fmt.Errorf("failed to poll captcha task: %w", err)
+		}
+
+		switch status.Status {
+		case "ready":
+			if status.Token == "" {
+				return "", fmt.Errorf("captcha task %s is ready without a token", created.ID)
+			}
+			return status.Token, nil
+		case "failed":
+			return "", fmt.Errorf("captcha task %s failed: %s", created.ID, status.Error)
+		}
+	}
+}
+
+// do sends a JSON request to the service and decodes the JSON response into out
+func (s *Solver) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(s.cfg.APIURL, "/")+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.cfg.APIKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("captcha service returned %s", resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode captcha service response: %w", err)
+	}
+	return nil
+}
+
+// record logs the invocation and stores it for the daily cost cap
+func (s *Solver) record(challengeType, outcome string, started time.Time, cost float64, err error) {
+	latency := time.Since(started)
+
+	solve := &storage.CaptchaSolve{
+		ChallengeType: challengeType,
+		Outcome:       outcome,
+		LatencyMS:     latency.Milliseconds(),
+		Cost:          cost,
+		CreatedAt:     time.Now(),
+	}
+	if err != nil {
+		solve.Error = err.Error()
+		logger.Warnf("CAPTCHA solver %s after %s: %v", outcome, latency.Round(time.Millisecond), err)
+	} else {
+		logger.Infof("CAPTCHA solver %s after %s", outcome, latency.Round(time.Millisecond))
+	}
+
+	if dbErr := s.db.SaveCaptchaSolve(solve); dbErr != nil {
+		logger.Errorf("Failed to record captcha solve: %v", dbErr)
+	}
+	s.db.LogActivity("captcha_solve", fmt.Sprintf("%s %s in %dms", challengeType, outcome, solve.LatencyMS))
+}
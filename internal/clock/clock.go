@@ -0,0 +1,93 @@
+// Package clock lets scheduling and timing code read the time and wait through an
+// interface, so a fake clock can stand in for the real one.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the time and waits. Real uses the system clock; Fake stands in for it
+// so scheduling and timing logic can be driven through days without waiting.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	After(d time.Duration) <-chan time.Time
+}
+
+// Real is the system clock
+type Real struct{}
+
+// Now returns the current time
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// Sleep pauses for d
+func (Real) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// After returns a channel that receives the time once d has passed
+func (Real) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Fake is a clock that only moves when told to. Waiting on it moves it forward by the
+// time waited, so code that sleeps returns at once with the clock where it would be.
+type Fake struct {
+	mu    sync.Mutex
+	now   time.Time
+	slept []time.Duration
+}
+
+// NewFake returns a fake clock set to now
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now returns the fake time
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Set moves the clock to t
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = t
+}
+
+// Advance moves the clock forward by d
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+// Sleep moves the clock forward by d and records the wait
+func (f *Fake) Sleep(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if d > 0 {
+		f.now = f.now.Add(d)
+	}
+	f.slept = append(f.slept, d)
+}
+
+// After sleeps for d and returns a channel holding the new time
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.Sleep(d)
+	ch := make(chan time.Time, 1)
+	ch <- f.Now()
+	return ch
+}
+
+// Slept returns the waits made on the clock so far, in order
+func (f *Fake) Slept() []time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]time.Duration(nil), f.slept...)
+}
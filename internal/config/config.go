@@ -3,54 +3,469 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/Tanukumar01/linkedin-automation/configs"
+	"github.com/Tanukumar01/linkedin-automation/internal/render"
 )
 
 // Config represents the application configuration
 type Config struct {
-	Search      SearchConfig      `yaml:"search"`
-	Connections ConnectionsConfig `yaml:"connections"`
-	Messaging   MessagingConfig   `yaml:"messaging"`
-	Stealth     StealthConfig     `yaml:"stealth"`
-	Browser     BrowserConfig     `yaml:"browser"`
-	Logging     LoggingConfig     `yaml:"logging"`
+	Search        SearchConfig        `yaml:"search"`
+	Connections   ConnectionsConfig   `yaml:"connections"`
+	Messaging     MessagingConfig     `yaml:"messaging"`
+	Invites       InvitesConfig       `yaml:"invites"`
+	Engagement    EngagementConfig    `yaml:"engagement"`
+	Enrich        EnrichConfig        `yaml:"enrich"`
+	ActionBudget  ActionBudgetConfig  `yaml:"action_budget"`
+	Prune         PruneConfig         `yaml:"prune"`
+	Queue         QueueConfig         `yaml:"queue"`
+	Diagnostics   DiagnosticsConfig   `yaml:"diagnostics"`
+	Database      DatabaseConfig      `yaml:"database"`
+	Campaigns     []CampaignConfig    `yaml:"campaigns"`
+	Monitors      []MonitorConfig     `yaml:"monitors"`
+	Planner       PlannerConfig       `yaml:"planner"`
+	Session       SessionConfig       `yaml:"session"`
+	Stealth       StealthConfig       `yaml:"stealth"`
+	Browser       BrowserConfig       `yaml:"browser"`
+	Logging       LoggingConfig       `yaml:"logging"`
+	Reporting     ReportingConfig     `yaml:"reporting"`
+	Notifications NotificationsConfig `yaml:"notifications"`
+	Debug         DebugConfig         `yaml:"debug"`
+	Captcha       CaptchaConfig       `yaml:"captcha"`
+	Safety        SafetyConfig        `yaml:"safety"`
+	LinkedIn      LinkedInConfig      `yaml:"linkedin"`
+	Locale        string              `yaml:"locale"` // BCP 47 locale for template helpers, e.g. en-US or de-DE
+}
+
+// LinkedInConfig describes how the account's LinkedIn UI is shown
+type LinkedInConfig struct {
+	UILanguage string `yaml:"ui_language"` // language button texts are matched in; auto reads it from the page after login
 }
 
+// UILanguageAuto detects the UI language from the page
+const UILanguageAuto = "auto"
+
+// UILanguages lists the valid linkedin.ui_language values
+var UILanguages = []string{UILanguageAuto, "en", "de", "fr", "es", "pt"}
+
+// How search results are read, set with search.parser
+const (
+	SearchParserAuto = "auto" // the embedded JSON where a page has it, else the result cards
+	SearchParserJSON = "json" // only the JSON LinkedIn embeds in the page
+	SearchParserCSS  = "css"  // only the result cards
+)
+
+// SearchParsers lists the valid search.parser values
+var SearchParsers = []string{SearchParserAuto, SearchParserJSON, SearchParserCSS}
+
 // SearchConfig contains search-related settings
 type SearchConfig struct {
-	MaxResults         int      `yaml:"max_results"`
-	PaginationDelayMin int      `yaml:"pagination_delay_min"`
-	PaginationDelayMax int      `yaml:"pagination_delay_max"`
-	Filters            Filters  `yaml:"filters"`
+	MaxResults          int     `yaml:"max_results"`
+	PaginationDelayMin  int     `yaml:"pagination_delay_min"`
+	PaginationDelayMax  int     `yaml:"pagination_delay_max"`
+	StopAfterEmptyPages int     `yaml:"stop_after_empty_pages"` // consecutive pages without new profiles; negative never stops early
+	Filters             Filters `yaml:"filters"`
+
+	Provider       string               `yaml:"provider"` // standard or sales_navigator
+	Parser         string               `yaml:"parser"`   // auto, json or css
+	SalesNavigator SalesNavigatorConfig `yaml:"sales_navigator"`
+	Sources        []SourceConfig       `yaml:"sources"` // groups and events to harvest besides searching
 }
 
+// SourceConfig is a LinkedIn group, event or company whose members or employees are harvested as prospects
+type SourceConfig struct {
+	URL string `yaml:"url"` // a /groups/<id>, /events/<id> or /company/<name> URL
+	Max int    `yaml:"max"` // members read per run; defaults to the campaign's max_results
+}
+
+// IsGroup reports whether the source is a group rather than an event
+func (s SourceConfig) IsGroup() bool {
+	return strings.Contains(s.URL, "/groups/")
+}
+
+// IsCompany reports whether the source is a company page, whose employees are harvested
+func (s SourceConfig) IsCompany() bool {
+	return strings.Contains(s.URL, "/company/")
+}
+
+// SalesNavigatorConfig contains settings of the Sales Navigator search provider
+type SalesNavigatorConfig struct {
+	SavedSearchID string   `yaml:"saved_search_id"` // run a saved search instead of the filters
+	Spotlights    []string `yaml:"spotlights"`      // changed_jobs, posted_on_linkedin
+}
+
+// SalesNavigatorSpotlights are the spotlight filters search.sales_navigator.spotlights accepts
+var SalesNavigatorSpotlights = []string{"changed_jobs", "posted_on_linkedin"}
+
 // Filters contains search filter criteria
 type Filters struct {
 	JobTitles []string `yaml:"job_titles"`
 	Companies []string `yaml:"companies"`
 	Locations []string `yaml:"locations"`
 	Keywords  []string `yaml:"keywords"`
+
+	// Checked against each result before it is saved. Patterns match case-insensitively
+	// anywhere in the field, with * and ? as wildcards, or as a regular expression after re:
+	TitleMustInclude    []string `yaml:"title_must_include"`
+	TitleMustExclude    []string `yaml:"title_must_exclude"`
+	LocationMustInclude []string `yaml:"location_must_include"`
+	CompanyMustExclude  []string `yaml:"company_must_exclude"`
+
+	// Network facets of the standard search: degrees of connection (NetworkDepths), and
+	// members whose connections to search, by member ID, profile URN or profile URL
+	NetworkDepth []string `yaml:"network_depth"`
+	ConnectionOf []string `yaml:"connection_of"`
+}
+
+// Network depth facets, as LinkedIn names them in search URLs
+const (
+	NetworkFirst  = "F" // 1st degree: already connected
+	NetworkSecond = "S" // 2nd degree
+	NetworkThird  = "O" // 3rd degree and beyond
+)
+
+// NetworkDepths lists the valid filters.network_depth values, nearest first
+var NetworkDepths = []string{NetworkFirst, NetworkSecond, NetworkThird}
+
+// MaxDegree returns the furthest degree of connection the network depth filter lets
+// through, 0 when it doesn't limit it
+func (f Filters) MaxDegree() int {
+	degree := 0
+	for _, depth := range f.NetworkDepth {
+		if i := slices.Index(NetworkDepths, depth); i+1 > degree {
+			degree = i + 1
+		}
+	}
+	if degree == len(NetworkDepths) {
+		return 0
+	}
+	return degree
 }
 
 // ConnectionsConfig contains connection request settings
 type ConnectionsConfig struct {
-	DailyLimit                  int      `yaml:"daily_limit"`
-	HourlyLimit                 int      `yaml:"hourly_limit"`
-	NoteTemplates               []string `yaml:"note_templates"`
-	NoteCharacterLimit          int      `yaml:"note_character_limit"`
-	CooldownBetweenRequestsMin  int      `yaml:"cooldown_between_requests_min"`
-	CooldownBetweenRequestsMax  int      `yaml:"cooldown_between_requests_max"`
+	DailyLimit                 int        `yaml:"daily_limit"`
+	WeeklyLimit                int        `yaml:"weekly_limit"` // over the last 7 days; 0 for no weekly cap
+	HourlyLimit                int        `yaml:"hourly_limit"`
+	NoteTemplates              []Template `yaml:"note_templates"`
+	TemplateSelection          string     `yaml:"template_selection"` // uniform or weighted
+	NoteCharacterLimit         int        `yaml:"note_character_limit"`
+	CooldownBetweenRequestsMin int        `yaml:"cooldown_between_requests_min"`
+	CooldownBetweenRequestsMax int        `yaml:"cooldown_between_requests_max"`
+	NameResolution             string     `yaml:"name_resolution"` // rescrape_then_fallback, fallback, skip
+
+	// Pass over a share of eligible prospects, each at most MaxSkips times, so selection isn't fully predictable
+	SkipProbability float64 `yaml:"skip_probability"`
+	MaxSkips        int     `yaml:"max_skips"`
+
+	// Visit a share of prospects and leave without connecting; they come back after the cool-down
+	OrganicSkipProbability  float64 `yaml:"organic_skip_probability"` // 0 disables, as does a negative value
+	OrganicSkipCooldownDays int     `yaml:"organic_skip_cooldown_days"`
+
+	Prioritization PrioritizationConfig `yaml:"prioritization"`
+
+	TargetOrder      string `yaml:"target_order"`        // newest, oldest, random or priority
+	MaxResultAgeDays int    `yaml:"max_result_age_days"` // search results found longer ago are left out; 0 keeps all
+
+	PreEngage string `yaml:"pre_engage"` // empty, or like to like a recent post before inviting
+
+	// Which requests carry a note, and how many notes a calendar month may use
+	NoteMode              string `yaml:"note_mode"`                // always, never or priority
+	MonthlyNoteBudget     int    `yaml:"monthly_note_budget"`      // 0 for no budget
+	NotePriorityMinMutual int    `yaml:"note_priority_min_mutual"` // mutual connections that make a prospect a priority
+
+	// How long one profile may take to invite or message before it is abandoned; negative disables
+	PerProfileTimeoutSeconds int `yaml:"per_profile_timeout_seconds"`
+
+	// Days after an invite was withdrawn, or since it was sent while it sits pending, before
+	// the profile may be invited again; 0 never invites a profile twice
+	ReinviteCooldownDays int `yaml:"reinvite_cooldown_days"`
+}
+
+// ProfileTimeout returns the time budget of one profile, 0 when there is none
+func (c *ConnectionsConfig) ProfileTimeout() time.Duration {
+	if c.PerProfileTimeoutSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(c.PerProfileTimeoutSeconds) * time.Second
+}
+
+// Orders connections.target_order can take
+const (
+	TargetOrderNewest   = "newest"   // most recently found first
+	TargetOrderOldest   = "oldest"   // in the order they were found
+	TargetOrderRandom   = "random"   // shuffled
+	TargetOrderPriority = "priority" // most mutual connections first
+)
+
+// TargetOrders lists the valid connections.target_order values
+var TargetOrders = []string{TargetOrderNewest, TargetOrderOldest, TargetOrderRandom, TargetOrderPriority}
+
+// PrioritizationConfig decides which uncontacted prospects are contacted first
+type PrioritizationConfig struct {
+	Order          string `yaml:"order"`             // deprecated: found or mutual_connections, read when target_order is unset
+	SkipOpenToWork bool   `yaml:"skip_open_to_work"` // leave out profiles showing the "Open to work" badge
+
+	BoostTags []string `yaml:"boost_tags"` // profiles tagged with any of these by hand are invited first
 }
 
 // MessagingConfig contains messaging settings
 type MessagingConfig struct {
-	DailyLimit                 int      `yaml:"daily_limit"`
-	HourlyLimit                int      `yaml:"hourly_limit"`
-	Templates                  []string `yaml:"templates"`
-	CooldownBetweenMessagesMin int      `yaml:"cooldown_between_messages_min"`
-	CooldownBetweenMessagesMax int      `yaml:"cooldown_between_messages_max"`
+	DailyLimit                 int                     `yaml:"daily_limit"`
+	HourlyLimit                int                     `yaml:"hourly_limit"`
+	Templates                  []Template              `yaml:"templates"`
+	WelcomeTemplates           []Template              `yaml:"welcome_templates"`
+	TemplateSelection          string                  `yaml:"template_selection"` // uniform or weighted
+	AcceptanceMessage          AcceptanceMessageConfig `yaml:"acceptance_message"`
+	CooldownBetweenMessagesMin int                     `yaml:"cooldown_between_messages_min"`
+	CooldownBetweenMessagesMax int                     `yaml:"cooldown_between_messages_max"`
+
+	Sequences []SequenceConfig `yaml:"sequences"` // drip sequences accepted connections are enrolled in
+	InMail    InMailConfig     `yaml:"inmail"`
+}
+
+// InMailConfig controls InMail to prospects who can't be invited, on Premium or
+// Sales Navigator accounts
+type InMailConfig struct {
+	Enabled    bool       `yaml:"enabled"`
+	DailyLimit int        `yaml:"daily_limit"`
+	Subjects   []Template `yaml:"subjects"`
+	Templates  []Template `yaml:"templates"` // bodies; {{subject}} is the subject line picked
+}
+
+// SequenceConfig is a series of messages sent to an accepted connection one step at a time
+type SequenceConfig struct {
+	Name     string               `yaml:"name"`
+	Campaign string               `yaml:"campaign"` // campaign whose connections are enrolled; empty for any campaign
+	Steps    []SequenceStepConfig `yaml:"steps"`
+}
+
+// SequenceStepConfig is one message of a sequence
+type SequenceStepConfig struct {
+	DelayDays int        `yaml:"delay_days"` // days after the previous step, or after acceptance for the first
+	Templates []Template `yaml:"templates"`
+}
+
+// AcceptanceMessageConfig controls the message sent shortly after a request is accepted
+type AcceptanceMessageConfig struct {
+	Enabled       bool `yaml:"enabled"`
+	DelayMinHours int  `yaml:"delay_min_hours"`
+	DelayMaxHours int  `yaml:"delay_max_hours"`
+}
+
+// Template is a note or message template.
+// In YAML it is either a plain string or a mapping with text and weight.
+type Template struct {
+	Text   string  `yaml:"text"`
+	Weight float64 `yaml:"weight"`
+}
+
+// UnmarshalYAML accepts both the plain string and the mapping form
+func (t *Template) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		t.Text = value.Value
+		t.Weight = 1
+		return nil
+	}
+
+	type plain Template
+	var p plain
+	if err := value.Decode(&p); err != nil {
+		return err
+	}
+
+	*t = Template(p)
+	if t.Weight == 0 {
+		t.Weight = 1
+	}
+
+	return nil
+}
+
+// CampaignConfig describes one target audience with its own filters, templates and budget
+type CampaignConfig struct {
+	Name          string     `yaml:"name"`
+	MaxResults    int        `yaml:"max_results"`
+	Filters       Filters    `yaml:"filters"`
+	NoteTemplates []Template `yaml:"note_templates"`
+	BudgetShare   float64    `yaml:"budget_share"`
+	Locale        string     `yaml:"locale"`         // defaults to the top-level locale
+	PriorityNotes bool       `yaml:"priority_notes"` // with note_mode priority, every prospect gets a note
+
+	Sources []SourceConfig `yaml:"sources"`
+}
+
+// MonitorConfig is a saved search the monitor command runs to report the profiles that
+// appeared since its last run; it never contacts anyone
+type MonitorConfig struct {
+	Name     string  `yaml:"name"`
+	Filters  Filters `yaml:"filters"`
+	MaxPages int     `yaml:"max_pages"` // result pages read each run, default 3
+}
+
+// sourcesWithDefaults copies sources, capping those without a max at maxResults
+func sourcesWithDefaults(sources []SourceConfig, maxResults int) []SourceConfig {
+	out := make([]SourceConfig, len(sources))
+	for i, source := range sources {
+		if source.Max == 0 {
+			source.Max = maxResults
+		}
+		out[i] = source
+	}
+	return out
+}
+
+// DefaultCampaign is the campaign name used when no campaigns are configured
+const DefaultCampaign = "default"
+
+// EffectiveCampaigns returns the configured campaigns with defaults filled in from the
+// top-level search and connections settings, or a single default campaign if none are set
+func (c *Config) EffectiveCampaigns() []CampaignConfig {
+	if len(c.Campaigns) == 0 {
+		return []CampaignConfig{{
+			Name:          DefaultCampaign,
+			MaxResults:    c.Search.MaxResults,
+			Filters:       c.Search.Filters,
+			NoteTemplates: c.Connections.NoteTemplates,
+			BudgetShare:   1,
+			Locale:        c.Locale,
+			Sources:       sourcesWithDefaults(c.Search.Sources, c.Search.MaxResults),
+		}}
+	}
+
+	campaigns := make([]CampaignConfig, len(c.Campaigns))
+	for i, campaign := range c.Campaigns {
+		if campaign.MaxResults == 0 {
+			campaign.MaxResults = c.Search.MaxResults
+		}
+		if len(campaign.NoteTemplates) == 0 {
+			campaign.NoteTemplates = c.Connections.NoteTemplates
+		}
+		if campaign.Locale == "" {
+			campaign.Locale = c.Locale
+		}
+		campaign.Sources = sourcesWithDefaults(campaign.Sources, campaign.MaxResults)
+		campaigns[i] = campaign
+	}
+
+	return campaigns
+}
+
+// CampaignBudgets splits a day's connect budget across campaigns by budget share.
+// Rounding leftovers go to the campaigns with the largest shares first.
+func (c *Config) CampaignBudgets(campaigns []CampaignConfig, total int) map[string]int {
+	budgets := make(map[string]int)
+
+	shares := 0.0
+	for _, campaign := range campaigns {
+		shares += campaign.BudgetShare
+	}
+	if shares <= 0 {
+		return budgets
+	}
+
+	assigned := 0
+	for _, campaign := range campaigns {
+		budget := int(float64(total) * campaign.BudgetShare / shares)
+		budgets[campaign.Name] = budget
+		assigned += budget
+	}
+
+	order := make([]CampaignConfig, len(campaigns))
+	copy(order, campaigns)
+	sort.SliceStable(order, func(i, j int) bool { return order[i].BudgetShare > order[j].BudgetShare })
+
+	for i := 0; assigned < total; i = (i + 1) % len(order) {
+		if order[i].BudgetShare > 0 {
+			budgets[order[i].Name]++
+			assigned++
+		}
+	}
+
+	return budgets
+}
+
+// InvitesConfig contains settings for processing incoming connection requests
+type InvitesConfig struct {
+	Enabled              bool     `yaml:"enabled"`
+	DailyAcceptLimit     int      `yaml:"daily_accept_limit"`
+	TitlePatterns        []string `yaml:"title_patterns"`
+	MinMutualConnections int      `yaml:"min_mutual_connections"`
+	Exclude              []string `yaml:"exclude"`
+	IgnoreSpam           bool     `yaml:"ignore_spam"`
+	SpamKeywords         []string `yaml:"spam_keywords"`
+}
+
+// PreEngageLike likes a prospect's most recent post before inviting them
+const PreEngageLike = "like"
+
+// Note modes: which connection requests carry a note
+const (
+	NoteModeAlways   = "always"
+	NoteModeNever    = "never"
+	NoteModePriority = "priority" // prospects with enough mutual connections, or from priority_notes campaigns
+)
+
+// EngagementConfig contains settings for engaging with prospects before inviting them
+type EngagementConfig struct {
+	DailyLikeLimit    int `yaml:"daily_like_limit"`
+	HoursBeforeInvite int `yaml:"hours_before_invite"` // how long a like settles before the invite
+}
+
+// EnrichConfig contains settings for scraping full profile details before prospects are contacted
+type EnrichConfig struct {
+	Enabled    bool `yaml:"enabled"`
+	DailyLimit int  `yaml:"daily_limit"`
+	StaleDays  int  `yaml:"stale_days"` // profiles scraped longer ago are scraped again
+}
+
+// ActionBudgetConfig caps the actions of every kind a day together, on top of each kind's own
+// limit, sharing the total out in priority order
+type ActionBudgetConfig struct {
+	Enabled    bool     `yaml:"enabled"`
+	DailyTotal int      `yaml:"daily_total"`
+	Priority   []string `yaml:"priority"` // action types served first; types left out come last
+}
+
+// Action types the daily action budget is shared between
+const (
+	ActionFollowUp = "follow_up" // messages: follow-ups, sequence steps, welcomes and InMails
+	ActionInvite   = "invite"    // connection requests
+	ActionVisit    = "visit"     // profiles read by enrich before inviting
+	ActionLike     = "like"      // posts liked to warm prospects up
+)
+
+// ActionTypes lists the action types in their default priority
+var ActionTypes = []string{ActionFollowUp, ActionInvite, ActionVisit, ActionLike}
+
+// PruneConfig contains settings of the prune command, which removes connections and unfollows profiles
+type PruneConfig struct {
+	MaxPerRun int `yaml:"max_per_run"` // profiles one prune run acts on at most
+}
+
+// QueueConfig contains settings of the outreach queue between discovery and outreach
+type QueueConfig struct {
+	MaxAttempts       int `yaml:"max_attempts"`        // attempts at a prospect before it is dead-lettered
+	RetryDelayMinutes int `yaml:"retry_delay_minutes"` // before the first retry; it doubles with each further one
+}
+
+// DiagnosticsConfig contains settings for recording the browser's failed API calls and console errors
+type DiagnosticsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	MaxRows int  `yaml:"max_rows"` // the latest rows kept; older ones are dropped
+}
+
+// DatabaseConfig contains the retention windows of db prune, in days; negative keeps rows forever
+type DatabaseConfig struct {
+	SearchResultRetentionDays int `yaml:"search_result_retention_days"` // search results never contacted
+	ActivityLogRetentionDays  int `yaml:"activity_log_retention_days"`
+	DiagnosticRetentionDays   int `yaml:"diagnostic_retention_days"`
 }
 
 // StealthConfig contains anti-detection settings
@@ -60,13 +475,29 @@ type StealthConfig struct {
 	Typing     TypingConfig     `yaml:"typing"`
 	Scrolling  ScrollingConfig  `yaml:"scrolling"`
 	Scheduling SchedulingConfig `yaml:"scheduling"`
+	Humanize   HumanizeConfig   `yaml:"humanize"`
+	Fatigue    FatigueConfig    `yaml:"fatigue"`
+	Persona    PersonaConfig    `yaml:"persona"`
+
+	// How thoroughly a prospect's profile is read before connecting: none, light or full
+	ProfileReading string `yaml:"profile_reading"`
 }
 
+// Depths stealth.profile_reading can take
+const (
+	ProfileReadingNone  = "none"  // a short scroll and on to Connect
+	ProfileReadingLight = "light" // skim the About section
+	ProfileReadingFull  = "full"  // read About and Experience, now and then hovering over a position
+)
+
+// ProfileReadings lists the valid stealth.profile_reading values
+var ProfileReadings = []string{ProfileReadingNone, ProfileReadingLight, ProfileReadingFull}
+
 // MouseConfig contains mouse movement settings
 type MouseConfig struct {
-	BezierPoints              int     `yaml:"bezier_points"`
-	SpeedVariation            float64 `yaml:"speed_variation"`
-	OvershootProbability      float64 `yaml:"overshoot_probability"`
+	BezierPoints               int     `yaml:"bezier_points"`
+	SpeedVariation             float64 `yaml:"speed_variation"`
+	OvershootProbability       float64 `yaml:"overshoot_probability"`
 	MicroCorrectionProbability float64 `yaml:"micro_correction_probability"`
 }
 
@@ -85,6 +516,39 @@ type TypingConfig struct {
 	WPMMax           int     `yaml:"wpm_max"`
 	TypoProbability  float64 `yaml:"typo_probability"`
 	PauseProbability float64 `yaml:"pause_probability"`
+
+	Typos TypoConfig `yaml:"typos"`
+}
+
+// TypoConfig weighs the kinds of typo made and how soon they are corrected
+type TypoConfig struct {
+	Adjacent       float64 `yaml:"adjacent"`        // a neighboring key instead of the intended one
+	Doubled        float64 `yaml:"doubled"`         // the intended key twice
+	Transposed     float64 `yaml:"transposed"`      // two keys in swapped order
+	LateCorrection float64 `yaml:"late_correction"` // chance a typo is noticed only a few characters later
+}
+
+// HumanizeConfig contains settings for idle browsing between connection requests
+type HumanizeConfig struct {
+	Enabled         bool    `yaml:"enabled"`
+	Probability     float64 `yaml:"probability"` // chance of an idle action after each connection request
+	FeedMinSeconds  int     `yaml:"feed_min_seconds"`
+	FeedMaxSeconds  int     `yaml:"feed_max_seconds"`
+	AllowLikes      bool    `yaml:"allow_likes"`      // idle actions never react to posts unless set
+	LikeProbability float64 `yaml:"like_probability"` // chance of liking a post while browsing the feed
+}
+
+// FatigueConfig contains settings for slowing down as a session wears on
+type FatigueConfig struct {
+	Enabled     bool    `yaml:"enabled"`
+	MaxSlowdown float64 `yaml:"max_slowdown"` // how much slower delays and typing get at most, e.g. 0.3 for 30%; typos rise alike
+	RampMinutes int     `yaml:"ramp_minutes"` // active minutes until fully tired; a break starts over
+}
+
+// PersonaConfig contains settings for the pace sampled for each session
+type PersonaConfig struct {
+	Enabled bool    `yaml:"enabled"`
+	Spread  float64 `yaml:"spread"` // how far a persona scales the configured timing, typing and scrolling, e.g. 0.3 for 0.7 to 1.3 times
 }
 
 // ScrollingConfig contains scrolling behavior settings
@@ -104,22 +568,240 @@ type SchedulingConfig struct {
 	BreakDurationMin   int     `yaml:"break_duration_min"`
 	BreakDurationMax   int     `yaml:"break_duration_max"`
 	BreakProbability   float64 `yaml:"break_probability"`
+	SpreadActions      bool    `yaml:"spread_actions"` // plan connection requests across the day instead of sending them back-to-back
+
+	WeekdayOverrides map[string]WeekdayOverride `yaml:"weekday_overrides"` // keyed by weekday name
+
+	// Active time caps; waits for breaks and planned slots don't count. 0 disables a cap.
+	MaxSessionMinutes     int `yaml:"max_session_minutes"`
+	MaxDailyActiveMinutes int `yaml:"max_daily_active_minutes"`
+}
+
+// WeekdayOverride changes the connection limit or activity of one weekday
+type WeekdayOverride struct {
+	DailyLimit int   `yaml:"daily_limit"` // 0 keeps connections.daily_limit
+	Active     *bool `yaml:"active"`      // unset keeps the weekday/weekend rule
+}
+
+// weekdayNames maps accepted weekday names to weekdays
+var weekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// weekdayOverride returns the override configured for day, if any
+func (s *SchedulingConfig) weekdayOverride(day time.Weekday) (WeekdayOverride, bool) {
+	for name, override := range s.WeekdayOverrides {
+		if weekdayNames[strings.ToLower(name)] == day {
+			return override, true
+		}
+	}
+	return WeekdayOverride{}, false
+}
+
+// ActiveDays returns the weekdays whose activity is set explicitly
+func (s *SchedulingConfig) ActiveDays() map[time.Weekday]bool {
+	days := make(map[time.Weekday]bool)
+	for name, override := range s.WeekdayOverrides {
+		if override.Active != nil {
+			days[weekdayNames[strings.ToLower(name)]] = *override.Active
+		}
+	}
+	return days
+}
+
+// DailyConnectLimit returns the connection limit for the day t falls on in the scheduling timezone
+func (c *Config) DailyConnectLimit(t time.Time) int {
+	if loc, err := time.LoadLocation(c.Stealth.Scheduling.Timezone); err == nil {
+		t = t.In(loc)
+	}
+
+	if override, ok := c.Stealth.Scheduling.weekdayOverride(t.Weekday()); ok && override.DailyLimit > 0 {
+		return override.DailyLimit
+	}
+	return c.Connections.DailyLimit
 }
 
 // BrowserConfig contains browser settings
 type BrowserConfig struct {
-	Headless        bool     `yaml:"headless"`
-	UserAgents      []string `yaml:"user_agents"`
-	ViewportWidths  []int    `yaml:"viewport_widths"`
-	ViewportHeights []int    `yaml:"viewport_heights"`
-	TimeoutSeconds  int      `yaml:"timeout_seconds"`
+	Headless       bool             `yaml:"headless"`
+	UserAgents     []string         `yaml:"user_agents"`
+	Viewports      []ViewportConfig `yaml:"viewports"` // an account keeps the one it was first given
+	TimeoutSeconds int              `yaml:"timeout_seconds"`
+
+	Hardware []HardwareConfig `yaml:"hardware"` // GPU and machine profiles; none leaves the real values
+
+	ExtraArgs []string `yaml:"extra_args"` // more Chrome flags, --flag or --flag=value
+
+	BlockResources []string      `yaml:"block_resources"` // image, font, media and/or analytics
+	AllowHosts     []string      `yaml:"allow_hosts"`     // never blocked, e.g. static.licdn.com
+	Network        NetworkConfig `yaml:"network"`
+
+	DailyNavigationLimit int `yaml:"daily_navigation_limit"` // page loads per day; defaults to the safety ceiling
+}
+
+// ViewportConfig is a screen a persona can present; its size and scale are picked together
+type ViewportConfig struct {
+	Width             int     `yaml:"width"`
+	Height            int     `yaml:"height"`
+	DeviceScaleFactor float64 `yaml:"device_scale_factor"` // defaults to 1
+	Label             string  `yaml:"label"`               // defaults to WIDTHxHEIGHT
+}
+
+// HardwareConfig is a machine a persona can run on, as WebGL and navigator report it
+type HardwareConfig struct {
+	Platform            string  `yaml:"platform"` // windows, mac or linux; empty fits any user agent
+	WebGLVendor         string  `yaml:"webgl_vendor"`
+	WebGLRenderer       string  `yaml:"webgl_renderer"`
+	HardwareConcurrency int     `yaml:"hardware_concurrency"`
+	DeviceMemory        float64 `yaml:"device_memory"` // GiB, as navigator.deviceMemory rounds it
+}
+
+// NetworkConfig emulates the connection the browser's traffic goes through; all zero
+// leaves it unshaped
+type NetworkConfig struct {
+	DownloadKbps int `yaml:"download_kbps"`
+	UploadKbps   int `yaml:"upload_kbps"`
+	LatencyMs    int `yaml:"latency_ms"`
 }
 
+// BlockableResources are the kinds of requests browser.block_resources can abort
+var BlockableResources = []string{"image", "font", "media", "analytics"}
+
+// HardwarePlatforms are the platforms browser.hardware entries can be tied to
+var HardwarePlatforms = []string{"windows", "mac", "linux"}
+
 // LoggingConfig contains logging settings
 type LoggingConfig struct {
 	Level  string `yaml:"level"`
 	Format string `yaml:"format"`
-	Output string `yaml:"output"`
+	Output string `yaml:"output"` // stdout, or a file path that gets JSON logs besides stdout
+
+	Rotation LogRotationConfig `yaml:"rotation"`
+}
+
+// LogRotationConfig controls rotation of the log file
+type LogRotationConfig struct {
+	MaxSizeMB  int `yaml:"max_size_mb"`
+	MaxAgeDays int `yaml:"max_age_days"` // rotated files older than this are deleted
+	MaxBackups int `yaml:"max_backups"`  // rotated files kept
+}
+
+// PlannerConfig contains daily budget planning settings
+type PlannerConfig struct {
+	Backlog BacklogConfig `yaml:"backlog"`
+}
+
+// BacklogConfig controls how the connect budget shrinks while accepted connections await a message
+type BacklogConfig struct {
+	Enabled            bool    `yaml:"enabled"`
+	Threshold          int     `yaml:"threshold"`            // unmessaged accepted connections that trigger the rule
+	Policy             string  `yaml:"policy"`               // reduce or pause
+	ReduceFactor       float64 `yaml:"reduce_factor"`        // share of the connect budget kept under the reduce policy
+	MaxConsecutiveDays int     `yaml:"max_consecutive_days"` // never reduce more than this many runs in a row
+}
+
+// Run phases that get their own time budget
+const (
+	PhaseSearch    = "search"
+	PhaseConnect   = "connect"
+	PhaseInvites   = "invites"
+	PhaseMessaging = "messaging"
+)
+
+// Phases lists the budgeted phases in run order
+var Phases = []string{PhaseSearch, PhaseConnect, PhaseInvites, PhaseMessaging}
+
+// defaultPhaseShares is the share of the session each phase gets unless configured
+var defaultPhaseShares = map[string]float64{
+	PhaseSearch:    0.2,
+	PhaseConnect:   0.5,
+	PhaseInvites:   0.1,
+	PhaseMessaging: 0.2,
+}
+
+// SessionConfig contains the session length and per-phase time budgets
+type SessionConfig struct {
+	MaxMinutes   int            `yaml:"max_minutes"`   // defaults to the business-hours window
+	PhaseBudgets map[string]int `yaml:"phase_budgets"` // minutes per phase; unset phases get a share of max_minutes
+
+	// What a run paused with ctl pause does until it is resumed
+	PauseKeepAlive        string `yaml:"pause_keep_alive"`         // idle or none
+	PauseKeepAliveMinutes int    `yaml:"pause_keep_alive_minutes"` // how often an idle action runs while paused
+}
+
+// What a paused run does to keep its session alive
+const (
+	PauseKeepAliveIdle = "idle" // an idle action, such as reading the feed, now and then
+	PauseKeepAliveNone = "none" // nothing; the browser is left alone
+)
+
+// PauseKeepAlives lists the values session.pause_keep_alive can take
+var PauseKeepAlives = []string{PauseKeepAliveIdle, PauseKeepAliveNone}
+
+// PhaseBudget returns the time budget of a phase
+func (c *Config) PhaseBudget(phase string) time.Duration {
+	return time.Duration(c.Session.PhaseBudgets[phase]) * time.Minute
+}
+
+// ReportingConfig contains run report settings
+type ReportingConfig struct {
+	Dir string `yaml:"dir"`
+}
+
+// NotificationsConfig contains webhook and email notification settings
+type NotificationsConfig struct {
+	WebhookURL string      `yaml:"webhook_url" secret:"true"`
+	Format     string      `yaml:"format"` // json or slack
+	Events     []string    `yaml:"events"`
+	Email      EmailConfig `yaml:"email"`
+}
+
+// EmailConfig contains the daily digest email settings. The SMTP login comes from the
+// SMTP_USERNAME and SMTP_PASSWORD environment variables.
+type EmailConfig struct {
+	Enabled  bool     `yaml:"enabled"`
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"`
+	TLS      string   `yaml:"tls"` // starttls or none
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+	Events   []string `yaml:"events"`    // events also mailed as they happen; empty mails only the digest
+	DigestAt string   `yaml:"digest_at"` // HH:MM to mail the digest in daemon mode; empty mails it after each run
+}
+
+// DigestTime returns when on the day of now the daemon mails the digest, and false when
+// it is mailed after each run instead
+func (e EmailConfig) DigestTime(now time.Time) (time.Time, bool) {
+	at, err := time.Parse("15:04", e.DigestAt)
+	if e.DigestAt == "" || err != nil {
+		return time.Time{}, false
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), at.Hour(), at.Minute(), 0, 0, now.Location()), true
+}
+
+// DebugConfig contains failure diagnostics settings
+type DebugConfig struct {
+	CaptureOnError bool   `yaml:"capture_on_error"`
+	ArtifactsDir   string `yaml:"artifacts_dir"`
+	MaxArtifacts   int    `yaml:"max_artifacts"` // oldest artifacts are evicted beyond this count
+	MaxTotalMB     int    `yaml:"max_total_mb"`  // or beyond this total size
+}
+
+// CaptchaConfig contains settings for the optional CAPTCHA solving service
+type CaptchaConfig struct {
+	Enabled             bool    `yaml:"enabled"`
+	APIURL              string  `yaml:"api_url"`
+	APIKey              string  `yaml:"api_key" secret:"true"` // prefer the CAPTCHA_API_KEY environment variable
+	TimeoutSeconds      int     `yaml:"timeout_seconds"`
+	PollIntervalSeconds int     `yaml:"poll_interval_seconds"`
+	CostPerSolve        float64 `yaml:"cost_per_solve"`
+	MaxDailyCost        float64 `yaml:"max_daily_cost"`
 }
 
 // Credentials contains LinkedIn login credentials
@@ -128,7 +810,10 @@ type Credentials struct {
 	Password string
 }
 
-// LoadConfig loads configuration from YAML file and environment variables
+// LoadConfig loads configuration from YAML file and environment variables.
+// Later sources win: the YAML file, then environment variables (the named ones such as
+// LOG_LEVEL, then the LA_ overrides, see EnvName), then command-line flags, which the
+// caller applies to the returned config.
 func LoadConfig(configPath string) (*Config, error) {
 	// Read YAML file
 	data, err := os.ReadFile(configPath)
@@ -150,14 +835,49 @@ func LoadConfig(configPath string) (*Config, error) {
 		config.Browser.Headless = true
 	}
 
-	// Validate configuration
-	if err := validateConfig(&config); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
+	if webhookURL := os.Getenv("NOTIFY_WEBHOOK_URL"); webhookURL != "" {
+		config.Notifications.WebhookURL = webhookURL
+	}
+
+	if apiKey := os.Getenv("CAPTCHA_API_KEY"); apiKey != "" {
+		config.Captcha.APIKey = apiKey
+	}
+
+	if err := applyEnvOverrides(&config, os.LookupEnv); err != nil {
+		return nil, fmt.Errorf("invalid environment override: %w", err)
 	}
 
+	if err := finish(&config); err != nil {
+		return nil, err
+	}
 	return &config, nil
 }
 
+// Default returns the configuration shipped in configs/config.yaml, without reading a
+// file or the environment
+func Default() (*Config, error) {
+	var config Config
+	if err := yaml.Unmarshal(configs.Default, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse default config: %w", err)
+	}
+	if err := finish(&config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// finish fills in defaults, applies the safety ceilings and validates the result
+func finish(config *Config) error {
+	applyDefaults(config)
+	applySafetyCeilings(config)
+
+	// Validate configuration
+	if err := validateConfig(config); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return nil
+}
+
 // LoadCredentials loads LinkedIn credentials from environment variables
 func LoadCredentials() (*Credentials, error) {
 	email := os.Getenv("LINKEDIN_EMAIL")
@@ -173,32 +893,267 @@ func LoadCredentials() (*Credentials, error) {
 	}, nil
 }
 
-// validateConfig validates the configuration values
-func validateConfig(config *Config) error {
-	if config.Search.MaxResults <= 0 {
-		return fmt.Errorf("search.max_results must be greater than 0")
+// applyDefaults fills in optional settings that were left empty
+func applyDefaults(config *Config) {
+	if config.Browser.DailyNavigationLimit == 0 {
+		config.Browser.DailyNavigationLimit = config.Safety.Ceilings().DailyNavigations
 	}
 
-	if config.Connections.DailyLimit <= 0 {
-		return fmt.Errorf("connections.daily_limit must be greater than 0")
+	for i := range config.Browser.Viewports {
+		viewport := &config.Browser.Viewports[i]
+		if viewport.DeviceScaleFactor == 0 {
+			viewport.DeviceScaleFactor = 1
+		}
+		if viewport.Label == "" {
+			viewport.Label = fmt.Sprintf("%dx%d", viewport.Width, viewport.Height)
+		}
 	}
 
-	if config.Messaging.DailyLimit <= 0 {
-		return fmt.Errorf("messaging.daily_limit must be greater than 0")
+	if config.Search.StopAfterEmptyPages == 0 {
+		config.Search.StopAfterEmptyPages = 2
 	}
 
-	if config.Browser.TimeoutSeconds <= 0 {
-		return fmt.Errorf("browser.timeout_seconds must be greater than 0")
+	if config.Search.Provider == "" {
+		config.Search.Provider = "standard"
 	}
 
-	if len(config.Browser.UserAgents) == 0 {
-		return fmt.Errorf("browser.user_agents must contain at least one user agent")
+	if config.Search.Parser == "" {
+		config.Search.Parser = SearchParserAuto
 	}
 
-	// Validate timezone
-	if _, err := time.LoadLocation(config.Stealth.Scheduling.Timezone); err != nil {
-		return fmt.Errorf("invalid timezone: %w", err)
+	if config.Connections.NameResolution == "" {
+		config.Connections.NameResolution = "rescrape_then_fallback"
 	}
 
-	return nil
+	if config.Connections.MaxSkips == 0 {
+		config.Connections.MaxSkips = 3
+	}
+	if config.Connections.OrganicSkipCooldownDays == 0 {
+		config.Connections.OrganicSkipCooldownDays = 30
+	}
+
+	if config.Connections.TemplateSelection == "" {
+		config.Connections.TemplateSelection = "uniform"
+	}
+
+	// LinkedIn's limit on invitation notes
+	if config.Connections.NoteCharacterLimit == 0 {
+		config.Connections.NoteCharacterLimit = 300
+	}
+
+	if config.Connections.NoteMode == "" {
+		config.Connections.NoteMode = NoteModeAlways
+	}
+	if config.Connections.NotePriorityMinMutual == 0 {
+		config.Connections.NotePriorityMinMutual = 10
+	}
+	if config.Connections.PerProfileTimeoutSeconds == 0 {
+		config.Connections.PerProfileTimeoutSeconds = 300
+	}
+
+	if config.Engagement.DailyLikeLimit == 0 {
+		config.Engagement.DailyLikeLimit = 10
+	}
+
+	if config.Prune.MaxPerRun == 0 {
+		config.Prune.MaxPerRun = 10
+	}
+	if config.Diagnostics.MaxRows == 0 {
+		config.Diagnostics.MaxRows = 5000
+	}
+	if config.Database.SearchResultRetentionDays == 0 {
+		config.Database.SearchResultRetentionDays = 180
+	}
+	if config.Database.ActivityLogRetentionDays == 0 {
+		config.Database.ActivityLogRetentionDays = 365
+	}
+	if config.Database.DiagnosticRetentionDays == 0 {
+		config.Database.DiagnosticRetentionDays = 30
+	}
+	for i := range config.Monitors {
+		if config.Monitors[i].MaxPages == 0 {
+			config.Monitors[i].MaxPages = 3
+		}
+	}
+	if config.Queue.MaxAttempts == 0 {
+		config.Queue.MaxAttempts = 3
+	}
+	if config.Queue.RetryDelayMinutes == 0 {
+		config.Queue.RetryDelayMinutes = 60
+	}
+	if config.Engagement.HoursBeforeInvite == 0 {
+		config.Engagement.HoursBeforeInvite = 24
+	}
+
+	if config.Enrich.DailyLimit == 0 {
+		config.Enrich.DailyLimit = 20
+	}
+	if config.Enrich.StaleDays == 0 {
+		config.Enrich.StaleDays = 30
+	}
+	if config.ActionBudget.DailyTotal == 0 {
+		config.ActionBudget.DailyTotal = 60
+	}
+	if len(config.ActionBudget.Priority) == 0 {
+		config.ActionBudget.Priority = ActionTypes
+	}
+
+	// The order used to be prioritization.order, where found is oldest first
+	if config.Connections.TargetOrder == "" {
+		switch config.Connections.Prioritization.Order {
+		case "found":
+			config.Connections.TargetOrder = TargetOrderOldest
+		case "mutual_connections":
+			config.Connections.TargetOrder = TargetOrderPriority
+		default:
+			config.Connections.TargetOrder = TargetOrderNewest
+		}
+	}
+
+	if config.Locale == "" {
+		config.Locale = render.DefaultLocale
+	}
+
+	if config.Messaging.TemplateSelection == "" {
+		config.Messaging.TemplateSelection = "uniform"
+	}
+
+	if config.Notifications.Format == "" {
+		config.Notifications.Format = "json"
+	}
+
+	if config.Planner.Backlog.Policy == "" {
+		config.Planner.Backlog.Policy = "reduce"
+	}
+
+	if config.Planner.Backlog.MaxConsecutiveDays == 0 {
+		config.Planner.Backlog.MaxConsecutiveDays = 2
+	}
+
+	// An inverted business-hours window is reported by validation, not turned into a negative session
+	if config.Session.PauseKeepAlive == "" {
+		config.Session.PauseKeepAlive = PauseKeepAliveIdle
+	}
+	if config.Session.PauseKeepAliveMinutes == 0 {
+		config.Session.PauseKeepAliveMinutes = 10
+	}
+
+	if config.Session.MaxMinutes == 0 && config.Stealth.Scheduling.BusinessHoursEnd > config.Stealth.Scheduling.BusinessHoursStart {
+		config.Session.MaxMinutes = (config.Stealth.Scheduling.BusinessHoursEnd - config.Stealth.Scheduling.BusinessHoursStart) * 60
+		// Phases share a capped session rather than the whole window
+		if capped := config.Stealth.Scheduling.MaxSessionMinutes; capped > 0 && capped < config.Session.MaxMinutes {
+			config.Session.MaxMinutes = capped
+		}
+	}
+
+	if config.Session.PhaseBudgets == nil {
+		config.Session.PhaseBudgets = make(map[string]int)
+	}
+
+	for _, phase := range Phases {
+		if config.Session.PhaseBudgets[phase] != 0 {
+			continue
+		}
+		minutes := int(float64(config.Session.MaxMinutes) * defaultPhaseShares[phase])
+		// Spread connection requests are planned across the whole window
+		if phase == PhaseConnect && config.Stealth.Scheduling.SpreadActions {
+			minutes = config.Session.MaxMinutes
+		}
+		if minutes < 1 {
+			minutes = 1
+		}
+		config.Session.PhaseBudgets[phase] = minutes
+	}
+
+	if config.Logging.Rotation.MaxSizeMB == 0 {
+		config.Logging.Rotation.MaxSizeMB = 50
+	}
+
+	if config.Logging.Rotation.MaxBackups == 0 {
+		config.Logging.Rotation.MaxBackups = 5
+	}
+
+	if config.Logging.Rotation.MaxAgeDays == 0 {
+		config.Logging.Rotation.MaxAgeDays = 30
+	}
+
+	if config.Reporting.Dir == "" {
+		config.Reporting.Dir = "reports"
+	}
+
+	if config.Notifications.Email.Port == 0 {
+		config.Notifications.Email.Port = 587
+	}
+
+	if config.Notifications.Email.TLS == "" {
+		config.Notifications.Email.TLS = "starttls"
+	}
+
+	if config.LinkedIn.UILanguage == "" {
+		config.LinkedIn.UILanguage = UILanguageAuto
+	}
+
+	if config.Safety.Breaker.MaxConsecutiveFailures == 0 {
+		config.Safety.Breaker.MaxConsecutiveFailures = 5
+	}
+
+	if config.Safety.Breaker.Window == 0 {
+		config.Safety.Breaker.Window = 20
+	}
+
+	if config.Safety.Breaker.MaxFailureRate == 0 {
+		config.Safety.Breaker.MaxFailureRate = 0.5
+	}
+
+	if config.Debug.ArtifactsDir == "" {
+		config.Debug.ArtifactsDir = "artifacts"
+	}
+
+	if config.Debug.MaxArtifacts == 0 {
+		config.Debug.MaxArtifacts = 50
+	}
+
+	if config.Debug.MaxTotalMB == 0 {
+		config.Debug.MaxTotalMB = 200
+	}
+
+	if t := config.Stealth.Typing.Typos; t == (TypoConfig{}) {
+		config.Stealth.Typing.Typos = TypoConfig{Adjacent: 0.6, Doubled: 0.2, Transposed: 0.2, LateCorrection: 0.2}
+	}
+
+	if config.Stealth.ProfileReading == "" {
+		config.Stealth.ProfileReading = ProfileReadingLight
+	}
+	if config.Stealth.Fatigue.MaxSlowdown == 0 {
+		config.Stealth.Fatigue.MaxSlowdown = 0.3
+	}
+	if config.Stealth.Fatigue.RampMinutes == 0 {
+		config.Stealth.Fatigue.RampMinutes = 90
+	}
+	if config.Stealth.Persona.Spread == 0 {
+		config.Stealth.Persona.Spread = 0.25
+	}
+	if config.Stealth.Humanize.Probability == 0 {
+		config.Stealth.Humanize.Probability = 0.3
+	}
+
+	if config.Stealth.Humanize.FeedMinSeconds == 0 {
+		config.Stealth.Humanize.FeedMinSeconds = 20
+	}
+
+	if config.Stealth.Humanize.FeedMaxSeconds == 0 {
+		config.Stealth.Humanize.FeedMaxSeconds = 90
+	}
+
+	if config.Stealth.Humanize.LikeProbability == 0 {
+		config.Stealth.Humanize.LikeProbability = 0.05
+	}
+
+	if config.Captcha.TimeoutSeconds == 0 {
+		config.Captcha.TimeoutSeconds = 120
+	}
+
+	if config.Captcha.PollIntervalSeconds == 0 {
+		config.Captcha.PollIntervalSeconds = 5
+	}
 }
@@ -0,0 +1,22 @@
+package config
+
+import "testing"
+
+func TestApplyDefaultsKeepsOrganicSkipOff(t *testing.T) {
+	for _, p := range []float64{0, -1, 0.2} {
+		cfg := &Config{}
+		cfg.Connections.OrganicSkipProbability = p
+		applyDefaults(cfg)
+		if got := cfg.Connections.OrganicSkipProbability; got != p {
+			t.Errorf("organic_skip_probability %v became %v", p, got)
+		}
+	}
+}
+
+func TestApplyDefaultsNoteCharacterLimit(t *testing.T) {
+	cfg := &Config{}
+	applyDefaults(cfg)
+	if cfg.Connections.NoteCharacterLimit != 300 {
+		t.Fatalf("note_character_limit = %d, want LinkedIn's 300", cfg.Connections.NoteCharacterLimit)
+	}
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix starts the environment variables that override config settings
+const EnvPrefix = "LA_"
+
+// EnvName returns the environment variable that overrides the setting at a YAML path,
+// e.g. LA_CONNECTIONS_DAILY_LIMIT for connections.daily_limit
+func EnvName(path string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(path, ".", "_"))
+}
+
+// applyEnvOverrides sets every setting whose environment variable, as named by EnvName,
+// is present in lookup. Numbers, booleans, strings and lists of strings or numbers can
+// be set; lists are comma-separated. Settings inside lists and maps, such as campaigns
+// and weekday overrides, can't.
+func applyEnvOverrides(config *Config, lookup func(string) (string, bool)) error {
+	return overrideFields(reflect.ValueOf(config).Elem(), EnvPrefix, lookup)
+}
+
+// overrideFields applies the overrides to the fields of struct v, whose variables start with prefix
+func overrideFields(v reflect.Value, prefix string, lookup func(string) (string, bool)) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if !field.IsExported() || tag == "" || tag == "-" {
+			continue
+		}
+
+		name := prefix + strings.ToUpper(tag)
+		if field.Type.Kind() == reflect.Struct {
+			if err := overrideFields(v.Field(i), name+"_", lookup); err != nil {
+				return err
+			}
+			continue
+		}
+
+		raw, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		if err := setFromEnv(v.Field(i), raw); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// setFromEnv parses raw into a setting
+func setFromEnv(field reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", raw)
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", raw)
+		}
+		field.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		switch field.Type().Elem().Kind() {
+		case reflect.String, reflect.Int:
+		default:
+			return fmt.Errorf("this setting can't be set from the environment")
+		}
+
+		items := reflect.MakeSlice(field.Type(), 0, 0)
+		if raw != "" {
+			for _, part := range strings.Split(raw, ",") {
+				item := reflect.New(field.Type().Elem()).Elem()
+				if err := setFromEnv(item, part); err != nil {
+					return err
+				}
+				items = reflect.Append(items, item)
+			}
+		}
+		field.Set(items)
+	default:
+		return fmt.Errorf("this setting can't be set from the environment")
+	}
+	return nil
+}
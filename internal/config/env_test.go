@@ -0,0 +1,65 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnvName(t *testing.T) {
+	if got := EnvName("connections.daily_limit"); got != "LA_CONNECTIONS_DAILY_LIMIT" {
+		t.Fatalf("EnvName = %s", got)
+	}
+}
+
+func TestApplyEnvOverrides(t *testing.T) {
+	env := map[string]string{
+		"LA_CONNECTIONS_DAILY_LIMIT":           "7",
+		"LA_BROWSER_HEADLESS":                  "true",
+		"LA_STEALTH_TYPING_TYPO_PROBABILITY":   " 0.05 ",
+		"LA_SEARCH_FILTERS_KEYWORDS":           "golang,rust",
+		"LA_BROWSER_EXTRA_ARGS":                "",
+		"LA_CONNECTIONS_NOTE_TEMPLATES_0_TEXT": "settings inside lists are out of reach",
+		"CONNECTIONS_DAILY_LIMIT":              "99", // no prefix
+	}
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+
+	cfg := Config{Browser: BrowserConfig{ExtraArgs: []string{"--mute-audio"}}}
+	cfg.Connections.DailyLimit = 20
+	if err := applyEnvOverrides(&cfg, lookup); err != nil {
+		t.Fatalf("applyEnvOverrides: %v", err)
+	}
+
+	if cfg.Connections.DailyLimit != 7 || !cfg.Browser.Headless || cfg.Stealth.Typing.TypoProbability != 0.05 {
+		t.Fatalf("overrides not applied: daily limit %d, headless %v, typos %v",
+			cfg.Connections.DailyLimit, cfg.Browser.Headless, cfg.Stealth.Typing.TypoProbability)
+	}
+	if strings.Join(cfg.Search.Filters.Keywords, "|") != "golang|rust" {
+		t.Fatalf("keywords = %q", cfg.Search.Filters.Keywords)
+	}
+	if len(cfg.Browser.ExtraArgs) != 0 {
+		t.Fatalf("an empty list override left %q", cfg.Browser.ExtraArgs)
+	}
+}
+
+func TestApplyEnvOverridesRejectsBadValues(t *testing.T) {
+	for name, value := range map[string]string{
+		"LA_CONNECTIONS_DAILY_LIMIT":         "twenty",
+		"LA_BROWSER_HEADLESS":                "maybe",
+		"LA_STEALTH_TYPING_TYPO_PROBABILITY": "5%",
+		"LA_CAMPAIGNS":                       "founders",
+	} {
+		lookup := func(n string) (string, bool) {
+			if n == name {
+				return value, true
+			}
+			return "", false
+		}
+		err := applyEnvOverrides(&Config{}, lookup)
+		if err == nil || !strings.HasPrefix(err.Error(), name+": ") {
+			t.Errorf("%s=%s: error = %v, want one naming the variable", name, value, err)
+		}
+	}
+}
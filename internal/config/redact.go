@@ -0,0 +1,28 @@
+package config
+
+import "reflect"
+
+// RedactedValue is the placeholder that replaces secret values
+const RedactedValue = "[redacted]"
+
+// Redacted returns a copy of the config with every field tagged secret:"true" masked.
+// Unset secrets stay empty so a snapshot still shows whether one was configured.
+func (c *Config) Redacted() *Config {
+	copied := *c
+	redact(reflect.ValueOf(&copied).Elem())
+	return &copied
+}
+
+// redact masks secret string fields of v and of the structs nested in it by value
+func redact(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		switch {
+		case field.Kind() == reflect.Struct:
+			redact(field)
+		case field.Kind() == reflect.String && t.Field(i).Tag.Get("secret") == "true" && field.String() != "":
+			field.SetString(RedactedValue)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SafetyConfig lifts the built-in safety ceilings. Both the acknowledgement and a
+// per-value override are required; a value without an override keeps its ceiling.
+type SafetyConfig struct {
+	IKnowWhatImDoing bool           `yaml:"i_know_what_im_doing"`
+	Overrides        SafetyCeilings `yaml:"overrides"`
+	Breaker          BreakerConfig  `yaml:"breaker"`
+
+	Clamped []Clamp `yaml:"-"` // configured values that were lowered to a ceiling
+}
+
+// SafetyCeilings caps daily volumes regardless of the limits configured
+type SafetyCeilings struct {
+	DailyConnections int `yaml:"daily_connections"`
+	DailyMessages    int `yaml:"daily_messages"`
+	DailyNavigations int `yaml:"daily_navigations"`
+}
+
+// BreakerConfig puts the bot in safe mode once profile actions keep failing: the run
+// stops and no other starts until the resume command clears it
+type BreakerConfig struct {
+	MaxConsecutiveFailures int     `yaml:"max_consecutive_failures"` // negative never trips on a streak
+	Window                 int     `yaml:"window"`                   // recent actions the failure rate is taken over
+	MaxFailureRate         float64 `yaml:"max_failure_rate"`         // 0-1; negative never trips on the rate
+}
+
+// DefaultSafetyCeilings are conservative maximums a typo in the config can't get past
+var DefaultSafetyCeilings = SafetyCeilings{
+	DailyConnections: 40,
+	DailyMessages:    60,
+	DailyNavigations: 400,
+}
+
+// Clamp records a configured value lowered to its safety ceiling
+type Clamp struct {
+	Setting    string
+	Configured int
+	Ceiling    int
+}
+
+// String describes the clamp for logs and the plan
+func (c Clamp) String() string {
+	return fmt.Sprintf("%s %d exceeds the safety ceiling, clamped to %d", c.Setting, c.Configured, c.Ceiling)
+}
+
+// Ceilings returns the ceilings in effect: the defaults, with overrides applied only
+// when i_know_what_im_doing is set
+func (s *SafetyConfig) Ceilings() SafetyCeilings {
+	ceilings := DefaultSafetyCeilings
+	if !s.IKnowWhatImDoing {
+		return ceilings
+	}
+
+	if s.Overrides.DailyConnections > 0 {
+		ceilings.DailyConnections = s.Overrides.DailyConnections
+	}
+	if s.Overrides.DailyMessages > 0 {
+		ceilings.DailyMessages = s.Overrides.DailyMessages
+	}
+	if s.Overrides.DailyNavigations > 0 {
+		ceilings.DailyNavigations = s.Overrides.DailyNavigations
+	}
+	return ceilings
+}
+
+// applySafetyCeilings lowers every daily volume above its ceiling, so the limit
+// tracker, planner and stats all see the clamped value, and records each clamp
+func applySafetyCeilings(config *Config) {
+	ceilings := config.Safety.Ceilings()
+	config.Safety.Clamped = nil
+
+	clamp := func(setting string, value *int, ceiling int) {
+		if *value > ceiling {
+			config.Safety.Clamped = append(config.Safety.Clamped, Clamp{Setting: setting, Configured: *value, Ceiling: ceiling})
+			*value = ceiling
+		}
+	}
+
+	clamp("connections.daily_limit", &config.Connections.DailyLimit, ceilings.DailyConnections)
+	clamp("messaging.daily_limit", &config.Messaging.DailyLimit, ceilings.DailyMessages)
+	clamp("browser.daily_navigation_limit", &config.Browser.DailyNavigationLimit, ceilings.DailyNavigations)
+
+	// Map order is random; keep the clamps in a stable order
+	days := make([]string, 0, len(config.Stealth.Scheduling.WeekdayOverrides))
+	for day := range config.Stealth.Scheduling.WeekdayOverrides {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+	for _, day := range days {
+		override := config.Stealth.Scheduling.WeekdayOverrides[day]
+		clamp("stealth.scheduling.weekday_overrides."+strings.ToLower(day)+".daily_limit", &override.DailyLimit, ceilings.DailyConnections)
+		config.Stealth.Scheduling.WeekdayOverrides[day] = override
+	}
+}
+
+// validateSafety checks that ceilings are only lifted deliberately
+func validateSafety(p *problems, s *SafetyConfig) {
+	o := s.Overrides
+	if o.DailyConnections < 0 || o.DailyMessages < 0 || o.DailyNavigations < 0 {
+		p.addf("safety.overrides must not be negative")
+	}
+
+	if o != (SafetyCeilings{}) && !s.IKnowWhatImDoing {
+		p.addf("safety.overrides requires safety.i_know_what_im_doing: true")
+	}
+
+	if s.Breaker.Window < 0 {
+		p.addf("safety.breaker.window must not be negative")
+	}
+	if s.Breaker.MaxFailureRate > 1 {
+		p.addf("safety.breaker.max_failure_rate must be at most 1")
+	}
+}
@@ -0,0 +1,783 @@
+package config
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/profileurl"
+	"github.com/Tanukumar01/linkedin-automation/internal/render"
+)
+
+// ValidationError lists every problem found in a configuration. Each problem starts
+// with the YAML path of the setting it is about.
+type ValidationError struct {
+	Problems []string
+}
+
+// Error joins the problems on one line
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Problems, "; ")
+}
+
+// problems collects validation problems so they can be reported together
+type problems []string
+
+// addf records a problem
+func (p *problems) addf(format string, args ...interface{}) {
+	*p = append(*p, fmt.Sprintf(format, args...))
+}
+
+// minMax checks a pair of bounds: neither may be negative and min may not exceed max,
+// since a random value is drawn between them
+func (p *problems) minMax(section, minName, maxName string, min, max int) {
+	if min < 0 || max < 0 {
+		p.addf("%s.%s and %s.%s must not be negative", section, minName, section, maxName)
+	} else if min > max {
+		p.addf("%s.%s (%d) must not exceed %s.%s (%d)", section, minName, min, section, maxName, max)
+	}
+}
+
+// probability checks that a setting is a probability
+func (p *problems) probability(path string, value float64) {
+	if value < 0 || value > 1 {
+		p.addf("%s must be between 0 and 1", path)
+	}
+}
+
+// err returns the collected problems as a *ValidationError, or nil when there are none
+func (p problems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: p}
+}
+
+// Validate checks a configuration changed after it was loaded, such as one adjusted in code
+func (c *Config) Validate() error {
+	return validateConfig(c)
+}
+
+// validateConfig validates the configuration values, reporting every problem at once
+func validateConfig(config *Config) error {
+	var p problems
+
+	validateSearch(&p, config)
+	validateConnections(&p, config)
+	validateSafety(&p, &config.Safety)
+
+	if !slices.Contains(UILanguages, config.LinkedIn.UILanguage) {
+		p.addf("linkedin.ui_language must be one of %s", strings.Join(UILanguages, ", "))
+	}
+	validateMessaging(&p, config)
+	validateCampaigns(&p, config)
+	validateMonitors(&p, config)
+
+	if config.Invites.Enabled && config.Invites.DailyAcceptLimit <= 0 {
+		p.addf("invites.daily_accept_limit must be greater than 0 when invites are enabled")
+	}
+	if config.Invites.MinMutualConnections < 0 {
+		p.addf("invites.min_mutual_connections must not be negative")
+	}
+	for i, pattern := range config.Invites.TitlePatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			p.addf("invites.title_patterns[%d]: invalid pattern %q: %v", i, pattern, err)
+		}
+	}
+
+	if !slices.Contains(PauseKeepAlives, config.Session.PauseKeepAlive) {
+		p.addf("session.pause_keep_alive must be one of %s", strings.Join(PauseKeepAlives, ", "))
+	}
+	if config.Session.PauseKeepAliveMinutes < 0 {
+		p.addf("session.pause_keep_alive_minutes must not be negative")
+	}
+
+	if config.Notifications.Format != "json" && config.Notifications.Format != "slack" {
+		p.addf("notifications.format must be json or slack")
+	}
+
+	if email := config.Notifications.Email; email.Enabled {
+		if email.Host == "" {
+			p.addf("notifications.email.host is required")
+		}
+		if email.Port <= 0 || email.Port > 65535 {
+			p.addf("notifications.email.port must be between 1 and 65535")
+		}
+		if email.TLS != "starttls" && email.TLS != "none" {
+			p.addf("notifications.email.tls must be starttls or none")
+		}
+		if _, err := mail.ParseAddress(email.From); err != nil {
+			p.addf("notifications.email.from: invalid address %q", email.From)
+		}
+		if len(email.To) == 0 {
+			p.addf("notifications.email.to needs at least one address")
+		}
+		for i, to := range email.To {
+			if _, err := mail.ParseAddress(to); err != nil {
+				p.addf("notifications.email.to[%d]: invalid address %q", i, to)
+			}
+		}
+		if _, err := time.Parse("15:04", email.DigestAt); email.DigestAt != "" && err != nil {
+			p.addf("notifications.email.digest_at must be a time of day such as 18:00")
+		}
+	}
+
+	if backlog := config.Planner.Backlog; backlog.Enabled {
+		if backlog.Threshold <= 0 {
+			p.addf("planner.backlog.threshold must be greater than 0")
+		}
+		if backlog.Policy != "reduce" && backlog.Policy != "pause" {
+			p.addf("planner.backlog.policy must be reduce or pause")
+		}
+		if backlog.Policy == "reduce" && (backlog.ReduceFactor < 0 || backlog.ReduceFactor >= 1) {
+			p.addf("planner.backlog.reduce_factor must be between 0 and 1")
+		}
+		if backlog.MaxConsecutiveDays < 0 {
+			p.addf("planner.backlog.max_consecutive_days must not be negative")
+		}
+	}
+
+	if config.Prune.MaxPerRun < 0 {
+		p.addf("prune.max_per_run must not be negative")
+	}
+
+	if config.Diagnostics.MaxRows < 0 {
+		p.addf("diagnostics.max_rows must not be negative")
+	}
+	// Pruning search results still handed out would drop prospects the campaigns are working through
+	retention, maxAge := config.Database.SearchResultRetentionDays, config.Connections.MaxResultAgeDays
+	if retention > 0 && maxAge > 0 && retention < maxAge {
+		p.addf("database.search_result_retention_days must be at least connections.max_result_age_days")
+	}
+
+	if config.Queue.MaxAttempts < 0 {
+		p.addf("queue.max_attempts must not be negative")
+	}
+	if config.Queue.RetryDelayMinutes < 0 {
+		p.addf("queue.retry_delay_minutes must not be negative")
+	}
+
+	if config.Session.MaxMinutes < 0 {
+		p.addf("session.max_minutes must not be negative")
+	}
+	for phase, minutes := range config.Session.PhaseBudgets {
+		if _, ok := defaultPhaseShares[phase]; !ok {
+			p.addf("session.phase_budgets: unknown phase %q (use %s)", phase, strings.Join(Phases, ", "))
+		} else if minutes < 0 {
+			p.addf("session.phase_budgets.%s must not be negative", phase)
+		}
+	}
+
+	if r := config.Logging.Rotation; r.MaxSizeMB < 0 || r.MaxAgeDays < 0 || r.MaxBackups < 0 {
+		p.addf("logging.rotation settings must not be negative")
+	}
+
+	if config.Debug.MaxArtifacts < 0 {
+		p.addf("debug.max_artifacts must not be negative")
+	}
+	if config.Debug.MaxTotalMB < 0 {
+		p.addf("debug.max_total_mb must not be negative")
+	}
+
+	if captcha := config.Captcha; captcha.Enabled {
+		if captcha.APIURL == "" {
+			p.addf("captcha.api_url is required when captcha solving is enabled")
+		}
+		if captcha.TimeoutSeconds < 0 || captcha.PollIntervalSeconds < 0 {
+			p.addf("captcha.timeout_seconds and captcha.poll_interval_seconds must not be negative")
+		}
+		if captcha.CostPerSolve < 0 {
+			p.addf("captcha.cost_per_solve must not be negative")
+		}
+		if captcha.MaxDailyCost <= 0 {
+			p.addf("captcha.max_daily_cost must be greater than 0 when captcha solving is enabled")
+		}
+	}
+
+	validateStealth(&p, config)
+	validateBrowser(&p, &config.Browser)
+
+	return p.err()
+}
+
+// validateSearch checks the search settings
+func validateSearch(p *problems, config *Config) {
+	search := &config.Search
+	if search.MaxResults <= 0 {
+		p.addf("search.max_results must be greater than 0")
+	}
+	p.minMax("search", "pagination_delay_min", "pagination_delay_max", search.PaginationDelayMin, search.PaginationDelayMax)
+
+	switch search.Provider {
+	case "standard", "sales_navigator":
+	default:
+		p.addf("search.provider must be one of standard, sales_navigator")
+	}
+	if !slices.Contains(SearchParsers, search.Parser) {
+		p.addf("search.parser must be one of %s", strings.Join(SearchParsers, ", "))
+	} else if search.Parser == SearchParserJSON && search.Provider != "standard" {
+		p.addf("search.parser json needs search.provider standard")
+	}
+
+	validateSources(p, "search.sources", search.Sources)
+	validateFilters(p, "search.filters", search.Filters)
+	validateSearchProvider(p, "search.filters", search.Provider, search.Filters)
+
+	for i, spotlight := range search.SalesNavigator.Spotlights {
+		if !slices.Contains(SalesNavigatorSpotlights, spotlight) {
+			p.addf("search.sales_navigator.spotlights[%d]: unknown spotlight %q (use %s)", i, spotlight, strings.Join(SalesNavigatorSpotlights, ", "))
+		}
+	}
+}
+
+// minReinviteCooldownDays is how long LinkedIn blocks inviting someone again after an
+// invite to them was withdrawn
+const minReinviteCooldownDays = 21
+
+// validateConnections checks the connection request and pre-engagement settings
+func validateConnections(p *problems, config *Config) {
+	connections := &config.Connections
+	if connections.DailyLimit <= 0 {
+		p.addf("connections.daily_limit must be greater than 0")
+	}
+	if connections.HourlyLimit < 0 {
+		p.addf("connections.hourly_limit must not be negative")
+	}
+	if connections.WeeklyLimit < 0 {
+		p.addf("connections.weekly_limit must not be negative")
+	}
+	if weekly := connections.WeeklyLimit; weekly > 0 && connections.DailyLimit > weekly {
+		p.addf("connections.daily_limit (%d) must not exceed connections.weekly_limit (%d)", connections.DailyLimit, weekly)
+	}
+
+	if config.Browser.DailyNavigationLimit < 0 {
+		p.addf("browser.daily_navigation_limit must not be negative")
+	}
+
+	switch connections.NameResolution {
+	case "rescrape_then_fallback", "fallback", "skip":
+	default:
+		p.addf("connections.name_resolution must be one of rescrape_then_fallback, fallback, skip")
+	}
+
+	validateSelection(p, "connections.template_selection", connections.TemplateSelection)
+	validateTemplates(p, "connections.note_templates", connections.NoteTemplates, config.Locale)
+
+	switch connections.NoteMode {
+	case NoteModeAlways, NoteModeNever, NoteModePriority:
+	default:
+		p.addf("connections.note_mode must be one of always, never, priority")
+	}
+	if connections.MonthlyNoteBudget < 0 {
+		p.addf("connections.monthly_note_budget must not be negative")
+	}
+	if connections.NotePriorityMinMutual < 0 {
+		p.addf("connections.note_priority_min_mutual must not be negative")
+	}
+
+	// Notes over the limit are cut to make room for "...", whichever templates they come from
+	notes := len(connections.NoteTemplates) > 0
+	for _, campaign := range config.Campaigns {
+		notes = notes || len(campaign.NoteTemplates) > 0
+	}
+	if notes && connections.NoteCharacterLimit <= 3 {
+		p.addf("connections.note_character_limit must be greater than 3 when note templates are set")
+	}
+	p.minMax("connections", "cooldown_between_requests_min", "cooldown_between_requests_max", connections.CooldownBetweenRequestsMin, connections.CooldownBetweenRequestsMax)
+
+	if sp := connections.SkipProbability; sp < 0 || sp > 0.5 {
+		p.addf("connections.skip_probability must be between 0 and 0.5")
+	}
+	if connections.MaxSkips < 0 {
+		p.addf("connections.max_skips must not be negative")
+	}
+	if timeout := connections.PerProfileTimeoutSeconds; timeout > 0 && timeout < 30 {
+		p.addf("connections.per_profile_timeout_seconds must be at least 30, or negative to disable it")
+	}
+	if connections.OrganicSkipProbability > 0.5 {
+		p.addf("connections.organic_skip_probability must be at most 0.5")
+	}
+	if connections.OrganicSkipCooldownDays < 0 {
+		p.addf("connections.organic_skip_cooldown_days must not be negative")
+	}
+	if connections.ReinviteCooldownDays < 0 {
+		p.addf("connections.reinvite_cooldown_days must not be negative")
+	} else if connections.ReinviteCooldownDays > 0 && connections.ReinviteCooldownDays < minReinviteCooldownDays {
+		p.addf("connections.reinvite_cooldown_days must be 0 or at least %d, LinkedIn refuses re-invites sooner after a withdrawal", minReinviteCooldownDays)
+	}
+
+	switch connections.Prioritization.Order {
+	case "", "found", "mutual_connections":
+	default:
+		p.addf("connections.prioritization.order must be one of found, mutual_connections")
+	}
+	for i, tag := range connections.Prioritization.BoostTags {
+		if strings.TrimSpace(tag) == "" || strings.Contains(tag, ",") {
+			p.addf("connections.prioritization.boost_tags[%d]: invalid tag %q", i, tag)
+		}
+	}
+	if !slices.Contains(TargetOrders, connections.TargetOrder) {
+		p.addf("connections.target_order must be one of %s", strings.Join(TargetOrders, ", "))
+	}
+	if connections.MaxResultAgeDays < 0 {
+		p.addf("connections.max_result_age_days must not be negative")
+	}
+
+	if pe := connections.PreEngage; pe != "" && pe != PreEngageLike {
+		p.addf("connections.pre_engage must be empty or like")
+	}
+	if config.Engagement.DailyLikeLimit < 0 {
+		p.addf("engagement.daily_like_limit must not be negative")
+	}
+	if config.Engagement.HoursBeforeInvite < 0 {
+		p.addf("engagement.hours_before_invite must not be negative")
+	}
+	if config.Enrich.DailyLimit < 0 {
+		p.addf("enrich.daily_limit must not be negative")
+	}
+	if config.Enrich.StaleDays < 0 {
+		p.addf("enrich.stale_days must not be negative")
+	}
+
+	if config.ActionBudget.DailyTotal < 0 {
+		p.addf("action_budget.daily_total must not be negative")
+	}
+	for i, action := range config.ActionBudget.Priority {
+		if !slices.Contains(ActionTypes, action) {
+			p.addf("action_budget.priority[%d] must be one of %s", i, strings.Join(ActionTypes, ", "))
+		} else if slices.Index(config.ActionBudget.Priority, action) < i {
+			p.addf("action_budget.priority lists %s twice", action)
+		}
+	}
+}
+
+// validateMessaging checks the messaging settings
+func validateMessaging(p *problems, config *Config) {
+	messaging := &config.Messaging
+	if messaging.DailyLimit <= 0 {
+		p.addf("messaging.daily_limit must be greater than 0")
+	}
+	if messaging.HourlyLimit < 0 {
+		p.addf("messaging.hourly_limit must not be negative")
+	}
+
+	validateSelection(p, "messaging.template_selection", messaging.TemplateSelection)
+	validateTemplates(p, "messaging.templates", messaging.Templates, config.Locale)
+	validateTemplates(p, "messaging.welcome_templates", messaging.WelcomeTemplates, config.Locale)
+	p.minMax("messaging", "cooldown_between_messages_min", "cooldown_between_messages_max", messaging.CooldownBetweenMessagesMin, messaging.CooldownBetweenMessagesMax)
+
+	if am := messaging.AcceptanceMessage; am.Enabled {
+		p.minMax("messaging.acceptance_message", "delay_min_hours", "delay_max_hours", am.DelayMinHours, am.DelayMaxHours)
+		if len(messaging.Templates) == 0 {
+			p.addf("messaging.templates must not be empty when messaging.acceptance_message is enabled")
+		}
+	}
+
+	validateSequences(p, config)
+
+	if inMail := messaging.InMail; inMail.Enabled {
+		if inMail.DailyLimit <= 0 {
+			p.addf("messaging.inmail.daily_limit must be greater than 0 when InMail is enabled")
+		}
+		if len(inMail.Subjects) == 0 {
+			p.addf("messaging.inmail.subjects must not be empty when InMail is enabled")
+		}
+		if len(inMail.Templates) == 0 {
+			p.addf("messaging.inmail.templates must not be empty when InMail is enabled")
+		}
+		validateTemplates(p, "messaging.inmail.subjects", inMail.Subjects, config.Locale)
+		validateTemplates(p, "messaging.inmail.templates", inMail.Templates, config.Locale)
+	}
+}
+
+// validateSequences checks sequence names, campaigns and steps
+func validateSequences(p *problems, config *Config) {
+	campaigns := make(map[string]bool)
+	for _, campaign := range config.EffectiveCampaigns() {
+		campaigns[campaign.Name] = true
+	}
+
+	names := make(map[string]bool)
+	for i, sequence := range config.Messaging.Sequences {
+		path := fmt.Sprintf("messaging.sequences[%d]", i)
+		if sequence.Name == "" {
+			p.addf("%s.name must be set", path)
+		} else if names[sequence.Name] {
+			p.addf("%s.name: duplicate sequence name %q", path, sequence.Name)
+		}
+		names[sequence.Name] = true
+
+		if sequence.Campaign != "" && !campaigns[sequence.Campaign] {
+			p.addf("%s.campaign: unknown campaign %q", path, sequence.Campaign)
+		}
+
+		if len(sequence.Steps) == 0 {
+			p.addf("%s.steps must not be empty", path)
+		}
+		for j, step := range sequence.Steps {
+			stepPath := fmt.Sprintf("%s.steps[%d]", path, j)
+			if step.DelayDays < 0 {
+				p.addf("%s.delay_days must not be negative", stepPath)
+			}
+			if len(step.Templates) == 0 {
+				p.addf("%s.templates must not be empty", stepPath)
+			}
+			validateTemplates(p, stepPath+".templates", step.Templates, config.Locale)
+		}
+	}
+}
+
+// validateCampaigns checks campaign names, shares, templates and sources
+func validateCampaigns(p *problems, config *Config) {
+	names := make(map[string]bool)
+	for i, campaign := range config.Campaigns {
+		path := fmt.Sprintf("campaigns[%d]", i)
+		if campaign.Name == "" {
+			p.addf("%s.name must be set", path)
+		} else if names[campaign.Name] {
+			p.addf("%s.name: duplicate campaign name %q", path, campaign.Name)
+		}
+		names[campaign.Name] = true
+
+		if campaign.MaxResults < 0 {
+			p.addf("%s.max_results must not be negative", path)
+		}
+		if campaign.BudgetShare <= 0 {
+			p.addf("%s.budget_share must be greater than 0", path)
+		}
+
+		locale := campaign.Locale
+		if locale == "" {
+			locale = config.Locale
+		}
+		validateTemplates(p, path+".note_templates", campaign.NoteTemplates, locale)
+		validateSources(p, path+".sources", campaign.Sources)
+		validateFilters(p, path+".filters", campaign.Filters)
+		validateSearchProvider(p, path+".filters", config.Search.Provider, campaign.Filters)
+	}
+}
+
+// validateMonitors checks monitor names, which must not be a campaign's, pages and filters
+func validateMonitors(p *problems, config *Config) {
+	names := make(map[string]bool)
+	for _, campaign := range config.EffectiveCampaigns() {
+		names[campaign.Name] = true
+	}
+	for i, monitor := range config.Monitors {
+		path := fmt.Sprintf("monitors[%d]", i)
+		if monitor.Name == "" {
+			p.addf("%s.name must be set", path)
+		} else if names[monitor.Name] {
+			p.addf("%s.name: %q is already a campaign or monitor name", path, monitor.Name)
+		}
+		names[monitor.Name] = true
+
+		if monitor.MaxPages < 0 {
+			p.addf("%s.max_pages must not be negative", path)
+		}
+		validateFilters(p, path+".filters", monitor.Filters)
+		validateSearchProvider(p, path+".filters", config.Search.Provider, monitor.Filters)
+	}
+}
+
+// validateFilters checks that the regular expressions among the result filter patterns compile
+func validateFilters(p *problems, path string, filters Filters) {
+	rules := []struct {
+		name     string
+		patterns []string
+	}{
+		{"title_must_include", filters.TitleMustInclude},
+		{"title_must_exclude", filters.TitleMustExclude},
+		{"location_must_include", filters.LocationMustInclude},
+		{"company_must_exclude", filters.CompanyMustExclude},
+	}
+	for _, rule := range rules {
+		for i, pattern := range rule.patterns {
+			expr, isRegexp := strings.CutPrefix(pattern, "re:")
+			if strings.TrimSpace(expr) == "" {
+				p.addf("%s.%s[%d] must not be empty", path, rule.name, i)
+				continue
+			}
+			if !isRegexp {
+				continue
+			}
+			if _, err := regexp.Compile("(?i)" + expr); err != nil {
+				p.addf("%s.%s[%d]: invalid regular expression: %v", path, rule.name, i, err)
+			}
+		}
+	}
+
+	validateNetworkFilters(p, path, filters)
+}
+
+// validateNetworkFilters checks the network depth facets and the members whose
+// connections are searched, and that together they can match someone to invite
+func validateNetworkFilters(p *problems, path string, filters Filters) {
+	seen := make(map[string]bool)
+	for i, depth := range filters.NetworkDepth {
+		if !slices.Contains(NetworkDepths, depth) {
+			p.addf("%s.network_depth[%d]: unknown depth %q (use %s)", path, i, depth, strings.Join(NetworkDepths, ", "))
+			continue
+		}
+		if seen[depth] {
+			p.addf("%s.network_depth[%d]: duplicate depth %q", path, i, depth)
+		}
+		seen[depth] = true
+	}
+	if len(seen) == 1 && seen[NetworkFirst] {
+		p.addf("%s.network_depth: %s alone only finds existing connections, who can't be invited", path, NetworkFirst)
+	}
+
+	for i, member := range filters.ConnectionOf {
+		if profileurl.ParseMemberID(member) == "" {
+			p.addf("%s.connection_of[%d]: %q is not a member ID (ACoAA...), profile URN or profile URL carrying one", path, i, member)
+		}
+	}
+	// A connection's connections are 1st or 2nd degree, never further
+	if len(filters.ConnectionOf) > 0 && len(seen) > 0 && !seen[NetworkFirst] && !seen[NetworkSecond] {
+		p.addf("%s: connection_of finds 1st and 2nd degree members only, which network_depth leaves out", path)
+	}
+}
+
+// validateSearchProvider checks that filters only use facets the search provider supports
+func validateSearchProvider(p *problems, path, provider string, filters Filters) {
+	if provider == "sales_navigator" && (len(filters.NetworkDepth) > 0 || len(filters.ConnectionOf) > 0) {
+		p.addf("%s.network_depth and connection_of need search.provider standard", path)
+	}
+}
+
+// validateStealth checks the stealth ranges, probabilities and scheduling
+func validateStealth(p *problems, config *Config) {
+	stealth := &config.Stealth
+
+	if stealth.Mouse.BezierPoints < 0 {
+		p.addf("stealth.mouse.bezier_points must not be negative")
+	}
+	p.probability("stealth.mouse.speed_variation", stealth.Mouse.SpeedVariation)
+	p.probability("stealth.mouse.overshoot_probability", stealth.Mouse.OvershootProbability)
+	p.probability("stealth.mouse.micro_correction_probability", stealth.Mouse.MicroCorrectionProbability)
+
+	p.minMax("stealth.timing", "action_delay_min", "action_delay_max", stealth.Timing.ActionDelayMin, stealth.Timing.ActionDelayMax)
+	p.minMax("stealth.timing", "think_time_min", "think_time_max", stealth.Timing.ThinkTimeMin, stealth.Timing.ThinkTimeMax)
+	if stealth.Timing.ReadingSpeedWPM < 0 {
+		p.addf("stealth.timing.reading_speed_wpm must not be negative")
+	}
+
+	p.minMax("stealth.typing", "wpm_min", "wpm_max", stealth.Typing.WPMMin, stealth.Typing.WPMMax)
+	p.probability("stealth.typing.typo_probability", stealth.Typing.TypoProbability)
+	p.probability("stealth.typing.pause_probability", stealth.Typing.PauseProbability)
+	if t := stealth.Typing.Typos; t.Adjacent < 0 || t.Doubled < 0 || t.Transposed < 0 || t.Adjacent+t.Doubled+t.Transposed == 0 {
+		p.addf("stealth.typing.typos weights must not be negative and must not all be 0")
+	}
+	p.probability("stealth.typing.typos.late_correction", stealth.Typing.Typos.LateCorrection)
+
+	p.minMax("stealth.scrolling", "speed_min", "speed_max", stealth.Scrolling.SpeedMin, stealth.Scrolling.SpeedMax)
+	p.probability("stealth.scrolling.scroll_back_probability", stealth.Scrolling.ScrollBackProbability)
+	p.probability("stealth.scrolling.pause_probability", stealth.Scrolling.PauseProbability)
+
+	if !slices.Contains(ProfileReadings, stealth.ProfileReading) {
+		p.addf("stealth.profile_reading must be one of %s", strings.Join(ProfileReadings, ", "))
+	}
+
+	if h := stealth.Humanize; h.Enabled {
+		p.probability("stealth.humanize.probability", h.Probability)
+		p.probability("stealth.humanize.like_probability", h.LikeProbability)
+		p.minMax("stealth.humanize", "feed_min_seconds", "feed_max_seconds", h.FeedMinSeconds, h.FeedMaxSeconds)
+	}
+
+	if f := stealth.Fatigue; f.Enabled {
+		if f.MaxSlowdown < 0 || f.MaxSlowdown > 2 {
+			p.addf("stealth.fatigue.max_slowdown must be between 0 and 2")
+		}
+		if f.RampMinutes < 0 {
+			p.addf("stealth.fatigue.ramp_minutes must not be negative")
+		}
+	}
+
+	if persona := stealth.Persona; persona.Enabled && (persona.Spread < 0 || persona.Spread > 0.9) {
+		p.addf("stealth.persona.spread must be between 0 and 0.9")
+	}
+
+	scheduling := &stealth.Scheduling
+	if start, end := scheduling.BusinessHoursStart, scheduling.BusinessHoursEnd; start < 0 || end > 24 || start >= end {
+		p.addf("stealth.scheduling business hours must satisfy 0 <= business_hours_start < business_hours_end <= 24 (got %d and %d)", start, end)
+	}
+	p.minMax("stealth.scheduling", "break_duration_min", "break_duration_max", scheduling.BreakDurationMin, scheduling.BreakDurationMax)
+	p.probability("stealth.scheduling.break_probability", scheduling.BreakProbability)
+
+	if _, err := time.LoadLocation(scheduling.Timezone); err != nil {
+		p.addf("stealth.scheduling.timezone: invalid timezone: %v", err)
+	}
+
+	validateWeekdayOverrides(p, config)
+
+	sessionCap, dailyCap := scheduling.MaxSessionMinutes, scheduling.MaxDailyActiveMinutes
+	if sessionCap < 0 || dailyCap < 0 {
+		p.addf("stealth.scheduling.max_session_minutes and max_daily_active_minutes must not be negative")
+	} else if dailyCap > 0 && sessionCap > dailyCap {
+		p.addf("stealth.scheduling.max_session_minutes must not exceed max_daily_active_minutes")
+	}
+}
+
+// managedBrowserFlags are launcher flags browser.extra_args may not override: the bot
+// needs them to drive the browser, or sets them from the fingerprint
+var managedBrowserFlags = []string{"remote-debugging-port", "user-data-dir", "headless", "user-agent", "enable-automation"}
+
+// validateBrowser checks the browser settings a persona is drawn from
+func validateBrowser(p *problems, browser *BrowserConfig) {
+	if browser.TimeoutSeconds <= 0 {
+		p.addf("browser.timeout_seconds must be greater than 0")
+	}
+
+	if len(browser.UserAgents) == 0 {
+		p.addf("browser.user_agents must contain at least one user agent")
+	}
+	for i, userAgent := range browser.UserAgents {
+		// The headless browser's own token; a user agent carrying it gives the bot away
+		if strings.Contains(userAgent, "Headless") {
+			p.addf("browser.user_agents[%d] must not contain \"Headless\"", i)
+		}
+	}
+
+	for i, kind := range browser.BlockResources {
+		if !slices.Contains(BlockableResources, kind) {
+			p.addf("browser.block_resources[%d] must be one of %s", i, strings.Join(BlockableResources, ", "))
+		}
+	}
+	for i, host := range browser.AllowHosts {
+		if host == "" || strings.ContainsAny(host, "/: ") {
+			p.addf("browser.allow_hosts[%d] must be a host name, like static.licdn.com", i)
+		}
+	}
+	network := browser.Network
+	if network.DownloadKbps < 0 || network.UploadKbps < 0 || network.LatencyMs < 0 {
+		p.addf("browser.network download_kbps, upload_kbps and latency_ms must not be negative")
+	}
+
+	for i, arg := range browser.ExtraArgs {
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		switch {
+		case !strings.HasPrefix(arg, "--") || name == "":
+			p.addf("browser.extra_args[%d] must look like --flag or --flag=value", i)
+		case slices.Contains(managedBrowserFlags, name):
+			p.addf("browser.extra_args[%d]: --%s is set by the bot", i, name)
+		}
+	}
+
+	if len(browser.Viewports) == 0 {
+		p.addf("browser.viewports must contain at least one viewport")
+	}
+	labels := make(map[string]bool)
+	for i, viewport := range browser.Viewports {
+		path := fmt.Sprintf("browser.viewports[%d]", i)
+		// Desktop screens run from small laptops to 4K monitors, always in landscape
+		if viewport.Width < 800 || viewport.Width > 3840 || viewport.Height < 600 || viewport.Height > 2160 {
+			p.addf("%s: %dx%d is not a desktop screen size (800x600 up to 3840x2160)", path, viewport.Width, viewport.Height)
+		} else if ratio := float64(viewport.Width) / float64(viewport.Height); ratio < 1.25 || ratio > 2.4 {
+			// Between 5:4 monitors and 21:9 ultrawides; 1920x768 is no screen anyone has
+			p.addf("%s: %dx%d has no realistic aspect ratio (5:4 up to 21:9)", path, viewport.Width, viewport.Height)
+		}
+		if viewport.DeviceScaleFactor < 1 || viewport.DeviceScaleFactor > 3 {
+			p.addf("%s.device_scale_factor must be between 1 and 3", path)
+		}
+		if labels[viewport.Label] {
+			p.addf("%s.label: duplicate label %q", path, viewport.Label)
+		}
+		labels[viewport.Label] = true
+	}
+
+	for i, hardware := range browser.Hardware {
+		path := fmt.Sprintf("browser.hardware[%d]", i)
+		if hardware.Platform != "" && !slices.Contains(HardwarePlatforms, hardware.Platform) {
+			p.addf("%s.platform must be empty or one of %s", path, strings.Join(HardwarePlatforms, ", "))
+		}
+		if hardware.WebGLVendor == "" || hardware.WebGLRenderer == "" {
+			p.addf("%s: webgl_vendor and webgl_renderer must be set", path)
+		}
+		if hardware.HardwareConcurrency < 1 || hardware.HardwareConcurrency > 64 {
+			p.addf("%s.hardware_concurrency must be between 1 and 64", path)
+		}
+		// Browsers only report these steps, capped at 8
+		if !slices.Contains([]float64{0.25, 0.5, 1, 2, 4, 8}, hardware.DeviceMemory) {
+			p.addf("%s.device_memory must be one of 0.25, 0.5, 1, 2, 4, 8", path)
+		}
+	}
+}
+
+// validateWeekdayOverrides checks weekday names and limits, and that some day stays active
+func validateWeekdayOverrides(p *problems, config *Config) {
+	scheduling := &config.Stealth.Scheduling
+	seen := make(map[time.Weekday]string)
+	for name, override := range scheduling.WeekdayOverrides {
+		path := "stealth.scheduling.weekday_overrides." + name
+		day, ok := weekdayNames[strings.ToLower(name)]
+		if !ok {
+			p.addf("%s: unknown weekday %q", path, name)
+			continue
+		}
+		if other, dup := seen[day]; dup {
+			p.addf("%s: %q and %q name the same weekday", path, other, name)
+		}
+		seen[day] = name
+
+		if override.DailyLimit < 0 {
+			p.addf("%s.daily_limit must not be negative", path)
+		}
+		if weekly := config.Connections.WeeklyLimit; weekly > 0 && override.DailyLimit > weekly {
+			p.addf("%s.daily_limit (%d) must not exceed connections.weekly_limit (%d)", path, override.DailyLimit, weekly)
+		}
+	}
+
+	active := scheduling.ActiveDays()
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if on, ok := active[day]; ok {
+			if on {
+				return
+			}
+			continue
+		}
+		if scheduling.WeekendActivity || (day != time.Saturday && day != time.Sunday) {
+			return
+		}
+	}
+	p.addf("stealth.scheduling.weekday_overrides leave no active day")
+}
+
+// validateSelection checks a template selection mode
+func validateSelection(p *problems, path, selection string) {
+	if selection != "uniform" && selection != "weighted" {
+		p.addf("%s must be uniform or weighted", path)
+	}
+}
+
+// validateTemplates checks template weights and that every template renders in the locale
+func validateTemplates(p *problems, path string, templates []Template, locale string) {
+	if _, err := render.ParseLocale(locale); err != nil {
+		p.addf("%s: %v", path, err)
+		return
+	}
+
+	for i, t := range templates {
+		if t.Weight < 0 {
+			p.addf("%s[%d] has a negative weight", path, i)
+		}
+		if err := render.Check(t.Text, locale); err != nil {
+			p.addf("%s[%d]: %v", path, i, err)
+		}
+	}
+}
+
+// validateSources checks that every source is a group, event or company URL with a usable cap
+func validateSources(p *problems, path string, sources []SourceConfig) {
+	for i, source := range sources {
+		if !strings.Contains(source.URL, "/groups/") && !strings.Contains(source.URL, "/events/") && !source.IsCompany() {
+			p.addf("%s[%d].url: %q is not a LinkedIn group, event or company URL", path, i, source.URL)
+		}
+		if source.Max < 0 {
+			p.addf("%s[%d].max must not be negative", path, i)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// defaultConfig returns the shipped configuration, which is valid
+func defaultConfig(t *testing.T) *Config {
+	t.Helper()
+	cfg, err := Default()
+	if err != nil {
+		t.Fatalf("Default: %v", err)
+	}
+	return cfg
+}
+
+func TestValidate(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		change func(*Config)
+		want   string // the problem expected, from its YAML path on; "" for none
+	}{
+		{"default", func(c *Config) {}, ""},
+		{"wpm min over max", func(c *Config) { c.Stealth.Typing.WPMMin, c.Stealth.Typing.WPMMax = 90, 60 },
+			"stealth.typing.wpm_min (90) must not exceed stealth.typing.wpm_max (60)"},
+		{"negative cooldown", func(c *Config) { c.Connections.CooldownBetweenRequestsMin = -1 },
+			"connections.cooldown_between_requests_min and connections.cooldown_between_requests_max must not be negative"},
+		{"probability over 1", func(c *Config) { c.Stealth.Mouse.OvershootProbability = 1.5 },
+			"stealth.mouse.overshoot_probability must be between 0 and 1"},
+		{"negative probability", func(c *Config) { c.Stealth.Scheduling.BreakProbability = -0.1 },
+			"stealth.scheduling.break_probability must be between 0 and 1"},
+		{"inverted business hours", func(c *Config) {
+			c.Stealth.Scheduling.BusinessHoursStart, c.Stealth.Scheduling.BusinessHoursEnd = 17, 9
+		}, "stealth.scheduling business hours must satisfy"},
+		{"empty business hours", func(c *Config) {
+			c.Stealth.Scheduling.BusinessHoursStart, c.Stealth.Scheduling.BusinessHoursEnd = 9, 9
+		}, "stealth.scheduling business hours must satisfy"},
+		{"acceptance message without templates", func(c *Config) {
+			c.Messaging.AcceptanceMessage.Enabled = true
+			c.Messaging.Templates = nil
+		}, "messaging.templates must not be empty when messaging.acceptance_message is enabled"},
+		{"InMail without templates", func(c *Config) {
+			c.Messaging.InMail.Enabled = true
+			c.Messaging.InMail.DailyLimit = 5
+			c.Messaging.InMail.Subjects = []Template{{Text: "Hello {{firstName}}"}}
+			c.Messaging.InMail.Templates = nil
+		}, "messaging.inmail.templates must not be empty when InMail is enabled"},
+		{"negative captcha timeout", func(c *Config) {
+			c.Captcha.Enabled = true
+			c.Captcha.APIURL = "https://solver.example.com"
+			c.Captcha.MaxDailyCost = 1
+			c.Captcha.TimeoutSeconds = -1
+		}, "captcha.timeout_seconds and captcha.poll_interval_seconds must not be negative"},
+		{"campaign notes without a limit", func(c *Config) {
+			c.Connections.NoteTemplates = nil
+			c.Connections.NoteCharacterLimit = 3
+			c.Campaigns = []CampaignConfig{{Name: "founders", BudgetShare: 1, NoteTemplates: []Template{{Text: "Hi {{firstName}}"}}}}
+		}, "connections.note_character_limit must be greater than 3 when note templates are set"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig(t)
+			tt.change(cfg)
+			err := cfg.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Validate = %v, want nil", err)
+				}
+				return
+			}
+
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Validate = %v, want a *ValidationError", err)
+			}
+			if len(verr.Problems) != 1 || !strings.HasPrefix(verr.Problems[0], tt.want) {
+				t.Fatalf("problems = %q, want one starting %q", verr.Problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateCollectsEveryProblem(t *testing.T) {
+	cfg := defaultConfig(t)
+	cfg.Connections.DailyLimit = 0
+	cfg.Stealth.Typing.WPMMin, cfg.Stealth.Typing.WPMMax = 90, 60
+	cfg.Stealth.Typing.TypoProbability = 2
+	cfg.Stealth.Scheduling.BusinessHoursStart, cfg.Stealth.Scheduling.BusinessHoursEnd = 18, 8
+
+	var verr *ValidationError
+	if err := cfg.Validate(); !errors.As(err, &verr) {
+		t.Fatalf("Validate = %v, want a *ValidationError", err)
+	}
+	want := []string{"connections.daily_limit", "stealth.typing.wpm_min", "stealth.typing.typo_probability", "stealth.scheduling"}
+	if len(verr.Problems) != len(want) {
+		t.Fatalf("problems = %q, want %d", verr.Problems, len(want))
+	}
+	for i, path := range want {
+		if !strings.HasPrefix(verr.Problems[i], path) {
+			t.Errorf("problem %d = %q, want it to start with %s", i, verr.Problems[i], path)
+		}
+	}
+	if got := verr.Error(); got != strings.Join(verr.Problems, "; ") {
+		t.Fatalf("Error() = %q, want the problems on one line", got)
+	}
+}
+
+func TestValidateNetworkFilters(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		filters Filters
+		want    string // part of the one problem expected; "" for none
+	}{
+		{"2nd and 3rd+", Filters{NetworkDepth: []string{"S", "O"}}, ""},
+		{"connections of a member", Filters{NetworkDepth: []string{"S"}, ConnectionOf: []string{"ACoAAB12cd"}}, ""},
+		{"member by URN and URL", Filters{ConnectionOf: []string{"urn:li:fsd_profile:ACoAAB12cd", "https://www.linkedin.com/in/ACoAAC34ef"}}, ""},
+		{"unknown depth", Filters{NetworkDepth: []string{"S", "2nd"}}, `network_depth[1]: unknown depth "2nd"`},
+		{"duplicate depth", Filters{NetworkDepth: []string{"S", "S"}}, `network_depth[1]: duplicate depth "S"`},
+		{"1st alone", Filters{NetworkDepth: []string{"F"}}, "F alone only finds existing connections"},
+		{"vanity URL", Filters{ConnectionOf: []string{"https://www.linkedin.com/in/ada-lovelace/"}}, "connection_of[0]"},
+		{"connections of a member at 3rd+", Filters{NetworkDepth: []string{"O"}, ConnectionOf: []string{"ACoAAB12cd"}}, "1st and 2nd degree members only"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var p problems
+			validateNetworkFilters(&p, "search.filters", tt.filters)
+			if tt.want == "" {
+				if len(p) != 0 {
+					t.Fatalf("problems = %q, want none", p)
+				}
+				return
+			}
+			if len(p) != 1 || !strings.Contains(p[0], tt.want) {
+				t.Fatalf("problems = %q, want one about %q", p, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSearchProviderNetworkFilters(t *testing.T) {
+	filters := Filters{NetworkDepth: []string{"S"}}
+
+	var standard, salesNavigator problems
+	validateSearchProvider(&standard, "search.filters", "standard", filters)
+	validateSearchProvider(&salesNavigator, "search.filters", "sales_navigator", filters)
+	if len(standard) != 0 || len(salesNavigator) != 1 {
+		t.Fatalf("standard problems %q, Sales Navigator problems %q; want one under Sales Navigator only", standard, salesNavigator)
+	}
+}
+
+func TestFiltersMaxDegree(t *testing.T) {
+	for _, tt := range []struct {
+		depths []string
+		want   int
+	}{
+		{nil, 0},
+		{[]string{"F"}, 1},
+		{[]string{"S"}, 2},
+		{[]string{"S", "F"}, 2},
+		{[]string{"O"}, 0},
+		{[]string{"F", "S", "O"}, 0},
+	} {
+		if got := (Filters{NetworkDepth: tt.depths}).MaxDegree(); got != tt.want {
+			t.Errorf("MaxDegree(%v) = %d, want %d", tt.depths, got, tt.want)
+		}
+	}
+}
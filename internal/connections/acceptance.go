@@ -0,0 +1,107 @@
+package connections
+
+import (
+	"fmt"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/profileurl"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+const connectionsListURL = "https://www.linkedin.com/mynetwork/invite-connect/connections/"
+
+// AcceptancePoller detects pending connection requests that have been accepted
+type AcceptancePoller struct {
+	page     pageops.Page
+	db       *storage.DB
+	timing   *stealth.TimingController
+	scroller pageops.Scroller
+}
+
+// NewAcceptancePoller creates a new acceptance poller
+func NewAcceptancePoller(page pageops.Page, db *storage.DB, timing *stealth.TimingController, scroller pageops.Scroller) *AcceptancePoller {
+	return &AcceptancePoller{
+		page:     page,
+		db:       db,
+		timing:   timing,
+		scroller: scroller,
+	}
+}
+
+// Poll checks the recent connections list and marks newly accepted requests.
+// It returns the requests that were accepted since the last poll.
+func (ap *AcceptancePoller) Poll() ([]storage.ConnectionRequest, error) {
+	pending, err := ap.db.GetConnectionRequestsByStatus("pending")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending requests: %w", err)
+	}
+
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	logger.Infof("Checking %d pending connection requests for acceptance", len(pending))
+
+	if err := ap.page.Navigate(connectionsListURL); err != nil {
+		return nil, fmt.Errorf("failed to navigate to connections: %w", err)
+	}
+
+	if err := ap.page.WaitLoad(); err != nil {
+		logger.Warnf("Connections page load wait failed: %v", err)
+	}
+
+	ap.timing.Wait(ap.timing.ThinkTime())
+
+	if err := ap.scroller.ScrollDown(1200); err != nil {
+		logger.Warnf("Failed to scroll: %v", err)
+	}
+
+	connected, err := ap.recentConnections()
+	if err != nil {
+		return nil, err
+	}
+
+	var accepted []storage.ConnectionRequest
+	for _, req := range pending {
+		if !connected[profileurl.Normalize(req.ProfileURL)] {
+			continue
+		}
+
+		if err := ap.db.UpdateConnectionStatus(req.ProfileURL, "accepted"); err != nil {
+			logger.Errorf("Failed to mark request accepted: %v", err)
+			continue
+		}
+
+		logger.Infof("Connection request accepted by %s", req.ProfileName)
+		ap.db.Audit(storage.AuditEntry{Action: "connection_accepted", ProfileURL: req.ProfileURL, Details: req.ProfileName,
+			Result: storage.AuditSuccess, PageURL: pageops.CurrentURL(ap.page)})
+		req.Status = "accepted"
+		accepted = append(accepted, req)
+	}
+
+	return accepted, nil
+}
+
+// recentConnections returns the normalized profile URLs listed on the connections page
+func (ap *AcceptancePoller) recentConnections() (map[string]bool, error) {
+	connected := make(map[string]bool)
+
+	links, err := selectors.FindAll(ap.page, selectors.ConnectionCardLink)
+	if err != nil {
+		logger.Warnf("No connection cards found: %v", err)
+		return connected, nil
+	}
+
+	for _, link := range links {
+		href, err := link.Property("href")
+		if err != nil {
+			continue
+		}
+		connected[profileurl.Normalize(href)] = true
+	}
+
+	return connected, nil
+}
@@ -1,269 +1,929 @@
 package connections
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
 	"strings"
 	"time"
 
-	"github.com/go-rod/rod"
-
+	"github.com/Tanukumar01/linkedin-automation/internal/artifacts"
+	"github.com/Tanukumar01/linkedin-automation/internal/budget"
+	"github.com/Tanukumar01/linkedin-automation/internal/clock"
 	"github.com/Tanukumar01/linkedin-automation/internal/config"
 	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/notify"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/profiles"
+	"github.com/Tanukumar01/linkedin-automation/internal/profileurl"
+	"github.com/Tanukumar01/linkedin-automation/internal/render"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
 	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
 	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+	"github.com/Tanukumar01/linkedin-automation/internal/templates"
+	"go.uber.org/zap"
 )
 
 // ConnectionManager handles connection requests
 type ConnectionManager struct {
-	page     *rod.Page
-	config   *config.ConnectionsConfig
-	db       *storage.DB
-	timing   *stealth.TimingController
-	typer    *stealth.Typer
-	mouse    *stealth.MouseMover
-	scroller *stealth.Scroller
-	rand     *rand.Rand
+	page       pageops.Page
+	config     *config.ConnectionsConfig
+	db         *storage.DB
+	timing     *stealth.TimingController
+	typer      pageops.TextTyper
+	clicker    pageops.Clicker
+	scroller   pageops.Scroller
+	rand       *rand.Rand
+	clock      clock.Clock
+	notifier   notify.Notifier
+	artifacts  *artifacts.Collector
+	actions    *budget.DailyBudget // the day's action budget invites take from; nil for none
+	campaign   string
+	dailyLimit func(time.Time) int
+	locale     string
+	reading    string // how thoroughly a profile is read before connecting
+	layout     string // the open profile's layout, detected on each load
+	flow       string // the open invite's flow variant, detected after each Connect click
+	log        *zap.SugaredLogger
+
+	reviewer   Reviewer // approves invites before they are sent; nil sends them unreviewed
+	reviewHold func() (resume func())
+
+	ensureLoggedIn func() error // signs in again after the sign-in wall; nil fails on it
+
+	limitNotified bool
+
+	// notesLocked caches whether LinkedIn locked the note field earlier this month
+	notesLocked  bool
+	notesChecked bool
+}
+
+// ErrDailyLimitReached is returned once the daily connection limit has been used up
+var ErrDailyLimitReached = errors.New("daily connection limit reached")
+
+// ErrWeeklyLimitReached is returned once the configured weekly connection limit has been used up
+var ErrWeeklyLimitReached = errors.New("weekly connection limit reached")
+
+// ErrHourlyLimitReached is returned once the configured hourly connection limit has been used up
+var ErrHourlyLimitReached = errors.New("hourly connection limit reached")
+
+// ErrUnsupportedFlow is returned when LinkedIn presents an invite flow we can't drive
+var ErrUnsupportedFlow = errors.New("unsupported invite flow")
+
+// ErrRestricted is returned when LinkedIn shows an invitation restriction
+var ErrRestricted = errors.New("invitation restriction detected")
+
+// ErrNotConfirmed is returned when LinkedIn doesn't confirm an invite after Send was clicked
+var ErrNotConfirmed = errors.New("invite not confirmed")
+
+// elementWait bounds how long to wait for dialog controls to render
+const elementWait = 10 * time.Second
+
+// verifyInterval is how often the page is checked while confirming a sent invite
+const verifyInterval = 500 * time.Millisecond
+
+// Invite flow variants
+const (
+	FlowModal       = "modal"        // desktop modal with optional "Add a note"
+	FlowBottomSheet = "bottom_sheet" // simplified "lite" flow shown to mobile clients
+)
+
+// Request outcomes
+const (
+	OutcomeSent    = "sent"
+	OutcomeSkipped = "skipped"
+)
+
+// ReasonFollowOnly is the skip reason of a profile that offers Follow instead of Connect
+const ReasonFollowOnly = "follow only"
+
+// ReasonSkippedOrganic is the skip reason of a profile visited and left without connecting
+const ReasonSkippedOrganic = "skipped_organic"
+
+// Note statuses of requests sent without a note
+const (
+	NoteDeniedUpsell = "note_denied_upsell" // LinkedIn locked the note field behind a Premium upsell
+	NoteOptedOut     = "note_opted_out"     // connections.note_mode left the prospect without a note
+	NoteBudgetUsed   = "note_budget_used"   // this month's connections.monthly_note_budget was used up
+	NoteFailed       = "note_failed"        // typing the note failed, so the invite went out blank
+)
+
+// RequestResult describes what happened to a single connection attempt
+type RequestResult struct {
+	ProfileURL  string
+	ProfileName string
+	Outcome     string
+	Reason      string // why the profile was skipped
+	Note        string
+	TemplateID  string
 }
 
 // NewConnectionManager creates a new connection manager
-func NewConnectionManager(page *rod.Page, cfg *config.ConnectionsConfig, db *storage.DB, timing *stealth.TimingController, typer *stealth.Typer, mouse *stealth.MouseMover, scroller *stealth.Scroller) *ConnectionManager {
+func NewConnectionManager(page pageops.Page, cfg *config.ConnectionsConfig, db *storage.DB, timing *stealth.TimingController, typer pageops.TextTyper, clicker pageops.Clicker, scroller pageops.Scroller) *ConnectionManager {
 	return &ConnectionManager{
 		page:     page,
 		config:   cfg,
 		db:       db,
 		timing:   timing,
 		typer:    typer,
-		mouse:    mouse,
+		clicker:  clicker,
 		scroller: scroller,
 		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		clock:    clock.Real{},
+		notifier: notify.Nop{},
+		campaign: config.DefaultCampaign,
+		locale:   render.DefaultLocale,
+		log:      logger.With(),
+	}
+}
+
+// SetLocale sets the locale template helpers format numbers, money and dates for
+func (cm *ConnectionManager) SetLocale(locale string) {
+	cm.locale = locale
+}
+
+// SetRand sets the random source behind the organic skip decisions, so a seeded source
+// makes them repeatable
+func (cm *ConnectionManager) SetRand(r *rand.Rand) {
+	cm.rand = r
+}
+
+// SetClock sets the clock the limits and note budget are counted by
+func (cm *ConnectionManager) SetClock(c clock.Clock) {
+	cm.clock = c
+}
+
+// SetCampaign sets the campaign that sent requests are attributed to
+func (cm *ConnectionManager) SetCampaign(name string) {
+	cm.campaign = name
+}
+
+// SetDailyLimitFunc sets how the daily limit is derived for a given day, e.g. from weekday overrides
+func (cm *ConnectionManager) SetDailyLimitFunc(fn func(time.Time) int) {
+	cm.dailyLimit = fn
+}
+
+// SetNotifier sets the notifier used to report limits and restrictions
+func (cm *ConnectionManager) SetNotifier(n notify.Notifier) {
+	cm.notifier = n
+}
+
+// SetArtifacts sets the collector that captures the page when a request fails
+func (cm *ConnectionManager) SetArtifacts(c *artifacts.Collector) {
+	cm.artifacts = c
+}
+
+// SetActionBudget sets the day's action budget each invite takes from as it is sent
+func (cm *ConnectionManager) SetActionBudget(b *budget.DailyBudget) {
+	cm.actions = b
+}
+
+// SetLogger sets the logger the manager writes to, e.g. one carrying the run's fields
+func (cm *ConnectionManager) SetLogger(l *zap.SugaredLogger) {
+	cm.log = l
+}
+
+// forProfile makes every line the manager logs carry the profile and action until
+// the returned func restores its logger
+func (cm *ConnectionManager) forProfile(profileURL, profileName, action string) (restore func()) {
+	base := cm.log
+	cm.log = base.With(logger.ProfileFields(profileURL, profileName, action)...)
+	return func() { cm.log = base }
+}
+
+// SendConnectionRequest sends a connection request to a profile. priority marks a prospect
+// worth a note when connections.note_mode is priority.
+func (cm *ConnectionManager) SendConnectionRequest(profileURL, profileName, jobTitle, company string, priority bool) (*RequestResult, error) {
+	started := time.Now()
+
+	// A stuck page fails its next operation once the profile's budget is used, rather than holding up the run
+	timeout := cm.config.ProfileTimeout()
+	release := pageops.WithDeadline(cm.page, timeout)
+	result, err := cm.sendConnectionRequest(profileURL, profileName, jobTitle, company, priority, started)
+	release()
+	err = pageops.TimedOut(err, timeout)
+
+	// Whatever happened, the next profile starts without a dialog in the way
+	cm.closeDialogs()
+
+	// Sent and skipped requests are audited where they're recorded; limits stop the run before any
+	// action, and the sign-in wall is audited as a session problem rather than the profile's
+	if err != nil && !errors.Is(err, ErrDailyLimitReached) && !errors.Is(err, ErrWeeklyLimitReached) && !errors.Is(err, ErrHourlyLimitReached) && !errors.Is(err, budget.ErrExhausted) && !errors.Is(err, pageops.ErrAuthwall) {
+		outcome := storage.AuditFailed
+		if errors.Is(err, pageops.ErrTimeout) {
+			outcome = storage.AuditTimeout
+		}
+		cm.db.Audit(storage.AuditEntry{Action: "connection_failed", ProfileURL: profileURL, Details: profileName,
+			Result: outcome, Err: err, PageURL: pageops.CurrentURL(cm.page), Started: started})
 	}
+	return result, err
 }
 
-// SendConnectionRequest sends a connection request to a profile
-func (cm *ConnectionManager) SendConnectionRequest(profileURL, profileName, jobTitle, company string) error {
-	logger.Infof("Sending connection request to: %s", profileName)
+// sendConnectionRequest is SendConnectionRequest before the audit of failures
+func (cm *ConnectionManager) sendConnectionRequest(profileURL, profileName, jobTitle, company string, priority bool, started time.Time) (*RequestResult, error) {
+	defer cm.forProfile(profileURL, profileName, "connect")()
+	cm.log.Infof("Sending connection request to: %s", profileName)
+
+	result := &RequestResult{
+		ProfileURL:  profileURL,
+		ProfileName: profileName,
+	}
 
 	// Check daily limit
 	if err := cm.checkDailyLimit(); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Check if already contacted
-	contacted, err := cm.db.IsProfileContacted(profileURL)
+	memberID := profileurl.MemberID(profileURL)
+	contacted, err := cm.db.IsProfileContacted(profileURL, memberID)
 	if err != nil {
-		return fmt.Errorf("failed to check if profile contacted: %w", err)
+		return nil, fmt.Errorf("failed to check if profile contacted: %w", err)
 	}
 
 	if contacted {
-		logger.Infof("Profile already contacted: %s", profileName)
-		return nil
+		cm.log.Infof("Profile already contacted: %s", profileName)
+		result.Outcome = OutcomeSkipped
+		result.Reason = "already contacted"
+		return result, nil
 	}
 
 	// Navigate to profile
-	if err := cm.page.Navigate(profileURL); err != nil {
-		return fmt.Errorf("failed to navigate to profile: %w", err)
+	notFound, err := cm.loadProfile(profileURL, started)
+	if err != nil {
+		return nil, err
 	}
-
-	if err := cm.page.WaitLoad(); err != nil {
-		return fmt.Errorf("failed to wait for profile page: %w", err)
+	if notFound {
+		cm.log.Infof("Profile %s was deleted or doesn't exist, skipping it for good", profileName)
+		cm.recordUnavailable(profileURL, profileName, started)
+		result.Outcome = OutcomeSkipped
+		result.Reason = ReasonUnavailable
+		return result, nil
 	}
 
 	cm.timing.Wait(cm.timing.ThinkTime())
+	cm.layout = selectors.DetectLayout(cm.page)
+
+	// The page names the member, who may have been contacted under another URL
+	if id := pageops.MemberID(cm.page); id != "" {
+		memberID = id
+		if cm.contactedAsMember(profileURL, memberID) {
+			cm.log.Infof("Profile already contacted under another URL: %s", profileName)
+			result.Outcome = OutcomeSkipped
+			result.Reason = "already contacted"
+			return result, nil
+		}
+	}
 
-	// Scroll to view profile
-	if err := cm.scroller.ScrollDown(cm.page, 300); err != nil {
-		logger.Warnf("Failed to scroll: %v", err)
+	// Make sure we have a usable name before personalizing anything
+	profileName, resolution := cm.resolveProfileName(profileName)
+	if resolution == NameSkipped {
+		cm.log.Warnf("Skipping profile with unusable name: %s", profileURL)
+		cm.recordSkip(profileURL, profileName, jobTitle, company)
+		result.Outcome = OutcomeSkipped
+		result.Reason = "unusable profile name"
+		return result, nil
+	}
+	result.ProfileName = profileName
+
+	switch state := cm.detectProfileState(); state {
+	case ProfilePending, ProfileConnected:
+		// An earlier run may have sent the request without recording it
+		cm.log.Infof("Profile %s is already %s, recording instead of sending", profileName, state)
+		cm.recordExisting(profileURL, profileName, state)
+		result.Outcome = OutcomeSkipped
+		result.Reason = "already " + state + " on LinkedIn"
+		return result, nil
+	case ProfileFollowOnly:
+		// Looking for a Connect button that isn't there would only burn a retry
+		cm.log.Infof("Profile %s only offers Follow, skipping", profileName)
+		if err := cm.db.MarkProfileContacted(profileURL); err != nil {
+			cm.log.Errorf("Failed to mark profile as contacted: %v", err)
+		}
+		cm.db.Audit(storage.AuditEntry{Action: "connection_skipped", ProfileURL: profileURL, Details: "Follow only",
+			Result: storage.AuditSkipped, PageURL: pageops.CurrentURL(cm.page), Started: started})
+		result.Outcome = OutcomeSkipped
+		result.Reason = ReasonFollowOnly
+		return result, nil
 	}
 
-	cm.timing.Wait(cm.timing.ShortPause())
+	// Read the profile as someone deciding whether to connect would
+	cm.readProfile()
+
+	if cm.skipOrganically(profileURL, profileName, started) {
+		result.Outcome = OutcomeSkipped
+		result.Reason = ReasonSkippedOrganic
+		return result, nil
+	}
 
 	// Find Connect button
 	connectButton, err := cm.findConnectButton()
 	if err != nil {
-		return fmt.Errorf("failed to find connect button: %w", err)
+		return nil, cm.captureFailure(fmt.Errorf("failed to find connect button: %w", err))
 	}
 
 	// Click Connect button with human-like mouse movement
-	if err := cm.mouse.ClickElement(connectButton); err != nil {
-		return fmt.Errorf("failed to click connect button: %w", err)
+	pageops.DismissInterruptions(cm.page, cm.clicker)
+	if err := cm.clicker.Click(connectButton); err != nil {
+		return nil, cm.captureFailure(fmt.Errorf("failed to click connect button: %w", err))
 	}
 
 	cm.timing.Wait(cm.timing.ShortPause())
 
+	// The simplified bottom-sheet flow has controls of its own; a sheet without any we
+	// know would fail every profile the same way
+	flow := cm.detectInviteFlow()
+	cm.flow = flow
+	if flow == FlowBottomSheet && !selectors.Has(cm.page, selectors.SheetSendButton) && !selectors.Has(cm.page, selectors.SheetAddNoteButton) {
+		return nil, cm.captureFailure(fmt.Errorf("%w: %s without Send or Add a note", ErrUnsupportedFlow, flow))
+	}
+
+	// Some accounts see LinkedIn's own count of the invitations left this week
+	if cm.readInviteCounter() {
+		return nil, cm.limitReached(fmt.Errorf("%w (LinkedIn reports none left)", ErrWeeklyLimitReached))
+	}
+
 	// Check if "Add a note" option is available
 	hasNoteOption := cm.hasAddNoteOption()
 
-	var note string
+	// Once the free notes are used up this month, send straight away without opening the note field
+	if hasNoteOption && cm.notesLockedThisMonth() {
+		cm.log.Debugf("Notes are locked this month, sending without a note")
+		hasNoteOption = false
+	}
+
+	var note, templateID, noteStatus string
+	if hasNoteOption {
+		if reason := cm.skipNote(priority); reason != "" {
+			cm.log.Infof("Sending without a note (%s)", reason)
+			noteStatus = reason
+			hasNoteOption = false
+		}
+	}
+
+	noteUsed, reviewed := false, false
+	send := cm.clickSendButton
 	if hasNoteOption {
 		// Click "Add a note" button
 		if err := cm.clickAddNoteButton(); err != nil {
-			logger.Warnf("Failed to click add note button: %v", err)
+			cm.log.Warnf("Failed to click add note button: %v", err)
 		} else {
 			cm.timing.Wait(cm.timing.ShortPause())
 
-			// Generate personalized note
-			note = cm.generateNote(profileName, jobTitle, company)
-
-			// Type note
-			if err := cm.typeNote(note); err != nil {
-				logger.Warnf("Failed to type note: %v", err)
+			if cm.noteLocked() {
+				// Free accounts past the monthly note allowance see the field disabled behind an upsell
+				cm.log.Infof("Note field is locked behind a Premium upsell, sending without a note")
+				noteStatus = NoteDeniedUpsell
+				cm.notesLocked = true
+				if !cm.sendButtonEnabled() {
+					send = cm.sendWithoutNote
+				}
+			} else {
+				// Generate personalized note
+				note, templateID = cm.generateNote(profileURL, profileName, jobTitle, company, resolution == NameFallback)
+
+				if err := lintNote(note); err != nil {
+					cm.log.Warnf("Note failed lint, sending without note: %v", err)
+					note, templateID = "", ""
+				}
+
+				// The operator may edit the note before it is typed; an edited note is no
+				// longer the template's
+				edited, verdict := cm.review(InviteReview{ProfileURL: profileURL, ProfileName: profileName,
+					Headline: jobTitle, Company: company, Note: note, NoteEditable: true})
+				if verdict != ReviewApproved {
+					return cm.declineReview(result, verdict, started), nil
+				}
+				if edited != note {
+					note, templateID = edited, ""
+				}
+				reviewed = true
+
+				// Type note; a note typed in part is thrown away with the dialog, and the
+				// invite goes out blank rather than malformed
+				if note != "" {
+					if err := cm.typeNote(note); err != nil {
+						cm.log.Warnf("Failed to type note, sending without one: %v", err)
+						note, templateID = "", ""
+						noteStatus = NoteFailed
+						send = cm.sendWithoutNote
+					} else {
+						noteUsed = true
+					}
+				}
 			}
 
 			cm.timing.Wait(cm.timing.ThinkTime())
 		}
 	}
 
-	// Click Send button
-	if err := cm.clickSendButton(); err != nil {
-		return fmt.Errorf("failed to click send button: %w", err)
+	if !reviewed {
+		if _, verdict := cm.review(InviteReview{ProfileURL: profileURL, ProfileName: profileName,
+			Headline: jobTitle, Company: company}); verdict != ReviewApproved {
+			return cm.declineReview(result, verdict, started), nil
+		}
 	}
 
-	logger.Infof("Connection request sent to: %s", profileName)
+	// Taken last, so a profile skipped or declined on the way doesn't spend it, and given
+	// back unless LinkedIn confirms the invite, so failures don't starve the other action types
+	if err := cm.actions.Take(config.ActionInvite); err != nil {
+		return nil, err
+	}
+	confirmed := false
+	defer func() {
+		if !confirmed {
+			if err := cm.actions.Refund(config.ActionInvite); err != nil {
+				cm.log.Warnf("Failed to refund the action budget: %v", err)
+			}
+		}
+	}()
 
-	// Save to database
+	// Write the row before clicking so a crash between the click and the save can be reconciled
 	request := &storage.ConnectionRequest{
-		ProfileURL:  profileURL,
-		ProfileName: profileName,
-		JobTitle:    jobTitle,
-		Company:     company,
-		Note:        note,
-		Status:      "pending",
-		SentAt:      time.Now(),
-		UpdatedAt:   time.Now(),
+		ProfileURL:     profileURL,
+		MemberID:       memberID,
+		ProfileName:    profileName,
+		JobTitle:       jobTitle,
+		Company:        company,
+		Note:           note,
+		NoteUsed:       noteUsed,
+		Status:         StatusSending,
+		NameResolution: resolution,
+		TemplateID:     templateID,
+		FlowVariant:    flow,
+		NoteStatus:     noteStatus,
+		Campaign:       cm.campaign,
+		SentAt:         cm.clock.Now(),
+		UpdatedAt:      cm.clock.Now(),
 	}
 
 	if err := cm.db.SaveConnectionRequest(request); err != nil {
-		logger.Errorf("Failed to save connection request: %v", err)
+		return nil, fmt.Errorf("failed to save connection request: %w", err)
+	}
+
+	// Click Send button
+	if err := send(); err != nil {
+		cm.discardWriteAhead(request)
+		return nil, cm.captureFailure(fmt.Errorf("failed to click send button: %w", err))
+	}
+
+	cm.timing.Wait(cm.timing.ShortPause())
+
+	// LinkedIn replaces the modal with a limit notice instead of sending
+	if restriction := cm.detectRestriction(); restriction != "" {
+		cm.discardWriteAhead(request)
+		cm.notify(notify.EventRestriction, restriction)
+		return nil, cm.captureFailure(fmt.Errorf("%w: %s", ErrRestricted, restriction))
 	}
 
-	// Mark profile as contacted
-	if err := cm.db.MarkProfileContacted(profileURL); err != nil {
-		logger.Errorf("Failed to mark profile as contacted: %v", err)
+	// A click that went through can still be rejected silently
+	if err := cm.verifySent(); err != nil {
+		if err := cm.db.SetConnectionRequestFailed(request.ID, err.Error()); err != nil {
+			cm.log.Errorf("Failed to record failed connection request: %v", err)
+		}
+		return nil, cm.captureFailure(err)
 	}
+	confirmed = true
 
-	// Log activity
-	cm.db.LogActivity("connection_request", fmt.Sprintf("Sent to %s", profileName))
+	cm.log.Infof("Connection request sent to: %s", profileName)
+
+	// Finalize the request, mark the profile contacted and log it together; should this
+	// fail, the request stays in the sending state and is reconciled on the next start
+	err = cm.db.WithTx(func(tx *storage.DB) error {
+		if err := tx.SetConnectionRequestStatus(request.ID, "pending"); err != nil {
+			return fmt.Errorf("failed to finalize connection request: %w", err)
+		}
+		if err := tx.MarkProfileContacted(profileURL); err != nil {
+			return fmt.Errorf("failed to mark profile as contacted: %w", err)
+		}
+		return tx.Audit(storage.AuditEntry{Action: "connection_request", ProfileURL: profileURL, Details: fmt.Sprintf("Sent to %s", profileName),
+			Result: storage.AuditSuccess, PageURL: pageops.CurrentURL(cm.page), Started: started})
+	})
+	if err != nil {
+		cm.log.Errorf("Failed to record sent request: %v", err)
+	}
 
 	// Cooldown
 	cooldown := time.Duration(cm.config.CooldownBetweenRequestsMin+cm.rand.Intn(cm.config.CooldownBetweenRequestsMax-cm.config.CooldownBetweenRequestsMin+1)) * time.Second
+	cm.db.LogActivity(stealth.ActivityCooldown, cooldown.String())
 	cm.timing.Wait(cooldown)
 
-	return nil
+	result.Outcome = OutcomeSent
+	result.Note = note
+	result.TemplateID = templateID
+	return result, nil
 }
 
-// checkDailyLimit checks if daily connection limit has been reached
+// contactedAsMember stores memberID, read from the open profile, on the rows of profileURL
+// and reports whether the member was contacted under any URL. A profile found contacted
+// is marked so, keeping it out of later selections.
+func (cm *ConnectionManager) contactedAsMember(profileURL, memberID string) bool {
+	if err := cm.db.SetProfileMemberID(profileURL, memberID); err != nil {
+		cm.log.Warnf("Failed to store member ID: %v", err)
+	}
+
+	contacted, err := cm.db.IsProfileContacted(profileURL, memberID)
+	if err != nil {
+		cm.log.Warnf("Failed to check if member contacted: %v", err)
+		return false
+	}
+	if contacted {
+		if err := cm.db.MarkProfileContacted(profileURL); err != nil {
+			cm.log.Errorf("Failed to mark profile as contacted: %v", err)
+		}
+	}
+	return contacted
+}
+
+// captureFailure saves the page as a debug artifact and returns err unchanged
+func (cm *ConnectionManager) captureFailure(err error) error {
+	cm.artifacts.Capture(cm.page, "connect", err)
+	return err
+}
+
+// checkDailyLimit checks if today's connection limit, or the weekly or hourly one, has been reached
 func (cm *ConnectionManager) checkDailyLimit() error {
-	count, err := cm.db.GetConnectionRequestsCountByDate(time.Now())
+	now := cm.clock.Now()
+	limit := cm.config.DailyLimit
+	if cm.dailyLimit != nil {
+		limit = cm.dailyLimit(now)
+	}
+
+	count, err := cm.db.GetConnectionRequestsCountByDate(now)
 	if err != nil {
 		return fmt.Errorf("failed to get connection count: %w", err)
 	}
 
-	if count >= cm.config.DailyLimit {
-		return fmt.Errorf("daily connection limit reached (%d/%d)", count, cm.config.DailyLimit)
+	if count >= limit {
+		return cm.limitReached(fmt.Errorf("%w (%d/%d)", ErrDailyLimitReached, count, limit))
+	}
+
+	weekly, err := GetWeeklyAllowance(cm.db, cm.config.WeeklyLimit, now)
+	if err != nil {
+		return err
+	}
+	if weekly.Limit > 0 {
+		if weekly.Remaining == 0 {
+			return cm.limitReached(fmt.Errorf("%w (%s)", ErrWeeklyLimitReached, weekly))
+		}
+		cm.log.Infof("Weekly connections: %s", weekly)
+	}
+
+	// An hourly limit of 0 leaves the hour uncapped
+	if cm.config.HourlyLimit > 0 {
+		lastHour, err := cm.db.GetConnectionRequestsCountSince(now.Add(-time.Hour))
+		if err != nil {
+			return fmt.Errorf("failed to get hourly connection count: %w", err)
+		}
+		if lastHour >= cm.config.HourlyLimit {
+			return cm.limitReached(fmt.Errorf("%w (%d/%d)", ErrHourlyLimitReached, lastHour, cm.config.HourlyLimit))
+		}
 	}
 
-	logger.Infof("Daily connections: %d/%d", count, cm.config.DailyLimit)
+	cm.log.Infof("Daily connections: %d/%d", count, limit)
 	return nil
 }
 
-// findConnectButton finds the Connect button on the profile
-func (cm *ConnectionManager) findConnectButton() (*rod.Element, error) {
-	// Try different methods for Connect button
+// WeeklyRequestCount returns how many requests count against the weekly limit at now: those of the last 7 days
+func WeeklyRequestCount(db *storage.DB, now time.Time) (int, error) {
+	return db.GetConnectionRequestsCountSince(now.AddDate(0, 0, -7))
+}
+
+// limitReached notifies about the first limit hit in this run and returns err
+func (cm *ConnectionManager) limitReached(err error) error {
+	if !cm.limitNotified {
+		cm.limitNotified = true
+		cm.notify(notify.EventDailyLimit, err.Error())
+	}
+	return err
+}
 
-	// 1. Text-based search (most reliable)
-	if el, err := cm.page.ElementR("button", "(?i)^Connect$"); err == nil {
-		return el, nil
+// detectRestriction returns a description of any invitation restriction shown on the page
+func (cm *ConnectionManager) detectRestriction() string {
+	if selectors.Has(cm.page, selectors.InviteLimitAlert) {
+		return "weekly invitation limit reached"
 	}
 
-	// 2. Aria-label based search (often contains extra text like "Connect to Name")
-	if el, err := cm.page.Element("button[aria-label*='Connect']"); err == nil {
-		return el, nil
+	if el, err := selectors.FindFirst(cm.page, selectors.InviteLimitNotice); err == nil {
+		text, _ := el.Text()
+		return strings.TrimSpace(text)
 	}
 
-	// 3. Specific profile action area
-	if el, err := cm.page.Element(".pvs-profile-actions button"); err == nil {
-		if text, _ := el.Text(); strings.Contains(strings.ToLower(text), "connect") {
-			return el, nil
+	return ""
+}
+
+// verifySent waits for the invite dialog to close or the profile to show Pending.
+// An error toast, or the dialog still being open at the deadline, fails the request.
+// It waits on the manager's clock.
+func (cm *ConnectionManager) verifySent() error {
+	deadline := cm.clock.Now().Add(elementWait)
+	for {
+		if el, err := selectors.FindFirst(cm.page, selectors.ErrorToast); err == nil {
+			text, _ := el.Text()
+			return fmt.Errorf("%w: %s", ErrNotConfirmed, strings.TrimSpace(text))
+		}
+
+		if !selectors.Has(cm.page, cm.inviteChain(selectors.InviteModal)) || selectors.Has(cm.page, selectors.PendingButton) {
+			return nil
 		}
+
+		if cm.clock.Now().After(deadline) {
+			return fmt.Errorf("%w: invite dialog still open", ErrNotConfirmed)
+		}
+		cm.clock.Sleep(verifyInterval)
+	}
+}
+
+// closeDialogs presses Escape until no dialog is left open, dismissing the invite dialog
+// as a last resort, and warns when one stays
+func (cm *ConnectionManager) closeDialogs() {
+	escaper, canEscape := cm.page.(pageops.Escaper)
+	for attempt := 0; attempt < 3; attempt++ {
+		if !selectors.Has(cm.page, selectors.OpenDialog) {
+			return
+		}
+		if canEscape && attempt < 2 {
+			if err := escaper.PressEscape(); err != nil {
+				cm.log.Warnf("Failed to press Escape: %v", err)
+			}
+		} else if dismiss, err := cm.findDismissButton(); err == nil {
+			if err := cm.clicker.Click(dismiss); err != nil {
+				cm.log.Warnf("Failed to dismiss dialog: %v", err)
+			}
+		}
+		cm.timing.Wait(cm.timing.ShortPause())
+	}
+
+	if selectors.Has(cm.page, selectors.OpenDialog) {
+		cm.log.Warnf("A dialog is still open after connecting to the profile")
+	}
+}
+
+// findDismissButton finds the button that closes the invite modal or bottom sheet
+func (cm *ConnectionManager) findDismissButton() (pageops.Element, error) {
+	if dismiss, err := selectors.FindFirst(cm.page, selectors.InviteDismissButton); err == nil {
+		return dismiss, nil
+	}
+	return selectors.FindFirst(cm.page, selectors.SheetDismissButton)
+}
+
+// notify sends an event, logging rather than failing on delivery errors
+func (cm *ConnectionManager) notify(eventType, message string) {
+	if err := cm.notifier.Notify(notify.NewEvent(eventType, message)); err != nil {
+		cm.log.Warnf("Failed to send notification: %v", err)
+	}
+}
+
+// findConnectButton finds the Connect button on the profile, where the profile's layout
+// puts it, or else opens the More menu for its Connect item
+func (cm *ConnectionManager) findConnectButton() (pageops.Element, error) {
+	button, err := selectors.FindFirst(cm.page, selectors.ForLayout(selectors.ConnectButton, cm.layout))
+	if err == nil || !selectors.Has(cm.page, selectors.ProfileMoreButton) {
+		return button, err
 	}
 
-	return nil, fmt.Errorf("connect button not found")
+	if err := cm.openMoreMenu(); err != nil {
+		return nil, err
+	}
+	return selectors.FindFirst(cm.page, selectors.MoreMenuConnectItem)
+}
+
+// detectInviteFlow identifies which invite dialog LinkedIn opened after clicking Connect
+func (cm *ConnectionManager) detectInviteFlow() string {
+	if selectors.Has(cm.page, selectors.InviteBottomSheet) {
+		return FlowBottomSheet
+	}
+	return FlowModal
+}
+
+// inviteChain returns the chain to look an invite control up with, for the open invite's
+// flow and the profile's layout
+func (cm *ConnectionManager) inviteChain(name string) string {
+	if cm.flow == FlowBottomSheet {
+		return selectors.ForSheet(name)
+	}
+	return selectors.ForLayout(name, cm.layout)
 }
 
 // hasAddNoteOption checks if "Add a note" option is available
 func (cm *ConnectionManager) hasAddNoteOption() bool {
-	has, _, _ := cm.page.Has("button[aria-label*='Add a note']")
-	return has
+	return selectors.Has(cm.page, cm.inviteChain(selectors.AddNoteButton))
 }
 
 // clickAddNoteButton clicks the "Add a note" button
 func (cm *ConnectionManager) clickAddNoteButton() error {
-	button, err := cm.page.Element("button[aria-label*='Add a note']")
+	button, err := selectors.FindFirst(cm.page, cm.inviteChain(selectors.AddNoteButton))
 	if err != nil {
 		return err
 	}
 
-	return cm.mouse.ClickElement(button)
+	return cm.clicker.Click(button)
 }
 
 // typeNote types the connection note
 func (cm *ConnectionManager) typeNote(note string) error {
 	// Find note textarea
-	textarea, err := cm.page.Element("textarea[name='message']")
+	textarea, err := selectors.WaitFirst(cm.page, cm.inviteChain(selectors.NoteTextarea), elementWait)
 	if err != nil {
 		return err
 	}
 
-	return cm.typer.TypeText(cm.page, textarea, note)
+	return cm.typer.TypeText(textarea, note)
 }
 
-// clickSendButton clicks the Send button
-func (cm *ConnectionManager) clickSendButton() error {
-	// Try multiple ways to find the send button
+// notesLockedThisMonth reports whether LinkedIn locked the note field earlier this month,
+// meaning the free notes are used up until the allowance resets
+func (cm *ConnectionManager) notesLockedThisMonth() bool {
+	if !cm.notesChecked {
+		now := cm.clock.Now()
+		monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+		locked, err := cm.db.HasNoteStatusSince(NoteDeniedUpsell, monthStart)
+		if err != nil {
+			cm.log.Warnf("Failed to check for locked notes: %v", err)
+		}
+		cm.notesLocked = cm.notesLocked || locked
+		cm.notesChecked = true
+	}
+	return cm.notesLocked
+}
+
+// skipNote returns the note status of a request that should go without a note, per
+// connections.note_mode and this month's note budget, or "" when it gets one
+func (cm *ConnectionManager) skipNote(priority bool) string {
+	switch cm.config.NoteMode {
+	case config.NoteModeNever:
+		return NoteOptedOut
+	case config.NoteModePriority:
+		if !priority {
+			return NoteOptedOut
+		}
+	}
+
+	if cm.config.MonthlyNoteBudget > 0 {
+		now := cm.clock.Now()
+		monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+		used, err := cm.db.GetNotesCountSince(monthStart)
+		if err != nil {
+			// An unknown count spends no note rather than risk going over
+			cm.log.Warnf("Failed to count this month's notes, sending without a note: %v", err)
+			return NoteBudgetUsed
+		}
+		if used >= cm.config.MonthlyNoteBudget {
+			return NoteBudgetUsed
+		}
+	}
+	return ""
+}
+
+// noteLocked reports whether the note field is shown but disabled or read-only,
+// or replaced by a Premium upsell
+func (cm *ConnectionManager) noteLocked() bool {
+	// An upsell in place of the field won't turn into one by waiting
+	field, upsell := cm.inviteChain(selectors.NoteTextarea), cm.inviteChain(selectors.NoteUpsell)
+	if !selectors.Has(cm.page, field) && selectors.Has(cm.page, upsell) {
+		return true
+	}
+
+	textarea, err := selectors.WaitFirst(cm.page, field, elementWait)
+	if err != nil {
+		return selectors.Has(cm.page, upsell)
+	}
+
+	for _, property := range []string{"disabled", "readOnly"} {
+		if value, err := textarea.Property(property); err == nil && value == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// sendButtonEnabled reports whether the invite's Send button can be clicked
+func (cm *ConnectionManager) sendButtonEnabled() bool {
+	button, err := selectors.FindFirst(cm.page, cm.inviteChain(selectors.InviteSendButton))
+	if err != nil {
+		return false
+	}
+
+	disabled, err := button.Property("disabled")
+	return err == nil && disabled != "true"
+}
+
+// sendWithoutNote closes the invite dialog and sends the invite again through "Send without a note"
+func (cm *ConnectionManager) sendWithoutNote() error {
+	dismiss, err := selectors.FindFirst(cm.page, cm.inviteChain(selectors.InviteDismissButton))
+	if err != nil {
+		return fmt.Errorf("dismiss button not found: %w", err)
+	}
+	if err := cm.clicker.Click(dismiss); err != nil {
+		return fmt.Errorf("failed to dismiss invite dialog: %w", err)
+	}
+
+	cm.timing.Wait(cm.timing.ShortPause())
+
+	connectButton, err := cm.findConnectButton()
+	if err != nil {
+		return fmt.Errorf("failed to find connect button: %w", err)
+	}
+	if err := cm.clicker.Click(connectButton); err != nil {
+		return fmt.Errorf("failed to click connect button: %w", err)
+	}
+
+	cm.timing.Wait(cm.timing.ShortPause())
 
-	// 1. Text-based (most robust)
-	if el, err := cm.page.ElementR("button", "(?i)Send"); err == nil {
-		return cm.mouse.ClickElement(el)
+	button, err := selectors.WaitFirst(cm.page, cm.inviteChain(selectors.SendWithoutNoteButton), elementWait)
+	if err != nil {
+		return fmt.Errorf("send without a note button not found: %w", err)
 	}
+	return cm.clicker.Click(button)
+}
 
-	// 2. Aria-label based
-	button, err := cm.page.Element("button[aria-label*='Send']")
+// clickSendButton clicks the Send button
+func (cm *ConnectionManager) clickSendButton() error {
+	button, err := selectors.WaitFirst(cm.page, cm.inviteChain(selectors.InviteSendButton), elementWait)
 	if err != nil {
 		return fmt.Errorf("send button not found: %w", err)
 	}
 
-	return cm.mouse.ClickElement(button)
+	return cm.clicker.Click(button)
 }
 
-// generateNote generates a personalized connection note
-func (cm *ConnectionManager) generateNote(profileName, jobTitle, company string) string {
-	if len(cm.config.NoteTemplates) == 0 {
-		return ""
+// generateNote generates a personalized connection note and returns it with its template ID.
+// When noName is set only templates that don't reference {{firstName}} are used. Details
+// of an enriched profile are available to the template.
+func (cm *ConnectionManager) generateNote(profileURL, profileName, jobTitle, company string, noName bool) (string, string) {
+	candidates := cm.config.NoteTemplates
+	if noName {
+		candidates = nil
+		for _, t := range cm.config.NoteTemplates {
+			if !strings.Contains(t.Text, "{{firstName}}") && !strings.Contains(t.Text, ".FirstName") {
+				candidates = append(candidates, t)
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		return "", ""
 	}
 
 	// Select random template
-	template := cm.config.NoteTemplates[cm.rand.Intn(len(cm.config.NoteTemplates))]
+	template := templates.Pick(cm.rand, candidates, cm.config.TemplateSelection == "weighted")
 
 	// Extract first name
 	firstName := strings.Split(profileName, " ")[0]
 
-	// Replace variables
-	note := strings.ReplaceAll(template, "{{firstName}}", firstName)
-	note = strings.ReplaceAll(note, "{{jobTitle}}", jobTitle)
-	note = strings.ReplaceAll(note, "{{company}}", company)
+	vars := render.Vars{FirstName: firstName, JobTitle: jobTitle, Company: company}
+	enriched, err := cm.db.GetProfile(profileURL)
+	if err != nil {
+		cm.log.Warnf("Failed to get enriched profile: %v", err)
+	}
+	vars = profiles.TemplateVars(vars, enriched, cm.clock.Now())
+	if vars.CurationNote, err = cm.db.GetCurationNote(profileURL); err != nil {
+		cm.log.Warnf("%v", err)
+	}
+
+	// Fill variables and helpers
+	note, err := render.Execute(template.Text, vars, cm.locale)
+	if err != nil {
+		cm.log.Warnf("Failed to render note, sending without note: %v", err)
+		return "", ""
+	}
 
-	// Ensure note doesn't exceed character limit
-	if len(note) > cm.config.NoteCharacterLimit {
-		note = note[:cm.config.NoteCharacterLimit-3] + "..."
+	// Ensure note doesn't exceed character limit; LinkedIn counts characters, not bytes
+	if runes := []rune(note); len(runes) > cm.config.NoteCharacterLimit {
+		note = string(runes[:cm.config.NoteCharacterLimit-3]) + "..."
 	}
 
-	return note
+	return note, templates.ID(template.Text)
 }
 
 // GetPendingConnections returns pending connection requests
 func (cm *ConnectionManager) GetPendingConnections() ([]storage.ConnectionRequest, error) {
-	// This would query the database for pending connections
-	// For now, return empty slice
-	return []storage.ConnectionRequest{}, nil
+	return cm.db.GetConnectionRequestsByStatus("pending")
+}
+
+// skipOrganically decides, with connections.organic_skip_probability, to leave a profile
+// without connecting, as people do with most profiles they look at. The visit goes on a
+// little longer so leaving doesn't look abrupt, and the prospect comes back after
+// connections.organic_skip_cooldown_days.
+func (cm *ConnectionManager) skipOrganically(profileURL, profileName string, started time.Time) bool {
+	if p := cm.config.OrganicSkipProbability; p <= 0 || cm.rand.Float64() >= p {
+		return false
+	}
+
+	if err := cm.db.RecordOrganicSkip(profileURL); err != nil {
+		cm.log.Warnf("Failed to record organic skip, connecting instead: %v", err)
+		return false
+	}
+
+	if err := cm.scroller.ScrollDown(300 + cm.rand.Intn(400)); err != nil {
+		cm.log.Debugf("Failed to scroll: %v", err)
+	}
+	cm.timing.Wait(cm.timing.ThinkTime())
+
+	cm.log.Infof("Leaving %s without connecting, back in %d days", profileName, cm.config.OrganicSkipCooldownDays)
+	cm.db.Audit(storage.AuditEntry{Action: ReasonSkippedOrganic, ProfileURL: profileURL, Details: profileName,
+		Result: storage.AuditSkipped, PageURL: pageops.CurrentURL(cm.page), Started: started})
+	return true
 }
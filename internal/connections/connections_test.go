@@ -0,0 +1,894 @@
+package connections
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/budget"
+	"github.com/Tanukumar01/linkedin-automation/internal/clock"
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops/pagetest"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger("error", "console", "stdout", logger.Rotation{})
+	os.Exit(m.Run())
+}
+
+const profileURL = "https://www.linkedin.com/in/ada-lovelace/"
+
+// profilePage is a profile with the given buttons in its actions bar
+func profilePage(buttons ...string) string {
+	var b strings.Builder
+	b.WriteString(`<html><body><main><section class="pv-top-card"><h1>Ada Lovelace</h1>`)
+	b.WriteString(`<div class="pvs-profile-actions">`)
+	for _, button := range buttons {
+		b.WriteString(`<button>` + button + `</button>`)
+	}
+	b.WriteString(`</div></section></main></body></html>`)
+	return b.String()
+}
+
+// inviteModal is the invite dialog Connect opens, with the note field once Add a note is clicked
+func inviteModal(withNote bool) string {
+	field := `<button aria-label="Add a note">Add a note</button>`
+	if withNote {
+		field = `<textarea name="message"></textarea>`
+	}
+	return profilePage("Connect") + `<div class="artdeco-modal send-invite" role="dialog">
+		<button aria-label="Dismiss"></button>` + field + `<button aria-label="Send now">Send</button></div>`
+}
+
+// inviteSheet is the bottom sheet Connect opens in the simplified "lite" invite flow
+func inviteSheet(content string) string {
+	return profilePage("Connect") + `<div class="artdeco-bottom-sheet" role="dialog">
+		<button aria-label="Dismiss"></button>` + content + `</div>`
+}
+
+// harness is a ConnectionManager on a fixture profile
+type harness struct {
+	cm      *ConnectionManager
+	page    *pagetest.Page
+	db      *storage.DB
+	clicker *pagetest.Clicker
+	typer   *pagetest.Typer
+	cfg     *config.ConnectionsConfig
+}
+
+func newHarness(t *testing.T, profile string) *harness {
+	t.Helper()
+
+	db, err := storage.NewDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	page := pagetest.New("about:blank", "")
+	page.Serve(profileURL, profile)
+
+	cfg := &config.ConnectionsConfig{
+		DailyLimit:               10,
+		NoteTemplates:            []config.Template{{Text: "Hi {{firstName}}, glad to connect."}},
+		NoteCharacterLimit:       300,
+		NoteMode:                 config.NoteModeAlways,
+		NameResolution:           "fallback",
+		PerProfileTimeoutSeconds: 300,
+	}
+
+	timing := stealth.NewTimingController(1, 2, 1, 2, 200)
+	timing.SetClock(clock.NewFake(time.Now()))
+
+	h := &harness{page: page, db: db, clicker: &pagetest.Clicker{}, typer: &pagetest.Typer{}, cfg: cfg}
+	h.cm = NewConnectionManager(page, cfg, db, timing, h.typer, h.clicker, &pagetest.Scroller{})
+	h.cm.SetRand(rand.New(rand.NewSource(1)))
+	return h
+}
+
+// openInvites makes Connect open the invite dialog, Add a note show the note field and
+// Send close the dialog
+func (h *harness) openInvites() {
+	h.page.OnClick("button", func(el *pagetest.Element) {
+		switch text, _ := el.Text(); text {
+		case "Connect":
+			h.page.SetHTML(inviteModal(false))
+		case "Add a note":
+			h.page.SetHTML(inviteModal(true))
+		case "Send":
+			h.page.SetHTML(profilePage("Pending"))
+		}
+	})
+}
+
+func TestSendConnectionRequestWithNote(t *testing.T) {
+	h := newHarness(t, profilePage("Connect", "More"))
+	h.openInvites()
+
+	result, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "Analyst", "Engines Ltd", false)
+	if err != nil {
+		t.Fatalf("SendConnectionRequest: %v", err)
+	}
+	if result.Outcome != OutcomeSent {
+		t.Fatalf("Outcome = %q (%s), want sent", result.Outcome, result.Reason)
+	}
+	if want := "Hi Ada, glad to connect."; result.Note != want || len(h.typer.Typed) != 1 || h.typer.Typed[0] != want {
+		t.Fatalf("note = %q, typed %q, want %q", result.Note, h.typer.Typed, want)
+	}
+	if got, want := strings.Join(h.clicker.Clicked, ","), "Connect,Add a note,Send"; got != want {
+		t.Fatalf("clicked %s, want %s", got, want)
+	}
+
+	pending, err := h.db.GetConnectionRequestsByStatus("pending")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].ProfileURL != profileURL || !pending[0].NoteUsed || pending[0].FlowVariant != FlowModal {
+		t.Fatalf("pending requests = %+v", pending)
+	}
+	if contacted, _ := h.db.IsProfileContacted(profileURL, ""); !contacted {
+		t.Fatal("the profile isn't marked contacted")
+	}
+
+	// The next attempt doesn't open the profile again
+	again, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "Analyst", "Engines Ltd", false)
+	if err != nil {
+		t.Fatalf("second SendConnectionRequest: %v", err)
+	}
+	if again.Outcome != OutcomeSkipped || again.Reason != "already contacted" {
+		t.Fatalf("second attempt = %+v, want skipped as already contacted", again)
+	}
+	if len(h.page.Navigations) != 1 {
+		t.Fatalf("navigations = %v, want one", h.page.Navigations)
+	}
+}
+
+func TestSendConnectionRequestWithoutNote(t *testing.T) {
+	h := newHarness(t, profilePage("Connect"))
+	h.cfg.NoteMode = config.NoteModeNever
+	h.openInvites()
+
+	result, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "", "", false)
+	if err != nil {
+		t.Fatalf("SendConnectionRequest: %v", err)
+	}
+	if result.Outcome != OutcomeSent || result.Note != "" {
+		t.Fatalf("result = %+v, want sent without a note", result)
+	}
+	if len(h.typer.Typed) != 0 {
+		t.Fatalf("typed %q", h.typer.Typed)
+	}
+	if got, want := strings.Join(h.clicker.Clicked, ","), "Connect,Send"; got != want {
+		t.Fatalf("clicked %s, want %s", got, want)
+	}
+}
+
+func TestSendConnectionRequestSkips(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		profile string
+		reason  string
+		clicked string // the menus opened to read the profile's state
+	}{
+		{"pending", profilePage("Pending", "Message"), "already pending on LinkedIn", ""},
+		{"connected", profilePage("Message", "More"), "already connected on LinkedIn", ""},
+		{"follow only", profilePage("Follow", "More"), ReasonFollowOnly, "More"},
+		{"not found", `<main><div class="not-found-404">This page doesn't exist</div></main>`, ReasonUnavailable, ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, tt.profile)
+			h.openInvites()
+
+			result, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "", "", false)
+			if err != nil {
+				t.Fatalf("SendConnectionRequest: %v", err)
+			}
+			if result.Outcome != OutcomeSkipped || result.Reason != tt.reason {
+				t.Fatalf("result = %+v, want skipped: %s", result, tt.reason)
+			}
+			if got := strings.Join(h.clicker.Clicked, ","); got != tt.clicked {
+				t.Fatalf("clicked %q, want %q", got, tt.clicked)
+			}
+		})
+	}
+}
+
+func TestSendConnectionRequestRecordsExistingWithoutSpendingLimits(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		profile string
+	}{
+		{"pending", profilePage("Pending", "Message")},
+		{"connected", profilePage("Message", "More")},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, tt.profile)
+			if _, err := h.db.SaveSearchResults([]*storage.SearchResult{{ProfileURL: profileURL, ProfileName: "Ada Lovelace", FoundAt: time.Now()}}); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "", "", false); err != nil {
+				t.Fatalf("SendConnectionRequest: %v", err)
+			}
+			if profiles, _ := h.db.GetUncontactedProfiles("", 10, storage.ProspectPolicy{}); len(profiles) != 0 {
+				t.Fatalf("uncontacted profiles = %+v, want the profile marked contacted", profiles)
+			}
+
+			// Nothing was sent, so nothing counts as sent today
+			if recorded, _ := h.db.HasConnectionRequest(profileURL); recorded {
+				t.Fatal("a request was recorded for an invite that was never sent")
+			}
+			if count, err := h.db.GetConnectionRequestsCountByDate(time.Now()); err != nil || count != 0 {
+				t.Fatalf("today's count = %d, %v; want 0", count, err)
+			}
+		})
+	}
+}
+
+func TestSendConnectionRequestThroughMoreMenu(t *testing.T) {
+	h := newHarness(t, moreMenuProfile(false, true))
+	h.cfg.NoteMode = config.NoteModeNever
+	h.openInvites()
+	h.page.OnClick("button[aria-label='More actions']", func(*pagetest.Element) { h.page.SetHTML(moreMenuProfile(true, true)) })
+	h.page.OnClick("div[role='button']", func(el *pagetest.Element) {
+		if text, _ := el.Text(); text == "Connect" {
+			h.page.SetHTML(inviteModal(false))
+		}
+	})
+	h.page.OnEscape(func() { h.page.SetHTML(moreMenuProfile(false, true)) })
+
+	result, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "", "", false)
+	if err != nil {
+		t.Fatalf("SendConnectionRequest: %v", err)
+	}
+	if result.Outcome != OutcomeSent {
+		t.Fatalf("result = %+v, want sent", result)
+	}
+	if got, want := strings.Join(h.clicker.Clicked, ","), "More,More,Connect,Send"; got != want {
+		t.Fatalf("clicked %s, want %s", got, want)
+	}
+}
+
+func TestSendConnectionRequestSkipsMemberContactedUnderAnotherURL(t *testing.T) {
+	const member = "ACoAAB1xYz2"
+	h := newHarness(t, profilePage("Connect", "More"))
+	h.openInvites()
+	h.page.EvalFunc = func(js string, args ...interface{}) (string, error) {
+		if strings.Contains(js, "publicIdentifier") {
+			return member, nil
+		}
+		return "", errors.New("unexpected script")
+	}
+	err := h.db.SaveConnectionRequest(&storage.ConnectionRequest{ProfileURL: "https://www.linkedin.com/in/" + member, MemberID: member,
+		ProfileName: "Ada Lovelace", Status: "pending", SentAt: time.Now(), UpdatedAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "", "", false)
+	if err != nil {
+		t.Fatalf("SendConnectionRequest: %v", err)
+	}
+	if result.Outcome != OutcomeSkipped || result.Reason != "already contacted" {
+		t.Fatalf("result = %+v, want skipped as already contacted", result)
+	}
+	if len(h.clicker.Clicked) != 0 {
+		t.Fatalf("clicked %v", h.clicker.Clicked)
+	}
+
+	if pending, _ := h.db.GetConnectionRequestsByStatus("pending"); len(pending) != 1 {
+		t.Fatalf("pending requests = %+v, want only the earlier one", pending)
+	}
+}
+func TestSendConnectionRequestUnconfirmed(t *testing.T) {
+	h := newHarness(t, profilePage("Connect"))
+	h.cfg.NoteMode = config.NoteModeNever
+	h.openInvites()
+	h.page.OnClick("button[aria-label='Send now']", func(*pagetest.Element) {
+		h.page.SetHTML(inviteModal(false) + `<div class="artdeco-toast-item--error">Something went wrong</div>`)
+	})
+
+	_, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "", "", false)
+	if !errors.Is(err, ErrNotConfirmed) {
+		t.Fatalf("SendConnectionRequest = %v, want ErrNotConfirmed", err)
+	}
+
+	failed, _ := h.db.GetConnectionRequestsByStatus("failed")
+	if len(failed) != 1 {
+		t.Fatalf("failed requests = %+v, want the profile's", failed)
+	}
+	if contacted, _ := h.db.IsProfileContacted(profileURL, ""); contacted {
+		t.Fatal("an unconfirmed invite marked the profile contacted")
+	}
+}
+
+func TestVerifySentTimesOutOnTheClock(t *testing.T) {
+	h := newHarness(t, profilePage("Connect"))
+	h.cfg.NoteMode = config.NoteModeNever
+	h.openInvites()
+	fake := clock.NewFake(time.Date(2026, time.March, 10, 10, 0, 0, 0, time.UTC))
+	h.cm.SetClock(fake)
+
+	// Send is clicked, but the dialog never closes
+	h.page.OnClick("button[aria-label='Send now']", func(*pagetest.Element) { h.page.SetHTML(inviteModal(false)) })
+
+	started := time.Now()
+	_, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "", "", false)
+	if !errors.Is(err, ErrNotConfirmed) || !strings.Contains(err.Error(), "still open") {
+		t.Fatalf("SendConnectionRequest = %v, want ErrNotConfirmed with the dialog open", err)
+	}
+	if waited := time.Since(started); waited > elementWait/2 {
+		t.Fatalf("waited %s of real time for the confirmation", waited)
+	}
+
+	var slept time.Duration
+	for _, d := range fake.Slept() {
+		slept += d
+	}
+	if slept <= elementWait || slept > elementWait+verifyInterval {
+		t.Fatalf("waited %s on the clock, want just past %s", slept, elementWait)
+	}
+}
+
+func TestSendConnectionRequestTakesActionBudget(t *testing.T) {
+	h := newHarness(t, profilePage("Connect"))
+	h.cfg.NoteMode = config.NoteModeNever
+	h.openInvites()
+
+	const followOnly = "https://www.linkedin.com/in/follow-only/"
+	const second = "https://www.linkedin.com/in/grace-hopper/"
+	h.page.Serve(followOnly, profilePage("Follow"))
+	h.page.Serve(second, profilePage("Connect"))
+
+	cfg := &config.Config{Connections: *h.cfg, ActionBudget: config.ActionBudgetConfig{Enabled: true, DailyTotal: 1}}
+	actions := budget.New(cfg, h.db)
+	h.cm.SetActionBudget(actions)
+
+	// A profile skipped before the invite spends nothing
+	if result, err := h.cm.SendConnectionRequest(followOnly, "Ada Follower", "", "", false); err != nil || result.Outcome != OutcomeSkipped {
+		t.Fatalf("follow only profile = %+v, %v; want skipped", result, err)
+	}
+	if result, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "", "", false); err != nil || result.Outcome != OutcomeSent {
+		t.Fatalf("first invite = %+v, %v; want sent", result, err)
+	}
+
+	// The budget runs out before Send, leaving nothing on record for the profile
+	h.clicker.Clicked = nil
+	if _, err := h.cm.SendConnectionRequest(second, "Grace Hopper", "", "", false); !errors.Is(err, budget.ErrExhausted) {
+		t.Fatalf("second invite = %v, want ErrExhausted", err)
+	}
+	for _, label := range h.clicker.Clicked {
+		if label == "Send" {
+			t.Fatalf("clicked %v after the budget ran out", h.clicker.Clicked)
+		}
+	}
+	if contacted, _ := h.db.IsProfileContacted(second, ""); contacted {
+		t.Fatal("the profile the budget stopped is recorded as contacted")
+	}
+
+	allocations, err := actions.Allocations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range allocations {
+		if a.Action == config.ActionInvite && a.Used != 1 {
+			t.Fatalf("invites used %d of the budget, want 1", a.Used)
+		}
+	}
+}
+
+func TestSendConnectionRequestRefundsActionBudgetOnFailure(t *testing.T) {
+	h := newHarness(t, profilePage("Connect"))
+	h.cfg.NoteMode = config.NoteModeNever
+	h.openInvites()
+	h.page.OnClick("button[aria-label='Send now']", func(*pagetest.Element) {
+		h.page.SetHTML(inviteModal(false) + `<div class="artdeco-toast-item--error">Something went wrong</div>`)
+	})
+
+	cfg := &config.Config{Connections: *h.cfg, ActionBudget: config.ActionBudgetConfig{Enabled: true, DailyTotal: 1}}
+	actions := budget.New(cfg, h.db)
+	h.cm.SetActionBudget(actions)
+
+	if _, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "", "", false); !errors.Is(err, ErrNotConfirmed) {
+		t.Fatalf("SendConnectionRequest = %v, want ErrNotConfirmed", err)
+	}
+
+	allocations, err := actions.Allocations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range allocations {
+		if a.Used != 0 {
+			t.Fatalf("%s used %d of the budget after a failed invite, want none", a.Action, a.Used)
+		}
+	}
+}
+
+// sentAt records a pending request to url as sent at t
+func (h *harness) sentAt(t *testing.T, url string, at time.Time, noteUsed bool) {
+	t.Helper()
+	err := h.db.SaveConnectionRequest(&storage.ConnectionRequest{ProfileURL: url, Status: "pending", NoteUsed: noteUsed, SentAt: at, UpdatedAt: at})
+	if err != nil {
+		t.Fatalf("SaveConnectionRequest: %v", err)
+	}
+}
+
+func TestHourlyLimitWindow(t *testing.T) {
+	base := time.Date(2026, time.March, 10, 10, 0, 0, 0, time.UTC)
+
+	for _, tt := range []struct {
+		name    string
+		now     time.Time
+		reached bool
+	}{
+		{"both in the last hour", base, true},
+		{"oldest an hour old", base.Add(10 * time.Minute), true},
+		{"oldest out of the window", base.Add(10*time.Minute + time.Second), false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, profilePage("Connect"))
+			h.cfg.HourlyLimit = 2
+			h.cm.SetClock(clock.NewFake(tt.now))
+			h.sentAt(t, "https://www.linkedin.com/in/first/", base.Add(-50*time.Minute), false)
+			h.sentAt(t, "https://www.linkedin.com/in/second/", base.Add(-20*time.Minute), false)
+
+			err := h.cm.checkDailyLimit()
+			if reached := errors.Is(err, ErrHourlyLimitReached); reached != tt.reached {
+				t.Fatalf("checkDailyLimit = %v, want the hourly limit reached: %v", err, tt.reached)
+			}
+			if !tt.reached && err != nil {
+				t.Fatalf("checkDailyLimit: %v", err)
+			}
+		})
+	}
+}
+
+func TestMonthlyNoteBudgetResetsAtMonthEnd(t *testing.T) {
+	h := newHarness(t, profilePage("Connect"))
+	h.cfg.MonthlyNoteBudget = 1
+	fake := clock.NewFake(time.Date(2026, time.January, 31, 23, 50, 0, 0, time.UTC))
+	h.cm.SetClock(fake)
+	h.sentAt(t, "https://www.linkedin.com/in/first/", time.Date(2026, time.January, 31, 23, 30, 0, 0, time.UTC), true)
+
+	if status := h.cm.skipNote(false); status != NoteBudgetUsed {
+		t.Fatalf("skipNote on Jan 31 = %q, want %q", status, NoteBudgetUsed)
+	}
+
+	fake.Set(time.Date(2026, time.February, 1, 0, 10, 0, 0, time.UTC))
+	if status := h.cm.skipNote(false); status != "" {
+		t.Fatalf("skipNote on Feb 1 = %q, want a note", status)
+	}
+}
+
+func TestSendConnectionRequestCancelled(t *testing.T) {
+	h := newHarness(t, profilePage("Connect"))
+	h.cfg.NoteMode = config.NoteModeNever
+	h.openInvites()
+
+	// The context is cancelled once the invite dialog is open
+	ctx, cancel := context.WithCancel(context.Background())
+	h.page.OnClick("button", func(el *pagetest.Element) {
+		if text, _ := el.Text(); text == "Connect" {
+			cancel()
+		}
+	})
+	release := pageops.WithContext(h.page, ctx)
+	defer release()
+
+	if _, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "", "", false); !errors.Is(err, context.Canceled) {
+		t.Fatalf("SendConnectionRequest = %v, want context.Canceled", err)
+	}
+	if got := strings.Join(h.clicker.Clicked, ","); got != "Connect" {
+		t.Fatalf("clicked %s, want Connect only", got)
+	}
+	if contacted, _ := h.db.IsProfileContacted(profileURL, ""); contacted {
+		t.Fatal("the cancelled invite marked the profile contacted")
+	}
+}
+
+func TestSendConnectionRequestTimesOut(t *testing.T) {
+	h := newHarness(t, profilePage("Connect"))
+	h.openInvites()
+
+	// The profile's page never loads before its budget runs out
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now())
+	defer cancel()
+	release := pageops.WithContext(h.page, ctx)
+	defer release()
+
+	_, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "", "", false)
+	if !errors.Is(err, pageops.ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("SendConnectionRequest = %v, want ErrTimeout", err)
+	}
+	if budget, _ := h.page.DeadlineState(); budget != 0 {
+		t.Fatalf("the %s deadline wasn't released", budget)
+	}
+
+	history, err := h.db.ProfileHistory(profileURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(history); n == 0 || history[n-1].Action != "connection_failed" || history[n-1].Result != storage.AuditTimeout {
+		t.Fatalf("activity = %+v, want a timed out connection", history)
+	}
+}
+
+func TestSendConnectionRequestWithoutTheNoteThatFailed(t *testing.T) {
+	h := newHarness(t, profilePage("Connect"))
+	h.typer.Err = errors.New("keyboard detached")
+
+	dialog := func(buttons string) string {
+		return profilePage("Connect") + `<div class="artdeco-modal send-invite" role="dialog">
+			<button aria-label="Dismiss">Dismiss</button>` + buttons + `</div>`
+	}
+	h.page.OnClick("button", func(el *pagetest.Element) {
+		switch text, _ := el.Text(); text {
+		case "Connect":
+			h.page.SetHTML(dialog(`<button>Add a note</button><button aria-label="Send without a note">Send without a note</button>`))
+		case "Add a note":
+			h.page.SetHTML(dialog(`<textarea name="message"></textarea><button aria-label="Send now">Send</button>`))
+		case "Dismiss":
+			h.page.SetHTML(profilePage("Connect"))
+		case "Send without a note", "Send":
+			h.page.SetHTML(profilePage("Pending"))
+		}
+	})
+
+	result, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "", "", false)
+	if err != nil {
+		t.Fatalf("SendConnectionRequest: %v", err)
+	}
+	if result.Outcome != OutcomeSent || result.Note != "" || result.TemplateID != "" {
+		t.Fatalf("result = %+v, want sent without a note", result)
+	}
+	if got, want := strings.Join(h.clicker.Clicked, ","), "Connect,Add a note,Dismiss,Connect,Send without a note"; got != want {
+		t.Fatalf("clicked %s, want %s", got, want)
+	}
+
+	pending, err := h.db.GetConnectionRequestsByStatus("pending")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].NoteUsed || pending[0].Note != "" || pending[0].NoteStatus != NoteFailed {
+		t.Fatalf("pending requests = %+v, want a blank invite marked %s", pending, NoteFailed)
+	}
+}
+
+func TestSendConnectionRequestInBottomSheet(t *testing.T) {
+	const (
+		addNote = `<button aria-label="Add a note">Add a note</button>`
+		field   = `<textarea id="custom-message"></textarea>`
+		send    = `<button aria-label="Send invitation">Send</button>`
+	)
+
+	for _, tt := range []struct {
+		name    string
+		sheet   string // what Connect opens
+		note    string
+		clicked string
+	}{
+		{"with a note", addNote + send, "Hi Ada, glad to connect.", "Connect,Add a note,Send"},
+		{"without a note option", send, "", "Connect,Send"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, profilePage("Connect"))
+			h.page.OnClick("button", func(el *pagetest.Element) {
+				switch text, _ := el.Text(); text {
+				case "Connect":
+					h.page.SetHTML(inviteSheet(tt.sheet))
+				case "Add a note":
+					h.page.SetHTML(inviteSheet(field + send))
+				case "Send":
+					h.page.SetHTML(profilePage("Pending"))
+				}
+			})
+
+			result, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "", "", false)
+			if err != nil {
+				t.Fatalf("SendConnectionRequest: %v", err)
+			}
+			if result.Outcome != OutcomeSent || result.Note != tt.note {
+				t.Fatalf("result = %+v, want sent with note %q", result, tt.note)
+			}
+			if got := strings.Join(h.clicker.Clicked, ","); got != tt.clicked {
+				t.Fatalf("clicked %s, want %s", got, tt.clicked)
+			}
+
+			pending, err := h.db.GetConnectionRequestsByStatus("pending")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pending) != 1 || pending[0].FlowVariant != FlowBottomSheet || pending[0].NoteUsed != (tt.note != "") {
+				t.Fatalf("pending requests = %+v, want one sent through the %s", pending, FlowBottomSheet)
+			}
+		})
+	}
+}
+
+func TestSendConnectionRequestBottomSheetWithoutTheNoteThatFailed(t *testing.T) {
+	h := newHarness(t, profilePage("Connect"))
+	h.typer.Err = errors.New("keyboard detached")
+	h.page.OnClick("button", func(el *pagetest.Element) {
+		switch text, _ := el.Text(); text {
+		case "Connect":
+			h.page.SetHTML(inviteSheet(`<button>Add a note</button><button>Send</button>`))
+		case "Add a note":
+			h.page.SetHTML(inviteSheet(`<textarea></textarea><button>Send</button>`))
+		case "Send":
+			h.page.SetHTML(profilePage("Pending"))
+		}
+	})
+	h.page.OnClick("button[aria-label='Dismiss']", func(*pagetest.Element) { h.page.SetHTML(profilePage("Connect")) })
+
+	result, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "", "", false)
+	if err != nil {
+		t.Fatalf("SendConnectionRequest: %v", err)
+	}
+	if result.Outcome != OutcomeSent || result.Note != "" {
+		t.Fatalf("result = %+v, want sent without a note", result)
+	}
+	if got, want := strings.Join(h.clicker.Clicked, ","), "Connect,Add a note,Dismiss,Connect,Send"; got != want {
+		t.Fatalf("clicked %s, want %s", got, want)
+	}
+}
+
+func TestSendConnectionRequestUnsupportedSheetIsClosed(t *testing.T) {
+	h := newHarness(t, profilePage("Connect"))
+	h.page.OnClick("button", func(el *pagetest.Element) {
+		if text, _ := el.Text(); text == "Connect" {
+			h.page.SetHTML(inviteSheet(`<p>Tap to pick a reason</p>`))
+		}
+	})
+	h.page.OnClick("button[aria-label='Dismiss']", func(*pagetest.Element) { h.page.SetHTML(profilePage("Connect")) })
+
+	_, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "", "", false)
+	if !errors.Is(err, ErrUnsupportedFlow) {
+		t.Fatalf("SendConnectionRequest = %v, want ErrUnsupportedFlow", err)
+	}
+
+	// Escape doesn't close this sheet, so it is dismissed
+	if h.page.Escapes != 2 || selectors.Has(h.page, selectors.OpenDialog) {
+		t.Fatalf("the sheet is still open after %d Escape presses", h.page.Escapes)
+	}
+	if got, want := strings.Join(h.clicker.Clicked, ","), "Connect,Dismiss"; got != want {
+		t.Fatalf("clicked %s, want %s", got, want)
+	}
+	if recorded, _ := h.db.HasConnectionRequest(profileURL); recorded {
+		t.Fatal("a request was recorded for the unsupported flow")
+	}
+}
+
+// lockedNotes makes Connect open an invite dialog whose Add a note shows field and send in
+// place of the note field, as LinkedIn does for accounts past their free notes. Dismiss
+// closes the dialog and either Send sends the invite.
+func (h *harness) lockedNotes(field, send string) {
+	dialog := func(content string) string {
+		return profilePage("Connect") + `<div class="artdeco-modal send-invite" role="dialog">
+			<button aria-label="Dismiss">Dismiss</button>` + content + `</div>`
+	}
+	h.page.OnClick("button", func(el *pagetest.Element) {
+		switch text, _ := el.Text(); text {
+		case "Connect":
+			h.page.SetHTML(dialog(`<button aria-label="Add a note">Add a note</button><button aria-label="Send without a note">Send without a note</button>`))
+		case "Add a note":
+			h.page.SetHTML(dialog(field + send))
+		case "Dismiss":
+			h.page.SetHTML(profilePage("Connect"))
+		case "Send", "Send without a note":
+			h.page.SetHTML(profilePage("Pending"))
+		}
+	})
+}
+
+func TestSendConnectionRequestWithLockedNote(t *testing.T) {
+	const (
+		upsell      = `<div class="premium-upsell">Personalize your invitations with Premium</div>`
+		send        = `<button aria-label="Send now">Send</button>`
+		sendBlank   = `<button aria-label="Send without a note">Send without a note</button>`
+		sendBlocked = `<button aria-label="Send now" disabled>Send</button>`
+	)
+
+	for _, tt := range []struct {
+		name    string
+		field   string
+		send    string
+		clicked string
+	}{
+		{"disabled field", `<textarea name="message" disabled></textarea>`, send, "Connect,Add a note,Send"},
+		{"read-only field", `<textarea name="message" readonly></textarea>`, send, "Connect,Add a note,Send"},
+		{"upsell with send", upsell, sendBlank, "Connect,Add a note,Send without a note"},
+		{"upsell without send", upsell, sendBlocked, "Connect,Add a note,Dismiss,Connect,Send without a note"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, profilePage("Connect"))
+			h.lockedNotes(tt.field, tt.send)
+
+			result, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "", "", false)
+			if err != nil {
+				t.Fatalf("SendConnectionRequest: %v", err)
+			}
+			if result.Outcome != OutcomeSent || result.Note != "" {
+				t.Fatalf("result = %+v, want sent without a note", result)
+			}
+			if len(h.typer.Typed) != 0 {
+				t.Fatalf("typed %q into a locked note field", h.typer.Typed)
+			}
+			if got := strings.Join(h.clicker.Clicked, ","); got != tt.clicked {
+				t.Fatalf("clicked %s, want %s", got, tt.clicked)
+			}
+
+			pending, err := h.db.GetConnectionRequestsByStatus("pending")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pending) != 1 || pending[0].NoteUsed || pending[0].NoteStatus != NoteDeniedUpsell {
+				t.Fatalf("pending requests = %+v, want a blank invite marked %s", pending, NoteDeniedUpsell)
+			}
+		})
+	}
+}
+
+func TestLockedNotesSkipTheNoteForTheMonth(t *testing.T) {
+	h := newHarness(t, profilePage("Connect"))
+	h.lockedNotes(`<textarea name="message" disabled></textarea>`, `<button aria-label="Send now">Send</button>`)
+
+	const second = "https://www.linkedin.com/in/grace-hopper/"
+	const third = "https://www.linkedin.com/in/mary-somerville/"
+	h.page.Serve(second, profilePage("Connect"))
+	h.page.Serve(third, profilePage("Connect"))
+
+	if _, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "", "", false); err != nil {
+		t.Fatalf("first SendConnectionRequest: %v", err)
+	}
+
+	// Later invites go out blank without opening the note field again, in this run and
+	// in the next one, which finds the lock among this month's requests
+	for i, url := range []string{second, third} {
+		if i == 1 {
+			h.cm.notesLocked, h.cm.notesChecked = false, false
+		}
+		h.clicker.Clicked = nil
+
+		result, err := h.cm.SendConnectionRequest(url, "Grace Hopper", "", "", false)
+		if err != nil {
+			t.Fatalf("SendConnectionRequest(%s): %v", url, err)
+		}
+		if result.Outcome != OutcomeSent {
+			t.Fatalf("result = %+v, want sent", result)
+		}
+		if got, want := strings.Join(h.clicker.Clicked, ","), "Connect,Send without a note"; got != want {
+			t.Fatalf("clicked %s, want %s", got, want)
+		}
+	}
+}
+
+func TestCloseDialogs(t *testing.T) {
+	const open = `<main></main><div class="artdeco-modal" role="dialog"><button aria-label="Dismiss">Dismiss</button></div>`
+
+	for _, tt := range []struct {
+		name      string
+		escapes   int // presses that close the dialog; 0 when Escape doesn't
+		pressed   int
+		dismissed bool
+	}{
+		{"closed by Escape", 1, 1, false},
+		{"closed by a second Escape", 2, 2, false},
+		{"deaf to Escape", 0, 2, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, "")
+			h.page.SetHTML(open)
+			h.page.OnEscape(func() {
+				if h.page.Escapes == tt.escapes {
+					h.page.SetHTML(`<main></main>`)
+				}
+			})
+			h.page.OnClick("button", func(*pagetest.Element) { h.page.SetHTML(`<main></main>`) })
+
+			h.cm.closeDialogs()
+
+			if h.page.Escapes != tt.pressed {
+				t.Fatalf("pressed Escape %d times, want %d", h.page.Escapes, tt.pressed)
+			}
+			if dismissed := len(h.clicker.Clicked) > 0; dismissed != tt.dismissed {
+				t.Fatalf("clicked %v, want dismissed %v", h.clicker.Clicked, tt.dismissed)
+			}
+			if selectors.Has(h.page, selectors.OpenDialog) {
+				t.Fatal("a dialog is still open")
+			}
+		})
+	}
+}
+
+func TestSendConnectionRequestSkipsOrganically(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		seed    int64 // decides the skip at a probability of 0.5
+		skipped bool
+	}{
+		{"skipped", 2, true},
+		{"connected", 1, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, profilePage("Connect", "More"))
+			h.openInvites()
+			h.cfg.OrganicSkipProbability = 0.5
+			h.cfg.OrganicSkipCooldownDays = 30
+			h.cm.SetRand(rand.New(rand.NewSource(tt.seed)))
+			if _, err := h.db.SaveSearchResults([]*storage.SearchResult{{ProfileURL: profileURL, ProfileName: "Ada Lovelace", FoundAt: time.Now()}}); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "", "", false)
+			if err != nil {
+				t.Fatalf("SendConnectionRequest: %v", err)
+			}
+			if !tt.skipped {
+				if result.Outcome != OutcomeSent {
+					t.Fatalf("result = %s (%s), want sent", result.Outcome, result.Reason)
+				}
+				return
+			}
+
+			if result.Outcome != OutcomeSkipped || result.Reason != ReasonSkippedOrganic {
+				t.Fatalf("result = %s (%s), want skipped organically", result.Outcome, result.Reason)
+			}
+			if len(h.clicker.Clicked) != 0 {
+				t.Fatalf("clicked %v on a profile left without connecting", h.clicker.Clicked)
+			}
+			if recorded, _ := h.db.HasConnectionRequest(profileURL); recorded {
+				t.Fatal("a request was recorded for a profile left without connecting")
+			}
+
+			// Left out while the cool-down lasts, back once it's over
+			for _, c := range []struct {
+				before time.Time
+				want   int
+			}{
+				{time.Now().AddDate(0, 0, -h.cfg.OrganicSkipCooldownDays), 0},
+				{time.Now().Add(time.Minute), 1},
+			} {
+				profiles, err := h.db.GetUncontactedProfiles("", 10, storage.ProspectPolicy{OrganicSkippedBefore: c.before})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(profiles) != c.want {
+					t.Fatalf("uncontacted profiles skipped before %s = %d, want %d", c.before.Format(time.DateOnly), len(profiles), c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateNoteCountsYearsOnTheClock(t *testing.T) {
+	h := newHarness(t, profilePage("Connect"))
+	h.cfg.NoteTemplates = []config.Template{{Text: "{{.CurrentPositionYears}} years at {{.Company}}"}}
+	err := h.db.SaveProfile(&storage.Profile{ProfileURL: profileURL, Name: "Ada Lovelace", ScrapedAt: time.Now(),
+		Experience: []storage.Position{{Title: "Analyst", Company: "Engines Ltd", Start: time.Date(2019, 9, 1, 0, 0, 0, 0, time.UTC), Current: true}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		now  time.Time
+		want string
+	}{
+		{time.Date(2024, 8, 15, 12, 0, 0, 0, time.UTC), "4 years at Engines Ltd"},
+		{time.Date(2024, 9, 15, 12, 0, 0, 0, time.UTC), "5 years at Engines Ltd"},
+	} {
+		h.cm.SetClock(clock.NewFake(tt.now))
+		if note, _ := h.cm.generateNote(profileURL, "Ada Lovelace", "Analyst", "Engines Ltd", false); note != tt.want {
+			t.Errorf("note on %s = %q, want %q", tt.now.Format(time.DateOnly), note, tt.want)
+		}
+	}
+}
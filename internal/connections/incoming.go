@@ -0,0 +1,261 @@
+package connections
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/insight"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+const invitationManagerURL = "https://www.linkedin.com/mynetwork/invitation-manager/"
+
+// Invite decisions
+const (
+	InviteAccepted = "accepted"
+	InviteIgnored  = "ignored"
+	InviteLeft     = "left"
+)
+
+// IncomingInvitesProcessor accepts received invitations that match the target criteria
+type IncomingInvitesProcessor struct {
+	page          pageops.Page
+	config        *config.InvitesConfig
+	db            *storage.DB
+	timing        *stealth.TimingController
+	clicker       pageops.Clicker
+	scroller      pageops.Scroller
+	titlePatterns []*regexp.Regexp
+	checkpoint    func() bool // reports whether processing should wrap up
+}
+
+// Inviter represents a person who sent us a connection request
+type Inviter struct {
+	ProfileURL        string
+	Name              string
+	Headline          string
+	MutualConnections int
+	card              pageops.Element
+}
+
+// InvitesResult summarizes a pass over the received invitations
+type InvitesResult struct {
+	Accepted int
+	Ignored  int
+	Left     int
+}
+
+// NewIncomingInvitesProcessor creates a new incoming invites processor
+func NewIncomingInvitesProcessor(page pageops.Page, cfg *config.InvitesConfig, db *storage.DB, timing *stealth.TimingController, clicker pageops.Clicker, scroller pageops.Scroller) (*IncomingInvitesProcessor, error) {
+	var patterns []*regexp.Regexp
+	for _, p := range cfg.TitlePatterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid title pattern %q: %w", p, err)
+		}
+		patterns = append(patterns, re)
+	}
+
+	return &IncomingInvitesProcessor{
+		page:          page,
+		config:        cfg,
+		db:            db,
+		timing:        timing,
+		clicker:       clicker,
+		scroller:      scroller,
+		titlePatterns: patterns,
+		checkpoint:    func() bool { return false },
+	}, nil
+}
+
+// SetCheckpoint sets the check made between invitations; once it returns true the rest are left for later
+func (p *IncomingInvitesProcessor) SetCheckpoint(fn func() bool) {
+	p.checkpoint = fn
+}
+
+// ProcessInvites visits the received invitations page and handles every pending invite
+func (p *IncomingInvitesProcessor) ProcessInvites() (*InvitesResult, error) {
+	logger.Info("Processing incoming invitations")
+
+	result := &InvitesResult{}
+
+	accepted, err := p.db.GetAcceptedInvitesCountByDate(time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accepted invites count: %w", err)
+	}
+
+	if err := p.page.Navigate(invitationManagerURL); err != nil {
+		return nil, fmt.Errorf("failed to navigate to invitations: %w", err)
+	}
+
+	if err := p.page.WaitLoad(); err != nil {
+		logger.Warnf("Invitations page load wait failed: %v", err)
+	}
+
+	p.timing.Wait(p.timing.ThinkTime())
+
+	if err := p.scroller.ScrollDown(600); err != nil {
+		logger.Warnf("Failed to scroll: %v", err)
+	}
+
+	inviters, err := p.parseInviters()
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Found %d pending invitations", len(inviters))
+
+	for _, inviter := range inviters {
+		if p.checkpoint() {
+			logger.Info("Invitations time budget used, leaving remaining invitations")
+			break
+		}
+
+		decision, reason := p.evaluate(inviter)
+
+		if decision == InviteAccepted && accepted >= p.config.DailyAcceptLimit {
+			logger.Infof("Daily accept limit reached (%d/%d), leaving remaining invitations", accepted, p.config.DailyAcceptLimit)
+			break
+		}
+
+		switch decision {
+		case InviteAccepted:
+			if err := p.clickCardButton(inviter.card, selectors.InvitationAccept); err != nil {
+				logger.Warnf("Failed to accept invitation from %s: %v", inviter.Name, err)
+				continue
+			}
+			accepted++
+			result.Accepted++
+		case InviteIgnored:
+			if err := p.clickCardButton(inviter.card, selectors.InvitationIgnore); err != nil {
+				logger.Warnf("Failed to ignore invitation from %s: %v", inviter.Name, err)
+				continue
+			}
+			result.Ignored++
+		default:
+			result.Left++
+		}
+
+		logger.Infof("Invitation from %s: %s (%s)", inviter.Name, decision, reason)
+
+		invite := &storage.IncomingInvite{
+			ProfileURL:        inviter.ProfileURL,
+			InviterName:       inviter.Name,
+			Headline:          inviter.Headline,
+			MutualConnections: inviter.MutualConnections,
+			Decision:          decision,
+			Reason:            reason,
+			DecidedAt:         time.Now(),
+		}
+
+		if err := p.db.SaveIncomingInvite(invite); err != nil {
+			logger.Errorf("Failed to save incoming invite: %v", err)
+		}
+
+		if decision != InviteLeft {
+			p.db.Audit(storage.AuditEntry{Action: "invite_" + decision, ProfileURL: inviter.ProfileURL, Details: fmt.Sprintf("%s (%s)", inviter.Name, reason),
+				Result: storage.AuditSuccess, PageURL: pageops.CurrentURL(p.page)})
+			p.timing.Wait(p.timing.ActionDelay())
+		}
+	}
+
+	return result, nil
+}
+
+// evaluate decides what to do with an invitation and why
+func (p *IncomingInvitesProcessor) evaluate(inviter Inviter) (string, string) {
+	text := strings.ToLower(inviter.Name + " " + inviter.Headline)
+
+	for _, excluded := range p.config.Exclude {
+		if excluded != "" && strings.Contains(text, strings.ToLower(excluded)) {
+			return InviteLeft, fmt.Sprintf("excluded by %q", excluded)
+		}
+	}
+
+	for _, keyword := range p.config.SpamKeywords {
+		if keyword != "" && strings.Contains(text, strings.ToLower(keyword)) {
+			if p.config.IgnoreSpam {
+				return InviteIgnored, fmt.Sprintf("spam keyword %q", keyword)
+			}
+			return InviteLeft, fmt.Sprintf("spam keyword %q", keyword)
+		}
+	}
+
+	if inviter.MutualConnections < p.config.MinMutualConnections {
+		return InviteLeft, fmt.Sprintf("%d mutual connections, need %d", inviter.MutualConnections, p.config.MinMutualConnections)
+	}
+
+	if len(p.titlePatterns) == 0 {
+		return InviteAccepted, "no title criteria"
+	}
+
+	for _, re := range p.titlePatterns {
+		if re.MatchString(inviter.Headline) {
+			return InviteAccepted, fmt.Sprintf("headline matches %s", re.String())
+		}
+	}
+
+	return InviteLeft, "headline doesn't match title criteria"
+}
+
+// parseInviters parses the invitation cards on the current page
+func (p *IncomingInvitesProcessor) parseInviters() ([]Inviter, error) {
+	// No cards simply means there are no pending invitations
+	cards, _ := selectors.FindAll(p.page, selectors.InvitationCard)
+
+	var inviters []Inviter
+	for _, card := range cards {
+		inviter := Inviter{card: card}
+
+		link, err := selectors.FindFirst(card, selectors.InvitationLink)
+		if err != nil {
+			continue
+		}
+
+		href, err := link.Property("href")
+		if err != nil {
+			continue
+		}
+
+		inviter.ProfileURL = href
+		if idx := strings.Index(inviter.ProfileURL, "?"); idx != -1 {
+			inviter.ProfileURL = inviter.ProfileURL[:idx]
+		}
+
+		if el, err := selectors.FindFirst(card, selectors.InvitationName); err == nil {
+			name, _ := el.Text()
+			inviter.Name = strings.TrimSpace(name)
+		}
+
+		if el, err := selectors.FindFirst(card, selectors.InvitationHeadline); err == nil {
+			headline, _ := el.Text()
+			inviter.Headline = strings.TrimSpace(headline)
+		}
+
+		if el, err := selectors.FindFirst(card, selectors.InvitationInsights); err == nil {
+			insights, _ := el.Text()
+			inviter.MutualConnections = insight.MutualConnections(insights)
+		}
+
+		inviters = append(inviters, inviter)
+	}
+
+	return inviters, nil
+}
+
+// clickCardButton clicks the Accept or Ignore button inside an invitation card
+func (p *IncomingInvitesProcessor) clickCardButton(card pageops.Element, name string) error {
+	button, err := selectors.FindFirst(card, name)
+	if err != nil {
+		return err
+	}
+
+	return p.clicker.Click(button)
+}
@@ -0,0 +1,162 @@
+package connections
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// StatusRemoved marks a connection the account removed again
+const StatusRemoved = "removed"
+
+// Maintenance errors; the profile is left as it was
+var (
+	ErrNotConnected = errors.New("profile is not a connection")
+	ErrNotFollowing = errors.New("profile is not followed")
+)
+
+// RemoveConnection removes a connection: it opens the profile's More menu, picks Remove
+// connection and confirms. The request on record is marked removed.
+func (cm *ConnectionManager) RemoveConnection(profileURL string) error {
+	return cm.maintain(profileURL, "connection_removed", cm.removeConnection)
+}
+
+// removeConnection removes the connection whose profile is open
+func (cm *ConnectionManager) removeConnection(profileURL string) error {
+	if state := cm.detectProfileState(); state != ProfileConnected {
+		return fmt.Errorf("%w: profile is %s", ErrNotConnected, state)
+	}
+
+	if err := cm.openMoreMenu(); err != nil {
+		return err
+	}
+	if err := cm.clickWhenShown(selectors.RemoveConnectionItem); err != nil {
+		return fmt.Errorf("failed to find Remove connection in the More menu: %w", err)
+	}
+	if err := cm.clickWhenShown(selectors.RemoveConfirmButton); err != nil {
+		return fmt.Errorf("failed to confirm the removal: %w", err)
+	}
+	cm.timing.Wait(cm.timing.ActionDelay())
+
+	if cm.detectProfileState() == ProfileConnected {
+		return fmt.Errorf("profile still shows the connection after confirming")
+	}
+
+	if err := cm.db.UpdateConnectionStatus(profileURL, StatusRemoved); err != nil {
+		return fmt.Errorf("failed to record the removal: %w", err)
+	}
+	return nil
+}
+
+// Unfollow stops following a profile, from its Following button or else its More menu,
+// and confirms when LinkedIn asks
+func (cm *ConnectionManager) Unfollow(profileURL string) error {
+	return cm.maintain(profileURL, "unfollowed", cm.unfollow)
+}
+
+// unfollow unfollows the profile that is open
+func (cm *ConnectionManager) unfollow(string) error {
+	if following, err := selectors.FindFirst(cm.page, selectors.ProfileFollowingButton); err == nil {
+		if err := cm.clicker.Click(following); err != nil {
+			return fmt.Errorf("failed to click Following: %w", err)
+		}
+	} else {
+		if err := cm.openMoreMenu(); err != nil {
+			return err
+		}
+		if err := cm.clickWhenShown(selectors.UnfollowItem); err != nil {
+			return fmt.Errorf("%w: the More menu offers no Unfollow", ErrNotFollowing)
+		}
+	}
+
+	// Creators' profiles ask to confirm, the others unfollow at once
+	cm.timing.Wait(cm.timing.ShortPause())
+	if confirm, err := selectors.FindFirst(cm.page, selectors.UnfollowConfirmButton); err == nil {
+		if err := cm.clicker.Click(confirm); err != nil {
+			return fmt.Errorf("failed to confirm unfollowing: %w", err)
+		}
+	}
+	cm.timing.Wait(cm.timing.ActionDelay())
+
+	if selectors.Has(cm.page, selectors.ProfileFollowingButton) {
+		return fmt.Errorf("profile is still followed after unfollowing")
+	}
+	return nil
+}
+
+// maintain opens a profile and runs a maintenance action on it within the profile's time
+// budget, auditing the outcome as action either way
+func (cm *ConnectionManager) maintain(profileURL, action string, fn func(profileURL string) error) error {
+	started := time.Now()
+	defer cm.forProfile(profileURL, "", action)()
+
+	timeout := cm.config.ProfileTimeout()
+	release := pageops.WithDeadline(cm.page, timeout)
+	err := cm.openProfile(profileURL)
+	if err == nil {
+		err = fn(profileURL)
+	}
+	release()
+	err = pageops.TimedOut(err, timeout)
+
+	entry := storage.AuditEntry{Action: action, ProfileURL: profileURL, Result: storage.AuditSuccess,
+		PageURL: pageops.CurrentURL(cm.page), Started: started}
+	switch {
+	case errors.Is(err, ErrNotConnected), errors.Is(err, ErrNotFollowing):
+		entry.Result, entry.Err = storage.AuditSkipped, err
+		cm.log.Infof("Skipping %s: %v", profileURL, err)
+	case errors.Is(err, pageops.ErrTimeout):
+		entry.Result, entry.Err = storage.AuditTimeout, err
+	case err != nil:
+		entry.Result, entry.Err = storage.AuditFailed, err
+		cm.artifacts.Capture(cm.page, action, err)
+	default:
+		cm.log.Infof("Done: %s %s", action, profileURL)
+	}
+	cm.closeDialogs()
+	cm.db.Audit(entry)
+	return err
+}
+
+// openProfile navigates to a profile and pauses as a reader would
+func (cm *ConnectionManager) openProfile(profileURL string) error {
+	if err := cm.page.Navigate(profileURL); err != nil {
+		return fmt.Errorf("failed to navigate to profile: %w", err)
+	}
+	if err := cm.page.WaitLoad(); err != nil {
+		cm.log.Warnf("Profile page load wait failed: %v", err)
+	}
+	cm.timing.Wait(cm.timing.ThinkTime())
+	cm.layout = selectors.DetectLayout(cm.page)
+	return nil
+}
+
+// openMoreMenu opens the profile's More actions menu
+func (cm *ConnectionManager) openMoreMenu() error {
+	more, err := selectors.FindFirst(cm.page, selectors.ProfileMoreButton)
+	if err != nil {
+		return fmt.Errorf("failed to find the More button: %w", err)
+	}
+	if err := cm.clicker.Click(more); err != nil {
+		return fmt.Errorf("failed to open the More menu: %w", err)
+	}
+	cm.timing.Wait(cm.timing.ShortPause())
+	return nil
+}
+
+// clickWhenShown waits for the named element to render and clicks it
+func (cm *ConnectionManager) clickWhenShown(name string) error {
+	el, err := selectors.WaitFirst(cm.page, name, elementWait)
+	if err != nil {
+		return err
+	}
+	if err := cm.clicker.Click(el); err != nil {
+		return err
+	}
+	cm.timing.Wait(cm.timing.ShortPause())
+	return nil
+}
@@ -0,0 +1,124 @@
+package connections
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// Name resolutions recorded on each connection request
+const (
+	NameParsed    = "parsed"
+	NameRescraped = "rescraped"
+	NameFallback  = "fallback"
+	NameSkipped   = "skipped"
+)
+
+// blankGreeting matches greetings whose name placeholder was filled with nothing, e.g. "Hi ,"
+var blankGreeting = regexp.MustCompile(`(?i)^\s*(hi|hello|hey|dear)\s+[,!.]`)
+
+// isJunkName reports whether a parsed profile name is unusable for personalization
+func isJunkName(name string) bool {
+	name = strings.TrimSpace(name)
+
+	if utf8.RuneCountInString(name) <= 1 {
+		return true
+	}
+
+	if strings.EqualFold(name, "LinkedIn Member") {
+		return true
+	}
+
+	return strings.Contains(name, "…") || strings.Contains(name, "...")
+}
+
+// resolveProfileName applies the configured name resolution strategy.
+// It expects the profile page to already be loaded.
+func (cm *ConnectionManager) resolveProfileName(profileName string) (string, string) {
+	if !isJunkName(profileName) {
+		return profileName, NameParsed
+	}
+
+	cm.log.Warnf("Profile name %q looks unusable, applying %s", profileName, cm.config.NameResolution)
+
+	switch cm.config.NameResolution {
+	case "skip":
+		return profileName, NameSkipped
+	case "fallback":
+		return profileName, NameFallback
+	}
+
+	if name, err := cm.scrapeProfileName(); err == nil && !isJunkName(name) {
+		cm.log.Infof("Re-scraped profile name: %s", name)
+		return name, NameRescraped
+	}
+
+	return profileName, NameFallback
+}
+
+// scrapeProfileName reads the name from the profile page header
+func (cm *ConnectionManager) scrapeProfileName() (string, error) {
+	el, err := selectors.FindFirst(cm.page, selectors.ProfileName)
+	if err != nil {
+		return "", fmt.Errorf("profile header not found: %w", err)
+	}
+
+	name, err := el.Text()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(name), nil
+}
+
+// recordSkip stores a skipped profile so it isn't picked up again
+func (cm *ConnectionManager) recordSkip(profileURL, profileName, jobTitle, company string) {
+	request := &storage.ConnectionRequest{
+		ProfileURL:     profileURL,
+		ProfileName:    profileName,
+		JobTitle:       jobTitle,
+		Company:        company,
+		Status:         "skipped",
+		NameResolution: NameSkipped,
+		Campaign:       cm.campaign,
+		SentAt:         time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	err := cm.db.WithTx(func(tx *storage.DB) error {
+		if err := tx.SaveConnectionRequest(request); err != nil {
+			return err
+		}
+		if err := tx.MarkProfileContacted(profileURL); err != nil {
+			return fmt.Errorf("failed to mark profile as contacted: %w", err)
+		}
+		return tx.Audit(storage.AuditEntry{Action: "connection_skipped", ProfileURL: profileURL, Details: "Unusable name",
+			Result: storage.AuditSkipped, PageURL: pageops.CurrentURL(cm.page)})
+	})
+	if err != nil {
+		cm.log.Errorf("Failed to save skipped request: %v", err)
+	}
+}
+
+// lintNote rejects notes containing artifacts that must never be sent
+func lintNote(note string) error {
+	if note == "" {
+		return nil
+	}
+
+	if blankGreeting.MatchString(note) {
+		return fmt.Errorf("note starts with a greeting without a name")
+	}
+
+	if strings.Contains(note, "{{") || strings.Contains(note, "}}") {
+		return fmt.Errorf("note contains an unreplaced template variable")
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package connections
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+)
+
+// Words read at most at each depth; a reader skims the rest of a long profile. Even an
+// empty profile holds the reader for the headline and top card.
+const (
+	lightReadWords = 120
+	fullReadWords  = 600
+	lightMinDwell  = 3 * time.Second
+	fullMinDwell   = 6 * time.Second
+
+	// hoverProbability is the chance a full read hovers over one of the positions
+	hoverProbability = 0.5
+)
+
+// profileWordsScript counts the words rendered in the profile sections with the given
+// anchor IDs; innerText leaves out what collapsed "see more" blocks hide
+const profileWordsScript = `(ids) => String(ids.reduce((n, id) => {
+	const anchor = document.getElementById(id);
+	const section = anchor && anchor.closest('section');
+	const text = section ? section.innerText : '';
+	return n + text.split(/\s+/).filter(Boolean).length;
+}, 0))`
+
+// SetProfileReading sets how thoroughly a profile is read before connecting, one of the
+// config.ProfileReading depths
+func (cm *ConnectionManager) SetProfileReading(depth string) {
+	cm.reading = depth
+}
+
+// ProfileDwell returns how long a profile of words words is read at depth: the reading
+// time of the words a reader at that depth gets through, and never less than a glance
+func ProfileDwell(timing *stealth.TimingController, depth string, words int) time.Duration {
+	switch depth {
+	case config.ProfileReadingLight:
+		return max(timing.ReadingTime(min(words, lightReadWords)), lightMinDwell)
+	case config.ProfileReadingFull:
+		return max(timing.ReadingTime(min(words, fullReadWords)), fullMinDwell)
+	default:
+		return 0
+	}
+}
+
+// readProfile reads the open profile before the Connect button is looked for, scrolling
+// two or three times while the dwell lasts. The dwell never takes more than a third of the
+// profile's time budget.
+func (cm *ConnectionManager) readProfile() {
+	if cm.reading == "" || cm.reading == config.ProfileReadingNone {
+		if err := cm.scroller.ScrollDown(300); err != nil {
+			cm.log.Warnf("Failed to scroll: %v", err)
+		}
+		cm.timing.Wait(cm.timing.ShortPause())
+		return
+	}
+
+	words := cm.profileWords()
+	dwell := ProfileDwell(cm.timing, cm.reading, words)
+	if timeout := cm.config.ProfileTimeout(); timeout > 0 {
+		dwell = min(dwell, timeout/3)
+	}
+	cm.log.Debugf("Reading %d words of the profile for %s", words, dwell.Round(time.Second))
+
+	scrolls := 2 + cm.rand.Intn(2)
+	hoverAt := -1
+	if cm.reading == config.ProfileReadingFull && cm.rand.Float64() < hoverProbability {
+		hoverAt = 1 + cm.rand.Intn(scrolls-1)
+	}
+	for i := 0; i < scrolls; i++ {
+		if err := cm.scroller.ScrollDown(250 + cm.rand.Intn(350)); err != nil {
+			cm.log.Warnf("Failed to scroll: %v", err)
+		}
+		if i == hoverAt {
+			cm.hoverPosition()
+		}
+		cm.timing.Wait(dwell / time.Duration(scrolls))
+	}
+}
+
+// profileWords counts the words of the open profile's About section, and at full depth
+// its Experience too. Pages that can't run scripts fall back to the About text.
+func (cm *ConnectionManager) profileWords() int {
+	sections := []string{"about"}
+	if cm.reading == config.ProfileReadingFull {
+		sections = append(sections, "experience")
+	}
+
+	if evaluator, ok := cm.page.(pageops.Evaluator); ok {
+		out, err := evaluator.Eval(profileWordsScript, sections)
+		if n, convErr := strconv.Atoi(out); err == nil && convErr == nil {
+			return n
+		}
+		cm.log.Debugf("Failed to count the profile's words: %v", err)
+	}
+
+	if el, err := selectors.FindFirst(cm.page, selectors.AboutText); err == nil {
+		text, _ := el.Text()
+		return len(strings.Fields(text))
+	}
+	return 0
+}
+
+// hoverPosition rests the mouse on one of the first positions in the Experience list
+func (cm *ConnectionManager) hoverPosition() {
+	hoverer, ok := cm.clicker.(pageops.Hoverer)
+	if !ok {
+		return
+	}
+	items, err := selectors.FindAll(cm.page, selectors.ExperienceItem)
+	if err != nil || len(items) == 0 {
+		return
+	}
+	if err := hoverer.Hover(items[cm.rand.Intn(min(len(items), 3))]); err != nil {
+		cm.log.Debugf("Failed to hover over a position: %v", err)
+	}
+}
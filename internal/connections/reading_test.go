@@ -0,0 +1,91 @@
+package connections
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/clock"
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops/pagetest"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+)
+
+func TestProfileDwell(t *testing.T) {
+	timing := stealth.NewTimingController(1, 2, 1, 2, 200)
+
+	for _, tt := range []struct {
+		depth    string
+		words    int
+		min, max time.Duration
+	}{
+		{config.ProfileReadingNone, 500, 0, 0},
+		{config.ProfileReadingLight, 0, lightMinDwell, lightMinDwell},
+		{config.ProfileReadingLight, 60, 14 * time.Second, 22 * time.Second},
+		{config.ProfileReadingLight, 5000, 28 * time.Second, 44 * time.Second}, // 120 words skimmed
+		{config.ProfileReadingFull, 0, fullMinDwell, fullMinDwell},
+		{config.ProfileReadingFull, 5000, 144 * time.Second, 216 * time.Second}, // 600 words read
+	} {
+		if got := ProfileDwell(timing, tt.depth, tt.words); got < tt.min || got > tt.max {
+			t.Errorf("ProfileDwell(%s, %d) = %s, want %s to %s", tt.depth, tt.words, got, tt.min, tt.max)
+		}
+	}
+}
+
+func TestReadProfile(t *testing.T) {
+	const profile = `<main><section><div id="about"></div><p>About Ada</p></section>
+		<section><div id="experience"></div><ul>
+			<li class="artdeco-list__item">Analyst</li><li class="artdeco-list__item">Mathematician</li>
+		</ul></section></main>`
+
+	for _, tt := range []struct {
+		depth    string
+		scrolls  []int         // the scroll counts allowed
+		dwell    time.Duration // the time read, allowing for rounding
+		sections string        // the sections counted
+	}{
+		{config.ProfileReadingNone, []int{1}, 0, ""},
+		{config.ProfileReadingLight, []int{2, 3}, 36 * time.Second, "[about]"},
+		{config.ProfileReadingFull, []int{2, 3}, 100 * time.Second, "[about experience]"}, // a third of the budget
+	} {
+		t.Run(tt.depth, func(t *testing.T) {
+			hovered := false
+			for seed := int64(0); seed < 20; seed++ {
+				h := newHarness(t, "")
+				h.page.SetHTML(profile)
+				h.cm.SetProfileReading(tt.depth)
+				h.cm.SetRand(rand.New(rand.NewSource(seed)))
+				fake := clock.NewFake(time.Now())
+				h.cm.timing.SetClock(fake)
+
+				var counted string
+				h.page.EvalFunc = func(js string, args ...interface{}) (string, error) {
+					counted = fmt.Sprint(args...)
+					return "1000", nil
+				}
+
+				h.cm.readProfile()
+
+				scrolls := h.cm.scroller.(*pagetest.Scroller).Scrolls
+				if scrolls < tt.scrolls[0] || scrolls > tt.scrolls[len(tt.scrolls)-1] {
+					t.Fatalf("seed %d: scrolled %d times, want %v", seed, scrolls, tt.scrolls)
+				}
+				if counted != tt.sections {
+					t.Fatalf("seed %d: counted the words of %s, want %s", seed, counted, tt.sections)
+				}
+				var read time.Duration
+				for _, d := range fake.Slept() {
+					read += d
+				}
+				if tt.dwell > 0 && (read < tt.dwell*3/4 || read > tt.dwell*5/4) {
+					t.Fatalf("seed %d: read for %s, want about %s", seed, read, tt.dwell)
+				}
+				hovered = hovered || len(h.clicker.Hovered) > 0
+			}
+			if hovered != (tt.depth == config.ProfileReadingFull) {
+				t.Fatalf("hovered over a position: %v", hovered)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package connections
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// StatusSending marks a write-ahead row created just before the Send click
+const StatusSending = "sending"
+
+// Relationship states shown on a profile page
+const (
+	ProfileConnectable = "connectable"
+	ProfilePending     = "pending"
+	ProfileConnected   = "connected"
+	ProfileFollowOnly  = "follow_only" // Follow is offered instead of Connect
+	ProfileUnknown     = "unknown"
+)
+
+// ReconcileResult summarizes a reconciliation pass over in-flight requests
+type ReconcileResult struct {
+	Confirmed  int // the invitation went out before the crash
+	Discarded  int // the click never happened, the profile can be contacted again
+	Unresolved int // the profile state couldn't be determined
+}
+
+// ReconcileInFlight checks every request left in the sending state by an interrupted run
+// and records what actually happened on LinkedIn
+func (cm *ConnectionManager) ReconcileInFlight() (*ReconcileResult, error) {
+	requests, err := cm.db.GetConnectionRequestsByStatus(StatusSending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get in-flight requests: %w", err)
+	}
+
+	result := &ReconcileResult{}
+	if len(requests) == 0 {
+		return result, nil
+	}
+
+	cm.log.Infof("Reconciling %d in-flight connection requests", len(requests))
+
+	base := cm.log
+	defer func() { cm.log = base }()
+	for _, request := range requests {
+		cm.log = base.With(logger.ProfileFields(request.ProfileURL, request.ProfileName, "reconcile")...)
+
+		if err := cm.page.Navigate(request.ProfileURL); err != nil {
+			cm.log.Warnf("Failed to open %s for reconciliation: %v", request.ProfileURL, err)
+			result.Unresolved++
+			continue
+		}
+
+		if err := cm.page.WaitLoad(); err != nil {
+			cm.log.Warnf("Profile page load wait failed: %v", err)
+		}
+
+		cm.timing.Wait(cm.timing.ThinkTime())
+		cm.layout = selectors.DetectLayout(cm.page)
+
+		state := cm.detectProfileState()
+		switch state {
+		case ProfilePending, ProfileConnected:
+			status := "pending"
+			if state == ProfileConnected {
+				status = "accepted"
+			}
+			if err := cm.db.SetConnectionRequestStatus(request.ID, status); err != nil {
+				cm.log.Errorf("Failed to update reconciled request: %v", err)
+				continue
+			}
+			if err := cm.db.MarkProfileContacted(request.ProfileURL); err != nil {
+				cm.log.Errorf("Failed to mark profile as contacted: %v", err)
+			}
+			result.Confirmed++
+		case ProfileConnectable, ProfileFollowOnly:
+			if err := cm.db.DeleteConnectionRequest(request.ID); err != nil {
+				cm.log.Errorf("Failed to discard unsent request: %v", err)
+				continue
+			}
+			result.Discarded++
+		default:
+			cm.log.Warnf("Could not determine state of %s, leaving it in-flight", request.ProfileURL)
+			result.Unresolved++
+			continue
+		}
+
+		cm.log.Infof("Reconciled %s: profile is %s", request.ProfileName, state)
+		cm.db.Audit(storage.AuditEntry{Action: "reconcile", ProfileURL: request.ProfileURL, Details: fmt.Sprintf("%s: %s", request.ProfileName, state),
+			Result: storage.AuditSuccess, PageURL: pageops.CurrentURL(cm.page)})
+		cm.timing.Wait(cm.timing.ActionDelay())
+	}
+
+	return result, nil
+}
+
+// detectProfileState reads the relationship state from the open profile's action buttons.
+// A profile that leads with Follow is still connectable when its More menu offers Connect.
+func (cm *ConnectionManager) detectProfileState() string {
+	state := classifyProfileState(cm.page, cm.layout)
+	if state == ProfileFollowOnly && cm.connectUnderMore() {
+		return ProfileConnectable
+	}
+	return state
+}
+
+// connectUnderMore opens the profile's More menu and reports whether it offers Connect.
+// The menu is closed again, so reading the state doesn't change the page.
+func (cm *ConnectionManager) connectUnderMore() bool {
+	if !selectors.Has(cm.page, selectors.ProfileMoreButton) {
+		return false
+	}
+	if err := cm.openMoreMenu(); err != nil {
+		cm.log.Debugf("%v", err)
+		return false
+	}
+	found := selectors.Has(cm.page, selectors.MoreMenuConnectItem)
+
+	if escaper, ok := cm.page.(pageops.Escaper); ok {
+		if err := escaper.PressEscape(); err != nil {
+			cm.log.Debugf("Failed to close the More menu: %v", err)
+		}
+	}
+	return found
+}
+
+// classifyProfileState reads the relationship state from a profile's action buttons,
+// looking for Connect where the profile's layout puts it
+func classifyProfileState(page pageops.ElementFinder, layout string) string {
+	if selectors.Has(page, selectors.PendingButton) {
+		return ProfilePending
+	}
+
+	if selectors.Has(page, selectors.ForLayout(selectors.ConnectButton, layout)) {
+		return ProfileConnectable
+	}
+
+	// Message without Connect means we're already connected; open profiles show both.
+	// Creators also offer Message next to Follow, so then the degree badge decides.
+	following := selectors.Has(page, selectors.ProfileFollowButton)
+	if selectors.Has(page, selectors.ProfileMessageButton) && (!following || firstDegree(page)) {
+		return ProfileConnected
+	}
+
+	if following {
+		return ProfileFollowOnly
+	}
+
+	return ProfileUnknown
+}
+
+// firstDegree reports whether the profile's distance badge says 1st
+func firstDegree(page pageops.ElementFinder) bool {
+	badge, err := selectors.FindFirst(page, selectors.ProfileDistanceBadge)
+	if err != nil {
+		return false
+	}
+	text, _ := badge.Text()
+	return strings.Contains(text, "1st")
+}
+
+// CheckProfile opens a profile and returns the relationship LinkedIn shows. A pending
+// request to someone now connected is marked accepted, so single profiles can be
+// checked for acceptance without the connections list.
+func (cm *ConnectionManager) CheckProfile(profileURL, profileName string) (string, error) {
+	defer cm.forProfile(profileURL, profileName, "check_profile")()
+
+	if err := cm.openProfile(profileURL); err != nil {
+		return "", err
+	}
+
+	state := cm.detectProfileState()
+	if state == ProfileConnected {
+		cm.acceptPending(profileURL, profileName)
+	}
+	return state, nil
+}
+
+// acceptPending marks the profile's pending request accepted, if there is one
+func (cm *ConnectionManager) acceptPending(profileURL, profileName string) {
+	accepted, err := cm.db.AcceptPendingRequest(profileURL)
+	if err != nil {
+		cm.log.Errorf("Failed to mark request accepted: %v", err)
+		return
+	}
+	if accepted {
+		cm.log.Infof("Connection request accepted by %s", profileName)
+		cm.db.Audit(storage.AuditEntry{Action: "connection_accepted", ProfileURL: profileURL, Details: profileName,
+			Result: storage.AuditSuccess, PageURL: pageops.CurrentURL(cm.page)})
+	}
+}
+
+// recordExisting records a profile LinkedIn already shows as pending or connected. A
+// request on record is kept, flipping from pending to accepted once connected or starting
+// its re-invite cooldown over while still pending. Without one the profile is only marked
+// contacted: nothing was sent, so nothing counts against the limits or the sent stats.
+func (cm *ConnectionManager) recordExisting(profileURL, profileName, state string) {
+	recorded, err := cm.db.HasConnectionRequest(profileURL)
+	if err != nil {
+		cm.log.Errorf("Failed to look up connection request: %v", err)
+	}
+	if recorded {
+		if state == ProfileConnected {
+			cm.acceptPending(profileURL, profileName)
+		} else if err := cm.db.MarkStillPending(profileURL); err != nil {
+			cm.log.Errorf("Failed to update pending request: %v", err)
+		}
+	}
+
+	if err := cm.db.MarkProfileContacted(profileURL); err != nil {
+		cm.log.Errorf("Failed to mark profile as contacted: %v", err)
+	}
+}
+
+// discardWriteAhead removes the sending row for a request whose click didn't go through
+func (cm *ConnectionManager) discardWriteAhead(request *storage.ConnectionRequest) {
+	if err := cm.db.DeleteConnectionRequest(request.ID); err != nil {
+		cm.log.Errorf("Failed to discard write-ahead row: %v", err)
+	}
+}
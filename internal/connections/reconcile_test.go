@@ -0,0 +1,182 @@
+package connections
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops/pagetest"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+func TestClassifyProfileState(t *testing.T) {
+	creator := func(degree string) string {
+		return `<main><section class="pv-top-card"><span class="dist-value">` + degree + `</span>
+			<div class="pvs-profile-actions"><button>Follow</button><button>Message</button></div></section></main>`
+	}
+	// The redesigned top card's Connect is an icon button named only by its aria-label
+	topCard := func(buttons string) string {
+		return `<main><section class="profile-top-card"><h1>Ada Lovelace</h1>
+			<div data-view-name="profile-top-card-sticky-header">` + buttons + `</div></section></main>`
+	}
+	iconConnect := `<button aria-label="Invite Ada Lovelace to connect"></button>`
+
+	for _, tt := range []struct {
+		name   string
+		html   string
+		layout string
+		want   string
+	}{
+		{"connect", profilePage("Connect", "More"), selectors.LayoutClassic, ProfileConnectable},
+		{"pending", profilePage("Pending", "Message"), selectors.LayoutClassic, ProfilePending},
+		{"connected", profilePage("Message", "More"), selectors.LayoutClassic, ProfileConnected},
+		{"follow only", profilePage("Follow", "More"), selectors.LayoutClassic, ProfileFollowOnly},
+		{"creator we follow", creator("2nd"), selectors.LayoutClassic, ProfileFollowOnly},
+		{"creator we're connected to", creator("1st"), selectors.LayoutClassic, ProfileConnected},
+		{"no actions", `<main><h1>Ada Lovelace</h1></main>`, selectors.LayoutClassic, ProfileUnknown},
+		{"top card connect", topCard(`<button>Follow</button>` + iconConnect), selectors.LayoutTopCard, ProfileConnectable},
+		{"top card connect read as classic", topCard(`<button>Follow</button>` + iconConnect), selectors.LayoutClassic, ProfileFollowOnly},
+		{"top card pending", topCard(`<button>Pending</button><button>Message</button>`), selectors.LayoutTopCard, ProfilePending},
+		{"top card connected", topCard(`<a href="/messaging/">Message</a><button>More</button>`), selectors.LayoutTopCard, ProfileConnected},
+		{"top card follow only", topCard(`<button>Follow</button><button>More</button>`), selectors.LayoutTopCard, ProfileFollowOnly},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyProfileState(pagetest.New(profileURL, tt.html), tt.layout); got != tt.want {
+				t.Fatalf("state = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// moreMenuProfile is a profile leading with Follow whose More menu offers Connect when offered is set
+func moreMenuProfile(open, offered bool) string {
+	menu := ""
+	if open {
+		item := `<div role="button" aria-label="Save to PDF">Save to PDF</div>`
+		if offered {
+			item += `<div role="button" aria-label="Invite Ada Lovelace to connect">Connect</div>`
+		}
+		menu = `<div class="artdeco-dropdown__content">` + item + `</div>`
+	}
+	return `<main><section class="pv-top-card"><h1>Ada Lovelace</h1><div class="pvs-profile-actions">
+		<button>Follow</button><button aria-label="More actions">More</button>` + menu + `</div></section></main>`
+}
+
+func TestDetectProfileStateLooksUnderMore(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		offered bool
+		want    string
+	}{
+		{"connect under more", true, ProfileConnectable},
+		{"follow only", false, ProfileFollowOnly},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, "")
+			h.page.SetHTML(moreMenuProfile(false, tt.offered))
+			h.page.OnClick("button[aria-label='More actions']", func(*pagetest.Element) { h.page.SetHTML(moreMenuProfile(true, tt.offered)) })
+			h.page.OnEscape(func() { h.page.SetHTML(moreMenuProfile(false, tt.offered)) })
+
+			if got := h.cm.detectProfileState(); got != tt.want {
+				t.Fatalf("state = %s, want %s", got, tt.want)
+			}
+			if h.page.Escapes != 1 || selectors.Has(h.page, selectors.MoreMenuConnectItem) {
+				t.Fatalf("the More menu was left open after %d Escape presses", h.page.Escapes)
+			}
+		})
+	}
+}
+
+func TestReconcileInFlight(t *testing.T) {
+	h := newHarness(t, "")
+
+	// Requests an interrupted run left between the write-ahead row and the confirmation
+	profiles := []struct {
+		url, html, status string // the status left on record; "" once discarded
+	}{
+		{"https://www.linkedin.com/in/sent/", profilePage("Pending", "Message"), "pending"},
+		{"https://www.linkedin.com/in/accepted-since/", profilePage("Message", "More"), "accepted"},
+		{"https://www.linkedin.com/in/never-clicked/", profilePage("Connect", "More"), ""},
+		{"https://www.linkedin.com/in/unreadable/", `<main></main>`, StatusSending},
+	}
+	for _, p := range profiles {
+		h.page.Serve(p.url, p.html)
+		err := h.db.SaveConnectionRequest(&storage.ConnectionRequest{ProfileURL: p.url, ProfileName: "Ada Lovelace", Status: StatusSending,
+			SentAt: time.Now(), UpdatedAt: time.Now()})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := h.cm.ReconcileInFlight()
+	if err != nil {
+		t.Fatalf("ReconcileInFlight: %v", err)
+	}
+	if *result != (ReconcileResult{Confirmed: 2, Discarded: 1, Unresolved: 1}) {
+		t.Fatalf("result = %+v", *result)
+	}
+
+	for _, p := range profiles {
+		recorded, err := h.db.HasConnectionRequest(p.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if recorded != (p.status != "") {
+			t.Errorf("%s recorded: %v, want %q", p.url, recorded, p.status)
+		}
+		if p.status == "" {
+			continue
+		}
+		requests, _ := h.db.GetConnectionRequestsByStatus(p.status)
+		found := false
+		for _, r := range requests {
+			found = found || r.ProfileURL == p.url
+		}
+		if !found {
+			t.Errorf("%s isn't %s", p.url, p.status)
+		}
+	}
+
+	// A second pass has only the unresolved request left to look at
+	h.page.Navigations = nil
+	if _, err := h.cm.ReconcileInFlight(); err != nil {
+		t.Fatal(err)
+	}
+	if len(h.page.Navigations) != 1 {
+		t.Fatalf("second pass opened %v", h.page.Navigations)
+	}
+}
+
+func TestCheckProfileAcceptsPending(t *testing.T) {
+	creator := `<main><section class="pv-top-card"><span class="dist-value">1st</span>
+		<div class="pvs-profile-actions"><button>Follow</button><button>Message</button></div></section></main>`
+
+	for _, tt := range []struct {
+		name    string
+		profile string
+		state   string
+		status  string
+	}{
+		{"still pending", profilePage("Pending", "Message"), ProfilePending, "pending"},
+		{"connected", profilePage("Message", "More"), ProfileConnected, "accepted"},
+		{"connected creator", creator, ProfileConnected, "accepted"},
+		{"withdrawn", profilePage("Connect", "More"), ProfileConnectable, "pending"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, tt.profile)
+			h.sentAt(t, profileURL, time.Now().AddDate(0, 0, -3), false)
+
+			state, err := h.cm.CheckProfile(profileURL, "Ada Lovelace")
+			if err != nil {
+				t.Fatalf("CheckProfile: %v", err)
+			}
+			if state != tt.state {
+				t.Fatalf("state = %s, want %s", state, tt.state)
+			}
+			requests, _ := h.db.GetConnectionRequestsByStatus(tt.status)
+			if len(requests) != 1 || requests[0].ProfileURL != profileURL {
+				t.Fatalf("%s requests = %+v, want the profile's", tt.status, requests)
+			}
+		})
+	}
+}
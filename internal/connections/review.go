@@ -0,0 +1,80 @@
+package connections
+
+import (
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// Reasons an invite the operator reviewed went unsent. An unanswered one is offered again
+// later; a rejected one is not.
+const (
+	ReasonReviewRejected   = "rejected in review"
+	ReasonReviewUnanswered = "unanswered in review"
+)
+
+// ReviewVerdict is the operator's answer to an invite review
+type ReviewVerdict int
+
+// Review verdicts
+const (
+	ReviewApproved   ReviewVerdict = iota // send, with the note Review returned
+	ReviewRejected                        // skip the profile
+	ReviewUnanswered                      // nobody answered in time
+)
+
+// InviteReview is an invite about to be sent, as shown to the operator
+type InviteReview struct {
+	ProfileURL   string
+	ProfileName  string
+	Headline     string
+	Company      string
+	Note         string                  // the rendered note; empty for none
+	NoteEditable bool                    // the note field is open, so the note may be edited
+	Check        func(note string) error // vets an edited note before it is accepted
+}
+
+// Reviewer has invites approved before they are sent
+type Reviewer interface {
+	// Review shows an invite and returns the operator's verdict, with the note to send,
+	// edited or not, when it is approved
+	Review(invite InviteReview) (note string, verdict ReviewVerdict)
+}
+
+// SetReviewer has r approve each invite before it is sent. hold is called around each
+// review and returns the func that ends the hold, so time spent waiting on the operator
+// can be kept out of the session's budgets; it may be nil.
+func (cm *ConnectionManager) SetReviewer(r Reviewer, hold func() (resume func())) {
+	cm.reviewer = r
+	cm.reviewHold = hold
+}
+
+// review has the reviewer approve an invite, if there is one. The profile's time budget
+// doesn't run while the operator is asked.
+func (cm *ConnectionManager) review(invite InviteReview) (string, ReviewVerdict) {
+	if cm.reviewer == nil {
+		return invite.Note, ReviewApproved
+	}
+
+	defer pageops.PauseDeadline(cm.page)()
+	if cm.reviewHold != nil {
+		defer cm.reviewHold()()
+	}
+	invite.Check = lintNote
+	return cm.reviewer.Review(invite)
+}
+
+// declineReview settles an invite the operator didn't approve as skipped
+func (cm *ConnectionManager) declineReview(result *RequestResult, verdict ReviewVerdict, started time.Time) *RequestResult {
+	result.Outcome = OutcomeSkipped
+	result.Reason = ReasonReviewRejected
+	if verdict == ReviewUnanswered {
+		result.Reason = ReasonReviewUnanswered
+	}
+
+	cm.log.Infof("Not sending to %s: %s", result.ProfileName, result.Reason)
+	cm.db.Audit(storage.AuditEntry{Action: "connection_skipped", ProfileURL: result.ProfileURL, Details: result.Reason,
+		Result: storage.AuditSkipped, PageURL: pageops.CurrentURL(cm.page), Started: started})
+	return result
+}
@@ -0,0 +1,73 @@
+package connections
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeReviewer answers every review with verdict and records what the page's deadline
+// looked like while it was asked
+type fakeReviewer struct {
+	h       *harness
+	note    string
+	verdict ReviewVerdict
+	invites []InviteReview
+	budget  time.Duration
+	paused  bool
+}
+
+func (r *fakeReviewer) Review(invite InviteReview) (string, ReviewVerdict) {
+	r.invites = append(r.invites, invite)
+	r.budget, r.paused = r.h.page.DeadlineState()
+	return r.note, r.verdict
+}
+
+func TestReviewPausesProfileDeadline(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		verdict ReviewVerdict
+		outcome string
+		reason  string
+	}{
+		{"approved", ReviewApproved, OutcomeSent, ""},
+		{"rejected", ReviewRejected, OutcomeSkipped, ReasonReviewRejected},
+		{"unanswered", ReviewUnanswered, OutcomeSkipped, ReasonReviewUnanswered},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, profilePage("Connect"))
+			h.openInvites()
+
+			reviewer := &fakeReviewer{h: h, note: "Edited note for Ada", verdict: tt.verdict}
+			held, resumed := 0, 0
+			h.cm.SetReviewer(reviewer, func() func() {
+				held++
+				return func() { resumed++ }
+			})
+
+			result, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "", "", false)
+			if err != nil {
+				t.Fatalf("SendConnectionRequest: %v", err)
+			}
+			if result.Outcome != tt.outcome || result.Reason != tt.reason {
+				t.Fatalf("result = %+v, want %s %q", result, tt.outcome, tt.reason)
+			}
+
+			if len(reviewer.invites) != 1 || !reviewer.invites[0].NoteEditable || reviewer.invites[0].Check == nil {
+				t.Fatalf("reviewed %+v, want the invite with an editable note", reviewer.invites)
+			}
+			if reviewer.budget != h.cfg.ProfileTimeout() || !reviewer.paused {
+				t.Fatalf("during the review the deadline was %s, paused %v; want %s paused", reviewer.budget, reviewer.paused, h.cfg.ProfileTimeout())
+			}
+			if budget, paused := h.page.DeadlineState(); budget != 0 || paused {
+				t.Fatalf("after the request the deadline is %s, paused %v; want it released", budget, paused)
+			}
+			if held != 1 || resumed != 1 {
+				t.Fatalf("held %d, resumed %d times; want once each", held, resumed)
+			}
+
+			if tt.verdict == ReviewApproved && (result.Note != reviewer.note || result.TemplateID != "") {
+				t.Fatalf("sent note %q from template %q, want the edited note", result.Note, result.TemplateID)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package connections
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// What opening a profile can land on instead of the profile
+const (
+	PageAuthwall = "authwall"  // LinkedIn's sign-in wall: the session was signed out
+	PageNotFound = "not_found" // the profile was deleted or never existed
+)
+
+// ReasonUnavailable is the skip reason of a profile that was deleted or doesn't exist
+const ReasonUnavailable = "profile unavailable"
+
+// Activity log actions telling a lost session apart from a missing profile
+const (
+	ActivityAuthwall           = "authwall"
+	ActivityProfileUnavailable = "profile_unavailable"
+)
+
+// detectUnavailable reports what the page at url landed on instead of a profile: the
+// sign-in wall, the 404 page, or "" for a profile. A deleted profile can also redirect
+// to /in/unavailable/.
+func detectUnavailable(url string, scope selectors.Scope) string {
+	switch {
+	case pageops.IsAuthwallURL(url):
+		return PageAuthwall
+	case strings.Contains(url, "/404") || strings.Contains(url, "/in/unavailable"):
+		return PageNotFound
+	case selectors.Has(scope, selectors.ProfileNotFound):
+		return PageNotFound
+	}
+	return ""
+}
+
+// SetSessionCheck sets how the session is checked and signed in again once LinkedIn puts a
+// profile behind its sign-in wall; without one the wall fails the request
+func (cm *ConnectionManager) SetSessionCheck(ensureLoggedIn func() error) {
+	cm.ensureLoggedIn = ensureLoggedIn
+}
+
+// loadProfile opens a profile page and reports whether it was deleted or doesn't exist. The
+// sign-in wall says nothing about the profile, so the session is checked instead and the
+// profile opened once more; ErrAuthwall is returned when it lands on the wall again.
+func (cm *ConnectionManager) loadProfile(profileURL string, started time.Time) (notFound bool, err error) {
+	for attempt := 0; ; attempt++ {
+		if err := cm.page.Navigate(profileURL); err != nil {
+			return false, cm.captureFailure(fmt.Errorf("failed to navigate to profile: %w", err))
+		}
+		if err := cm.page.WaitLoad(); err != nil {
+			return false, cm.captureFailure(fmt.Errorf("failed to wait for profile page: %w", err))
+		}
+
+		url := pageops.CurrentURL(cm.page)
+		switch detectUnavailable(url, cm.page) {
+		case PageNotFound:
+			return true, nil
+		case "":
+			return false, nil
+		}
+
+		cm.log.Warnf("LinkedIn put the profile behind its sign-in wall: %s", url)
+		cm.db.Audit(storage.AuditEntry{Action: ActivityAuthwall, ProfileURL: profileURL, Details: "session signed out",
+			Result: storage.AuditFailed, PageURL: url, Started: started})
+		if attempt > 0 || cm.ensureLoggedIn == nil {
+			return false, fmt.Errorf("%w: %s", pageops.ErrAuthwall, url)
+		}
+		if err := cm.ensureLoggedIn(); err != nil {
+			return false, fmt.Errorf("%w, and signing in again failed: %v", pageops.ErrAuthwall, err)
+		}
+	}
+}
+
+// recordUnavailable marks a deleted or missing profile so it is never retried
+func (cm *ConnectionManager) recordUnavailable(profileURL, profileName string, started time.Time) {
+	if err := cm.db.MarkProfileUnavailable(profileURL); err != nil {
+		cm.log.Errorf("%v", err)
+	}
+	cm.db.Audit(storage.AuditEntry{Action: ActivityProfileUnavailable, ProfileURL: profileURL, Details: profileName,
+		Result: storage.AuditSkipped, PageURL: pageops.CurrentURL(cm.page), Started: started})
+}
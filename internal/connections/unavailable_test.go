@@ -0,0 +1,132 @@
+package connections
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops/pagetest"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+const authwallURL = "https://www.linkedin.com/authwall?trk=bf&sessionRedirect=ada-lovelace"
+
+func TestDetectUnavailable(t *testing.T) {
+	for _, tt := range []struct {
+		name, url, html, want string
+	}{
+		{"profile", profileURL, profilePage("Connect"), ""},
+		{"sign-in wall", authwallURL, `<main><h1>Join LinkedIn</h1></main>`, PageAuthwall},
+		{"404 redirect", "https://www.linkedin.com/404/", `<main></main>`, PageNotFound},
+		{"unavailable redirect", "https://www.linkedin.com/in/unavailable/", `<main></main>`, PageNotFound},
+		{"404 page", profileURL, `<main><div class="not-found-404"></div></main>`, PageNotFound},
+		{"404 heading", profileURL, `<main><h1>This page doesn't exist</h1></main>`, PageNotFound},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectUnavailable(tt.url, pagetest.New(tt.url, tt.html)); got != tt.want {
+				t.Fatalf("detectUnavailable = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendConnectionRequestSkipsUnavailableProfile(t *testing.T) {
+	h := newHarness(t, `<main><h1>This page doesn't exist</h1></main>`)
+	if _, err := h.db.SaveSearchResults([]*storage.SearchResult{{ProfileURL: profileURL, ProfileName: "Ada Lovelace", FoundAt: time.Now()}}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "", "", false)
+	if err != nil {
+		t.Fatalf("SendConnectionRequest: %v", err)
+	}
+	if result.Outcome != OutcomeSkipped || result.Reason != ReasonUnavailable {
+		t.Fatalf("result = %s (%s), want skipped as unavailable", result.Outcome, result.Reason)
+	}
+	if len(h.clicker.Clicked) != 0 {
+		t.Fatalf("clicked %v on a missing profile", h.clicker.Clicked)
+	}
+
+	history, err := h.db.ProfileHistory(profileURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(history); n == 0 || history[n-1].Action != ActivityProfileUnavailable || history[n-1].Result != storage.AuditSkipped {
+		t.Fatalf("activity = %+v, want the profile skipped as unavailable", history)
+	}
+
+	// It's never selected again
+	profiles, err := h.db.GetUncontactedProfiles("", 10, storage.ProspectPolicy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profiles) != 0 {
+		t.Fatalf("uncontacted profiles = %+v, want none", profiles)
+	}
+}
+
+func TestSendConnectionRequestSignsInAgainAtAuthwall(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		check    bool // whether a session check is set
+		clears   bool // whether signing in again lifts the wall
+		checkErr error
+		logins   int
+		sent     bool
+	}{
+		{"signed in again", true, true, nil, 1, true},
+		{"wall again", true, false, nil, 1, false},
+		{"sign-in fails", true, false, errors.New("wrong password"), 1, false},
+		{"no session check", false, false, nil, 0, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, profilePage("Connect", "More"))
+			h.openInvites()
+			h.page.Redirect(profileURL, authwallURL)
+			h.page.Serve(authwallURL, `<main><h1>Join LinkedIn</h1></main>`)
+
+			logins := 0
+			if tt.check {
+				h.cm.SetSessionCheck(func() error {
+					logins++
+					if tt.clears {
+						h.page.Redirect(profileURL, profileURL)
+					}
+					return tt.checkErr
+				})
+			}
+
+			result, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "", "", false)
+			if logins != tt.logins {
+				t.Fatalf("signed in %d times, want %d", logins, tt.logins)
+			}
+			if tt.sent {
+				if err != nil || result.Outcome != OutcomeSent {
+					t.Fatalf("result = %+v, err = %v, want sent", result, err)
+				}
+				return
+			}
+			if !errors.Is(err, pageops.ErrAuthwall) {
+				t.Fatalf("err = %v, want the sign-in wall", err)
+			}
+
+			// The wall is the session's problem: the profile isn't failed or recorded
+			history, err := h.db.ProfileHistory(profileURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(history) == 0 {
+				t.Fatal("the sign-in wall wasn't audited")
+			}
+			for _, entry := range history {
+				if entry.Action != ActivityAuthwall {
+					t.Fatalf("activity = %+v, want only the sign-in wall", history)
+				}
+			}
+			if recorded, _ := h.db.HasConnectionRequest(profileURL); recorded {
+				t.Fatal("a request was recorded behind the sign-in wall")
+			}
+		})
+	}
+}
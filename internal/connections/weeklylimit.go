@@ -0,0 +1,125 @@
+package connections
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// Patterns of the counter in the invite modal's weekly limit warning, e.g. "You have 12
+// invitations left this week", "You can send 5 more invitations this week" or "You've
+// sent 88 of 100 invitations this week"
+var (
+	invitesLeftRe = regexp.MustCompile(`(?i)(\d+)\s+(?:more\s+)?invitations?\s+(?:left|remaining)|send\s+(\d+)\s+more\s+invitations?`)
+	invitesUsedRe = regexp.MustCompile(`(?i)(\d+)\s*(?:/|of|out of)\s*(\d+)\s+(?:weekly\s+)?invitations?`)
+)
+
+// ParseInviteCounter reads how many invitations LinkedIn says are left this week from
+// the text of its weekly limit warning. ok is false when the text carries no count.
+func ParseInviteCounter(text string) (remaining int, ok bool) {
+	text = strings.Join(strings.Fields(text), " ")
+
+	if m := invitesLeftRe.FindStringSubmatch(text); m != nil {
+		n := m[1]
+		if n == "" {
+			n = m[2]
+		}
+		left, err := strconv.Atoi(n)
+		return left, err == nil
+	}
+
+	if m := invitesUsedRe.FindStringSubmatch(text); m != nil {
+		used, err1 := strconv.Atoi(m[1])
+		total, err2 := strconv.Atoi(m[2])
+		if err1 != nil || err2 != nil || used > total {
+			return 0, false
+		}
+		return total - used, true
+	}
+
+	return 0, false
+}
+
+// readInviteCounter records the count of the weekly limit warning when the invite modal
+// shows one, and reports whether LinkedIn says none are left
+func (cm *ConnectionManager) readInviteCounter() bool {
+	el, err := selectors.FindFirst(cm.page, selectors.InviteLimitWarning)
+	if err != nil {
+		return false
+	}
+	text, _ := el.Text()
+	remaining, ok := ParseInviteCounter(text)
+	if !ok {
+		cm.log.Infof("LinkedIn warns of the weekly invitation limit without a count: %s", strings.TrimSpace(text))
+		return false
+	}
+
+	cm.log.Infof("LinkedIn reports %d invitations left this week", remaining)
+	if err := cm.db.SaveLimitState(storage.LimitWeeklyInvites, remaining, time.Now()); err != nil {
+		cm.log.Errorf("%v", err)
+	}
+	return remaining == 0
+}
+
+// WeeklyAllowance is what the weekly limit leaves of this week's requests
+type WeeklyAllowance struct {
+	Sent         int  // requests of the last 7 days
+	Limit        int  // the effective limit; 0 for none
+	Remaining    int  // requests still allowed; only meaningful with a Limit
+	FromLinkedIn bool // LinkedIn's counter lowered the limit below connections.weekly_limit
+}
+
+// String describes the allowance for logs and reports
+func (a WeeklyAllowance) String() string {
+	if a.Limit == 0 {
+		return fmt.Sprintf("%d sent, no weekly limit", a.Sent)
+	}
+	s := fmt.Sprintf("%d/%d, %d left", a.Sent, a.Limit, a.Remaining)
+	if a.FromLinkedIn {
+		s += " (as LinkedIn reports)"
+	}
+	return s
+}
+
+// GetWeeklyAllowance reconciles the configured weekly limit with the count LinkedIn last
+// showed this week: when LinkedIn said fewer invitations were left than the limit allows,
+// less the requests sent since, LinkedIn is trusted for the rest of the week
+func GetWeeklyAllowance(db *storage.DB, configured int, now time.Time) (*WeeklyAllowance, error) {
+	sent, err := WeeklyRequestCount(db, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weekly connection count: %w", err)
+	}
+	allowance := &WeeklyAllowance{Sent: sent, Limit: configured}
+
+	state, err := db.GetLimitState(storage.LimitWeeklyInvites)
+	if err != nil {
+		return nil, err
+	}
+	if state != nil && !state.ObservedAt.Before(weekStart(now)) {
+		since, err := db.GetConnectionRequestsCountSince(state.ObservedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get connection count since LinkedIn's report: %w", err)
+		}
+		if limit := sent + max(state.Remaining-since, 0); configured == 0 || limit < configured {
+			allowance.Limit = limit
+			allowance.FromLinkedIn = true
+		}
+	}
+
+	if allowance.Limit > 0 {
+		allowance.Remaining = max(allowance.Limit-sent, 0)
+	}
+	return allowance, nil
+}
+
+// weekStart returns the Monday midnight that began the week of t
+func weekStart(t time.Time) time.Time {
+	days := (int(t.Weekday()) + 6) % 7
+	y, m, d := t.AddDate(0, 0, -days).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
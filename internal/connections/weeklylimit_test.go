@@ -0,0 +1,114 @@
+package connections
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops/pagetest"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+func TestParseInviteCounter(t *testing.T) {
+	for _, tt := range []struct {
+		text      string
+		remaining int
+		ok        bool
+	}{
+		{"You have 12 invitations left this week", 12, true},
+		{"You're approaching the weekly invitation limit. 3 invitations remaining.", 3, true},
+		{"You can send 5 more invitations this week", 5, true},
+		{"You've sent 88 of 100 invitations this week", 12, true},
+		{"You've sent 100/100 weekly invitations", 0, true},
+		{"You have 1\n  invitation   left this week", 1, true},
+		{"You're approaching the weekly invitation limit", 0, false},
+		{"You've sent 120 of 100 invitations this week", 0, false},
+		{"", 0, false},
+	} {
+		remaining, ok := ParseInviteCounter(tt.text)
+		if remaining != tt.remaining || ok != tt.ok {
+			t.Errorf("ParseInviteCounter(%q) = %d, %v; want %d, %v", tt.text, remaining, ok, tt.remaining, tt.ok)
+		}
+	}
+}
+
+func TestGetWeeklyAllowance(t *testing.T) {
+	// A Wednesday; the week began on Monday the 12th
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.Local)
+	tuesday := time.Date(2026, 10, 13, 9, 0, 0, 0, time.Local)
+
+	for _, tt := range []struct {
+		name       string
+		configured int
+		reported   int       // invitations LinkedIn said were left; -1 for no report
+		observed   time.Time // when it said so
+		want       WeeklyAllowance
+	}{
+		{"no report", 100, -1, time.Time{}, WeeklyAllowance{Sent: 3, Limit: 100, Remaining: 97}},
+		{"fewer left on LinkedIn", 100, 5, tuesday, WeeklyAllowance{Sent: 3, Limit: 7, Remaining: 4, FromLinkedIn: true}},
+		{"none left on LinkedIn", 100, 0, tuesday, WeeklyAllowance{Sent: 3, Limit: 3, Remaining: 0, FromLinkedIn: true}},
+		{"more left on LinkedIn", 100, 200, tuesday, WeeklyAllowance{Sent: 3, Limit: 100, Remaining: 97}},
+		{"reported last week", 100, 0, tuesday.AddDate(0, 0, -2), WeeklyAllowance{Sent: 3, Limit: 100, Remaining: 97}},
+		{"no configured limit", 0, 5, tuesday, WeeklyAllowance{Sent: 3, Limit: 7, Remaining: 4, FromLinkedIn: true}},
+		{"neither", 0, -1, time.Time{}, WeeklyAllowance{Sent: 3}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, "")
+			// Two requests before LinkedIn's report and one since
+			for i, at := range []time.Time{tuesday.Add(-20 * time.Hour), tuesday.Add(-19 * time.Hour), tuesday.Add(6 * time.Hour)} {
+				h.sentAt(t, fmt.Sprintf("https://www.linkedin.com/in/member-%d/", i), at, false)
+			}
+			if tt.reported >= 0 {
+				if err := h.db.SaveLimitState(storage.LimitWeeklyInvites, tt.reported, tt.observed); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := GetWeeklyAllowance(h.db, tt.configured, now)
+			if err != nil {
+				t.Fatalf("GetWeeklyAllowance: %v", err)
+			}
+			if *got != tt.want {
+				t.Fatalf("allowance = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendConnectionRequestStopsWhenLinkedInReportsNoneLeft(t *testing.T) {
+	h := newHarness(t, profilePage("Connect"))
+	h.cfg.NoteMode = config.NoteModeNever
+	h.openInvites()
+	h.page.OnClick("button", func(el *pagetest.Element) {
+		if text, _ := el.Text(); text == "Connect" {
+			h.page.SetHTML(profilePage("Connect") + `<div class="artdeco-modal send-invite" role="dialog">
+				<p>You have 0 invitations left this week</p><button aria-label="Send now">Send</button></div>`)
+		}
+	})
+
+	if _, err := h.cm.SendConnectionRequest(profileURL, "Ada Lovelace", "", "", false); !errors.Is(err, ErrWeeklyLimitReached) {
+		t.Fatalf("SendConnectionRequest = %v, want ErrWeeklyLimitReached", err)
+	}
+	for _, label := range h.clicker.Clicked {
+		if label == "Send" {
+			t.Fatal("sent an invite LinkedIn has no room for")
+		}
+	}
+
+	state, err := h.db.GetLimitState(storage.LimitWeeklyInvites)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state == nil || state.Remaining != 0 {
+		t.Fatalf("limit state = %+v, want none left", state)
+	}
+	allowance, err := GetWeeklyAllowance(h.db, 100, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if allowance.Remaining != 0 || !allowance.FromLinkedIn {
+		t.Fatalf("allowance = %s, want none left as LinkedIn reports", allowance)
+	}
+}
@@ -0,0 +1,164 @@
+// Package control lets another terminal pause and resume a running bot. A command is
+// left in a file next to the run's instance lock, which the run polls for, and the run
+// keeps its pause state in a status file beside it.
+package control
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+)
+
+// Commands a run accepts
+const (
+	Pause  = "pause"
+	Resume = "resume"
+)
+
+// pollInterval is how often a run looks for a command
+const pollInterval = 2 * time.Second
+
+// Target is what commands act on; *stealth.Scheduler satisfies it
+type Target interface {
+	Pause()
+	Resume()
+	PauseState() (state string, since time.Time, paused time.Duration)
+}
+
+// Status is a run's pause state as its status file records it
+type Status struct {
+	PID           int       `json:"pid"`
+	State         string    `json:"state"` // running, pausing or paused
+	Since         time.Time `json:"since"` // zero while the run never paused
+	PausedSeconds float64   `json:"paused_seconds"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Paths returns the command and status files of the run holding the lock at lockPath
+func Paths(lockPath string) (command, status string) {
+	base := strings.TrimSuffix(lockPath, filepath.Ext(lockPath))
+	return base + ".control", base + ".status"
+}
+
+// Send leaves command for the run holding the lock at lockPath to pick up
+func Send(lockPath, command string) error {
+	path, _ := Paths(lockPath)
+	if err := writeFile(path, []byte(command+"\n")); err != nil {
+		return fmt.Errorf("failed to send %s: %w", command, err)
+	}
+	return nil
+}
+
+// ReadStatus returns the status of the run holding the lock at lockPath, or nil if it
+// has written none
+func ReadStatus(lockPath string) (*Status, error) {
+	_, path := Paths(lockPath)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run status: %w", err)
+	}
+
+	var status Status
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to parse run status %s: %w", path, err)
+	}
+	return &status, nil
+}
+
+// Watch applies the commands left for the run holding the lock at lockPath to target and
+// keeps its status file current, until the returned stop is called. A command left from
+// before the run started is dropped.
+func Watch(lockPath string, target Target) (stop func()) {
+	commandPath, statusPath := Paths(lockPath)
+	if err := os.Remove(commandPath); err == nil {
+		logger.Warnf("Ignoring a control command left from an earlier run in %s", commandPath)
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w := &watcher{commandPath: commandPath, statusPath: statusPath, target: target}
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			w.poll()
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+			os.Remove(statusPath)
+			os.Remove(commandPath)
+		})
+	}
+}
+
+// watcher applies the commands left in one command file
+type watcher struct {
+	commandPath string
+	statusPath  string
+	target      Target
+	written     string // the state and since last written to the status file
+}
+
+// poll applies a waiting command and records a changed state
+func (w *watcher) poll() {
+	data, err := os.ReadFile(w.commandPath)
+	if err == nil {
+		os.Remove(w.commandPath)
+		switch command := strings.TrimSpace(string(data)); command {
+		case Pause:
+			w.target.Pause()
+		case Resume:
+			w.target.Resume()
+		default:
+			logger.Warnf("Ignoring unknown control command %q", command)
+		}
+	} else if !os.IsNotExist(err) {
+		logger.Warnf("Failed to read control command: %v", err)
+	}
+
+	state, since, paused := w.target.PauseState()
+	key := state + since.String()
+	if key == w.written {
+		return
+	}
+	status := Status{PID: os.Getpid(), State: state, Since: since, PausedSeconds: paused.Seconds(), UpdatedAt: time.Now()}
+	data, err = json.MarshalIndent(status, "", "  ")
+	if err == nil {
+		err = writeFile(w.statusPath, data)
+	}
+	if err != nil {
+		logger.Warnf("Failed to write run status: %v", err)
+		return
+	}
+	w.written = key
+}
+
+// writeFile replaces path with data in one step, so a reader never sees half of it
+func writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
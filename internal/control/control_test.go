@@ -0,0 +1,144 @@
+package control
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger("error", "console", "stdout", logger.Rotation{})
+	os.Exit(m.Run())
+}
+
+// fakeTarget records the commands applied to it
+type fakeTarget struct {
+	pauses, resumes int
+	state           string
+	since           time.Time
+}
+
+func (f *fakeTarget) Pause() {
+	f.pauses++
+	f.state, f.since = "paused", time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+}
+
+func (f *fakeTarget) Resume() {
+	f.resumes++
+	f.state, f.since = "running", time.Date(2024, 3, 4, 10, 30, 0, 0, time.UTC)
+}
+
+func (f *fakeTarget) PauseState() (string, time.Time, time.Duration) {
+	if f.state == "" {
+		return "running", time.Time{}, 0
+	}
+	return f.state, f.since, 0
+}
+
+// newWatcher returns a watcher for a run locked at a path in a fresh directory, and the lock path
+func newWatcher(t *testing.T) (*watcher, *fakeTarget, string) {
+	t.Helper()
+	lockPath := filepath.Join(t.TempDir(), "bot.lock")
+	commandPath, statusPath := Paths(lockPath)
+	target := &fakeTarget{}
+	return &watcher{commandPath: commandPath, statusPath: statusPath, target: target}, target, lockPath
+}
+
+func TestPaths(t *testing.T) {
+	command, status := Paths("/tmp/bot.lock")
+	if command != "/tmp/bot.control" || status != "/tmp/bot.status" {
+		t.Fatalf("Paths = %s, %s, want /tmp/bot.control, /tmp/bot.status", command, status)
+	}
+}
+
+func TestPollAppliesCommands(t *testing.T) {
+	w, target, lockPath := newWatcher(t)
+
+	for _, tt := range []struct {
+		command         string
+		pauses, resumes int
+		state           string
+	}{
+		{Pause, 1, 0, "paused"},
+		{Resume, 1, 1, "running"},
+		{"reboot", 1, 1, "running"}, // unknown commands are ignored
+	} {
+		if err := Send(lockPath, tt.command); err != nil {
+			t.Fatalf("Send(%s): %v", tt.command, err)
+		}
+		w.poll()
+
+		if target.pauses != tt.pauses || target.resumes != tt.resumes {
+			t.Fatalf("after %s: pauses = %d, resumes = %d, want %d, %d", tt.command, target.pauses, target.resumes, tt.pauses, tt.resumes)
+		}
+		if _, err := os.Stat(w.commandPath); !os.IsNotExist(err) {
+			t.Fatalf("after %s: the command file is still there", tt.command)
+		}
+		status, err := ReadStatus(lockPath)
+		if err != nil {
+			t.Fatalf("ReadStatus: %v", err)
+		}
+		if status == nil || status.State != tt.state || status.PID != os.Getpid() {
+			t.Fatalf("after %s: status = %+v, want %s for this process", tt.command, status, tt.state)
+		}
+	}
+}
+
+func TestPollWithoutCommand(t *testing.T) {
+	w, target, lockPath := newWatcher(t)
+
+	w.poll()
+	if target.pauses != 0 || target.resumes != 0 {
+		t.Fatalf("pauses = %d, resumes = %d without a command, want none", target.pauses, target.resumes)
+	}
+	status, err := ReadStatus(lockPath)
+	if err != nil {
+		t.Fatalf("ReadStatus: %v", err)
+	}
+	if status == nil || status.State != "running" || !status.Since.IsZero() {
+		t.Fatalf("status = %+v, want running and never paused", status)
+	}
+}
+
+func TestWatchDropsStaleCommand(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "bot.lock")
+	commandPath, statusPath := Paths(lockPath)
+	if err := Send(lockPath, Pause); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	target := &fakeTarget{}
+	stop := Watch(lockPath, target)
+	stop()
+
+	if target.pauses != 0 {
+		t.Fatalf("a command left from an earlier run paused the run %d times", target.pauses)
+	}
+	for _, path := range []string{commandPath, statusPath} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("%s left behind after stop", filepath.Base(path))
+		}
+	}
+}
+
+func TestReadStatus(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "bot.lock")
+	_, statusPath := Paths(lockPath)
+
+	// No run has written one
+	status, err := ReadStatus(lockPath)
+	if err != nil || status != nil {
+		t.Fatalf("ReadStatus = %+v, %v, want nil, nil", status, err)
+	}
+
+	// A torn or foreign file is an error, not a status
+	if err := os.WriteFile(statusPath, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadStatus(lockPath); err == nil {
+		t.Fatal("ReadStatus of a corrupt file succeeded")
+	}
+}
@@ -0,0 +1,117 @@
+// Package diagnostics records the browser-side signs of the session being flagged: calls to
+// LinkedIn's voyager API that fail, often with 429s well before any page shows a warning,
+// and errors in the page console. It also sums them up for the diagnose command.
+package diagnostics
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// maxFieldLength caps the stored URLs and messages; LinkedIn's are long and repetitive
+const maxFieldLength = 500
+
+// Recorder stores diagnostics; *storage.DB satisfies it
+type Recorder interface {
+	SaveDiagnostic(d *storage.Diagnostic, maxRows int) error
+}
+
+// Listen records the page's failed API calls and console errors until the page closes,
+// keeping the latest maxRows of them
+func Listen(page *rod.Page, rec Recorder, maxRows int) {
+	l := &listener{rec: rec, maxRows: maxRows}
+	go page.EachEvent(l.onResponse, l.onConsole, l.onException, l.onNavigated)()
+}
+
+// listener turns page events into diagnostics
+type listener struct {
+	rec     Recorder
+	maxRows int
+
+	mu      sync.Mutex
+	pageURL string // the page open, for context
+}
+
+// onResponse records a voyager API call that failed; LinkedIn answers 999 when it refuses a client
+func (l *listener) onResponse(e *proto.NetworkResponseReceived) {
+	if e.Response == nil || e.Response.Status < 400 || !IsVoyagerURL(e.Response.URL) {
+		return
+	}
+	l.record(storage.DiagnosticHTTP, e.Response.Status, e.Response.URL, e.Response.StatusText)
+}
+
+// onConsole records console.error calls
+func (l *listener) onConsole(e *proto.RuntimeConsoleAPICalled) {
+	if e.Type != proto.RuntimeConsoleAPICalledTypeError {
+		return
+	}
+
+	var parts []string
+	for _, arg := range e.Args {
+		if arg.Description != "" {
+			parts = append(parts, arg.Description)
+		} else if s := arg.Value.String(); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	var source string
+	if e.StackTrace != nil && len(e.StackTrace.CallFrames) > 0 {
+		source = e.StackTrace.CallFrames[0].URL
+	}
+	l.record(storage.DiagnosticConsole, 0, source, strings.Join(parts, " "))
+}
+
+// onException records uncaught exceptions
+func (l *listener) onException(e *proto.RuntimeExceptionThrown) {
+	details := e.ExceptionDetails
+	if details == nil {
+		return
+	}
+	message := details.Text
+	if details.Exception != nil && details.Exception.Description != "" {
+		message = details.Exception.Description
+	}
+	l.record(storage.DiagnosticException, 0, details.URL, message)
+}
+
+// onNavigated follows the page's URL
+func (l *listener) onNavigated(e *proto.PageFrameNavigated) {
+	if e.Frame != nil && e.Frame.ParentID == "" {
+		l.mu.Lock()
+		l.pageURL = e.Frame.URL
+		l.mu.Unlock()
+	}
+}
+
+// record saves a diagnostic; failing to is only worth a debug line
+func (l *listener) record(kind string, status int, url, message string) {
+	l.mu.Lock()
+	pageURL := l.pageURL
+	l.mu.Unlock()
+
+	d := &storage.Diagnostic{Kind: kind, Status: status, URL: truncate(url), Message: truncate(strings.TrimSpace(message)),
+		PageURL: truncate(pageURL), CreatedAt: time.Now()}
+	if err := l.rec.SaveDiagnostic(d, l.maxRows); err != nil {
+		logger.Debugf("%v", err)
+	}
+}
+
+// IsVoyagerURL reports whether url is a call to LinkedIn's internal voyager API
+func IsVoyagerURL(url string) bool {
+	return strings.Contains(url, "linkedin.com/voyager/api/")
+}
+
+// truncate caps s at maxFieldLength bytes, dropping a character cut in half
+func truncate(s string) string {
+	if len(s) <= maxFieldLength {
+		return s
+	}
+	return strings.ToValidUTF8(s[:maxFieldLength], "")
+}
@@ -0,0 +1,158 @@
+package diagnostics
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// A spike is at least spikeMin throttled calls in the last hour, spikeFactor times the
+// hourly rate before it
+const (
+	spikeMin    = 5
+	spikeFactor = 3
+)
+
+// Count is how often something occurred
+type Count struct {
+	Key string
+	N   int
+}
+
+// Summary sums up the diagnostics recorded in a window
+type Summary struct {
+	Since, Until time.Time
+	Failed       map[int]int // failed API calls by status
+	Console      int
+	Exceptions   int
+	Endpoints    []Count // the API endpoints that failed most
+	Messages     []Count // the most frequent console errors
+
+	Throttled         int     // 429 and 999 responses
+	ThrottledLastHour int     // of them in the last hour
+	ThrottledPerHour  float64 // hourly rate before the last hour
+	Spike             bool
+
+	Advice []string
+}
+
+// Summarize sums up the diagnostics recorded from since to now and suggests what to do
+func Summarize(diagnostics []storage.Diagnostic, since, now time.Time) *Summary {
+	s := &Summary{Since: since, Until: now, Failed: make(map[int]int)}
+	endpoints := make(map[string]int)
+	messages := make(map[string]int)
+	hourAgo := now.Add(-time.Hour)
+
+	for _, d := range diagnostics {
+		switch d.Kind {
+		case storage.DiagnosticHTTP:
+			s.Failed[d.Status]++
+			endpoints[endpoint(d.URL)]++
+			if throttling(d.Status) {
+				s.Throttled++
+				if !d.CreatedAt.Before(hourAgo) {
+					s.ThrottledLastHour++
+				}
+			}
+		case storage.DiagnosticConsole:
+			s.Console++
+			messages[firstLine(d.Message)]++
+		case storage.DiagnosticException:
+			s.Exceptions++
+			messages[firstLine(d.Message)]++
+		}
+	}
+	s.Endpoints = top(endpoints, 5)
+	s.Messages = top(messages, 5)
+
+	if before := hourAgo.Sub(since).Hours(); before > 0 {
+		s.ThrottledPerHour = float64(s.Throttled-s.ThrottledLastHour) / before
+	}
+	s.Spike = s.ThrottledLastHour >= spikeMin && float64(s.ThrottledLastHour) >= spikeFactor*s.ThrottledPerHour
+
+	s.advise()
+	return s
+}
+
+// advise suggests what to do about the errors found
+func (s *Summary) advise() {
+	if s.Spike {
+		s.Advice = append(s.Advice, fmt.Sprintf("Throttling spiked: %d calls in the last hour against %.1f per hour before. Stop the bot now and let the account rest for a day or two.",
+			s.ThrottledLastHour, s.ThrottledPerHour))
+	} else if s.Throttled > 0 {
+		s.Advice = append(s.Advice, fmt.Sprintf("LinkedIn throttled %d API calls (429/999). Back off: lower connections.daily_limit, lengthen stealth.timing delays and pause for 24-48 hours.",
+			s.Throttled))
+	}
+	if refused := s.Failed[401] + s.Failed[403]; refused > 0 {
+		s.Advice = append(s.Advice, fmt.Sprintf("%d calls were refused (401/403): the session may be logged out or flagged. Look for a checkpoint and log in again by hand.", refused))
+	}
+	serverErrors := 0
+	for status, n := range s.Failed {
+		if status >= 500 && status < 600 {
+			serverErrors += n
+		}
+	}
+	if serverErrors > 0 {
+		s.Advice = append(s.Advice, fmt.Sprintf("%d server errors (5xx) are usually LinkedIn's own trouble and pass on their own.", serverErrors))
+	}
+	if len(s.Advice) == 0 {
+		if s.Console+s.Exceptions > 0 {
+			s.Advice = append(s.Advice, "No failed API calls. Console errors alone are common on LinkedIn and rarely mean detection.")
+		} else {
+			s.Advice = append(s.Advice, "Nothing recorded points at throttling or detection.")
+		}
+	}
+}
+
+// throttling reports whether status is LinkedIn turning the client away: 429 Too Many
+// Requests, or its own 999
+func throttling(status int) bool {
+	return status == 429 || status == 999
+}
+
+// endpoint reduces a voyager URL to its path with IDs replaced, so calls to the same
+// endpoint count together
+func endpoint(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	segments := strings.Split(strings.TrimPrefix(u.Path, "/voyager/api"), "/")
+	for i, seg := range segments {
+		if len(seg) >= 8 && strings.IndexFunc(seg, unicode.IsDigit) >= 0 {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// firstLine returns the first line of a message, where a stack trace would follow
+func firstLine(message string) string {
+	if i := strings.IndexByte(message, '\n'); i >= 0 {
+		return message[:i]
+	}
+	return message
+}
+
+// top returns the n most frequent keys, most frequent first
+func top(counts map[string]int, n int) []Count {
+	result := make([]Count, 0, len(counts))
+	for key, count := range counts {
+		result = append(result, Count{Key: key, N: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].N != result[j].N {
+			return result[i].N > result[j].N
+		}
+		return result[i].Key < result[j].Key
+	})
+	if len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
@@ -0,0 +1,156 @@
+// Package engagement warms prospects up before they are invited: it likes the
+// most recent post a prospect wrote themselves, at most once per prospect and
+// within its own daily cap.
+package engagement
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// ErrDailyLikeLimit is returned once today's likes are used up
+var ErrDailyLikeLimit = errors.New("daily like limit reached")
+
+// Engager likes prospects' recent posts
+type Engager struct {
+	cfg      config.EngagementConfig
+	page     pageops.Page
+	scroller pageops.Scroller
+	clicker  pageops.Clicker
+	timing   *stealth.TimingController
+	db       *storage.DB
+}
+
+// NewEngager creates an engager
+func NewEngager(cfg config.EngagementConfig, page pageops.Page, scroller pageops.Scroller, clicker pageops.Clicker, timing *stealth.TimingController, db *storage.DB) *Engager {
+	return &Engager{
+		cfg:      cfg,
+		page:     page,
+		scroller: scroller,
+		clicker:  clicker,
+		timing:   timing,
+		db:       db,
+	}
+}
+
+// LikeRecentPost opens a prospect's recent activity, picks their most recent original
+// post and likes it with the stealth mouse. It returns the recorded outcome: a profile
+// without original posts is recorded as no_activity and is never liked later, and a
+// profile engaged with before returns its earlier outcome without visiting the page.
+func (e *Engager) LikeRecentPost(profileURL string) (string, error) {
+	if earlier, err := e.db.GetEngagement(profileURL); err != nil {
+		return "", err
+	} else if earlier != nil {
+		return earlier.Outcome, nil
+	}
+
+	liked, err := e.db.GetLikesCountByDate(time.Now())
+	if err != nil {
+		return "", fmt.Errorf("failed to get today's like count: %w", err)
+	}
+	if liked >= e.cfg.DailyLikeLimit {
+		return "", fmt.Errorf("%w (%d/%d)", ErrDailyLikeLimit, liked, e.cfg.DailyLikeLimit)
+	}
+
+	if err := e.page.Navigate(activityURL(profileURL)); err != nil {
+		return "", fmt.Errorf("failed to open recent activity: %w", err)
+	}
+	if err := e.page.WaitLoad(); err != nil {
+		logger.Warnf("Failed to wait for recent activity: %v", err)
+	}
+	e.timing.Wait(e.timing.ThinkTime())
+
+	post := e.recentOriginalPost()
+	if post == nil {
+		logger.Infof("No original posts by %s, nothing to like", profileURL)
+		return e.record(profileURL, storage.EngagementNoActivity, "")
+	}
+
+	postURL := ""
+	if link, err := selectors.FindFirst(post, selectors.ActivityPostLink); err == nil {
+		postURL, _ = link.Property("href")
+	}
+
+	if selectors.Has(post, selectors.ActivityPostLiked) {
+		return e.record(profileURL, storage.EngagementAlreadyLiked, postURL)
+	}
+
+	// Scroll down to the post and read it before reacting
+	if err := e.scroller.ScrollDown(300); err != nil {
+		logger.Debugf("Failed to scroll activity: %v", err)
+	}
+	if err := post.ScrollIntoView(); err != nil {
+		logger.Debugf("Failed to scroll to post: %v", err)
+	}
+	e.timing.Wait(e.timing.ReadingTime(40))
+
+	button, err := selectors.FindFirst(post, selectors.FeedLikeButton)
+	if err != nil {
+		logger.Warnf("No like button on the post by %s: %v", profileURL, err)
+		return e.record(profileURL, storage.EngagementFailed, postURL)
+	}
+	if err := e.clicker.Click(button); err != nil {
+		logger.Warnf("Failed to like the post by %s: %v", profileURL, err)
+		return e.record(profileURL, storage.EngagementFailed, postURL)
+	}
+
+	e.timing.Wait(e.timing.ShortPause())
+	if !selectors.Has(post, selectors.ActivityPostLiked) {
+		logger.Warnf("Like on the post by %s didn't register", profileURL)
+		return e.record(profileURL, storage.EngagementFailed, postURL)
+	}
+
+	logger.Infof("Liked the most recent post by %s", profileURL)
+	outcome, err := e.record(profileURL, storage.EngagementLiked, postURL)
+
+	// Don't move straight on to the next prospect
+	e.timing.Wait(e.timing.LongPause())
+	return outcome, err
+}
+
+// recentOriginalPost returns the first post in the activity list the prospect wrote
+// themselves, passing over reposts and posts they only commented on or reacted to
+func (e *Engager) recentOriginalPost() pageops.Element {
+	posts, err := selectors.FindAll(e.page, selectors.ActivityPost)
+	if err != nil {
+		return nil
+	}
+
+	for _, post := range posts {
+		if !selectors.Has(post, selectors.ActivityReshared) {
+			return post
+		}
+	}
+	return nil
+}
+
+// record stores the engagement and logs it
+func (e *Engager) record(profileURL, outcome, postURL string) (string, error) {
+	engagement := &storage.Engagement{
+		ProfileURL: profileURL,
+		Action:     config.PreEngageLike,
+		Outcome:    outcome,
+		PostURL:    postURL,
+		EngagedAt:  time.Now(),
+	}
+	if _, err := e.db.SaveEngagement(engagement); err != nil {
+		return outcome, err
+	}
+
+	e.db.LogActivity("engagement_"+outcome, profileURL)
+	return outcome, nil
+}
+
+// activityURL returns the recent activity page of a profile
+func activityURL(profileURL string) string {
+	return strings.TrimRight(profileURL, "/") + "/recent-activity/all/"
+}
@@ -0,0 +1,251 @@
+package graph
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// Node kinds
+const (
+	KindSelf     = "self"
+	KindProspect = "prospect"
+	KindCompany  = "company"
+	KindSource   = "source"
+)
+
+// Edge kinds
+const (
+	EdgeFoundBy  = "found-by"
+	EdgeWorksAt  = "works-at"
+	EdgeMessaged = "messaged"
+)
+
+// statusColors maps prospect statuses to display colors
+var statusColors = map[string]string{
+	"found":     "#bdbdbd",
+	"pending":   "#ffb300",
+	"accepted":  "#43a047",
+	"replied":   "#1e88e5",
+	"rejected":  "#e53935",
+	"withdrawn": "#8e24aa",
+	"skipped":   "#757575",
+	"ignored":   "#e53935",
+	"left":      "#bdbdbd",
+}
+
+// Node is a vertex of the outreach graph
+type Node struct {
+	ID     string
+	Label  string
+	Kind   string
+	Status string
+}
+
+// Color returns the display color of the node
+func (n Node) Color() string {
+	switch n.Kind {
+	case KindSelf:
+		return "#000000"
+	case KindCompany:
+		return "#90caf9"
+	case KindSource:
+		return "#ce93d8"
+	}
+
+	if color, ok := statusColors[n.Status]; ok {
+		return color
+	}
+	return "#bdbdbd"
+}
+
+// Edge is a directed relation between two nodes
+type Edge struct {
+	From string
+	To   string
+	Kind string
+}
+
+// Graph is an outreach network ready to be written out
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+	index map[string]bool
+}
+
+// Build creates a graph from outreach records.
+// maxProspects caps the number of prospect nodes; 0 means no cap.
+func Build(records []storage.OutreachRecord, maxProspects int) *Graph {
+	g := &Graph{index: make(map[string]bool)}
+	g.addNode(Node{ID: "me", Label: "Me", Kind: KindSelf})
+
+	prospects := 0
+	for _, r := range records {
+		if maxProspects > 0 && prospects >= maxProspects {
+			break
+		}
+
+		id := "p:" + r.ProfileURL
+		if g.index[id] {
+			continue
+		}
+
+		label := r.ProfileName
+		if label == "" {
+			label = r.ProfileURL
+		}
+
+		g.addNode(Node{ID: id, Label: label, Kind: KindProspect, Status: r.Status})
+		prospects++
+
+		sourceID := "s:" + r.Source
+		g.addNode(Node{ID: sourceID, Label: r.Source, Kind: KindSource})
+		g.Edges = append(g.Edges, Edge{From: id, To: sourceID, Kind: EdgeFoundBy})
+
+		if company := strings.TrimSpace(r.Company); company != "" {
+			companyID := "c:" + strings.ToLower(company)
+			g.addNode(Node{ID: companyID, Label: company, Kind: KindCompany})
+			g.Edges = append(g.Edges, Edge{From: id, To: companyID, Kind: EdgeWorksAt})
+		}
+
+		if r.Messaged {
+			g.Edges = append(g.Edges, Edge{From: "me", To: id, Kind: EdgeMessaged})
+		}
+	}
+
+	return g
+}
+
+// addNode adds a node unless one with the same ID exists
+func (g *Graph) addNode(n Node) {
+	if g.index[n.ID] {
+		return
+	}
+	g.index[n.ID] = true
+	g.Nodes = append(g.Nodes, n)
+}
+
+// WriteDOT writes the graph in Graphviz DOT format
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("digraph outreach {\n")
+	b.WriteString("  node [style=filled, fontname=\"Helvetica\"];\n")
+
+	for _, n := range g.Nodes {
+		shape := "ellipse"
+		switch n.Kind {
+		case KindCompany:
+			shape = "box"
+		case KindSource:
+			shape = "diamond"
+		case KindSelf:
+			shape = "doublecircle"
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, shape=%s, fillcolor=%q, kind=%q, status=%q];\n",
+			dotQuote(n.ID), dotQuote(n.Label), shape, n.Color(), n.Kind, n.Status)
+	}
+
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s [label=%q];\n", dotQuote(e.From), dotQuote(e.To), e.Kind)
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotQuote quotes a DOT identifier
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ").Replace(s) + `"`
+}
+
+// GraphML document structure
+type graphMLDoc struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML writes the graph in GraphML format
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	doc := graphMLDoc{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "label", For: "node", AttrName: "label", AttrType: "string"},
+			{ID: "kind", For: "node", AttrName: "kind", AttrType: "string"},
+			{ID: "status", For: "node", AttrName: "status", AttrType: "string"},
+			{ID: "color", For: "node", AttrName: "color", AttrType: "string"},
+			{ID: "relation", For: "edge", AttrName: "relation", AttrType: "string"},
+		},
+		Graph: graphMLGraph{ID: "outreach", EdgeDefault: "directed"},
+	}
+
+	for _, n := range g.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: n.ID,
+			Data: []graphMLData{
+				{Key: "label", Value: n.Label},
+				{Key: "kind", Value: n.Kind},
+				{Key: "status", Value: n.Status},
+				{Key: "color", Value: n.Color()},
+			},
+		})
+	}
+
+	for _, e := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			Source: e.From,
+			Target: e.To,
+			Data:   []graphMLData{{Key: "relation", Value: e.Kind}},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode graphml: %w", err)
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,116 @@
+package graph
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+var records = []storage.OutreachRecord{
+	{ProfileURL: "https://www.linkedin.com/in/ada/", ProfileName: "Ada \"Countess\" Lovelace", Company: "Engines Ltd", Source: "search", Status: "accepted", Messaged: true},
+	{ProfileURL: "https://www.linkedin.com/in/charles/", ProfileName: "Charles Babbage", Company: " engines ltd ", Source: "search", Status: "pending"},
+	{ProfileURL: "https://www.linkedin.com/in/ada/", ProfileName: "Ada Lovelace", Source: "incoming_invite", Status: "accepted"},
+	{ProfileURL: "https://www.linkedin.com/in/grace/", Source: "incoming_invite", Status: "ignored"},
+}
+
+func TestBuild(t *testing.T) {
+	g := Build(records, 0)
+
+	kinds := map[string]int{}
+	for _, n := range g.Nodes {
+		kinds[n.Kind]++
+	}
+	// The repeated profile is one node and the company is matched case-insensitively
+	if kinds[KindSelf] != 1 || kinds[KindProspect] != 3 || kinds[KindCompany] != 1 || kinds[KindSource] != 2 {
+		t.Fatalf("node kinds = %v", kinds)
+	}
+
+	relations := map[string]int{}
+	for _, e := range g.Edges {
+		relations[e.Kind]++
+	}
+	if relations[EdgeFoundBy] != 3 || relations[EdgeWorksAt] != 2 || relations[EdgeMessaged] != 1 {
+		t.Fatalf("edge kinds = %v", relations)
+	}
+
+	for _, n := range g.Nodes {
+		if n.ID == "p:https://www.linkedin.com/in/grace/" && n.Label != "https://www.linkedin.com/in/grace/" {
+			t.Fatalf("a nameless prospect is labelled %q, want its URL", n.Label)
+		}
+	}
+}
+
+func TestBuildCapsProspects(t *testing.T) {
+	g := Build(records, 1)
+
+	prospects := 0
+	for _, n := range g.Nodes {
+		if n.Kind == KindProspect {
+			prospects++
+		}
+	}
+	if prospects != 1 {
+		t.Fatalf("got %d prospects, want 1", prospects)
+	}
+}
+
+func TestNodeColor(t *testing.T) {
+	for _, tt := range []struct {
+		node Node
+		want string
+	}{
+		{Node{Kind: KindSelf}, "#000000"},
+		{Node{Kind: KindCompany, Status: "accepted"}, "#90caf9"},
+		{Node{Kind: KindProspect, Status: "accepted"}, "#43a047"},
+		{Node{Kind: KindProspect, Status: "unknown"}, "#bdbdbd"},
+	} {
+		if got := tt.node.Color(); got != tt.want {
+			t.Errorf("%+v color = %s, want %s", tt.node, got, tt.want)
+		}
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	var b strings.Builder
+	if err := Build(records[:1], 0).WriteDOT(&b); err != nil {
+		t.Fatal(err)
+	}
+	dot := b.String()
+
+	for _, want := range []string{
+		"digraph outreach {\n",
+		`"p:https://www.linkedin.com/in/ada/" [label="Ada \"Countess\" Lovelace", shape=ellipse, fillcolor="#43a047", kind="prospect", status="accepted"];`,
+		`"c:engines ltd" [label="Engines Ltd", shape=box`,
+		`"me" -> "p:https://www.linkedin.com/in/ada/" [label="messaged"];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT output lacks %s:\n%s", want, dot)
+		}
+	}
+	if !strings.HasSuffix(dot, "}\n") {
+		t.Errorf("DOT output isn't closed:\n%s", dot)
+	}
+}
+
+func TestWriteGraphML(t *testing.T) {
+	var b strings.Builder
+	g := Build(records, 0)
+	if err := g.WriteGraphML(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	// The output reads back as the same graph
+	var doc graphMLDoc
+	if err := xml.Unmarshal([]byte(b.String()), &doc); err != nil {
+		t.Fatalf("output isn't valid GraphML: %v", err)
+	}
+	if len(doc.Graph.Nodes) != len(g.Nodes) || len(doc.Graph.Edges) != len(g.Edges) {
+		t.Fatalf("read back %d nodes and %d edges, want %d and %d", len(doc.Graph.Nodes), len(doc.Graph.Edges), len(g.Nodes), len(g.Edges))
+	}
+	ada := doc.Graph.Nodes[1]
+	if ada.ID != "p:https://www.linkedin.com/in/ada/" || ada.Data[0].Value != `Ada "Countess" Lovelace` {
+		t.Fatalf("prospect node = %+v", ada)
+	}
+}
@@ -0,0 +1,106 @@
+package headline
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Headline is a profile headline split into its primary role and organization
+type Headline struct {
+	Raw     string
+	Title   string // primary role, empty when none could be told apart
+	Company string // organization of the primary role, empty when not named
+}
+
+// separators split a headline into segments, strongest first; a dash only
+// separates when it is spaced, so "Co-founder" stays whole
+var separators = []string{"|", "•", "·", "⋅", "►", "▪", " // ", " / ", " – ", " — ", " - "}
+
+// dashSeparators may separate a role from its organization, as in "Engineer - Acme"
+var dashSeparators = map[string]bool{" – ": true, " — ": true, " - ": true}
+
+// orgMarker finds "@" or a standalone "at" introducing an organization
+var orgMarker = regexp.MustCompile(`(?i)\s*@\s*|\s+at\s+`)
+
+// roleWords mark a segment as a role rather than an organization
+var roleWords = regexp.MustCompile(`(?i)\b(?:engineer|developer|manager|founder|ceo|cto|cfo|coo|cmo|cio|` +
+	`director|lead|head|vp|president|consultant|advisor|adviser|investor|designer|analyst|` +
+	`architect|specialist|student|enthusiast|speaker|author|recruiter|scientist|officer|` +
+	`partner|owner|mentor|coach|intern|freelancer|writer|researcher|professor|teacher|` +
+	`expert|evangelist|strategist|leader|builder|helping|passionate|open to)s?\b`)
+
+// Parse splits a headline such as "CTO @ Acme | Angel Investor | Advisor" into its
+// primary role and organization. The first segment is taken as the primary role; the
+// organization follows an "@" or "at" in it, or is the dash-separated segment right
+// after it when that doesn't read as another role.
+func Parse(raw string) Headline {
+	h := Headline{Raw: raw}
+
+	text := strings.Join(strings.Fields(raw), " ")
+	if text == "" {
+		return h
+	}
+
+	segments, seps := split(text)
+	first := segments[0]
+
+	if loc := orgMarker.FindStringIndex(first); loc != nil && loc[0] > 0 {
+		h.Title = clean(first[:loc[0]])
+		h.Company = organization(first[loc[1]:])
+		return h
+	}
+
+	h.Title = clean(first)
+	if len(segments) > 1 && dashSeparators[seps[0]] && !isRole(segments[1]) {
+		h.Company = organization(segments[1])
+	}
+	return h
+}
+
+// split cuts text into non-empty segments along with the separator after each one
+func split(text string) ([]string, []string) {
+	segments := []string{text}
+	seps := []string{""}
+
+	for _, sep := range separators {
+		var nextSegments, nextSeps []string
+		for i, segment := range segments {
+			parts := strings.Split(segment, sep)
+			for j, part := range parts {
+				if strings.TrimSpace(part) == "" {
+					continue
+				}
+				nextSegments = append(nextSegments, part)
+				if j < len(parts)-1 {
+					nextSeps = append(nextSeps, sep)
+				} else {
+					nextSeps = append(nextSeps, seps[i])
+				}
+			}
+		}
+		if len(nextSegments) > 0 {
+			segments, seps = nextSegments, nextSeps
+		}
+	}
+
+	return segments, seps
+}
+
+// isRole reports whether a segment reads as a role rather than an organization
+func isRole(segment string) bool {
+	return roleWords.MatchString(segment) || orgMarker.MatchString(segment)
+}
+
+// organization cleans an organization name, dropping asides such as ", ex-Google"
+// or "(YC W21)" after it
+func organization(s string) string {
+	if i := strings.IndexAny(s, ",;("); i > 0 {
+		s = s[:i]
+	}
+	return clean(s)
+}
+
+// clean trims spaces and dangling punctuation from a segment
+func clean(s string) string {
+	return strings.Trim(s, " ,;:.!-–—")
+}
@@ -0,0 +1,77 @@
+package headline
+
+import "testing"
+
+func TestParseCorpus(t *testing.T) {
+	for _, tt := range []struct {
+		raw, title, company string
+	}{
+		// "@" and "at" name the organization of the first segment
+		{"CTO @ Acme | Angel Investor | Advisor", "CTO", "Acme"},
+		{"Senior Software Engineer at Google", "Senior Software Engineer", "Google"},
+		{"Product Manager @Stripe", "Product Manager", "Stripe"},
+		{"Head of Growth at Notion | ex-Dropbox", "Head of Growth", "Notion"},
+		{"Founder & CEO at Lumen Labs (YC W21)", "Founder & CEO", "Lumen Labs"},
+		{"Co-founder @ Bramble, ex-Google", "Co-founder", "Bramble"},
+		{"VP Engineering at Datadog • Speaker • Mentor", "VP Engineering", "Datadog"},
+		{"Staff Engineer at Shopify · Ruby · Rails", "Staff Engineer", "Shopify"},
+		{"Data Scientist at Spotify ► ML ► NLP", "Data Scientist", "Spotify"},
+		{"Partner at Sequoia Capital; board member", "Partner", "Sequoia Capital"},
+		{"Recruiter at Meta | We're hiring!", "Recruiter", "Meta"},
+		{"Engineering Manager at GitHub // Open Source", "Engineering Manager", "GitHub"},
+		{"Chief Technology Officer at Monzo Bank.", "Chief Technology Officer", "Monzo Bank"},
+		{"Director of Sales AT Salesforce", "Director of Sales", "Salesforce"},
+		{"PhD Student at MIT CSAIL", "PhD Student", "MIT CSAIL"},
+		{"Intern @ Microsoft | CS @ Stanford", "Intern", "Microsoft"},
+		{"  Backend Developer   at   Wise  ", "Backend Developer", "Wise"},
+		{"Principal Designer @ Figma ▪ Design Systems", "Principal Designer", "Figma"},
+		{"Solutions Architect at AWS / Serverless", "Solutions Architect", "AWS"},
+		{"Associate Professor at Oxford University, AI ethics", "Associate Professor", "Oxford University"},
+		{"Owner at Rosie's Bakery", "Owner", "Rosie's Bakery"},
+		{"Software Engineer II at Microsoft | Azure", "Software Engineer II", "Microsoft"},
+		{"CEO @ Kiwi.com", "CEO", "Kiwi.com"},
+		{"Talent Partner at Index Ventures — hiring engineers", "Talent Partner", "Index Ventures"},
+		{"SRE at Cloudflare|Go|Kubernetes", "SRE", "Cloudflare"},
+		{"Founding Engineer at Ramp ⋅ NYC", "Founding Engineer", "Ramp"},
+
+		// A dash-separated organization after the role
+		{"Software Engineer - Atlassian", "Software Engineer", "Atlassian"},
+		{"Marketing Lead – Canva | Storyteller", "Marketing Lead", "Canva"},
+		{"Frontend Developer — Vercel", "Frontend Developer", "Vercel"},
+		{"Account Executive - HubSpot - Boston", "Account Executive", "HubSpot"},
+		{"Co-Founder - Pocket Labs (YC S22)", "Co-Founder", "Pocket Labs"},
+		{"Nurse Practitioner - Mayo Clinic", "Nurse Practitioner", "Mayo Clinic"},
+
+		// A dash followed by another role names no organization
+		{"Software Engineer - Open Source Enthusiast", "Software Engineer", ""},
+		{"Founder - Angel Investor - Advisor", "Founder", ""},
+		{"Data Engineer – Passionate about pipelines", "Data Engineer", ""},
+		{"Writer - Speaker - Coach", "Writer", ""},
+
+		// Only a role, or no organization to tell apart
+		{"Software Engineer", "Software Engineer", ""},
+		{"Freelance UX Designer | Helping startups ship", "Freelance UX Designer", ""},
+		{"Serial Entrepreneur | Investor | Dad", "Serial Entrepreneur", ""},
+		{"Open to work | Full-stack developer", "Open to work", ""},
+		{"Building the future of payments", "Building the future of payments", ""},
+		{"Marketing • Growth • Brand", "Marketing", ""},
+		{"Product / Strategy / Operations", "Product", ""},
+		{"Ex-Googler. Now building something new.", "Ex-Googler. Now building something new", ""},
+		{"Love what you do ✨", "Love what you do ✨", ""},
+		{"Retired", "Retired", ""},
+		{"Co-founder", "Co-founder", ""},
+
+		// Separators and markers without a role before them
+		{"@ Acme | Founder", "@ Acme", ""},
+		{"| CTO | Acme", "CTO", ""},
+		{"", "", ""},
+	} {
+		got := Parse(tt.raw)
+		if got.Title != tt.title || got.Company != tt.company {
+			t.Errorf("Parse(%q) = %q, %q; want %q, %q", tt.raw, got.Title, got.Company, tt.title, tt.company)
+		}
+		if got.Raw != tt.raw {
+			t.Errorf("Parse(%q) lost the raw headline: %q", tt.raw, got.Raw)
+		}
+	}
+}
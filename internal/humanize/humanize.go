@@ -0,0 +1,202 @@
+// Package humanize fills the gaps between connection requests with the kind of
+// idle browsing a person does: reading the feed, glancing at notifications and
+// checking their own profile. Idle actions only navigate, scroll and move the
+// mouse; they never comment, and they only like posts when explicitly allowed.
+package humanize
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// Pages visited by idle actions
+const (
+	feedURL          = "https://www.linkedin.com/feed/"
+	notificationsURL = "https://www.linkedin.com/notifications/"
+	ownProfileURL    = "https://www.linkedin.com/in/me/"
+)
+
+// IdleMover moves the mouse around without a target
+type IdleMover interface {
+	RandomIdleMovement() error
+}
+
+// Humanizer runs idle actions between connection requests.
+// A nil *Humanizer is valid and does nothing.
+type Humanizer struct {
+	cfg      config.HumanizeConfig
+	page     pageops.Page
+	scroller pageops.Scroller
+	mouse    IdleMover
+	clicker  pageops.Clicker
+	timing   *stealth.TimingController
+	db       *storage.DB
+	rand     *rand.Rand
+}
+
+// NewHumanizer creates a humanizer, or returns nil when idle activity is disabled
+func NewHumanizer(cfg config.HumanizeConfig, page pageops.Page, scroller pageops.Scroller, mouse IdleMover, clicker pageops.Clicker, timing *stealth.TimingController, db *storage.DB) *Humanizer {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	return &Humanizer{
+		cfg:      cfg,
+		page:     page,
+		scroller: scroller,
+		mouse:    mouse,
+		clicker:  clicker,
+		timing:   timing,
+		db:       db,
+		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// MaybeIdle runs one randomly chosen idle action with the configured probability.
+// Failures are logged and never interrupt the caller.
+func (h *Humanizer) MaybeIdle() {
+	if h == nil || h.rand.Float64() >= h.cfg.Probability {
+		return
+	}
+	h.Idle()
+}
+
+// Idle runs one randomly chosen idle action. Failures are logged and never interrupt
+// the caller.
+func (h *Humanizer) Idle() {
+	if h == nil {
+		return
+	}
+
+	var name string
+	var err error
+	started := time.Now()
+
+	switch n := h.rand.Float64(); {
+	case n < 0.6:
+		name, err = "browse_feed", h.BrowseFeed(h.feedDuration())
+	case n < 0.85:
+		name, err = "view_notifications", h.ViewRandomNotifications()
+	default:
+		name, err = "view_own_profile", h.ViewOwnProfile()
+	}
+
+	elapsed := time.Since(started).Round(time.Second)
+	if err != nil {
+		logger.Warnf("Idle action %s failed after %s: %v", name, elapsed, err)
+		h.db.LogActivity("idle_"+name, fmt.Sprintf("Failed after %s: %v", elapsed, err))
+		return
+	}
+
+	logger.Infof("Idle action %s took %s", name, elapsed)
+	h.db.LogActivity("idle_"+name, fmt.Sprintf("Duration %s", elapsed))
+}
+
+// BrowseFeed scrolls through the feed for about duration, pausing to read posts
+func (h *Humanizer) BrowseFeed(duration time.Duration) error {
+	if err := h.open(feedURL); err != nil {
+		return err
+	}
+	if !selectors.Has(h.page, selectors.FeedPost) {
+		return fmt.Errorf("feed shows no posts")
+	}
+
+	deadline := time.Now().Add(duration)
+	for time.Now().Before(deadline) {
+		if err := h.scroller.ScrollDown(300 + h.rand.Intn(500)); err != nil {
+			return fmt.Errorf("failed to scroll feed: %w", err)
+		}
+
+		// Read a short post's worth of words
+		h.timing.Wait(h.timing.ReadingTime(20 + h.rand.Intn(80)))
+
+		if h.rand.Float64() < 0.3 {
+			if err := h.mouse.RandomIdleMovement(); err != nil {
+				logger.Debugf("Idle mouse movement failed: %v", err)
+			}
+		}
+
+		if h.cfg.AllowLikes && h.rand.Float64() < h.cfg.LikeProbability {
+			h.likeVisiblePost()
+		}
+	}
+
+	return nil
+}
+
+// ViewRandomNotifications opens the notifications page and skims a few of them
+func (h *Humanizer) ViewRandomNotifications() error {
+	if err := h.open(notificationsURL); err != nil {
+		return err
+	}
+
+	skims := 1 + h.rand.Intn(3)
+	for i := 0; i < skims; i++ {
+		h.timing.Wait(h.timing.ReadingTime(15 + h.rand.Intn(30)))
+		if err := h.scroller.ScrollDown(200 + h.rand.Intn(300)); err != nil {
+			return fmt.Errorf("failed to scroll notifications: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ViewOwnProfile opens the logged-in member's profile and looks it over
+func (h *Humanizer) ViewOwnProfile() error {
+	if err := h.open(ownProfileURL); err != nil {
+		return err
+	}
+
+	if err := h.mouse.RandomIdleMovement(); err != nil {
+		logger.Debugf("Idle mouse movement failed: %v", err)
+	}
+	h.timing.Wait(h.timing.ReadingTime(40 + h.rand.Intn(60)))
+
+	if err := h.scroller.ScrollDown(400 + h.rand.Intn(400)); err != nil {
+		return fmt.Errorf("failed to scroll profile: %w", err)
+	}
+	h.timing.Wait(h.timing.MediumPause())
+
+	return nil
+}
+
+// open navigates to url and waits for it to settle
+func (h *Humanizer) open(url string) error {
+	if err := h.page.Navigate(url); err != nil {
+		return fmt.Errorf("failed to navigate to %s: %w", url, err)
+	}
+	if err := h.page.WaitLoad(); err != nil {
+		return fmt.Errorf("failed to wait for %s: %w", url, err)
+	}
+
+	h.timing.Wait(h.timing.ThinkTime())
+	return nil
+}
+
+// likeVisiblePost likes the first unliked post loaded in the feed; only called when likes are allowed
+func (h *Humanizer) likeVisiblePost() {
+	button, err := selectors.FindFirst(h.page, selectors.FeedLikeButton)
+	if err != nil {
+		return
+	}
+
+	if err := h.clicker.Click(button); err != nil {
+		logger.Debugf("Failed to like post: %v", err)
+		return
+	}
+	h.db.LogActivity("idle_like", "Liked a feed post")
+}
+
+// feedDuration draws how long to browse the feed
+func (h *Humanizer) feedDuration() time.Duration {
+	seconds := h.cfg.FeedMinSeconds + h.rand.Intn(h.cfg.FeedMaxSeconds-h.cfg.FeedMinSeconds+1)
+	return time.Duration(seconds) * time.Second
+}
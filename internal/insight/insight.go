@@ -0,0 +1,56 @@
+// Package insight reads the short insight lines LinkedIn shows under a person in
+// search results and invitation cards.
+package insight
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// mutualPattern extracts the count from texts like "12 mutual connections"
+var mutualPattern = regexp.MustCompile(`(?i)(\d[\d,.]*)\s+(other\s+)?mutual`)
+
+// openToWorkPattern matches the "Open to work" badge text and photo frame label
+var openToWorkPattern = regexp.MustCompile(`(?i)open\s*to\s*work|#opentowork`)
+
+// MutualConnections extracts the mutual connection count from insight text
+func MutualConnections(text string) int {
+	match := mutualPattern.FindStringSubmatch(text)
+	if match == nil {
+		// "Jane Doe is a mutual connection"
+		if strings.Contains(strings.ToLower(text), "mutual connection") {
+			return 1
+		}
+		return 0
+	}
+
+	n, err := strconv.Atoi(strings.NewReplacer(",", "", ".", "").Replace(match[1]))
+	if err != nil {
+		return 0
+	}
+
+	// "Jane Doe and 11 other mutual connections"
+	if match[2] != "" {
+		n++
+	}
+
+	return n
+}
+
+// degreePattern finds the degree of connection in badge texts like "• 2nd" or "3rd+"
+var degreePattern = regexp.MustCompile(`(?i)\b([123])(?:st|nd|rd)\b`)
+
+// Degree returns the degree of connection a badge shows, 0 when it shows none
+func Degree(text string) int {
+	match := degreePattern.FindStringSubmatch(text)
+	if match == nil {
+		return 0
+	}
+	return int(match[1][0] - '0')
+}
+
+// OpenToWork reports whether text carries the "Open to work" badge
+func OpenToWork(text string) bool {
+	return openToWorkPattern.MatchString(text)
+}
@@ -0,0 +1,52 @@
+package insight
+
+import "testing"
+
+func TestMutualConnections(t *testing.T) {
+	for _, tt := range []struct {
+		text string
+		want int
+	}{
+		{"12 mutual connections", 12},
+		{"Jane Doe and 11 other mutual connections", 12},
+		{"Jane Doe and 1,203 other mutual connections", 1204},
+		{"Jane Doe is a mutual connection", 1},
+		{"Followed by 3 people you know", 0},
+		{"", 0},
+	} {
+		if got := MutualConnections(tt.text); got != tt.want {
+			t.Errorf("MutualConnections(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestOpenToWork(t *testing.T) {
+	for text, want := range map[string]bool{
+		"Open to work":                        true,
+		"Backend engineer #OpenToWork":        true,
+		"Software Engineer | OPEN TO WORK":    true,
+		"Opening new offices in Berlin":       false,
+		"Hiring: open roles across the world": false,
+	} {
+		if got := OpenToWork(text); got != want {
+			t.Errorf("OpenToWork(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
+
+func TestDegree(t *testing.T) {
+	for text, want := range map[string]int{
+		"• 1st":                  1,
+		"• 2nd":                  2,
+		"3rd+":                   3,
+		"3rd+ degree connection": 3,
+		"Premium • 2nd degree":   2,
+		"21st Century Fox":       0,
+		"Out of network":         0,
+		"":                       0,
+	} {
+		if got := Degree(text); got != want {
+			t.Errorf("Degree(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
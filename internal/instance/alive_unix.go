@@ -0,0 +1,16 @@
+//go:build !windows
+
+package instance
+
+import (
+	"errors"
+
+	"golang.org/x/sys/unix"
+)
+
+// alive reports whether a process with the given PID is running
+func alive(pid int) bool {
+	// Signal 0 checks the process exists without disturbing it; EPERM means it does but isn't ours
+	err := unix.Kill(pid, 0)
+	return err == nil || errors.Is(err, unix.EPERM)
+}
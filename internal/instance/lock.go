@@ -0,0 +1,181 @@
+// Package instance keeps two copies of the bot from working one account at the same time.
+package instance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrLocked is returned by Acquire while another live process holds the lock
+var ErrLocked = errors.New("another instance is running for this account")
+
+// fresh is how long a lock file that can't be read yet is taken to be one being written
+const fresh = 10 * time.Second
+
+// Owner is the process holding a lock, as its lock file records it
+type Owner struct {
+	PID     int       `json:"pid"`
+	Host    string    `json:"host"`
+	Account string    `json:"account"`
+	Started time.Time `json:"started"`
+}
+
+// Lock is a held instance lock
+type Lock struct {
+	path  string
+	owner Owner
+
+	mu       sync.Mutex
+	released bool
+}
+
+// Path returns the lock file of account, kept next to the database at dbPath
+func Path(dbPath, account string) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, account)
+	return filepath.Join(filepath.Dir(dbPath), "linkedin-bot-"+name+".lock")
+}
+
+// Acquire takes the lock at path for account. It fails with ErrLocked while a live process
+// holds it, and takes over a lock whose process is gone.
+func Acquire(path, account string) (*Lock, error) {
+	host, _ := os.Hostname()
+	l := &Lock{path: path, owner: Owner{PID: os.Getpid(), Host: host, Account: account, Started: time.Now()}}
+
+	// A second attempt follows removing a stale lock
+	for attempt := 0; attempt < 2; attempt++ {
+		err := l.create()
+		if err == nil {
+			return l, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		holder, err := readOwner(path)
+		if err != nil {
+			return nil, err
+		}
+		if holder == nil {
+			return nil, fmt.Errorf("%w: %s is being written", ErrLocked, path)
+		}
+		if !stale(*holder, host) {
+			return nil, fmt.Errorf("%w: pid %d on %s since %s (%s)", ErrLocked, holder.PID, holder.Host,
+				holder.Started.Format("2006-01-02 15:04:05"), path)
+		}
+
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove stale lock: %w", err)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s was taken over by another process", ErrLocked, path)
+}
+
+// create writes the lock file, failing with os.ErrExist when there already is one
+func (l *Lock) create() error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+
+	err = json.NewEncoder(f).Encode(l.owner)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		os.Remove(l.path)
+		return fmt.Errorf("failed to write lock: %w", err)
+	}
+	return nil
+}
+
+// readOwner reads the process holding the lock at path. It returns nil for a file that
+// can't be read yet because it was only just created, and a stale owner for one left
+// unreadable or one that disappeared meanwhile.
+func readOwner(path string) (*Owner, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Owner{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lock: %w", err)
+	}
+
+	var owner Owner
+	if err := json.Unmarshal(data, &owner); err != nil || owner.PID <= 0 {
+		if info, sErr := os.Stat(path); sErr == nil && time.Since(info.ModTime()) < fresh {
+			return nil, nil
+		}
+		return &Owner{}, nil
+	}
+	return &owner, nil
+}
+
+// stale reports whether the process holding a lock is gone. A lock taken on another host
+// can't be checked, so it is never stale.
+func stale(owner Owner, host string) bool {
+	if owner.PID <= 0 {
+		return true
+	}
+	if owner.Host != "" && owner.Host != host {
+		return false
+	}
+	return !alive(owner.PID)
+}
+
+// Holder returns the live process holding the lock at path, or nil when none does
+func Holder(path string) (*Owner, error) {
+	owner, err := readOwner(path)
+	if err != nil || owner == nil {
+		return nil, err
+	}
+	host, _ := os.Hostname()
+	if stale(*owner, host) {
+		return nil, nil
+	}
+	return owner, nil
+}
+
+// Owner returns the process holding the lock
+func (l *Lock) Owner() Owner {
+	return l.owner
+}
+
+// Release removes the lock file if this process still holds it. It is safe to call more
+// than once, from any goroutine, and on a nil lock.
+func (l *Lock) Release() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.released {
+		return nil
+	}
+	l.released = true
+
+	holder, err := readOwner(l.path)
+	if err != nil {
+		return err
+	}
+	if holder == nil || holder.PID != l.owner.PID || !holder.Started.Equal(l.owner.Started) {
+		return nil
+	}
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+	return nil
+}
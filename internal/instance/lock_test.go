@@ -0,0 +1,120 @@
+package instance
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// deadPID is a process ID no running process has
+const deadPID = 1<<31 - 2
+
+// writeLock leaves a lock file at path as owner wrote it
+func writeLock(t *testing.T, path string, owner Owner) {
+	t.Helper()
+	data, err := json.Marshal(owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPath(t *testing.T) {
+	got := Path("/var/lib/bot/linkedin.db", "ada@example.com/../x")
+	if want := "/var/lib/bot/linkedin-bot-ada_example.com_.._x.lock"; got != filepath.FromSlash(want) {
+		t.Fatalf("Path = %q, want %q", got, want)
+	}
+}
+
+func TestAcquireRefusesASecondInstance(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.lock")
+
+	lock, err := Acquire(path, "ada@example.com")
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	if owner := lock.Owner(); owner.PID != os.Getpid() || owner.Account != "ada@example.com" {
+		t.Fatalf("owner = %+v", owner)
+	}
+
+	if _, err := Acquire(path, "ada@example.com"); !errors.Is(err, ErrLocked) {
+		t.Fatalf("second Acquire: %v, want ErrLocked", err)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if err := lock.Release(); err != nil {
+		t.Fatalf("second Release: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("lock file left after release: %v", err)
+	}
+
+	again, err := Acquire(path, "ada@example.com")
+	if err != nil {
+		t.Fatalf("Acquire after release: %v", err)
+	}
+	again.Release()
+}
+
+func TestAcquireTakesOverStaleLocks(t *testing.T) {
+	host, _ := os.Hostname()
+
+	for _, tt := range []struct {
+		name  string
+		write func(path string)
+		taken bool
+	}{
+		{"dead process", func(path string) { writeLock(t, path, Owner{PID: deadPID, Host: host}) }, true},
+		{"live process", func(path string) { writeLock(t, path, Owner{PID: os.Getppid(), Host: host}) }, false},
+		{"other host", func(path string) { writeLock(t, path, Owner{PID: deadPID, Host: host + "-other"}) }, false},
+		{"being written", func(path string) { os.WriteFile(path, nil, 0644) }, false},
+		{"left unreadable", func(path string) {
+			os.WriteFile(path, []byte("{"), 0644)
+			old := time.Now().Add(-time.Minute)
+			os.Chtimes(path, old, old)
+		}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "bot.lock")
+			tt.write(path)
+
+			lock, err := Acquire(path, "ada@example.com")
+			if tt.taken != (err == nil) {
+				t.Fatalf("Acquire: %v, want taken %v", err, tt.taken)
+			}
+			if err != nil && !errors.Is(err, ErrLocked) {
+				t.Fatalf("Acquire: %v, want ErrLocked", err)
+			}
+			lock.Release()
+		})
+	}
+}
+
+func TestReleaseKeepsAnotherOwnersLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.lock")
+	lock, err := Acquire(path, "ada@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another process took the lock over meanwhile
+	writeLock(t, path, Owner{PID: os.Getppid(), Started: time.Now()})
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("released another process's lock: %v", err)
+	}
+
+	var nilLock *Lock
+	if err := nilLock.Release(); err != nil {
+		t.Fatalf("nil Release: %v", err)
+	}
+}
@@ -9,8 +9,15 @@ import (
 
 var Log *zap.SugaredLogger
 
-// InitLogger initializes the global logger
-func InitLogger(level string, format string) error {
+// root is Log without the fields added by Bind
+var root *zap.SugaredLogger
+
+// OutputStdout logs to stdout only
+const OutputStdout = "stdout"
+
+// InitLogger initializes the global logger. Logs go to stdout in the given format;
+// when output is a file path they also go to that file as JSON, rotated as configured.
+func InitLogger(level, format, output string, rotation Rotation) error {
 	var zapLevel zapcore.Level
 	switch level {
 	case "debug":
@@ -52,12 +59,50 @@ func InitLogger(level string, format string) error {
 		zapLevel,
 	)
 
+	if output != "" && output != OutputStdout {
+		file, err := openRotatingFile(output, rotation)
+		if err != nil {
+			return err
+		}
+
+		// The file is for machines, whatever the console shows
+		fileConfig := encoderConfig
+		fileConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		core = zapcore.NewTee(core, zapcore.NewCore(zapcore.NewJSONEncoder(fileConfig), file, zapLevel))
+	}
+
 	logger := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
-	Log = logger.Sugar()
+	root = logger.Sugar()
+	Log = root
 
 	return nil
 }
 
+// SetLogger makes l the global logger, for programs that embed the bot and log their own way
+func SetLogger(l *zap.Logger) {
+	root = l.WithOptions(zap.AddCallerSkip(1)).Sugar()
+	Log = root
+}
+
+// With returns a logger that adds the given key-value pairs to every line it writes,
+// on top of any fields bound to the global logger
+func With(keysAndValues ...interface{}) *zap.SugaredLogger {
+	// The global logger skips a frame for these wrappers; a scoped logger is called directly
+	return Log.Desugar().WithOptions(zap.AddCallerSkip(-1)).Sugar().With(keysAndValues...)
+}
+
+// ProfileFields are the fields that tie a line to the profile being processed and
+// what was being done to it, for use with With
+func ProfileFields(profileURL, profileName, action string) []interface{} {
+	return []interface{}{"profile_url", profileURL, "profile_name", profileName, "action", action}
+}
+
+// Bind adds the given key-value pairs to every line the global logger writes from now on,
+// replacing the ones bound before
+func Bind(keysAndValues ...interface{}) {
+	Log = root.With(keysAndValues...)
+}
+
 // Debug logs a debug message
 func Debug(args ...interface{}) {
 	Log.Debug(args...)
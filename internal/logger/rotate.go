@@ -0,0 +1,144 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat stamps rotated log files; it sorts chronologically and is safe in file names
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// Rotation controls when the log file is rotated and how many rotated files are kept
+type Rotation struct {
+	MaxSizeMB  int // rotate once the file would grow past this size; 0 never rotates
+	MaxAgeDays int // delete rotated files older than this; 0 keeps them regardless of age
+	MaxBackups int // keep at most this many rotated files; 0 keeps them all
+}
+
+// rotatingFile is a log file that is renamed aside with a timestamp once it reaches
+// its size limit, with a fresh file taking its place
+type rotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	rotation Rotation
+	file     *os.File
+	size     int64
+}
+
+// openRotatingFile opens the log file for appending, creating its directory if needed
+func openRotatingFile(path string, rotation Rotation) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	r := &rotatingFile{path: path, rotation: rotation}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// open opens the current log file and records its size
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+// Write appends p, rotating first when p would take the file past its size limit
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	limit := int64(r.rotation.MaxSizeMB) * 1024 * 1024
+	if limit > 0 && r.size > 0 && r.size+int64(len(p)) > limit {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Sync flushes the log file to disk
+func (r *rotatingFile) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Sync()
+}
+
+// rotate moves the current file aside, opens a fresh one and prunes old backups
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+
+	if err := os.Rename(r.path, r.backupName(time.Now())); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	// Pruning is housekeeping; a leftover backup never stops logging
+	r.prune(time.Now())
+	return nil
+}
+
+// backupName returns the name the current file is rotated to at t: bot.log becomes
+// bot-2006-01-02T15-04-05.000.log
+func (r *rotatingFile) backupName(t time.Time) string {
+	ext := filepath.Ext(r.path)
+	return fmt.Sprintf("%s-%s%s", strings.TrimSuffix(r.path, ext), t.Format(backupTimeFormat), ext)
+}
+
+// prune deletes rotated files beyond MaxBackups or older than MaxAgeDays
+func (r *rotatingFile) prune(now time.Time) {
+	ext := filepath.Ext(r.path)
+	prefix := strings.TrimSuffix(r.path, ext) + "-"
+
+	matches, err := filepath.Glob(prefix + "*" + ext)
+	if err != nil {
+		return
+	}
+
+	type backup struct {
+		path    string
+		rotated time.Time
+	}
+	var backups []backup
+	for _, match := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(match, prefix), ext)
+		if rotated, err := time.ParseInLocation(backupTimeFormat, stamp, time.Local); err == nil {
+			backups = append(backups, backup{match, rotated})
+		}
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].rotated.After(backups[j].rotated) })
+
+	for i, b := range backups {
+		tooMany := r.rotation.MaxBackups > 0 && i >= r.rotation.MaxBackups
+		tooOld := r.rotation.MaxAgeDays > 0 && now.Sub(b.rotated) > time.Duration(r.rotation.MaxAgeDays)*24*time.Hour
+		if tooMany || tooOld {
+			os.Remove(b.path)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRotatingFileRotatesAtSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "bot.log")
+	r, err := openRotatingFile(path, Rotation{MaxSizeMB: 1})
+	if err != nil {
+		t.Fatalf("openRotatingFile: %v", err)
+	}
+	defer r.file.Close()
+
+	line := bytes.Repeat([]byte("x"), 1023)
+	line = append(line, '\n')
+	for i := 0; i < 1024; i++ {
+		if _, err := r.Write(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if backups, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "bot-*.log")); len(backups) != 0 {
+		t.Fatalf("rotated at exactly the limit: %v", backups)
+	}
+
+	// The next line would take the file past 1MB
+	if _, err := r.Write(line); err != nil {
+		t.Fatal(err)
+	}
+	backups, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "bot-*.log"))
+	if len(backups) != 1 {
+		t.Fatalf("backups = %v, want one", backups)
+	}
+	if info, _ := os.Stat(backups[0]); info.Size() != 1024*1024 {
+		t.Fatalf("backup holds %d bytes, want the full megabyte", info.Size())
+	}
+	if info, _ := os.Stat(path); info.Size() != int64(len(line)) {
+		t.Fatalf("fresh log holds %d bytes, want the one line", info.Size())
+	}
+}
+
+func TestRotatingFileReopensAtItsSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.log")
+	if err := os.WriteFile(path, []byte("from the last run\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := openRotatingFile(path, Rotation{MaxSizeMB: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.file.Close()
+	if r.size != int64(len("from the last run\n")) {
+		t.Fatalf("size = %d, want the existing file's", r.size)
+	}
+}
+
+func TestPruneBackups(t *testing.T) {
+	now := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.Local)
+
+	for _, tt := range []struct {
+		name     string
+		rotation Rotation
+		kept     []int // ages in days of the backups left
+	}{
+		{"by count", Rotation{MaxBackups: 2}, []int{1, 3}},
+		{"by age", Rotation{MaxAgeDays: 7}, []int{1, 3, 6}},
+		{"by both", Rotation{MaxBackups: 1, MaxAgeDays: 7}, []int{1}},
+		{"keep all", Rotation{}, []int{1, 3, 6, 10, 30}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			r := &rotatingFile{path: filepath.Join(dir, "bot.log"), rotation: tt.rotation}
+			for _, age := range []int{1, 3, 6, 10, 30} {
+				if err := os.WriteFile(r.backupName(now.AddDate(0, 0, -age)), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// Files that aren't backups are left alone
+			other := filepath.Join(dir, "bot-notes.log")
+			os.WriteFile(other, nil, 0644)
+
+			r.prune(now)
+
+			for _, age := range tt.kept {
+				if _, err := os.Stat(r.backupName(now.AddDate(0, 0, -age))); err != nil {
+					t.Errorf("the %d-day-old backup was deleted", age)
+				}
+			}
+			backups, _ := filepath.Glob(filepath.Join(dir, "bot-2*.log"))
+			if len(backups) != len(tt.kept) {
+				t.Errorf("backups left: %v, want %d", backups, len(tt.kept))
+			}
+			if _, err := os.Stat(other); err != nil {
+				t.Error("pruned a file that isn't a backup")
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package messaging
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/render"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+	"github.com/Tanukumar01/linkedin-automation/internal/templates"
+)
+
+// ErrNoInMailCredits is returned once the account has no InMail credits left
+var ErrNoInMailCredits = errors.New("no InMail credits left")
+
+// ErrInMailLimitReached is returned once messaging.inmail.daily_limit InMails went out today
+var ErrInMailLimitReached = errors.New("daily InMail limit reached")
+
+// ErrInMailUnavailable is returned when the profile offers no InMail compose form, e.g.
+// on an account without Premium
+var ErrInMailUnavailable = errors.New("InMail not available for this profile")
+
+// Credit counters as LinkedIn words them: "Use 1 of 14 InMail credits", "14 InMail
+// credits left", or "You're out of InMail credits"
+var (
+	creditsOfPattern   = regexp.MustCompile(`(?i)\bof\s+(\d+)\s+InMail\s+credits?`)
+	creditsLeftPattern = regexp.MustCompile(`(?i)(\d+)\s+(?:InMail\s+)?credits?\s+(?:left|remaining|available)`)
+	creditsOutPattern  = regexp.MustCompile(`(?i)\b(?:out of|no)\s+(?:InMail\s+)?credits?`)
+)
+
+// parseInMailCredits reads the remaining credits from the compose form's counter,
+// reporting false when the text doesn't say
+func parseInMailCredits(text string) (int, bool) {
+	if creditsOutPattern.MatchString(text) {
+		return 0, true
+	}
+	for _, pattern := range []*regexp.Regexp{creditsOfPattern, creditsLeftPattern} {
+		if m := pattern.FindStringSubmatch(text); m != nil {
+			if n, err := strconv.Atoi(m[1]); err == nil {
+				return n, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// InMailCredits returns the InMail credits left as last read from the compose form,
+// and false before any was read
+func (mm *MessageManager) InMailCredits() (int, bool) {
+	return mm.inMailCredits, mm.inMailCredits >= 0
+}
+
+// SendInMail sends an InMail with the given subject and body to a profile outside the network
+func (mm *MessageManager) SendInMail(profileURL, subject, body string) (*MessageResult, error) {
+	return mm.sendInMail(profileURL, "", subject, body, "")
+}
+
+// SendTemplatedInMail sends an InMail generated from the messaging.inmail subjects and templates
+func (mm *MessageManager) SendTemplatedInMail(profileURL, profileName, jobTitle, company string) (*MessageResult, error) {
+	subject, body, templateID, err := mm.generateInMail(profileURL, profileName, jobTitle, company)
+	if err != nil {
+		return nil, err
+	}
+	return mm.sendInMail(profileURL, profileName, subject, body, templateID)
+}
+
+// sendInMail opens the InMail compose form on a profile, fills in the subject and body and sends it
+func (mm *MessageManager) sendInMail(profileURL, profileName, subject, body, templateID string) (_ *MessageResult, err error) {
+	defer mm.forProfile(profileURL, profileName, "inmail")()
+	started := time.Now()
+	defer func() { mm.auditFailure(profileURL, profileName, err, started) }()
+
+	mm.log.Infof("Sending InMail to: %s", profileURL)
+
+	// Credits seen running out earlier in the run won't come back
+	if mm.inMailCredits == 0 {
+		return nil, ErrNoInMailCredits
+	}
+
+	if err := mm.checkInMailLimit(); err != nil {
+		return nil, err
+	}
+	if err := mm.checkLimits(); err != nil {
+		return nil, err
+	}
+
+	// Non-connections get the InMail form behind the same Message button
+	if err := mm.openThread(profileURL); err != nil {
+		if errors.Is(err, ErrNotConnected) {
+			return nil, fmt.Errorf("%w: no Message button", ErrInMailUnavailable)
+		}
+		return nil, err
+	}
+
+	subjectField, err := selectors.WaitFirst(mm.page, selectors.InMailSubject, 10*time.Second)
+	if err != nil {
+		return nil, mm.captureFailure(fmt.Errorf("%w: no subject field", ErrInMailUnavailable))
+	}
+
+	if credits, ok := mm.readInMailCredits(); ok {
+		mm.inMailCredits = credits
+		mm.log.Infof("InMail credits left: %d", credits)
+		if credits == 0 {
+			return nil, ErrNoInMailCredits
+		}
+	}
+
+	if err := mm.typer.TypeText(subjectField, subject); err != nil {
+		return nil, mm.captureFailure(fmt.Errorf("failed to type subject: %w", err))
+	}
+
+	mm.timing.Wait(mm.timing.ShortPause())
+
+	if err := mm.typeMessage(body); err != nil {
+		return nil, mm.captureFailure(fmt.Errorf("failed to type message: %w", err))
+	}
+
+	mm.timing.Wait(mm.timing.ThinkTime())
+
+	if err := mm.clickSendButton(); err != nil {
+		return nil, mm.captureFailure(fmt.Errorf("failed to send InMail: %w", err))
+	}
+
+	if err := mm.verifySent(body); err != nil {
+		return nil, mm.captureFailure(err)
+	}
+
+	if mm.inMailCredits > 0 {
+		mm.inMailCredits--
+	}
+	mm.log.Infof("InMail sent to: %s", profileURL)
+
+	msg := &storage.Message{
+		ProfileURL:  profileURL,
+		ProfileName: profileName,
+		Channel:     storage.ChannelInMail,
+		Subject:     subject,
+		Content:     body,
+		TemplateID:  templateID,
+		SentAt:      time.Now(),
+	}
+	if err := mm.recordMessage(msg, started, nil); err != nil {
+		mm.log.Errorf("Failed to save InMail: %v", err)
+	}
+
+	mm.cooldown()
+
+	return &MessageResult{
+		ProfileURL:  profileURL,
+		ProfileName: profileName,
+		Content:     body,
+		TemplateID:  templateID,
+	}, nil
+}
+
+// checkInMailLimit checks if the daily InMail limit has been reached
+func (mm *MessageManager) checkInMailLimit() error {
+	count, err := mm.db.GetInMailCountByDate(time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to get InMail count: %w", err)
+	}
+
+	if limit := mm.config.InMail.DailyLimit; count >= limit {
+		return fmt.Errorf("%w (%d/%d)", ErrInMailLimitReached, count, limit)
+	}
+
+	mm.log.Infof("Daily InMails: %d/%d", count, mm.config.InMail.DailyLimit)
+	return nil
+}
+
+// readInMailCredits reads the credits counter of the open compose form
+func (mm *MessageManager) readInMailCredits() (int, bool) {
+	counter, err := selectors.FindFirst(mm.page, selectors.InMailCredits)
+	if err != nil {
+		return 0, false
+	}
+	text, _ := counter.Text()
+	return parseInMailCredits(strings.TrimSpace(text))
+}
+
+// generateInMail renders a subject and a body for a profile and returns them with the body's template ID
+func (mm *MessageManager) generateInMail(profileURL, profileName, jobTitle, company string) (string, string, string, error) {
+	cfg := mm.config.InMail
+	if len(cfg.Subjects) == 0 || len(cfg.Templates) == 0 {
+		return "", "", "", fmt.Errorf("%w: no InMail subjects or templates configured", ErrInMailUnavailable)
+	}
+
+	weighted := mm.config.TemplateSelection == "weighted"
+	vars := render.Vars{FirstName: strings.Split(profileName, " ")[0], JobTitle: jobTitle, Company: company, CurationNote: mm.curationNote(profileURL)}
+
+	subject, err := render.Execute(templates.Pick(mm.rand, cfg.Subjects, weighted).Text, vars, mm.locale)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to generate InMail subject: %w", err)
+	}
+
+	vars.Subject = subject
+	template := templates.Pick(mm.rand, cfg.Templates, weighted)
+	body, err := render.Execute(template.Text, vars, mm.locale)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to generate InMail: %w", err)
+	}
+
+	return subject, body, templates.ID(template.Text), nil
+}
@@ -1,229 +1,574 @@
 package messaging
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
 	"strings"
 	"time"
 
-	"github.com/go-rod/rod"
-	
+	"github.com/Tanukumar01/linkedin-automation/internal/artifacts"
+	"github.com/Tanukumar01/linkedin-automation/internal/breaker"
+	"github.com/Tanukumar01/linkedin-automation/internal/budget"
 	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/headline"
 	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/render"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
 	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
 	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+	"github.com/Tanukumar01/linkedin-automation/internal/templates"
+	"github.com/Tanukumar01/linkedin-automation/pkg/browser"
+	"go.uber.org/zap"
 )
 
 // MessageManager handles messaging operations
 type MessageManager struct {
-	page     *rod.Page
-	config   *config.MessagingConfig
-	db       *storage.DB
-	timing   *stealth.TimingController
-	typer    *stealth.Typer
-	mouse    *stealth.MouseMover
-	scroller *stealth.Scroller
-	rand     *rand.Rand
+	page       pageops.Page
+	config     *config.MessagingConfig
+	db         *storage.DB
+	timing     *stealth.TimingController
+	typer      pageops.TextTyper
+	clicker    pageops.Clicker
+	scroller   pageops.Scroller
+	rand       *rand.Rand
+	artifacts  *artifacts.Collector
+	breaker    *breaker.Breaker
+	actions    *budget.DailyBudget // the day's action budget messages take from; nil for none
+	checkpoint func() bool         // reports whether sending should wrap up
+	locale     string
+	log        *zap.SugaredLogger
+
+	profileTimeout time.Duration // how long one profile may take; 0 for no limit
+
+	inMailCredits int    // as last read from the InMail form; -1 until read
+	layout        string // the open profile's layout, detected on each load
+}
+
+// ErrDailyLimitReached is returned once the daily message limit has been used up
+var ErrDailyLimitReached = errors.New("daily message limit reached")
+
+// ErrHourlyLimitReached is returned once messaging.hourly_limit messages went out in the last hour
+var ErrHourlyLimitReached = errors.New("hourly message limit reached")
+
+// ErrNotConfirmed is returned when the sent message doesn't show up in the thread
+var ErrNotConfirmed = errors.New("message not confirmed")
+
+// ErrNotConnected is returned when a profile offers no Message button, e.g. after the
+// connection was removed
+var ErrNotConnected = errors.New("no message button, no longer connected")
+
+// verifyWait bounds how long to wait for a sent message to appear in the thread
+const verifyWait = 10 * time.Second
+
+// verifyInterval is how often the thread is checked while confirming a sent message
+const verifyInterval = 500 * time.Millisecond
+
+// MessageResult describes a message that was sent
+type MessageResult struct {
+	ProfileURL  string
+	ProfileName string
+	Content     string
+	TemplateID  string
 }
 
 // NewMessageManager creates a new message manager
-func NewMessageManager(page *rod.Page, cfg *config.MessagingConfig, db *storage.DB, timing *stealth.TimingController, typer *stealth.Typer, mouse *stealth.MouseMover, scroller *stealth.Scroller) *MessageManager {
+func NewMessageManager(page pageops.Page, cfg *config.MessagingConfig, db *storage.DB, timing *stealth.TimingController, typer pageops.TextTyper, clicker pageops.Clicker, scroller pageops.Scroller) *MessageManager {
 	return &MessageManager{
-		page:     page,
-		config:   cfg,
-		db:       db,
-		timing:   timing,
-		typer:    typer,
-		mouse:    mouse,
-		scroller: scroller,
-		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		page:       page,
+		config:     cfg,
+		db:         db,
+		timing:     timing,
+		typer:      typer,
+		clicker:    clicker,
+		scroller:   scroller,
+		rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
+		checkpoint: func() bool { return false },
+		locale:     render.DefaultLocale,
+		log:        logger.With(),
+
+		inMailCredits: -1,
 	}
 }
 
+// SetLocale sets the locale template helpers format numbers, money and dates for
+func (mm *MessageManager) SetLocale(locale string) {
+	mm.locale = locale
+}
+
+// SetArtifacts sets the collector that captures the page when a message fails
+func (mm *MessageManager) SetArtifacts(c *artifacts.Collector) {
+	mm.artifacts = c
+}
+
+// SetBreaker sets the breaker that queued messages count towards; once it trips, sending stops
+func (mm *MessageManager) SetBreaker(b *breaker.Breaker) {
+	mm.breaker = b
+}
+
+// SetActionBudget sets the day's action budget each message takes a follow-up from
+func (mm *MessageManager) SetActionBudget(b *budget.DailyBudget) {
+	mm.actions = b
+}
+
+// SetCheckpoint sets the check made between queued messages; once it returns true the rest stay queued
+func (mm *MessageManager) SetCheckpoint(fn func() bool) {
+	mm.checkpoint = fn
+}
+
+// SetProfileTimeout abandons a message whose profile takes longer than timeout; 0 never does
+func (mm *MessageManager) SetProfileTimeout(timeout time.Duration) {
+	mm.profileTimeout = timeout
+}
+
+// SetLogger sets the logger the manager writes to, e.g. one carrying the run's fields
+func (mm *MessageManager) SetLogger(l *zap.SugaredLogger) {
+	mm.log = l
+}
+
+// forProfile makes every line the manager logs carry the profile and action until
+// the returned func restores its logger
+func (mm *MessageManager) forProfile(profileURL, profileName, action string) (restore func()) {
+	base := mm.log
+	mm.log = base.With(logger.ProfileFields(profileURL, profileName, action)...)
+	return func() { mm.log = base }
+}
+
 // SendMessage sends a message to a connection
-func (mm *MessageManager) SendMessage(profileURL, profileName, jobTitle, company string) error {
-	logger.Infof("Sending message to: %s", profileName)
+func (mm *MessageManager) SendMessage(profileURL, profileName, jobTitle, company string) (*MessageResult, error) {
+	defer mm.forProfile(profileURL, profileName, "message")()
+	return mm.sendTemplatedMessage(profileURL, profileName, jobTitle, company, mm.config.Templates)
+}
+
+// SendWelcomeMessage sends a welcome message to someone whose invitation we accepted
+func (mm *MessageManager) SendWelcomeMessage(profileURL, profileName, rawHeadline string) (*MessageResult, error) {
+	defer mm.forProfile(profileURL, profileName, "welcome_message")()
 
-	// Check daily limit
-	if err := mm.checkDailyLimit(); err != nil {
-		return err
+	candidates := mm.config.WelcomeTemplates
+	if len(candidates) == 0 {
+		candidates = mm.config.Templates
 	}
 
-	// Navigate to profile
-	if err := mm.page.Navigate(profileURL); err != nil {
-		return fmt.Errorf("failed to navigate to profile: %w", err)
+	jobTitle, company := rawHeadline, ""
+	if h := headline.Parse(rawHeadline); h.Title != "" {
+		jobTitle, company = h.Title, h.Company
 	}
 
-	if err := mm.page.WaitLoad(); err != nil {
-		return fmt.Errorf("failed to wait for profile page: %w", err)
+	return mm.sendTemplatedMessage(profileURL, profileName, jobTitle, company, candidates)
+}
+
+// sendTemplatedMessage sends a message generated from one of the given templates
+func (mm *MessageManager) sendTemplatedMessage(profileURL, profileName, jobTitle, company string, candidates []config.Template) (_ *MessageResult, err error) {
+	started := time.Now()
+	defer func() { mm.auditFailure(profileURL, profileName, err, started) }()
+	defer mm.withinBudget(&err)()
+
+	mm.log.Infof("Sending message to: %s", profileName)
+
+	// Check daily and hourly limits
+	if err := mm.checkLimits(); err != nil {
+		return nil, err
 	}
 
-	mm.timing.Wait(mm.timing.ThinkTime())
+	if err := mm.openThread(profileURL); err != nil {
+		return nil, err
+	}
 
-	// Find Message button
-	messageButton, err := mm.findMessageButton()
+	// Generate message
+	message, templateID, err := mm.generateMessage(candidates, profileURL, profileName, jobTitle, company)
 	if err != nil {
-		return fmt.Errorf("failed to find message button: %w", err)
+		return nil, err
 	}
 
-	// Click Message button
-	if err := mm.mouse.ClickElement(messageButton); err != nil {
-		return fmt.Errorf("failed to click message button: %w", err)
+	return mm.deliver(profileURL, profileName, message, templateID, started)
+}
+
+// SendText sends text as it is to a connection, without a template
+func (mm *MessageManager) SendText(profileURL, profileName, text string) (_ *MessageResult, err error) {
+	defer mm.forProfile(profileURL, profileName, "message")()
+	started := time.Now()
+	defer func() { mm.auditFailure(profileURL, profileName, err, started) }()
+	defer mm.withinBudget(&err)()
+
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("message is empty")
 	}
 
-	mm.timing.Wait(mm.timing.ShortPause())
+	mm.log.Infof("Sending message to: %s", profileName)
 
-	// Generate message
-	message := mm.generateMessage(profileName, jobTitle, company)
+	if err := mm.checkLimits(); err != nil {
+		return nil, err
+	}
+	if err := mm.openThread(profileURL); err != nil {
+		return nil, err
+	}
+
+	return mm.deliver(profileURL, profileName, text, "", started)
+}
 
+// withinBudget bounds the page to the profile's time budget until the returned func is
+// called, which then marks *err as a timeout if the budget ended it
+func (mm *MessageManager) withinBudget(err *error) func() {
+	release := pageops.WithDeadline(mm.page, mm.profileTimeout)
+	return func() {
+		release()
+		*err = pageops.TimedOut(*err, mm.profileTimeout)
+	}
+}
+
+// deliver types message into the open thread, sends it and records it once it shows up
+func (mm *MessageManager) deliver(profileURL, profileName, message, templateID string, started time.Time) (*MessageResult, error) {
 	// Type message
 	if err := mm.typeMessage(message); err != nil {
-		return fmt.Errorf("failed to type message: %w", err)
+		return nil, mm.captureFailure(fmt.Errorf("failed to type message: %w", err))
 	}
 
 	mm.timing.Wait(mm.timing.ThinkTime())
 
 	// Send message
 	if err := mm.clickSendButton(); err != nil {
-		return fmt.Errorf("failed to send message: %w", err)
+		return nil, mm.captureFailure(fmt.Errorf("failed to send message: %w", err))
+	}
+
+	// Only record the message once it shows up in the thread
+	if err := mm.verifySent(message); err != nil {
+		return nil, mm.captureFailure(err)
 	}
 
-	logger.Infof("Message sent to: %s", profileName)
+	mm.log.Infof("Message sent to: %s", profileName)
 
 	// Save to database
 	msg := &storage.Message{
 		ProfileURL:  profileURL,
 		ProfileName: profileName,
 		Content:     message,
+		TemplateID:  templateID,
 		SentAt:      time.Now(),
 	}
 
-	if err := mm.db.SaveMessage(msg); err != nil {
-		logger.Errorf("Failed to save message: %v", err)
+	if err := mm.recordMessage(msg, started, nil); err != nil {
+		mm.log.Errorf("Failed to save message: %v", err)
+	}
+
+	mm.cooldown()
+
+	return &MessageResult{
+		ProfileURL:  profileURL,
+		ProfileName: profileName,
+		Content:     message,
+		TemplateID:  templateID,
+	}, nil
+}
+
+// openThread opens the profile and its message thread. A profile without a Message
+// button fails with ErrNotConnected.
+func (mm *MessageManager) openThread(profileURL string) error {
+	// Navigate to profile
+	if err := mm.page.Navigate(profileURL); err != nil {
+		return mm.captureFailure(fmt.Errorf("failed to navigate to profile: %w", err))
+	}
+
+	if err := mm.page.WaitLoad(); err != nil {
+		return mm.captureFailure(fmt.Errorf("failed to wait for profile page: %w", err))
+	}
+
+	mm.timing.Wait(mm.timing.ThinkTime())
+	mm.layout = selectors.DetectLayout(mm.page)
+
+	// Find Message button
+	messageButton, err := mm.findMessageButton()
+	if err != nil {
+		return mm.captureFailure(fmt.Errorf("%w: %v", ErrNotConnected, err))
+	}
+
+	// Click Message button; a conversation left open would take the message instead
+	pageops.DismissInterruptions(mm.page, mm.clicker)
+	if err := mm.clicker.Click(messageButton); err != nil {
+		return mm.captureFailure(fmt.Errorf("failed to click message button: %w", err))
 	}
 
-	// Log activity
-	mm.db.LogActivity("message_sent", fmt.Sprintf("Sent to %s", profileName))
+	mm.timing.Wait(mm.timing.ShortPause())
+	return nil
+}
+
+// recordMessage saves a sent message and audits it, together with whatever else has to
+// be stored in the same transaction. started is when sending began; zero when unknown.
+func (mm *MessageManager) recordMessage(msg *storage.Message, started time.Time, also func(tx *storage.DB) error) error {
+	return mm.db.WithTx(func(tx *storage.DB) error {
+		if err := tx.SaveMessage(msg); err != nil {
+			return err
+		}
+		if also != nil {
+			if err := also(tx); err != nil {
+				return err
+			}
+		}
+		activity := "message_sent"
+		if msg.Channel == storage.ChannelInMail {
+			activity = "inmail_sent"
+		}
+		recipient := msg.ProfileName
+		if recipient == "" {
+			recipient = msg.ProfileURL
+		}
+		return tx.Audit(storage.AuditEntry{Action: activity, ProfileURL: msg.ProfileURL, Details: fmt.Sprintf("Sent to %s", recipient),
+			Result: storage.AuditSuccess, PageURL: pageops.CurrentURL(mm.page), Started: started})
+	})
+}
 
-	// Cooldown
+// auditFailure audits a message that failed to go out. Limits, which stop sending
+// before anything happens, and replies, which end a sequence, aren't failures.
+func (mm *MessageManager) auditFailure(profileURL, profileName string, err error, started time.Time) {
+	if err == nil {
+		return
+	}
+	for _, notFailed := range []error{ErrDailyLimitReached, ErrHourlyLimitReached, budget.ErrExhausted, ErrInMailLimitReached, ErrNoInMailCredits, pageops.ErrNavigationLimit, ErrReplied} {
+		if errors.Is(err, notFailed) {
+			return
+		}
+	}
+	outcome := storage.AuditFailed
+	if errors.Is(err, pageops.ErrTimeout) {
+		outcome = storage.AuditTimeout
+	}
+	mm.db.Audit(storage.AuditEntry{Action: "message_failed", ProfileURL: profileURL, Details: profileName,
+		Result: outcome, Err: err, PageURL: pageops.CurrentURL(mm.page), Started: started})
+}
+
+// cooldown waits a random time between messages
+func (mm *MessageManager) cooldown() {
 	cooldown := time.Duration(mm.config.CooldownBetweenMessagesMin+mm.rand.Intn(mm.config.CooldownBetweenMessagesMax-mm.config.CooldownBetweenMessagesMin+1)) * time.Second
+	mm.db.LogActivity(stealth.ActivityCooldown, cooldown.String())
 	mm.timing.Wait(cooldown)
+}
 
-	return nil
+// captureFailure saves the page as a debug artifact and returns err unchanged
+func (mm *MessageManager) captureFailure(err error) error {
+	mm.artifacts.Capture(mm.page, "message", err)
+	return err
 }
 
-// checkDailyLimit checks if daily message limit has been reached
-func (mm *MessageManager) checkDailyLimit() error {
+// checkLimits checks if the daily or hourly message limit or the day's action budget has been reached
+func (mm *MessageManager) checkLimits() error {
 	count, err := mm.db.GetMessagesCountByDate(time.Now())
 	if err != nil {
 		return fmt.Errorf("failed to get message count: %w", err)
 	}
 
 	if count >= mm.config.DailyLimit {
-		return fmt.Errorf("daily message limit reached (%d/%d)", count, mm.config.DailyLimit)
+		return fmt.Errorf("%w (%d/%d)", ErrDailyLimitReached, count, mm.config.DailyLimit)
 	}
 
-	logger.Infof("Daily messages: %d/%d", count, mm.config.DailyLimit)
-	return nil
-}
+	mm.log.Infof("Daily messages: %d/%d", count, mm.config.DailyLimit)
 
-// findMessageButton finds the Message button on the profile
-func (mm *MessageManager) findMessageButton() (*rod.Element, error) {
-	// Try different selectors for Message button
-	selectors := []string{
-		"button[aria-label*='Message']",
-		"button:has-text('Message')",
-		"div.pvs-profile-actions button:has-text('Message')",
-	}
-
-	for _, selector := range selectors {
-		element, err := mm.page.Element(selector)
-		if err == nil {
-			return element, nil
+	// An hourly limit of 0 leaves the hour uncapped
+	if mm.config.HourlyLimit > 0 {
+		lastHour, err := mm.db.GetMessagesCountSince(time.Now().Add(-time.Hour))
+		if err != nil {
+			return fmt.Errorf("failed to get hourly message count: %w", err)
+		}
+		if lastHour >= mm.config.HourlyLimit {
+			return fmt.Errorf("%w (%d/%d)", ErrHourlyLimitReached, lastHour, mm.config.HourlyLimit)
 		}
 	}
 
-	return nil, fmt.Errorf("message button not found")
+	// Taken last, so a message another limit stops doesn't spend it
+	return mm.actions.Take(config.ActionFollowUp)
+}
+
+// findMessageButton finds the Message button on the profile, where the profile's layout puts it
+func (mm *MessageManager) findMessageButton() (pageops.Element, error) {
+	return selectors.FindFirst(mm.page, selectors.ForLayout(selectors.MessageButton, mm.layout))
 }
 
 // typeMessage types the message in the message box
 func (mm *MessageManager) typeMessage(message string) error {
 	// Wait for message box to appear
-	time.Sleep(1 * time.Second)
+	messageBox, err := selectors.WaitFirst(mm.page, selectors.MessageBox, 10*time.Second)
+	if err != nil {
+		return fmt.Errorf("message input not found: %w", err)
+	}
+
+	// Focus and type
+	return mm.typer.TypeText(messageBox, message)
+}
 
-	// Find message input
-	selectors := []string{
-		"div.msg-form__contenteditable",
-		"div[role='textbox']",
-		"div.msg-form__msg-content-container div[contenteditable='true']",
+// clickSendButton clicks the Send button
+func (mm *MessageManager) clickSendButton() error {
+	button, err := selectors.FindFirst(mm.page, selectors.MessageSendButton)
+	if err != nil {
+		return fmt.Errorf("send button not found: %w", err)
 	}
 
-	var messageBox *rod.Element
-	var err error
+	return mm.clicker.Click(button)
+}
 
-	for _, selector := range selectors {
-		messageBox, err = mm.page.Element(selector)
-		if err == nil {
-			break
+// verifySent waits for the message to appear as the latest bubble in the thread.
+// An error toast or a failed-to-send marker fails the message.
+func (mm *MessageManager) verifySent(message string) error {
+	want := normalizeSpace(message)
+	deadline := time.Now().Add(verifyWait)
+	for {
+		for _, name := range []string{selectors.ErrorToast, selectors.MessageSendFailed} {
+			if el, err := selectors.FindFirst(mm.page, name); err == nil {
+				text, _ := el.Text()
+				return fmt.Errorf("%w: %s", ErrNotConfirmed, strings.TrimSpace(text))
+			}
+		}
+
+		if bubbles, err := selectors.FindAll(mm.page, selectors.SentMessageBubble); err == nil && len(bubbles) > 0 {
+			text, _ := bubbles[len(bubbles)-1].Text()
+			if got := normalizeSpace(text); strings.Contains(got, want) {
+				return nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w: sent message not found in the thread", ErrNotConfirmed)
 		}
+		time.Sleep(verifyInterval)
 	}
+}
 
-	if messageBox == nil {
-		return fmt.Errorf("message input not found")
+// normalizeSpace collapses runs of whitespace so rendered text compares with what was typed
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// generateMessage generates a personalized message and returns it with its template ID
+func (mm *MessageManager) generateMessage(candidates []config.Template, profileURL, profileName, jobTitle, company string) (string, string, error) {
+	if len(candidates) == 0 {
+		return "Thanks for connecting!", "", nil
 	}
 
-	// Focus and type
-	if err := messageBox.Focus(); err != nil {
-		return err
+	// Select template
+	template := templates.Pick(mm.rand, candidates, mm.config.TemplateSelection == "weighted")
+
+	// Extract first name
+	firstName := strings.Split(profileName, " ")[0]
+
+	// Fill variables and helpers
+	vars := render.Vars{FirstName: firstName, JobTitle: jobTitle, Company: company, CurationNote: mm.curationNote(profileURL)}
+	message, err := render.Execute(template.Text, vars, mm.locale)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate message: %w", err)
 	}
 
-	return mm.typer.TypeText(mm.page, messageBox, message)
+	return message, templates.ID(template.Text), nil
 }
 
-// clickSendButton clicks the Send button
-func (mm *MessageManager) clickSendButton() error {
-	selectors := []string{
-		"button[type='submit']",
-		"button.msg-form__send-button",
-		"button:has-text('Send')",
+// curationNote returns the note added to a profile by hand, empty when there is none
+func (mm *MessageManager) curationNote(profileURL string) string {
+	note, err := mm.db.GetCurationNote(profileURL)
+	if err != nil {
+		mm.log.Warnf("%v", err)
 	}
+	return note
+}
 
-	for _, selector := range selectors {
-		button, err := mm.page.Element(selector)
-		if err == nil {
-			return mm.mouse.ClickElement(button)
+// ScheduleAcceptanceMessages queues a follow-up for each newly accepted request.
+// Each message is due after a random delay, kept inside the scheduler's active window.
+func (mm *MessageManager) ScheduleAcceptanceMessages(accepted []storage.ConnectionRequest, scheduler *stealth.Scheduler) int {
+	cfg := mm.config.AcceptanceMessage
+	scheduled := 0
+
+	for _, req := range accepted {
+		log := mm.log.With(logger.ProfileFields(req.ProfileURL, req.ProfileName, "schedule_message")...)
+		now := time.Now()
+		dueAt := mm.acceptanceDueTime(now, scheduler)
+
+		msg := &storage.ScheduledMessage{
+			ProfileURL:  req.ProfileURL,
+			ProfileName: req.ProfileName,
+			JobTitle:    req.JobTitle,
+			Company:     req.Company,
+			Kind:        "acceptance",
+			DueAt:       dueAt,
+			CreatedAt:   now,
+		}
+
+		queued, err := mm.db.ScheduleMessage(msg)
+		if err != nil {
+			log.Errorf("Failed to schedule acceptance message: %v", err)
+			continue
+		}
+
+		if queued {
+			scheduled++
+			log.Infof("Scheduled follow-up to %s at %s (delay %d-%dh)", req.ProfileName, dueAt.Format(time.RFC1123), cfg.DelayMinHours, cfg.DelayMaxHours)
 		}
 	}
 
-	return fmt.Errorf("send button not found")
+	return scheduled
 }
 
-// generateMessage generates a personalized message
-func (mm *MessageManager) generateMessage(profileName, jobTitle, company string) string {
-	if len(mm.config.Templates) == 0 {
-		return "Thanks for connecting!"
-	}
+// acceptanceDueTime picks a send time after the configured delay that falls inside an active window
+func (mm *MessageManager) acceptanceDueTime(now time.Time, scheduler *stealth.Scheduler) time.Time {
+	cfg := mm.config.AcceptanceMessage
+	minDelay := time.Duration(cfg.DelayMinHours) * time.Hour
+	maxDelay := time.Duration(cfg.DelayMaxHours) * time.Hour
+	delay := minDelay + time.Duration(mm.rand.Int63n(int64(maxDelay-minDelay)+1))
 
-	// Select random template
-	template := mm.config.Templates[mm.rand.Intn(len(mm.config.Templates))]
+	dueAt := now.Add(delay)
+	windowEnd := scheduler.ActiveWindowEnd(now)
 
-	// Extract first name
-	firstName := strings.Split(profileName, " ")[0]
+	if !dueAt.After(windowEnd) {
+		return dueAt
+	}
 
-	// Replace variables
-	message := strings.ReplaceAll(template, "{{firstName}}", firstName)
-	message = strings.ReplaceAll(message, "{{jobTitle}}", jobTitle)
-	message = strings.ReplaceAll(message, "{{company}}", company)
+	// Squeeze into what's left of today if the minimum delay still fits
+	if earliest := now.Add(minDelay); earliest.Before(windowEnd) {
+		return earliest.Add(time.Duration(mm.rand.Int63n(int64(windowEnd.Sub(earliest)))))
+	}
 
-	return message
+	// Otherwise carry the remaining spread over into the next window
+	return scheduler.NextActiveStart(now).Add(time.Duration(mm.rand.Int63n(int64(maxDelay-minDelay) + 1)))
 }
 
-// SendFollowUpMessages sends follow-up messages to newly accepted connections
-func (mm *MessageManager) SendFollowUpMessages() error {
-	logger.Info("Checking for newly accepted connections")
+// SendDueMessages sends scheduled messages whose time has come.
+// Messages left over when the daily limit is hit stay queued for the next run.
+func (mm *MessageManager) SendDueMessages() ([]*MessageResult, error) {
+	due, err := mm.db.GetDueScheduledMessages(time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due messages: %w", err)
+	}
 
-	// Get uncontacted profiles (this would need to be implemented in the database)
-	// For now, we'll skip this functionality
-	
-	return nil
+	var results []*MessageResult
+	for _, msg := range due {
+		if mm.checkpoint() {
+			mm.log.Info("Messaging time budget used, remaining messages stay queued")
+			break
+		}
+
+		log := mm.log.With(logger.ProfileFields(msg.ProfileURL, msg.ProfileName, "message")...)
+		result, err := mm.SendMessage(msg.ProfileURL, msg.ProfileName, msg.JobTitle, msg.Company)
+		if err != nil {
+			if stopsSending(err) {
+				return results, err
+			}
+
+			log.Errorf("Failed to send scheduled message to %s: %v", msg.ProfileName, err)
+			if mm.breaker.Record(err) {
+				return results, mm.breaker.Err()
+			}
+			continue
+		}
+		mm.breaker.Record(nil)
+
+		if err := mm.db.UpdateScheduledMessageStatus(msg.ID, "sent"); err != nil {
+			log.Errorf("Failed to mark scheduled message sent: %v", err)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// stopsSending reports whether err ends the sending pass: a limit is used up or the browser
+// can't go on, so the remaining messages stay queued for a later run
+func stopsSending(err error) bool {
+	return errors.Is(err, ErrDailyLimitReached) || errors.Is(err, ErrHourlyLimitReached) || errors.Is(err, budget.ErrExhausted) ||
+		errors.Is(err, pageops.ErrNavigationLimit) || browser.NeedsRelaunch(err)
 }
@@ -0,0 +1,361 @@
+package messaging
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// ErrReplied is returned when the contact has written back in the thread
+var ErrReplied = errors.New("contact replied")
+
+// sequenceFor returns the sequence a connection from the campaign is enrolled in: one
+// for that campaign, else the first without a campaign, else nil
+func (mm *MessageManager) sequenceFor(campaign string) *config.SequenceConfig {
+	var fallback *config.SequenceConfig
+	for i := range mm.config.Sequences {
+		sequence := &mm.config.Sequences[i]
+		switch {
+		case sequence.Campaign == campaign:
+			return sequence
+		case sequence.Campaign == "" && fallback == nil:
+			fallback = sequence
+		}
+	}
+	return fallback
+}
+
+// sequenceByName returns the configured sequence with the given name, or nil
+func (mm *MessageManager) sequenceByName(name string) *config.SequenceConfig {
+	for i := range mm.config.Sequences {
+		if mm.config.Sequences[i].Name == name {
+			return &mm.config.Sequences[i]
+		}
+	}
+	return nil
+}
+
+// EnrollInSequences starts a sequence for each newly accepted request whose campaign has
+// one. It returns how many were enrolled and the requests no sequence applies to.
+func (mm *MessageManager) EnrollInSequences(accepted []storage.ConnectionRequest) (int, []storage.ConnectionRequest) {
+	enrolled := 0
+	var rest []storage.ConnectionRequest
+
+	for _, req := range accepted {
+		sequence := mm.sequenceFor(req.Campaign)
+		if sequence == nil {
+			rest = append(rest, req)
+			continue
+		}
+
+		log := mm.log.With(logger.ProfileFields(req.ProfileURL, req.ProfileName, "schedule_message")...)
+		state := &storage.SequenceState{
+			ProfileURL:  req.ProfileURL,
+			ProfileName: req.ProfileName,
+			JobTitle:    req.JobTitle,
+			Company:     req.Company,
+			Sequence:    sequence.Name,
+			NextDueAt:   time.Now().AddDate(0, 0, sequence.Steps[0].DelayDays),
+		}
+
+		started, err := mm.db.StartSequence(state)
+		if err != nil {
+			log.Errorf("Failed to start sequence %s: %v", sequence.Name, err)
+			continue
+		}
+
+		if started {
+			enrolled++
+			log.Infof("Enrolled %s in sequence %s, first step at %s", req.ProfileName, sequence.Name, state.NextDueAt.Format(time.RFC1123))
+		}
+	}
+
+	return enrolled, rest
+}
+
+// ProcessSequences sends the sequence steps that are due, first settling any step whose
+// Send click was interrupted. A contact who replied, or can no longer be messaged, leaves
+// their sequence for good. Steps left over when a limit is hit stay due for the next run.
+func (mm *MessageManager) ProcessSequences() ([]*MessageResult, error) {
+	var results []*MessageResult
+
+	reconciled, err := mm.reconcileSequences()
+	results = append(results, reconciled...)
+	if err != nil {
+		return results, err
+	}
+
+	due, err := mm.db.GetDueSequenceStates(time.Now())
+	if err != nil {
+		return results, fmt.Errorf("failed to get due sequence steps: %w", err)
+	}
+
+	for _, state := range due {
+		if mm.checkpoint() {
+			mm.log.Info("Messaging time budget used, remaining sequence steps stay due")
+			break
+		}
+
+		log := mm.log.With(logger.ProfileFields(state.ProfileURL, state.ProfileName, "sequence_message")...)
+
+		sequence := mm.sequenceByName(state.Sequence)
+		if sequence == nil || state.Step >= len(sequence.Steps) {
+			// The sequence was removed or shortened in the config since enrollment
+			log.Warnf("Sequence %s has no step %d any more, cancelling it", state.Sequence, state.Step+1)
+			mm.endSequence(&state, storage.SequenceCancelled)
+			continue
+		}
+
+		result, err := mm.sendStep(&state, sequence)
+		switch {
+		case err == nil:
+			results = append(results, result)
+			mm.breaker.Record(nil)
+		case errors.Is(err, ErrReplied):
+			log.Infof("%s replied, ending sequence %s", state.ProfileName, state.Sequence)
+			mm.endSequence(&state, storage.SequenceReplied)
+			mm.breaker.Record(nil)
+		case errors.Is(err, ErrNotConnected):
+			log.Infof("%s can no longer be messaged, cancelling sequence %s: %v", state.ProfileName, state.Sequence, err)
+			mm.endSequence(&state, storage.SequenceCancelled)
+			// A Message button that went missing for everyone is a broken selector
+			if mm.breaker.Record(err) {
+				return results, mm.breaker.Err()
+			}
+		case stopsSending(err):
+			return results, err
+		default:
+			log.Errorf("Failed to send step %d of sequence %s: %v", state.Step+1, state.Sequence, err)
+			if mm.breaker.Record(err) {
+				return results, mm.breaker.Err()
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// sendStep sends the sequence's current step. The row is put in the sending state just
+// before the Send click, so a crash between the click and the save never sends the step
+// twice: reconcileSequences settles it from the thread on the next run.
+func (mm *MessageManager) sendStep(state *storage.SequenceState, sequence *config.SequenceConfig) (_ *MessageResult, err error) {
+	defer mm.forProfile(state.ProfileURL, state.ProfileName, "sequence_message")()
+	started := time.Now()
+	defer func() { mm.auditFailure(state.ProfileURL, state.ProfileName, err, started) }()
+
+	mm.log.Infof("Sending step %d/%d of sequence %s to: %s", state.Step+1, len(sequence.Steps), sequence.Name, state.ProfileName)
+
+	if err := mm.checkLimits(); err != nil {
+		return nil, err
+	}
+
+	if err := mm.openThread(state.ProfileURL); err != nil {
+		return nil, err
+	}
+
+	if mm.hasReply() {
+		return nil, ErrReplied
+	}
+
+	message, templateID, err := mm.generateMessage(sequence.Steps[state.Step].Templates, state.ProfileURL, state.ProfileName, state.JobTitle, state.Company)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mm.typeMessage(message); err != nil {
+		return nil, mm.captureFailure(fmt.Errorf("failed to type message: %w", err))
+	}
+
+	mm.timing.Wait(mm.timing.ThinkTime())
+
+	if err := mm.db.MarkSequenceSending(state.ID, message); err != nil {
+		return nil, fmt.Errorf("failed to record sequence step: %w", err)
+	}
+
+	if err := mm.clickSendButton(); err != nil {
+		// The click didn't happen, so the step can go out on a later run
+		if err := mm.db.SetSequenceStatus(state.ID, storage.SequenceActive); err != nil {
+			mm.log.Errorf("Failed to reset sequence step: %v", err)
+		}
+		return nil, mm.captureFailure(fmt.Errorf("failed to send message: %w", err))
+	}
+
+	// An unconfirmed step stays in the sending state; reconciliation decides whether it went out
+	if err := mm.verifySent(message); err != nil {
+		return nil, mm.captureFailure(err)
+	}
+
+	mm.log.Infof("Sequence step sent to: %s", state.ProfileName)
+
+	msg := &storage.Message{
+		ProfileURL:  state.ProfileURL,
+		ProfileName: state.ProfileName,
+		Content:     message,
+		TemplateID:  templateID,
+		SentAt:      time.Now(),
+	}
+	if err := mm.recordMessage(msg, started, mm.advance(state, sequence)); err != nil {
+		mm.log.Errorf("Failed to record sequence step, it will be reconciled on the next run: %v", err)
+	}
+
+	mm.cooldown()
+
+	return &MessageResult{
+		ProfileURL:  state.ProfileURL,
+		ProfileName: state.ProfileName,
+		Content:     message,
+		TemplateID:  templateID,
+	}, nil
+}
+
+// advance returns the update that moves a sequence past its current step
+func (mm *MessageManager) advance(state *storage.SequenceState, sequence *config.SequenceConfig) func(tx *storage.DB) error {
+	return func(tx *storage.DB) error {
+		next := state.Step + 1
+		if next >= len(sequence.Steps) {
+			return tx.AdvanceSequence(state.ID, next, time.Now(), true)
+		}
+		return tx.AdvanceSequence(state.ID, next, time.Now().AddDate(0, 0, sequence.Steps[next].DelayDays), false)
+	}
+}
+
+// reconcileSequences settles every step left in the sending state by an interrupted run:
+// a step found in the thread counts as sent, one that isn't is due again
+func (mm *MessageManager) reconcileSequences() ([]*MessageResult, error) {
+	states, err := mm.db.GetSequenceStatesByStatus(storage.SequenceSending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get in-flight sequence steps: %w", err)
+	}
+
+	if len(states) == 0 {
+		return nil, nil
+	}
+
+	mm.log.Infof("Reconciling %d in-flight sequence steps", len(states))
+
+	var results []*MessageResult
+	for _, state := range states {
+		result, err := mm.reconcileStep(&state)
+		if err != nil {
+			if stopsSending(err) {
+				return results, err
+			}
+			continue
+		}
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// reconcileStep checks the thread for an in-flight step and records what happened. It
+// returns the step as a result when it turns out to have been sent.
+func (mm *MessageManager) reconcileStep(state *storage.SequenceState) (*MessageResult, error) {
+	defer mm.forProfile(state.ProfileURL, state.ProfileName, "reconcile")()
+
+	sequence := mm.sequenceByName(state.Sequence)
+	if sequence == nil || state.Step >= len(sequence.Steps) {
+		mm.log.Warnf("Sequence %s has no step %d any more, cancelling it", state.Sequence, state.Step+1)
+		mm.endSequence(state, storage.SequenceCancelled)
+		return nil, nil
+	}
+
+	if err := mm.openThread(state.ProfileURL); err != nil {
+		if errors.Is(err, ErrNotConnected) {
+			mm.log.Infof("%s can no longer be messaged, cancelling sequence %s", state.ProfileName, state.Sequence)
+			mm.endSequence(state, storage.SequenceCancelled)
+			return nil, nil
+		}
+		mm.log.Warnf("Could not open the thread, leaving the step in-flight: %v", err)
+		return nil, err
+	}
+
+	sent, err := mm.threadContains(state.ProfileURL, state.PendingContent)
+	if err != nil {
+		mm.log.Warnf("Could not check the thread, leaving the step in-flight: %v", err)
+		return nil, err
+	}
+	if !sent {
+		mm.log.Infof("Step %d of sequence %s was not sent, it is due again", state.Step+1, state.Sequence)
+		if err := mm.db.SetSequenceStatus(state.ID, storage.SequenceActive); err != nil {
+			mm.log.Errorf("Failed to reset sequence step: %v", err)
+		}
+		return nil, nil
+	}
+
+	mm.log.Infof("Step %d of sequence %s went out before the interruption", state.Step+1, state.Sequence)
+
+	msg := &storage.Message{
+		ProfileURL:  state.ProfileURL,
+		ProfileName: state.ProfileName,
+		Content:     state.PendingContent,
+		SentAt:      state.UpdatedAt,
+	}
+	if err := mm.recordMessage(msg, time.Time{}, mm.advance(state, sequence)); err != nil {
+		mm.log.Errorf("Failed to record reconciled sequence step: %v", err)
+		return nil, nil
+	}
+
+	return &MessageResult{
+		ProfileURL:  state.ProfileURL,
+		ProfileName: state.ProfileName,
+		Content:     state.PendingContent,
+	}, nil
+}
+
+// endSequence stops a contact's sequence for good; a reply is also recorded on their
+// connection request
+func (mm *MessageManager) endSequence(state *storage.SequenceState, status string) {
+	if err := mm.db.SetSequenceStatus(state.ID, status); err != nil {
+		mm.log.Errorf("Failed to end sequence: %v", err)
+		return
+	}
+
+	if status == storage.SequenceReplied {
+		if _, err := mm.db.MarkConnectionReplied(state.ProfileURL); err != nil {
+			mm.log.Errorf("Failed to mark connection replied: %v", err)
+		}
+	}
+	mm.db.Audit(storage.AuditEntry{Action: "sequence_" + status, ProfileURL: state.ProfileURL, Details: fmt.Sprintf("%s: %s", state.ProfileName, state.Sequence),
+		Result: storage.AuditSuccess, PageURL: pageops.CurrentURL(mm.page)})
+}
+
+// hasReply reports whether the open thread holds a message from the contact
+func (mm *MessageManager) hasReply() bool {
+	return selectors.Has(mm.page, selectors.ReceivedMessageBubble)
+}
+
+// threadContains reports whether the open thread holds message once more than it was
+// recorded as sent to the profile. A previous step may have read the same, and that
+// one is already on record.
+func (mm *MessageManager) threadContains(profileURL, message string) (bool, error) {
+	bubbles, err := selectors.FindAll(mm.page, selectors.SentMessageBubble)
+	if err != nil {
+		return false, nil
+	}
+	want := normalizeSpace(message)
+	inThread := 0
+	for _, bubble := range bubbles {
+		if text, _ := bubble.Text(); strings.Contains(normalizeSpace(text), want) {
+			inThread++
+		}
+	}
+	if inThread == 0 {
+		return false, nil
+	}
+
+	recorded, err := mm.db.CountMessagesWithContent(profileURL, message)
+	if err != nil {
+		return false, fmt.Errorf("failed to count recorded messages: %w", err)
+	}
+	return inThread > recorded, nil
+}
@@ -0,0 +1,277 @@
+package messaging
+
+import (
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/clock"
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops/pagetest"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger("error", "console", "stdout", logger.Rotation{})
+	os.Exit(m.Run())
+}
+
+const profileURL = "https://www.linkedin.com/in/ada-lovelace/"
+
+// The two steps of the test sequence, as rendered for Ada
+const (
+	firstStep  = "Thanks for connecting, Ada!"
+	secondStep = "How are things at Engines Ltd?"
+)
+
+// thread is a profile with its message thread open: the sent and received messages in
+// order, the message box and Send. Without a Message button the profile can't be messaged.
+func thread(messageButton bool, sent []string, received []string) string {
+	var b strings.Builder
+	b.WriteString(`<html><body><main><section class="pv-top-card"><h1>Ada Lovelace</h1><div class="pvs-profile-actions">`)
+	if messageButton {
+		b.WriteString(`<button>Message</button>`)
+	}
+	b.WriteString(`</div></section><ul class="msg-s-message-list">`)
+	for _, text := range received {
+		b.WriteString(`<li class="msg-s-event-listitem msg-s-event-listitem--other"><p class="msg-s-event-listitem__body">` + text + `</p></li>`)
+	}
+	for _, text := range sent {
+		b.WriteString(`<li class="msg-s-event-listitem"><p class="msg-s-event-listitem__body">` + text + `</p></li>`)
+	}
+	b.WriteString(`</ul><div class="msg-form__contenteditable"></div><button type="submit">Send</button></main></body></html>`)
+	return b.String()
+}
+
+// harness is a MessageManager with one contact enrolled in a two-step sequence
+type harness struct {
+	mm      *MessageManager
+	page    *pagetest.Page
+	db      *storage.DB
+	clicker *pagetest.Clicker
+	state   storage.SequenceState
+}
+
+func newHarness(t *testing.T, profile string) *harness {
+	t.Helper()
+
+	db, err := storage.NewDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	page := pagetest.New("about:blank", "")
+	page.Serve(profileURL, profile)
+
+	cfg := &config.MessagingConfig{
+		DailyLimit: 10,
+		Sequences: []config.SequenceConfig{{Name: "welcome", Steps: []config.SequenceStepConfig{
+			{DelayDays: 1, Templates: []config.Template{{Text: "Thanks for connecting, {{firstName}}!"}}},
+			{DelayDays: 7, Templates: []config.Template{{Text: "How are things at {{company}}?"}}},
+		}}},
+	}
+
+	timing := stealth.NewTimingController(1, 2, 1, 2, 200)
+	timing.SetClock(clock.NewFake(time.Now()))
+
+	h := &harness{page: page, db: db, clicker: &pagetest.Clicker{}}
+	h.mm = NewMessageManager(page, cfg, db, timing, &pagetest.Typer{}, h.clicker, &pagetest.Scroller{})
+	h.mm.rand = rand.New(rand.NewSource(1))
+
+	h.state = storage.SequenceState{ProfileURL: profileURL, ProfileName: "Ada Lovelace", Company: "Engines Ltd",
+		Sequence: "welcome", NextDueAt: time.Now().Add(-time.Minute)}
+	if started, err := db.StartSequence(&h.state); err != nil || !started {
+		t.Fatalf("StartSequence = %v, %v", started, err)
+	}
+	return h
+}
+
+// status returns the contact's sequence row, looked up in every status
+func (h *harness) status(t *testing.T) storage.SequenceState {
+	t.Helper()
+	for _, status := range []string{storage.SequenceActive, storage.SequenceSending, storage.SequenceCompleted, storage.SequenceReplied, storage.SequenceCancelled} {
+		states, err := h.db.GetSequenceStatesByStatus(status)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(states) == 1 {
+			return states[0]
+		}
+	}
+	t.Fatal("the sequence row is gone")
+	return storage.SequenceState{}
+}
+
+// sent returns how many messages were recorded today
+func (h *harness) sent(t *testing.T) int {
+	t.Helper()
+	n, err := h.db.GetMessagesCountByDate(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestProcessSequencesSendsDueStep(t *testing.T) {
+	h := newHarness(t, thread(true, nil, nil))
+	h.page.OnClick("button", func(el *pagetest.Element) {
+		if text, _ := el.Text(); text == "Send" {
+			h.page.SetHTML(thread(true, []string{firstStep}, nil))
+		}
+	})
+
+	results, err := h.mm.ProcessSequences()
+	if err != nil {
+		t.Fatalf("ProcessSequences: %v", err)
+	}
+	if len(results) != 1 || results[0].Content != firstStep {
+		t.Fatalf("results = %+v, want the first step sent", results)
+	}
+	if state := h.status(t); state.Status != storage.SequenceActive || state.Step != 1 || state.PendingContent != "" {
+		t.Fatalf("sequence = %s at step %d (pending %q), want active at step 1", state.Status, state.Step, state.PendingContent)
+	}
+	if n := h.sent(t); n != 1 {
+		t.Fatalf("recorded %d messages, want 1", n)
+	}
+}
+
+func TestSendStepLeavesUnconfirmedStepSending(t *testing.T) {
+	h := newHarness(t, thread(true, nil, nil))
+	h.page.OnClick("button", func(el *pagetest.Element) {
+		if text, _ := el.Text(); text == "Send" {
+			h.page.SetHTML(thread(true, nil, nil) + `<div class="artdeco-toast-item--error">Something went wrong</div>`)
+		}
+	})
+
+	sequence := h.mm.sequenceByName("welcome")
+	if _, err := h.mm.sendStep(&h.state, sequence); !errors.Is(err, ErrNotConfirmed) {
+		t.Fatalf("sendStep = %v, want %v", err, ErrNotConfirmed)
+	}
+
+	// Whether it went out is settled from the thread on the next run, never by sending again
+	if state := h.status(t); state.Status != storage.SequenceSending || state.Step != 0 || state.PendingContent != firstStep {
+		t.Fatalf("sequence = %s at step %d (pending %q), want sending %q at step 0", state.Status, state.Step, state.PendingContent, firstStep)
+	}
+	if n := h.sent(t); n != 0 {
+		t.Fatalf("recorded %d messages for an unconfirmed step, want none", n)
+	}
+	due, err := h.db.GetDueSequenceStates(time.Now())
+	if err != nil || len(due) != 0 {
+		t.Fatalf("due = %+v, %v, want the in-flight step not due", due, err)
+	}
+}
+
+func TestReconcileSequences(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		step     int      // the step in flight
+		pending  string   // what it was about to send
+		recorded []string // the messages on record for the contact
+		thread   []string // the thread's sent messages
+		advance  bool
+	}{
+		{"went out", 0, firstStep, nil, []string{firstStep}, true},
+		{"didn't go out", 0, firstStep, nil, nil, false},
+		{"went out after the first step", 1, secondStep, []string{firstStep}, []string{firstStep, secondStep}, true},
+		{"didn't go out after the first step", 1, secondStep, []string{firstStep}, []string{firstStep}, false},
+		// The first step read the same, and it's already on record
+		{"only the first step reads the same", 1, firstStep, []string{firstStep}, []string{firstStep}, false},
+		{"went out reading the same as the first step", 1, firstStep, []string{firstStep}, []string{firstStep, firstStep}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, thread(true, tt.thread, nil))
+			for _, content := range tt.recorded {
+				if err := h.db.SaveMessage(&storage.Message{ProfileURL: profileURL, ProfileName: "Ada Lovelace", Content: content, SentAt: time.Now()}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.step > 0 {
+				if err := h.db.AdvanceSequence(h.state.ID, tt.step, time.Now(), false); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := h.db.MarkSequenceSending(h.state.ID, tt.pending); err != nil {
+				t.Fatal(err)
+			}
+
+			results, err := h.mm.reconcileSequences()
+			if err != nil {
+				t.Fatalf("reconcileSequences: %v", err)
+			}
+			for _, clicked := range h.clicker.Clicked {
+				if clicked == "Send" {
+					t.Fatal("reconciling clicked Send")
+				}
+			}
+
+			state, recorded := h.status(t), h.sent(t)-len(tt.recorded)
+			if tt.advance {
+				want := storage.SequenceActive
+				if tt.step == 1 {
+					want = storage.SequenceCompleted
+				}
+				if len(results) != 1 || state.Status != want || state.Step != tt.step+1 || recorded != 1 {
+					t.Fatalf("results = %d, sequence = %s at step %d, %d recorded; want the step counted as sent", len(results), state.Status, state.Step, recorded)
+				}
+				return
+			}
+			if len(results) != 0 || state.Status != storage.SequenceActive || state.Step != tt.step || state.PendingContent != "" || recorded != 0 {
+				t.Fatalf("results = %d, sequence = %s at step %d (pending %q), %d recorded; want the step due again", len(results), state.Status, state.Step, state.PendingContent, recorded)
+			}
+		})
+	}
+}
+
+func TestProcessSequencesEndsSequence(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		profile  string
+		inFlight bool // whether the step was left sending by an interrupted run
+		want     string
+	}{
+		{"no longer connected", thread(false, nil, nil), false, storage.SequenceCancelled},
+		{"no longer connected while in flight", thread(false, nil, nil), true, storage.SequenceCancelled},
+		{"replied", thread(true, nil, []string{"Thanks, likewise!"}), false, storage.SequenceReplied},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, tt.profile)
+			if tt.inFlight {
+				if err := h.db.MarkSequenceSending(h.state.ID, firstStep); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			results, err := h.mm.ProcessSequences()
+			if err != nil {
+				t.Fatalf("ProcessSequences: %v", err)
+			}
+			if len(results) != 0 {
+				t.Fatalf("results = %+v, want nothing sent", results)
+			}
+			if state := h.status(t); state.Status != tt.want {
+				t.Fatalf("sequence = %s, want %s", state.Status, tt.want)
+			}
+			if n := h.sent(t); n != 0 {
+				t.Fatalf("recorded %d messages, want none", n)
+			}
+			for _, clicked := range h.clicker.Clicked {
+				if clicked == "Send" {
+					t.Fatal("clicked Send on an ended sequence")
+				}
+			}
+
+			// It's over for good
+			due, err := h.db.GetDueSequenceStates(time.Now().AddDate(1, 0, 0))
+			if err != nil || len(due) != 0 {
+				t.Fatalf("due = %+v, %v, want none", due, err)
+			}
+		})
+	}
+}
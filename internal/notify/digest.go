@@ -0,0 +1,133 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"text/template"
+)
+
+// Digest is one day's results as the daily digest email reports them
+type Digest struct {
+	Date         string // 2006-01-02
+	Runs         int    // runs that wrote a report today
+	InvitesSent  int
+	Acceptances  int
+	Replies      int
+	MessagesSent int
+	Searches     int
+	Errors       []DigestError
+	Limits       []DigestLimit
+	Restrictions []string // limits and restrictions the runs hit
+	SafeMode     string   // why a run put the bot in safe mode
+}
+
+// DigestError is one action that failed today
+type DigestError struct {
+	Action  string
+	Profile string
+	Reason  string
+}
+
+// DigestLimit is how much of a limit is used
+type DigestLimit struct {
+	Name  string
+	Used  int
+	Limit int
+}
+
+// Reached reports whether the limit is used up
+func (l DigestLimit) Reached() bool {
+	return l.Limit > 0 && l.Used >= l.Limit
+}
+
+// maxDigestErrors caps the errors listed; the count still includes the rest
+const maxDigestErrors = 20
+
+// Subject returns the digest email's subject line
+func (d *Digest) Subject() string {
+	subject := fmt.Sprintf("LinkedIn daily digest for %s: %d invites, %d accepted, %d replies", d.Date, d.InvitesSent, d.Acceptances, d.Replies)
+	if d.SafeMode != "" {
+		subject += " (safe mode)"
+	}
+	return subject
+}
+
+// ListedErrors returns the errors the digest lists
+func (d *Digest) ListedErrors() []DigestError {
+	if len(d.Errors) > maxDigestErrors {
+		return d.Errors[:maxDigestErrors]
+	}
+	return d.Errors
+}
+
+// MoreErrors returns how many errors aren't listed
+func (d *Digest) MoreErrors() int {
+	return len(d.Errors) - len(d.ListedErrors())
+}
+
+// Render returns the digest as plain text and as HTML
+func (d *Digest) Render() (text, html string, err error) {
+	var t, h bytes.Buffer
+	if err := digestText.Execute(&t, d); err != nil {
+		return "", "", fmt.Errorf("failed to render digest: %w", err)
+	}
+	if err := digestHTML.Execute(&h, d); err != nil {
+		return "", "", fmt.Errorf("failed to render digest: %w", err)
+	}
+	return t.String(), h.String(), nil
+}
+
+var digestText = template.Must(template.New("digest").Parse(`Daily digest for {{.Date}}{{if .SafeMode}}
+
+SAFE MODE: the bot stopped and needs attention ({{.SafeMode}}){{end}}
+
+Invites sent:        {{.InvitesSent}}
+Acceptances:         {{.Acceptances}}
+Replies:             {{.Replies}}
+Messages sent:       {{.MessagesSent}}
+Searches:            {{.Searches}}
+Runs:                {{.Runs}}
+
+Limits
+{{range .Limits}}  {{.Name}}: {{.Used}} of {{.Limit}}{{if .Reached}} (reached){{end}}
+{{else}}  none configured
+{{end}}{{if .Restrictions}}
+Limits and restrictions hit
+{{range .Restrictions}}  - {{.}}
+{{end}}{{end}}
+Errors: {{len .Errors}}
+{{range .ListedErrors}}  - {{.Action}} {{.Profile}}: {{.Reason}}
+{{end}}{{if .MoreErrors}}  ... and {{.MoreErrors}} more
+{{end}}`))
+
+var digestHTML = htmltemplate.Must(htmltemplate.New("digest").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; font-size: 14px; color: #222;">
+<h2>Daily digest for {{.Date}}</h2>
+{{if .SafeMode}}<p style="color: #b00020;"><strong>Safe mode: the bot stopped and needs attention.</strong> {{.SafeMode}}</p>
+{{end}}<table cellpadding="4">
+<tr><td>Invites sent</td><td><strong>{{.InvitesSent}}</strong></td></tr>
+<tr><td>Acceptances</td><td><strong>{{.Acceptances}}</strong></td></tr>
+<tr><td>Replies</td><td><strong>{{.Replies}}</strong></td></tr>
+<tr><td>Messages sent</td><td>{{.MessagesSent}}</td></tr>
+<tr><td>Searches</td><td>{{.Searches}}</td></tr>
+<tr><td>Runs</td><td>{{.Runs}}</td></tr>
+</table>
+<h3>Limits</h3>
+{{if .Limits}}<table cellpadding="4">
+{{range .Limits}}<tr><td>{{.Name}}</td><td>{{.Used}} of {{.Limit}}</td><td>{{if .Reached}}<strong>reached</strong>{{end}}</td></tr>
+{{end}}</table>
+{{else}}<p>None configured</p>
+{{end}}{{if .Restrictions}}<h3>Limits and restrictions hit</h3>
+<ul>
+{{range .Restrictions}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}<h3>Errors: {{len .Errors}}</h3>
+{{if .Errors}}<ul>
+{{range .ListedErrors}}<li>{{.Action}} <a href="{{.Profile}}">{{.Profile}}</a>: {{.Reason}}</li>
+{{end}}{{if .MoreErrors}}<li>... and {{.MoreErrors}} more</li>
+{{end}}</ul>
+{{end}}</body>
+</html>
+`))
@@ -0,0 +1,250 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTP security modes
+const (
+	SMTPStartTLS = "starttls"
+	SMTPNone     = "none" // plaintext, for a relay on localhost
+)
+
+// EmailConfig is where an EmailNotifier sends mail
+type EmailConfig struct {
+	Host     string
+	Port     int
+	TLS      string // starttls or none
+	Username string // no authentication when empty
+	Password string
+	From     string
+	To       []string
+	Events   []string // events mailed as they happen; empty mails only the digest
+}
+
+// EmailConfigFromEnv fills in the SMTP login from SMTP_USERNAME and SMTP_PASSWORD
+func EmailConfigFromEnv(cfg EmailConfig) EmailConfig {
+	if username := os.Getenv("SMTP_USERNAME"); username != "" {
+		cfg.Username = username
+	}
+	if password := os.Getenv("SMTP_PASSWORD"); password != "" {
+		cfg.Password = password
+	}
+	return cfg
+}
+
+// EmailNotifier mails the daily digest, and the events it is configured for, over SMTP
+type EmailNotifier struct {
+	cfg    EmailConfig
+	events map[string]bool
+	dial   func(addr string) (net.Conn, error)
+	now    func() time.Time
+}
+
+// NewEmailNotifier creates an email notifier
+func NewEmailNotifier(cfg EmailConfig) *EmailNotifier {
+	allowed := make(map[string]bool)
+	for _, e := range cfg.Events {
+		allowed[e] = true
+	}
+
+	return &EmailNotifier{
+		cfg:    cfg,
+		events: allowed,
+		dial: func(addr string) (net.Conn, error) {
+			return net.DialTimeout("tcp", addr, 30*time.Second)
+		},
+		now: time.Now,
+	}
+}
+
+// Notify mails the event if its type is one of the configured events
+func (e *EmailNotifier) Notify(event Event) error {
+	if !e.events[event.Type] {
+		return nil
+	}
+
+	keys := make([]string, 0, len(event.Fields))
+	for key := range event.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	text := event.Message + "\n"
+	for _, key := range keys {
+		text += fmt.Sprintf("\n%s: %v", key, event.Fields[key])
+	}
+
+	msg, err := e.message(fmt.Sprintf("LinkedIn bot: %s", event.Type), text+"\n", "")
+	if err != nil {
+		return err
+	}
+	return e.send(msg)
+}
+
+// SendDigest mails the daily digest as plain text with an HTML alternative
+func (e *EmailNotifier) SendDigest(d *Digest) error {
+	text, html, err := d.Render()
+	if err != nil {
+		return err
+	}
+
+	msg, err := e.message(d.Subject(), text, html)
+	if err != nil {
+		return err
+	}
+	return e.send(msg)
+}
+
+// message assembles a MIME message with a plain text body, and an HTML alternative
+// when html isn't empty
+func (e *EmailNotifier) message(subject, text, html string) ([]byte, error) {
+	var buf bytes.Buffer
+	header := func(key, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+
+	header("From", e.cfg.From)
+	header("To", strings.Join(e.cfg.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", subject))
+	header("Date", e.now().Format(time.RFC1123Z))
+	header("Message-ID", e.messageID())
+	header("MIME-Version", "1.0")
+
+	if html == "" {
+		header("Content-Type", "text/plain; charset=utf-8")
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	header("Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": parts.Boundary()}))
+	buf.WriteString("\r\n")
+
+	// The last alternative is the preferred one
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", html},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to write email part: %w", err)
+		}
+		if err := writeQuotedPrintable(w, part.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write email: %w", err)
+	}
+
+	buf.Write(body.Bytes())
+	return buf.Bytes(), nil
+}
+
+// messageID returns a unique Message-ID in the sender's domain
+func (e *EmailNotifier) messageID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+
+	domain := "localhost"
+	if at := strings.LastIndex(e.cfg.From, "@"); at >= 0 {
+		domain = strings.Trim(e.cfg.From[at+1:], "> ")
+	}
+	return fmt.Sprintf("<%d.%s@%s>", e.now().UnixNano(), hex.EncodeToString(b), domain)
+}
+
+// send delivers msg to every recipient over one SMTP connection
+func (e *EmailNotifier) send(msg []byte) error {
+	addr := net.JoinHostPort(e.cfg.Host, strconv.Itoa(e.cfg.Port))
+	conn, err := e.dial(addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server %s: %w", addr, err)
+	}
+
+	client, err := smtp.NewClient(conn, e.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if e.cfg.TLS == SMTPStartTLS {
+		if err := client.StartTLS(&tls.Config{ServerName: e.cfg.Host}); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+
+	if e.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.cfg.Host)); err != nil {
+			return fmt.Errorf("failed to authenticate to SMTP server: %w", err)
+		}
+	}
+
+	if err := client.Mail(envelopeAddress(e.cfg.From)); err != nil {
+		return fmt.Errorf("failed to set sender: %w", err)
+	}
+	for _, to := range e.cfg.To {
+		if err := client.Rcpt(envelopeAddress(to)); err != nil {
+			return fmt.Errorf("failed to add recipient %s: %w", to, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// envelopeAddress returns the bare address of "Name <addr>"
+func envelopeAddress(address string) string {
+	if start := strings.LastIndex(address, "<"); start >= 0 {
+		return strings.TrimSuffix(address[start+1:], ">")
+	}
+	return strings.TrimSpace(address)
+}
+
+// writeQuotedPrintable writes s to w quoted-printable encoded
+func writeQuotedPrintable(w io.Writer, s string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(s)); err != nil {
+		return fmt.Errorf("failed to encode email: %w", err)
+	}
+	if err := qp.Close(); err != nil {
+		return fmt.Errorf("failed to encode email: %w", err)
+	}
+	return nil
+}
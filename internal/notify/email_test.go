@@ -0,0 +1,237 @@
+package notify
+
+import (
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpServer is an SMTP server on the other end of a pipe that records what it is sent
+type smtpServer struct {
+	addr string
+	auth string // the decoded AUTH PLAIN credentials
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTP makes n deliver to a recording server instead of dialing out
+func fakeSMTP(n *EmailNotifier) *smtpServer {
+	s := &smtpServer{}
+	n.dial = func(addr string) (net.Conn, error) {
+		s.addr = addr
+		client, server := net.Pipe()
+		go s.serve(server)
+		return client, nil
+	}
+	n.now = func() time.Time { return time.Date(2026, 10, 16, 18, 0, 0, 0, time.UTC) }
+	return s
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+	c := textproto.NewConn(conn)
+	c.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			c.PrintfLine("250-localhost")
+			c.PrintfLine("250 AUTH PLAIN")
+		case "AUTH":
+			creds, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(arg, "PLAIN "))
+			s.auth = string(creds)
+			c.PrintfLine("235 Authenticated")
+		case "MAIL":
+			s.from = strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")
+			c.PrintfLine("250 OK")
+		case "RCPT":
+			s.to = append(s.to, strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>"))
+			c.PrintfLine("250 OK")
+		case "DATA":
+			c.PrintfLine("354 Go ahead")
+			data, _ := io.ReadAll(c.DotReader())
+			s.data = string(data)
+			c.PrintfLine("250 Queued")
+		case "QUIT":
+			c.PrintfLine("221 Bye")
+			return
+		default:
+			c.PrintfLine("502 Not implemented")
+		}
+	}
+}
+
+func testEmailConfig(events ...string) EmailConfig {
+	return EmailConfig{
+		Host:     "localhost",
+		Port:     2525,
+		TLS:      SMTPNone,
+		Username: "bot",
+		Password: "secret",
+		From:     "LinkedIn Bot <bot@example.com>",
+		To:       []string{"ada@example.com", "Charles <charles@example.com>"},
+		Events:   events,
+	}
+}
+
+func TestSendDigest(t *testing.T) {
+	n := NewEmailNotifier(testEmailConfig())
+	server := fakeSMTP(n)
+
+	digest := &Digest{
+		Date:        "2026-10-16",
+		Runs:        2,
+		InvitesSent: 12,
+		Acceptances: 4,
+		Replies:     1,
+		Limits:      []DigestLimit{{Name: "Daily invites", Used: 12, Limit: 12}},
+		Errors:      []DigestError{{Action: "connect", Profile: "https://www.linkedin.com/in/ada/", Reason: "button <missing>"}},
+	}
+	if err := n.SendDigest(digest); err != nil {
+		t.Fatalf("SendDigest: %v", err)
+	}
+
+	if server.addr != "localhost:2525" {
+		t.Fatalf("dialed %s", server.addr)
+	}
+	if server.auth != "\x00bot\x00secret" {
+		t.Fatalf("auth = %q", server.auth)
+	}
+	if server.from != "bot@example.com" || strings.Join(server.to, ",") != "ada@example.com,charles@example.com" {
+		t.Fatalf("envelope from %s to %v", server.from, server.to)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(server.data))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if subject != "LinkedIn daily digest for 2026-10-16: 12 invites, 4 accepted, 1 replies" {
+		t.Fatalf("subject = %q", subject)
+	}
+	if id := msg.Header.Get("Message-ID"); !strings.HasSuffix(id, "@example.com>") {
+		t.Fatalf("Message-ID = %q", id)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q", msg.Header.Get("Content-Type"))
+	}
+	parts := make(map[string]string)
+	r := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextPart: %v", err)
+		}
+		body, _ := io.ReadAll(part)
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[contentType] = string(body)
+	}
+
+	text := parts["text/plain"]
+	for _, want := range []string{"Invites sent:        12", "Daily invites: 12 of 12 (reached)", "connect https://www.linkedin.com/in/ada/: button <missing>"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text part lacks %q:\n%s", want, text)
+		}
+	}
+	html := parts["text/html"]
+	for _, want := range []string{"<strong>12</strong>", "<strong>reached</strong>", "button &lt;missing&gt;"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML part lacks %q:\n%s", want, html)
+		}
+	}
+}
+
+func TestEmailNotifyConfiguredEvents(t *testing.T) {
+	n := NewEmailNotifier(testEmailConfig(EventDailyLimit))
+	server := fakeSMTP(n)
+
+	if err := n.Notify(NewEvent(EventSafeMode, "safe mode")); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if server.addr != "" {
+		t.Fatal("mailed an event that isn't configured")
+	}
+
+	event := NewEvent(EventDailyLimit, "daily connection limit reached (20/20)")
+	event.Fields = map[string]interface{}{"sent": 20, "limit": 20}
+	if err := n.Notify(event); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(server.data))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	if ct := msg.Header.Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	body, _ := io.ReadAll(msg.Body)
+	if want := "daily connection limit reached (20/20)\n\nlimit: 20\nsent: 20"; !strings.Contains(string(body), want) {
+		t.Fatalf("body = %q, want the message and its fields in order", body)
+	}
+}
+
+func TestDigestRender(t *testing.T) {
+	d := &Digest{Date: "2026-10-16", SafeMode: "restriction detected"}
+	for i := 0; i < maxDigestErrors+5; i++ {
+		d.Errors = append(d.Errors, DigestError{Action: "connect", Reason: "timeout"})
+	}
+
+	if !strings.HasSuffix(d.Subject(), "(safe mode)") {
+		t.Fatalf("subject = %q", d.Subject())
+	}
+	if len(d.ListedErrors()) != maxDigestErrors || d.MoreErrors() != 5 {
+		t.Fatalf("listed %d errors, %d more", len(d.ListedErrors()), d.MoreErrors())
+	}
+
+	text, html, err := d.Render()
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	for _, want := range []string{"SAFE MODE", "none configured", "Errors: 25", "... and 5 more"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text lacks %q:\n%s", want, text)
+		}
+	}
+	if !strings.Contains(html, "Safe mode") || !strings.Contains(html, "... and 5 more") {
+		t.Errorf("HTML lacks safe mode or the error count:\n%s", html)
+	}
+}
+
+func TestEmailConfigFromEnv(t *testing.T) {
+	t.Setenv("SMTP_USERNAME", "env-user")
+	t.Setenv("SMTP_PASSWORD", "")
+
+	cfg := EmailConfigFromEnv(EmailConfig{Username: "config-user", Password: "config-password"})
+	if cfg.Username != "env-user" || cfg.Password != "config-password" {
+		t.Fatalf("login = %s/%s, want the environment's username and the config's password", cfg.Username, cfg.Password)
+	}
+}
+
+func TestEnvelopeAddress(t *testing.T) {
+	for address, want := range map[string]string{
+		"bot@example.com":                "bot@example.com",
+		" bot@example.com ":              "bot@example.com",
+		"LinkedIn Bot <bot@example.com>": "bot@example.com",
+	} {
+		if got := envelopeAddress(address); got != want {
+			t.Errorf("envelopeAddress(%q) = %q, want %q", address, got, want)
+		}
+	}
+}
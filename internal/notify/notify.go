@@ -0,0 +1,153 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// Event types
+const (
+	EventDailyLimit   = "daily_limit_reached"
+	EventRestriction  = "restriction_detected"
+	EventChallenge    = "challenge_required"
+	EventDailySummary = "daily_summary"
+	EventOutsideHours = "outside_business_hours"
+	EventSafeMode     = "safe_mode_entered"
+	EventMonitorNew   = "monitor_matches"
+)
+
+// Event is a notification about something that happened during a run
+type Event struct {
+	Type      string                 `json:"event"`
+	Message   string                 `json:"message"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+}
+
+// NewEvent creates an event stamped with the current time
+func NewEvent(eventType, message string) Event {
+	return Event{
+		Type:      eventType,
+		Message:   message,
+		Timestamp: time.Now(),
+	}
+}
+
+// Notifier delivers events to an external channel
+type Notifier interface {
+	Notify(event Event) error
+}
+
+// Nop is a Notifier that discards every event
+type Nop struct{}
+
+// Notify discards the event
+func (Nop) Notify(Event) error {
+	return nil
+}
+
+// Multi delivers each event to every notifier in it
+type Multi []Notifier
+
+// Notify delivers the event to every notifier, returning what failed
+func (m Multi) Notify(event Event) error {
+	var errs []error
+	for _, n := range m {
+		if err := n.Notify(event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WebhookNotifier posts events to a webhook URL
+type WebhookNotifier struct {
+	url        string
+	format     string // json or slack
+	events     map[string]bool
+	client     *http.Client
+	maxRetries int
+	retryDelay time.Duration
+}
+
+// NewWebhookNotifier creates a webhook notifier.
+// An empty events list allows every event type.
+func NewWebhookNotifier(url, format string, events []string) *WebhookNotifier {
+	allowed := make(map[string]bool)
+	for _, e := range events {
+		allowed[e] = true
+	}
+
+	return &WebhookNotifier{
+		url:        url,
+		format:     format,
+		events:     allowed,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		maxRetries: 3,
+		retryDelay: time.Second,
+	}
+}
+
+// Notify posts the event if its type is allowed, retrying on server errors
+func (w *WebhookNotifier) Notify(event Event) error {
+	if len(w.events) > 0 && !w.events[event.Type] {
+		return nil
+	}
+
+	body, err := json.Marshal(w.payload(event))
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < w.maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(w.retryDelay * time.Duration(1<<(attempt-1)))
+		}
+
+		resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			lastErr = fmt.Errorf("failed to post notification: %w", err)
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= 500 {
+			lastErr = fmt.Errorf("webhook returned %s", resp.Status)
+			continue
+		}
+
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("webhook returned %s", resp.Status)
+		}
+
+		return nil
+	}
+
+	return lastErr
+}
+
+// payload builds the request body for the configured format
+func (w *WebhookNotifier) payload(event Event) interface{} {
+	if w.format != "slack" {
+		return event
+	}
+
+	keys := make([]string, 0, len(event.Fields))
+	for key := range event.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	text := fmt.Sprintf("*%s*: %s", event.Type, event.Message)
+	for _, key := range keys {
+		text += fmt.Sprintf("\n• %s: %v", key, event.Fields[key])
+	}
+
+	return map[string]string{"text": text}
+}
@@ -0,0 +1,143 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhook is a test server answering with the given statuses in turn, then 200
+type webhook struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	bodies   [][]byte
+}
+
+func newWebhook(t *testing.T, statuses ...int) *webhook {
+	t.Helper()
+	w := &webhook{statuses: statuses}
+	w.Server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.mu.Lock()
+		w.bodies = append(w.bodies, body)
+		status := http.StatusOK
+		if len(w.statuses) > 0 {
+			status, w.statuses = w.statuses[0], w.statuses[1:]
+		}
+		w.mu.Unlock()
+		rw.WriteHeader(status)
+	}))
+	t.Cleanup(w.Close)
+	return w
+}
+
+func (w *webhook) requests() [][]byte {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.bodies
+}
+
+func newTestNotifier(url, format string, events ...string) *WebhookNotifier {
+	n := NewWebhookNotifier(url, format, events)
+	n.retryDelay = time.Millisecond
+	return n
+}
+
+func TestWebhookJSONPayload(t *testing.T) {
+	hook := newWebhook(t)
+	event := NewEvent(EventDailyLimit, "daily connection limit reached (20/20)")
+	event.Fields = map[string]interface{}{"sent": 20}
+
+	if err := newTestNotifier(hook.URL, "json").Notify(event); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	requests := hook.requests()
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	var got Event
+	if err := json.Unmarshal(requests[0], &got); err != nil {
+		t.Fatalf("payload isn't an event: %v", err)
+	}
+	if got.Type != EventDailyLimit || got.Message != event.Message || got.Fields["sent"] != float64(20) {
+		t.Fatalf("payload = %+v", got)
+	}
+}
+
+func TestWebhookSlackPayload(t *testing.T) {
+	hook := newWebhook(t)
+	event := NewEvent(EventDailySummary, "Daily summary for 2026-03-10")
+	event.Fields = map[string]interface{}{"messages_sent": 3, "connections_sent": 12}
+
+	if err := newTestNotifier(hook.URL, "slack").Notify(event); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	var got map[string]string
+	if err := json.Unmarshal(hook.requests()[0], &got); err != nil {
+		t.Fatal(err)
+	}
+	want := "*daily_summary*: Daily summary for 2026-03-10\n• connections_sent: 12\n• messages_sent: 3"
+	if got["text"] != want {
+		t.Fatalf("text = %q, want %q", got["text"], want)
+	}
+}
+
+func TestWebhookEventFilter(t *testing.T) {
+	hook := newWebhook(t)
+	n := newTestNotifier(hook.URL, "json", EventRestriction)
+
+	if err := n.Notify(NewEvent(EventDailySummary, "summary")); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Notify(NewEvent(EventRestriction, "restricted")); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(hook.requests()); got != 1 {
+		t.Fatalf("posted %d events, want only the allowed one", got)
+	}
+}
+
+func TestWebhookRetries(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		statuses []int
+		posts    int
+		fails    bool
+	}{
+		{"server error then success", []int{500, 503}, 3, false},
+		{"server errors throughout", []int{500, 500, 500}, 3, true},
+		{"client error", []int{400}, 1, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			hook := newWebhook(t, tt.statuses...)
+
+			err := newTestNotifier(hook.URL, "json").Notify(NewEvent(EventSafeMode, "safe mode"))
+			if (err != nil) != tt.fails {
+				t.Fatalf("Notify = %v, want failure: %v", err, tt.fails)
+			}
+			if got := len(hook.requests()); got != tt.posts {
+				t.Fatalf("posted %d times, want %d", got, tt.posts)
+			}
+		})
+	}
+}
+
+func TestMultiJoinsErrors(t *testing.T) {
+	ok := newWebhook(t)
+	failing := newWebhook(t, 400)
+
+	err := Multi{Nop{}, newTestNotifier(failing.URL, "json"), newTestNotifier(ok.URL, "json")}.Notify(NewEvent(EventChallenge, "checkpoint"))
+	if err == nil {
+		t.Fatal("Multi hid the failed delivery")
+	}
+	if len(ok.requests()) != 1 {
+		t.Fatal("a failing notifier kept the event from the others")
+	}
+}
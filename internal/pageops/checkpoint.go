@@ -0,0 +1,95 @@
+package pageops
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+)
+
+// ErrCheckpoint is returned by a navigation that landed on a LinkedIn checkpoint nobody resolved
+var ErrCheckpoint = errors.New("LinkedIn security checkpoint not resolved")
+
+// DefaultCheckpointTimeout is how long a checkpoint is waited on before giving up
+const DefaultCheckpointTimeout = 10 * time.Minute
+
+// ErrAuthwall is returned when LinkedIn puts a page behind its sign-in wall, which means the
+// session was signed out rather than anything about the page
+var ErrAuthwall = errors.New("LinkedIn signed the session out (authwall)")
+
+// IsCheckpointURL reports whether url is LinkedIn's security checkpoint, where it can
+// redirect any page mid-session
+func IsCheckpointURL(url string) bool {
+	return strings.Contains(url, "/checkpoint/")
+}
+
+// IsAuthwallURL reports whether url is LinkedIn's sign-in wall, shown in place of a page
+// once the session is signed out. Signing in again clears it; waiting doesn't.
+func IsAuthwallURL(url string) bool {
+	return strings.Contains(url, "/authwall")
+}
+
+// CheckpointGuard handles a navigation that LinkedIn redirected to a checkpoint: it reports
+// it, waits for someone to resolve it in the browser and then retries the navigation once
+type CheckpointGuard struct {
+	notify  func(url string) // told once per checkpoint; may be nil
+	timeout time.Duration
+	poll    time.Duration
+	failed  error // a checkpoint that wasn't resolved; later ones aren't waited on again
+}
+
+// NewCheckpointGuard creates a guard that calls notify on each checkpoint and waits up
+// to timeout for it to be resolved
+func NewCheckpointGuard(notify func(url string), timeout time.Duration) *CheckpointGuard {
+	if timeout <= 0 {
+		timeout = DefaultCheckpointTimeout
+	}
+	return &CheckpointGuard{notify: notify, timeout: timeout, poll: 5 * time.Second}
+}
+
+// Resolve waits for the checkpoint the page is on to be cleared, then opens target again.
+// It returns ErrCheckpoint when the wait times out or the retry lands on a checkpoint too,
+// and from then on without waiting.
+func (g *CheckpointGuard) Resolve(page Navigator, target string) error {
+	if g.failed != nil {
+		return g.failed
+	}
+	if err := g.resolve(page, target); err != nil {
+		if errors.Is(err, ErrCheckpoint) {
+			g.failed = err
+		}
+		return err
+	}
+	return nil
+}
+
+// resolve waits for the checkpoint and retries the navigation
+func (g *CheckpointGuard) resolve(page Navigator, target string) error {
+	url := CurrentURL(page)
+	logger.Warnf("LinkedIn redirected %s to a checkpoint (%s); waiting up to %s for it to be resolved in the browser",
+		target, url, g.timeout)
+	if g.notify != nil {
+		g.notify(url)
+	}
+
+	for deadline := time.Now().Add(g.timeout); IsCheckpointURL(CurrentURL(page)); {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w after %s: %s", ErrCheckpoint, g.timeout, url)
+		}
+		time.Sleep(g.poll)
+	}
+	logger.Infof("Checkpoint resolved, opening %s again", target)
+
+	if err := page.Navigate(target); err != nil {
+		return err
+	}
+	if err := page.WaitLoad(); err != nil {
+		return err
+	}
+	if url := CurrentURL(page); IsCheckpointURL(url) {
+		return fmt.Errorf("%w: redirected again to %s", ErrCheckpoint, url)
+	}
+	return nil
+}
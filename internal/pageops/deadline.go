@@ -0,0 +1,67 @@
+package pageops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTimeout is returned by an action on a profile abandoned once its time budget ran out
+var ErrTimeout = errors.New("profile timed out")
+
+// Deadliner bounds the page operations that follow until release is called; pages may optionally implement it
+type Deadliner interface {
+	Deadline(timeout time.Duration) (release func())
+}
+
+// Canceler ties the page operations that follow to a context until release is called;
+// pages may optionally implement it
+type Canceler interface {
+	WithContext(ctx context.Context) (release func())
+}
+
+// DeadlinePauser stops the clock of a deadline while the bot waits on something outside
+// the page; pages may optionally implement it
+type DeadlinePauser interface {
+	PauseDeadline() (resume func())
+}
+
+// PauseDeadline stops the clock of page's deadline until resume is called. Pages that
+// can't pause it keep it running.
+func PauseDeadline(page Page) (resume func()) {
+	pauser, ok := page.(DeadlinePauser)
+	if !ok {
+		return func() {}
+	}
+	return pauser.PauseDeadline()
+}
+
+// WithDeadline bounds the operations on page to timeout until the returned func is
+// called. Pages that can't be bounded, and a timeout of 0, leave it unbounded.
+func WithDeadline(page Page, timeout time.Duration) (release func()) {
+	deadliner, ok := page.(Deadliner)
+	if !ok || timeout <= 0 {
+		return func() {}
+	}
+	return deadliner.Deadline(timeout)
+}
+
+// TimedOut marks err as ErrTimeout when it ended a profile's budget of timeout, keeping
+// the deadline error it wraps
+func TimedOut(err error, timeout time.Duration) error {
+	if err == nil || timeout <= 0 || !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrTimeout) {
+		return err
+	}
+	return fmt.Errorf("%w after %s: %w", ErrTimeout, timeout, err)
+}
+
+// WithContext fails the operations on page once ctx is done, until the returned func is
+// called. Pages that can't be tied to a context leave it to the caller to check ctx.
+func WithContext(page Page, ctx context.Context) (release func()) {
+	canceler, ok := page.(Canceler)
+	if !ok {
+		return func() {}
+	}
+	return canceler.WithContext(ctx)
+}
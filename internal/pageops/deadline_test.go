@@ -0,0 +1,71 @@
+package pageops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// boundedPage is a page that records the deadline it was given
+type boundedPage struct {
+	Page
+	timeout  time.Duration
+	released bool
+}
+
+func (p *boundedPage) Deadline(timeout time.Duration) func() {
+	p.timeout = timeout
+	return func() { p.released = true }
+}
+
+func TestWithDeadline(t *testing.T) {
+	page := &boundedPage{}
+	release := WithDeadline(page, time.Minute)
+	if page.timeout != time.Minute {
+		t.Fatalf("deadline = %s, want a minute", page.timeout)
+	}
+	release()
+	if !page.released {
+		t.Fatal("the deadline wasn't released")
+	}
+
+	unbounded := &boundedPage{}
+	WithDeadline(unbounded, 0)()
+	if unbounded.timeout != 0 || unbounded.released {
+		t.Fatalf("a zero timeout bounded the page: %+v", unbounded)
+	}
+}
+
+func TestTimedOut(t *testing.T) {
+	deadline := fmt.Errorf("failed to click: %w", context.DeadlineExceeded)
+	other := errors.New("element not found")
+
+	for _, tt := range []struct {
+		name     string
+		err      error
+		timeout  time.Duration
+		timedOut bool
+	}{
+		{"deadline passed", deadline, time.Minute, true},
+		{"other error", other, time.Minute, false},
+		{"no budget", deadline, 0, false},
+		{"no error", nil, time.Minute, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := TimedOut(tt.err, tt.timeout)
+			if errors.Is(err, ErrTimeout) != tt.timedOut {
+				t.Fatalf("TimedOut = %v, want a timeout: %v", err, tt.timedOut)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("TimedOut = %v, lost %v", err, tt.err)
+			}
+		})
+	}
+
+	once := TimedOut(deadline, time.Minute)
+	if twice := TimedOut(once, time.Minute); twice != once {
+		t.Fatalf("marked twice: %v", twice)
+	}
+}
@@ -0,0 +1,177 @@
+package pageops
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+)
+
+// ErrCovered is returned by a click whose element is hidden behind another one, such as a modal
+var ErrCovered = errors.New("element is covered by another element")
+
+// Interruption is a dialog, banner or pop-up LinkedIn shows unprompted that can cover
+// the elements the bot needs. Its text patterns may name labels as {Label}, which are
+// matched in the UI language.
+type Interruption struct {
+	Name      string
+	Container string // CSS of the interruption itself
+	Text      string // regular expression the container's text must match; empty for any
+	Close     string // CSS of the buttons inside the container that dismiss it
+	CloseText string // regular expression the close button's text must match; empty for any
+}
+
+// expandText turns the {Label} references in a text pattern into the label's texts
+var expandText = func(pattern string) string { return pattern }
+
+// SetTextExpander sets how {Label} references in interruption texts are expanded; the
+// selectors package, which keeps the labels, sets it
+func SetTextExpander(fn func(pattern string) string) {
+	expandText = fn
+}
+
+// interruptions are checked in order. The invite dialog, error toasts and the conversation
+// being written are left alone: the managers read and use those.
+var interruptions = []Interruption{
+	{
+		Name:      "premium_upsell",
+		Container: ".artdeco-modal:not(.send-invite):has([class*='upsell']), .modal-upsell",
+		Close:     "button[aria-label='Dismiss'], button.artdeco-modal__dismiss",
+	},
+	{
+		Name:      "phone_number_prompt",
+		Container: ".artdeco-modal:not(.send-invite)",
+		Text:      `(?i){PhonePrompt}`,
+		Close:     "button",
+		CloseText: `(?i)^\s*{NotNow}\s*$`,
+	},
+	{
+		Name:      "phone_number_prompt",
+		Container: ".artdeco-modal:not(.send-invite):has(input[type='tel'])",
+		Close:     "button[aria-label='Dismiss'], button.artdeco-modal__dismiss",
+	},
+	{
+		Name:      "cookie_banner",
+		Container: ".artdeco-global-alert[type='COOKIE_CONSENT'], [data-test-global-alert-type='COOKIE_CONSENT']",
+		Close:     "button",
+		CloseText: `(?i)^\s*{Accept}\s*$`,
+	},
+	{
+		Name:      "toast",
+		Container: ".artdeco-toast-item:not(.artdeco-toast-item--error):not([data-test-artdeco-toast-item-type='error'])",
+		Close:     "button.artdeco-toast-item__dismiss, button[aria-label*='Dismiss']",
+	},
+	{
+		Name:      "messaging_overlay",
+		Container: ".msg-overlay-conversation-bubble:not(.msg-overlay-conversation-bubble--is-minimized)",
+		Close:     "button[data-control-name='overlay.close_conversation_window'], button.msg-overlay-bubble-header__control[aria-label*='Close']",
+	},
+}
+
+// DismissInterruptions closes every known interruption on the page and returns the names
+// of those it dismissed. Call it before a click that an interruption would swallow.
+func DismissInterruptions(page Page, clicker Clicker) []string {
+	var dismissed []string
+	for _, interruption := range interruptions {
+		found, container := findInterruption(page, interruption)
+		if !found {
+			continue
+		}
+
+		button, err := closeButton(container, interruption)
+		if err != nil {
+			logger.Debugf("Found interruption %s but no way to close it: %v", interruption.Name, err)
+			continue
+		}
+		if err := clicker.Click(button); err != nil {
+			logger.Warnf("Failed to dismiss interruption %s: %v", interruption.Name, err)
+			continue
+		}
+
+		logger.Infof("Dismissed interruption: %s", interruption.Name)
+		dismissed = append(dismissed, interruption.Name)
+	}
+	return dismissed
+}
+
+// CollapseMessagingOverlay minimizes the conversation list docked in the bottom-right
+// corner, which covers the lower part of every page while open
+func CollapseMessagingOverlay(page Page, clicker Clicker) error {
+	has, header := page.Has(".msg-overlay-list-bubble:not(.msg-overlay-list-bubble--is-minimized) .msg-overlay-bubble-header")
+	if !has {
+		return nil
+	}
+	if err := clicker.Click(header); err != nil {
+		return fmt.Errorf("failed to collapse messaging overlay: %w", err)
+	}
+	logger.Infof("Collapsed the messaging overlay")
+	return nil
+}
+
+// findInterruption returns the container of interruption when it is on the page
+func findInterruption(page Page, interruption Interruption) (bool, Element) {
+	if interruption.Text != "" {
+		return page.HasR(interruption.Container, expandText(interruption.Text))
+	}
+	return page.Has(interruption.Container)
+}
+
+// closeButton returns the button that dismisses the interruption in container, without waiting
+func closeButton(container Element, interruption Interruption) (Element, error) {
+	buttons, err := container.Elements(interruption.Close)
+	if err != nil {
+		return nil, err
+	}
+	if interruption.CloseText == "" {
+		if len(buttons) == 0 {
+			return nil, fmt.Errorf("no %s", interruption.Close)
+		}
+		return buttons[0], nil
+	}
+
+	pattern, err := regexp.Compile(expandText(interruption.CloseText))
+	if err != nil {
+		return nil, err
+	}
+	for _, button := range buttons {
+		if text, err := button.Text(); err == nil && pattern.MatchString(text) {
+			return button, nil
+		}
+	}
+	return nil, fmt.Errorf("no %s matching /%s/", interruption.Close, interruption.CloseText)
+}
+
+// InterruptionGuard is a Clicker that, when an interruption covers the element to click,
+// dismisses it and tries the click once more
+type InterruptionGuard struct {
+	page    Page
+	clicker Clicker
+}
+
+// NewInterruptionGuard wraps clicker
+func NewInterruptionGuard(page Page, clicker Clicker) *InterruptionGuard {
+	return &InterruptionGuard{page: page, clicker: clicker}
+}
+
+// Click clicks el, retrying once after dismissing interruptions if it was covered
+func (g *InterruptionGuard) Click(el Element) error {
+	err := g.clicker.Click(el)
+	if !errors.Is(err, ErrCovered) {
+		return err
+	}
+
+	if len(DismissInterruptions(g.page, g.clicker)) == 0 {
+		return err
+	}
+	return g.clicker.Click(el)
+}
+
+// Hover hovers over el when the wrapped clicker can
+func (g *InterruptionGuard) Hover(el Element) error {
+	hoverer, ok := g.clicker.(Hoverer)
+	if !ok {
+		return errors.New("clicker can't hover")
+	}
+	return hoverer.Hover(el)
+}
@@ -0,0 +1,63 @@
+package pageops
+
+import "github.com/Tanukumar01/linkedin-automation/internal/profileurl"
+
+// memberIDScript finds the entity ID of the member whose profile is open. The page data
+// LinkedIn embeds in code blocks pairs each profile's URN with its public identifier,
+// which is matched against the URL; failing that, the top card's data attributes carry
+// the URN of the profile they act on.
+const memberIDScript = `() => {
+	const urn = /urn:li:(?:fsd_profile|fs_miniProfile|fs_profile):(ACoAA[A-Za-z0-9_-]+)/;
+	const decode = (s) => { try { return decodeURIComponent(s); } catch (e) { return s; } };
+	const slug = decode(location.pathname.split('/')[2] || '').toLowerCase();
+	if (/^acoaa/.test(slug)) {
+		return location.pathname.split('/')[2];
+	}
+
+	const find = (node) => {
+		if (!node || typeof node !== 'object') return '';
+		if (typeof node.publicIdentifier === 'string' && node.publicIdentifier.toLowerCase() === slug) {
+			const m = urn.exec(node.entityUrn || node.objectUrn || node['*profile'] || '');
+			if (m) return m[1];
+		}
+		for (const key in node) {
+			const id = find(node[key]);
+			if (id) return id;
+		}
+		return '';
+	};
+	for (const code of document.querySelectorAll('code')) {
+		if (!slug || !code.textContent.includes('"publicIdentifier"')) continue;
+		try {
+			const id = find(JSON.parse(code.textContent));
+			if (id) return id;
+		} catch (e) {}
+	}
+
+	const card = document.querySelector('main section');
+	if (card) {
+		for (const el of [card, ...card.querySelectorAll('*')]) {
+			for (const attr of el.attributes) {
+				if (!attr.name.startsWith('data-') && attr.name !== 'href') continue;
+				const m = urn.exec(decode(attr.value));
+				if (m) return m[1];
+			}
+		}
+	}
+	return '';
+}`
+
+// MemberID returns the entity ID of the member whose profile page is open, or "" when the
+// page doesn't reveal it. Unlike the profile URL it never changes, so it recognizes one
+// person reached through both a vanity URL and an ID URL.
+func MemberID(page Page) string {
+	evaluator, ok := page.(Evaluator)
+	if !ok {
+		return ""
+	}
+	id, err := evaluator.Eval(memberIDScript)
+	if err != nil || !profileurl.IsMemberID(id) {
+		return ""
+	}
+	return id
+}
@@ -0,0 +1,98 @@
+// Package pageops describes the browser interactions the automation needs,
+// so the managers don't depend on a concrete rod page.
+package pageops
+
+import (
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// Element is a node found on a page
+type Element interface {
+	Text() (string, error)
+	Property(name string) (string, error)
+	Element(selector string) (Element, error)
+	ElementR(selector, pattern string) (Element, error)
+	Elements(selector string) ([]Element, error)
+	ScrollIntoView() error
+}
+
+// Navigator moves the page between URLs
+type Navigator interface {
+	Navigate(url string) error
+	WaitLoad() error
+	URL() (string, error)
+}
+
+// ElementFinder looks up elements on the current page
+type ElementFinder interface {
+	Element(selector string) (Element, error)
+	ElementR(selector, pattern string) (Element, error)
+	Elements(selector string) ([]Element, error)
+	Has(selector string) (bool, Element)
+	HasR(selector, pattern string) (bool, Element)
+	WaitElements(selector string, timeout time.Duration) error
+}
+
+// Page is a browser tab that can be navigated and searched for elements
+type Page interface {
+	Navigator
+	ElementFinder
+}
+
+// Clicker clicks elements
+type Clicker interface {
+	Click(el Element) error
+}
+
+// Hoverer rests the mouse on elements; clickers may optionally implement it
+type Hoverer interface {
+	Hover(el Element) error
+}
+
+// TextTyper types text into elements
+type TextTyper interface {
+	TypeText(el Element, text string) error
+}
+
+// Scroller scrolls the current page
+type Scroller interface {
+	ScrollDown(distance int) error
+	ScrollToBottom() error
+}
+
+// Screenshotter captures the current page; pages may optionally implement it
+type Screenshotter interface {
+	Screenshot() ([]byte, error)
+}
+
+// DOMSnapshotter returns the current page's HTML; pages may optionally implement it
+type DOMSnapshotter interface {
+	HTML() (string, error)
+}
+
+// Evaluator runs JavaScript in the page and returns the result as a string; pages may optionally implement it
+type Evaluator interface {
+	Eval(js string, args ...interface{}) (string, error)
+}
+
+// Escaper presses Escape in the page, which closes most dialogs; pages may optionally implement it
+type Escaper interface {
+	PressEscape() error
+}
+
+// CookieJar reads and writes browser cookies; *rod.Page satisfies it
+type CookieJar interface {
+	Cookies(urls []string) ([]*proto.NetworkCookie, error)
+	SetCookies(cookies []*proto.NetworkCookieParam) error
+}
+
+// CurrentURL returns the page's URL, or "" when it can't be read
+func CurrentURL(page Navigator) string {
+	url, err := page.URL()
+	if err != nil {
+		return ""
+	}
+	return url
+}
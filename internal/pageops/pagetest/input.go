@@ -0,0 +1,101 @@
+package pagetest
+
+import (
+	"fmt"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+)
+
+// Clicker clicks fixture elements, running the page's OnClick handlers
+type Clicker struct {
+	// Clicked lists the clicked elements' texts, in order
+	Clicked []string
+	// Hovered lists the hovered elements' texts, in order
+	Hovered []string
+	// Err, when set, fails every click
+	Err error
+}
+
+// Click records the click and runs the handlers of the element's page
+func (c *Clicker) Click(el pageops.Element) error {
+	if c.Err != nil {
+		return c.Err
+	}
+	e, ok := el.(*Element)
+	if !ok {
+		return fmt.Errorf("pagetest: can't click a %T", el)
+	}
+	if e.page != nil {
+		if err := e.page.canceled(); err != nil {
+			return err
+		}
+	}
+	c.Clicked = append(c.Clicked, e.label())
+	if e.page != nil {
+		e.page.click(e)
+	}
+	return nil
+}
+
+// Hover records the hover
+func (c *Clicker) Hover(el pageops.Element) error {
+	if e, ok := el.(*Element); ok {
+		c.Hovered = append(c.Hovered, e.label())
+	}
+	return nil
+}
+
+// Typer types into fixture elements by appending to their value
+type Typer struct {
+	// Typed lists the texts typed, in order
+	Typed []string
+	// Err, when set, fails every call
+	Err error
+}
+
+// TypeText appends text to the element's value
+func (t *Typer) TypeText(el pageops.Element, text string) error {
+	if t.Err != nil {
+		return t.Err
+	}
+	e, ok := el.(*Element)
+	if !ok {
+		return fmt.Errorf("pagetest: can't type into a %T", el)
+	}
+	if e.page != nil {
+		if err := e.page.canceled(); err != nil {
+			return err
+		}
+	}
+	t.Typed = append(t.Typed, text)
+	e.Attrs["value"] += text
+	return nil
+}
+
+// Scroller counts scrolls
+type Scroller struct {
+	Scrolls int
+}
+
+// ScrollDown counts the scroll
+func (s *Scroller) ScrollDown(distance int) error {
+	s.Scrolls++
+	return nil
+}
+
+// ScrollToBottom counts the scroll
+func (s *Scroller) ScrollToBottom() error {
+	s.Scrolls++
+	return nil
+}
+
+// label names an element in Clicked and Hovered: its text, or its aria-label when it has none
+func (e *Element) label() string {
+	if text, _ := e.Text(); text != "" {
+		return text
+	}
+	if label := e.Attrs["aria-label"]; label != "" {
+		return label
+	}
+	return e.Tag
+}
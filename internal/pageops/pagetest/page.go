@@ -0,0 +1,530 @@
+// Package pagetest provides an in-memory pageops.Page for tests. Pages are built from
+// HTML fixtures and looked up with the same CSS selectors the bot uses on LinkedIn, and
+// clicking, typing or navigating can swap in the fixture the next step would show.
+package pagetest
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+)
+
+// ErrNotFound is returned when no element matches a selector
+var ErrNotFound = errors.New("element not found")
+
+// documentTag is the tag of the root every fixture is parsed under
+const documentTag = "#document"
+
+// Page is a browser tab showing HTML fixtures
+type Page struct {
+	mu    sync.Mutex
+	doc   *Element
+	html  string
+	url   string
+	sites map[string]string // the fixture Navigate shows for each URL
+	moved map[string]string // URLs Navigate is redirected from, to where
+
+	clicks  []clickHandler
+	escapes []func()
+
+	// Navigations lists the URLs Navigate opened, in order
+	Navigations []string
+	// NavigateErr, when set, fails every Navigate
+	NavigateErr error
+	// EvalFunc answers Eval; without it Eval fails
+	EvalFunc func(js string, args ...interface{}) (string, error)
+	// Escapes counts the Escape presses
+	Escapes int
+
+	deadline time.Duration
+	bounded  bool
+	paused   bool
+
+	ctx context.Context // fails the operations once done; nil for none
+}
+
+// clickHandler runs when an element matching sel is clicked
+type clickHandler struct {
+	sel selectorList
+	fn  func(el *Element)
+}
+
+// New returns a page at pageURL showing html
+func New(pageURL, html string) *Page {
+	p := &Page{sites: make(map[string]string), moved: make(map[string]string)}
+	p.url = pageURL
+	p.SetHTML(html)
+	return p
+}
+
+// SetHTML replaces what the page shows, keeping its URL. It panics on HTML that can't be
+// parsed, which is a broken fixture.
+func (p *Page) SetHTML(html string) {
+	doc, err := Parse(html)
+	if err != nil {
+		panic(fmt.Sprintf("pagetest: %v", err))
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.setDoc(doc, html)
+}
+
+// setDoc shows doc; callers hold mu
+func (p *Page) setDoc(doc *Element, html string) {
+	doc.walk(func(el *Element) { el.page = p })
+	p.doc, p.html = doc, html
+}
+
+// SetURL changes the URL the page reports without loading anything
+func (p *Page) SetURL(pageURL string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.url = pageURL
+}
+
+// Serve makes Navigate to pageURL show html
+func (p *Page) Serve(pageURL, html string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sites[pageURL] = html
+}
+
+// Redirect makes Navigate to from end up at to, as a server redirect would
+func (p *Page) Redirect(from, to string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.moved[from] = to
+}
+
+// OnClick runs fn whenever an element matching selector is clicked, typically to show
+// what the click opens
+func (p *Page) OnClick(selector string, fn func(el *Element)) {
+	sel, err := parseSelector(selector)
+	if err != nil {
+		panic(fmt.Sprintf("pagetest: %v", err))
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clicks = append(p.clicks, clickHandler{sel: sel, fn: fn})
+}
+
+// OnEscape runs fn whenever Escape is pressed
+func (p *Page) OnEscape(fn func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.escapes = append(p.escapes, fn)
+}
+
+// Navigate shows the fixture served for url, or an empty page
+func (p *Page) Navigate(pageURL string) error {
+	if err := p.canceled(); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	if p.NavigateErr != nil {
+		err := p.NavigateErr
+		p.mu.Unlock()
+		return err
+	}
+	p.Navigations = append(p.Navigations, pageURL)
+	if to, ok := p.moved[pageURL]; ok {
+		pageURL = to
+	}
+	html := p.sites[pageURL]
+	p.url = pageURL
+	p.mu.Unlock()
+
+	p.SetHTML(html)
+	return nil
+}
+
+// WaitLoad returns at once; fixtures are loaded when they are shown
+func (p *Page) WaitLoad() error {
+	return nil
+}
+
+// URL returns the page's URL
+func (p *Page) URL() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.url, nil
+}
+
+// Element returns the first element matching selector
+func (p *Page) Element(selector string) (pageops.Element, error) {
+	if err := p.canceled(); err != nil {
+		return nil, err
+	}
+	return first(p.root().Elements(selector))
+}
+
+// ElementR returns the first element matching selector whose text matches pattern
+func (p *Page) ElementR(selector, pattern string) (pageops.Element, error) {
+	if err := p.canceled(); err != nil {
+		return nil, err
+	}
+	return p.root().ElementR(selector, pattern)
+}
+
+// Elements returns every element matching selector, in document order
+func (p *Page) Elements(selector string) ([]pageops.Element, error) {
+	if err := p.canceled(); err != nil {
+		return nil, err
+	}
+	return p.root().Elements(selector)
+}
+
+// Has reports whether an element matches selector
+func (p *Page) Has(selector string) (bool, pageops.Element) {
+	el, err := p.Element(selector)
+	return err == nil, el
+}
+
+// HasR reports whether an element matches selector and pattern
+func (p *Page) HasR(selector, pattern string) (bool, pageops.Element) {
+	el, err := p.ElementR(selector, pattern)
+	return err == nil, el
+}
+
+// WaitElements fails at once when nothing matches; a fixture doesn't change by waiting
+func (p *Page) WaitElements(selector string, timeout time.Duration) error {
+	_, err := p.Element(selector)
+	return err
+}
+
+// Screenshot returns a placeholder image
+func (p *Page) Screenshot() ([]byte, error) {
+	return []byte("\x89PNG"), nil
+}
+
+// HTML returns the fixture the page shows
+func (p *Page) HTML() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.html, nil
+}
+
+// Eval answers through EvalFunc
+func (p *Page) Eval(js string, args ...interface{}) (string, error) {
+	if p.EvalFunc == nil {
+		return "", errors.New("pagetest: no EvalFunc set")
+	}
+	return p.EvalFunc(js, args...)
+}
+
+// PressEscape counts the press and runs the OnEscape handlers
+func (p *Page) PressEscape() error {
+	p.mu.Lock()
+	p.Escapes++
+	handlers := append([]func(){}, p.escapes...)
+	p.mu.Unlock()
+
+	for _, fn := range handlers {
+		fn()
+	}
+	return nil
+}
+
+// Deadline records that the page is bounded by timeout until release is called
+func (p *Page) Deadline(timeout time.Duration) (release func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.deadline, p.bounded = timeout, true
+	return func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.bounded = false
+	}
+}
+
+// WithContext fails the page's operations, and clicks and typing on its elements, once
+// ctx is done, until release is called
+func (p *Page) WithContext(ctx context.Context) (release func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ctx = ctx
+	return func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.ctx = nil
+	}
+}
+
+// canceled returns the error of the context the page is tied to, once it is done
+func (p *Page) canceled() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.ctx == nil {
+		return nil
+	}
+	return p.ctx.Err()
+}
+
+// PauseDeadline records that the deadline's clock is stopped until resume is called
+func (p *Page) PauseDeadline() (resume func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.bounded || p.paused {
+		return func() {}
+	}
+	p.paused = true
+	return func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.paused = false
+	}
+}
+
+// DeadlineState reports the timeout the page is bounded by, zero when it isn't, and
+// whether its clock is paused
+func (p *Page) DeadlineState() (timeout time.Duration, paused bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.bounded {
+		return 0, false
+	}
+	return p.deadline, p.paused
+}
+
+// root returns the document the page shows
+func (p *Page) root() *Element {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.doc
+}
+
+// click runs the click handlers that match el
+func (p *Page) click(el *Element) {
+	p.mu.Lock()
+	var run []func(*Element)
+	for _, h := range p.clicks {
+		if h.sel.matches(el) {
+			run = append(run, h.fn)
+		}
+	}
+	p.mu.Unlock()
+
+	for _, fn := range run {
+		fn(el)
+	}
+}
+
+// Element is a node of a fixture. Text is kept in nodes with an empty Tag.
+type Element struct {
+	Tag      string
+	Attrs    map[string]string
+	Data     string // a text node's text
+	Children []*Element
+
+	parent *Element
+	page   *Page
+}
+
+// Text returns the element's text with runs of whitespace collapsed, as innerText shows it
+func (e *Element) Text() (string, error) {
+	return strings.Join(strings.Fields(e.textContent()), " "), nil
+}
+
+// Property returns a DOM property: boolean attributes as "true" or "false", links
+// resolved against the page's URL, textContent, and other attributes as they are
+func (e *Element) Property(name string) (string, error) {
+	switch name {
+	case "textContent":
+		return e.textContent(), nil
+	case "innerText":
+		return e.Text()
+	case "disabled", "readOnly", "checked", "hidden", "required":
+		_, ok := e.Attrs[strings.ToLower(name)]
+		if !ok && name == "readOnly" {
+			_, ok = e.Attrs["readonly"]
+		}
+		return fmt.Sprint(ok), nil
+	case "className":
+		return e.Attrs["class"], nil
+	case "href", "src":
+		return e.resolve(e.Attrs[name]), nil
+	}
+	return e.Attrs[strings.ToLower(name)], nil
+}
+
+// Element returns the first descendant matching selector
+func (e *Element) Element(selector string) (pageops.Element, error) {
+	return first(e.Elements(selector))
+}
+
+// ElementR returns the first descendant matching selector whose text matches pattern
+func (e *Element) ElementR(selector, pattern string) (pageops.Element, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	els, err := e.Elements(selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, el := range els {
+		if text, _ := el.Text(); re.MatchString(text) {
+			return el, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s /%s/", ErrNotFound, selector, pattern)
+}
+
+// Elements returns every descendant matching selector, in document order
+func (e *Element) Elements(selector string) ([]pageops.Element, error) {
+	sel, err := parseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	var els []pageops.Element
+	for _, el := range e.find(sel, false) {
+		els = append(els, el)
+	}
+	return els, nil
+}
+
+// ScrollIntoView does nothing; fixtures have no layout
+func (e *Element) ScrollIntoView() error {
+	return nil
+}
+
+// Remove takes the element out of the page
+func (e *Element) Remove() {
+	if e.parent == nil {
+		return
+	}
+	siblings := e.parent.Children
+	for i, child := range siblings {
+		if child == e {
+			e.parent.Children = append(siblings[:i:i], siblings[i+1:]...)
+			break
+		}
+	}
+	e.parent = nil
+}
+
+// find returns the descendants matching sel; with first set it stops at the first one
+func (e *Element) find(sel selectorList, first bool) []*Element {
+	var found []*Element
+	var visit func(el *Element) bool
+	visit = func(el *Element) bool {
+		for _, child := range el.Children {
+			if sel.matches(child) {
+				found = append(found, child)
+				if first {
+					return false
+				}
+			}
+			if !visit(child) {
+				return false
+			}
+		}
+		return true
+	}
+	visit(e)
+	return found
+}
+
+// walk calls fn for the element and each descendant
+func (e *Element) walk(fn func(el *Element)) {
+	fn(e)
+	for _, child := range e.Children {
+		child.walk(fn)
+	}
+}
+
+// textContent returns the text of every text node under the element
+func (e *Element) textContent() string {
+	var b strings.Builder
+	e.walk(func(el *Element) {
+		if el.Tag == "" {
+			b.WriteString(el.Data)
+		}
+	})
+	return b.String()
+}
+
+// resolve makes a link absolute against the page's URL
+func (e *Element) resolve(link string) string {
+	if link == "" || e.page == nil {
+		return link
+	}
+	base, err := url.Parse(e.page.url)
+	if err != nil {
+		return link
+	}
+	ref, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	return base.ResolveReference(ref).String()
+}
+
+// first returns the first of els, or ErrNotFound
+func first(els []pageops.Element, err error) (pageops.Element, error) {
+	if err != nil {
+		return nil, err
+	}
+	if len(els) == 0 {
+		return nil, ErrNotFound
+	}
+	return els[0], nil
+}
+
+// Parse parses an HTML fixture. Fixtures are forgiving HTML: void elements, attributes
+// without values and HTML entities are fine, but <script> bodies aren't.
+func Parse(html string) (*Element, error) {
+	d := xml.NewDecoder(strings.NewReader(html))
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+
+	root := &Element{Tag: documentTag}
+	cur := root
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse fixture: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			el := &Element{Tag: strings.ToLower(t.Name.Local), Attrs: make(map[string]string), parent: cur}
+			for _, a := range t.Attr {
+				name := a.Name.Local
+				if a.Name.Space != "" {
+					name = a.Name.Space + ":" + name
+				}
+				el.Attrs[strings.ToLower(name)] = a.Value
+			}
+			cur.Children = append(cur.Children, el)
+			cur = el
+		case xml.EndElement:
+			// Close up to the matching element, tolerating ones left open
+			tag := strings.ToLower(t.Name.Local)
+			for el := cur; el != root; el = el.parent {
+				if el.Tag == tag {
+					cur = el.parent
+					break
+				}
+			}
+		case xml.CharData:
+			cur.Children = append(cur.Children, &Element{Data: string(t), parent: cur})
+		}
+	}
+	return root, nil
+}
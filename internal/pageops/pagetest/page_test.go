@@ -0,0 +1,112 @@
+package pagetest
+
+import (
+	"testing"
+)
+
+const fixture = `<html><body>
+<main id="profile">
+  <section class="artdeco-card"><div id="about"></div>
+    <button class="inline-show-more-text__button" aria-label="See more">see more</button>
+  </section>
+  <div class="pv-top-card">
+    <button class="artdeco-button artdeco-button--primary" aria-label="Invite Ada to connect">Connect</button>
+    <button class="artdeco-button" aria-label="More actions" disabled>More</button>
+    <a href="/in/ada-lovelace/" class="app-aware-link">Ada &amp; co</a>
+  </div>
+  <ul><li class="artdeco-list__item"><span aria-hidden="true">one</span></li><li class="artdeco-list__item">two</li></ul>
+  <input type="text" name="pin">
+</main>
+</body></html>`
+
+func TestSelectors(t *testing.T) {
+	page := New("https://www.linkedin.com/in/ada-lovelace/", fixture)
+
+	for _, tt := range []struct {
+		selector string
+		want     int
+	}{
+		{"button", 3},
+		{"#profile button", 3},
+		{"main > button", 0},
+		{".pv-top-card > button", 2},
+		{"button.artdeco-button.artdeco-button--primary", 1},
+		{"button[aria-label*='connect']", 1},
+		{"button[aria-label*='CONNECT' i]", 1},
+		{"button[aria-label^='Invite'][aria-label$='connect']", 1},
+		{"button[disabled]", 1},
+		{"button:not([disabled])", 2},
+		{"a[href*='/in/']", 1},
+		{"section:has(#about) button.inline-show-more-text__button", 1},
+		{"section:has(#missing) button", 0},
+		{"ul > li.artdeco-list__item", 2},
+		{"li span[aria-hidden='true'], input[name='pin']", 2},
+		{"div.pv-top-card button:not(.artdeco-button--primary):not([disabled])", 0},
+	} {
+		els, err := page.Elements(tt.selector)
+		if err != nil {
+			t.Errorf("Elements(%q): %v", tt.selector, err)
+			continue
+		}
+		if len(els) != tt.want {
+			t.Errorf("Elements(%q) = %d elements, want %d", tt.selector, len(els), tt.want)
+		}
+	}
+}
+
+func TestElementProperties(t *testing.T) {
+	page := New("https://www.linkedin.com/in/ada-lovelace/", fixture)
+
+	link, err := page.Element("a.app-aware-link")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if href, _ := link.Property("href"); href != "https://www.linkedin.com/in/ada-lovelace/" {
+		t.Errorf("href = %q", href)
+	}
+	if text, _ := link.Text(); text != "Ada & co" {
+		t.Errorf("text = %q", text)
+	}
+
+	more, _ := page.Element("button[aria-label='More actions']")
+	if disabled, _ := more.Property("disabled"); disabled != "true" {
+		t.Errorf("disabled = %q, want true", disabled)
+	}
+	if _, err := page.ElementR("button", "(?i)^connect$"); err != nil {
+		t.Errorf("ElementR: %v", err)
+	}
+	if ok, _ := page.HasR("button", "Follow"); ok {
+		t.Error("HasR found a Follow button")
+	}
+}
+
+func TestClickHandlersAndNavigation(t *testing.T) {
+	page := New("https://www.linkedin.com/in/ada-lovelace/", fixture)
+	page.OnClick("button[aria-label*='connect']", func(*Element) {
+		page.SetHTML(`<div role="dialog" class="send-invite"><button aria-label="Send now">Send</button></div>`)
+	})
+
+	connect, _ := page.Element("button[aria-label*='connect']")
+	clicker := &Clicker{}
+	if err := clicker.Click(connect); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := page.Has(".send-invite button"); !ok {
+		t.Fatal("the click handler didn't show the dialog")
+	}
+	if len(clicker.Clicked) != 1 || clicker.Clicked[0] != "Connect" {
+		t.Fatalf("Clicked = %v", clicker.Clicked)
+	}
+
+	page.Serve("https://www.linkedin.com/feed/", `<nav class="global-nav"></nav>`)
+	page.Redirect("https://www.linkedin.com/", "https://www.linkedin.com/feed/")
+	if err := page.Navigate("https://www.linkedin.com/"); err != nil {
+		t.Fatal(err)
+	}
+	if u, _ := page.URL(); u != "https://www.linkedin.com/feed/" {
+		t.Fatalf("URL = %q after the redirect", u)
+	}
+	if ok, _ := page.Has("nav.global-nav"); !ok {
+		t.Fatal("the served fixture isn't shown")
+	}
+}
@@ -0,0 +1,402 @@
+package pagetest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The selectors the bot uses are matched by a small CSS engine: type, #id, .class and
+// attribute selectors ([a], =, ~=, ^=, $=, *=, with an optional i flag), :not() and
+// :has(), joined by descendant and child combinators and grouped with commas.
+
+// selectorList is a comma-separated group; an element matches when any member does
+type selectorList []complexSelector
+
+// complexSelector is compound selectors joined by combinators, stored right to left
+type complexSelector struct {
+	parts       []compound
+	combinators []byte // combinators[i] joins parts[i] to parts[i+1]: ' ' or '>'
+}
+
+// compound is a run of simple selectors that all apply to one element
+type compound struct {
+	tag     string
+	id      string
+	classes []string
+	attrs   []attrSelector
+	not     []selectorList
+	has     []selectorList
+}
+
+// attrSelector is one [name op value] test
+type attrSelector struct {
+	name, op, value string
+	fold            bool
+}
+
+// parseSelector parses a selector group
+func parseSelector(s string) (selectorList, error) {
+	var list selectorList
+	for _, part := range splitTop(s, ',') {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("empty selector in %q", s)
+		}
+		c, err := parseComplex(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %w", s, err)
+		}
+		list = append(list, c)
+	}
+	return list, nil
+}
+
+// parseComplex parses compound selectors joined by combinators
+func parseComplex(s string) (complexSelector, error) {
+	var parts []compound
+	var combinators []byte
+
+	i := 0
+	for i < len(s) {
+		comb := byte(0)
+		for i < len(s) && (s[i] == ' ' || s[i] == '>') {
+			if s[i] == '>' {
+				comb = '>'
+			} else if comb == 0 {
+				comb = ' '
+			}
+			i++
+		}
+		if i == len(s) {
+			break
+		}
+		if len(parts) > 0 {
+			combinators = append(combinators, comb)
+		} else if comb == '>' {
+			return complexSelector{}, fmt.Errorf("leading combinator")
+		}
+
+		end := compoundEnd(s, i)
+		c, err := parseCompound(s[i:end])
+		if err != nil {
+			return complexSelector{}, err
+		}
+		parts = append(parts, c)
+		i = end
+	}
+	if len(parts) == 0 {
+		return complexSelector{}, fmt.Errorf("no selector")
+	}
+
+	// Stored right to left, the order matching walks them in
+	sel := complexSelector{}
+	for i := len(parts) - 1; i >= 0; i-- {
+		sel.parts = append(sel.parts, parts[i])
+		if i > 0 {
+			sel.combinators = append(sel.combinators, combinators[i-1])
+		}
+	}
+	return sel, nil
+}
+
+// compoundEnd returns where the compound selector starting at i ends
+func compoundEnd(s string, i int) int {
+	depth := 0
+	var quote byte
+	for ; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case depth == 0 && (c == ' ' || c == '>'):
+			return i
+		}
+	}
+	return i
+}
+
+// parseCompound parses one compound selector
+func parseCompound(s string) (compound, error) {
+	var c compound
+	i := 0
+	if n := identEnd(s, 0); n > 0 {
+		c.tag = strings.ToLower(s[:n])
+		i = n
+	} else if strings.HasPrefix(s, "*") {
+		i = 1
+	}
+
+	for i < len(s) {
+		switch s[i] {
+		case '#':
+			n := identEnd(s, i+1)
+			c.id = s[i+1 : n]
+			i = n
+		case '.':
+			n := identEnd(s, i+1)
+			c.classes = append(c.classes, s[i+1:n])
+			i = n
+		case '[':
+			end := closing(s, i, '[', ']')
+			if end < 0 {
+				return c, fmt.Errorf("unclosed attribute selector")
+			}
+			attr, err := parseAttr(s[i+1 : end])
+			if err != nil {
+				return c, err
+			}
+			c.attrs = append(c.attrs, attr)
+			i = end + 1
+		case ':':
+			n := identEnd(s, i+1)
+			name := s[i+1 : n]
+			if n >= len(s) || s[n] != '(' {
+				return c, fmt.Errorf("unsupported pseudo-class :%s", name)
+			}
+			end := closing(s, n, '(', ')')
+			if end < 0 {
+				return c, fmt.Errorf("unclosed :%s", name)
+			}
+			inner, err := parseSelector(s[n+1 : end])
+			if err != nil {
+				return c, err
+			}
+			switch name {
+			case "not":
+				c.not = append(c.not, inner)
+			case "has":
+				c.has = append(c.has, inner)
+			default:
+				return c, fmt.Errorf("unsupported pseudo-class :%s", name)
+			}
+			i = end + 1
+		default:
+			return c, fmt.Errorf("unexpected %q", s[i:])
+		}
+	}
+	return c, nil
+}
+
+// parseAttr parses the inside of an attribute selector
+func parseAttr(s string) (attrSelector, error) {
+	s = strings.TrimSpace(s)
+	n := identEnd(s, 0)
+	if n == 0 {
+		return attrSelector{}, fmt.Errorf("invalid attribute selector [%s]", s)
+	}
+	attr := attrSelector{name: strings.ToLower(s[:n])}
+	rest := strings.TrimSpace(s[n:])
+	if rest == "" {
+		return attr, nil
+	}
+
+	for _, op := range []string{"~=", "^=", "$=", "*=", "|=", "="} {
+		if strings.HasPrefix(rest, op) {
+			attr.op = op
+			rest = strings.TrimSpace(rest[len(op):])
+			break
+		}
+	}
+	if attr.op == "" {
+		return attrSelector{}, fmt.Errorf("invalid attribute selector [%s]", s)
+	}
+
+	if rest != "" && (rest[0] == '\'' || rest[0] == '"') {
+		end := strings.IndexByte(rest[1:], rest[0])
+		if end < 0 {
+			return attrSelector{}, fmt.Errorf("unclosed string in [%s]", s)
+		}
+		attr.value = rest[1 : end+1]
+		rest = strings.TrimSpace(rest[end+2:])
+	} else {
+		n := identEnd(rest, 0)
+		attr.value = rest[:n]
+		rest = strings.TrimSpace(rest[n:])
+	}
+
+	switch strings.ToLower(rest) {
+	case "":
+	case "i":
+		attr.fold = true
+	default:
+		return attrSelector{}, fmt.Errorf("invalid attribute selector [%s]", s)
+	}
+	return attr, nil
+}
+
+// identEnd returns where the identifier starting at i ends
+func identEnd(s string, i int) int {
+	for i < len(s) {
+		c := s[i]
+		if c == '-' || c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80 {
+			i++
+			continue
+		}
+		break
+	}
+	return i
+}
+
+// closing returns the index of the bracket closing the one at i, or -1
+func closing(s string, i int, open, close byte) int {
+	depth := 0
+	var quote byte
+	for ; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == open:
+			depth++
+		case c == close:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitTop splits s at sep outside brackets, parentheses and strings
+func splitTop(s string, sep byte) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// matches reports whether el matches any selector in the list
+func (l selectorList) matches(el *Element) bool {
+	for _, c := range l {
+		if c.matches(el) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether el matches the complex selector
+func (c complexSelector) matches(el *Element) bool {
+	return c.matchFrom(0, el)
+}
+
+// matchFrom matches parts[i:] with el as the subject of parts[i]
+func (c complexSelector) matchFrom(i int, el *Element) bool {
+	if !c.parts[i].matches(el) {
+		return false
+	}
+	if i == len(c.parts)-1 {
+		return true
+	}
+	if c.combinators[i] == '>' {
+		return el.parent != nil && c.matchFrom(i+1, el.parent)
+	}
+	for anc := el.parent; anc != nil; anc = anc.parent {
+		if c.matchFrom(i+1, anc) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether el satisfies every simple selector of the compound
+func (c compound) matches(el *Element) bool {
+	if el.Tag == "" || el.Tag == documentTag {
+		return false
+	}
+	if c.tag != "" && c.tag != el.Tag {
+		return false
+	}
+	if c.id != "" && el.Attrs["id"] != c.id {
+		return false
+	}
+	classes := strings.Fields(el.Attrs["class"])
+	for _, class := range c.classes {
+		if !contains(classes, class) {
+			return false
+		}
+	}
+	for _, a := range c.attrs {
+		if !a.matches(el) {
+			return false
+		}
+	}
+	for _, not := range c.not {
+		if not.matches(el) {
+			return false
+		}
+	}
+	for _, has := range c.has {
+		if len(el.find(has, false)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// matches reports whether el's attribute passes the test
+func (a attrSelector) matches(el *Element) bool {
+	value, ok := el.Attrs[a.name]
+	if !ok {
+		return false
+	}
+	want := a.value
+	if a.fold {
+		value, want = strings.ToLower(value), strings.ToLower(want)
+	}
+
+	switch a.op {
+	case "":
+		return true
+	case "=":
+		return value == want
+	case "~=":
+		return contains(strings.Fields(value), want)
+	case "^=":
+		return want != "" && strings.HasPrefix(value, want)
+	case "$=":
+		return want != "" && strings.HasSuffix(value, want)
+	case "*=":
+		return want != "" && strings.Contains(value, want)
+	case "|=":
+		return value == want || strings.HasPrefix(value, want+"-")
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
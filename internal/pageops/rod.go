@@ -0,0 +1,414 @@
+package pageops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
+	"github.com/go-rod/rod/lib/proto"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+)
+
+// ErrNavigationLimit is returned by Navigate once today's page loads are used up
+var ErrNavigationLimit = errors.New("daily navigation limit reached")
+
+// NavigationCounter persists page loads per day so restarts don't reset the limit
+type NavigationCounter interface {
+	GetNavigationCount(date string) (int, error)
+	AddNavigation(date string) error
+}
+
+// RodPage implements Page on top of a rod page
+type RodPage struct {
+	page           *rod.Page
+	beforeNavigate func()
+	onNavigate     func()
+
+	navigations     NavigationCounter // nil leaves navigation unlimited
+	navigationLimit int
+
+	checkpoints *CheckpointGuard // nil leaves checkpoint redirects to the caller
+	target      string           // the URL last navigated to
+	resolving   bool             // a checkpoint is being resolved, so navigations aren't guarded
+
+	unbounded *rod.Page // the page without its deadline while Deadline has set one
+	deadline  time.Time // when the deadline Deadline set runs out
+}
+
+// NewRodPage wraps a rod page
+func NewRodPage(page *rod.Page) *RodPage {
+	return &RodPage{page: page}
+}
+
+// Rod returns the underlying rod page
+func (p *RodPage) Rod() *rod.Page {
+	return p.page
+}
+
+// BeforeNavigate registers fn to run before every Navigate
+func (p *RodPage) BeforeNavigate(fn func()) {
+	p.beforeNavigate = fn
+}
+
+// OnNavigate registers fn to run after every successful Navigate
+func (p *RodPage) OnNavigate(fn func()) {
+	p.onNavigate = fn
+}
+
+// SetNavigationLimit caps how many pages Navigate opens per day
+func (p *RodPage) SetNavigationLimit(counter NavigationCounter, limit int) {
+	p.navigations = counter
+	p.navigationLimit = limit
+}
+
+// SetCheckpointGuard has every navigation LinkedIn redirects to a checkpoint wait for it
+// to be resolved and then retried once
+func (p *RodPage) SetCheckpointGuard(guard *CheckpointGuard) {
+	p.checkpoints = guard
+}
+
+// Deadline fails every page operation once timeout has passed, until release is called.
+// rod bounds a derived page, which stands in for the page until then.
+func (p *RodPage) Deadline(timeout time.Duration) (release func()) {
+	if p.unbounded != nil {
+		return func() {}
+	}
+
+	p.unbounded = p.page
+	p.page = p.page.Timeout(timeout)
+	p.deadline = time.Now().Add(timeout)
+	return func() {
+		p.page.CancelTimeout()
+		p.page, p.unbounded = p.unbounded, nil
+	}
+}
+
+// WithContext fails every page operation once ctx is done, until release is called. A
+// deadline set meanwhile is derived from it, so both apply; while one is set it does nothing.
+func (p *RodPage) WithContext(ctx context.Context) (release func()) {
+	if p.unbounded != nil {
+		return func() {}
+	}
+
+	page := p.page
+	p.page = page.Context(ctx)
+	return func() { p.page = page }
+}
+
+// PauseDeadline stops the deadline's clock until resume is called, which bounds the page
+// again by the time that was left
+func (p *RodPage) PauseDeadline() (resume func()) {
+	if p.unbounded == nil || p.page == p.unbounded {
+		return func() {}
+	}
+
+	left := time.Until(p.deadline)
+	p.page.CancelTimeout()
+	p.page = p.unbounded
+	return func() {
+		p.deadline = time.Now().Add(left)
+		p.page = p.unbounded.Timeout(left)
+	}
+}
+
+// base returns the page without any deadline, for what must not be cut short
+func (p *RodPage) base() *rod.Page {
+	if p.unbounded != nil {
+		return p.unbounded
+	}
+	return p.page
+}
+
+// Navigate opens url in the page
+func (p *RodPage) Navigate(url string) error {
+	today := time.Now().Format("2006-01-02")
+	if p.navigations != nil {
+		count, err := p.navigations.GetNavigationCount(today)
+		if err != nil {
+			return fmt.Errorf("failed to check navigation count: %w", err)
+		}
+		if count >= p.navigationLimit {
+			return fmt.Errorf("%w (%d/%d)", ErrNavigationLimit, count, p.navigationLimit)
+		}
+	}
+
+	if p.beforeNavigate != nil {
+		p.beforeNavigate()
+	}
+	if err := p.page.Navigate(url); err != nil {
+		return err
+	}
+
+	if p.navigations != nil {
+		if err := p.navigations.AddNavigation(today); err != nil {
+			return fmt.Errorf("failed to count navigation: %w", err)
+		}
+	}
+	if p.onNavigate != nil {
+		p.onNavigate()
+	}
+	p.target = url
+	return p.guardCheckpoint()
+}
+
+// WaitLoad waits for the page's load event
+func (p *RodPage) WaitLoad() error {
+	if err := p.page.WaitLoad(); err != nil {
+		return err
+	}
+	return p.guardCheckpoint()
+}
+
+// guardCheckpoint resolves the checkpoint the page was redirected to, if any
+func (p *RodPage) guardCheckpoint() error {
+	if p.checkpoints == nil || p.resolving || !IsCheckpointURL(CurrentURL(p)) {
+		return nil
+	}
+
+	// Waiting on a person to clear the checkpoint isn't held to the deadline
+	bounded := p.page
+	p.page = p.base()
+	p.resolving = true
+	defer func() {
+		p.page = bounded
+		p.resolving = false
+	}()
+	return p.checkpoints.Resolve(p, p.target)
+}
+
+// URL returns the current page URL, deadline or not, so failures can say where they happened
+func (p *RodPage) URL() (string, error) {
+	info, err := p.base().Info()
+	if err != nil {
+		return "", err
+	}
+	return info.URL, nil
+}
+
+// Element returns the first element matching selector
+func (p *RodPage) Element(selector string) (Element, error) {
+	return wrap(p.page.Element(selector))
+}
+
+// ElementR returns the first element matching selector whose text matches pattern
+func (p *RodPage) ElementR(selector, pattern string) (Element, error) {
+	return wrap(p.page.ElementR(selector, pattern))
+}
+
+// Elements returns all elements matching selector
+func (p *RodPage) Elements(selector string) ([]Element, error) {
+	return wrapAll(p.page.Elements(selector))
+}
+
+// Has reports whether an element matching selector exists, without waiting
+func (p *RodPage) Has(selector string) (bool, Element) {
+	has, el, err := p.page.Has(selector)
+	if err != nil || !has {
+		return false, nil
+	}
+	return true, &RodElement{el: el}
+}
+
+// HasR reports whether an element matching selector and pattern exists, without waiting
+func (p *RodPage) HasR(selector, pattern string) (bool, Element) {
+	has, el, err := p.page.HasR(selector, pattern)
+	if err != nil || !has {
+		return false, nil
+	}
+	return true, &RodElement{el: el}
+}
+
+// WaitElements waits up to timeout for at least one element matching selector
+func (p *RodPage) WaitElements(selector string, timeout time.Duration) error {
+	return p.page.Timeout(timeout).WaitElementsMoreThan(selector, 0)
+}
+
+// Screenshot captures the full page, even once a deadline has passed, so a timed out
+// action can still be captured
+func (p *RodPage) Screenshot() ([]byte, error) {
+	return p.base().Screenshot(true, nil)
+}
+
+// HTML returns the outer HTML of the whole document, deadline or not
+func (p *RodPage) HTML() (string, error) {
+	return p.base().HTML()
+}
+
+// Eval runs a JavaScript function in the page with args and returns its result as a string
+func (p *RodPage) Eval(js string, args ...interface{}) (string, error) {
+	res, err := p.page.Eval(js, args...)
+	if err != nil {
+		return "", err
+	}
+	return res.Value.String(), nil
+}
+
+// PressEscape presses and releases Escape, deadline or not, so a dialog left open by a
+// timed out action can still be closed
+func (p *RodPage) PressEscape() error {
+	return p.base().Keyboard.Type(input.Escape)
+}
+
+// Cookies returns the browser cookies for urls
+func (p *RodPage) Cookies(urls []string) ([]*proto.NetworkCookie, error) {
+	return p.page.Cookies(urls)
+}
+
+// SetCookies sets browser cookies
+func (p *RodPage) SetCookies(cookies []*proto.NetworkCookieParam) error {
+	return p.page.SetCookies(cookies)
+}
+
+// RodElement implements Element on top of a rod element
+type RodElement struct {
+	el *rod.Element
+}
+
+// Rod returns the underlying rod element
+func (e *RodElement) Rod() *rod.Element {
+	return e.el
+}
+
+// Text returns the element's visible text
+func (e *RodElement) Text() (string, error) {
+	return e.el.Text()
+}
+
+// Property returns a DOM property as a string
+func (e *RodElement) Property(name string) (string, error) {
+	value, err := e.el.Property(name)
+	if err != nil {
+		return "", err
+	}
+	return value.String(), nil
+}
+
+// Element returns the first descendant matching selector
+func (e *RodElement) Element(selector string) (Element, error) {
+	return wrap(e.el.Element(selector))
+}
+
+// ElementR returns the first descendant matching selector whose text matches pattern
+func (e *RodElement) ElementR(selector, pattern string) (Element, error) {
+	return wrap(e.el.ElementR(selector, pattern))
+}
+
+// Elements returns all descendants matching selector
+func (e *RodElement) Elements(selector string) ([]Element, error) {
+	return wrapAll(e.el.Elements(selector))
+}
+
+// ScrollIntoView scrolls the element into the viewport
+func (e *RodElement) ScrollIntoView() error {
+	return e.el.ScrollIntoView()
+}
+
+// RodClicker clicks elements with human-like mouse movement
+type RodClicker struct {
+	mouse *stealth.MouseMover
+}
+
+// NewRodClicker creates a clicker backed by the stealth mouse mover
+func NewRodClicker(mouse *stealth.MouseMover) *RodClicker {
+	return &RodClicker{mouse: mouse}
+}
+
+// Click moves to the element and clicks it. It returns ErrCovered instead when another
+// element sits on top of it, since the click would land on that one.
+func (c *RodClicker) Click(el Element) error {
+	rodEl, err := unwrap(el)
+	if err != nil {
+		return err
+	}
+
+	var covered *rod.CoveredError
+	if err := rodEl.ScrollIntoView(); err == nil {
+		if _, err := rodEl.Interactable(); errors.As(err, &covered) {
+			return fmt.Errorf("%w: %v", ErrCovered, err)
+		}
+	}
+	return c.mouse.ClickElement(rodEl)
+}
+
+// Hover moves to the element and rests on it for a moment
+func (c *RodClicker) Hover(el Element) error {
+	rodEl, err := unwrap(el)
+	if err != nil {
+		return err
+	}
+	return c.mouse.HoverElement(rodEl)
+}
+
+// RodTyper types text with human-like timing
+type RodTyper struct {
+	page  *rod.Page
+	typer *stealth.Typer
+}
+
+// NewRodTyper creates a text typer backed by the stealth typer
+func NewRodTyper(page *rod.Page, typer *stealth.Typer) *RodTyper {
+	return &RodTyper{page: page, typer: typer}
+}
+
+// TypeText focuses the element and types text into it
+func (t *RodTyper) TypeText(el Element, text string) error {
+	rodEl, err := unwrap(el)
+	if err != nil {
+		return err
+	}
+	return t.typer.TypeText(t.page, rodEl, text)
+}
+
+// RodScroller scrolls with human-like motion
+type RodScroller struct {
+	page     *rod.Page
+	scroller *stealth.Scroller
+}
+
+// NewRodScroller creates a scroller backed by the stealth scroller
+func NewRodScroller(page *rod.Page, scroller *stealth.Scroller) *RodScroller {
+	return &RodScroller{page: page, scroller: scroller}
+}
+
+// ScrollDown scrolls down by roughly distance pixels
+func (s *RodScroller) ScrollDown(distance int) error {
+	return s.scroller.ScrollDown(s.page, distance)
+}
+
+// ScrollToBottom scrolls to the end of the page
+func (s *RodScroller) ScrollToBottom() error {
+	return s.scroller.ScrollToBottom(s.page)
+}
+
+// wrap converts a rod lookup result into an Element
+func wrap(el *rod.Element, err error) (Element, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &RodElement{el: el}, nil
+}
+
+// wrapAll converts a rod multi-element lookup result into Elements
+func wrapAll(els rod.Elements, err error) ([]Element, error) {
+	if err != nil {
+		return nil, err
+	}
+	elements := make([]Element, len(els))
+	for i, el := range els {
+		elements[i] = &RodElement{el: el}
+	}
+	return elements, nil
+}
+
+// unwrap returns the rod element behind an Element
+func unwrap(el Element) (*rod.Element, error) {
+	rodEl, ok := el.(*RodElement)
+	if !ok {
+		return nil, fmt.Errorf("element %T is not backed by rod", el)
+	}
+	return rodEl.el, nil
+}
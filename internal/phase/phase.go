@@ -0,0 +1,135 @@
+// Package phase keeps each phase of a run within its share of the session.
+// Phases check their budget at cooperative checkpoints (between pages or
+// profiles) and wrap up once it is used, so one runaway phase can't starve the rest.
+package phase
+
+import (
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+)
+
+// Budget tracks how long a phase may run. Time accumulates across Start/Stop
+// pairs, so a phase that runs once per campaign shares a single budget.
+type Budget struct {
+	name    string
+	allowed time.Duration
+	used    time.Duration
+	started time.Time // zero while the phase isn't running
+	overrun bool
+	first   time.Time // first start
+	last    time.Time // latest stop
+}
+
+// Start resumes the phase clock
+func (b *Budget) Start() {
+	if b.started.IsZero() {
+		b.started = time.Now()
+		if b.first.IsZero() {
+			b.first = b.started
+		}
+	}
+}
+
+// Stop pauses the phase clock
+func (b *Budget) Stop() {
+	if !b.started.IsZero() {
+		b.last = time.Now()
+		b.used += b.last.Sub(b.started)
+		b.started = time.Time{}
+	}
+}
+
+// Elapsed returns how long the phase has run so far
+func (b *Budget) Elapsed() time.Duration {
+	if b.started.IsZero() {
+		return b.used
+	}
+	return b.used + time.Since(b.started)
+}
+
+// Exceeded is the phase's checkpoint: it reports whether the phase should finish
+// its in-flight item and return. A zero or negative allowance never runs out.
+func (b *Budget) Exceeded() bool {
+	if b.allowed <= 0 || b.Elapsed() < b.allowed {
+		return false
+	}
+
+	if !b.overrun {
+		b.overrun = true
+		logger.Warnf("Phase %s used its %s budget, wrapping up", b.name, b.allowed)
+	}
+	return true
+}
+
+// Span returns when the phase first started and last stopped; both are zero if it never ran
+func (b *Budget) Span() (time.Time, time.Time) {
+	if !b.started.IsZero() {
+		return b.first, time.Now()
+	}
+	return b.first, b.last
+}
+
+// Name returns the phase name
+func (b *Budget) Name() string {
+	return b.name
+}
+
+// Allowed returns the phase's time budget
+func (b *Budget) Allowed() time.Duration {
+	return b.allowed
+}
+
+// Overrun reports whether the phase ran out of budget
+func (b *Budget) Overrun() bool {
+	return b.overrun || (b.allowed > 0 && b.Elapsed() > b.allowed)
+}
+
+// Tracker holds the budgets of a run's phases
+type Tracker struct {
+	budgets []*Budget
+}
+
+// NewTracker creates an empty tracker
+func NewTracker() *Tracker {
+	return &Tracker{}
+}
+
+// Add registers a phase with its time budget and returns it
+func (t *Tracker) Add(name string, allowed time.Duration) *Budget {
+	b := &Budget{name: name, allowed: allowed}
+	t.budgets = append(t.budgets, b)
+	return b
+}
+
+// Get returns the budget of a phase, or an unlimited one if the phase wasn't added
+func (t *Tracker) Get(name string) *Budget {
+	for _, b := range t.budgets {
+		if b.name == name {
+			return b
+		}
+	}
+	return t.Add(name, 0)
+}
+
+// Hold stops the clocks of the running phases until resume is called, e.g. while the run
+// is paused
+func (t *Tracker) Hold() (resume func()) {
+	var held []*Budget
+	for _, b := range t.budgets {
+		if !b.started.IsZero() {
+			b.Stop()
+			held = append(held, b)
+		}
+	}
+	return func() {
+		for _, b := range held {
+			b.Start()
+		}
+	}
+}
+
+// Budgets returns every phase budget in the order they were added
+func (t *Tracker) Budgets() []*Budget {
+	return t.budgets
+}
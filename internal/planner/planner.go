@@ -0,0 +1,148 @@
+// Package planner decides how the day's outreach budget is split between
+// sending new invites and following up with people who already accepted.
+package planner
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// Decision describes the connect budget chosen for today and why
+type Decision struct {
+	Backlog       int    `json:"backlog"`
+	ConnectBudget int    `json:"connect_budget"`
+	FullBudget    int    `json:"full_budget"`
+	Reduced       bool   `json:"reduced"`
+	Reason        string `json:"reason"`
+}
+
+// Planner applies the backlog rule to the daily connection limit
+type Planner struct {
+	config *config.Config
+	db     *storage.DB
+}
+
+// NewPlanner creates a new planner
+func NewPlanner(cfg *config.Config, db *storage.DB) *Planner {
+	return &Planner{
+		config: cfg,
+		db:     db,
+	}
+}
+
+// Plan decides today's connect budget and records the decision
+func (p *Planner) Plan(now time.Time) (*Decision, error) {
+	decision, err := p.Decide(now)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Record(decision, now)
+	return decision, nil
+}
+
+// Decide works out today's connect budget without recording anything
+func (p *Planner) Decide(now time.Time) (*Decision, error) {
+	rule := p.config.Planner.Backlog
+	limit := p.config.DailyConnectLimit(now)
+	decision := &Decision{
+		ConnectBudget: limit,
+		FullBudget:    limit,
+		Reason:        "backlog rule disabled",
+	}
+
+	if rule.Enabled {
+		backlog, err := p.db.GetUnmessagedAcceptedCount()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get message backlog: %w", err)
+		}
+		decision.Backlog = backlog
+
+		if err := p.applyBacklogRule(decision, now); err != nil {
+			return nil, err
+		}
+	}
+
+	return decision, nil
+}
+
+// Record logs a decision and stores it as today's
+func (p *Planner) Record(decision *Decision, now time.Time) {
+	logger.Infof("Planner: connect budget %d/%d, backlog %d (%s)",
+		decision.ConnectBudget, decision.FullBudget, decision.Backlog, decision.Reason)
+
+	record := &storage.PlannerDecision{
+		Date:          now.Format("2006-01-02"),
+		Backlog:       decision.Backlog,
+		ConnectBudget: decision.ConnectBudget,
+		Reduced:       decision.Reduced,
+		Reason:        decision.Reason,
+		DecidedAt:     now,
+	}
+
+	if err := p.db.SavePlannerDecision(record); err != nil {
+		logger.Errorf("Failed to save planner decision: %v", err)
+	}
+}
+
+// applyBacklogRule shrinks the connect budget while the backlog is above the threshold,
+// unless the budget has already been reduced for too many runs in a row
+func (p *Planner) applyBacklogRule(decision *Decision, now time.Time) error {
+	rule := p.config.Planner.Backlog
+
+	if decision.Backlog <= rule.Threshold {
+		decision.Reason = fmt.Sprintf("backlog %d within threshold %d", decision.Backlog, rule.Threshold)
+		return nil
+	}
+
+	streak, err := p.reducedStreak(now)
+	if err != nil {
+		return err
+	}
+
+	if streak >= rule.MaxConsecutiveDays {
+		decision.Reason = fmt.Sprintf("backlog %d above threshold %d, but budget was already reduced %d days in a row",
+			decision.Backlog, rule.Threshold, streak)
+		return nil
+	}
+
+	decision.Reduced = true
+	if rule.Policy == "pause" {
+		decision.ConnectBudget = 0
+		decision.Reason = fmt.Sprintf("backlog %d above threshold %d, pausing invites to message accepted connections",
+			decision.Backlog, rule.Threshold)
+		return nil
+	}
+
+	decision.ConnectBudget = int(float64(decision.FullBudget) * rule.ReduceFactor)
+	decision.Reason = fmt.Sprintf("backlog %d above threshold %d, reducing invites to %.0f%%",
+		decision.Backlog, rule.Threshold, rule.ReduceFactor*100)
+	return nil
+}
+
+// reducedStreak counts how many of the most recent earlier run days had a reduced budget
+func (p *Planner) reducedStreak(now time.Time) (int, error) {
+	limit := p.config.Planner.Backlog.MaxConsecutiveDays
+	if limit <= 0 {
+		return 0, nil
+	}
+
+	decisions, err := p.db.GetPlannerDecisionsBefore(now.Format("2006-01-02"), limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get planner history: %w", err)
+	}
+
+	streak := 0
+	for _, d := range decisions {
+		if !d.Reduced {
+			break
+		}
+		streak++
+	}
+
+	return streak, nil
+}
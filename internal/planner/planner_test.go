@@ -0,0 +1,144 @@
+package planner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger("error", "console", "stdout", logger.Rotation{})
+	os.Exit(m.Run())
+}
+
+// monday is a weekday run, so the plain daily limit applies
+var monday = time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+
+// newPlanner returns a planner with a daily limit of 20 under the backlog rule, over a
+// database holding backlog accepted connections that haven't been messaged
+func newPlanner(t *testing.T, policy string, backlog int) *Planner {
+	t.Helper()
+
+	db, err := storage.NewDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	for i := 0; i < backlog; i++ {
+		req := &storage.ConnectionRequest{ProfileURL: fmt.Sprintf("https://www.linkedin.com/in/accepted-%d/", i), Status: "accepted", SentAt: monday.AddDate(0, 0, -7)}
+		if err := db.SaveConnectionRequest(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{}
+	cfg.Connections.DailyLimit = 20
+	cfg.Stealth.Scheduling.Timezone = "UTC"
+	cfg.Planner.Backlog = config.BacklogConfig{Enabled: true, Threshold: 5, Policy: policy, ReduceFactor: 0.5, MaxConsecutiveDays: 2}
+	return NewPlanner(cfg, db)
+}
+
+func TestDecideBacklogRule(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		policy  string
+		backlog int
+		budget  int
+		reduced bool
+	}{
+		{"no backlog", "reduce", 0, 20, false},
+		{"at the threshold", "reduce", 5, 20, false},
+		{"above the threshold, reduce", "reduce", 6, 10, true},
+		{"above the threshold, pause", "pause", 6, 0, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, err := newPlanner(t, tt.policy, tt.backlog).Decide(monday)
+			if err != nil {
+				t.Fatalf("Decide: %v", err)
+			}
+			if decision.Backlog != tt.backlog || decision.ConnectBudget != tt.budget || decision.FullBudget != 20 || decision.Reduced != tt.reduced {
+				t.Fatalf("decision = %+v, want backlog %d, budget %d/20, reduced %v", decision, tt.backlog, tt.budget, tt.reduced)
+			}
+		})
+	}
+}
+
+func TestDecideBacklogRuleDisabled(t *testing.T) {
+	p := newPlanner(t, "pause", 50)
+	p.config.Planner.Backlog.Enabled = false
+
+	decision, err := p.Decide(monday)
+	if err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if decision.ConnectBudget != 20 || decision.Reduced || decision.Backlog != 0 {
+		t.Fatalf("decision = %+v, want the full budget without looking at the backlog", decision)
+	}
+}
+
+func TestPlanDoesNotStarveConnections(t *testing.T) {
+	p := newPlanner(t, "pause", 6)
+
+	// With max_consecutive_days 2 the backlog never clears here, so every third day is a full one
+	want := []int{0, 0, 20, 0, 0, 20, 0}
+	for i, budget := range want {
+		day := monday.AddDate(0, 0, i)
+		decision, err := p.Plan(day)
+		if err != nil {
+			t.Fatalf("Plan: %v", err)
+		}
+		if decision.ConnectBudget != budget || decision.Reduced != (budget == 0) {
+			t.Fatalf("day %d: decision = %+v, want a budget of %d", i+1, decision, budget)
+		}
+	}
+}
+
+func TestPlanStreakSkipsDaysWithoutARun(t *testing.T) {
+	p := newPlanner(t, "pause", 6)
+
+	// Two reduced runs a week apart still make a streak: only run days count
+	for _, day := range []time.Time{monday, monday.AddDate(0, 0, 7)} {
+		if decision, err := p.Plan(day); err != nil || !decision.Reduced {
+			t.Fatalf("Plan(%s) = %+v, %v, want reduced", day.Format(time.DateOnly), decision, err)
+		}
+	}
+	decision, err := p.Plan(monday.AddDate(0, 0, 14))
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if decision.Reduced || decision.ConnectBudget != 20 {
+		t.Fatalf("decision = %+v, want the full budget after two reduced runs", decision)
+	}
+}
+
+func TestRecordReplacesTheDaysDecision(t *testing.T) {
+	p := newPlanner(t, "reduce", 0)
+
+	p.Record(&Decision{Backlog: 9, ConnectBudget: 10, FullBudget: 20, Reduced: true, Reason: "first run"}, monday)
+	p.Record(&Decision{Backlog: 2, ConnectBudget: 20, FullBudget: 20, Reason: "second run"}, monday.Add(3*time.Hour))
+
+	decisions, err := p.db.GetPlannerDecisionsBefore(monday.AddDate(0, 0, 1).Format("2006-01-02"), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decisions) != 1 {
+		t.Fatalf("decisions = %+v, want one for the day", decisions)
+	}
+	if d := decisions[0]; d.Reason != "second run" || d.Reduced || d.ConnectBudget != 20 || d.Backlog != 2 {
+		t.Fatalf("decision = %+v, want the second run's", d)
+	}
+
+	// A day rerun after a reduced decision no longer counts it towards the streak
+	p.config.Planner.Backlog.Policy = "pause"
+	streak, err := p.reducedStreak(monday.AddDate(0, 0, 1))
+	if err != nil || streak != 0 {
+		t.Fatalf("reducedStreak = %d, %v, want 0", streak, err)
+	}
+}
@@ -0,0 +1,250 @@
+// Package profiles enriches prospects with the details of their profile page:
+// headline, about text, experience, education and follower count. Scraped
+// profiles are stored and only visited again once they go stale.
+package profiles
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/render"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// ErrDailyLimit is returned once today's profile scrapes are used up
+var ErrDailyLimit = errors.New("daily enrichment limit reached")
+
+// Scraper reads profile pages into stored profiles
+type Scraper struct {
+	cfg      config.EnrichConfig
+	page     pageops.Page
+	scroller pageops.Scroller
+	clicker  pageops.Clicker
+	timing   *stealth.TimingController
+	db       *storage.DB
+}
+
+// NewScraper creates a profile scraper
+func NewScraper(cfg config.EnrichConfig, page pageops.Page, scroller pageops.Scroller, clicker pageops.Clicker, timing *stealth.TimingController, db *storage.DB) *Scraper {
+	return &Scraper{
+		cfg:      cfg,
+		page:     page,
+		scroller: scroller,
+		clicker:  clicker,
+		timing:   timing,
+		db:       db,
+	}
+}
+
+// Scrape returns the details of a profile. A profile scraped within enrich.stale_days is
+// returned from the database without visiting it; otherwise the page is read, the About
+// section expanded and the Experience and Education lists walked, and the result saved.
+func (s *Scraper) Scrape(profileURL string) (*storage.Profile, error) {
+	stored, err := s.db.GetProfile(profileURL)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil && time.Since(stored.ScrapedAt) < time.Duration(s.cfg.StaleDays)*24*time.Hour {
+		return stored, nil
+	}
+
+	scraped, err := s.db.GetProfilesScrapedCountByDate(time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get today's enrichment count: %w", err)
+	}
+	if scraped >= s.cfg.DailyLimit {
+		return nil, fmt.Errorf("%w (%d/%d)", ErrDailyLimit, scraped, s.cfg.DailyLimit)
+	}
+
+	if err := s.page.Navigate(profileURL); err != nil {
+		return nil, fmt.Errorf("failed to navigate to profile: %w", err)
+	}
+	if err := s.page.WaitLoad(); err != nil {
+		logger.Warnf("Profile page load wait failed: %v", err)
+	}
+	s.timing.Wait(s.timing.ThinkTime())
+
+	// Rows saved before the member ID was known pick it up here
+	if err := s.db.SetProfileMemberID(profileURL, pageops.MemberID(s.page)); err != nil {
+		logger.Warnf("%v", err)
+	}
+
+	profile := &storage.Profile{
+		ProfileURL: profileURL,
+		Name:       s.text(s.page, selectors.ProfileName),
+		Headline:   s.text(s.page, selectors.ProfileHeadline),
+		Location:   s.text(s.page, selectors.ProfileLocation),
+		Followers:  parseFollowers(s.text(s.page, selectors.ProfileFollowers)),
+	}
+
+	// Read down the page the way a person would; the lower sections load as they come into view
+	for i := 0; i < 3; i++ {
+		if err := s.scroller.ScrollDown(600); err != nil {
+			logger.Debugf("Failed to scroll profile: %v", err)
+		}
+		s.timing.Wait(s.timing.ShortPause())
+	}
+
+	profile.About = s.about()
+	profile.Experience = s.experience()
+	profile.Education = s.education()
+	profile.ScrapedAt = time.Now()
+
+	if err := s.db.SaveProfile(profile); err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Enriched %s: %d positions, %d schools, %d followers", profileURL, len(profile.Experience), len(profile.Education), profile.Followers)
+	s.db.LogActivity("profile_scraped", profileURL)
+	s.timing.Wait(s.timing.ActionDelay())
+	return profile, nil
+}
+
+// about expands the About section and returns its text
+func (s *Scraper) about() string {
+	if button, err := selectors.FindFirst(s.page, selectors.AboutSeeMore); err == nil {
+		if err := s.clicker.Click(button); err != nil {
+			logger.Debugf("Failed to expand About: %v", err)
+		}
+		s.timing.Wait(s.timing.ShortPause())
+	}
+
+	about := s.text(s.page, selectors.AboutText)
+	if about != "" {
+		s.timing.Wait(s.timing.ReadingTime(len(strings.Fields(about))))
+	}
+	return about
+}
+
+// experience walks the Experience list
+func (s *Scraper) experience() []storage.Position {
+	items, err := selectors.FindAll(s.page, selectors.ExperienceItem)
+	if err != nil {
+		return nil
+	}
+
+	var positions []storage.Position
+	for _, item := range items {
+		position := storage.Position{
+			Title:   s.text(item, selectors.ExperienceTitle),
+			Company: companyName(s.text(item, selectors.ExperienceCompany)),
+		}
+		if position.Title == "" {
+			continue
+		}
+		position.Start, position.Current = parseDateRange(s.text(item, selectors.ExperienceDates))
+		positions = append(positions, position)
+	}
+	return positions
+}
+
+// education returns the schools in the Education list
+func (s *Scraper) education() []string {
+	items, err := selectors.FindAll(s.page, selectors.EducationItem)
+	if err != nil {
+		return nil
+	}
+
+	var schools []string
+	for _, item := range items {
+		if school := s.text(item, selectors.EducationSchool); school != "" {
+			schools = append(schools, school)
+		}
+	}
+	return schools
+}
+
+// text returns the trimmed text of the first element matching name, or ""
+func (s *Scraper) text(scope selectors.Scope, name string) string {
+	el, err := selectors.FindFirst(scope, name)
+	if err != nil {
+		return ""
+	}
+	text, _ := el.Text()
+	return strings.TrimSpace(text)
+}
+
+// companyName drops the employment type LinkedIn appends, as in "Acme · Full-time"
+func companyName(text string) string {
+	name, _, _ := strings.Cut(text, "·")
+	return strings.TrimSpace(name)
+}
+
+// yearOnly matches a date range bound given as a bare year
+var yearOnly = regexp.MustCompile(`^\d{4}$`)
+
+// parseDateRange reads the start month of a position and whether it is still held from
+// text like "Jan 2020 - Present · 4 yrs 2 mos" or "2018 – 2021 · 3 yrs"
+func parseDateRange(text string) (time.Time, bool) {
+	span, _, _ := strings.Cut(text, "·")
+	span = strings.ReplaceAll(span, "–", "-")
+	from, to, found := strings.Cut(span, "-")
+	if !found {
+		return time.Time{}, false
+	}
+
+	from = strings.TrimSpace(from)
+	current := strings.EqualFold(strings.TrimSpace(to), "present")
+
+	if start, err := time.Parse("Jan 2006", from); err == nil {
+		return start, current
+	}
+	if yearOnly.MatchString(from) {
+		start, _ := time.Parse("2006", from)
+		return start, current
+	}
+	return time.Time{}, current
+}
+
+// parseFollowers reads a follower count like "1,234 followers" or "12K followers"
+func parseFollowers(text string) int {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return 0
+	}
+
+	number := strings.ReplaceAll(fields[0], ",", "")
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(strings.ToUpper(number), "K"):
+		multiplier, number = 1000, number[:len(number)-1]
+	case strings.HasSuffix(strings.ToUpper(number), "M"):
+		multiplier, number = 1000000, number[:len(number)-1]
+	}
+
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0
+	}
+	return int(n * multiplier)
+}
+
+// TemplateVars adds the enriched details of a profile to the variables a template is
+// filled with; with no profile the variables are returned unchanged
+func TemplateVars(vars render.Vars, profile *storage.Profile, now time.Time) render.Vars {
+	if profile == nil {
+		return vars
+	}
+
+	vars.Headline = profile.Headline
+	vars.About = profile.About
+	vars.Location = profile.Location
+	vars.Followers = profile.Followers
+	if len(profile.Education) > 0 {
+		vars.School = profile.Education[0]
+	}
+	if position := profile.CurrentPosition(); position != nil && !position.Start.IsZero() {
+		months := (now.Year()-position.Start.Year())*12 + int(now.Month()-position.Start.Month())
+		vars.CurrentPositionYears = months / 12
+	}
+	return vars
+}
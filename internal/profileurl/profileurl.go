@@ -0,0 +1,118 @@
+// Package profileurl reduces the many spellings of a LinkedIn profile URL to one,
+// so the same person is recognized however the URL was captured.
+package profileurl
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// canonicalHost is the host every LinkedIn profile URL is normalized to
+const canonicalHost = "www.linkedin.com"
+
+// Normalize returns the canonical form of a profile URL: https on www.linkedin.com,
+// no query string or fragment, no trailing slash, and a lowercase path. Country
+// subdomains such as uk.linkedin.com map to www. Anything that doesn't parse as a
+// URL is only trimmed and lowercased.
+func Normalize(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		// Relative links such as "/in/jane-doe/" as found in page markup
+		if strings.HasPrefix(raw, "/in/") {
+			return Normalize("https://" + canonicalHost + raw)
+		}
+		return strings.ToLower(strings.TrimRight(stripQuery(raw), "/"))
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "linkedin.com" || strings.HasSuffix(host, ".linkedin.com") {
+		host = canonicalHost
+	}
+
+	// Slugs may arrive percent-encoded or not; compare them decoded
+	path := u.Path
+	if decoded, err := url.PathUnescape(u.EscapedPath()); err == nil {
+		path = decoded
+	}
+	path = strings.ToLower(strings.TrimRight(path, "/"))
+
+	return "https://" + host + path
+}
+
+// IsProfile reports whether raw is a LinkedIn public profile URL, /in/ followed by a
+// slug. Only these are safe to compare normalized: other URLs, such as Sales Navigator
+// leads or other sites, may be case-sensitive.
+func IsProfile(raw string) bool {
+	normalized := Normalize(raw)
+	slug := strings.TrimPrefix(normalized, "https://"+canonicalHost+"/in/")
+	return slug != normalized && slug != ""
+}
+
+// stripQuery drops everything from the first "?" or "#"
+func stripQuery(s string) string {
+	if idx := strings.IndexAny(s, "?#"); idx != -1 {
+		return s[:idx]
+	}
+	return s
+}
+
+// IsSalesLead reports whether raw is a Sales Navigator lead URL rather than a
+// public profile URL
+func IsSalesLead(raw string) bool {
+	return strings.Contains(raw, "/sales/lead/") || strings.Contains(raw, "/sales/people/")
+}
+
+// memberIDPattern matches the entity ID LinkedIn gives every member, as used in
+// urn:li:fsd_profile URNs and in /in/ URLs that carry no vanity name
+var memberIDPattern = regexp.MustCompile(`^ACoAA[A-Za-z0-9_-]+$`)
+
+// memberURNPattern finds a member's profile URN in a query string or page markup
+var memberURNPattern = regexp.MustCompile(`urn:li:(?:fsd_profile|fs_miniProfile|fs_profile):(ACoAA[A-Za-z0-9_-]+)`)
+
+// IsMemberID reports whether id looks like a member's entity ID
+func IsMemberID(id string) bool {
+	return memberIDPattern.MatchString(id)
+}
+
+// ParseMemberID returns the member's entity ID given as the ID itself, in a profile URN
+// or in a profile URL carrying it; "" when s is none of these
+func ParseMemberID(s string) string {
+	s = strings.TrimSpace(s)
+	if IsMemberID(s) {
+		return s
+	}
+	if m := memberURNPattern.FindStringSubmatch(s); m != nil {
+		return m[1]
+	}
+	return MemberID(s)
+}
+
+// MemberID returns the member's entity ID carried by a profile URL, either as its /in/
+// path or in a profile URN of its query string, such as the miniProfileUrn search
+// results link with. It returns "" for URLs that carry none, such as vanity ones.
+func MemberID(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return ""
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) >= 2 && segments[0] == "in" && IsMemberID(segments[1]) {
+		return segments[1]
+	}
+
+	query, err := url.QueryUnescape(u.RawQuery)
+	if err != nil {
+		return ""
+	}
+	if m := memberURNPattern.FindStringSubmatch(query); m != nil {
+		return m[1]
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package profileurl
+
+import "testing"
+
+const memberID = "ACoAAB1xYz2_Q-abc"
+
+func TestMemberID(t *testing.T) {
+	for _, tt := range []struct {
+		url, want string
+	}{
+		{"https://www.linkedin.com/in/" + memberID, memberID},
+		{"https://www.linkedin.com/in/" + memberID + "/?trk=people", memberID},
+		{"https://www.linkedin.com/in/ada-lovelace?miniProfileUrn=urn%3Ali%3Afs_miniProfile%3A" + memberID, memberID},
+		{"https://www.linkedin.com/in/ada-lovelace/?profileUrn=urn:li:fsd_profile:" + memberID, memberID},
+		{"https://www.linkedin.com/in/ada-lovelace/", ""},
+		{"https://www.linkedin.com/in/ACME-corp/", ""},
+		{"https://www.linkedin.com/company/" + memberID, ""},
+		{"", ""},
+	} {
+		if got := MemberID(tt.url); got != tt.want {
+			t.Errorf("MemberID(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestIsMemberID(t *testing.T) {
+	for id, want := range map[string]bool{
+		memberID:       true,
+		"ada-lovelace": false,
+		"ACoAA":        false,
+		"xACoAAB1xYz2": false,
+		memberID + "/": false,
+	} {
+		if got := IsMemberID(id); got != want {
+			t.Errorf("IsMemberID(%q) = %v, want %v", id, got, want)
+		}
+	}
+}
+
+func TestIsProfile(t *testing.T) {
+	for url, want := range map[string]bool{
+		"https://www.linkedin.com/in/ada-lovelace/":          true,
+		"https://uk.linkedin.com/in/Ada-Lovelace?trk=people": true,
+		"/in/ada-lovelace/":                                           true,
+		"https://www.linkedin.com/in/":                                false,
+		"https://www.linkedin.com/sales/lead/ACwAAB1xYz2,NAME_SEARCH": false,
+		"https://www.linkedin.com/company/acme/":                      false,
+		"https://example.com/in/ada-lovelace":                         false,
+		"ada-lovelace":                                                false,
+		"":                                                            false,
+	} {
+		if got := IsProfile(url); got != want {
+			t.Errorf("IsProfile(%q) = %v, want %v", url, got, want)
+		}
+	}
+}
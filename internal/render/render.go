@@ -0,0 +1,354 @@
+// Package render fills note and message templates.
+//
+// Templates use Go's text/template syntax. The profile variables are exposed
+// as functions so the existing {{firstName}}, {{jobTitle}} and {{company}}
+// placeholders keep working, along with {{subject}} in InMail bodies and
+// locale-aware helpers:
+//
+//	{{number 40000}}           40,000 (en-US) / 40.000 (de-DE)
+//	{{money 40000 "EUR"}}      €40,000.00 (en-US) / 40.000,00 € (de-DE)
+//	{{date "2025-03-14"}}      March 14, 2025 (en-US) / 14.03.2025 (de-DE)
+//	{{plural 3 "team" "teams"}} teams
+//
+// The currency symbol goes after the amount in the languages listed in
+// symbolAfter and before it elsewhere; spaces within figures are non-breaking.
+//
+// Details of enriched profiles are fields of the template data, e.g.
+// {{.CurrentPositionYears}} or {{if .School}}...{{end}}; they are empty for
+// profiles that weren't scraped. {{.CurationNote}} is the note added by hand
+// with profiles note.
+package render
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// DefaultLocale is used when no locale is configured
+const DefaultLocale = "en-US"
+
+// Vars are the profile values a template can reference
+type Vars struct {
+	FirstName string
+	JobTitle  string
+	Company   string
+	Subject   string // the InMail subject line, empty elsewhere
+
+	// Enriched profile details, zero until the profile is scraped
+	Headline             string
+	About                string
+	Location             string
+	School               string // the first school listed
+	Followers            int
+	CurrentPositionYears int // whole years in the current position
+
+	CurationNote string // the note added by hand with profiles note, empty when none
+}
+
+// sampleVars fill templates when they are checked at config load
+var sampleVars = Vars{
+	FirstName: "Alex", JobTitle: "Engineer", Company: "Acme", Subject: "Quick question",
+	Headline: "Engineer at Acme", About: "Building things.", Location: "Berlin", School: "TU Berlin",
+	Followers: 500, CurrentPositionYears: 3, CurationNote: "Met at KubeCon",
+}
+
+// dateLayouts maps a locale, or its language, to the layout used by {{date}}.
+// Month names are only spelled out for English; other languages use numeric dates.
+var dateLayouts = map[string]string{
+	"en-US": "January 2, 2006",
+	"en":    "2 January 2006",
+	"de":    "02.01.2006",
+	"fr":    "02/01/2006",
+	"es":    "02/01/2006",
+	"it":    "02/01/2006",
+	"pt":    "02/01/2006",
+	"nl":    "02-01-2006",
+	"ja":    "2006/01/02",
+	"zh":    "2006/01/02",
+	"ko":    "2006. 01. 02.",
+}
+
+// symbolAfter maps a locale, or its language, to whether {{money}} puts the
+// currency symbol after the amount
+var symbolAfter = map[string]bool{
+	"de":    true,
+	"de-AT": false,
+	"de-CH": false,
+	"fr":    true,
+	"es":    true,
+	"it":    true,
+	"pt":    true,
+	"pt-BR": false,
+	"pl":    true,
+	"cs":    true,
+	"sv":    true,
+	"fi":    true,
+}
+
+// helpers are the template functions that take arguments; calls to them with
+// constant arguments are tried out by Check
+var helpers = []string{"number", "money", "date", "plural"}
+
+// Execute renders text for a profile in the given locale
+func Execute(text string, vars Vars, locale string) (string, error) {
+	tag, err := ParseLocale(locale)
+	if err != nil {
+		return "", err
+	}
+
+	tmpl, err := template.New("template").Funcs(funcs(vars, tag)).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, vars); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return out.String(), nil
+}
+
+// Check renders text with sample values and with empty ones, and tries every
+// helper call with constant arguments, in branches neither rendering takes
+// too, so unknown helpers and bad helper arguments are reported when the
+// config is loaded rather than at send time
+func Check(text, locale string) error {
+	if _, err := Execute(text, sampleVars, locale); err != nil {
+		return err
+	}
+	if _, err := Execute(text, Vars{}, locale); err != nil {
+		return err
+	}
+
+	tag, err := ParseLocale(locale)
+	if err != nil {
+		return err
+	}
+	fm := funcs(sampleVars, tag)
+	tmpl, err := template.New("template").Funcs(fm).Parse(text)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+	for _, t := range tmpl.Templates() {
+		if err := checkCalls(t.Root, fm); err != nil {
+			return fmt.Errorf("failed to render template: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkCalls calls every helper under node whose arguments are all constants
+func checkCalls(node parse.Node, fm template.FuncMap) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := checkCalls(child, fm); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		return checkCalls(n.Pipe, fm)
+	case *parse.IfNode:
+		return checkBranch(&n.BranchNode, fm)
+	case *parse.RangeNode:
+		return checkBranch(&n.BranchNode, fm)
+	case *parse.WithNode:
+		return checkBranch(&n.BranchNode, fm)
+	case *parse.TemplateNode:
+		return checkCalls(n.Pipe, fm)
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for i, cmd := range n.Cmds {
+			// A later command also gets the value piped in, which isn't known here
+			if i == 0 {
+				if err := checkCall(cmd, fm); err != nil {
+					return err
+				}
+			}
+			for _, arg := range cmd.Args {
+				if err := checkCalls(arg, fm); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkBranch checks the pipeline and both bodies of an if, range or with
+func checkBranch(n *parse.BranchNode, fm template.FuncMap) error {
+	for _, node := range []parse.Node{n.Pipe, n.List, n.ElseList} {
+		if err := checkCalls(node, fm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkCall calls cmd's helper when cmd is a helper call whose arguments are all
+// constants, returning the helper's error or the mismatch template execution would report
+func checkCall(cmd *parse.CommandNode, fm template.FuncMap) error {
+	ident, ok := cmd.Args[0].(*parse.IdentifierNode)
+	if !ok || !slices.Contains(helpers, ident.Ident) {
+		return nil
+	}
+
+	var args []reflect.Value
+	for _, arg := range cmd.Args[1:] {
+		switch a := arg.(type) {
+		case *parse.NumberNode:
+			if a.IsInt {
+				args = append(args, reflect.ValueOf(int(a.Int64)))
+			} else {
+				args = append(args, reflect.ValueOf(a.Float64))
+			}
+		case *parse.StringNode:
+			args = append(args, reflect.ValueOf(a.Text))
+		default:
+			return nil
+		}
+	}
+
+	fn := reflect.ValueOf(fm[ident.Ident])
+	if fn.Type().NumIn() != len(args) {
+		return fmt.Errorf("%s: wrong number of args: want %d got %d", ident.Ident, fn.Type().NumIn(), len(args))
+	}
+	for i, arg := range args {
+		if want := fn.Type().In(i); !arg.Type().AssignableTo(want) {
+			return fmt.Errorf("%s: argument %d is a %s, want a %s", ident.Ident, i+1, arg.Type(), want)
+		}
+	}
+	if out := fn.Call(args); !out[1].IsNil() {
+		return fmt.Errorf("%s: %w", ident.Ident, out[1].Interface().(error))
+	}
+	return nil
+}
+
+// ParseLocale parses a BCP 47 locale such as "en-US" or "de"; empty means DefaultLocale
+func ParseLocale(locale string) (language.Tag, error) {
+	if locale == "" {
+		locale = DefaultLocale
+	}
+
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return language.Und, fmt.Errorf("invalid locale %q: %w", locale, err)
+	}
+	return tag, nil
+}
+
+// funcs returns the variables and helpers available to a template
+func funcs(vars Vars, tag language.Tag) template.FuncMap {
+	printer := message.NewPrinter(tag)
+
+	return template.FuncMap{
+		"firstName": func() string { return vars.FirstName },
+		"jobTitle":  func() string { return vars.JobTitle },
+		"company":   func() string { return vars.Company },
+		"subject":   func() string { return vars.Subject },
+
+		"number": func(v interface{}) (string, error) {
+			n, err := toFloat(v)
+			if err != nil {
+				return "", err
+			}
+			return printer.Sprint(number.Decimal(n)), nil
+		},
+
+		"money": func(v interface{}, code string) (string, error) {
+			n, err := toFloat(v)
+			if err != nil {
+				return "", err
+			}
+			unit, err := currency.ParseISO(code)
+			if err != nil {
+				return "", fmt.Errorf("unknown currency %q", code)
+			}
+			scale, _ := currency.Standard.Rounding(unit)
+			amount := printer.Sprint(number.Decimal(n, number.Scale(scale)))
+			symbol := printer.Sprint(currency.Symbol(unit))
+			if lookup(symbolAfter, tag) {
+				return amount + "\u00a0" + symbol, nil
+			}
+			// Symbols that end in a letter, such as CHF or JPY, keep a space
+			if last, _ := utf8.DecodeLastRuneInString(symbol); unicode.IsLetter(last) {
+				return symbol + "\u00a0" + amount, nil
+			}
+			return symbol + amount, nil
+		},
+
+		"date": func(value string) (string, error) {
+			t, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return "", fmt.Errorf("%q is not a YYYY-MM-DD date", value)
+			}
+			return t.Format(dateLayout(tag)), nil
+		},
+
+		"plural": func(v interface{}, one, other string) (string, error) {
+			n, err := toFloat(v)
+			if err != nil {
+				return "", err
+			}
+			if n != math.Trunc(n) {
+				return other, nil
+			}
+			if plural.Cardinal.MatchPlural(tag, int(math.Abs(n)), 0, 0, 0, 0) == plural.One {
+				return one, nil
+			}
+			return other, nil
+		},
+	}
+}
+
+// dateLayout returns the layout for tag's region, then its language, then ISO
+func dateLayout(tag language.Tag) string {
+	if layout := lookup(dateLayouts, tag); layout != "" {
+		return layout
+	}
+	return "2006-01-02"
+}
+
+// lookup returns the entry of m for tag's region, then its language, then the zero value
+func lookup[V any](m map[string]V, tag language.Tag) V {
+	base, _ := tag.Base()
+	region, _ := tag.Region()
+
+	if v, ok := m[base.String()+"-"+region.String()]; ok {
+		return v
+	}
+	return m[base.String()]
+}
+
+// toFloat accepts the numeric values a template literal can produce
+func toFloat(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case float64:
+		return n, nil
+	default:
+		return 0, fmt.Errorf("expected a number, got %T %v", v, v)
+	}
+}
@@ -0,0 +1,96 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHelpers(t *testing.T) {
+	for _, tt := range []struct {
+		locale, text, want string
+	}{
+		{"en-US", `{{number 40000}}`, "40,000"},
+		{"de-DE", `{{number 40000}}`, "40.000"},
+		{"fr", `{{number 1234.5}}`, "1\u00a0234,5"},
+		{"ja", `{{number 40000}}`, "40,000"},
+
+		{"en-US", `{{money 40000 "USD"}}`, "$40,000.00"},
+		{"en-US", `{{money 40000 "EUR"}}`, "€40,000.00"},
+		{"de-DE", `{{money 40000 "EUR"}}`, "40.000,00\u00a0€"},
+		{"de-CH", `{{money 40000 "EUR"}}`, "EUR\u00a040’000.00"},
+		{"fr", `{{money 40000 "EUR"}}`, "40\u00a0000,00\u00a0€"},
+		{"ja", `{{money 40000 "JPY"}}`, "￥40,000"},
+
+		{"en-US", `{{date "2025-03-14"}}`, "March 14, 2025"},
+		{"de-DE", `{{date "2025-03-14"}}`, "14.03.2025"},
+		{"fr", `{{date "2025-03-14"}}`, "14/03/2025"},
+		{"ja", `{{date "2025-03-14"}}`, "2025/03/14"},
+
+		{"en-US", `{{plural 1 "team" "teams"}}`, "team"},
+		{"en-US", `{{plural 3 "team" "teams"}}`, "teams"},
+		{"de-DE", `{{plural 1 "Team" "Teams"}}`, "Team"},
+		{"fr", `{{plural 0 "équipe" "équipes"}}`, "équipe"}, // French counts 0 as one
+		{"ja", `{{plural 1 "チーム" "チーム"}}`, "チーム"},
+		{"en-US", `{{plural 1.5 "team" "teams"}}`, "teams"},
+	} {
+		t.Run(tt.locale+" "+tt.text, func(t *testing.T) {
+			got, err := Execute(tt.text, Vars{}, tt.locale)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("Execute = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteVars(t *testing.T) {
+	text := `Hi {{firstName}}{{if .School}}, fellow {{.School}} alum{{end}}`
+	for _, tt := range []struct {
+		vars Vars
+		want string
+	}{
+		{Vars{FirstName: "Ada", School: "UCL"}, "Hi Ada, fellow UCL alum"},
+		{Vars{FirstName: "Ada"}, "Hi Ada"},
+	} {
+		if got, err := Execute(text, tt.vars, "en-US"); err != nil || got != tt.want {
+			t.Errorf("Execute = %q, %v, want %q", got, err, tt.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	for _, tt := range []struct {
+		name, text, locale string
+		want               string // part of the error expected; "" for none
+	}{
+		{"helpers", `{{money 40000 "EUR"}} for {{plural .Followers "follower" "followers"}}`, "de-DE", ""},
+		{"field argument", `{{number .Followers}}`, "en-US", ""},
+		{"piped argument", `{{40000 | number}}`, "en-US", ""},
+		{"unknown helper", `{{shout firstName}}`, "en-US", "shout"},
+		{"invalid locale", `Hi`, "not a locale!", "invalid locale"},
+		{"unknown currency", `{{money 1 "ZZZ"}}`, "en-US", `unknown currency "ZZZ"`},
+		{"bad date", `{{date "14.03.2025"}}`, "en-US", "is not a YYYY-MM-DD date"},
+		{"text for a number", `{{number "many"}}`, "en-US", "expected a number"},
+		{"missing argument", `{{money 40000}}`, "en-US", "wrong number of args"},
+		{"branch a scraped profile takes", `{{if .School}}{{date "soon"}}{{end}}`, "en-US", "is not a YYYY-MM-DD date"},
+		{"branch an unscraped profile takes", `{{if not .School}}{{money 1 "ZZZ"}}{{end}}`, "en-US", `unknown currency "ZZZ"`},
+		{"branch no profile takes", `{{if and .School (not .School)}}{{money 1 "ZZZ"}}{{end}}`, "en-US", `unknown currency "ZZZ"`},
+		{"else branch", `{{with .About}}{{.}}{{else}}{{plural 2 "team"}}{{end}}`, "en-US", "wrong number of args"},
+		{"nested call", `{{printf "%s" (money 1 "ZZZ")}}`, "en-US", `unknown currency "ZZZ"`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(tt.text, tt.locale)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Check = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Check = %v, want an error about %q", err, tt.want)
+			}
+		})
+	}
+}
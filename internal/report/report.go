@@ -0,0 +1,342 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/budget"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/planner"
+	"github.com/Tanukumar01/linkedin-automation/internal/snapshot"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+	"github.com/Tanukumar01/linkedin-automation/pkg/browser"
+)
+
+// RunReport summarizes everything that happened during a single run
+type RunReport struct {
+	StartedAt         time.Time                   `json:"started_at"`
+	FinishedAt        time.Time                   `json:"finished_at"`
+	DurationSeconds   float64                     `json:"duration_seconds"`
+	SearchesPerformed int                         `json:"searches_performed"`
+	ProfilesFound     int                         `json:"profiles_found"`
+	ProfilesNew       int                         `json:"profiles_new"`
+	Connections       ConnectionSummary           `json:"connections"`
+	Campaigns         map[string]*CampaignSummary `json:"campaigns"`
+	Planner           *planner.Decision           `json:"planner,omitempty"`
+	ActionBudget      []budget.Allocation         `json:"action_budget,omitempty"` // the day's action budget at the end of the run; nil when disabled
+	MessagesSent      int                         `json:"messages_sent"`
+	RestrictionsHit   []string                    `json:"restrictions_hit"`
+	SafeMode          string                      `json:"safe_mode,omitempty"` // why the run put the bot in safe mode
+	Skips             []ProfileOutcome            `json:"skips"`
+	Failures          []ProfileOutcome            `json:"failures"`
+	FailuresByKind    map[string]int              `json:"failures_by_kind"`
+	Phases            []PhaseTiming               `json:"phases"`
+	Stealth           *stealth.MetricsSummary     `json:"stealth,omitempty"`
+	Pacing            *stealth.Persona            `json:"pacing,omitempty"`   // the session persona's pace; nil when disabled
+	Snapshot          *snapshot.Snapshot          `json:"snapshot,omitempty"` // effective config and persona
+}
+
+// ConnectionSummary counts connection request outcomes
+type ConnectionSummary struct {
+	Attempted int `json:"attempted"`
+	Sent      int `json:"sent"`
+	Skipped   int `json:"skipped"`
+	Failed    int `json:"failed"`
+}
+
+// CampaignSummary describes what a single campaign did during the run
+type CampaignSummary struct {
+	Budget        int               `json:"budget"`
+	ProfilesFound int               `json:"profiles_found"`
+	ProfilesNew   int               `json:"profiles_new"`
+	Connections   ConnectionSummary `json:"connections"`
+}
+
+// PhaseTiming compares a phase's time budget with how long it actually ran
+type PhaseTiming struct {
+	Phase         string  `json:"phase"`
+	BudgetSeconds float64 `json:"budget_seconds"`
+	ActualSeconds float64 `json:"actual_seconds"`
+	Overrun       bool    `json:"overrun"`
+
+	StartedAt  time.Time `json:"started_at"`  // first start; the phase may have paused in between
+	FinishedAt time.Time `json:"finished_at"` // last stop
+}
+
+// ProfileOutcome describes why an action on a profile was skipped or failed
+type ProfileOutcome struct {
+	Action      string `json:"action"`
+	ProfileURL  string `json:"profile_url"`
+	ProfileName string `json:"profile_name"`
+	Reason      string `json:"reason"`
+	ErrorKind   string `json:"error_kind,omitempty"` // browser error kind for failures
+}
+
+// NewRunReport creates a report for a run starting now
+func NewRunReport() *RunReport {
+	return &RunReport{
+		StartedAt:       time.Now(),
+		Campaigns:       map[string]*CampaignSummary{},
+		RestrictionsHit: []string{},
+		Skips:           []ProfileOutcome{},
+		Failures:        []ProfileOutcome{},
+		FailuresByKind:  map[string]int{},
+		Phases:          []PhaseTiming{},
+	}
+}
+
+// campaign returns the summary for a campaign, creating it on first use
+func (r *RunReport) campaign(name string) *CampaignSummary {
+	summary, ok := r.Campaigns[name]
+	if !ok {
+		summary = &CampaignSummary{}
+		r.Campaigns[name] = summary
+	}
+	return summary
+}
+
+// RecordCampaignBudget records the connection budget allocated to a campaign
+func (r *RunReport) RecordCampaignBudget(campaign string, budget int) {
+	r.campaign(campaign).Budget = budget
+}
+
+// RecordSearch records a completed search for a campaign
+func (r *RunReport) RecordSearch(campaign string, found, newProfiles int) {
+	r.SearchesPerformed++
+	r.ProfilesFound += found
+	r.ProfilesNew += newProfiles
+
+	c := r.campaign(campaign)
+	c.ProfilesFound += found
+	c.ProfilesNew += newProfiles
+}
+
+// RecordConnectionSent records a connection request that was sent
+func (r *RunReport) RecordConnectionSent(campaign string) {
+	r.Connections.Attempted++
+	r.Connections.Sent++
+
+	c := r.campaign(campaign)
+	c.Connections.Attempted++
+	c.Connections.Sent++
+}
+
+// RecordConnectionSkipped records a profile that was deliberately not contacted
+func (r *RunReport) RecordConnectionSkipped(campaign, profileURL, profileName, reason string) {
+	r.Connections.Attempted++
+	r.Connections.Skipped++
+
+	c := r.campaign(campaign)
+	c.Connections.Attempted++
+	c.Connections.Skipped++
+
+	r.Skips = append(r.Skips, ProfileOutcome{
+		Action:      "connect",
+		ProfileURL:  profileURL,
+		ProfileName: profileName,
+		Reason:      reason,
+	})
+}
+
+// RecordConnectionFailed records a connection request that failed
+func (r *RunReport) RecordConnectionFailed(campaign, profileURL, profileName string, err error) {
+	r.Connections.Attempted++
+	r.Connections.Failed++
+
+	c := r.campaign(campaign)
+	c.Connections.Attempted++
+	c.Connections.Failed++
+
+	r.RecordFailure("connect", profileURL, profileName, err)
+}
+
+// RecordMessageSent records a message that was sent
+func (r *RunReport) RecordMessageSent() {
+	r.MessagesSent++
+}
+
+// RecordFailure records a failed action on a profile
+func (r *RunReport) RecordFailure(action, profileURL, profileName string, err error) {
+	kind := string(browser.Classify(err).Kind)
+	r.FailuresByKind[kind]++
+
+	r.Failures = append(r.Failures, ProfileOutcome{
+		Action:      action,
+		ProfileURL:  profileURL,
+		ProfileName: profileName,
+		Reason:      err.Error(),
+		ErrorKind:   kind,
+	})
+}
+
+// RecordPhase records a phase's budgeted and actual duration and when it ran
+func (r *RunReport) RecordPhase(phase string, budget, actual time.Duration, overrun bool, started, finished time.Time) {
+	r.Phases = append(r.Phases, PhaseTiming{
+		Phase:         phase,
+		BudgetSeconds: budget.Seconds(),
+		ActualSeconds: actual.Seconds(),
+		Overrun:       overrun,
+		StartedAt:     started,
+		FinishedAt:    finished,
+	})
+}
+
+// RecordRestriction records a limit or restriction that stopped part of the run
+func (r *RunReport) RecordRestriction(restriction string) {
+	r.RestrictionsHit = append(r.RestrictionsHit, restriction)
+}
+
+// RecordSafeMode records that the run stopped itself and put the bot in safe mode
+func (r *RunReport) RecordSafeMode(reason string) {
+	r.SafeMode = reason
+}
+
+// Finish marks the end of the run
+func (r *RunReport) Finish() {
+	r.FinishedAt = time.Now()
+	r.DurationSeconds = r.FinishedAt.Sub(r.StartedAt).Seconds()
+}
+
+// ID identifies the run by its start time; it names the report file
+func (r *RunReport) ID() string {
+	return r.StartedAt.Format("2006-01-02T15-04")
+}
+
+// WriteJSON writes the report to a timestamped file in dir and returns its path
+func (r *RunReport) WriteJSON(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("run-%s.json", r.ID()))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write report: %w", err)
+	}
+
+	return path, nil
+}
+
+// LogSummary writes a human-readable summary to the log
+func (r *RunReport) LogSummary() {
+	logger.Info("Run Summary:")
+	logger.Infof("  Duration: %s", time.Duration(r.DurationSeconds*float64(time.Second)).Round(time.Second))
+	logger.Infof("  Searches: %d (profiles found: %d, new: %d)", r.SearchesPerformed, r.ProfilesFound, r.ProfilesNew)
+	logger.Infof("  Connections: %d attempted, %d sent, %d skipped, %d failed",
+		r.Connections.Attempted, r.Connections.Sent, r.Connections.Skipped, r.Connections.Failed)
+	logger.Infof("  Messages Sent: %d", r.MessagesSent)
+
+	if r.Planner != nil {
+		logger.Infof("  Planner: connect budget %d/%d, message backlog %d (%s)",
+			r.Planner.ConnectBudget, r.Planner.FullBudget, r.Planner.Backlog, r.Planner.Reason)
+	}
+
+	names := make([]string, 0, len(r.Campaigns))
+	for name := range r.Campaigns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		c := r.Campaigns[name]
+		logger.Infof("  Campaign %s: budget %d, found %d (new %d), %d sent, %d skipped, %d failed",
+			name, c.Budget, c.ProfilesFound, c.ProfilesNew, c.Connections.Sent, c.Connections.Skipped, c.Connections.Failed)
+	}
+
+	for _, p := range r.Phases {
+		status := ""
+		if p.Overrun {
+			status = " (overrun)"
+		}
+		logger.Infof("  Phase %s: %s of %s%s", p.Phase,
+			time.Duration(p.ActualSeconds*float64(time.Second)).Round(time.Second),
+			time.Duration(p.BudgetSeconds*float64(time.Second)).Round(time.Second), status)
+	}
+
+	for _, restriction := range r.RestrictionsHit {
+		logger.Infof("  Restriction: %s", restriction)
+	}
+
+	if r.SafeMode != "" {
+		logger.Infof("  Safe mode: %s", r.SafeMode)
+	}
+
+	for _, f := range r.Failures {
+		logger.Infof("  Failed %s %s (%s): %s", f.Action, f.ProfileURL, f.ErrorKind, f.Reason)
+	}
+
+	kinds := make([]string, 0, len(r.FailuresByKind))
+	for kind := range r.FailuresByKind {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		logger.Infof("  Failures (%s): %d", kind, r.FailuresByKind[kind])
+	}
+
+	if r.Stealth != nil {
+		logger.Infof("  Stealth: typing %.1f WPM mean, mouse curvature %.3f, scroll p90 %.0f px/s",
+			r.Stealth.Typing.MeanWPM, r.Stealth.Mouse.MeanCurvature, r.Stealth.Scrolling.P90Velocity)
+		for _, flag := range r.Stealth.Flags {
+			logger.Warnf("  Stealth flag: %s", flag)
+		}
+	}
+}
+
+// LoadLatest reads the most recent run report from dir
+func LoadLatest(dir string) (*RunReport, string, error) {
+	ids, err := List(dir)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(ids) == 0 {
+		return nil, "", fmt.Errorf("no run reports found in %s", dir)
+	}
+
+	return Load(dir, ids[len(ids)-1])
+}
+
+// List returns the IDs of the run reports in dir, oldest first
+func List(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "run-*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Timestamped names sort chronologically
+	sort.Strings(paths)
+
+	ids := make([]string, len(paths))
+	for i, path := range paths {
+		ids[i] = strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "run-"), ".json")
+	}
+	return ids, nil
+}
+
+// Load reads the run report with the given ID from dir
+func Load(dir, id string) (*RunReport, string, error) {
+	path := filepath.Join(dir, "run-"+id+".json")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read report: %w", err)
+	}
+
+	var r RunReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, "", fmt.Errorf("failed to parse report: %w", err)
+	}
+
+	return &r, path, nil
+}
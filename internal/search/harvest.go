@@ -0,0 +1,346 @@
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/artifacts"
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/headline"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/profileurl"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// maxStaleRounds is how many scrolls in a row may turn up no unseen members
+// before the list is taken to be exhausted
+const maxStaleRounds = 3
+
+// SourceCompanyPage tags employees harvested from a company page
+const SourceCompanyPage = "company_page"
+
+var (
+	groupIDPattern     = regexp.MustCompile(`/groups/(\d+)`)
+	eventIDPattern     = regexp.MustCompile(`/events/([^/?#]+)`)
+	companySlugPattern = regexp.MustCompile(`/company/([^/?#]+)`)
+	geoIDPattern       = regexp.MustCompile(`^\d+$`)
+)
+
+// memberList describes the list of people being harvested
+type memberList struct {
+	source  string      // tagged on every member
+	item    string      // selector of one row
+	company string      // tagged on every member of a company's employee list
+	wall    string      // selector of a notice that ends the list early; empty for none
+	filter  *PostFilter // nil saves every member unflagged
+}
+
+// name identifies the list in logs
+func (l memberList) name() string {
+	if l.company != "" {
+		return fmt.Sprintf("%s:%s", l.source, l.company)
+	}
+	return l.source
+}
+
+// Harvester collects prospects from a group's member list, an event's attendee list
+// or a company's employees, as an alternative to search. Harvested profiles are stored
+// like search results, tagged with the group, event or company they came from.
+type Harvester struct {
+	page       pageops.Page
+	db         *storage.DB
+	timing     *stealth.TimingController
+	scroller   pageops.Scroller
+	clicker    pageops.Clicker
+	campaign   string
+	artifacts  *artifacts.Collector
+	checkpoint func() bool // reports whether harvesting should wrap up
+}
+
+// NewHarvester creates a new harvester
+func NewHarvester(page pageops.Page, db *storage.DB, timing *stealth.TimingController, scroller pageops.Scroller, clicker pageops.Clicker) *Harvester {
+	return &Harvester{
+		page:       page,
+		db:         db,
+		timing:     timing,
+		scroller:   scroller,
+		clicker:    clicker,
+		campaign:   config.DefaultCampaign,
+		checkpoint: func() bool { return false },
+	}
+}
+
+// SetCampaign sets the campaign that harvested profiles are tagged with
+func (h *Harvester) SetCampaign(name string) {
+	h.campaign = name
+}
+
+// SetArtifacts sets the collector that captures the page when harvesting fails
+func (h *Harvester) SetArtifacts(c *artifacts.Collector) {
+	h.artifacts = c
+}
+
+// SetCheckpoint sets the check made between scrolls; once it returns true harvesting stops
+func (h *Harvester) SetCheckpoint(fn func() bool) {
+	h.checkpoint = fn
+}
+
+// FromGroup reads up to max members of a group
+func (h *Harvester) FromGroup(groupURL string, max int) (*SearchSummary, error) {
+	match := groupIDPattern.FindStringSubmatch(groupURL)
+	if match == nil {
+		return nil, fmt.Errorf("not a group URL: %s", groupURL)
+	}
+
+	membersURL := fmt.Sprintf("https://www.linkedin.com/groups/%s/members/", match[1])
+	logger.Infof("Harvesting members of group %s", match[1])
+	if err := h.open(membersURL); err != nil {
+		return nil, err
+	}
+
+	return h.harvest(memberList{source: "group:" + match[1], item: selectors.MemberListItem}, max)
+}
+
+// FromEvent reads up to max attendees of an event
+func (h *Harvester) FromEvent(eventURL string, max int) (*SearchSummary, error) {
+	match := eventIDPattern.FindStringSubmatch(eventURL)
+	if match == nil {
+		return nil, fmt.Errorf("not an event URL: %s", eventURL)
+	}
+
+	logger.Infof("Harvesting attendees of event %s", match[1])
+	if err := h.open(fmt.Sprintf("https://www.linkedin.com/events/%s/", match[1])); err != nil {
+		return nil, err
+	}
+
+	// The attendee list opens from a link on the event page
+	link, err := selectors.FindFirst(h.page, selectors.EventAttendeesLink)
+	if err != nil {
+		err = fmt.Errorf("failed to find the attendee list: %w", err)
+		h.artifacts.Capture(h.page, "harvest", err)
+		return nil, err
+	}
+	if err := h.clicker.Click(link); err != nil {
+		return nil, fmt.Errorf("failed to open the attendee list: %w", err)
+	}
+	h.timing.Wait(h.timing.ShortPause())
+
+	return h.harvest(memberList{source: "event:" + match[1], item: selectors.MemberListItem}, max)
+}
+
+// FromCompany reads up to max employees from the People tab of a company page, narrowed
+// to the locations of filters. Numeric locations are LinkedIn geo IDs; others are picked
+// by name from the tab's list of where employees live. The result filters apply as they
+// do to search results, except location_must_include: the employee cards show no location.
+// Members are tagged with the company's name and stop at the wall LinkedIn puts up before
+// the rest of the list unless the account has Premium.
+func (h *Harvester) FromCompany(companyURL string, filters config.Filters, max int) (*SearchSummary, error) {
+	match := companySlugPattern.FindStringSubmatch(companyURL)
+	if match == nil {
+		return nil, fmt.Errorf("not a company URL: %s", companyURL)
+	}
+	slug := match[1]
+
+	resultFilters := filters
+	resultFilters.LocationMustInclude = nil
+	filter, err := NewPostFilter(resultFilters)
+	if err != nil {
+		return nil, err
+	}
+
+	var geoIDs, places []string
+	for _, location := range filters.Locations {
+		if geoIDPattern.MatchString(location) {
+			geoIDs = append(geoIDs, location)
+		} else {
+			places = append(places, location)
+		}
+	}
+
+	peopleURL := fmt.Sprintf("https://www.linkedin.com/company/%s/people/", slug)
+	if len(geoIDs) > 0 {
+		peopleURL += "?facetGeoRegion=" + strings.Join(geoIDs, ",")
+	}
+
+	logger.Infof("Harvesting employees of company %s", slug)
+	if err := h.open(peopleURL); err != nil {
+		return nil, err
+	}
+
+	company := slug
+	if el, err := selectors.FindFirst(h.page, selectors.CompanyName); err == nil {
+		if name, _ := el.Text(); strings.TrimSpace(name) != "" {
+			company = strings.TrimSpace(name)
+		}
+	}
+
+	for _, place := range places {
+		if err := h.pickLocation(place); err != nil {
+			h.artifacts.Capture(h.page, "harvest", err)
+			return nil, err
+		}
+	}
+
+	return h.harvest(memberList{
+		source:  SourceCompanyPage,
+		item:    selectors.CompanyEmployeeCard,
+		company: company,
+		wall:    selectors.CompanyPremiumWall,
+		filter:  filter,
+	}, max)
+}
+
+// pickLocation narrows the People tab to employees living in place, as listed under
+// where they live. Harvesting everyone instead would ignore the campaign's targeting,
+// so a place that isn't listed is an error.
+func (h *Harvester) pickLocation(place string) error {
+	facets, err := selectors.FindAll(h.page, selectors.CompanyLocationFacet)
+	if err != nil {
+		return fmt.Errorf("failed to find the employee locations: %w", err)
+	}
+
+	for _, facet := range facets {
+		text, _ := facet.Text()
+		if !strings.Contains(strings.ToLower(text), strings.ToLower(place)) {
+			continue
+		}
+		if err := h.clicker.Click(facet); err != nil {
+			return fmt.Errorf("failed to pick location %q: %w", place, err)
+		}
+		h.timing.Wait(h.timing.ShortPause())
+		return nil
+	}
+	return fmt.Errorf("location %q is not among the company's employee locations", place)
+}
+
+// open navigates to a member list page and waits for the first members
+func (h *Harvester) open(url string) error {
+	if err := h.page.Navigate(url); err != nil {
+		err = fmt.Errorf("failed to navigate to %s: %w", url, err)
+		h.artifacts.Capture(h.page, "harvest", err)
+		return err
+	}
+	if err := h.page.WaitLoad(); err != nil {
+		logger.Warnf("Failed to wait for page load: %v", err)
+	}
+	h.timing.Wait(h.timing.ThinkTime())
+	return nil
+}
+
+// harvest scrolls through the open member list, saving members it hasn't seen yet,
+// until max members were read, the list stops growing or hits its wall, or the
+// checkpoint says stop. The list is virtualized: rows scrolled past may be dropped, so
+// members are collected round by round rather than read at the end.
+func (h *Harvester) harvest(list memberList, max int) (*SearchSummary, error) {
+	started := time.Now()
+	source := list.name()
+	seen := make(map[string]bool)
+	summary := &SearchSummary{}
+
+	for stale := 0; len(seen) < max && stale < maxStaleRounds; {
+		items, err := selectors.FindAll(h.page, list.item)
+		if err != nil && len(seen) == 0 {
+			err = fmt.Errorf("failed to find members: %w", err)
+			h.artifacts.Capture(h.page, "harvest", err)
+			return nil, err
+		}
+
+		var batch []ProfileResult
+		for _, item := range items {
+			member := parseMember(item)
+			if member == nil {
+				continue
+			}
+			key := profileurl.Normalize(member.URL)
+			if seen[key] || len(seen) >= max {
+				continue
+			}
+			seen[key] = true
+			if list.company != "" {
+				member.Company = list.company
+			}
+			batch = append(batch, *member)
+		}
+
+		if len(batch) == 0 {
+			stale++
+		} else {
+			stale = 0
+			fresh, err := saveResults(h.db, h.campaign, list.source, batch, list.filter, false)
+			if err != nil {
+				logger.Warnf("Failed to save members of %s: %v", source, err)
+			}
+			summary.Results = append(summary.Results, batch...)
+			summary.NewProfiles += len(fresh)
+			logger.Infof("%s: %d members read, %d new", source, len(batch), len(fresh))
+		}
+
+		if h.checkpoint() {
+			logger.Infof("Search time budget used, stopping harvest of %s", source)
+			break
+		}
+		if list.wall != "" && selectors.Has(h.page, list.wall) {
+			logger.Infof("%s: LinkedIn shows no more members without Premium, stopping", source)
+			break
+		}
+
+		h.loadMore(items)
+	}
+
+	h.db.Audit(storage.AuditEntry{Action: "harvest", Details: fmt.Sprintf("%s: read %d members, %d new", source, len(summary.Results), summary.NewProfiles),
+		Result: storage.AuditSuccess, PageURL: pageops.CurrentURL(h.page), Started: started})
+	return summary, nil
+}
+
+// loadMore brings the next members into the list: scrolling past the last one
+// loads more in place, and some lists also need a "Show more" click
+func (h *Harvester) loadMore(items []pageops.Element) {
+	if len(items) > 0 {
+		if err := items[len(items)-1].ScrollIntoView(); err != nil {
+			logger.Debugf("Failed to scroll to the last member: %v", err)
+		}
+	}
+	if err := h.scroller.ScrollDown(600); err != nil {
+		logger.Debugf("Failed to scroll member list: %v", err)
+	}
+
+	if button, err := selectors.FindFirst(h.page, selectors.MemberShowMore); err == nil {
+		if err := h.clicker.Click(button); err != nil {
+			logger.Debugf("Failed to click show more: %v", err)
+		}
+	}
+
+	h.timing.Wait(h.timing.ShortPause())
+}
+
+// parseMember reads one row of a member list; nil when it has no profile link
+func parseMember(item pageops.Element) *ProfileResult {
+	link, err := selectors.FindFirst(item, selectors.MemberProfileLink)
+	if err != nil {
+		return nil
+	}
+	href, err := link.Property("href")
+	if err != nil || href == "" {
+		return nil
+	}
+
+	member := &ProfileResult{URL: stripQuery(href), MemberID: profileurl.MemberID(href)}
+
+	if el, err := selectors.FindFirst(item, selectors.MemberName); err == nil {
+		name, _ := el.Text()
+		member.Name = strings.TrimSpace(name)
+	}
+
+	if el, err := selectors.FindFirst(item, selectors.MemberHeadline); err == nil {
+		text, _ := el.Text()
+		member.JobTitle = strings.TrimSpace(text)
+		member.Headline = headline.Parse(member.JobTitle)
+	}
+
+	return member
+}
@@ -0,0 +1,215 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/headline"
+	"github.com/Tanukumar01/linkedin-automation/internal/insight"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/profileurl"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
+)
+
+// LinkedIn renders the first page of a people search with its data as JSON in hidden
+// <code> elements, the API responses the page would otherwise fetch. Each response lists
+// its entities under "included"; a search result is an EntityResultViewModel. The JSON
+// keeps its shape when the markup and its class names change, and carries the member's
+// URN with every result.
+
+// entityResultType ends the $type of a search result in the embedded JSON
+const entityResultType = "EntityResultViewModel"
+
+// payloadResponse is one embedded API response
+type payloadResponse struct {
+	Included []json.RawMessage `json:"included"`
+}
+
+// payloadText is a text view model, a text and what screen readers are told instead
+type payloadText struct {
+	Text              string `json:"text"`
+	AccessibilityText string `json:"accessibilityText"`
+}
+
+// payloadResult is the part of an EntityResultViewModel a result is read from
+type payloadResult struct {
+	Type              string       `json:"$type"`
+	EntityURN         string       `json:"entityUrn"` // wraps the member's urn:li:fsd_profile URN
+	NavigationURL     string       `json:"navigationUrl"`
+	Title             payloadText  `json:"title"`             // the name
+	PrimarySubtitle   *payloadText `json:"primarySubtitle"`   // the headline
+	SecondarySubtitle *payloadText `json:"secondarySubtitle"` // the location
+	Summary           *payloadText `json:"summary"`           // e.g. "Current: Engineer at Acme"
+	BadgeText         *payloadText `json:"badgeText"`         // "• 2nd"
+	Insights          []struct {
+		SimpleInsight *struct {
+			Title payloadText `json:"title"`
+		} `json:"simpleInsight"`
+	} `json:"insightsResolutionResults"`
+}
+
+// currentPrefix starts a summary naming the member's current position
+const currentPrefix = "Current:"
+
+// parsePayloadResults reads the search results from the JSON embedded in the page.
+// Payloads read on an earlier page are skipped: results LinkedIn loads in place, such as
+// after clicking Next, come without one and leave the first page's behind.
+func (s *Searcher) parsePayloadResults() ([]ProfileResult, error) {
+	elements, err := s.page.Elements("code")
+	if err != nil {
+		return nil, err
+	}
+
+	var payloads []string
+	for _, element := range elements {
+		text, err := element.Property("textContent")
+		if err != nil || !strings.Contains(text, entityResultType) {
+			continue
+		}
+
+		h := fnv.New64a()
+		h.Write([]byte(text))
+		if sum := h.Sum64(); !s.payloads[sum] {
+			s.payloads[sum] = true
+			payloads = append(payloads, text)
+		}
+	}
+	return decodeSearchPayloads(payloads), nil
+}
+
+// decodeSearchPayloads returns the profile results in embedded API responses, in the
+// order they are listed and each profile once. Responses that aren't JSON are skipped.
+func decodeSearchPayloads(payloads []string) []ProfileResult {
+	var results []ProfileResult
+	seen := make(map[string]bool)
+	for _, payload := range payloads {
+		var response payloadResponse
+		if err := json.Unmarshal([]byte(payload), &response); err != nil {
+			logger.Debugf("Skipping an embedded payload that isn't JSON: %v", err)
+			continue
+		}
+
+		for _, raw := range response.Included {
+			var entity payloadResult
+			if err := json.Unmarshal(raw, &entity); err != nil || !strings.HasSuffix(entity.Type, entityResultType) {
+				continue
+			}
+
+			result, ok := entity.profileResult()
+			if !ok || seen[result.URL] {
+				continue
+			}
+			seen[result.URL] = true
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// profileResult converts a search result entity, reporting false for one that isn't a
+// member's profile, such as a company or a group LinkedIn mixes into the results
+func (e payloadResult) profileResult() (ProfileResult, bool) {
+	u, err := url.Parse(e.NavigationURL)
+	if err != nil || !strings.HasPrefix(u.Path, "/in/") {
+		return ProfileResult{}, false
+	}
+
+	result := ProfileResult{
+		URL:      "https://www.linkedin.com" + strings.TrimSuffix(u.Path, "/"),
+		MemberID: profileurl.ParseMemberID(e.EntityURN),
+		Name:     strings.TrimSpace(e.Title.Text),
+	}
+	if result.MemberID == "" {
+		result.MemberID = profileurl.MemberID(e.NavigationURL)
+	}
+
+	if e.PrimarySubtitle != nil {
+		result.JobTitle = strings.TrimSpace(e.PrimarySubtitle.Text)
+		result.Headline = headline.Parse(result.JobTitle)
+	}
+	if e.SecondarySubtitle != nil {
+		result.Location = strings.TrimSpace(e.SecondarySubtitle.Text)
+	}
+	if e.Summary != nil {
+		result.Summary = strings.Join(strings.Fields(e.Summary.Text), " ")
+	}
+
+	// The summary names the current position when the headline doesn't match the search
+	if current, ok := strings.CutPrefix(result.Summary, currentPrefix); ok {
+		result.Company = headline.Parse(current).Company
+	}
+	if result.Company == "" {
+		result.Company = result.Headline.Company
+	}
+
+	if e.BadgeText != nil {
+		if result.Degree = insight.Degree(e.BadgeText.Text); result.Degree == 0 {
+			result.Degree = insight.Degree(e.BadgeText.AccessibilityText)
+		}
+	}
+	for _, i := range e.Insights {
+		if i.SimpleInsight == nil {
+			continue
+		}
+		if n := insight.MutualConnections(i.SimpleInsight.Title.Text); n > 0 {
+			result.MutualConnections = n
+			break
+		}
+	}
+	result.OpenToWork = insight.OpenToWork(result.JobTitle)
+
+	return result, true
+}
+
+// usePayload reports whether results are read from the embedded JSON first. Only the
+// standard search embeds it.
+func (s *Searcher) usePayload() bool {
+	_, standard := s.provider.(standardProvider)
+	return standard && s.config.Parser != config.SearchParserCSS
+}
+
+// openPage loads results page n of the current search, reporting false when the
+// pagination shows no next page
+func (s *Searcher) openPage(n int) (bool, error) {
+	nextButton, err := selectors.FindFirst(s.page, selectors.NextPageButton)
+	if err != nil {
+		return false, nil
+	}
+	if disabled, err := nextButton.Property("disabled"); err == nil && disabled == "true" {
+		return false, nil
+	}
+
+	u, err := url.Parse(pageops.CurrentURL(s.page))
+	if err != nil {
+		return false, fmt.Errorf("failed to read the search URL: %w", err)
+	}
+	query := u.Query()
+	query.Set("page", strconv.Itoa(n))
+	u.RawQuery = query.Encode()
+
+	if err := s.page.Navigate(u.String()); err != nil {
+		return false, fmt.Errorf("failed to open results page %d: %w", n, err)
+	}
+	if err := s.page.WaitLoad(); err != nil {
+		logger.Warnf("Failed to wait for next page load: %v", err)
+	}
+	return true, nil
+}
+
+// currentPage returns the results page number the URL shows, 1 when it names none
+func currentPage(current string) int {
+	u, err := url.Parse(current)
+	if err != nil {
+		return 1
+	}
+	if n, err := strconv.Atoi(u.Query().Get("page")); err == nil && n > 0 {
+		return n
+	}
+	return 1
+}
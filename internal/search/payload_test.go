@@ -0,0 +1,157 @@
+package search
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/clock"
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops/pagetest"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+)
+
+// searchPayload is an embedded API response listing Ada, a company LinkedIn mixed into
+// the results, and an entity that isn't a search result
+const searchPayload = `{"data":{},"included":[
+{"$type":"com.linkedin.voyager.dash.search.EntityResultViewModel",
+ "entityUrn":"urn:li:fsd_entityResultViewModel:(urn:li:fsd_profile:ACoAAB12cd_-ef,SEARCH_SRP,DEFAULT)",
+ "navigationUrl":"https://www.linkedin.com/in/ada-lovelace/?miniProfileUrn=urn%3Ali%3Afs_miniProfile%3AACoAAB12cd_-ef",
+ "title":{"text":" Ada Lovelace "},
+ "primarySubtitle":{"text":"Senior Analyst @ Engines Ltd | Mathematician"},
+ "secondarySubtitle":{"text":"London, England"},
+ "summary":{"text":"Current:   Analyst at\n Difference Co"},
+ "badgeText":{"text":"","accessibilityText":"2nd degree connection"},
+ "insightsResolutionResults":[{"simpleInsight":{"title":{"text":"Charles Babbage and 3 other mutual connections"}}}]},
+{"$type":"com.linkedin.voyager.dash.search.EntityResultViewModel",
+ "entityUrn":"urn:li:fsd_entityResultViewModel:(urn:li:fsd_company:1234,SEARCH_SRP,DEFAULT)",
+ "navigationUrl":"https://www.linkedin.com/company/engines-ltd/",
+ "title":{"text":"Engines Ltd"}},
+{"$type":"com.linkedin.voyager.dash.identity.profile.Profile","publicIdentifier":"ada-lovelace"}
+]}`
+
+// secondPayload lists Ada again and Grace, who is open to work
+const secondPayload = `{"included":[
+{"$type":"com.linkedin.voyager.dash.search.EntityResultViewModel",
+ "entityUrn":"urn:li:fsd_entityResultViewModel:(urn:li:fsd_profile:ACoAAB12cd_-ef,SEARCH_SRP,DEFAULT)",
+ "navigationUrl":"https://www.linkedin.com/in/ada-lovelace/",
+ "title":{"text":"Ada Lovelace"}},
+{"$type":"com.linkedin.voyager.dash.search.EntityResultViewModel",
+ "navigationUrl":"https://www.linkedin.com/in/ACoAAC98zy/",
+ "title":{"text":"Grace Hopper"},
+ "primarySubtitle":{"text":"Open to work: Compiler Engineer"},
+ "badgeText":{"text":"• 3rd+"}}
+]}`
+
+func TestDecodeSearchPayloads(t *testing.T) {
+	results := decodeSearchPayloads([]string{searchPayload, `{"included": [`, secondPayload})
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want Ada and Grace once each: %+v", len(results), results)
+	}
+
+	ada := results[0]
+	for _, tt := range []struct{ field, got, want string }{
+		{"URL", ada.URL, "https://www.linkedin.com/in/ada-lovelace"},
+		{"member ID", ada.MemberID, "ACoAAB12cd_-ef"},
+		{"name", ada.Name, "Ada Lovelace"},
+		{"job title", ada.JobTitle, "Senior Analyst @ Engines Ltd | Mathematician"},
+		{"location", ada.Location, "London, England"},
+		{"summary", ada.Summary, "Current: Analyst at Difference Co"},
+		{"company", ada.Company, "Difference Co"},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("Ada's %s = %q, want %q", tt.field, tt.got, tt.want)
+		}
+	}
+	if ada.Degree != 2 || ada.MutualConnections != 4 || ada.OpenToWork {
+		t.Errorf("Ada: degree %d, %d mutual, open to work %v", ada.Degree, ada.MutualConnections, ada.OpenToWork)
+	}
+
+	grace := results[1]
+	if grace.URL != "https://www.linkedin.com/in/ACoAAC98zy" || grace.MemberID != "ACoAAC98zy" {
+		t.Errorf("Grace: %s, member %q", grace.URL, grace.MemberID)
+	}
+	if grace.Degree != 3 || !grace.OpenToWork || grace.Company != "" {
+		t.Errorf("Grace: degree %d, open to work %v, company %q", grace.Degree, grace.OpenToWork, grace.Company)
+	}
+}
+
+// payloadPage is a results page with its data embedded as JSON next to the result cards
+func payloadPage(payloads ...string) string {
+	var b strings.Builder
+	b.WriteString(`<html><body>`)
+	for _, p := range payloads {
+		b.WriteString(`<code style="display: none">` + p + `</code>`)
+	}
+	b.WriteString(`<code style="display: none">{"request":"/voyager/api/graphql"}</code>`)
+	b.WriteString(strings.TrimPrefix(resultsPage, `<html><body>`))
+	return b.String()
+}
+
+func TestParseSearchResultsFromPayload(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		parser string
+		html   string
+		want   []string
+	}{
+		{"embedded JSON", config.SearchParserAuto, payloadPage(searchPayload), []string{"Ada Lovelace"}},
+		{"result cards without it", config.SearchParserAuto, payloadPage(), []string{"Ada Lovelace", "Grace Hopper"}},
+		{"JSON only", config.SearchParserJSON, payloadPage(), nil},
+		{"cards only", config.SearchParserCSS, payloadPage(searchPayload), []string{"Ada Lovelace", "Grace Hopper"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			page := pagetest.New("https://www.linkedin.com/search/results/people/?keywords=analyst", tt.html)
+			timing := stealth.NewTimingController(1, 2, 1, 2, 200)
+			timing.SetClock(clock.NewFake(time.Now()))
+			s := NewSearcher(page, &config.SearchConfig{Parser: tt.parser}, nil, timing, &pagetest.Scroller{}, &pagetest.Clicker{})
+			s.payloads = make(map[uint64]bool)
+
+			results, err := s.parseSearchResults()
+			if err != nil {
+				t.Fatalf("parseSearchResults: %v", err)
+			}
+			var names []string
+			for _, r := range results {
+				names = append(names, r.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("results %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePayloadResultsSkipsReadPayloads(t *testing.T) {
+	page := pagetest.New("https://www.linkedin.com/search/results/people/?keywords=analyst", payloadPage(searchPayload))
+	s := NewSearcher(page, &config.SearchConfig{Parser: config.SearchParserJSON}, nil, stealth.NewTimingController(1, 2, 1, 2, 200),
+		&pagetest.Scroller{}, &pagetest.Clicker{})
+	s.payloads = make(map[uint64]bool)
+
+	if results, err := s.parsePayloadResults(); err != nil || len(results) != 1 {
+		t.Fatalf("first page: %d results, err %v", len(results), err)
+	}
+
+	// Clicking Next leaves the first page's payload behind
+	page.SetHTML(payloadPage(searchPayload, secondPayload))
+	results, err := s.parsePayloadResults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].Name != "Ada Lovelace" || results[1].Name != "Grace Hopper" {
+		t.Fatalf("second page: %+v, want only the new payload's results", results)
+	}
+}
+
+func TestCurrentPage(t *testing.T) {
+	for url, want := range map[string]int{
+		"https://www.linkedin.com/search/results/people/?keywords=analyst":        1,
+		"https://www.linkedin.com/search/results/people/?keywords=analyst&page=4": 4,
+		"https://www.linkedin.com/search/results/people/?page=0":                  1,
+		"https://www.linkedin.com/search/results/people/?page=next":               1,
+	} {
+		if got := currentPage(url); got != want {
+			t.Errorf("currentPage(%q) = %d, want %d", url, got, want)
+		}
+	}
+}
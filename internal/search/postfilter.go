@@ -0,0 +1,107 @@
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+)
+
+// filterRule is one include or exclude list of the result filters
+type filterRule struct {
+	name     string
+	field    func(ProfileResult) string
+	include  bool // at least one pattern must match; otherwise none may
+	patterns []string
+	compiled []*regexp.Regexp
+}
+
+// PostFilter rejects parsed results by the title, location and company rules of the
+// search filters. Rejected results are still saved, flagged with the rule that matched.
+type PostFilter struct {
+	rules []filterRule
+}
+
+// NewPostFilter compiles the result rules of filters
+func NewPostFilter(filters config.Filters) (*PostFilter, error) {
+	rules := []filterRule{
+		{name: "title_must_include", field: titleField, include: true, patterns: filters.TitleMustInclude},
+		{name: "title_must_exclude", field: titleField, patterns: filters.TitleMustExclude},
+		{name: "location_must_include", field: locationField, include: true, patterns: filters.LocationMustInclude},
+		{name: "company_must_exclude", field: companyField, patterns: filters.CompanyMustExclude},
+	}
+
+	pf := &PostFilter{}
+	for _, rule := range rules {
+		if len(rule.patterns) == 0 {
+			continue
+		}
+		for _, pattern := range rule.patterns {
+			re, err := compilePattern(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compile %s pattern %q: %w", rule.name, pattern, err)
+			}
+			rule.compiled = append(rule.compiled, re)
+		}
+		pf.rules = append(pf.rules, rule)
+	}
+	return pf, nil
+}
+
+// Reject returns the rule a result fails, as "rule: pattern" for an exclude match, or ""
+// when it passes them all
+func (pf *PostFilter) Reject(result ProfileResult) string {
+	if pf == nil {
+		return ""
+	}
+	for _, rule := range pf.rules {
+		value := rule.field(result)
+		matched := -1
+		for i, re := range rule.compiled {
+			if re.MatchString(value) {
+				matched = i
+				break
+			}
+		}
+
+		switch {
+		case rule.include && matched < 0:
+			return rule.name
+		case !rule.include && matched >= 0:
+			return fmt.Sprintf("%s: %s", rule.name, rule.patterns[matched])
+		}
+	}
+	return ""
+}
+
+// compilePattern turns a filter pattern into a case-insensitive regular expression that
+// matches anywhere in a field: re: patterns are taken as they are, anything else is a
+// glob where * matches any run of characters and ? a single one
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if expr, ok := strings.CutPrefix(pattern, "re:"); ok {
+		return regexp.Compile("(?i)" + expr)
+	}
+
+	quoted := regexp.QuoteMeta(strings.TrimSpace(pattern))
+	quoted = strings.ReplaceAll(quoted, `\*`, ".*")
+	quoted = strings.ReplaceAll(quoted, `\?`, ".")
+	return regexp.Compile("(?i)" + quoted)
+}
+
+// titleField is the headline, which holds the job title
+func titleField(r ProfileResult) string {
+	return r.JobTitle
+}
+
+func locationField(r ProfileResult) string {
+	return r.Location
+}
+
+// companyField is every company the result names, the listed one and the one in the headline
+func companyField(r ProfileResult) string {
+	if r.Headline.Company == "" || strings.EqualFold(r.Headline.Company, r.Company) {
+		return r.Company
+	}
+	return r.Company + "\n" + r.Headline.Company
+}
@@ -0,0 +1,65 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/headline"
+)
+
+func TestPostFilterReject(t *testing.T) {
+	filters := config.Filters{
+		TitleMustInclude:    []string{"engineer", "re:^(vp|head) of"},
+		TitleMustExclude:    []string{"intern", "recruit*"},
+		LocationMustInclude: []string{"berlin", "*remote*"},
+		CompanyMustExclude:  []string{"Acme?Corp"},
+	}
+	pf, err := NewPostFilter(filters)
+	if err != nil {
+		t.Fatalf("NewPostFilter: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		result ProfileResult
+		want   string
+	}{
+		{"passes every rule", ProfileResult{JobTitle: "Senior Software Engineer", Location: "Berlin, Germany", Company: "Engines Ltd"}, ""},
+		{"regular expression include", ProfileResult{JobTitle: "VP of Engineering", Location: "Berlin"}, ""},
+		{"case-insensitive", ProfileResult{JobTitle: "STAFF ENGINEER", Location: "BERLIN"}, ""},
+		{"no title match", ProfileResult{JobTitle: "Product Manager", Location: "Berlin"}, "title_must_include"},
+		{"anchored expression", ProfileResult{JobTitle: "Chief of Staff to the VP of Sales", Location: "Berlin"}, "title_must_include"},
+		{"excluded title", ProfileResult{JobTitle: "Engineering Intern", Location: "Berlin"}, "title_must_exclude: intern"},
+		{"excluded title glob", ProfileResult{JobTitle: "Technical Recruiter for engineers", Location: "Berlin"}, "title_must_exclude: recruit*"},
+		{"location glob", ProfileResult{JobTitle: "Engineer", Location: "Germany (Remote)"}, ""},
+		{"location outside", ProfileResult{JobTitle: "Engineer", Location: "Munich, Germany"}, "location_must_include"},
+		{"excluded company", ProfileResult{JobTitle: "Engineer", Location: "Berlin", Company: "Acme Corp"}, "company_must_exclude: Acme?Corp"},
+		{"excluded company in the headline", ProfileResult{JobTitle: "Engineer @ AcmeXCorp", Location: "Berlin", Company: "Engines Ltd",
+			Headline: headline.Headline{Company: "AcmeXCorp"}}, "company_must_exclude: Acme?Corp"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pf.Reject(tt.result); got != tt.want {
+				t.Fatalf("Reject = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostFilterWithoutRules(t *testing.T) {
+	pf, err := NewPostFilter(config.Filters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var none *PostFilter
+	for _, f := range []*PostFilter{pf, none} {
+		if got := f.Reject(ProfileResult{JobTitle: "Intern"}); got != "" {
+			t.Fatalf("Reject = %q without rules", got)
+		}
+	}
+}
+
+func TestPostFilterBadPattern(t *testing.T) {
+	if _, err := NewPostFilter(config.Filters{TitleMustExclude: []string{"re:(unclosed"}}); err == nil {
+		t.Fatal("an invalid regular expression compiled")
+	}
+}
@@ -0,0 +1,71 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+)
+
+// Search providers, selected with search.provider
+const (
+	ProviderStandard       = "standard"
+	ProviderSalesNavigator = "sales_navigator"
+)
+
+// Provider is one LinkedIn people search product. Each has its own URLs, result
+// markup and pagination; the Searcher drives them the same way.
+type Provider interface {
+	// BuildURL returns the URL of the first results page
+	BuildURL(cfg *config.SearchConfig) string
+
+	// Selectors names the selector chains that locate results on the page
+	Selectors() ResultSelectors
+
+	// ParseResult reads one result element; a result without a URL is dropped
+	ParseResult(element pageops.Element) (*ProfileResult, error)
+
+	// NextPage moves to the next results page, reporting false when there is none
+	NextPage(s *Searcher) (bool, error)
+}
+
+// ResultSelectors are the selector names a provider's results are found with
+type ResultSelectors struct {
+	Loaded    string // present once results have rendered
+	NoResults string // shown when the search matched nobody
+	Item      string // one result
+}
+
+// newProvider returns the provider named in the config; the config is validated
+// on load, so anything else means the standard search
+func newProvider(name string) Provider {
+	if name == ProviderSalesNavigator {
+		return salesNavigatorProvider{}
+	}
+	return standardProvider{}
+}
+
+// keywordQuery combines the job titles, keywords and locations filters into one
+// keyword search; titles are quoted and joined with OR for flexibility
+func keywordQuery(filters config.Filters) string {
+	var parts []string
+
+	if len(filters.JobTitles) > 0 {
+		var titles []string
+		for _, t := range filters.JobTitles {
+			titles = append(titles, fmt.Sprintf("\"%s\"", t))
+		}
+		parts = append(parts, fmt.Sprintf("(%s)", strings.Join(titles, " OR ")))
+	}
+
+	if len(filters.Keywords) > 0 {
+		parts = append(parts, strings.Join(filters.Keywords, " "))
+	}
+
+	if len(filters.Locations) > 0 {
+		parts = append(parts, strings.Join(filters.Locations, " "))
+	}
+
+	return strings.Join(parts, " ")
+}
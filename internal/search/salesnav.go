@@ -0,0 +1,211 @@
+package search
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/headline"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/profileurl"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// salesNavSearchURL is the Sales Navigator lead search
+const salesNavSearchURL = "https://www.linkedin.com/sales/search/people"
+
+// salesNavSpotlights maps spotlight names to the filter type and value id Sales
+// Navigator puts in its query
+var salesNavSpotlights = map[string][2]string{
+	"changed_jobs":       {"RECENTLY_CHANGED_JOBS", "RPC"},
+	"posted_on_linkedin": {"POSTED_ON_LINKEDIN", "RPOL"},
+}
+
+// salesNavigatorProvider searches with Sales Navigator. Its results link to lead
+// pages; the public profile URL is stored alongside when a card shows it, otherwise
+// it is looked up with ResolveLead before the profile is contacted.
+type salesNavigatorProvider struct{}
+
+// BuildURL builds the lead search URL: the saved search when one is configured,
+// otherwise the keyword filters and spotlights in Sales Navigator's query syntax
+func (salesNavigatorProvider) BuildURL(cfg *config.SearchConfig) string {
+	params := url.Values{}
+
+	if id := cfg.SalesNavigator.SavedSearchID; id != "" {
+		params.Add("savedSearchId", id)
+		return salesNavSearchURL + "?" + params.Encode()
+	}
+
+	var fields []string
+	if query := keywordQuery(cfg.Filters); query != "" {
+		fields = append(fields, "keywords:"+strings.ReplaceAll(url.QueryEscape(query), "+", "%20"))
+	}
+
+	var filters []string
+	for _, name := range cfg.SalesNavigator.Spotlights {
+		if spotlight, ok := salesNavSpotlights[name]; ok {
+			filters = append(filters, fmt.Sprintf("(type:%s,values:List((id:%s,selectionType:INCLUDED)))", spotlight[0], spotlight[1]))
+		}
+	}
+	if len(filters) > 0 {
+		fields = append(fields, "filters:List("+strings.Join(filters, ",")+")")
+	}
+
+	if len(fields) > 0 {
+		params.Add("query", "("+strings.Join(fields, ",")+")")
+	}
+	return salesNavSearchURL + "?" + params.Encode()
+}
+
+// Selectors returns the selector names of the lead results list
+func (salesNavigatorProvider) Selectors() ResultSelectors {
+	return ResultSelectors{
+		Loaded:    selectors.SalesNavResultsLoaded,
+		NoResults: selectors.SalesNavNoResults,
+		Item:      selectors.SalesNavResultItem,
+	}
+}
+
+// ParseResult reads a lead card. Sales Navigator renders cards only once they
+// scroll into view, so the card is brought into view first.
+func (salesNavigatorProvider) ParseResult(element pageops.Element) (*ProfileResult, error) {
+	if err := element.ScrollIntoView(); err != nil {
+		logger.Debugf("Failed to scroll lead card into view: %v", err)
+	}
+
+	link, err := selectors.FindFirst(element, selectors.SalesNavLeadLink)
+	if err != nil {
+		return nil, err
+	}
+
+	href, err := link.Property("href")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ProfileResult{LeadURL: stripQuery(href)}
+	result.URL = result.LeadURL
+
+	if el, err := selectors.FindFirst(element, selectors.SalesNavPublicLink); err == nil {
+		if public, err := el.Property("href"); err == nil && public != "" {
+			result.URL = stripQuery(public)
+		}
+	}
+
+	if el, err := selectors.FindFirst(element, selectors.SalesNavLeadName); err == nil {
+		name, _ := el.Text()
+		result.Name = strings.TrimSpace(name)
+	}
+
+	if el, err := selectors.FindFirst(element, selectors.SalesNavLeadTitle); err == nil {
+		title, _ := el.Text()
+		result.JobTitle = strings.TrimSpace(title)
+	}
+
+	if el, err := selectors.FindFirst(element, selectors.SalesNavLeadCompany); err == nil {
+		company, _ := el.Text()
+		result.Company = strings.TrimSpace(company)
+	}
+
+	if el, err := selectors.FindFirst(element, selectors.SalesNavLeadLocation); err == nil {
+		loc, _ := el.Text()
+		result.Location = strings.TrimSpace(loc)
+	}
+
+	// Title and company are separate fields here, not a free-form headline
+	result.Headline = headline.Headline{Raw: result.JobTitle, Title: result.JobTitle, Company: result.Company}
+
+	return result, nil
+}
+
+// NextPage clicks Next below the lead list and waits for the new leads; the list
+// is replaced in place, so there is no page load to wait for
+func (salesNavigatorProvider) NextPage(s *Searcher) (bool, error) {
+	s.timing.Wait(s.timing.ShortPause())
+
+	hasNext, err := s.clickNext(selectors.SalesNavNextButton)
+	if err != nil || !hasNext {
+		return hasNext, err
+	}
+
+	if _, err := selectors.WaitFirst(s.page, selectors.SalesNavResultsLoaded, 15*time.Second); err != nil {
+		logger.Warnf("Leads didn't appear on the next page: %v", err)
+	}
+	return true, nil
+}
+
+// ResolveLead looks up the public profile URL of a prospect stored under its Sales
+// Navigator lead URL and stores it in place of the lead URL, so connection and
+// messaging flows work off the public profile. It reports false when the profile was
+// already stored under its public URL; the lead row is dropped then.
+func (s *Searcher) ResolveLead(profile *storage.SearchResult) (bool, error) {
+	publicURL, err := s.leadPublicURL(profile.ProfileURL)
+	if err != nil {
+		return false, err
+	}
+
+	resolved, err := s.db.ResolveLeadURL(profile.ID, publicURL)
+	if err != nil {
+		return false, err
+	}
+	if !resolved {
+		logger.Infof("Lead %s is already stored as %s", profile.ProfileName, publicURL)
+		return false, nil
+	}
+
+	logger.Debugf("Resolved lead %s to %s", profile.ProfileURL, publicURL)
+	if profile.LeadURL == "" {
+		profile.LeadURL = profile.ProfileURL
+	}
+	profile.ProfileURL = publicURL
+	return true, nil
+}
+
+// leadPublicURL opens a lead page and reads the link to the public profile,
+// opening the actions menu when the link isn't on the page itself
+func (s *Searcher) leadPublicURL(leadURL string) (string, error) {
+	if err := s.page.Navigate(leadURL); err != nil {
+		return "", fmt.Errorf("failed to open lead page: %w", err)
+	}
+	if err := s.page.WaitLoad(); err != nil {
+		logger.Warnf("Failed to wait for lead page load: %v", err)
+	}
+	s.timing.Wait(s.timing.ThinkTime())
+
+	link, err := selectors.FindFirst(s.page, selectors.SalesNavPublicLink)
+	if err != nil {
+		menu, menuErr := selectors.FindFirst(s.page, selectors.SalesNavLeadOverflow)
+		if menuErr != nil {
+			return "", fmt.Errorf("failed to find the public profile link: %w", err)
+		}
+		if err := s.clicker.Click(menu); err != nil {
+			return "", fmt.Errorf("failed to open lead actions: %w", err)
+		}
+
+		link, err = selectors.WaitFirst(s.page, selectors.SalesNavPublicLink, 5*time.Second)
+		if err != nil {
+			return "", fmt.Errorf("failed to find the public profile link: %w", err)
+		}
+	}
+
+	href, err := link.Property("href")
+	if err != nil {
+		return "", fmt.Errorf("failed to read the public profile link: %w", err)
+	}
+	if profileurl.IsSalesLead(href) || !strings.Contains(href, "/in/") {
+		return "", fmt.Errorf("unexpected public profile link: %s", href)
+	}
+	return stripQuery(href), nil
+}
+
+// stripQuery drops the query string and fragment of a URL
+func stripQuery(u string) string {
+	if idx := strings.IndexAny(u, "?#"); idx != -1 {
+		return u[:idx]
+	}
+	return u
+}
@@ -2,146 +2,210 @@ package search
 
 import (
 	"fmt"
-	"net/url"
-	"os"
-	"strings"
 	"time"
 
-	"github.com/go-rod/rod"
-	"github.com/go-rod/rod/lib/proto"
-
+	"github.com/Tanukumar01/linkedin-automation/internal/artifacts"
 	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/headline"
 	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
 	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
 	"github.com/Tanukumar01/linkedin-automation/internal/storage"
 )
 
 // Searcher handles LinkedIn search operations
 type Searcher struct {
-	page     *rod.Page
-	config   *config.SearchConfig
-	db       *storage.DB
-	timing   *stealth.TimingController
-	scroller *stealth.Scroller
+	page       pageops.Page
+	config     *config.SearchConfig
+	db         *storage.DB
+	timing     *stealth.TimingController
+	scroller   pageops.Scroller
+	clicker    pageops.Clicker
+	provider   Provider
+	campaign   string
+	artifacts  *artifacts.Collector
+	checkpoint func() bool // reports whether the search should wrap up
+
+	monitorPages int             // result pages a monitor reads whole; 0 searches for prospects
+	payloads     map[uint64]bool // hashes of the embedded result payloads already read
 }
 
 // ProfileResult represents a search result
 type ProfileResult struct {
-	URL      string
+	URL      string // public /in/ URL, or the lead URL until that is known
+	LeadURL  string // Sales Navigator lead URL, when found there
+	MemberID string // LinkedIn's entity ID for the member, when the result reveals it
 	Name     string
-	JobTitle string
+	JobTitle string // the raw headline
 	Company  string
 	Location string
+	Headline headline.Headline
+
+	Summary           string // snippet under the headline, often a matching part of the profile
+	MutualConnections int
+	Premium           bool
+	OpenToWork        bool
+	Degree            int // of connection, from the result's badge; 0 when it shows none
+}
+
+// SourceSearch tags profiles found by search; harvested ones are tagged group:<id> or event:<id>
+const SourceSearch = "search"
+
+// SearchSummary describes the outcome of a search run
+type SearchSummary struct {
+	Results     []ProfileResult
+	NewProfiles int             // profiles that weren't already in the database
+	New         []ProfileResult // those profiles, the ones the result filters rejected left out
 }
 
 // NewSearcher creates a new searcher
-func NewSearcher(page *rod.Page, cfg *config.SearchConfig, db *storage.DB, timing *stealth.TimingController, scroller *stealth.Scroller) *Searcher {
+func NewSearcher(page pageops.Page, cfg *config.SearchConfig, db *storage.DB, timing *stealth.TimingController, scroller pageops.Scroller, clicker pageops.Clicker) *Searcher {
 	return &Searcher{
-		page:     page,
-		config:   cfg,
-		db:       db,
-		timing:   timing,
-		scroller: scroller,
+		page:       page,
+		config:     cfg,
+		db:         db,
+		timing:     timing,
+		scroller:   scroller,
+		clicker:    clicker,
+		provider:   newProvider(cfg.Provider),
+		campaign:   config.DefaultCampaign,
+		checkpoint: func() bool { return false },
 	}
 }
 
+// SetCampaign sets the campaign that found profiles are tagged with
+func (s *Searcher) SetCampaign(name string) {
+	s.campaign = name
+}
+
+// SetArtifacts sets the collector that captures the page when a search fails
+func (s *Searcher) SetArtifacts(c *artifacts.Collector) {
+	s.artifacts = c
+}
+
+// SetCheckpoint sets the check made between result pages; once it returns true the search stops
+func (s *Searcher) SetCheckpoint(fn func() bool) {
+	s.checkpoint = fn
+}
+
+// SetMonitor has the search read its first pages whole instead of stopping once it found
+// max_results new profiles, since LinkedIn reorders results and new matches can turn up on
+// any page. What it finds is stored monitor-only, so no one is contacted.
+func (s *Searcher) SetMonitor(pages int) {
+	s.monitorPages = pages
+}
+
 // Search performs a LinkedIn search
-func (s *Searcher) Search() ([]ProfileResult, error) {
-	logger.Info("Starting LinkedIn search")
+func (s *Searcher) Search() (*SearchSummary, error) {
+	started := time.Now()
+	logger.Infof("Starting LinkedIn search for campaign %s", s.campaign)
+	s.payloads = make(map[uint64]bool)
 
 	// Build search URL
-	searchURL := s.buildSearchURL()
+	searchURL := s.provider.BuildURL(s.config)
 	logger.Infof("Search URL: %s", searchURL)
 
 	// Navigate to search
 	logger.Infof("Navigating to search URL...")
 	if err := s.page.Navigate(searchURL); err != nil {
-		return nil, fmt.Errorf("failed to navigate to search: %w", err)
+		err = fmt.Errorf("failed to navigate to search: %w", err)
+		s.artifacts.Capture(s.page, "search", err)
+		return nil, err
 	}
 
 	// Use a more robust wait - wait for the search results container instead of full page load
 	logger.Info("Waiting for search results to appear...")
-	err := s.page.Timeout(30*time.Second).WaitElementsMoreThan(".reusable-search__result-container, .entity-result", 0)
-	if err != nil {
+	if _, err := selectors.WaitFirst(s.page, s.provider.Selectors().Loaded, 30*time.Second); err != nil {
 		logger.Warnf("Search results container didn't appear in 30s: %v. Continuing anyway...", err)
+		s.artifacts.Capture(s.page, "search", err)
 	}
 
 	s.timing.Wait(s.timing.ThinkTime())
 
-	// Take a screenshot for debugging search results
-	if data, sErr := s.page.Screenshot(true, nil); sErr == nil {
-		os.WriteFile("search_results_debug.png", data, 0644)
-		logger.Infof("Search results screenshot saved to search_results_debug.png")
-	}
-
 	// Scroll to load results
 	logger.Info("Scrolling to ensure results are loaded...")
-	if err := s.scroller.ScrollDown(s.page, 800); err != nil {
+	if err := s.scroller.ScrollDown(800); err != nil {
 		logger.Warnf("Failed to scroll: %v", err)
 	}
 
 	// Check for "No results found"
-	if hasNoResults, _, _ := s.page.Has("h2.artdeco-empty-state__headline"); hasNoResults {
+	if selectors.Has(s.page, s.provider.Selectors().NoResults) {
 		logger.Warn("LinkedIn reported no results for this search.")
-		return nil, nil
+		return &SearchSummary{}, nil
 	}
 
 	s.timing.Wait(s.timing.ShortPause())
 
-	var allResults []ProfileResult
-	resultsCollected := 0
+	postFilter, err := NewPostFilter(s.config.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	var allResults, fresh []ProfileResult
+	newProfiles := 0
+	emptyPages := 0
+	page := 1
 
-	// Paginate through results
-	for resultsCollected < s.config.MaxResults {
+	// Paginate through results until we have enough new profiles, or a monitor has read its pages
+	for s.monitorPages > 0 || newProfiles < s.config.MaxResults {
 		// Parse current page
 		results, err := s.parseSearchResults()
 		if err != nil {
 			logger.Errorf("Failed to parse search results: %v", err)
+			s.artifacts.Capture(s.page, "search", err)
 			break
 		}
 
 		if len(results) == 0 {
 			logger.Info("No more results found")
+			if page == 1 {
+				s.artifacts.Capture(s.page, "search", fmt.Errorf("no results parsed on the first page"))
+			}
 			break
 		}
 
-		// Save results to database
-		for _, result := range results {
-			logger.Infof("Processing found profile: %s (%s)", result.Name, result.URL)
-			// Check if already contacted
-			contacted, err := s.db.IsProfileContacted(result.URL)
-			if err != nil {
-				logger.Warnf("Failed to check if profile contacted: %v", err)
-			}
+		pageNew, err := saveResults(s.db, s.campaign, SourceSearch, results, postFilter, s.monitorPages > 0)
+		if err != nil {
+			logger.Warnf("Failed to save search results for page %d: %v", page, err)
+		}
+		inserted := len(pageNew)
 
-			// Save to database
-			searchResult := &storage.SearchResult{
-				ProfileURL:  result.URL,
-				ProfileName: result.Name,
-				JobTitle:    result.JobTitle,
-				Company:     result.Company,
-				Location:    result.Location,
-				FoundAt:     time.Now(),
-				Contacted:   contacted,
+		allResults = append(allResults, results...)
+		fresh = append(fresh, pageNew...)
+		newProfiles += inserted
+
+		logger.Infof("Page %d: %d parsed, %d new, %d already known", page, len(results), inserted, len(results)-inserted)
+
+		if s.monitorPages > 0 {
+			if page >= s.monitorPages {
+				break
+			}
+		} else {
+			// Check if we have enough results
+			if newProfiles >= s.config.MaxResults {
+				break
 			}
 
-			if err := s.db.SaveSearchResult(searchResult); err != nil {
-				logger.Warnf("Failed to save search result: %v", err)
+			// Stop when the search keeps returning profiles we already have
+			if inserted == 0 {
+				emptyPages++
+			} else {
+				emptyPages = 0
+			}
+			if s.config.StopAfterEmptyPages > 0 && emptyPages >= s.config.StopAfterEmptyPages {
+				logger.Infof("No new profiles on the last %d pages, stopping search", emptyPages)
+				break
 			}
 		}
 
-		allResults = append(allResults, results...)
-		resultsCollected += len(results)
-
-		logger.Infof("Collected %d results so far", resultsCollected)
-
-		// Check if we have enough results
-		if resultsCollected >= s.config.MaxResults {
+		if s.checkpoint() {
+			logger.Infof("Search time budget used, stopping after page %d", page)
 			break
 		}
 
 		// Try to go to next page
-		hasNext, err := s.goToNextPage()
+		hasNext, err := s.provider.NextPage(s)
 		if err != nil || !hasNext {
 			logger.Info("No more pages available")
 			break
@@ -150,48 +214,85 @@ func (s *Searcher) Search() ([]ProfileResult, error) {
 		// Random delay between pages
 		delay := time.Duration(s.config.PaginationDelayMin+int(time.Now().Unix())%(s.config.PaginationDelayMax-s.config.PaginationDelayMin+1)) * time.Second
 		s.timing.Wait(delay)
+		page++
 	}
 
-	logger.Infof("Search completed. Total results: %d", len(allResults))
+	logger.Infof("Search completed. Total results: %d (%d new)", len(allResults), newProfiles)
 
-	// Log activity
-	s.db.LogActivity("search", fmt.Sprintf("Found %d profiles", len(allResults)))
+	s.db.Audit(storage.AuditEntry{Action: "search", Details: fmt.Sprintf("Found %d profiles (%d new) on %d pages", len(allResults), newProfiles, page),
+		Result: storage.AuditSuccess, PageURL: pageops.CurrentURL(s.page), Started: started})
 
-	return allResults, nil
+	return &SearchSummary{
+		Results:     allResults,
+		NewProfiles: newProfiles,
+		New:         fresh,
+	}, nil
 }
 
-// buildSearchURL builds the LinkedIn search URL with filters
-func (s *Searcher) buildSearchURL() string {
-	baseURL := "https://www.linkedin.com/search/results/people/?"
-
-	var parts []string
+// saveResults stores found profiles under a campaign and source, all at once so a
+// crash never leaves half a page behind, and returns those that were new. Results the
+// post-filter rejects are stored flagged with the rule and don't count as new.
+// Monitor-only results are stored without being queued for outreach.
+func saveResults(db *storage.DB, campaign, source string, results []ProfileResult, postFilter *PostFilter, monitorOnly bool) ([]ProfileResult, error) {
+	rows := make([]*storage.SearchResult, 0, len(results))
+	for _, result := range results {
+		logger.Debugf("Processing found profile: %s (%s)", result.Name, result.URL)
+		// Check if already contacted
+		contacted, err := db.IsProfileContacted(result.URL, result.MemberID)
+		if err != nil {
+			logger.Warnf("Failed to check if profile contacted: %v", err)
+		}
 
-	// 1. Handle Job Titles (Join with OR for flexibility)
-	if len(s.config.Filters.JobTitles) > 0 {
-		var titles []string
-		for _, t := range s.config.Filters.JobTitles {
-			titles = append(titles, fmt.Sprintf("\"%s\"", t))
+		rows = append(rows, &storage.SearchResult{
+			ProfileURL:  result.URL,
+			MemberID:    result.MemberID,
+			ProfileName: result.Name,
+			JobTitle:    result.JobTitle,
+			Company:     result.Company,
+			Location:    result.Location,
+			Campaign:    campaign,
+			FoundAt:     time.Now(),
+			Contacted:   contacted,
+
+			PrimaryTitle:   result.Headline.Title,
+			PrimaryCompany: result.Headline.Company,
+
+			LeadURL:           result.LeadURL,
+			Summary:           result.Summary,
+			MutualConnections: result.MutualConnections,
+			Premium:           result.Premium,
+			OpenToWork:        result.OpenToWork,
+			Degree:            result.Degree,
+			Source:            source,
+			MonitorOnly:       monitorOnly,
+		})
+
+		if reason := postFilter.Reject(result); reason != "" {
+			logger.Debugf("Filtered out %s: %s", result.Name, reason)
+			rows[len(rows)-1].Filtered = true
+			rows[len(rows)-1].FilterReason = reason
 		}
-		parts = append(parts, fmt.Sprintf("(%s)", strings.Join(titles, " OR ")))
 	}
 
-	// 2. Add basic keywords
-	if len(s.config.Filters.Keywords) > 0 {
-		parts = append(parts, strings.Join(s.config.Filters.Keywords, " "))
+	if _, err := db.SaveSearchResults(rows); err != nil {
+		return nil, err
 	}
 
-	// 3. Add locations
-	if len(s.config.Filters.Locations) > 0 {
-		parts = append(parts, strings.Join(s.config.Filters.Locations, " "))
+	var fresh []ProfileResult
+	filtered := 0
+	for i, row := range rows {
+		switch {
+		case row.ID == 0:
+		case row.Filtered:
+			filtered++
+		default:
+			fresh = append(fresh, results[i])
+		}
 	}
-
-	params := url.Values{}
-	if len(parts) > 0 {
-		params.Add("keywords", strings.Join(parts, " "))
+	if filtered > 0 {
+		logger.Infof("%d new profiles filtered out by the result rules", filtered)
 	}
-	params.Add("origin", "GLOBAL_SEARCH_HEADER")
-
-	return baseURL + params.Encode()
+	return fresh, nil
 }
 
 // parseSearchResults parses search results from current page
@@ -199,31 +300,31 @@ func (s *Searcher) parseSearchResults() ([]ProfileResult, error) {
 	// Wait for results to load and ensure page is ready
 	s.timing.Wait(s.timing.ShortPause())
 
-	// LinkedIn search results are in a list
-	// Try multiple selectors as LinkedIn often AB tests layouts
-	selectors := []string{
-		"li.reusable-search__result-container",
-		"div.search-results-container li",
-		".entity-result",
-	}
-
-	var elements rod.Elements
-	var err error
-	for _, selector := range selectors {
-		elements, err = s.page.Elements(selector)
-		if err == nil && len(elements) > 0 {
-			break
+	if s.usePayload() {
+		results, err := s.parsePayloadResults()
+		switch {
+		case err == nil && len(results) > 0:
+			logger.Debugf("Read %d results from the page's embedded JSON", len(results))
+			return results, nil
+		case s.config.Parser == config.SearchParserJSON:
+			return results, err
+		case err != nil:
+			logger.Debugf("Failed to read the embedded search results: %v", err)
 		}
+		logger.Debugf("No embedded search results on this page, reading the result cards")
 	}
 
-	if err != nil || len(elements) == 0 {
+	// LinkedIn search results are in a list
+	// The selector chain covers the layouts LinkedIn AB tests
+	elements, err := selectors.FindAll(s.page, s.provider.Selectors().Item)
+	if err != nil {
 		return nil, fmt.Errorf("failed to find result elements: %w", err)
 	}
 
 	var results []ProfileResult
 
 	for _, element := range elements {
-		result, err := s.parseResultElement(element)
+		result, err := s.provider.ParseResult(element)
 		if err != nil {
 			continue
 		}
@@ -236,98 +337,28 @@ func (s *Searcher) parseSearchResults() ([]ProfileResult, error) {
 	return results, nil
 }
 
-// parseResultElement parses a single result element
-func (s *Searcher) parseResultElement(element *rod.Element) (*ProfileResult, error) {
-	result := &ProfileResult{}
-
-	// Get profile URL and Name (they are usually in the same link)
-	// Look for the primary title link
-	linkElement, err := element.Element("a.app-aware-link")
-	if err != nil {
-		// Try a more generic link if specific one fails
-		linkElement, err = element.Element("a[href*='/in/']")
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	href, err := linkElement.Property("href")
-	if err != nil {
-		return nil, err
-	}
-
-	result.URL = href.String()
-
-	// Clean URL (remove query parameters)
-	if idx := strings.Index(result.URL, "?"); idx != -1 {
-		result.URL = result.URL[:idx]
-	}
-
-	// Get name - often inside the link in a span
-	nameElement, err := linkElement.Element("span[aria-hidden='true']")
-	if err == nil {
-		name, _ := nameElement.Text()
-		result.Name = strings.TrimSpace(name)
-	}
-
-	// If name still empty, try looking in the whole element
-	if result.Name == "" {
-		if nameEl, err := element.Element(".entity-result__title-text"); err == nil {
-			name, _ := nameEl.Text()
-			result.Name = strings.TrimSpace(name)
-		}
-	}
-
-	// Get job title
-	if titleElement, err := element.Element(".entity-result__primary-subtitle"); err == nil {
-		title, _ := titleElement.Text()
-		result.JobTitle = strings.TrimSpace(title)
-	}
-
-	// Get location
-	if locElement, err := element.Element(".entity-result__secondary-subtitle"); err == nil {
-		loc, _ := locElement.Text()
-		result.Location = strings.TrimSpace(loc)
-	}
-
-	return result, nil
-}
-
-// goToNextPage navigates to the next page of results
-func (s *Searcher) goToNextPage() (bool, error) {
-	// Scroll to bottom to load pagination
-	if err := s.scroller.ScrollToBottom(s.page); err != nil {
-		logger.Warnf("Failed to scroll to bottom: %v", err)
-	}
-
-	s.timing.Wait(s.timing.ShortPause())
-
-	// Look for "Next" button - try multiple ways
-	var nextButton *rod.Element
-	var err error
-
-	// Try finding by aria-label first
-	nextButton, err = s.page.Element("button[aria-label*='Next']")
-	if err != nil {
-		// Try finding by text
-		nextButton, err = s.page.ElementR("button", "(?i)Next")
-	}
-
+// clickNext clicks the pagination button found by the named selector chain and waits
+// for the next page, reporting false when the button is missing or disabled
+func (s *Searcher) clickNext(name string) (bool, error) {
+	nextButton, err := selectors.FindFirst(s.page, name)
 	if err != nil {
 		return false, nil // No next button found
 	}
 
 	// Check if button is disabled
 	disabled, err := nextButton.Property("disabled")
-	if err == nil && disabled.Bool() {
+	if err == nil && disabled == "true" {
 		return false, nil
 	}
 
 	// Ensure button is in view
-	nextButton.MustScrollIntoView()
+	if err := nextButton.ScrollIntoView(); err != nil {
+		logger.Warnf("Failed to scroll next button into view: %v", err)
+	}
 
 	// Click next button
-	if err := nextButton.Click(proto.InputMouseButtonLeft, 1); err != nil {
+	pageops.DismissInterruptions(s.page, s.clicker)
+	if err := s.clicker.Click(nextButton); err != nil {
 		return false, err
 	}
 
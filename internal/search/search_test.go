@@ -0,0 +1,256 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/clock"
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops/pagetest"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger("error", "console", "stdout", logger.Rotation{})
+	os.Exit(m.Run())
+}
+
+// resultsPage is a page of standard search results as LinkedIn renders the cards
+const resultsPage = `<html><body><div class="search-results-container"><ul>
+<li class="reusable-search__result-container">
+  <div class="entity-result">
+    <span class="entity-result__title-text">
+      <a class="app-aware-link" href="/in/ada-lovelace?miniProfileUrn=urn%3Ali%3Afs_miniProfile%3AACoAAB12cd_-ef">
+        <span aria-hidden="true">Ada Lovelace</span><span class="visually-hidden">View Ada Lovelace's profile</span>
+      </a>
+    </span>
+    <span class="entity-result__badge-text"><span aria-hidden="true">• 2nd</span></span>
+    <div class="entity-result__primary-subtitle">Senior Analyst @ Engines Ltd | Mathematician</div>
+    <div class="entity-result__secondary-subtitle">London, England</div>
+    <p class="entity-result__summary">Current:   Analyst at
+      Engines Ltd</p>
+    <div class="entity-result__insights"><span class="entity-result__simple-insight-text">Charles Babbage and 3 other mutual connections</span></div>
+    <li-icon type="premium-badge"></li-icon>
+  </div>
+</li>
+<li class="reusable-search__result-container">
+  <div class="entity-result">
+    <span class="entity-result__title-text"><a class="app-aware-link" href="https://www.linkedin.com/in/grace-hopper/"><span aria-hidden="true">Grace Hopper</span></a></span>
+    <span class="entity-result__badge-text">3rd+ degree connection</span>
+    <div class="entity-result__primary-subtitle">Open to work: Compiler Engineer</div>
+  </div>
+</li>
+<li class="reusable-search__result-container">
+  <div class="entity-result"><span class="entity-result__title-text">LinkedIn Member</span></div>
+</li>
+</ul></div></body></html>`
+
+func TestParseSearchResults(t *testing.T) {
+	page := pagetest.New("https://www.linkedin.com/search/results/people/?keywords=analyst", resultsPage)
+	timing := stealth.NewTimingController(1, 2, 1, 2, 200)
+	timing.SetClock(clock.NewFake(time.Now()))
+
+	s := NewSearcher(page, &config.SearchConfig{Parser: config.SearchParserCSS}, nil, timing, &pagetest.Scroller{}, &pagetest.Clicker{})
+	results, err := s.parseSearchResults()
+	if err != nil {
+		t.Fatalf("parseSearchResults: %v", err)
+	}
+
+	// The out-of-network member has no profile link and is left out
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2: %+v", len(results), results)
+	}
+
+	for i, tt := range []struct {
+		url, memberID, name, title, location, summary string
+		mutual, degree                                int
+		premium, openToWork                           bool
+	}{
+		{
+			url:      "https://www.linkedin.com/in/ada-lovelace",
+			memberID: "ACoAAB12cd_-ef",
+			name:     "Ada Lovelace",
+			title:    "Senior Analyst @ Engines Ltd | Mathematician",
+			location: "London, England",
+			summary:  "Current: Analyst at Engines Ltd",
+			mutual:   4,
+			degree:   2,
+			premium:  true,
+		},
+		{
+			url:        "https://www.linkedin.com/in/grace-hopper/",
+			name:       "Grace Hopper",
+			title:      "Open to work: Compiler Engineer",
+			degree:     3,
+			openToWork: true,
+		},
+	} {
+		got := results[i]
+		if got.URL != tt.url || got.MemberID != tt.memberID || got.Name != tt.name {
+			t.Errorf("result %d = %q %q %q, want %q %q %q", i, got.URL, got.MemberID, got.Name, tt.url, tt.memberID, tt.name)
+		}
+		if got.JobTitle != tt.title || got.Location != tt.location || got.Summary != tt.summary {
+			t.Errorf("result %d title %q, location %q, summary %q", i, got.JobTitle, got.Location, got.Summary)
+		}
+		if got.MutualConnections != tt.mutual || got.Degree != tt.degree {
+			t.Errorf("result %d mutual %d, degree %d; want %d, %d", i, got.MutualConnections, got.Degree, tt.mutual, tt.degree)
+		}
+		if got.Premium != tt.premium || got.OpenToWork != tt.openToWork {
+			t.Errorf("result %d premium %v, open to work %v", i, got.Premium, got.OpenToWork)
+		}
+	}
+
+	if h := results[0].Headline; h.Title != "Senior Analyst" || h.Company != "Engines Ltd" {
+		t.Errorf("headline = %+v", h)
+	}
+}
+
+// newSearcher returns a searcher reading result cards over a fresh database, its page
+// showing html
+func newSearcher(t *testing.T, cfg *config.SearchConfig, html string) (*Searcher, *pagetest.Page, *storage.DB) {
+	t.Helper()
+
+	db, err := storage.NewDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	cfg.Parser = config.SearchParserCSS
+	page := pagetest.New("about:blank", html)
+	timing := stealth.NewTimingController(1, 2, 1, 2, 200)
+	timing.SetClock(clock.NewFake(time.Now()))
+	return NewSearcher(page, cfg, db, timing, &pagetest.Scroller{}, &pagetest.Clicker{}), page, db
+}
+
+// names returns the results' names
+func names(results []ProfileResult) []string {
+	var names []string
+	for _, r := range results {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+func TestSaveResultsReportsNewProfiles(t *testing.T) {
+	s, _, db := newSearcher(t, &config.SearchConfig{}, resultsPage)
+	results, err := s.parseSearchResults()
+	if err != nil || len(results) != 2 {
+		t.Fatalf("parseSearchResults = %d results, %v", len(results), err)
+	}
+	postFilter, err := NewPostFilter(config.Filters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fresh, err := saveResults(db, "default", SourceSearch, results, postFilter, false)
+	if err != nil {
+		t.Fatalf("saveResults: %v", err)
+	}
+	if got := names(fresh); strings.Join(got, ",") != "Ada Lovelace,Grace Hopper" {
+		t.Fatalf("first save: new = %v, want both", got)
+	}
+
+	// LinkedIn showing the same page again, as it does when it reorders results
+	fresh, err = saveResults(db, "default", SourceSearch, results, postFilter, false)
+	if err != nil {
+		t.Fatalf("saveResults: %v", err)
+	}
+	if len(fresh) != 0 {
+		t.Fatalf("second save: new = %v, want none", names(fresh))
+	}
+
+	// A page with one known and one unknown profile
+	page := append([]ProfileResult{results[0]}, ProfileResult{URL: "https://www.linkedin.com/in/alan-turing", Name: "Alan Turing"})
+	fresh, err = saveResults(db, "default", SourceSearch, page, postFilter, false)
+	if err != nil {
+		t.Fatalf("saveResults: %v", err)
+	}
+	if got := names(fresh); len(got) != 1 || got[0] != "Alan Turing" {
+		t.Fatalf("mixed page: new = %v, want Alan Turing", got)
+	}
+}
+
+func TestSaveResultsLeavesFilteredOut(t *testing.T) {
+	s, _, db := newSearcher(t, &config.SearchConfig{}, resultsPage)
+	results, err := s.parseSearchResults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	postFilter, err := NewPostFilter(config.Filters{TitleMustExclude: []string{"compiler"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Grace is stored flagged, so she is neither new now nor on the next page that shows her
+	for i, want := range []string{"Ada Lovelace", ""} {
+		fresh, err := saveResults(db, "default", SourceSearch, results, postFilter, false)
+		if err != nil {
+			t.Fatalf("saveResults: %v", err)
+		}
+		if got := strings.Join(names(fresh), ","); got != want {
+			t.Fatalf("save %d: new = %q, want %q", i+1, got, want)
+		}
+	}
+}
+
+// cardsPage is a results page with a card for each profile slug, and Next enabled unless
+// it is the last page
+func cardsPage(slugs []string, last bool) string {
+	var b strings.Builder
+	b.WriteString(`<html><body><div class="search-results-container"><ul>`)
+	for _, slug := range slugs {
+		b.WriteString(`<li class="reusable-search__result-container"><div class="entity-result"><span class="entity-result__title-text">`)
+		b.WriteString(`<a class="app-aware-link" href="https://www.linkedin.com/in/` + slug + `/"><span aria-hidden="true">` + slug + `</span></a>`)
+		b.WriteString(`</span></div></li>`)
+	}
+	b.WriteString(`</ul></div>`)
+	if last {
+		b.WriteString(`<button aria-label="Next" disabled="true">Next</button>`)
+	} else {
+		b.WriteString(`<button aria-label="Next">Next</button>`)
+	}
+	b.WriteString(`</body></html>`)
+	return b.String()
+}
+
+func TestSearchStopsAfterEmptyPages(t *testing.T) {
+	// Pages 2, 4 and 5 bring nothing new
+	pages := [][]string{{"ada", "grace"}, {"ada", "grace"}, {"alan"}, {"alan"}, {"grace"}, {"edsger"}}
+
+	for _, tt := range []struct {
+		name       string
+		stopAfter  int
+		read       int // pages read before stopping
+		newProfile int
+	}{
+		{"after one", 1, 2, 2},
+		{"after two, a new profile resetting the count", 2, 5, 3},
+		{"never", -1, 6, 4},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, page, _ := newSearcher(t, &config.SearchConfig{MaxResults: 100, StopAfterEmptyPages: tt.stopAfter}, "")
+			page.Serve(s.provider.BuildURL(s.config), cardsPage(pages[0], false))
+			read := 1
+			page.OnClick("button", func(el *pagetest.Element) {
+				if text, _ := el.Text(); text == "Next" {
+					page.SetHTML(cardsPage(pages[read], read == len(pages)-1))
+					read++
+				}
+			})
+
+			summary, err := s.Search()
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			if read != tt.read || summary.NewProfiles != tt.newProfile || len(summary.New) != tt.newProfile {
+				t.Fatalf("read %d pages with %d new (%v), want %d pages with %d new", read, summary.NewProfiles, names(summary.New), tt.read, tt.newProfile)
+			}
+		})
+	}
+}
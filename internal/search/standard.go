@@ -0,0 +1,158 @@
+package search
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/headline"
+	"github.com/Tanukumar01/linkedin-automation/internal/insight"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/profileurl"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
+)
+
+// standardSearchURL is the free people search
+const standardSearchURL = "https://www.linkedin.com/search/results/people/"
+
+// standardProvider searches with LinkedIn's free people search
+type standardProvider struct{}
+
+// BuildURL builds the people search URL with filters. The network facets take lists
+// the way LinkedIn writes them, e.g. network=["S","O"].
+func (standardProvider) BuildURL(cfg *config.SearchConfig) string {
+	params := url.Values{}
+	if query := keywordQuery(cfg.Filters); query != "" {
+		params.Add("keywords", query)
+	}
+
+	var members []string
+	for _, member := range cfg.Filters.ConnectionOf {
+		if id := profileurl.ParseMemberID(member); id != "" {
+			members = append(members, id)
+		}
+	}
+	if len(members) > 0 {
+		params.Add("connectionOf", facetList(members))
+	}
+	if len(cfg.Filters.NetworkDepth) > 0 {
+		params.Add("network", facetList(cfg.Filters.NetworkDepth))
+	}
+
+	// LinkedIn marks searches narrowed with facets as such
+	if len(members) > 0 || len(cfg.Filters.NetworkDepth) > 0 {
+		params.Add("origin", "FACETED_SEARCH")
+	} else {
+		params.Add("origin", "GLOBAL_SEARCH_HEADER")
+	}
+
+	return standardSearchURL + "?" + params.Encode()
+}
+
+// facetList writes facet values as the quoted list LinkedIn's search URLs carry
+func facetList(values []string) string {
+	return `["` + strings.Join(values, `","`) + `"]`
+}
+
+// Selectors returns the selector names of the standard results list
+func (standardProvider) Selectors() ResultSelectors {
+	return ResultSelectors{
+		Loaded:    selectors.SearchResultsLoaded,
+		NoResults: selectors.SearchNoResults,
+		Item:      selectors.SearchResultItem,
+	}
+}
+
+// ParseResult reads a result card of the standard search
+func (standardProvider) ParseResult(element pageops.Element) (*ProfileResult, error) {
+	result := &ProfileResult{}
+
+	// Get profile URL and Name (they are usually in the same link)
+	// Look for the primary title link
+	linkElement, err := selectors.FindFirst(element, selectors.ResultProfileLink)
+	if err != nil {
+		return nil, err
+	}
+
+	href, err := linkElement.Property("href")
+	if err != nil {
+		return nil, err
+	}
+
+	result.URL = href
+	// The link names the member's entity ID in its query, which outlasts the vanity URL
+	result.MemberID = profileurl.MemberID(href)
+
+	// Clean URL (remove query parameters)
+	if idx := strings.Index(result.URL, "?"); idx != -1 {
+		result.URL = result.URL[:idx]
+	}
+
+	// Get name - often inside the link in a span
+	nameElement, err := selectors.FindFirst(linkElement, selectors.ResultLinkName)
+	if err == nil {
+		name, _ := nameElement.Text()
+		result.Name = strings.TrimSpace(name)
+	}
+
+	// If name still empty, try looking in the whole element
+	if result.Name == "" {
+		if nameEl, err := selectors.FindFirst(element, selectors.ResultTitleText); err == nil {
+			name, _ := nameEl.Text()
+			result.Name = strings.TrimSpace(name)
+		}
+	}
+
+	// Get job title
+	if titleElement, err := selectors.FindFirst(element, selectors.ResultJobTitle); err == nil {
+		title, _ := titleElement.Text()
+		result.JobTitle = strings.TrimSpace(title)
+		result.Headline = headline.Parse(result.JobTitle)
+	}
+
+	// Get location
+	if locElement, err := selectors.FindFirst(element, selectors.ResultLocation); err == nil {
+		loc, _ := locElement.Text()
+		result.Location = strings.TrimSpace(loc)
+	}
+
+	if summaryElement, err := selectors.FindFirst(element, selectors.ResultSummary); err == nil {
+		summary, _ := summaryElement.Text()
+		result.Summary = strings.Join(strings.Fields(summary), " ")
+	}
+
+	// "Jane Doe and 3 other mutual connections"
+	if insightElement, err := selectors.FindFirst(element, selectors.ResultInsight); err == nil {
+		text, _ := insightElement.Text()
+		result.MutualConnections = insight.MutualConnections(text)
+	}
+
+	// "• 2nd", or "3rd+ degree connection" for screen readers
+	if degreeElement, err := selectors.FindFirst(element, selectors.ResultDegree); err == nil {
+		text, _ := degreeElement.Text()
+		result.Degree = insight.Degree(text)
+	}
+
+	result.Premium = selectors.Has(element, selectors.ResultPremiumBadge)
+	result.OpenToWork = selectors.Has(element, selectors.ResultOpenToWork) || insight.OpenToWork(result.JobTitle)
+
+	return result, nil
+}
+
+// NextPage scrolls down to the pagination and clicks Next
+func (standardProvider) NextPage(s *Searcher) (bool, error) {
+	// Scroll to bottom to load pagination
+	if err := s.scroller.ScrollToBottom(); err != nil {
+		logger.Warnf("Failed to scroll to bottom: %v", err)
+	}
+
+	s.timing.Wait(s.timing.ShortPause())
+
+	// Pages loaded by clicking Next come without the embedded JSON, so load them by URL
+	if s.config.Parser == config.SearchParserJSON {
+		return s.openPage(currentPage(pageops.CurrentURL(s.page)) + 1)
+	}
+
+	return s.clickNext(selectors.NextPageButton)
+}
@@ -0,0 +1,42 @@
+package search
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+)
+
+func TestStandardBuildURLNetworkFacets(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		filters config.Filters
+		want    url.Values
+	}{
+		{"no facets", config.Filters{Keywords: []string{"analyst"}},
+			url.Values{"keywords": {"analyst"}, "origin": {"GLOBAL_SEARCH_HEADER"}}},
+		{"network depth", config.Filters{Keywords: []string{"analyst"}, NetworkDepth: []string{"S", "O"}},
+			url.Values{"keywords": {"analyst"}, "network": {`["S","O"]`}, "origin": {"FACETED_SEARCH"}}},
+		{"connection of", config.Filters{NetworkDepth: []string{"S"}, ConnectionOf: []string{
+			"ACoAAB12cd",
+			"urn:li:fsd_profile:ACoAAC34ef",
+			"https://www.linkedin.com/in/ACoAAD56gh/",
+			"https://www.linkedin.com/in/no-id/",
+		}}, url.Values{"connectionOf": {`["ACoAAB12cd","ACoAAC34ef","ACoAAD56gh"]`}, "network": {`["S"]`}, "origin": {"FACETED_SEARCH"}}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := standardProvider{}.BuildURL(&config.SearchConfig{Filters: tt.filters})
+			if !strings.HasPrefix(raw, standardSearchURL+"?") {
+				t.Fatalf("URL %s isn't a people search", raw)
+			}
+			u, err := url.Parse(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := u.Query(); got.Encode() != tt.want.Encode() {
+				t.Fatalf("query = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
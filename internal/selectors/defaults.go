@@ -0,0 +1,482 @@
+package selectors
+
+// Selector names
+const (
+	// Login
+	LoginEmail        = "LoginEmail"
+	LoginPassword     = "LoginPassword"
+	LoginSubmit       = "LoginSubmit"
+	LoggedInIndicator = "LoggedInIndicator"
+	Challenge2FA      = "Challenge2FA"
+	ChallengeCaptcha  = "ChallengeCaptcha"
+	ChallengeUnusual  = "ChallengeUnusual"
+	ChallengeEmailPIN = "ChallengeEmailPIN"
+	ChallengeApproval = "ChallengeApproval"
+
+	// Search
+	SearchResultsLoaded = "SearchResultsLoaded"
+	SearchNoResults     = "SearchNoResults"
+	SearchResultItem    = "SearchResultItem"
+	ResultProfileLink   = "ResultProfileLink"
+	ResultLinkName      = "ResultLinkName"
+	ResultTitleText     = "ResultTitleText"
+	ResultJobTitle      = "ResultJobTitle"
+	ResultLocation      = "ResultLocation"
+	ResultSummary       = "ResultSummary"
+	ResultInsight       = "ResultInsight"
+	ResultPremiumBadge  = "ResultPremiumBadge"
+	ResultDegree        = "ResultDegree"
+	ResultOpenToWork    = "ResultOpenToWork"
+	NextPageButton      = "NextPageButton"
+
+	// Sales Navigator search and lead pages
+	SalesNavResultsLoaded = "SalesNavResultsLoaded"
+	SalesNavNoResults     = "SalesNavNoResults"
+	SalesNavResultItem    = "SalesNavResultItem"
+	SalesNavLeadLink      = "SalesNavLeadLink"
+	SalesNavLeadName      = "SalesNavLeadName"
+	SalesNavLeadTitle     = "SalesNavLeadTitle"
+	SalesNavLeadCompany   = "SalesNavLeadCompany"
+	SalesNavLeadLocation  = "SalesNavLeadLocation"
+	SalesNavPublicLink    = "SalesNavPublicLink"
+	SalesNavNextButton    = "SalesNavNextButton"
+	SalesNavLeadOverflow  = "SalesNavLeadOverflow"
+
+	// Profile and invite flow
+	ProfileName           = "ProfileName"
+	ConnectButton         = "ConnectButton"
+	PendingButton         = "PendingButton"
+	ProfileMessageButton  = "ProfileMessageButton"
+	ProfileFollowButton   = "ProfileFollowButton"
+	ProfileDistanceBadge  = "ProfileDistanceBadge"
+	InviteBottomSheet     = "InviteBottomSheet"
+	AddNoteButton         = "AddNoteButton"
+	NoteTextarea          = "NoteTextarea"
+	NoteUpsell            = "NoteUpsell"
+	InviteSendButton      = "InviteSendButton"
+	SendWithoutNoteButton = "SendWithoutNoteButton"
+	InviteDismissButton   = "InviteDismissButton"
+	InviteLimitAlert      = "InviteLimitAlert"
+	InviteLimitNotice     = "InviteLimitNotice"
+	InviteLimitWarning    = "InviteLimitWarning"
+	InviteModal           = "InviteModal"
+	OpenDialog            = "OpenDialog"
+	ErrorToast            = "ErrorToast"
+	ProfileNotFound       = "ProfileNotFound"
+
+	// The redesigned profile top card, whose actions sit in a sticky header
+	ProfileTopCardHeader    = "ProfileTopCardHeader"
+	TopCardConnectButton    = "TopCardConnectButton"
+	TopCardMessageButton    = "TopCardMessageButton"
+	TopCardAddNoteButton    = "TopCardAddNoteButton"
+	TopCardInviteSendButton = "TopCardInviteSendButton"
+
+	// The simplified "lite" invite flow, a bottom sheet in place of the modal
+	SheetAddNoteButton         = "SheetAddNoteButton"
+	SheetNoteTextarea          = "SheetNoteTextarea"
+	SheetNoteUpsell            = "SheetNoteUpsell"
+	SheetSendButton            = "SheetSendButton"
+	SheetSendWithoutNoteButton = "SheetSendWithoutNoteButton"
+	SheetDismissButton         = "SheetDismissButton"
+
+	// Pruning connections and follows
+	ProfileMoreButton      = "ProfileMoreButton"
+	ProfileFollowingButton = "ProfileFollowingButton"
+	MoreMenuConnectItem    = "MoreMenuConnectItem"
+	RemoveConnectionItem   = "RemoveConnectionItem"
+	UnfollowItem           = "UnfollowItem"
+	RemoveConfirmButton    = "RemoveConfirmButton"
+	UnfollowConfirmButton  = "UnfollowConfirmButton"
+
+	// Profile details scraped for enrichment
+	ProfileHeadline   = "ProfileHeadline"
+	ProfileLocation   = "ProfileLocation"
+	ProfileFollowers  = "ProfileFollowers"
+	AboutSeeMore      = "AboutSeeMore"
+	AboutText         = "AboutText"
+	ExperienceItem    = "ExperienceItem"
+	ExperienceTitle   = "ExperienceTitle"
+	ExperienceCompany = "ExperienceCompany"
+	ExperienceDates   = "ExperienceDates"
+	EducationItem     = "EducationItem"
+	EducationSchool   = "EducationSchool"
+
+	// Invitations and connections lists
+	InvitationCard     = "InvitationCard"
+	InvitationLink     = "InvitationLink"
+	InvitationName     = "InvitationName"
+	InvitationHeadline = "InvitationHeadline"
+	InvitationInsights = "InvitationInsights"
+	InvitationAccept   = "InvitationAccept"
+	InvitationIgnore   = "InvitationIgnore"
+	ConnectionCardLink = "ConnectionCardLink"
+
+	// Group members and event attendees
+	MemberListItem     = "MemberListItem"
+	MemberProfileLink  = "MemberProfileLink"
+	MemberName         = "MemberName"
+	MemberHeadline     = "MemberHeadline"
+	MemberShowMore     = "MemberShowMore"
+	EventAttendeesLink = "EventAttendeesLink"
+
+	// A company page's People tab
+	CompanyName          = "CompanyName"
+	CompanyEmployeeCard  = "CompanyEmployeeCard"
+	CompanyLocationFacet = "CompanyLocationFacet"
+	CompanyPremiumWall   = "CompanyPremiumWall"
+
+	// Messaging
+	MessageButton         = "MessageButton"
+	MessageBox            = "MessageBox"
+	MessageSendButton     = "MessageSendButton"
+	SentMessageBubble     = "SentMessageBubble"
+	ReceivedMessageBubble = "ReceivedMessageBubble"
+	MessageSendFailed     = "MessageSendFailed"
+	InMailSubject         = "InMailSubject"
+	InMailCredits         = "InMailCredits"
+
+	// Feed
+	FeedPost       = "FeedPost"
+	FeedLikeButton = "FeedLikeButton"
+
+	// A prospect's recent activity
+	ActivityPost      = "ActivityPost"
+	ActivityReshared  = "ActivityReshared"
+	ActivityPostLiked = "ActivityPostLiked"
+	ActivityPostLink  = "ActivityPostLink"
+)
+
+// css is a shorthand for a variant without a text pattern
+func css(selector string) Variant {
+	return Variant{CSS: selector}
+}
+
+// text is a shorthand for a variant whose text must match pattern
+func text(selector, pattern string) Variant {
+	return Variant{CSS: selector, Text: pattern}
+}
+
+// defaultChains returns the built-in fallback chains, most specific first
+func defaultChains() map[string][]Variant {
+	defaults := map[string][]Variant{
+		LoginEmail:    {css("#username")},
+		LoginPassword: {css("#password")},
+		LoginSubmit:   {css("button[type='submit']")},
+		LoggedInIndicator: {
+			css("nav.global-nav"),
+			css("#global-nav"),
+			css(".global-nav"),
+			css("button.global-nav__primary-link--active"),
+			css("div.authentication-outlet"), // Container for the logged in app
+			css("img.global-nav__me-photo"),  // Profile photo in nav
+		},
+		Challenge2FA:      {css("input[id*='verification']")},
+		ChallengeCaptcha:  {css("iframe[title*='recaptcha']")},
+		ChallengeUnusual:  {css("div[data-test-id='unusual-activity']")},
+		ChallengeEmailPIN: {css("input[name='pin']")},
+		ChallengeApproval: {css("button[id*='resend']")},
+
+		SearchResultsLoaded: {css(".reusable-search__result-container, .entity-result")},
+		SearchNoResults:     {css("h2.artdeco-empty-state__headline")},
+		SearchResultItem: {
+			css("li.reusable-search__result-container"),
+			css("div.search-results-container li"),
+			css(".entity-result"),
+		},
+		ResultProfileLink: {css("a.app-aware-link"), css("a[href*='/in/']")},
+		ResultLinkName:    {css("span[aria-hidden='true']")},
+		ResultTitleText:   {css(".entity-result__title-text")},
+		ResultJobTitle:    {css(".entity-result__primary-subtitle")},
+		ResultLocation:    {css(".entity-result__secondary-subtitle")},
+		ResultSummary:     {css(".entity-result__summary"), css("p[class*='summary']")},
+		NextPageButton:    {css("button[aria-label*='Next']"), text("button", "(?i){Next}")},
+		ResultInsight: {
+			css(".entity-result__simple-insight-text"),
+			css(".reusable-search-simple-insight__text"),
+			text(".entity-result__insights span, .entity-result__content span", "(?i)mutual connection"),
+		},
+		ResultDegree: {
+			css(".entity-result__badge-text span[aria-hidden='true']"),
+			css(".entity-result__badge-text"),
+			css("[class*='distance-badge']"),
+		},
+		ResultPremiumBadge: {
+			css("li-icon[type='premium-badge']"),
+			css("[class*='premium-badge']"),
+			css("[aria-label*='Premium' i]"),
+		},
+		ResultOpenToWork: {
+			css("img[alt*='open to work' i]"),
+			css("[aria-label*='open to work' i]"),
+			text(".entity-result__badge-text, .entity-result__image span", "(?i)open to work"),
+		},
+
+		SalesNavResultsLoaded: {css("ol.artdeco-list li.artdeco-list__item"), css("[data-x-search-result='LEAD']")},
+		SalesNavNoResults:     {text("h2, h3, p", "(?i)no (leads|results) (matched|found)")},
+		SalesNavResultItem:    {css("ol.artdeco-list > li.artdeco-list__item"), css("li:has([data-x-search-result='LEAD'])")},
+		SalesNavLeadLink: {
+			css("a[data-control-name='view_lead_panel_via_search_lead_name']"),
+			css("a[href*='/sales/lead/']"),
+		},
+		SalesNavLeadName:     {css("span[data-anonymize='person-name']")},
+		SalesNavLeadTitle:    {css("span[data-anonymize='title']")},
+		SalesNavLeadCompany:  {css("a[data-anonymize='company-name']"), css("span[data-anonymize='company-name']")},
+		SalesNavLeadLocation: {css("span[data-anonymize='location']")},
+		SalesNavPublicLink:   {css("a[href*='linkedin.com/in/']"), css("a[href^='/in/']")},
+		SalesNavNextButton:   {css("button.artdeco-pagination__button--next"), css("button[aria-label*='Next']")},
+		SalesNavLeadOverflow: {
+			css("button[aria-label*='overflow menu' i]"),
+			css("button[aria-label*='more actions' i]"),
+		},
+
+		ProfileName: {css("h1")},
+		ConnectButton: {
+			text("button", `(?i)^\s*{Connect}\s*$`),
+			css("button[aria-label*='Connect']"),
+			text(".pvs-profile-actions button", "(?i){Connect}"),
+		},
+		ProfileMessageButton: {
+			text(".pvs-profile-actions button, .pv-top-card button", `(?i)^\s*{Message}\s*$`),
+			text("[data-view-name='profile-top-card-sticky-header'] button, [data-view-name='profile-top-card-sticky-header'] a", `(?i)^\s*{Message}\s*$`),
+		},
+		ProfileFollowButton: {
+			text(".pvs-profile-actions button, .pv-top-card button", `(?i)^\s*{Follow}\s*$`),
+			text("[data-view-name='profile-top-card-sticky-header'] button", `(?i)^\s*{Follow}\s*$`),
+		},
+		PendingButton:        {text("button", `(?i)^\s*{Pending}\s*$`), css("button[aria-label*='Pending']")},
+		ProfileDistanceBadge: {css(".pv-top-card .dist-value"), css(".distance-badge .dist-value")},
+		InviteBottomSheet:    {css(".artdeco-bottom-sheet"), css("div[class*='bottom-sheet']")},
+		AddNoteButton:        {css("button[aria-label*='Add a note']"), text("button", `(?i)^\s*{AddNote}\s*$`)},
+		NoteTextarea:         {css("textarea[name='message']")},
+		NoteUpsell: {
+			css(".artdeco-modal [class*='premium-upsell']"),
+			text(".artdeco-modal p, .artdeco-modal span", "(?i)(personali[sz]ed invitations|premium)"),
+		},
+		InviteSendButton:      {text("button", "(?i){Send}"), css("button[aria-label*='Send']")},
+		SendWithoutNoteButton: {css("button[aria-label*='Send without a note']"), text("button", "(?i){SendWithoutNote}")},
+		InviteDismissButton:   {css(".artdeco-modal button[aria-label='Dismiss']"), text(".artdeco-modal button", `(?i)^\s*{Cancel}\s*$`)},
+		InviteLimitAlert:      {css(".ip-fuse-limit-alert")},
+		InviteLimitNotice:     {text("h2, p", "(?i)(weekly invitation limit|reached the limit|invitation limit)")},
+		InviteModal:           {css(".artdeco-modal.send-invite"), css(".artdeco-modal[role='dialog']")},
+		ErrorToast:            {css(".artdeco-toast-item--error"), css("[data-test-artdeco-toast-item-type='error']")},
+		OpenDialog: {
+			css(".artdeco-modal[role='dialog']"),
+			css("[role='dialog'][aria-modal='true']"),
+			css("[role='alertdialog']"),
+			css("[class*='bottom-sheet'][role='dialog']"),
+		},
+		InviteLimitWarning: {
+			css(".artdeco-modal .ip-fuse-limit-alert__warning"),
+			text(".artdeco-modal p, .artdeco-modal span", "(?i)(approaching the weekly invitation limit|invitations? (left|remaining) this week|more invitations? this week)"),
+		},
+		// A deleted or mistyped profile opens LinkedIn's 404 page in place of the profile
+		ProfileNotFound: {
+			css("main .not-found-404"),
+			css("[data-test-id='not-found']"),
+			text("main h1, main h2", `(?i)(this page doesn.t exist|page not found|profile is not available)`),
+		},
+
+		// The sticky header repeats actions such as "Send profile in a message", so the
+		// top card chains look inside it or the invite dialog before falling back to the
+		// page-wide variants
+		ProfileTopCardHeader: {
+			css("[data-view-name='profile-top-card-sticky-header']"),
+			css("section[class*='profile-top-card'] header[class*='sticky']"),
+		},
+		TopCardConnectButton: {
+			css("[data-view-name='profile-top-card-sticky-header'] button[aria-label*='to connect' i]"),
+			text("[data-view-name='profile-top-card-sticky-header'] button, header[class*='sticky'] button", `(?i)^\s*{Connect}\s*$`),
+			text("button", `(?i)^\s*{Connect}\s*$`),
+			css("button[aria-label*='Connect']"),
+		},
+		TopCardMessageButton: {
+			text("[data-view-name='profile-top-card-sticky-header'] button, header[class*='sticky'] button", `(?i)^\s*{Message}\s*$`),
+			text("[data-view-name='profile-top-card-sticky-header'] a, header[class*='sticky'] a", `(?i)^\s*{Message}\s*$`),
+			text("button", `(?i)^\s*{Message}\s*$`),
+		},
+		TopCardAddNoteButton: {
+			css("[role='dialog'] button[aria-label*='Add a note' i]"),
+			text("[role='dialog'] button", `(?i)^\s*{AddNote}\s*$`),
+			css("button[aria-label*='Add a note']"),
+		},
+		TopCardInviteSendButton: {
+			css("[role='dialog'] button[aria-label*='Send invitation' i]"),
+			text("[role='dialog'] button", `(?i)^\s*{Send}\s*$`),
+			css("[role='dialog'] button[aria-label*='Send']"),
+		},
+
+		// The sheet's controls are looked up inside it, so the profile's own buttons don't match
+		SheetAddNoteButton: {
+			css(".artdeco-bottom-sheet button[aria-label*='Add a note' i]"),
+			text("[class*='bottom-sheet'] button", `(?i)^\s*{AddNote}\s*$`),
+		},
+		SheetNoteTextarea: {css(".artdeco-bottom-sheet textarea"), css("[class*='bottom-sheet'] textarea")},
+		SheetNoteUpsell: {
+			css("[class*='bottom-sheet'] [class*='premium-upsell']"),
+			text("[class*='bottom-sheet'] p, [class*='bottom-sheet'] span", "(?i)(personali[sz]ed invitations|premium)"),
+		},
+		SheetSendButton: {
+			css(".artdeco-bottom-sheet button[aria-label*='Send' i]"),
+			text("[class*='bottom-sheet'] button", "(?i){Send}"),
+		},
+		SheetSendWithoutNoteButton: {
+			css("[class*='bottom-sheet'] button[aria-label*='Send without a note' i]"),
+			text("[class*='bottom-sheet'] button", "(?i){SendWithoutNote}"),
+			text("[class*='bottom-sheet'] button", `(?i)^\s*{Send}\s*$`),
+		},
+		SheetDismissButton: {
+			css(".artdeco-bottom-sheet button[aria-label='Dismiss']"),
+			css("[class*='bottom-sheet'] button[aria-label*='Close' i]"),
+			text("[class*='bottom-sheet'] button", `(?i)^\s*{Cancel}\s*$`),
+		},
+
+		ProfileMoreButton: {
+			css(".pvs-profile-actions button[aria-label*='More actions' i]"),
+			text(".pvs-profile-actions button, .pv-top-card button", `(?i)^\s*{More}\s*$`),
+			text("[data-view-name='profile-top-card-sticky-header'] button", `(?i)^\s*{More}\s*$`),
+		},
+		// Profiles that lead with Follow keep Connect in the More menu
+		MoreMenuConnectItem: {
+			css(".artdeco-dropdown__content div[aria-label*='to connect' i]"),
+			text(".artdeco-dropdown__content [role='button'], .artdeco-dropdown__item", `(?i)^\s*{Connect}\s*$`),
+		},
+		ProfileFollowingButton: {
+			css(".pvs-profile-actions button[aria-label^='Following' i]"),
+			text(".pvs-profile-actions button, .pv-top-card button", `(?i)^\s*{Following}\s*$`),
+		},
+		RemoveConnectionItem: {
+			css(".artdeco-dropdown__content div[aria-label*='Remove your connection' i]"),
+			text(".artdeco-dropdown__content [role='button'], .artdeco-dropdown__item", `(?i)^\s*{RemoveConnection}\s*$`),
+		},
+		UnfollowItem: {
+			css(".artdeco-dropdown__content div[aria-label^='Unfollow' i]"),
+			text(".artdeco-dropdown__content [role='button'], .artdeco-dropdown__item", `(?i)^\s*{Unfollow}\s*$`),
+		},
+		RemoveConfirmButton:   {text(".artdeco-modal button", `(?i)^\s*{Remove}\s*$`), css(".artdeco-modal button.artdeco-button--primary")},
+		UnfollowConfirmButton: {text(".artdeco-modal button", `(?i)^\s*{Unfollow}\s*$`), css(".artdeco-modal button.artdeco-button--primary")},
+
+		ProfileHeadline: {css(".pv-top-card .text-body-medium.break-words"), css("div.text-body-medium.break-words")},
+		ProfileLocation: {css(".pv-top-card .text-body-small.inline.t-black--light.break-words"), css("span.text-body-small.inline.t-black--light")},
+		ProfileFollowers: {
+			text(".pv-top-card li, .pv-top-card span", `(?i)^\s*[\d,.]+\s*[KM]?\s+followers?\s*$`),
+			text("li, span, p", `(?i)^\s*[\d,.]+\s*[KM]?\s+followers?\s*$`),
+		},
+		AboutSeeMore: {
+			css("section:has(#about) button.inline-show-more-text__button"),
+			text("section:has(#about) button", `(?i){SeeMore}`),
+		},
+		AboutText: {
+			css("section:has(#about) .inline-show-more-text span[aria-hidden='true']"),
+			css("section:has(#about) .display-flex span[aria-hidden='true']"),
+		},
+		ExperienceItem:    {css("section:has(#experience) li.artdeco-list__item")},
+		ExperienceTitle:   {css(".t-bold span[aria-hidden='true']")},
+		ExperienceCompany: {css(".t-14.t-normal:not(.t-black--light) span[aria-hidden='true']")},
+		ExperienceDates:   {css(".t-14.t-normal.t-black--light span[aria-hidden='true']"), css(".pvs-entity__caption-wrapper")},
+		EducationItem:     {css("section:has(#education) li.artdeco-list__item")},
+		EducationSchool:   {css(".t-bold span[aria-hidden='true']")},
+
+		InvitationCard: {
+			css("li.invitation-card"),
+			css("div.invitation-card"),
+			css("section.mn-invitation-list li"),
+		},
+		InvitationLink:     {css("a[href*='/in/']")},
+		InvitationName:     {css(".invitation-card__title")},
+		InvitationHeadline: {css(".invitation-card__subtitle")},
+		InvitationInsights: {css(".member-insights")},
+		InvitationAccept:   {css("button[aria-label*='Accept']"), text("button", `(?i)^\s*{Accept}\s*$`)},
+		InvitationIgnore:   {css("button[aria-label*='Ignore']"), text("button", `(?i)^\s*{Ignore}\s*$`)},
+		ConnectionCardLink: {css("li.mn-connection-card a[href*='/in/'], div.mn-connection-card a[href*='/in/']")},
+
+		MessageButton: {
+			css("button[aria-label*='Message']"),
+			text("button", `(?i)^\s*{Message}\s*$`),
+			text("div.pvs-profile-actions button", "(?i){Message}"),
+		},
+		MessageBox: {
+			css("div.msg-form__contenteditable"),
+			css("div[role='textbox']"),
+			css("div.msg-form__msg-content-container div[contenteditable='true']"),
+		},
+		MessageSendButton: {
+			css("button[type='submit']"),
+			css("button.msg-form__send-button"),
+			text("button", `(?i)^\s*{Send}\s*$`),
+		},
+		SentMessageBubble: {css(".msg-s-event-listitem__body"), css(".msg-s-message-list__event p")},
+		ReceivedMessageBubble: {
+			css(".msg-s-event-listitem--other .msg-s-event-listitem__body"),
+			css(".msg-s-event-listitem--other"),
+		},
+		InMailSubject: {
+			css("input[name='subject']"),
+			css("input.msg-form__subject"),
+			css("input[placeholder*='Subject']"),
+		},
+		InMailCredits: {
+			css(".msg-inmail-credits-display"),
+			text("span, p", `(?i)InMail credits?`),
+		},
+		MessageSendFailed: {
+			css(".msg-s-event-listitem--error"),
+			text(".msg-s-event-listitem, .msg-s-message-list__event", "(?i)(not sent|failed to send|couldn.t send)"),
+		},
+
+		MemberListItem: {
+			css("li.groups-members-list__typeahead-result"),
+			css(".artdeco-modal li.artdeco-list__item"),
+			css("ul.artdeco-list li"),
+		},
+		MemberProfileLink: {css("a[href*='/in/']")},
+		MemberName:        {css(".artdeco-entity-lockup__title"), css("span[aria-hidden='true']")},
+		MemberHeadline:    {css(".artdeco-entity-lockup__subtitle"), css(".artdeco-entity-lockup__caption")},
+		MemberShowMore:    {text("button", `(?i)^\s*{ShowMore}\s*$`)},
+		EventAttendeesLink: {
+			css("a[href*='attendees']"),
+			text("button, a", `(?i)(see all|show all|\d+) attendees`),
+		},
+
+		CompanyName: {css("h1.org-top-card-summary__title"), css("main h1")},
+		CompanyEmployeeCard: {
+			css("li.org-people-profile-card__profile-card-spacing"),
+			css(".org-people-profiles-module__profile-list li"),
+			css("div.org-people-profile-card"),
+		},
+		CompanyLocationFacet: {
+			css("button.org-people-bar-graph-element"),
+			css(".org-people-bar-graph-module__geo-region button"),
+		},
+		CompanyPremiumWall: {
+			css(".org-people__upsell"),
+			text("h2, h3, p", `(?i)(unlock|see) (more|all|the rest).{0,40}premium`),
+		},
+
+		FeedPost: {css("div.feed-shared-update-v2"), css("div[data-urn*='activity']")},
+		FeedLikeButton: {
+			css("button[aria-pressed='false'][aria-label*='React Like']"),
+			css("button.react-button__trigger[aria-pressed='false']"),
+		},
+
+		ActivityPost: {css("div.feed-shared-update-v2"), css("div[data-urn*='activity']")},
+		ActivityReshared: {
+			css(".update-components-header"),
+			css(".feed-shared-header"),
+			css(".update-components-mini-update-v2"),
+		},
+		ActivityPostLiked: {
+			css("button[aria-pressed='true'][aria-label*='React Like']"),
+			css("button.react-button__trigger[aria-pressed='true']"),
+		},
+		ActivityPostLink: {css("a[href*='/feed/update/']"), css("a[href*='/posts/']")},
+	}
+
+	for _, chain := range defaults {
+		for i := range chain {
+			// Built-in patterns are constants and always compile
+			chain[i].compile()
+		}
+	}
+
+	return defaults
+}
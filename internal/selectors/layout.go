@@ -0,0 +1,77 @@
+package selectors
+
+import "github.com/Tanukumar01/linkedin-automation/internal/logger"
+
+// Profile page layouts
+const (
+	LayoutClassic = "classic"  // actions in .pvs-profile-actions below the top card
+	LayoutTopCard = "top_card" // actions in the redesigned top card's sticky header
+)
+
+// topCardChains names the chain used instead of another on a top card profile
+var topCardChains = map[string]string{
+	ConnectButton:    TopCardConnectButton,
+	MessageButton:    TopCardMessageButton,
+	AddNoteButton:    TopCardAddNoteButton,
+	InviteSendButton: TopCardInviteSendButton,
+}
+
+// sheetChains names the chain used instead of another in the bottom-sheet invite flow
+var sheetChains = map[string]string{
+	AddNoteButton:         SheetAddNoteButton,
+	NoteTextarea:          SheetNoteTextarea,
+	NoteUpsell:            SheetNoteUpsell,
+	InviteSendButton:      SheetSendButton,
+	SendWithoutNoteButton: SheetSendWithoutNoteButton,
+	InviteDismissButton:   SheetDismissButton,
+	InviteModal:           InviteBottomSheet,
+}
+
+// lastLayout is the layout of the last profile detected; guarded by mu
+var lastLayout string
+
+// DetectLayout reports which layout the open profile page has. A layout that differs
+// from the last profile's is logged and recorded, so the top card's rollout to the
+// account shows in the activity log.
+func DetectLayout(scope Scope) string {
+	layout := LayoutClassic
+	if Has(scope, ProfileTopCardHeader) {
+		layout = LayoutTopCard
+	}
+
+	mu.Lock()
+	changed := layout != lastLayout
+	lastLayout = layout
+	r := recorder
+	mu.Unlock()
+
+	if !changed {
+		logger.Debugf("Profile page layout: %s", layout)
+		return layout
+	}
+	logger.Infof("Profile page layout detected: %s", layout)
+	if r != nil {
+		r.LogActivity("profile_layout", layout)
+	}
+	return layout
+}
+
+// ForLayout returns the name of the chain to look name up with on a profile of layout.
+// Names the layout has no chain of its own for are returned unchanged.
+func ForLayout(name, layout string) string {
+	if layout == LayoutTopCard {
+		if alt, ok := topCardChains[name]; ok {
+			return alt
+		}
+	}
+	return name
+}
+
+// ForSheet returns the name of the chain to look name up with in the bottom-sheet invite
+// flow. Names the sheet has no chain of its own for are returned unchanged.
+func ForSheet(name string) string {
+	if alt, ok := sheetChains[name]; ok {
+		return alt
+	}
+	return name
+}
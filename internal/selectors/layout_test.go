@@ -0,0 +1,108 @@
+package selectors
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops/pagetest"
+)
+
+// activityLog collects the activity recorded
+type activityLog struct {
+	entries []string
+}
+
+func (l *activityLog) LogActivity(action, details string) error {
+	l.entries = append(l.entries, action+": "+details)
+	return nil
+}
+
+const (
+	classicProfile = `<main><section class="pv-top-card"><h1>Ada Lovelace</h1>
+		<div class="pvs-profile-actions"><button>Message</button><button>Connect</button></div></section>
+		<aside><button aria-label="Invite Grace Hopper to connect">Connect</button></aside></main>`
+	topCardProfile = `<main><section class="profile-top-card"><h1>Ada Lovelace</h1>
+		<div data-view-name="profile-top-card-sticky-header">
+			<button aria-label="Invite Ada Lovelace to connect">Connect</button><button>Message</button>
+		</div></section>
+		<aside><button aria-label="Invite Grace Hopper to connect">Connect</button></aside></main>`
+)
+
+func TestDetectLayout(t *testing.T) {
+	log := &activityLog{}
+	SetRecorder(log)
+	t.Cleanup(func() { SetRecorder(nil) })
+	mu.Lock()
+	lastLayout = ""
+	mu.Unlock()
+
+	var layouts []string
+	for _, html := range []string{classicProfile, classicProfile, topCardProfile, topCardProfile, classicProfile} {
+		layouts = append(layouts, DetectLayout(pagetest.New("https://www.linkedin.com/in/ada-lovelace/", html)))
+	}
+
+	want := []string{LayoutClassic, LayoutClassic, LayoutTopCard, LayoutTopCard, LayoutClassic}
+	for i := range want {
+		if layouts[i] != want[i] {
+			t.Fatalf("layouts = %v, want %v", layouts, want)
+		}
+	}
+
+	// Only changes are recorded, the first detection among them
+	var changes []string
+	for _, entry := range log.entries {
+		if strings.HasPrefix(entry, "profile_layout: ") {
+			changes = append(changes, entry)
+		}
+	}
+	if len(changes) != 3 || changes[1] != "profile_layout: "+LayoutTopCard {
+		t.Fatalf("recorded %q, want the three layout changes", changes)
+	}
+}
+
+func TestForLayout(t *testing.T) {
+	for _, tt := range []struct {
+		name, layout, want string
+	}{
+		{ConnectButton, LayoutClassic, ConnectButton},
+		{ConnectButton, "", ConnectButton},
+		{ConnectButton, LayoutTopCard, TopCardConnectButton},
+		{MessageButton, LayoutTopCard, TopCardMessageButton},
+		{AddNoteButton, LayoutTopCard, TopCardAddNoteButton},
+		{InviteSendButton, LayoutTopCard, TopCardInviteSendButton},
+		{ProfileMoreButton, LayoutTopCard, ProfileMoreButton},
+	} {
+		if got := ForLayout(tt.name, tt.layout); got != tt.want {
+			t.Errorf("ForLayout(%s, %s) = %s, want %s", tt.name, tt.layout, got, tt.want)
+		}
+	}
+}
+
+func TestTopCardConnectButtonIsTheHeaders(t *testing.T) {
+	page := pagetest.New("https://www.linkedin.com/in/ada-lovelace/", topCardProfile)
+
+	button, err := FindFirst(page, ForLayout(ConnectButton, DetectLayout(page)))
+	if err != nil {
+		t.Fatalf("FindFirst: %v", err)
+	}
+	if label, _ := button.Property("aria-label"); label != "Invite Ada Lovelace to connect" {
+		t.Fatalf("found the button %q, want the top card's", label)
+	}
+}
+
+func TestForSheet(t *testing.T) {
+	for _, tt := range []struct {
+		name, want string
+	}{
+		{AddNoteButton, SheetAddNoteButton},
+		{NoteTextarea, SheetNoteTextarea},
+		{InviteSendButton, SheetSendButton},
+		{InviteDismissButton, SheetDismissButton},
+		{InviteModal, InviteBottomSheet},
+		{ConnectButton, ConnectButton},
+	} {
+		if got := ForSheet(tt.name); got != tt.want {
+			t.Errorf("ForSheet(%s) = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package selectors
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+)
+
+// Labels are the button texts that differ with the language LinkedIn's UI is shown in.
+// A text pattern names one as {Label}; it is expanded to the label's English text and
+// its text in the current language, so English strings LinkedIn hasn't translated still match.
+const (
+	LabelConnect          = "Connect"
+	LabelSend             = "Send"
+	LabelSendWithoutNote  = "SendWithoutNote"
+	LabelNext             = "Next"
+	LabelMessage          = "Message"
+	LabelAddNote          = "AddNote"
+	LabelPending          = "Pending"
+	LabelFollow           = "Follow"
+	LabelAccept           = "Accept"
+	LabelIgnore           = "Ignore"
+	LabelCancel           = "Cancel"
+	LabelShowMore         = "ShowMore"
+	LabelSeeMore          = "SeeMore"
+	LabelNotNow           = "NotNow"
+	LabelPhonePrompt      = "PhonePrompt"
+	LabelMore             = "More"
+	LabelFollowing        = "Following"
+	LabelUnfollow         = "Unfollow"
+	LabelRemove           = "Remove"
+	LabelRemoveConnection = "RemoveConnection"
+)
+
+// DefaultLanguage is the language labels fall back to
+const DefaultLanguage = "en"
+
+// labels maps each label to a regular expression per language. Each is a fragment
+// without anchors or flags, as the patterns naming it add those.
+var labels = map[string]map[string]string{
+	LabelConnect: {
+		"en": `Connect`,
+		"de": `Vernetzen`,
+		"fr": `Se connecter`,
+		"es": `Conectar`,
+		"pt": `Conectar`,
+	},
+	LabelSend: {
+		"en": `Send`,
+		"de": `Senden`,
+		"fr": `Envoyer`,
+		"es": `Enviar`,
+		"pt": `Enviar`,
+	},
+	LabelSendWithoutNote: {
+		"en": `Send without a note`,
+		"de": `Ohne Notiz senden`,
+		"fr": `Envoyer sans note`,
+		"es": `Enviar sin nota`,
+		"pt": `Enviar sem nota`,
+	},
+	LabelNext: {
+		"en": `Next`,
+		"de": `Weiter`,
+		"fr": `Suivant`,
+		"es": `Siguiente`,
+		"pt": `Avançar|Próximo`,
+	},
+	LabelMessage: {
+		"en": `Message`,
+		"de": `Nachricht`,
+		"fr": `Message`,
+		"es": `Mensaje`,
+		"pt": `Mensagem`,
+	},
+	LabelAddNote: {
+		"en": `Add a note`,
+		"de": `Notiz hinzufügen`,
+		"fr": `Ajouter une note`,
+		"es": `(Añadir|Agregar) una nota`,
+		"pt": `Adicionar( uma)? nota`,
+	},
+	LabelPending: {
+		"en": `Pending`,
+		"de": `Ausstehend`,
+		"fr": `En attente`,
+		"es": `Pendiente`,
+		"pt": `Pendente`,
+	},
+	LabelFollow: {
+		"en": `Follow|Following`,
+		"de": `Folgen|Folge ich|Gefolgt`,
+		"fr": `Suivre|Suivi|Abonné\(e\)|Abonné`,
+		"es": `Seguir|Siguiendo`,
+		"pt": `Seguir|Seguindo`,
+	},
+	LabelAccept: {
+		"en": `Accept`,
+		"de": `Annehmen|Akzeptieren`,
+		"fr": `Accepter`,
+		"es": `Aceptar`,
+		"pt": `Aceitar`,
+	},
+	LabelIgnore: {
+		"en": `Ignore`,
+		"de": `Ignorieren`,
+		"fr": `Ignorer`,
+		"es": `Ignorar`,
+		"pt": `Ignorar`,
+	},
+	LabelCancel: {
+		"en": `Cancel`,
+		"de": `Abbrechen`,
+		"fr": `Annuler`,
+		"es": `Cancelar`,
+		"pt": `Cancelar`,
+	},
+	LabelShowMore: {
+		"en": `Show more( results)?`,
+		"de": `Mehr (anzeigen|Ergebnisse anzeigen)`,
+		"fr": `Afficher plus( de résultats)?`,
+		"es": `Mostrar más( resultados)?`,
+		"pt": `(Exibir|Mostrar) mais( resultados)?`,
+	},
+	LabelSeeMore: {
+		"en": `see more`,
+		"de": `mehr anzeigen`,
+		"fr": `voir plus`,
+		"es": `ver más`,
+		"pt": `ver mais`,
+	},
+	LabelNotNow: {
+		"en": `skip|not now|dismiss`,
+		"de": `Überspringen|Nicht jetzt|Verwerfen`,
+		"fr": `Passer|Pas maintenant|Ignorer`,
+		"es": `Omitir|Ahora no|Descartar`,
+		"pt": `Pular|Agora não|Descartar`,
+	},
+	LabelPhonePrompt: {
+		"en": `(add|confirm|verify) your phone( number)?`,
+		"de": `Telefonnummer (hinzufügen|bestätigen|verifizieren)`,
+		"fr": `(ajoutez|confirmez|vérifiez) votre (numéro de )?téléphone`,
+		"es": `(añade|agrega|confirma|verifica) tu (número de )?teléfono`,
+		"pt": `(adicione|confirme|verifique) (o )?seu (número de )?telefone`,
+	},
+	LabelMore: {
+		"en": `More`,
+		"de": `Mehr`,
+		"fr": `Plus`,
+		"es": `Más`,
+		"pt": `Mais`,
+	},
+	LabelFollowing: {
+		"en": `Following`,
+		"de": `Folge ich|Gefolgt`,
+		"fr": `Suivi|Abonné\(e\)|Abonné`,
+		"es": `Siguiendo`,
+		"pt": `Seguindo`,
+	},
+	LabelUnfollow: {
+		"en": `Unfollow`,
+		"de": `Nicht mehr folgen`,
+		"fr": `Ne plus suivre`,
+		"es": `Dejar de seguir`,
+		"pt": `Deixar de seguir`,
+	},
+	LabelRemove: {
+		"en": `Remove`,
+		"de": `Entfernen`,
+		"fr": `Retirer|Supprimer`,
+		"es": `Eliminar`,
+		"pt": `Remover`,
+	},
+	LabelRemoveConnection: {
+		"en": `Remove connection`,
+		"de": `Kontakt entfernen`,
+		"fr": `Retirer la relation`,
+		"es": `Eliminar contacto`,
+		"pt": `Remover conexão`,
+	},
+}
+
+// labelRef matches a {Label} reference in a text pattern
+var labelRef = regexp.MustCompile(`\{([A-Za-z]+)\}`)
+
+// language is the UI language labels are expanded for
+var language = DefaultLanguage
+
+func init() {
+	// Interruption texts live in pageops, which can't import this package
+	pageops.SetTextExpander(ExpandLabels)
+}
+
+// Languages returns the UI languages labels are translated into
+func Languages() []string {
+	var langs []string
+	for lang := range labels[LabelConnect] {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// NormalizeLanguage reduces a language tag such as de-DE or pt_BR to the language the
+// labels are keyed by, and reports whether it is one of them
+func NormalizeLanguage(tag string) (string, bool) {
+	lang := strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	_, ok := labels[LabelConnect][lang]
+	return lang, ok
+}
+
+// SetLanguage expands every text pattern for the given UI language from now on
+func SetLanguage(tag string) error {
+	lang, ok := NormalizeLanguage(tag)
+	if !ok {
+		return fmt.Errorf("unsupported UI language %q (supported: %s)", tag, strings.Join(Languages(), ", "))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if lang == language {
+		return nil
+	}
+	language = lang
+
+	for name, chain := range chains {
+		for i := range chain {
+			if err := chain[i].compile(); err != nil {
+				return fmt.Errorf("selector %q variant %d: %w", name, i+1, err)
+			}
+		}
+	}
+	logger.Infof("Matching LinkedIn's UI texts in language %q", lang)
+	return nil
+}
+
+// Language returns the UI language text patterns are expanded for
+func Language() string {
+	mu.Lock()
+	defer mu.Unlock()
+	return language
+}
+
+// ExpandLabels replaces each {Label} in pattern with the label's texts in English and the
+// current language. Unknown names are left as they are.
+func ExpandLabels(pattern string) string {
+	mu.Lock()
+	lang := language
+	mu.Unlock()
+	return expandLabels(pattern, lang)
+}
+
+// expandLabels replaces each {Label} in pattern with its texts in English and lang
+func expandLabels(pattern, lang string) string {
+	return labelRef.ReplaceAllStringFunc(pattern, func(ref string) string {
+		texts, ok := labels[ref[1:len(ref)-1]]
+		if !ok {
+			return ref
+		}
+		if lang == DefaultLanguage || texts[lang] == "" {
+			return "(?:" + texts[DefaultLanguage] + ")"
+		}
+		return "(?:" + texts[DefaultLanguage] + "|" + texts[lang] + ")"
+	})
+}
@@ -0,0 +1,94 @@
+package selectors
+
+import (
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops/pagetest"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger("error", "console", "stdout", logger.Rotation{})
+	os.Exit(m.Run())
+}
+
+func TestLabelsCoverEveryLanguage(t *testing.T) {
+	for name, texts := range labels {
+		for _, lang := range Languages() {
+			text, ok := texts[lang]
+			if !ok || text == "" {
+				t.Errorf("%s has no %s text", name, lang)
+				continue
+			}
+			if _, err := regexp.Compile(expandLabels("(?i)^{"+name+"}$", lang)); err != nil {
+				t.Errorf("%s in %s doesn't compile: %v", name, lang, err)
+			}
+		}
+	}
+}
+
+func TestExpandLabels(t *testing.T) {
+	for _, tt := range []struct {
+		pattern, lang, want string
+	}{
+		{`(?i)^\s*{Connect}\s*$`, "en", `(?i)^\s*(?:Connect)\s*$`},
+		{`(?i)^\s*{Connect}\s*$`, "de", `(?i)^\s*(?:Connect|Vernetzen)\s*$`},
+		{`{Next}`, "pt", `(?:Next|Avançar|Próximo)`},
+		{`{Send} or {Cancel}`, "fr", `(?:Send|Envoyer) or (?:Cancel|Annuler)`},
+		{`{Unknown}`, "de", `{Unknown}`},
+	} {
+		if got := expandLabels(tt.pattern, tt.lang); got != tt.want {
+			t.Errorf("expandLabels(%q, %s) = %q, want %q", tt.pattern, tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+	for tag, want := range map[string]string{"de-DE": "de", "pt_BR": "pt", " FR ": "fr", "en": "en"} {
+		if lang, ok := NormalizeLanguage(tag); !ok || lang != want {
+			t.Errorf("NormalizeLanguage(%q) = %s, %v; want %s", tag, lang, ok, want)
+		}
+	}
+	if _, ok := NormalizeLanguage("ja-JP"); ok {
+		t.Error("ja is supported")
+	}
+	if err := SetLanguage("ja-JP"); err == nil {
+		t.Error("SetLanguage accepted an unsupported language")
+	}
+}
+
+func TestSetLanguageMatchesTranslatedButtons(t *testing.T) {
+	t.Cleanup(func() { SetLanguage(DefaultLanguage) })
+
+	german := pagetest.New("https://www.linkedin.com/in/ada/", `<main><section class="pv-top-card">
+		<div class="pvs-profile-actions"><button>Nachricht</button><button>Vernetzen</button></div></section></main>`)
+	untranslated := pagetest.New("https://www.linkedin.com/in/ada/", `<main><section class="pv-top-card">
+		<div class="pvs-profile-actions"><button>Connect</button></div></section></main>`)
+
+	if Has(german, ConnectButton) {
+		t.Fatal("found Vernetzen while matching English")
+	}
+
+	if err := SetLanguage("de-DE"); err != nil {
+		t.Fatal(err)
+	}
+	if Language() != "de" {
+		t.Fatalf("Language = %s", Language())
+	}
+	connect, err := FindFirst(german, ConnectButton)
+	if err != nil {
+		t.Fatalf("Connect in German: %v", err)
+	}
+	if text, _ := connect.Text(); text != "Vernetzen" {
+		t.Fatalf("found %q", text)
+	}
+	if !Has(german, ProfileMessageButton) {
+		t.Fatal("Nachricht isn't the Message button")
+	}
+	// English texts LinkedIn left untranslated still match
+	if !Has(untranslated, ConnectButton) {
+		t.Fatal("English Connect no longer matches")
+	}
+}
@@ -0,0 +1,248 @@
+// Package selectors keeps every DOM selector the bot uses in one registry.
+// Each name maps to an ordered fallback chain that can be overridden from
+// configs/selectors.yaml when LinkedIn changes its markup.
+package selectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+)
+
+// Variant is a single way of locating an element: a CSS selector and an
+// optional regular expression the element's text must match, which may name
+// labels as {Label} to match them in the UI language
+type Variant struct {
+	CSS  string `yaml:"css"`
+	Text string `yaml:"text"`
+
+	pattern *regexp.Regexp
+}
+
+// UnmarshalYAML accepts both a plain CSS string and the mapping form
+func (v *Variant) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		v.CSS = value.Value
+		return nil
+	}
+
+	type plain Variant
+	var p plain
+	if err := value.Decode(&p); err != nil {
+		return err
+	}
+
+	*v = Variant(p)
+	return nil
+}
+
+// String returns a readable form of the variant for logs
+func (v Variant) String() string {
+	if v.Text == "" {
+		return v.CSS
+	}
+	return fmt.Sprintf("%s /%s/", v.CSS, v.Text)
+}
+
+// Scope is anything elements can be looked up in: a page or an element
+type Scope interface {
+	Elements(selector string) ([]pageops.Element, error)
+}
+
+// Recorder stores which selector variants matched; *storage.DB satisfies it
+type Recorder interface {
+	LogActivity(action, details string) error
+}
+
+var (
+	mu       sync.Mutex
+	chains   = defaultChains()
+	recorder Recorder
+	matched  = make(map[string]int) // last recorded variant index per name
+)
+
+// Load replaces the default chains with the ones defined in path.
+// Names missing from the file keep their built-in chain; a missing file is not an error.
+func Load(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read selectors file: %w", err)
+	}
+
+	var overrides map[string][]Variant
+	if err := yaml.Unmarshal(data, &overrides); err != nil {
+		return fmt.Errorf("failed to parse selectors file: %w", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for name, chain := range overrides {
+		if _, ok := chains[name]; !ok {
+			return fmt.Errorf("unknown selector name %q", name)
+		}
+		if len(chain) == 0 {
+			return fmt.Errorf("selector %q must have at least one variant", name)
+		}
+		for i := range chain {
+			if chain[i].CSS == "" {
+				return fmt.Errorf("selector %q variant %d has no css", name, i+1)
+			}
+			if err := chain[i].compile(); err != nil {
+				return fmt.Errorf("selector %q variant %d: %w", name, i+1, err)
+			}
+		}
+		chains[name] = chain
+		logger.Infof("Selector %s overridden with %d variants", name, len(chain))
+	}
+
+	return nil
+}
+
+// SetRecorder sets where matched variants are recorded
+func SetRecorder(r Recorder) {
+	mu.Lock()
+	defer mu.Unlock()
+	recorder = r
+}
+
+// FindFirst walks the chain for name and returns the first element any variant matches.
+// It doesn't wait for elements to appear.
+func FindFirst(scope Scope, name string) (pageops.Element, error) {
+	els, err := find(scope, name, true)
+	if err != nil {
+		return nil, err
+	}
+	return els[0], nil
+}
+
+// FindAll returns every element matched by the first variant in the chain that matches anything
+func FindAll(scope Scope, name string) ([]pageops.Element, error) {
+	return find(scope, name, false)
+}
+
+// Has reports whether any variant for name matches
+func Has(scope Scope, name string) bool {
+	_, err := FindFirst(scope, name)
+	return err == nil
+}
+
+// WaitFirst retries FindFirst until an element appears or timeout passes
+func WaitFirst(scope Scope, name string, timeout time.Duration) (pageops.Element, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		el, err := FindFirst(scope, name)
+		if err == nil || done(err) || time.Now().After(deadline) {
+			return el, err
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
+
+// find walks the chain for name; with first set it stops at the first matching element
+func find(scope Scope, name string, first bool) ([]pageops.Element, error) {
+	mu.Lock()
+	chain, ok := chains[name]
+	mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown selector name %q", name)
+	}
+
+	for i, variant := range chain {
+		els, err := scope.Elements(variant.CSS)
+		if done(err) {
+			// The page's context is over, so every other variant would fail alike
+			return nil, fmt.Errorf("failed to look up %s: %w", name, err)
+		}
+		if err != nil || len(els) == 0 {
+			continue
+		}
+
+		if variant.pattern != nil {
+			els = filterText(els, variant.pattern, first)
+			if len(els) == 0 {
+				continue
+			}
+		} else if first {
+			els = els[:1]
+		}
+
+		noteMatch(name, i, chain)
+		return els, nil
+	}
+
+	return nil, fmt.Errorf("%s not found (%d selectors tried)", name, len(chain))
+}
+
+// done reports whether err says the page's context was cancelled or ran out of time
+func done(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// filterText keeps the elements whose text matches pattern
+func filterText(els []pageops.Element, pattern *regexp.Regexp, first bool) []pageops.Element {
+	var kept []pageops.Element
+	for _, el := range els {
+		text, err := el.Text()
+		if err != nil || !pattern.MatchString(text) {
+			continue
+		}
+		kept = append(kept, el)
+		if first {
+			break
+		}
+	}
+	return kept
+}
+
+// noteMatch records the matched variant when it differs from the last one recorded for name,
+// and warns when only the last resort still works
+func noteMatch(name string, index int, chain []Variant) {
+	mu.Lock()
+	last, seen := matched[name]
+	matched[name] = index
+	r := recorder
+	mu.Unlock()
+
+	if seen && last == index {
+		return
+	}
+
+	details := fmt.Sprintf("%s matched variant %d/%d: %s", name, index+1, len(chain), chain[index])
+	logger.Debugf("Selector %s", details)
+	if r != nil {
+		r.LogActivity("selector_match", details)
+	}
+
+	if len(chain) > 1 && index == len(chain)-1 {
+		logger.Warnf("Only the last-resort selector for %s still matches (%s); update configs/selectors.yaml", name, chain[index])
+	}
+}
+
+// compile prepares the variant's text pattern for the current UI language; callers hold mu
+func (v *Variant) compile() error {
+	if v.Text == "" {
+		v.pattern = nil
+		return nil
+	}
+
+	re, err := regexp.Compile(expandLabels(v.Text, language))
+	if err != nil {
+		return fmt.Errorf("invalid text pattern %q: %w", v.Text, err)
+	}
+	v.pattern = re
+	return nil
+}
@@ -0,0 +1,394 @@
+// Package session opens a browser that presents one fingerprint and sets up the stealth
+// components that drive its tabs, as both the bot and the embeddable client need.
+package session
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/diagnostics"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/selectors"
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+	"github.com/Tanukumar01/linkedin-automation/pkg/browser"
+)
+
+// Options adjust how a session is opened
+type Options struct {
+	// Fingerprint picks the persona the browser presents; nil picks a new one
+	Fingerprint func(masker *stealth.FingerprintMasker) stealth.Fingerprint
+	// Navigations counts page loads against browser.daily_navigation_limit; nil leaves them unlimited
+	Navigations pageops.NavigationCounter
+	// Diagnostics records failed API calls and console errors when diagnostics are enabled; nil records none
+	Diagnostics diagnostics.Recorder
+}
+
+// The tabs a session opens. Each kind of work keeps to its own tab, so checking messages
+// doesn't navigate away from a profile halfway through a connect flow.
+const (
+	TabSearch    = "search"    // the first tab, where login happens: searches and harvesting
+	TabProfile   = "profile"   // profile visits: connecting, engaging, enriching and idle browsing
+	TabMessaging = "messaging" // conversations, incoming invitations and accepted connections
+)
+
+// Tabs lists the tabs in the order they are opened
+var Tabs = []string{TabSearch, TabProfile, TabMessaging}
+
+// Tab is one browser tab with the stealth components that drive it
+type Tab struct {
+	Name  string
+	Page  *rod.Page
+	Mouse *stealth.MouseMover
+
+	// Page interactions routed through the stealth components
+	Pages    *pageops.RodPage
+	Typer    pageops.TextTyper
+	Clicker  pageops.Clicker
+	Scroller pageops.Scroller
+}
+
+// Session is an open browser with the stealth components that drive its tabs
+type Session struct {
+	Browser     *browser.Browser
+	Masker      *stealth.FingerprintMasker
+	Fingerprint stealth.Fingerprint
+	Metrics     *stealth.SessionMetrics
+
+	Timing  *stealth.TimingController
+	Fatigue *stealth.Fatigue // nil unless stealth.fatigue is enabled; the scheduler feeds it active time
+	Persona *stealth.Persona // the pace sampled for this session; nil unless stealth.persona is enabled
+
+	// The search tab's page and components, which login and Prepare work on
+	Page     *rod.Page
+	Mouse    *stealth.MouseMover
+	Pages    *pageops.RodPage
+	Typer    pageops.TextTyper
+	Clicker  pageops.Clicker
+	Scroller pageops.Scroller
+
+	// Shared by the tabs, so typing and scrolling behave alike in each
+	typer    *stealth.Typer
+	scroller *stealth.Scroller
+
+	tabs map[string]*Tab
+	mu   sync.Mutex
+	// active is the tab in front; nil until one navigates
+	active *Tab
+
+	userDataDir string
+}
+
+// Open launches a browser set up as cfg describes. Close it once done.
+func Open(cfg *config.Config, opts Options) (_ *Session, err error) {
+	s := &Session{}
+	defer func() {
+		if err != nil {
+			s.Close()
+		}
+	}()
+
+	// Use temp dir for browser data to avoid OneDrive syncing/locking issues
+	s.userDataDir = filepath.Join(os.TempDir(), fmt.Sprintf("linkedin-bot-browser-data-%d", time.Now().UnixNano()))
+	if err := os.MkdirAll(s.userDataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create browser data directory: %w", err)
+	}
+	logger.Infof("Using browser data directory: %s", s.userDataDir)
+
+	s.Masker = stealth.NewFingerprintMasker(cfg.Browser.UserAgents, Viewports(cfg.Browser.Viewports), Hardware(cfg.Browser.Hardware))
+	if opts.Fingerprint != nil {
+		s.Fingerprint = opts.Fingerprint(s.Masker)
+	} else {
+		s.Fingerprint = s.Masker.NewFingerprint(cfg.Stealth.Scheduling.Timezone, cfg.Locale)
+	}
+	fp := s.Fingerprint
+
+	// Launch the browser to match the fingerprint, so its flags don't contradict what the pages present
+	windowWidth, windowHeight := fp.Viewport.WindowSize()
+	s.Browser, err = browser.NewBrowser(cfg.Browser.Headless, s.userDataDir, cfg.Browser.TimeoutSeconds, browser.LaunchOptions{
+		UserAgent:    fp.UserAgent,
+		WindowWidth:  windowWidth,
+		WindowHeight: windowHeight,
+		Languages:    fp.Languages,
+		ExtraArgs:    cfg.Browser.ExtraArgs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize browser: %w", err)
+	}
+
+	logger.Info("Browser initialized")
+
+	// Every tab opened from here on is shaped alike
+	if err := s.Browser.ShapeTraffic(browser.TrafficOptions{
+		BlockResources: cfg.Browser.BlockResources,
+		AllowHosts:     cfg.Browser.AllowHosts,
+		DownloadKbps:   cfg.Browser.Network.DownloadKbps,
+		UploadKbps:     cfg.Browser.Network.UploadKbps,
+		LatencyMs:      cfg.Browser.Network.LatencyMs,
+	}); err != nil {
+		logger.Warnf("Failed to shape network traffic: %v", err)
+	}
+
+	logger.Infof("Using User-Agent: %s", fp.UserAgent)
+	logger.Infof("Using viewport %s (%dx%d @%gx), timezone %q, languages %v, platform %q", fp.Viewport.Label, fp.Viewport.Width, fp.Viewport.Height,
+		fp.Viewport.DeviceScaleFactor, fp.Timezone, fp.Languages, fp.Platform)
+
+	s.setUpStealth(cfg)
+	logger.Info("Stealth components initialized")
+
+	s.tabs = make(map[string]*Tab, len(Tabs))
+	for _, name := range Tabs {
+		tab, err := s.openTab(cfg, opts, name)
+		if err != nil {
+			return nil, err
+		}
+		s.tabs[name] = tab
+	}
+
+	search := s.tabs[TabSearch]
+	s.Page, s.Mouse = search.Page, search.Mouse
+	s.Pages, s.Typer, s.Clicker, s.Scroller = search.Pages, search.Typer, search.Clicker, search.Scroller
+
+	return s, nil
+}
+
+// openTab opens a tab presenting the session's fingerprint, its interactions routed
+// through the shared stealth components
+func (s *Session) openTab(cfg *config.Config, opts Options, name string) (*Tab, error) {
+	fp := s.Fingerprint
+	page, err := s.Browser.NewPage(fp.UserAgent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s tab: %w", name, err)
+	}
+
+	if err := s.Masker.Apply(page, fp); err != nil {
+		logger.Warnf("Failed to apply fingerprint to the %s tab: %v", name, err)
+	}
+
+	// Apply fingerprint masking
+	if err := s.Masker.ApplyStealthScripts(page); err != nil {
+		logger.Warnf("Failed to apply stealth scripts to the %s tab: %v", name, err)
+	}
+
+	// Throttled API calls are often the first sign of being flagged, before any page shows it
+	if opts.Diagnostics != nil && cfg.Diagnostics.Enabled {
+		diagnostics.Listen(page, opts.Diagnostics, cfg.Diagnostics.MaxRows)
+	}
+
+	t := &Tab{Name: name, Page: page}
+	t.Mouse = stealth.NewMouseMover(
+		page,
+		cfg.Stealth.Mouse.BezierPoints,
+		cfg.Stealth.Mouse.SpeedVariation,
+		cfg.Stealth.Mouse.OvershootProbability,
+		cfg.Stealth.Mouse.MicroCorrectionProbability,
+	)
+	t.Mouse.SetMetrics(s.Metrics)
+
+	t.Pages = pageops.NewRodPage(page)
+	// A tab is brought to the front before it loads a page, as a person switching to it would
+	t.Pages.BeforeNavigate(func() { s.activate(t) })
+	t.Pages.OnNavigate(t.Mouse.ResetPosition)
+	if opts.Navigations != nil {
+		t.Pages.SetNavigationLimit(opts.Navigations, cfg.Browser.DailyNavigationLimit)
+	}
+	t.Typer = pageops.NewRodTyper(page, s.typer)
+	// A click an interruption covers dismisses it and tries again
+	t.Clicker = pageops.NewInterruptionGuard(t.Pages, pageops.NewRodClicker(t.Mouse))
+	t.Scroller = pageops.NewRodScroller(page, s.scroller)
+	return t, nil
+}
+
+// Tab returns the named tab, one of Tabs; nil for any other name
+func (s *Session) Tab(name string) *Tab {
+	return s.tabs[name]
+}
+
+// Active returns the tab in front, which shows the page last navigated to; the search
+// tab before any has navigated
+func (s *Session) Active() *Tab {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.active == nil {
+		return s.tabs[TabSearch]
+	}
+	return s.active
+}
+
+// activate brings t to the front, unless it already is. A tab left in the background
+// reports itself hidden to the page, which no one clicking in it would be.
+func (s *Session) activate(t *Tab) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.active == t {
+		return
+	}
+	if _, err := t.Page.Activate(); err != nil {
+		logger.Debugf("Failed to bring the %s tab to the front: %v", t.Name, err)
+		return
+	}
+	s.active = t
+}
+
+// SetCheckpointGuard has every tab's navigations wait on checkpoints LinkedIn redirects to
+func (s *Session) SetCheckpointGuard(guard *pageops.CheckpointGuard) {
+	for _, t := range s.tabs {
+		t.Pages.SetCheckpointGuard(guard)
+	}
+}
+
+// setUpStealth creates the stealth controllers the tabs share, paced by a persona
+// sampled for the session
+func (s *Session) setUpStealth(cfg *config.Config) {
+	pace := stealth.NeutralPersona
+	if c := cfg.Stealth.Persona; c.Enabled {
+		pace = stealth.NewPersonaSampler(c.Spread, time.Now().UnixNano()).Sample()
+		s.Persona = &pace
+		logger.Infof("Session persona: %s (delays x%.2f, typing x%.2f, typos x%.2f, scrolling x%.2f)",
+			pace.Name, pace.Delays, pace.Typing, pace.Typos, pace.Scroll)
+	}
+
+	s.Timing = stealth.NewTimingController(
+		cfg.Stealth.Timing.ActionDelayMin,
+		cfg.Stealth.Timing.ActionDelayMax,
+		cfg.Stealth.Timing.ThinkTimeMin,
+		cfg.Stealth.Timing.ThinkTimeMax,
+		cfg.Stealth.Timing.ReadingSpeedWPM,
+	)
+	s.Timing.SetPace(pace.Delays)
+
+	typer := stealth.NewTyper(
+		max(scaleInt(cfg.Stealth.Typing.WPMMin, pace.Typing), 1),
+		max(scaleInt(cfg.Stealth.Typing.WPMMax, pace.Typing), 1),
+		min(cfg.Stealth.Typing.TypoProbability*pace.Typos, 1),
+		cfg.Stealth.Typing.PauseProbability,
+	)
+	typos := cfg.Stealth.Typing.Typos
+	typer.SetTypoWeights(stealth.TypoWeights{
+		Adjacent:       typos.Adjacent,
+		Doubled:        typos.Doubled,
+		Transposed:     typos.Transposed,
+		LateCorrection: typos.LateCorrection,
+	})
+
+	scroller := stealth.NewScroller(
+		scaleInt(cfg.Stealth.Scrolling.SpeedMin, pace.Scroll),
+		scaleInt(cfg.Stealth.Scrolling.SpeedMax, pace.Scroll),
+		cfg.Stealth.Scrolling.ScrollBackProbability,
+		cfg.Stealth.Scrolling.PauseProbability,
+	)
+
+	// Delays and typing slow down as the session wears on
+	if f := cfg.Stealth.Fatigue; f.Enabled {
+		s.Fatigue = stealth.NewFatigue(f.MaxSlowdown, time.Duration(f.RampMinutes)*time.Minute)
+		s.Timing.SetFatigue(s.Fatigue)
+		typer.SetFatigue(s.Fatigue)
+	}
+
+	// Record realized stealth behavior for tuning
+	s.Metrics = stealth.NewSessionMetrics()
+	typer.SetMetrics(s.Metrics)
+	scroller.SetMetrics(s.Metrics)
+	s.typer, s.scroller = typer, scroller
+}
+
+// scaleInt scales a configured value by a persona's factor
+func scaleInt(v int, factor float64) int {
+	return int(math.Round(float64(v) * factor))
+}
+
+// Close closes the browser with all its tabs and removes its data directory
+func (s *Session) Close() {
+	if s.Browser != nil {
+		s.Browser.Close()
+	}
+	if s.userDataDir != "" {
+		os.RemoveAll(s.userDataDir)
+	}
+}
+
+// Prepare readies the page for work once logged in: it checks the stealth masking took,
+// matches button texts in the account's UI language and collapses the messaging list
+func (s *Session) Prepare(uiLanguage string) {
+	// Login navigated the page, so the stealth script should be in effect by now
+	if err := s.Masker.VerifyMasking(s.Page); err != nil {
+		logger.Warnf("Stealth masking check failed: %v", err)
+	}
+
+	// Buttons are found by their text, which follows the account's UI language
+	s.setUILanguage(uiLanguage)
+
+	// The docked conversation list covers the bottom of every page while open
+	if err := pageops.CollapseMessagingOverlay(s.Pages, s.Clicker); err != nil {
+		logger.Warnf("%v", err)
+	}
+}
+
+// setUILanguage matches button texts in the configured UI language, or in the one the
+// page declares when it is auto
+func (s *Session) setUILanguage(configured string) {
+	lang := configured
+	if lang == config.UILanguageAuto {
+		detected, err := pageLanguage(s.Pages)
+		if err != nil {
+			logger.Warnf("Failed to detect LinkedIn's UI language, matching English texts: %v", err)
+			return
+		}
+		if _, ok := selectors.NormalizeLanguage(detected); !ok {
+			logger.Warnf("LinkedIn's UI language %q has no translated texts, matching English ones", detected)
+			return
+		}
+		lang = detected
+	}
+
+	if err := selectors.SetLanguage(lang); err != nil {
+		logger.Warnf("%v", err)
+	}
+}
+
+// pageLanguage returns the language the page declares on its html element
+func pageLanguage(page pageops.Page) (string, error) {
+	evaluator, ok := page.(pageops.Evaluator)
+	if !ok {
+		return "", fmt.Errorf("page can't run scripts")
+	}
+	lang, err := evaluator.Eval(`() => document.documentElement.lang`)
+	if err != nil {
+		return "", err
+	}
+	if lang == "" {
+		return "", fmt.Errorf("the page declares no language")
+	}
+	return lang, nil
+}
+
+// Viewports converts the configured viewports for the fingerprint masker
+func Viewports(configured []config.ViewportConfig) []stealth.Viewport {
+	out := make([]stealth.Viewport, len(configured))
+	for i, v := range configured {
+		out[i] = stealth.Viewport{Width: v.Width, Height: v.Height, DeviceScaleFactor: v.DeviceScaleFactor, Label: v.Label}
+	}
+	return out
+}
+
+// Hardware converts the configured hardware profiles for the fingerprint masker
+func Hardware(configured []config.HardwareConfig) []stealth.Hardware {
+	out := make([]stealth.Hardware, len(configured))
+	for i, h := range configured {
+		out[i] = stealth.Hardware{
+			Platform:            h.Platform,
+			WebGLVendor:         h.WebGLVendor,
+			WebGLRenderer:       h.WebGLRenderer,
+			HardwareConcurrency: h.HardwareConcurrency,
+			DeviceMemory:        h.DeviceMemory,
+		}
+	}
+	return out
+}
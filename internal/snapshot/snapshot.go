@@ -0,0 +1,145 @@
+// Package snapshot records the effective configuration and browser persona of a
+// run, so a challenge can be traced back to the settings that were in effect.
+//
+// The config is redacted, then serialized canonically: YAML field names, map
+// keys sorted, as JSON. Identical settings therefore always hash the same.
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// Persona is the browser identity a run presented
+type Persona struct {
+	UserAgent      string `json:"user_agent"`
+	ViewportWidth  int    `json:"viewport_width"`
+	ViewportHeight int    `json:"viewport_height"`
+}
+
+// Snapshot is the redacted effective config and persona of a run
+type Snapshot struct {
+	Hash    string          `json:"hash"`
+	Persona Persona         `json:"persona"`
+	Config  json.RawMessage `json:"config"`
+}
+
+// Change is one setting that differs between two snapshots; an empty side means the setting was absent
+type Change struct {
+	Path string
+	Old  string
+	New  string
+}
+
+// Take snapshots cfg, with secrets redacted, together with persona
+func Take(cfg *config.Config, persona Persona) (*Snapshot, error) {
+	// Round-trip through YAML so keys match the config file
+	data, err := yaml.Marshal(cfg.Redacted())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var tree interface{}
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	canonical, err := json.Marshal(tree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize config: %w", err)
+	}
+
+	s := &Snapshot{Persona: persona, Config: canonical}
+
+	hashed, err := json.Marshal(struct {
+		Persona Persona         `json:"persona"`
+		Config  json.RawMessage `json:"config"`
+	}{persona, canonical})
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize snapshot: %w", err)
+	}
+	sum := sha256.Sum256(hashed)
+	s.Hash = hex.EncodeToString(sum[:])[:16]
+
+	return s, nil
+}
+
+// Save stores the snapshot in the config_snapshots table
+func (s *Snapshot) Save(db *storage.DB) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	return db.SaveConfigSnapshot(s.Hash, data)
+}
+
+// Diff lists the settings that differ from prev to cur, sorted by path
+func Diff(prev, cur *Snapshot) ([]Change, error) {
+	before, err := prev.flatten()
+	if err != nil {
+		return nil, err
+	}
+	after, err := cur.flatten()
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	for path, value := range after {
+		if old, ok := before[path]; !ok || old != value {
+			changes = append(changes, Change{Path: path, Old: old, New: value})
+		}
+	}
+	for path, old := range before {
+		if _, ok := after[path]; !ok {
+			changes = append(changes, Change{Path: path, Old: old})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// flatten maps every leaf setting, persona included, to its dotted path
+func (s *Snapshot) flatten() (map[string]string, error) {
+	var tree interface{}
+	if err := json.Unmarshal(s.Config, &tree); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot %s: %w", s.Hash, err)
+	}
+
+	out := map[string]string{
+		"persona.user_agent": s.Persona.UserAgent,
+		"persona.viewport":   fmt.Sprintf("%dx%d", s.Persona.ViewportWidth, s.Persona.ViewportHeight),
+	}
+	flattenInto(out, "", tree)
+	return out, nil
+}
+
+// flattenInto walks a decoded JSON value and records its leaves under prefix
+func flattenInto(out map[string]string, prefix string, v interface{}) {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		for key, child := range node {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			flattenInto(out, path, child)
+		}
+	case []interface{}:
+		for i, child := range node {
+			flattenInto(out, fmt.Sprintf("%s[%d]", prefix, i), child)
+		}
+	default:
+		data, _ := json.Marshal(node)
+		out[prefix] = string(data)
+	}
+}
@@ -0,0 +1,63 @@
+package stealth
+
+import (
+	"sync"
+	"time"
+)
+
+// Fatigue models how a person slows down over a session: fresh at first, then slower and
+// sloppier as active time adds up, until a break restores them. It only counts the active
+// time it is given, so it can be driven by the scheduler or by a simulated timeline alike.
+// A nil Fatigue never tires.
+type Fatigue struct {
+	maxSlowdown float64       // how much slower than fresh a fully tired person is, e.g. 0.3 for 30%
+	ramp        time.Duration // active time until fully tired
+
+	mu     sync.Mutex
+	active time.Duration // since the last rest
+}
+
+// NewFatigue creates a fatigue model that reaches 1+maxSlowdown after ramp of active time
+func NewFatigue(maxSlowdown float64, ramp time.Duration) *Fatigue {
+	return &Fatigue{maxSlowdown: maxSlowdown, ramp: ramp}
+}
+
+// AddActive adds active time
+func (f *Fatigue) AddActive(d time.Duration) {
+	if f == nil || d <= 0 {
+		return
+	}
+	f.mu.Lock()
+	f.active += d
+	f.mu.Unlock()
+}
+
+// Rest resets the model after a break
+func (f *Fatigue) Rest() {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	f.active = 0
+	f.mu.Unlock()
+}
+
+// Multiplier returns the factor delays are stretched by and typos made more often by: 1
+// when fresh, rising slowly at first, then faster, and leveling off at 1+maxSlowdown
+// once the ramp has passed
+func (f *Fatigue) Multiplier() float64 {
+	if f == nil || f.ramp <= 0 || f.maxSlowdown <= 0 {
+		return 1
+	}
+	f.mu.Lock()
+	progress := min(float64(f.active)/float64(f.ramp), 1)
+	f.mu.Unlock()
+
+	// Smoothstep: flat at both ends of the ramp
+	return 1 + f.maxSlowdown*progress*progress*(3-2*progress)
+}
+
+// scale stretches d by the current multiplier
+func (f *Fatigue) scale(d time.Duration) time.Duration {
+	return time.Duration(float64(d) * f.Multiplier())
+}
@@ -0,0 +1,136 @@
+package stealth
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/clock"
+)
+
+func TestFatigueMultiplier(t *testing.T) {
+	for _, tt := range []struct {
+		active time.Duration
+		want   float64
+	}{
+		{0, 1},
+		{9 * time.Minute, 1.0084},
+		{45 * time.Minute, 1.15},
+		{81 * time.Minute, 1.2916},
+		{90 * time.Minute, 1.3},
+		{4 * time.Hour, 1.3},
+	} {
+		f := NewFatigue(0.3, 90*time.Minute)
+		f.AddActive(tt.active)
+		if got := f.Multiplier(); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("after %s: multiplier %v, want %v", tt.active, got, tt.want)
+		}
+	}
+
+	f := NewFatigue(0.3, 90*time.Minute)
+	f.AddActive(time.Hour)
+	f.Rest()
+	if got := f.Multiplier(); got != 1 {
+		t.Fatalf("multiplier %v after a rest, want 1", got)
+	}
+
+	var none *Fatigue
+	none.AddActive(time.Hour)
+	none.Rest()
+	if got := none.Multiplier(); got != 1 {
+		t.Fatalf("nil multiplier %v, want 1", got)
+	}
+	if got := NewFatigue(0.3, 0).Multiplier(); got != 1 {
+		t.Fatalf("multiplier %v without a ramp, want 1", got)
+	}
+}
+
+func TestTimingStretchedByFatigue(t *testing.T) {
+	timing := NewTimingController(2, 2, 4, 4, 200)
+	f := NewFatigue(0.5, time.Hour)
+	timing.SetFatigue(f)
+
+	if got := timing.ActionDelay(); got != 2*time.Second {
+		t.Fatalf("fresh action delay %s, want 2s", got)
+	}
+	f.AddActive(time.Hour)
+	if got := timing.ActionDelay(); got != 3*time.Second {
+		t.Fatalf("tired action delay %s, want 3s", got)
+	}
+	if got := timing.ThinkTime(); got != 6*time.Second {
+		t.Fatalf("tired think time %s, want 6s", got)
+	}
+}
+
+func TestTypingSlowsAndSlipsWithFatigue(t *testing.T) {
+	text := strings.Repeat("the quick brown fox jumps over the lazy dog ", 40)
+
+	// typeWith returns how long typing text took and how many keys were taken back
+	typeWith := func(f *Fatigue) (time.Duration, int) {
+		typer := newTestTyper(0.05)
+		fake := clock.NewFake(time.Now())
+		typer.SetClock(fake)
+		typer.SetFatigue(f)
+
+		kb := &recordingKeyboard{}
+		if err := typer.typeText(kb, text); err != nil {
+			t.Fatalf("typeText: %v", err)
+		}
+		if kb.text.String() != text {
+			t.Fatalf("typed %q", kb.text.String())
+		}
+
+		var took time.Duration
+		for _, d := range fake.Slept() {
+			took += d
+		}
+		backspaces := 0
+		for _, e := range kb.events {
+			if e == "down Backspace" {
+				backspaces++
+			}
+		}
+		return took, backspaces
+	}
+
+	tired := NewFatigue(1, time.Hour)
+	tired.AddActive(time.Hour)
+
+	freshTook, freshSlips := typeWith(nil)
+	tiredTook, tiredSlips := typeWith(tired)
+	if tiredTook < freshTook*5/4 {
+		t.Fatalf("tired typing took %s, fresh %s; want it slower", tiredTook, freshTook)
+	}
+	if tiredSlips <= freshSlips {
+		t.Fatalf("tired typing took back %d keys, fresh %d; want more slips", tiredSlips, freshSlips)
+	}
+}
+
+func TestSchedulerFeedsFatigue(t *testing.T) {
+	s, fake := newTestScheduler(t, time.Date(2026, time.March, 10, 14, 0, 0, 0, time.UTC))
+	f := NewFatigue(0.3, 90*time.Minute)
+	f.AddActive(time.Hour) // left over from an earlier session
+	s.SetFatigue(f)
+
+	if err := s.StartSession(); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.Multiplier(); got != 1 {
+		t.Fatalf("multiplier %v at the start of a session, want 1", got)
+	}
+
+	fake.Advance(45 * time.Minute)
+	if err := s.CheckSession(); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.Multiplier(); math.Abs(got-1.15) > 1e-9 {
+		t.Fatalf("multiplier %v halfway through the ramp, want 1.15", got)
+	}
+
+	// Waiting out a break neither tires nor counts as active
+	s.TakeBreak()
+	if got := f.Multiplier(); got != 1 {
+		t.Fatalf("multiplier %v after a break, want 1", got)
+	}
+}
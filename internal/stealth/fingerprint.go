@@ -1,28 +1,70 @@
 package stealth
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"math/rand"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
 )
 
+// Viewport is a screen: its size in CSS pixels and its device scale factor
+type Viewport struct {
+	Width             int
+	Height            int
+	DeviceScaleFactor float64
+	Label             string
+}
+
+// Hardware is the machine a fingerprint claims, as WebGL and navigator report it
+type Hardware struct {
+	Platform            string // windows, mac or linux; empty fits any user agent
+	WebGLVendor         string
+	WebGLRenderer       string
+	HardwareConcurrency int
+	DeviceMemory        float64
+}
+
+// Fingerprint is the browser identity an account presents. It is chosen once per account
+// and reused, since the same session cookie showing up in a different browser every day
+// is itself a signal.
+type Fingerprint struct {
+	UserAgent  string
+	Viewport   Viewport
+	Timezone   string   // IANA name; empty leaves the machine's timezone
+	Languages  []string // navigator.languages, most preferred first
+	Platform   string   // navigator.platform, matching the user agent
+	Hardware   Hardware // zero leaves the real values
+	CanvasSeed int64    // drives the canvas noise, so the account's canvas hash stays put; 0 for none
+}
+
+// windowChromeHeight is the height of the tab strip and toolbar above the page, which
+// makes a real window's outerHeight exceed its innerHeight
+const windowChromeHeight = 85
+
 // FingerprintMasker handles browser fingerprint masking
 type FingerprintMasker struct {
-	userAgents      []string
-	viewportWidths  []int
-	viewportHeights []int
-	rand            *rand.Rand
+	userAgents []string
+	viewports  []Viewport
+	hardware   []Hardware
+	rand       *rand.Rand
+
+	// Fingerprint applied to the page, zero until then
+	fingerprint Fingerprint
 }
 
 // NewFingerprintMasker creates a new fingerprint masker
-func NewFingerprintMasker(userAgents []string, viewportWidths, viewportHeights []int) *FingerprintMasker {
+func NewFingerprintMasker(userAgents []string, viewports []Viewport, hardware []Hardware) *FingerprintMasker {
 	return &FingerprintMasker{
-		userAgents:      userAgents,
-		viewportWidths:  viewportWidths,
-		viewportHeights: viewportHeights,
-		rand:            rand.New(rand.NewSource(time.Now().UnixNano())),
+		userAgents: userAgents,
+		viewports:  viewports,
+		hardware:   hardware,
+		rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
 
@@ -34,107 +76,434 @@ func (f *FingerprintMasker) GetRandomUserAgent() string {
 	return f.userAgents[f.rand.Intn(len(f.userAgents))]
 }
 
-// GetRandomViewport returns random viewport dimensions
-func (f *FingerprintMasker) GetRandomViewport() (int, int) {
-	width := f.viewportWidths[f.rand.Intn(len(f.viewportWidths))]
-	height := f.viewportHeights[f.rand.Intn(len(f.viewportHeights))]
-	return width, height
+// GetRandomViewport returns one of the configured viewports, its size and scale together
+func (f *FingerprintMasker) GetRandomViewport() Viewport {
+	return f.viewports[f.rand.Intn(len(f.viewports))]
 }
 
-// ApplyStealthScripts applies stealth scripts to mask automation
-func (f *FingerprintMasker) ApplyStealthScripts(page *rod.Page) error {
-	// Disable navigator.webdriver
-	_, err := page.Eval(`() => {
-		Object.defineProperty(navigator, 'webdriver', {
-			get: () => undefined
-		});
-	}`)
-	if err != nil {
-		return err
+// hardwareFor returns the configured hardware that fits the system a user agent names
+func (f *FingerprintMasker) hardwareFor(userAgent string) []Hardware {
+	system := systemFor(userAgent)
+	var fits []Hardware
+	for _, hardware := range f.hardware {
+		if hardware.Platform == "" || hardware.Platform == system {
+			fits = append(fits, hardware)
+		}
 	}
+	return fits
+}
 
-	// Mask chrome automation properties
-	_, err = page.Eval(`() => {
-		window.navigator.chrome = {
-			runtime: {},
-		};
-	}`)
-	if err != nil {
-		return err
+// randomHardware returns configured hardware that fits the user agent, or none
+func (f *FingerprintMasker) randomHardware(userAgent string) Hardware {
+	fits := f.hardwareFor(userAgent)
+	if len(fits) == 0 {
+		return Hardware{}
+	}
+	return fits[f.rand.Intn(len(fits))]
+}
+
+// canvasSeed returns a new seed for the canvas noise; it fits a JavaScript number exactly
+func (f *FingerprintMasker) canvasSeed() int64 {
+	return f.rand.Int63n(1<<31-1) + 1
+}
+
+// NewFingerprint picks a user agent, viewport, hardware and canvas seed and derives the
+// rest: the platform from the user agent, the languages from locale, and timezone as given
+func (f *FingerprintMasker) NewFingerprint(timezone, locale string) Fingerprint {
+	userAgent := f.GetRandomUserAgent()
+	return Fingerprint{
+		UserAgent:  userAgent,
+		Viewport:   f.GetRandomViewport(),
+		Timezone:   timezone,
+		Languages:  languagesFor(locale),
+		Platform:   platformFor(userAgent),
+		Hardware:   f.randomHardware(userAgent),
+		CanvasSeed: f.canvasSeed(),
+	}
+}
+
+// Complete returns a stored fingerprint with the parts it lacks filled in, and a new user
+// agent, viewport or hardware in place of one that is no longer configured. It reports
+// whether anything changed.
+func (f *FingerprintMasker) Complete(fp Fingerprint, timezone, locale string) (Fingerprint, bool) {
+	changed := false
+	if len(f.userAgents) > 0 && !slices.Contains(f.userAgents, fp.UserAgent) {
+		fp.UserAgent = f.GetRandomUserAgent()
+		fp.Platform = platformFor(fp.UserAgent)
+		changed = true
+	}
+	if !slices.Contains(f.viewports, fp.Viewport) {
+		fp.Viewport = f.GetRandomViewport()
+		changed = true
+	}
+	if fp.Timezone == "" && timezone != "" {
+		fp.Timezone = timezone
+		changed = true
 	}
+	if len(fp.Languages) == 0 {
+		fp.Languages = languagesFor(locale)
+		changed = true
+	}
+	if fp.Platform == "" {
+		fp.Platform = platformFor(fp.UserAgent)
+		changed = fp.Platform != "" || changed
+	}
+	// Hardware has to fit the user agent too, which may just have changed
+	if len(f.hardware) > 0 && !slices.Contains(f.hardwareFor(fp.UserAgent), fp.Hardware) {
+		fp.Hardware = f.randomHardware(fp.UserAgent)
+		changed = true
+	}
+	if fp.CanvasSeed == 0 {
+		fp.CanvasSeed = f.canvasSeed()
+		changed = true
+	}
+	return fp, changed
+}
+
+// languagesFor returns the languages a browser set up for locale sends, falling back to English
+func languagesFor(locale string) []string {
+	if locale == "" {
+		locale = "en-US"
+	}
+	languages := []string{locale}
+	if base, _, found := strings.Cut(locale, "-"); found {
+		languages = append(languages, base)
+	}
+	if !strings.HasPrefix(locale, "en") {
+		languages = append(languages, "en-US", "en")
+	}
+	return languages
+}
+
+// systemFor returns the system a user agent names: windows, mac, linux, or ""
+func systemFor(userAgent string) string {
+	switch {
+	case strings.Contains(userAgent, "Windows"):
+		return "windows"
+	case strings.Contains(userAgent, "Macintosh"):
+		return "mac"
+	case strings.Contains(userAgent, "Linux"):
+		return "linux"
+	default:
+		return ""
+	}
+}
+
+// platformFor returns the navigator.platform of the system a user agent names, or ""
+func platformFor(userAgent string) string {
+	switch systemFor(userAgent) {
+	case "windows":
+		return "Win32"
+	case "mac":
+		return "MacIntel"
+	case "linux":
+		return "Linux x86_64"
+	default:
+		return ""
+	}
+}
+
+// stealthScript masks the signs of automation. It is injected ahead of every document, so
+// the overrides are in place before any page script runs; each one is guarded so a
+// failure leaves the rest working. %s is replaced with the scriptSettings as JSON.
+const stealthScript = `(() => {
+	const settings = %s;
+	const patched = new Set();
+	const guard = (fn) => { try { fn(); } catch (e) {} };
+
+	// Disable navigator.webdriver
+	guard(() => {
+		const get = () => undefined;
+		patched.add(get);
+		Object.defineProperty(navigator, 'webdriver', { get });
+	});
+
+	// Mask chrome automation properties
+	guard(() => {
+		window.navigator.chrome = { runtime: {} };
+	});
 
 	// Override permissions
-	_, err = page.Eval(`() => {
+	guard(() => {
 		const originalQuery = window.navigator.permissions.query;
-		window.navigator.permissions.query = (parameters) => (
+		const query = (parameters) => (
 			parameters.name === 'notifications' ?
 				Promise.resolve({ state: Notification.permission }) :
-				originalQuery(parameters)
+				originalQuery.call(window.navigator.permissions, parameters)
 		);
-	}`)
-	if err != nil {
-		return err
-	}
+		patched.add(query);
+		window.navigator.permissions.query = query;
+	});
 
 	// Mock plugins
-	_, err = page.Eval(`() => {
-		Object.defineProperty(navigator, 'plugins', {
-			get: () => [
-				{
-					0: {type: "application/x-google-chrome-pdf", suffixes: "pdf", description: "Portable Document Format"},
-					description: "Portable Document Format",
-					filename: "internal-pdf-viewer",
-					length: 1,
-					name: "Chrome PDF Plugin"
-				},
-				{
-					0: {type: "application/pdf", suffixes: "pdf", description: ""},
-					description: "",
-					filename: "mhjfbmdgcfjbbpaeojofohoefgiehjai",
-					length: 1,
-					name: "Chrome PDF Viewer"
-				}
-			],
+	guard(() => {
+		const plugins = [
+			{
+				0: {type: "application/x-google-chrome-pdf", suffixes: "pdf", description: "Portable Document Format"},
+				description: "Portable Document Format",
+				filename: "internal-pdf-viewer",
+				length: 1,
+				name: "Chrome PDF Plugin"
+			},
+			{
+				0: {type: "application/pdf", suffixes: "pdf", description: ""},
+				description: "",
+				filename: "mhjfbmdgcfjbbpaeojofohoefgiehjai",
+				length: 1,
+				name: "Chrome PDF Viewer"
+			}
+		];
+		const get = () => plugins;
+		patched.add(get);
+		Object.defineProperty(navigator, 'plugins', { get });
+	});
+
+	// Mock languages to the fingerprint's
+	guard(() => {
+		const languages = Object.freeze(settings.languages);
+		const get = () => languages;
+		patched.add(get);
+		Object.defineProperty(navigator, 'languages', { get });
+	});
+
+	// Report the fingerprint's machine
+	for (const name of ['hardwareConcurrency', 'deviceMemory']) {
+		if (!settings[name]) continue;
+		guard(() => {
+			const get = () => settings[name];
+			patched.add(get);
+			Object.defineProperty(Navigator.prototype, name, { get, configurable: true, enumerable: true });
 		});
-	}`)
-	if err != nil {
-		return err
 	}
 
-	// Mock languages
-	_, err = page.Eval(`() => {
-		Object.defineProperty(navigator, 'languages', {
-			get: () => ['en-US', 'en'],
+	// Report the fingerprint's GPU through the debug renderer info extension
+	if (settings.webglVendor) {
+		const UNMASKED_VENDOR_WEBGL = 0x9245, UNMASKED_RENDERER_WEBGL = 0x9246;
+		for (const context of [window.WebGLRenderingContext, window.WebGL2RenderingContext]) {
+			if (!context) continue;
+			guard(() => {
+				const original = context.prototype.getParameter;
+				const getParameter = ({ getParameter(parameter) {
+					if (parameter === UNMASKED_VENDOR_WEBGL) return settings.webglVendor;
+					if (parameter === UNMASKED_RENDERER_WEBGL) return settings.webglRenderer;
+					return original.call(this, parameter);
+				} }).getParameter;
+				patched.add(getParameter);
+				context.prototype.getParameter = getParameter;
+			});
+		}
+	}
+
+	// Add faint noise to what canvas reads return. It flips the lowest bit of a sparse set of
+	// channels picked by the account's seed, so the same drawing always reads back the same
+	// for this account and differently from other accounts.
+	if (settings.canvasSeed) {
+		guard(() => {
+			const noise = (data) => {
+				let state = settings.canvasSeed >>> 0;
+				for (let i = 0; i < data.length; i += 4) {
+					state ^= state << 13; state >>>= 0;
+					state ^= state >>> 17;
+					state ^= state << 5; state >>>= 0;
+					if ((state & 0x3f) === 0) data[i + ((state >>> 6) %% 3)] ^= 1;
+				}
+			};
+
+			const originalGetImageData = CanvasRenderingContext2D.prototype.getImageData;
+			const getImageData = ({ getImageData(...args) {
+				const image = originalGetImageData.apply(this, args);
+				noise(image.data);
+				return image;
+			} }).getImageData;
+
+			// A noisy copy of a canvas to export; the canvas itself is left alone
+			const noisy = (canvas) => {
+				if (!canvas.width || !canvas.height) return canvas;
+				const copy = document.createElement('canvas');
+				copy.width = canvas.width;
+				copy.height = canvas.height;
+				const context = copy.getContext('2d');
+				context.drawImage(canvas, 0, 0);
+				const image = originalGetImageData.call(context, 0, 0, copy.width, copy.height);
+				noise(image.data);
+				context.putImageData(image, 0, 0);
+				return copy;
+			};
+
+			const originalToDataURL = HTMLCanvasElement.prototype.toDataURL;
+			const toDataURL = ({ toDataURL(...args) {
+				return originalToDataURL.apply(noisy(this), args);
+			} }).toDataURL;
+
+			const originalToBlob = HTMLCanvasElement.prototype.toBlob;
+			const toBlob = ({ toBlob(...args) {
+				return originalToBlob.apply(noisy(this), args);
+			} }).toBlob;
+
+			for (const fn of [getImageData, toDataURL, toBlob]) patched.add(fn);
+			CanvasRenderingContext2D.prototype.getImageData = getImageData;
+			HTMLCanvasElement.prototype.toDataURL = toDataURL;
+			HTMLCanvasElement.prototype.toBlob = toBlob;
 		});
-	}`)
-	if err != nil {
-		return err
 	}
 
-	// Override toString methods to hide modifications
-	_, err = page.Eval(`() => {
+	// Make the overrides read as native code
+	guard(() => {
 		const originalToString = Function.prototype.toString;
-		Function.prototype.toString = function() {
-			if (this === navigator.permissions.query) {
-				return 'function query() { [native code] }';
+		const toString = ({ toString() {
+			if (patched.has(this)) {
+				return 'function ' + (this.name || '') + '() { [native code] }';
 			}
 			return originalToString.call(this);
-		};
-	}`)
+		} }).toString;
+		patched.add(toString);
+		Function.prototype.toString = toString;
+	});
+})();`
+
+// scriptSettings are the parts of the fingerprint the stealth script applies
+type scriptSettings struct {
+	Languages           []string `json:"languages"`
+	HardwareConcurrency int      `json:"hardwareConcurrency,omitempty"`
+	DeviceMemory        float64  `json:"deviceMemory,omitempty"`
+	WebGLVendor         string   `json:"webglVendor,omitempty"`
+	WebGLRenderer       string   `json:"webglRenderer,omitempty"`
+	CanvasSeed          int64    `json:"canvasSeed,omitempty"`
+}
+
+// ApplyStealthScripts registers the stealth script to run before the scripts of every
+// document the page loads from now on
+func (f *FingerprintMasker) ApplyStealthScripts(page *rod.Page) error {
+	fp := f.fingerprint
+	settings := scriptSettings{
+		Languages:           fp.Languages,
+		HardwareConcurrency: fp.Hardware.HardwareConcurrency,
+		DeviceMemory:        fp.Hardware.DeviceMemory,
+		WebGLVendor:         fp.Hardware.WebGLVendor,
+		WebGLRenderer:       fp.Hardware.WebGLRenderer,
+		CanvasSeed:          fp.CanvasSeed,
+	}
+	if len(settings.Languages) == 0 {
+		settings.Languages = []string{"en-US", "en"}
+	}
+	encoded, err := json.Marshal(settings)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to encode stealth settings: %w", err)
 	}
 
+	_, err = proto.PageAddScriptToEvaluateOnNewDocument{Source: fmt.Sprintf(stealthScript, encoded)}.Call(page)
+	if err != nil {
+		return fmt.Errorf("failed to inject stealth script: %w", err)
+	}
 	return nil
 }
 
-// RandomizeViewport randomly changes the viewport size
-func (f *FingerprintMasker) RandomizeViewport(page *rod.Page) error {
-	width, height := f.GetRandomViewport()
+// maskingProbe reads back what the stealth script overrides
+const maskingProbe = `() => {
+	const gl = document.createElement('canvas').getContext('webgl');
+	const info = gl && gl.getExtension('WEBGL_debug_renderer_info');
+	return {
+		webdriver: navigator.webdriver === true,
+		hardwareConcurrency: navigator.hardwareConcurrency || 0,
+		deviceMemory: navigator.deviceMemory || 0,
+		webglVendor: info ? gl.getParameter(info.UNMASKED_VENDOR_WEBGL) : '',
+		webglRenderer: info ? gl.getParameter(info.UNMASKED_RENDERER_WEBGL) : '',
+	};
+}`
+
+// VerifyMasking checks on a page that has navigated since ApplyStealthScripts that the
+// masking took effect, returning an error naming everything that shows through
+func (f *FingerprintMasker) VerifyMasking(page *rod.Page) error {
+	res, err := page.Eval(maskingProbe)
+	if err != nil {
+		return fmt.Errorf("failed to read the masked properties: %w", err)
+	}
+
+	var seen struct {
+		Webdriver           bool    `json:"webdriver"`
+		HardwareConcurrency int     `json:"hardwareConcurrency"`
+		DeviceMemory        float64 `json:"deviceMemory"`
+		WebGLVendor         string  `json:"webglVendor"`
+		WebGLRenderer       string  `json:"webglRenderer"`
+	}
+	if err := res.Value.Unmarshal(&seen); err != nil {
+		return fmt.Errorf("failed to read the masked properties: %w", err)
+	}
+
+	var exposed []string
+	if seen.Webdriver {
+		exposed = append(exposed, "navigator.webdriver is exposed")
+	}
+	hardware := f.fingerprint.Hardware
+	if hardware.HardwareConcurrency > 0 && seen.HardwareConcurrency != hardware.HardwareConcurrency {
+		exposed = append(exposed, fmt.Sprintf("hardwareConcurrency is %d, not %d", seen.HardwareConcurrency, hardware.HardwareConcurrency))
+	}
+	if hardware.DeviceMemory > 0 && seen.DeviceMemory != hardware.DeviceMemory {
+		exposed = append(exposed, fmt.Sprintf("deviceMemory is %g, not %g", seen.DeviceMemory, hardware.DeviceMemory))
+	}
+	// Without WebGL there is no renderer to give away
+	if hardware.WebGLVendor != "" && seen.WebGLVendor != "" && (seen.WebGLVendor != hardware.WebGLVendor || seen.WebGLRenderer != hardware.WebGLRenderer) {
+		exposed = append(exposed, fmt.Sprintf("WebGL reports %s / %s", seen.WebGLVendor, seen.WebGLRenderer))
+	}
+
+	if len(exposed) > 0 {
+		return errors.New(strings.Join(exposed, "; "))
+	}
+	return nil
+}
+
+// Fingerprint returns the fingerprint last applied to the page
+func (f *FingerprintMasker) Fingerprint() Fingerprint {
+	return f.fingerprint
+}
+
+// Apply makes the page present a fingerprint: its user agent with the matching platform
+// and Accept-Language, its timezone and its viewport. ApplyStealthScripts then uses its
+// languages.
+func (f *FingerprintMasker) Apply(page *rod.Page, fp Fingerprint) error {
+	f.fingerprint = fp
+
+	if err := page.SetUserAgent(&proto.NetworkSetUserAgentOverride{
+		UserAgent:      fp.UserAgent,
+		AcceptLanguage: strings.Join(fp.Languages, ","),
+		Platform:       fp.Platform,
+	}); err != nil {
+		return fmt.Errorf("failed to set user agent: %w", err)
+	}
+
+	if fp.Timezone != "" {
+		if err := (proto.EmulationSetTimezoneOverride{TimezoneID: fp.Timezone}).Call(page); err != nil {
+			return fmt.Errorf("failed to set timezone: %w", err)
+		}
+	}
+
+	return f.applyViewport(page, fp.Viewport)
+}
+
+// WindowSize returns the outer size of a browser window showing the viewport
+func (v Viewport) WindowSize() (int, int) {
+	return v.Width, v.Height + windowChromeHeight
+}
+
+// applyViewport sizes the browser window around the viewport, so the window's outer size
+// agrees with the page's inner size, and sets the page's metrics to match
+func (f *FingerprintMasker) applyViewport(page *rod.Page, v Viewport) error {
+	width, height := v.WindowSize()
+	if err := page.SetWindow(&proto.BrowserBounds{
+		Width:       &width,
+		Height:      &height,
+		WindowState: proto.BrowserWindowStateNormal,
+	}); err != nil {
+		return fmt.Errorf("failed to set window bounds: %w", err)
+	}
+
+	// The screen is the smallest that fits the window, as if it were maximized
 	return page.SetViewport(&proto.EmulationSetDeviceMetricsOverride{
-		Width:             width,
-		Height:            height,
-		DeviceScaleFactor: 1,
+		Width:             v.Width,
+		Height:            v.Height,
+		DeviceScaleFactor: v.DeviceScaleFactor,
 		Mobile:            false,
+		ScreenWidth:       &width,
+		ScreenHeight:      &height,
 	})
 }
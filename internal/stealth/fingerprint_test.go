@@ -0,0 +1,87 @@
+package stealth
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+const (
+	windowsUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+	macUA     = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+)
+
+var (
+	nvidia = Hardware{Platform: "windows", WebGLVendor: "Google Inc. (NVIDIA)", WebGLRenderer: "ANGLE (NVIDIA GeForce RTX 3060)", HardwareConcurrency: 12, DeviceMemory: 8}
+	apple  = Hardware{Platform: "mac", WebGLVendor: "Google Inc. (Apple)", WebGLRenderer: "ANGLE (Apple, Apple M1)", HardwareConcurrency: 8, DeviceMemory: 8}
+)
+
+func newTestMasker(seed int64, userAgents ...string) *FingerprintMasker {
+	f := NewFingerprintMasker(userAgents, []Viewport{{Width: 1440, Height: 900, DeviceScaleFactor: 2, Label: "laptop"}}, []Hardware{nvidia, apple})
+	f.rand = rand.New(rand.NewSource(seed))
+	return f
+}
+
+func TestNewFingerprintHardwareFitsUserAgent(t *testing.T) {
+	for seed := int64(0); seed < 50; seed++ {
+		fp := newTestMasker(seed, windowsUA, macUA).NewFingerprint("Europe/Berlin", "en-GB")
+
+		want := map[string]Hardware{windowsUA: nvidia, macUA: apple}[fp.UserAgent]
+		if fp.Hardware != want {
+			t.Fatalf("seed %d: %s presents %+v", seed, fp.UserAgent, fp.Hardware)
+		}
+		if fp.Platform != map[string]string{windowsUA: "Win32", macUA: "MacIntel"}[fp.UserAgent] {
+			t.Fatalf("seed %d: platform %s for %s", seed, fp.Platform, fp.UserAgent)
+		}
+		if fp.CanvasSeed <= 0 || fp.CanvasSeed >= 1<<31 {
+			t.Fatalf("seed %d: canvas seed %d doesn't fit a JavaScript integer", seed, fp.CanvasSeed)
+		}
+	}
+}
+
+func TestCompleteKeepsHardwareInStepWithUserAgent(t *testing.T) {
+	stored := newTestMasker(1, windowsUA).NewFingerprint("", "en-US")
+	if stored.Hardware != nvidia {
+		t.Fatalf("hardware = %+v", stored.Hardware)
+	}
+
+	// Still configured: nothing changes
+	if fp, changed := newTestMasker(2, windowsUA, macUA).Complete(stored, "", "en-US"); changed || fp.Hardware != nvidia || fp.CanvasSeed != stored.CanvasSeed {
+		t.Fatalf("Complete changed a current fingerprint: %+v", fp)
+	}
+
+	// The Windows user agent is gone, so the Windows GPU goes with it
+	fp, changed := newTestMasker(2, macUA).Complete(stored, "", "en-US")
+	if !changed || fp.UserAgent != macUA || fp.Platform != "MacIntel" || fp.Hardware != apple {
+		t.Fatalf("after the user agent was dropped: %+v", fp)
+	}
+	if fp.CanvasSeed != stored.CanvasSeed {
+		t.Fatal("the canvas seed changed with the user agent")
+	}
+
+	// Fingerprints stored before hardware was configured gain it and a canvas seed
+	old := Fingerprint{UserAgent: macUA, Viewport: stored.Viewport, Timezone: "UTC", Languages: []string{"en-US"}, Platform: "MacIntel"}
+	if fp, changed := newTestMasker(3, macUA).Complete(old, "", "en-US"); !changed || fp.Hardware != apple || fp.CanvasSeed == 0 {
+		t.Fatalf("completed old fingerprint = %+v", fp)
+	}
+}
+
+func TestStealthScriptSettings(t *testing.T) {
+	encoded, err := json.Marshal(scriptSettings{Languages: []string{"de-DE", "de"}, HardwareConcurrency: 12, WebGLVendor: `Google Inc. "NVIDIA"`, CanvasSeed: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := fmt.Sprintf(stealthScript, encoded)
+
+	if strings.Contains(script, "%!") || strings.Contains(script, "%%") {
+		t.Fatal("the script has unformatted verbs")
+	}
+	if !strings.Contains(script, `const settings = {"languages":["de-DE","de"],"hardwareConcurrency":12,"webglVendor":"Google Inc. \"NVIDIA\"","canvasSeed":42};`) {
+		t.Fatalf("settings aren't embedded as JSON:\n%s", script[:200])
+	}
+	if !strings.Contains(script, "data[i + ((state >>> 6) % 3)] ^= 1") {
+		t.Fatal("the canvas noise lost its modulo")
+	}
+}
@@ -0,0 +1,278 @@
+package stealth
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Plausibility thresholds for realized behavior
+const (
+	maxPlausibleWPM            = 120.0
+	minPlausibleCurvature      = 1.02
+	maxPlausibleScrollVelocity = 5000.0 // pixels per second
+)
+
+// SessionMetrics records the behavior the stealth components actually produced.
+// A nil *SessionMetrics is valid and records nothing.
+type SessionMetrics struct {
+	mu      sync.Mutex
+	typing  []typingSample
+	mouse   []mouseSample
+	scrolls []scrollSample
+
+	// The run's pause state, as Scheduler reports it
+	pauseState string
+	pauses     int
+	keepAlives int
+	pausedTime time.Duration // in finished pauses
+}
+
+type typingSample struct {
+	chars    int
+	duration time.Duration
+	pauses   int
+	typos    int
+}
+
+type mouseSample struct {
+	pathLength float64
+	distance   float64
+	duration   time.Duration
+}
+
+type scrollSample struct {
+	distance int
+	duration time.Duration
+	pauses   int
+	chunks   int
+}
+
+// MetricsSummary aggregates session metrics for reporting
+type MetricsSummary struct {
+	Typing    TypingSummary `json:"typing"`
+	Mouse     MouseSummary  `json:"mouse"`
+	Scrolling ScrollSummary `json:"scrolling"`
+	Pause     PauseSummary  `json:"pause"`
+	Flags     []string      `json:"flags"`
+}
+
+// TypingSummary describes realized typing speed
+type TypingSummary struct {
+	Fields    int       `json:"fields"`
+	FieldWPM  []float64 `json:"field_wpm"`
+	MeanWPM   float64   `json:"mean_wpm"`
+	MaxWPM    float64   `json:"max_wpm"`
+	PauseRate float64   `json:"pause_rate"` // pauses per character
+	TypoRate  float64   `json:"typo_rate"`  // typos per character
+}
+
+// MouseSummary describes realized mouse movements
+type MouseSummary struct {
+	Moves          int     `json:"moves"`
+	MeanCurvature  float64 `json:"mean_curvature"` // path length / straight distance
+	MeanDurationMs float64 `json:"mean_duration_ms"`
+}
+
+// ScrollSummary describes realized scroll velocities
+type ScrollSummary struct {
+	Scrolls      int     `json:"scrolls"`
+	MeanVelocity float64 `json:"mean_velocity"` // pixels per second
+	P50Velocity  float64 `json:"p50_velocity"`
+	P90Velocity  float64 `json:"p90_velocity"`
+	MaxVelocity  float64 `json:"max_velocity"`
+	PauseRate    float64 `json:"pause_rate"` // pauses per scroll chunk
+}
+
+// PauseSummary describes the run's pause state, as a gauge, and the pauses taken
+type PauseSummary struct {
+	State         string  `json:"state"`  // running, pausing or paused
+	Paused        int     `json:"paused"` // 1 while paused, 0 otherwise
+	Pauses        int     `json:"pauses"`
+	KeepAlives    int     `json:"keep_alives"`
+	PausedSeconds float64 `json:"paused_seconds"` // in finished pauses
+}
+
+// NewSessionMetrics creates an empty metrics recorder
+func NewSessionMetrics() *SessionMetrics {
+	return &SessionMetrics{}
+}
+
+// recordTyping records one TypeText call
+func (m *SessionMetrics) recordTyping(chars int, duration time.Duration, pauses, typos int) {
+	if m == nil || chars == 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.typing = append(m.typing, typingSample{chars: chars, duration: duration, pauses: pauses, typos: typos})
+}
+
+// recordMouse records one mouse movement along a path
+func (m *SessionMetrics) recordMouse(path []Point, start Point, duration time.Duration) {
+	if m == nil || len(path) == 0 {
+		return
+	}
+
+	end := path[len(path)-1]
+	distance := math.Hypot(end.X-start.X, end.Y-start.Y)
+	if distance < 1 {
+		return
+	}
+
+	length := 0.0
+	prev := start
+	for _, p := range path {
+		length += math.Hypot(p.X-prev.X, p.Y-prev.Y)
+		prev = p
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mouse = append(m.mouse, mouseSample{pathLength: length, distance: distance, duration: duration})
+}
+
+// recordScroll records one ScrollDown or ScrollUp call
+func (m *SessionMetrics) recordScroll(distance int, duration time.Duration, pauses, chunks int) {
+	if m == nil || distance <= 0 || duration <= 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.scrolls = append(m.scrolls, scrollSample{distance: distance, duration: duration, pauses: pauses, chunks: chunks})
+}
+
+// recordPause records a change of pause state; paused is how long a pause that just ended lasted
+func (m *SessionMetrics) recordPause(state string, paused time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if state == PausePaused {
+		m.pauses++
+	}
+	m.pauseState = state
+	m.pausedTime += paused
+}
+
+// recordKeepAlive records one keep-alive run while paused
+func (m *SessionMetrics) recordKeepAlive() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.keepAlives++
+}
+
+// Summary aggregates everything recorded so far
+func (m *SessionMetrics) Summary() *MetricsSummary {
+	summary := &MetricsSummary{Pause: PauseSummary{State: PauseRunning}, Flags: []string{}}
+	if m == nil {
+		return summary
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Pausing
+	if m.pauseState != "" {
+		summary.Pause.State = m.pauseState
+	}
+	if m.pauseState == PausePaused {
+		summary.Pause.Paused = 1
+	}
+	summary.Pause.Pauses = m.pauses
+	summary.Pause.KeepAlives = m.keepAlives
+	summary.Pause.PausedSeconds = round1(m.pausedTime.Seconds())
+
+	// Typing
+	totalChars, totalPauses, totalTypos := 0, 0, 0
+	for _, s := range m.typing {
+		minutes := s.duration.Minutes()
+		if minutes <= 0 {
+			continue
+		}
+		wpm := float64(s.chars) / 5 / minutes
+		summary.Typing.FieldWPM = append(summary.Typing.FieldWPM, round1(wpm))
+		summary.Typing.MeanWPM += wpm
+		summary.Typing.MaxWPM = math.Max(summary.Typing.MaxWPM, wpm)
+		totalChars += s.chars
+		totalPauses += s.pauses
+		totalTypos += s.typos
+	}
+	summary.Typing.Fields = len(summary.Typing.FieldWPM)
+	if summary.Typing.Fields > 0 {
+		summary.Typing.MeanWPM = round1(summary.Typing.MeanWPM / float64(summary.Typing.Fields))
+		summary.Typing.MaxWPM = round1(summary.Typing.MaxWPM)
+		summary.Typing.PauseRate = float64(totalPauses) / float64(totalChars)
+		summary.Typing.TypoRate = float64(totalTypos) / float64(totalChars)
+	}
+
+	// Mouse
+	for _, s := range m.mouse {
+		summary.Mouse.MeanCurvature += s.pathLength / s.distance
+		summary.Mouse.MeanDurationMs += float64(s.duration.Milliseconds())
+	}
+	summary.Mouse.Moves = len(m.mouse)
+	if summary.Mouse.Moves > 0 {
+		summary.Mouse.MeanCurvature = math.Round(summary.Mouse.MeanCurvature/float64(summary.Mouse.Moves)*1000) / 1000
+		summary.Mouse.MeanDurationMs = round1(summary.Mouse.MeanDurationMs / float64(summary.Mouse.Moves))
+	}
+
+	// Scrolling
+	var velocities []float64
+	totalChunks, scrollPauses := 0, 0
+	for _, s := range m.scrolls {
+		velocities = append(velocities, float64(s.distance)/s.duration.Seconds())
+		totalChunks += s.chunks
+		scrollPauses += s.pauses
+	}
+	summary.Scrolling.Scrolls = len(velocities)
+	if len(velocities) > 0 {
+		sort.Float64s(velocities)
+		sum := 0.0
+		for _, v := range velocities {
+			sum += v
+		}
+		summary.Scrolling.MeanVelocity = round1(sum / float64(len(velocities)))
+		summary.Scrolling.P50Velocity = round1(percentile(velocities, 0.5))
+		summary.Scrolling.P90Velocity = round1(percentile(velocities, 0.9))
+		summary.Scrolling.MaxVelocity = round1(velocities[len(velocities)-1])
+		if totalChunks > 0 {
+			summary.Scrolling.PauseRate = float64(scrollPauses) / float64(totalChunks)
+		}
+	}
+
+	// Plausibility flags
+	for i, wpm := range summary.Typing.FieldWPM {
+		if wpm > maxPlausibleWPM {
+			summary.Flags = append(summary.Flags, fmt.Sprintf("typing field %d at %.0f WPM exceeds %.0f", i+1, wpm, maxPlausibleWPM))
+		}
+	}
+	if summary.Mouse.Moves > 0 && summary.Mouse.MeanCurvature < minPlausibleCurvature {
+		summary.Flags = append(summary.Flags, fmt.Sprintf("mouse paths nearly straight (curvature %.3f)", summary.Mouse.MeanCurvature))
+	}
+	if summary.Scrolling.MaxVelocity > maxPlausibleScrollVelocity {
+		summary.Flags = append(summary.Flags, fmt.Sprintf("scroll velocity %.0f px/s exceeds %.0f", summary.Scrolling.MaxVelocity, maxPlausibleScrollVelocity))
+	}
+
+	return summary
+}
+
+// percentile returns the p-th percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
+
+// round1 rounds to one decimal place
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -1,10 +1,12 @@
 package stealth
 
 import (
+	"fmt"
 	"math"
 	"math/rand"
 	"time"
 
+	"github.com/Tanukumar01/linkedin-automation/internal/clock"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
 )
@@ -22,6 +24,22 @@ type MouseMover struct {
 	overshootProb       float64
 	microCorrectionProb float64
 	rand                *rand.Rand
+	metrics             *SessionMetrics
+	clock               clock.Clock
+
+	// position is where the cursor was last moved to, valid while hasPosition is set
+	position    Point
+	hasPosition bool
+}
+
+// box is an element's bounding rectangle in viewport coordinates
+type box struct {
+	X, Y, Width, Height float64
+}
+
+// contains reports whether p lies inside the box
+func (b box) contains(p Point) bool {
+	return p.X >= b.X && p.X <= b.X+b.Width && p.Y >= b.Y && p.Y <= b.Y+b.Height
 }
 
 // NewMouseMover creates a new mouse mover
@@ -33,39 +51,54 @@ func NewMouseMover(page *rod.Page, bezierPoints int, speedVariation, overshootPr
 		overshootProb:       overshootProb,
 		microCorrectionProb: microCorrectionProb,
 		rand:                rand.New(rand.NewSource(time.Now().UnixNano())),
+		clock:               clock.Real{},
 	}
 }
 
-// MoveToElement moves the mouse to an element with human-like behavior
-func (m *MouseMover) MoveToElement(element *rod.Element) error {
-	// Get element position and size
-	// Get element position and size using JS since Box() is not available
-	rect := m.page.MustEval(`(el) => {
-		const r = el.getBoundingClientRect();
-		return { x: r.x, y: r.y, width: r.width, height: r.height };
-	}`, element)
+// SetMetrics sets the recorder for realized mouse behavior
+func (m *MouseMover) SetMetrics(metrics *SessionMetrics) {
+	m.metrics = metrics
+}
 
-	boxX := rect.Get("x").Num()
-	boxY := rect.Get("y").Num()
-	boxWidth := rect.Get("width").Num()
-	boxHeight := rect.Get("height").Num()
+// SetClock sets the clock the mover waits on and times its movements with
+func (m *MouseMover) SetClock(c clock.Clock) {
+	m.clock = c
+}
+
+// ResetPosition forgets the tracked cursor position; call it after navigating,
+// so the next movement starts from a deliberate move on the new page
+func (m *MouseMover) ResetPosition() {
+	m.hasPosition = false
+}
 
-	// Calculate target position (random point within element)
-	targetX := boxX + m.rand.Float64()*boxWidth
-	targetY := boxY + m.rand.Float64()*boxHeight
+// Position returns the tracked cursor position and whether it is known
+func (m *MouseMover) Position() (Point, bool) {
+	return m.position, m.hasPosition
+}
 
-	// Get current mouse position
-	currentPos, err := m.getCurrentPosition()
+// MoveTo moves the cursor straight to p and tracks it there
+func (m *MouseMover) MoveTo(p Point) error {
+	if err := m.page.Mouse.MoveTo(proto.NewPoint(p.X, p.Y)); err != nil {
+		return err
+	}
+	m.position, m.hasPosition = p, true
+	return nil
+}
+
+// MoveToElement moves the mouse to an element with human-like behavior
+func (m *MouseMover) MoveToElement(element *rod.Element) error {
+	// Measure right before moving, so a scroll since the element was found is accounted for
+	b, err := m.elementBox(element)
 	if err != nil {
-		// If we can't get current position, start from a random point
-		currentPos = Point{
-			X: m.rand.Float64() * 1000,
-			Y: m.rand.Float64() * 600,
-		}
+		return err
+	}
+
+	start, err := m.currentPosition()
+	if err != nil {
+		return err
 	}
 
-	// Move to target with Bézier curve
-	return m.moveToPoint(currentPos, Point{X: targetX, Y: targetY})
+	return m.moveToPoint(start, m.pointIn(b))
 }
 
 // MoveToPoint moves the mouse to a specific point
@@ -79,6 +112,11 @@ func (m *MouseMover) moveToPoint(start, end Point) error {
 		path = append(path, overshoot...)
 	}
 
+	started := m.clock.Now()
+	defer func() {
+		m.metrics.recordMouse(path, start, m.clock.Now().Sub(started))
+	}()
+
 	// Move along the path
 	for i, point := range path {
 		// Calculate delay with speed variation
@@ -89,6 +127,8 @@ func (m *MouseMover) moveToPoint(start, end Point) error {
 		// Move mouse
 		err := m.page.Mouse.MoveAlong(singlePoint(proto.NewPoint(point.X, point.Y)))
 		if err != nil {
+			// Where the cursor stopped is unknown, so start over next time
+			m.hasPosition = false
 			return err
 		}
 
@@ -99,12 +139,14 @@ func (m *MouseMover) moveToPoint(start, end Point) error {
 				Y: point.Y + (m.rand.Float64()*4 - 2),
 			}
 			m.page.Mouse.MoveAlong(singlePoint(proto.NewPoint(correction.X, correction.Y)))
-			time.Sleep(delay / 2)
+			m.clock.Sleep(delay / 2)
 		}
 
-		time.Sleep(delay)
+		m.clock.Sleep(delay)
 	}
 
+	// Every path, overshoot included, ends on the target
+	m.position, m.hasPosition = end, true
 	return nil
 }
 
@@ -193,11 +235,63 @@ func (m *MouseMover) generateOvershoot(target Point) []Point {
 	return []Point{overshoot, target}
 }
 
-// getCurrentPosition gets the current mouse position
-func (m *MouseMover) getCurrentPosition() (Point, error) {
-	// Rod doesn't provide a way to get current mouse position
-	// We'll track it ourselves or estimate
-	return Point{X: 0, Y: 0}, nil
+// currentPosition returns the tracked cursor position. When it is unknown, after
+// a navigation or on the first movement, the cursor is first placed at a resting
+// point near the middle of the viewport.
+func (m *MouseMover) currentPosition() (Point, error) {
+	if m.hasPosition {
+		return m.position, nil
+	}
+
+	width, height, err := m.viewportSize()
+	if err != nil {
+		return Point{}, err
+	}
+
+	rest := Point{
+		X: width * (0.3 + m.rand.Float64()*0.4),
+		Y: height * (0.3 + m.rand.Float64()*0.4),
+	}
+	if err := m.MoveTo(rest); err != nil {
+		return Point{}, err
+	}
+	return rest, nil
+}
+
+// pointIn picks a target inside b, away from its edges
+func (m *MouseMover) pointIn(b box) Point {
+	return Point{
+		X: b.X + b.Width*(0.2+m.rand.Float64()*0.6),
+		Y: b.Y + b.Height*(0.2+m.rand.Float64()*0.6),
+	}
+}
+
+// elementBox returns the element's current bounding box in viewport coordinates
+func (m *MouseMover) elementBox(element *rod.Element) (box, error) {
+	rect, err := element.Eval(`() => {
+		const r = this.getBoundingClientRect();
+		return { x: r.x, y: r.y, width: r.width, height: r.height };
+	}`)
+	if err != nil {
+		return box{}, fmt.Errorf("failed to measure element: %w", err)
+	}
+
+	v := rect.Value
+	return box{
+		X:      v.Get("x").Num(),
+		Y:      v.Get("y").Num(),
+		Width:  v.Get("width").Num(),
+		Height: v.Get("height").Num(),
+	}, nil
+}
+
+// viewportSize returns the inner size of the browser window
+func (m *MouseMover) viewportSize() (float64, float64, error) {
+	viewport, err := m.page.Eval(`() => ({ width: window.innerWidth, height: window.innerHeight })`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read viewport size: %w", err)
+	}
+	return viewport.Value.Get("width").Num(), viewport.Value.Get("height").Num(), nil
 }
 
 // HoverElement hovers over an element
@@ -208,7 +302,7 @@ func (m *MouseMover) HoverElement(element *rod.Element) error {
 
 	// Stay hovered for a random duration
 	hoverDuration := time.Duration(500+m.rand.Intn(1500)) * time.Millisecond
-	time.Sleep(hoverDuration)
+	m.clock.Sleep(hoverDuration)
 
 	return nil
 }
@@ -221,7 +315,19 @@ func (m *MouseMover) ClickElement(element *rod.Element) error {
 	}
 
 	// Small pause before clicking
-	time.Sleep(time.Duration(100+m.rand.Intn(300)) * time.Millisecond)
+	m.clock.Sleep(time.Duration(100+m.rand.Intn(300)) * time.Millisecond)
+
+	// The page may have shifted under the cursor (a scroll settling, content
+	// loading above); correct onto the element's new position before clicking
+	b, err := m.elementBox(element)
+	if err != nil {
+		return err
+	}
+	if !b.contains(m.position) {
+		if err := m.moveToPoint(m.position, m.pointIn(b)); err != nil {
+			return err
+		}
+	}
 
 	// Click
 	if err := m.page.Mouse.Click(proto.InputMouseButtonLeft, 1); err != nil {
@@ -229,7 +335,7 @@ func (m *MouseMover) ClickElement(element *rod.Element) error {
 	}
 
 	// Small pause after clicking
-	time.Sleep(time.Duration(100+m.rand.Intn(200)) * time.Millisecond)
+	m.clock.Sleep(time.Duration(100+m.rand.Intn(200)) * time.Millisecond)
 
 	return nil
 }
@@ -248,21 +354,21 @@ func singlePoint(p proto.Point) func() (proto.Point, bool) {
 
 // RandomIdleMovement performs random idle mouse movements
 func (m *MouseMover) RandomIdleMovement() error {
-	// Get viewport size
-	viewport := m.page.MustEval(`() => ({ width: window.innerWidth, height: window.innerHeight })`).Map()
-	width := viewport["width"].Num()
-	height := viewport["height"].Num()
+	width, height, err := m.viewportSize()
+	if err != nil {
+		return err
+	}
 
-	// Generate random target within viewport
-	target := Point{
-		X: m.rand.Float64() * width,
-		Y: m.rand.Float64() * height,
+	start, err := m.currentPosition()
+	if err != nil {
+		return err
 	}
 
-	currentPos := Point{
+	// Generate random target within viewport
+	target := Point{
 		X: m.rand.Float64() * width,
 		Y: m.rand.Float64() * height,
 	}
 
-	return m.moveToPoint(currentPos, target)
+	return m.moveToPoint(start, target)
 }
@@ -0,0 +1,91 @@
+package stealth
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func TestSinglePointFeedsMoveAlongOnce(t *testing.T) {
+	next := singlePoint(proto.NewPoint(12, 34))
+
+	// Mouse.MoveAlong pulls points until the generator reports none left
+	var got []proto.Point
+	for p, ok := next(); ok; p, ok = next() {
+		got = append(got, p)
+		if len(got) > 1 {
+			break
+		}
+	}
+	if len(got) != 1 || got[0].X != 12 || got[0].Y != 34 {
+		t.Fatalf("generator yielded %v, want only (12, 34)", got)
+	}
+}
+
+func TestBezierPathEndsOnTarget(t *testing.T) {
+	m := NewMouseMover(nil, 4, 0.3, 0, 0)
+	m.rand = rand.New(rand.NewSource(1))
+	start, end := Point{X: 100, Y: 100}, Point{X: 700, Y: 400}
+
+	for i := 0; i < 50; i++ {
+		path := m.generateBezierPath(start, end)
+		if len(path) < 20 || len(path) >= 50 {
+			t.Fatalf("path has %d points, want 20-49", len(path))
+		}
+		if path[0] != start || math.Hypot(path[len(path)-1].X-end.X, path[len(path)-1].Y-end.Y) > 1e-9 {
+			t.Fatalf("path runs %v to %v, want %v to %v", path[0], path[len(path)-1], start, end)
+		}
+	}
+}
+
+func TestOvershootReturnsToTarget(t *testing.T) {
+	m := NewMouseMover(nil, 4, 0.3, 1, 0)
+	m.rand = rand.New(rand.NewSource(1))
+	target := Point{X: 300, Y: 200}
+
+	for i := 0; i < 50; i++ {
+		path := m.generateOvershoot(target)
+		if len(path) != 2 || path[1] != target {
+			t.Fatalf("overshoot = %v, want it to end on %v", path, target)
+		}
+		if d := math.Hypot(path[0].X-target.X, path[0].Y-target.Y); d < 10 || d > 40 {
+			t.Fatalf("overshot by %.1fpx, want 10-40", d)
+		}
+	}
+}
+
+func TestPointInStaysOffTheEdges(t *testing.T) {
+	m := NewMouseMover(nil, 4, 0.3, 0, 0)
+	m.rand = rand.New(rand.NewSource(1))
+	b := box{X: 40, Y: 300, Width: 120, Height: 32}
+	inner := box{X: b.X + b.Width*0.2, Y: b.Y + b.Height*0.2, Width: b.Width * 0.6, Height: b.Height * 0.6}
+
+	for i := 0; i < 200; i++ {
+		if p := m.pointIn(b); !inner.contains(p) {
+			t.Fatalf("target %v is within 20%% of the edge of %+v", p, b)
+		}
+	}
+	if b.contains(Point{X: 39, Y: 310}) || b.contains(Point{X: 100, Y: 333}) {
+		t.Fatal("a point outside the box counts as inside")
+	}
+}
+
+func TestResetPositionForgetsTheCursor(t *testing.T) {
+	m := NewMouseMover(nil, 4, 0.3, 0, 0)
+	if _, ok := m.Position(); ok {
+		t.Fatal("a new mover knows where the cursor is")
+	}
+
+	m.position, m.hasPosition = Point{X: 10, Y: 20}, true
+	if p, ok := m.Position(); !ok || p != (Point{X: 10, Y: 20}) {
+		t.Fatalf("Position = %v, %v", p, ok)
+	}
+
+	// After a navigation the next movement starts from a fresh resting point
+	m.ResetPosition()
+	if _, ok := m.Position(); ok {
+		t.Fatal("the position survived a reset")
+	}
+}
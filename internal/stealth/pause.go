@@ -0,0 +1,151 @@
+package stealth
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+)
+
+// States of a run that can be paused from outside
+const (
+	PauseRunning = "running"
+	PausePausing = "pausing" // a pause was requested; the profile in hand is finished first
+	PausePaused  = "paused"
+)
+
+// Activity log actions for pausing; details of resumed hold how long the pause lasted
+const (
+	ActivityPaused  = "paused"
+	ActivityResumed = "resumed"
+)
+
+// pauseControl is a pause requested from outside; the zero value is running
+type pauseControl struct {
+	mu      sync.Mutex
+	state   string
+	since   time.Time     // when the state was entered
+	resumed chan struct{} // closed by Resume; nil while running
+	total   time.Duration // spent paused, not counting a pause under way
+
+	hold           func() (resume func()) // stops the session's clocks while paused
+	keepAlive      func()                 // keeps the session alive while paused
+	keepAliveEvery time.Duration
+}
+
+// Pause asks the run to pause at its next checkpoint, once the profile in hand is done.
+// It is safe to call from another goroutine.
+func (s *Scheduler) Pause() {
+	p := &s.pausing
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.resumed != nil {
+		return
+	}
+
+	p.state, p.since = PausePausing, s.clock.Now()
+	p.resumed = make(chan struct{})
+	s.metrics.recordPause(PausePausing, 0)
+	logger.Infof("Pause requested, finishing the profile in hand")
+}
+
+// Resume lets a paused run, or one about to pause, carry on. It is safe to call from
+// another goroutine.
+func (s *Scheduler) Resume() {
+	p := &s.pausing
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.resumed == nil {
+		return
+	}
+
+	now := s.clock.Now()
+	if p.state == PausePaused {
+		paused := now.Sub(p.since)
+		p.total += paused
+		logger.Infof("Resumed after %s paused", paused.Round(time.Second))
+		s.recordWait(ActivityResumed, paused)
+		s.metrics.recordPause(PauseRunning, paused)
+	} else {
+		s.metrics.recordPause(PauseRunning, 0)
+		logger.Infof("Pause cancelled before it took effect")
+	}
+	close(p.resumed)
+	p.resumed = nil
+	p.state, p.since = PauseRunning, now
+}
+
+// PauseState returns whether the run is running, pausing or paused, since when, and how
+// long it has been paused in total, a pause under way included
+func (s *Scheduler) PauseState() (string, time.Time, time.Duration) {
+	p := &s.pausing
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	total := p.total
+	if p.state == PausePaused {
+		total += s.clock.Now().Sub(p.since)
+	}
+	if p.state == "" {
+		return PauseRunning, p.since, total
+	}
+	return p.state, p.since, total
+}
+
+// SetPauseHooks sets what pausing does to the current session: hold stops its clocks until
+// the returned resume is called, and keepAlive runs every so often while paused. Either may
+// be nil. EndSession clears them.
+func (s *Scheduler) SetPauseHooks(hold func() (resume func()), keepAlive func(), every time.Duration) {
+	p := &s.pausing
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.hold, p.keepAlive, p.keepAliveEvery = hold, keepAlive, every
+}
+
+// SetMetrics sets the recorder the run's pause state is reported to
+func (s *Scheduler) SetMetrics(m *SessionMetrics) {
+	s.metrics = m
+}
+
+// waitWhilePaused waits at a checkpoint for a requested pause to be resumed or the run to
+// be stopped. Paused time isn't active time.
+func (s *Scheduler) waitWhilePaused() {
+	p := &s.pausing
+	p.mu.Lock()
+	resumed := p.resumed
+	if resumed == nil {
+		p.mu.Unlock()
+		return
+	}
+	p.state, p.since = PausePaused, s.clock.Now()
+	hold, keepAlive, every := p.hold, p.keepAlive, p.keepAliveEvery
+	s.metrics.recordPause(PausePaused, 0)
+	p.mu.Unlock()
+
+	logger.Infof("Paused; the session stays open until resumed")
+	s.recordWait(ActivityPaused, 0)
+
+	s.pause()
+	defer s.resume()
+	if hold != nil {
+		defer hold()()
+	}
+
+	wait := waitStep
+	if keepAlive != nil && every > 0 {
+		wait = every
+	}
+	for {
+		select {
+		case <-resumed:
+			return
+		case <-s.stop:
+			return
+		case <-s.clock.After(wait):
+			if keepAlive != nil && every > 0 {
+				keepAlive()
+				s.metrics.recordKeepAlive()
+			}
+		}
+	}
+}
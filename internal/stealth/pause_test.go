@@ -0,0 +1,156 @@
+package stealth
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// activityRecorder records the scheduler's activity log actions
+type activityRecorder struct {
+	mu      sync.Mutex
+	actions []string
+}
+
+func (r *activityRecorder) LogActivity(action, details string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.actions = append(r.actions, action)
+	return nil
+}
+
+func (r *activityRecorder) recorded() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.actions...)
+}
+
+func TestPauseWaitsUntilResumed(t *testing.T) {
+	s, err := NewScheduler(9, 17, "America/New_York", false, 5, 10, 0)
+	if err != nil {
+		t.Fatalf("NewScheduler: %v", err)
+	}
+	metrics := NewSessionMetrics()
+	s.SetMetrics(metrics)
+	activities := &activityRecorder{}
+	s.SetActivityLog(activities)
+
+	s.Pause()
+	if state, _, _ := s.PauseState(); state != PausePausing {
+		t.Fatalf("state = %s, want %s", state, PausePausing)
+	}
+	if gauge := metrics.Summary().Pause; gauge.State != PausePausing || gauge.Paused != 0 {
+		t.Fatalf("gauge = %+v, want pausing", gauge)
+	}
+
+	// The next checkpoint waits out the pause
+	done := make(chan error)
+	go func() { done <- s.CheckSession() }()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if state, _, _ := s.PauseState(); state == PausePaused {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the checkpoint never paused")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("CheckSession returned %v while paused", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if gauge := metrics.Summary().Pause; gauge.State != PausePaused || gauge.Paused != 1 || gauge.Pauses != 1 {
+		t.Fatalf("gauge = %+v, want one pause under way", gauge)
+	}
+
+	s.Resume()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("CheckSession = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the checkpoint didn't resume")
+	}
+	if state, _, _ := s.PauseState(); state != PauseRunning {
+		t.Fatalf("state = %s, want %s", state, PauseRunning)
+	}
+	if gauge := metrics.Summary().Pause; gauge.State != PauseRunning || gauge.Paused != 0 || gauge.Pauses != 1 {
+		t.Fatalf("gauge = %+v, want running after one pause", gauge)
+	}
+	if got := activities.recorded(); len(got) != 2 || got[0] != ActivityPaused || got[1] != ActivityResumed {
+		t.Fatalf("activity = %v, want paused then resumed", got)
+	}
+}
+
+func TestPauseCancelledBeforeCheckpoint(t *testing.T) {
+	s, _ := newTestScheduler(t, time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC))
+	metrics := NewSessionMetrics()
+	s.SetMetrics(metrics)
+
+	s.Pause()
+	s.Resume()
+	if err := s.CheckSession(); err != nil {
+		t.Fatalf("CheckSession = %v, want nil", err)
+	}
+	if gauge := metrics.Summary().Pause; gauge.State != PauseRunning || gauge.Pauses != 0 {
+		t.Fatalf("gauge = %+v, want running and never paused", gauge)
+	}
+}
+
+func TestPauseKeepsSessionAlive(t *testing.T) {
+	s, fake := newTestScheduler(t, time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC))
+	metrics := NewSessionMetrics()
+	s.SetMetrics(metrics)
+
+	// A long pause: the keep-alive runs every ten minutes until the third resumes the run
+	const every = 10 * time.Minute
+	keepAlives, held, released := 0, 0, 0
+	hold := func() func() {
+		held++
+		return func() { released++ }
+	}
+	s.SetPauseHooks(hold, func() {
+		keepAlives++
+		if keepAlives == 3 {
+			s.Resume()
+		}
+	}, every)
+
+	s.Pause()
+	if err := s.CheckSession(); err != nil {
+		t.Fatalf("CheckSession = %v, want nil", err)
+	}
+
+	// Waking for the keep-alive and seeing the resume at once may run one more
+	if keepAlives < 3 {
+		t.Fatalf("keep-alive ran %d times, want at least 3", keepAlives)
+	}
+	if held != 1 || released != 1 {
+		t.Fatalf("held %d and released %d times, want once each", held, released)
+	}
+	for _, d := range fake.Slept() {
+		if d != every {
+			t.Fatalf("waited %v while paused, want steps of %v", fake.Slept(), every)
+		}
+	}
+	if _, _, total := s.PauseState(); total != 3*every {
+		t.Fatalf("total paused = %v, want %v", total, 3*every)
+	}
+	gauge := metrics.Summary().Pause
+	if gauge.State != PauseRunning || gauge.Pauses != 1 || gauge.KeepAlives != keepAlives || gauge.PausedSeconds != (3*every).Seconds() {
+		t.Fatalf("gauge = %+v, want one %v pause with %d keep-alives", gauge, 3*every, keepAlives)
+	}
+}
+
+func TestPauseStopsWaitingOnStop(t *testing.T) {
+	s, _ := newTestScheduler(t, time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC))
+	s.SetPauseHooks(nil, func() { s.Stop() }, time.Minute)
+
+	s.Pause()
+	if err := s.CheckSession(); err != ErrStopped {
+		t.Fatalf("CheckSession = %v, want %v", err, ErrStopped)
+	}
+}
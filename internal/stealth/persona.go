@@ -0,0 +1,81 @@
+package stealth
+
+import (
+	"math/rand"
+	"strings"
+)
+
+// Persona is the pace a session works at, sampled once at its start so runs don't all
+// share one statistical signature. Two traits drive it: tempo, how quickly the person
+// moves through pages, and care, how much they trade speed for accuracy. The factors
+// scale the configured timing, typing and scrolling and follow from the traits, so a
+// quick reader also scrolls quickly and a careful typist types slower and slips less.
+type Persona struct {
+	Name  string  `json:"name"`
+	Tempo float64 `json:"tempo"` // -1 quickest to 1 slowest
+	Care  float64 `json:"care"`  // -1 sloppiest to 1 most careful
+
+	Delays float64 `json:"delays"` // action delays, think times, pauses and reading time
+	Typing float64 `json:"typing"` // words per minute
+	Typos  float64 `json:"typos"`  // typo probability
+	Scroll float64 `json:"scroll"` // the wait between scroll steps
+}
+
+// NeutralPersona leaves the configured pace as it is
+var NeutralPersona = Persona{Name: "neutral", Delays: 1, Typing: 1, Typos: 1, Scroll: 1}
+
+// personaJitter is how much of a factor's spread comes from neither trait
+const personaJitter = 0.15
+
+// PersonaSampler draws personas whose factors stay within 1±spread
+type PersonaSampler struct {
+	spread float64
+	rand   *rand.Rand
+}
+
+// NewPersonaSampler creates a sampler for factors within 1±spread, e.g. 0.3 for 0.7 to
+// 1.3; the same seed draws the same personas
+func NewPersonaSampler(spread float64, seed int64) *PersonaSampler {
+	return &PersonaSampler{spread: min(max(spread, 0), 0.9), rand: rand.New(rand.NewSource(seed))}
+}
+
+// Sample draws a persona
+func (s *PersonaSampler) Sample() Persona {
+	tempo := s.rand.Float64()*2 - 1
+	care := s.rand.Float64()*2 - 1
+
+	// Each factor's trait weights and the jitter add up to 1, keeping it within 1±spread
+	p := Persona{Tempo: tempo, Care: care}
+	p.Delays = s.factor(0.7*tempo + 0.15*care)
+	p.Typing = s.factor(-0.6*tempo - 0.25*care)
+	p.Typos = s.factor(-0.65*care - 0.2*tempo)
+	p.Scroll = s.factor(0.75*tempo + 0.1*care)
+	p.Name = personaName(tempo, care)
+	return p
+}
+
+// factor turns a trait mix in [-1, 1] into a factor within 1±spread
+func (s *PersonaSampler) factor(mix float64) float64 {
+	mix += (s.rand.Float64()*2 - 1) * personaJitter
+	return 1 + min(max(mix, -1), 1)*s.spread
+}
+
+// personaName describes the traits, e.g. "fast reader, sloppy typist"
+func personaName(tempo, care float64) string {
+	var parts []string
+	switch {
+	case tempo < -1.0/3:
+		parts = append(parts, "fast reader")
+	case tempo > 1.0/3:
+		parts = append(parts, "slow reader")
+	default:
+		parts = append(parts, "steady reader")
+	}
+	switch {
+	case care < -1.0/3:
+		parts = append(parts, "sloppy typist")
+	case care > 1.0/3:
+		parts = append(parts, "careful typist")
+	}
+	return strings.Join(parts, ", ")
+}
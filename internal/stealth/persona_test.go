@@ -0,0 +1,107 @@
+package stealth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPersonaSamplerStaysWithinSpread(t *testing.T) {
+	for _, spread := range []float64{0, 0.25, 0.9} {
+		sampler := NewPersonaSampler(spread, 1)
+		for i := 0; i < 500; i++ {
+			p := sampler.Sample()
+			if p.Tempo < -1 || p.Tempo > 1 || p.Care < -1 || p.Care > 1 {
+				t.Fatalf("spread %v: traits out of range: %+v", spread, p)
+			}
+			for name, factor := range map[string]float64{"delays": p.Delays, "typing": p.Typing, "typos": p.Typos, "scroll": p.Scroll} {
+				if factor < 1-spread-1e-9 || factor > 1+spread+1e-9 {
+					t.Fatalf("spread %v: %s factor %v outside 1±%v", spread, name, factor, spread)
+				}
+			}
+		}
+	}
+
+	// A spread beyond what the config allows is capped
+	p := NewPersonaSampler(5, 1).Sample()
+	if p.Delays < 0.1 || p.Delays > 1.9 {
+		t.Fatalf("delays factor %v with an oversized spread", p.Delays)
+	}
+}
+
+func TestPersonaSamplerIsSeeded(t *testing.T) {
+	a, b := NewPersonaSampler(0.25, 42), NewPersonaSampler(0.25, 42)
+	for i := 0; i < 10; i++ {
+		if pa, pb := a.Sample(), b.Sample(); pa != pb {
+			t.Fatalf("draw %d: %+v and %+v from the same seed", i, pa, pb)
+		}
+	}
+	if NewPersonaSampler(0.25, 42).Sample() == NewPersonaSampler(0.25, 43).Sample() {
+		t.Fatal("different seeds drew the same persona")
+	}
+}
+
+func TestPersonaFactorsFollowTheTraits(t *testing.T) {
+	sampler := NewPersonaSampler(0.3, 7)
+	for i := 0; i < 500; i++ {
+		p := sampler.Sample()
+
+		// The jitter can't outweigh a strong trait
+		if p.Tempo < -0.7 && (p.Delays >= 1 || p.Scroll >= 1 || p.Typing <= 1) {
+			t.Fatalf("a quick persona isn't quicker: %+v", p)
+		}
+		if p.Tempo > 0.7 && (p.Delays <= 1 || p.Scroll <= 1 || p.Typing >= 1) {
+			t.Fatalf("a slow persona isn't slower: %+v", p)
+		}
+		if p.Care > 0.7 && p.Typos >= 1 {
+			t.Fatalf("a careful persona slips more: %+v", p)
+		}
+		if p.Care < -0.7 && p.Typos <= 1 {
+			t.Fatalf("a sloppy persona slips less: %+v", p)
+		}
+	}
+}
+
+func TestPersonaName(t *testing.T) {
+	for _, tt := range []struct {
+		tempo, care float64
+		want        string
+	}{
+		{-0.8, 0, "fast reader"},
+		{0.8, 0, "slow reader"},
+		{0, 0, "steady reader"},
+		{-0.5, -0.9, "fast reader, sloppy typist"},
+		{0.2, 0.6, "steady reader, careful typist"},
+	} {
+		if got := personaName(tt.tempo, tt.care); got != tt.want {
+			t.Errorf("personaName(%v, %v) = %q, want %q", tt.tempo, tt.care, got, tt.want)
+		}
+	}
+}
+
+func TestTimingPacedByPersona(t *testing.T) {
+	timing := NewTimingController(2, 2, 4, 4, 200)
+	if got := timing.ActionDelay(); got != 2*time.Second {
+		t.Fatalf("unpaced action delay %s, want 2s", got)
+	}
+
+	timing.SetPace(1.25)
+	if got := timing.ActionDelay(); got != 2500*time.Millisecond {
+		t.Fatalf("paced action delay %s, want 2.5s", got)
+	}
+	if got := timing.ThinkTime(); got != 5*time.Second {
+		t.Fatalf("paced think time %s, want 5s", got)
+	}
+
+	// The pace and fatigue stack
+	f := NewFatigue(0.2, time.Hour)
+	f.AddActive(time.Hour)
+	timing.SetFatigue(f)
+	if got := timing.ActionDelay(); got != 3*time.Second {
+		t.Fatalf("paced, tired action delay %s, want 3s", got)
+	}
+
+	timing.SetPace(NeutralPersona.Delays)
+	if got := timing.ActionDelay(); got != 2400*time.Millisecond {
+		t.Fatalf("neutral, tired action delay %s, want 2.4s", got)
+	}
+}
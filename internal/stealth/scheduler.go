@@ -1,8 +1,16 @@
 package stealth
 
 import (
+	"errors"
+	"fmt"
 	"math/rand"
+	"sort"
+	"sync"
 	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/clock"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/notify"
 )
 
 // Scheduler handles activity scheduling
@@ -14,9 +22,58 @@ type Scheduler struct {
 	breakDurationMin   int
 	breakDurationMax   int
 	breakProbability   float64
+	dayOverrides       map[time.Weekday]bool
 	rand               *rand.Rand
+	notifier           notify.Notifier
+	clock              clock.Clock
+
+	// Active time accounting; time spent in the scheduler's own waits is idle
+	store          ActiveTimeStore
+	maxSession     time.Duration
+	maxDailyActive time.Duration
+	running        bool
+	activeSince    time.Time // zero while waiting
+	sessionActive  time.Duration
+
+	activities ActivityLog // records waits so gaps in the day can be explained
+	fatigue    *Fatigue    // tires with active time and rests on breaks
+
+	stop     chan struct{} // closed by Stop
+	stopOnce sync.Once
+
+	pausing pauseControl    // a pause requested from outside, e.g. with ctl pause
+	metrics *SessionMetrics // reports the pause state; nil records nothing
+}
+
+// ActivityLog records scheduler waits alongside the rest of the run's activity
+type ActivityLog interface {
+	LogActivity(action, details string) error
+}
+
+// Activity log actions for the scheduler's waits; details hold the wait duration
+const (
+	ActivityBreak             = "break"
+	ActivityBusinessHoursWait = "wait_business_hours"
+	ActivitySlotWait          = "wait_slot"
+	ActivitySessionWait       = "wait_session" // the daemon between sessions and overnight
+	ActivityCooldown          = "cooldown"     // logged by the senders between requests and messages
+)
+
+// ActiveTimeStore persists active time per day so restarts don't reset the daily cap
+type ActiveTimeStore interface {
+	AddActiveTime(date string, d time.Duration) error
+	GetActiveTime(date string) (time.Duration, error)
 }
 
+// ErrSessionEnded is returned once the current session has used max_session_minutes of active time
+var ErrSessionEnded = errors.New("session time cap reached")
+
+// ErrDailyActiveCapReached is returned once today's max_daily_active_minutes have been used
+var ErrDailyActiveCapReached = errors.New("daily active time cap reached")
+
+// ErrStopped is returned once Stop has been called, e.g. on SIGTERM
+var ErrStopped = errors.New("shutdown requested")
+
 // NewScheduler creates a new scheduler
 func NewScheduler(businessHoursStart, businessHoursEnd int, timezone string, weekendActivity bool, breakDurationMin, breakDurationMax int, breakProbability float64) (*Scheduler, error) {
 	loc, err := time.LoadLocation(timezone)
@@ -33,16 +90,263 @@ func NewScheduler(businessHoursStart, businessHoursEnd int, timezone string, wee
 		breakDurationMax:   breakDurationMax,
 		breakProbability:   breakProbability,
 		rand:               rand.New(rand.NewSource(time.Now().UnixNano())),
+		notifier:           notify.Nop{},
+		clock:              clock.Real{},
+		stop:               make(chan struct{}),
 	}, nil
 }
 
+// SetNotifier sets the notifier used to report scheduling pauses
+func (s *Scheduler) SetNotifier(n notify.Notifier) {
+	s.notifier = n
+}
+
+// SetClock sets the clock the scheduler reads the time from and waits on
+func (s *Scheduler) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// SetActivityLog sets where the scheduler's waits are recorded
+func (s *Scheduler) SetActivityLog(log ActivityLog) {
+	s.activities = log
+}
+
+// recordWait logs a wait about to start
+func (s *Scheduler) recordWait(action string, d time.Duration) {
+	if s.activities == nil {
+		return
+	}
+	if err := s.activities.LogActivity(action, d.Round(time.Second).String()); err != nil {
+		logger.Warnf("Failed to log %s: %v", action, err)
+	}
+}
+
+// SetFatigue sets the fatigue model fed the session's active time and rested by its breaks
+func (s *Scheduler) SetFatigue(f *Fatigue) {
+	s.fatigue = f
+}
+
+// SetDayOverrides marks weekdays as active or quiet, taking precedence over the weekend rule
+func (s *Scheduler) SetDayOverrides(days map[time.Weekday]bool) {
+	s.dayOverrides = days
+}
+
+// isActiveDay reports whether activity is allowed on day
+func (s *Scheduler) isActiveDay(day time.Weekday) bool {
+	if active, ok := s.dayOverrides[day]; ok {
+		return active
+	}
+	return s.weekendActivity || (day != time.Saturday && day != time.Sunday)
+}
+
+// IsActiveDay reports whether t falls on a day the bot works
+func (s *Scheduler) IsActiveDay(t time.Time) bool {
+	return s.isActiveDay(t.In(s.timezone).Weekday())
+}
+
+// SetSessionLimits caps the active time of one session and of a whole day; zero disables a cap
+func (s *Scheduler) SetSessionLimits(store ActiveTimeStore, maxSession, maxDailyActive time.Duration) {
+	s.store = store
+	s.maxSession = maxSession
+	s.maxDailyActive = maxDailyActive
+}
+
+// Stop makes the session checks report ErrStopped and cuts the scheduler's waits short,
+// so work wraps up after the profile in hand. It is safe to call from another goroutine.
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+// Stopped reports whether Stop has been called
+func (s *Scheduler) Stopped() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// waitStep bounds a single sleep in a long wait, so the wait notices a jump in the wall
+// clock, e.g. after the machine was suspended, within a few minutes
+const waitStep = 5 * time.Minute
+
+// sleep waits for d, returning early once Stop is called
+func (s *Scheduler) sleep(d time.Duration) {
+	s.sleepUntil(s.clock.Now().Add(d))
+}
+
+// sleepUntil waits until the clock reads t, in steps of at most waitStep, returning
+// early once Stop is called
+func (s *Scheduler) sleepUntil(t time.Time) {
+	for {
+		remaining := t.Sub(s.clock.Now())
+		if remaining <= 0 {
+			return
+		}
+
+		select {
+		case <-s.clock.After(min(remaining, waitStep)):
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// StartSession starts counting active time, or refuses when today's cap is already used
+func (s *Scheduler) StartSession() error {
+	s.waitWhilePaused()
+	if s.Stopped() {
+		return ErrStopped
+	}
+	if err := s.CheckDailyCap(s.clock.Now()); err != nil {
+		return err
+	}
+
+	s.running = true
+	s.sessionActive = 0
+	s.activeSince = s.clock.Now()
+	s.fatigue.Rest()
+	return nil
+}
+
+// CheckSession records active time so far and reports whether a session or daily cap has been hit.
+// While a pause is requested it waits for the run to be resumed first.
+func (s *Scheduler) CheckSession() error {
+	s.waitWhilePaused()
+	return s.checkSession()
+}
+
+// checkSession is CheckSession without waiting out a pause
+func (s *Scheduler) checkSession() error {
+	if s.Stopped() {
+		return ErrStopped
+	}
+	if !s.running {
+		return nil
+	}
+	s.flush()
+
+	if err := s.CheckDailyCap(s.clock.Now()); err != nil {
+		return err
+	}
+	if s.maxSession > 0 && s.sessionActive >= s.maxSession {
+		return fmt.Errorf("%w (%s active)", ErrSessionEnded, s.sessionActive.Round(time.Second))
+	}
+	return nil
+}
+
+// CheckDailyCap reports whether the active time recorded for t's day has used up the daily cap
+func (s *Scheduler) CheckDailyCap(t time.Time) error {
+	if used := s.dailyActive(t); s.maxDailyActive > 0 && used >= s.maxDailyActive {
+		return fmt.Errorf("%w (%s of %s used today)", ErrDailyActiveCapReached, used.Round(time.Minute), s.maxDailyActive)
+	}
+	return nil
+}
+
+// ActiveTime returns the active time recorded for t's day and the daily cap, zero when uncapped
+func (s *Scheduler) ActiveTime(t time.Time) (time.Duration, time.Duration) {
+	return s.dailyActive(t), s.maxDailyActive
+}
+
+// SessionLimit returns the cap on one session's active time, zero when uncapped
+func (s *Scheduler) SessionLimit() time.Duration {
+	return s.maxSession
+}
+
+// SessionOver reports whether work should wrap up because a time cap was hit. Called
+// between profiles, it is also where a paused run waits.
+func (s *Scheduler) SessionOver() bool {
+	return s.CheckSession() != nil
+}
+
+// EndSession stops counting active time and returns the cap that ended the session, if any
+func (s *Scheduler) EndSession() error {
+	err := s.checkSession()
+	if s.running {
+		logger.Infof("Session ended after %s of active time", s.sessionActive.Round(time.Second))
+	}
+	s.running = false
+	s.activeSince = time.Time{}
+	s.SetPauseHooks(nil, nil, 0)
+	return err
+}
+
+// NextSessionStart returns when a session ended at t may be followed by the next one:
+// after a break-length gap, or at the start of the next active window if that gap runs past today's
+func (s *Scheduler) NextSessionStart(t time.Time) time.Time {
+	gap := s.breakDurationMin + s.rand.Intn(s.breakDurationMax-s.breakDurationMin+1)
+	next := t.Add(time.Duration(gap) * time.Minute)
+	if s.IsBusinessHoursAt(next) {
+		return next
+	}
+	return s.NextActiveStart(t)
+}
+
+// flush records active time since the last flush
+func (s *Scheduler) flush() {
+	if s.activeSince.IsZero() {
+		return
+	}
+
+	now := s.clock.Now()
+	d := now.Sub(s.activeSince)
+	s.activeSince = now
+	s.sessionActive += d
+	s.fatigue.AddActive(d)
+
+	if s.store != nil {
+		if err := s.store.AddActiveTime(now.In(s.timezone).Format("2006-01-02"), d); err != nil {
+			logger.Warnf("Failed to record active time: %v", err)
+		}
+	}
+}
+
+// pause stops counting active time while the scheduler waits
+func (s *Scheduler) pause() {
+	s.flush()
+	s.activeSince = time.Time{}
+}
+
+// resume counts active time again after a wait
+func (s *Scheduler) resume() {
+	if s.running {
+		s.activeSince = s.clock.Now()
+	}
+}
+
+// Hold stops counting active time until resume is called, e.g. while the bot waits on
+// the operator
+func (s *Scheduler) Hold() (resume func()) {
+	s.pause()
+	return s.resume
+}
+
+// dailyActive returns the active time recorded for t's day
+func (s *Scheduler) dailyActive(t time.Time) time.Duration {
+	if s.store == nil {
+		return 0
+	}
+
+	used, err := s.store.GetActiveTime(t.In(s.timezone).Format("2006-01-02"))
+	if err != nil {
+		logger.Warnf("Failed to get active time: %v", err)
+	}
+	return used
+}
+
 // IsBusinessHours checks if current time is within business hours
 func (s *Scheduler) IsBusinessHours() bool {
-	now := time.Now().In(s.timezone)
+	return s.IsBusinessHoursAt(s.clock.Now())
+}
+
+// IsBusinessHoursAt checks if t is within business hours
+func (s *Scheduler) IsBusinessHoursAt(t time.Time) bool {
+	now := t.In(s.timezone)
 	hour := now.Hour()
 
-	// Check if weekend
-	if !s.weekendActivity && (now.Weekday() == time.Saturday || now.Weekday() == time.Sunday) {
+	// Check if weekend or quiet day
+	if !s.isActiveDay(now.Weekday()) {
 		return false
 	}
 
@@ -52,32 +356,56 @@ func (s *Scheduler) IsBusinessHours() bool {
 
 // WaitForBusinessHours waits until business hours
 func (s *Scheduler) WaitForBusinessHours() {
-	for !s.IsBusinessHours() {
-		now := time.Now().In(s.timezone)
-		
-		// Calculate next business hour
-		var nextBusinessTime time.Time
-		
-		// If weekend, wait until Monday
-		if now.Weekday() == time.Saturday {
-			nextBusinessTime = time.Date(now.Year(), now.Month(), now.Day()+2, s.businessHoursStart, 0, 0, 0, s.timezone)
-		} else if now.Weekday() == time.Sunday {
-			nextBusinessTime = time.Date(now.Year(), now.Month(), now.Day()+1, s.businessHoursStart, 0, 0, 0, s.timezone)
-		} else {
-			// Weekday - wait until business hours start
-			if now.Hour() < s.businessHoursStart {
-				nextBusinessTime = time.Date(now.Year(), now.Month(), now.Day(), s.businessHoursStart, 0, 0, 0, s.timezone)
-			} else {
-				// After business hours - wait until next day
-				nextBusinessTime = time.Date(now.Year(), now.Month(), now.Day()+1, s.businessHoursStart, 0, 0, 0, s.timezone)
-			}
+	s.pause()
+	defer s.resume()
+
+	for !s.IsBusinessHours() && !s.Stopped() {
+		// Skips weekends and quiet days, however many in a row; the start is worked out
+		// in the configured timezone, so it stays at the same local hour across DST changes
+		nextBusinessTime := s.NextActiveStart(s.clock.Now())
+
+		waitDuration := nextBusinessTime.Sub(s.clock.Now())
+		s.recordWait(ActivityBusinessHoursWait, waitDuration)
+		logger.Infof("Outside business hours, waking at %s (in %s)", nextBusinessTime.Format(time.RFC1123), waitDuration.Round(time.Minute))
+
+		event := notify.NewEvent(notify.EventOutsideHours, fmt.Sprintf("Outside business hours, resuming at %s", nextBusinessTime.Format(time.RFC1123)))
+		if err := s.notifier.Notify(event); err != nil {
+			logger.Warnf("Failed to send notification: %v", err)
 		}
 
-		waitDuration := time.Until(nextBusinessTime)
-		time.Sleep(waitDuration)
+		s.sleepUntil(nextBusinessTime)
 	}
 }
 
+// ActiveWindowStart returns the start of the business-hours window on t's day
+func (s *Scheduler) ActiveWindowStart(t time.Time) time.Time {
+	t = t.In(s.timezone)
+	return time.Date(t.Year(), t.Month(), t.Day(), s.businessHoursStart, 0, 0, 0, s.timezone)
+}
+
+// ActiveWindowEnd returns the end of the business-hours window on t's day
+func (s *Scheduler) ActiveWindowEnd(t time.Time) time.Time {
+	t = t.In(s.timezone)
+	return time.Date(t.Year(), t.Month(), t.Day(), s.businessHoursEnd, 0, 0, 0, s.timezone)
+}
+
+// NextActiveStart returns the start of the next business-hours window after t
+func (s *Scheduler) NextActiveStart(t time.Time) time.Time {
+	t = t.In(s.timezone)
+	for i := 0; i < 8; i++ {
+		day := t.AddDate(0, 0, i)
+		start := time.Date(day.Year(), day.Month(), day.Day(), s.businessHoursStart, 0, 0, 0, s.timezone)
+		if !start.After(t) {
+			continue
+		}
+		if !s.isActiveDay(start.Weekday()) {
+			continue
+		}
+		return start
+	}
+	return t.Add(24 * time.Hour)
+}
+
 // ShouldTakeBreak determines if a break should be taken
 func (s *Scheduler) ShouldTakeBreak() bool {
 	return s.rand.Float64() < s.breakProbability
@@ -85,32 +413,133 @@ func (s *Scheduler) ShouldTakeBreak() bool {
 
 // TakeBreak takes a random break
 func (s *Scheduler) TakeBreak() {
-	duration := s.breakDurationMin + s.rand.Intn(s.breakDurationMax-s.breakDurationMin+1)
-	time.Sleep(time.Duration(duration) * time.Minute)
+	s.pause()
+	defer s.resume()
+
+	duration := time.Duration(s.breakDurationMin+s.rand.Intn(s.breakDurationMax-s.breakDurationMin+1)) * time.Minute
+	s.recordWait(ActivityBreak, duration)
+	s.sleep(duration)
+	s.fatigue.Rest()
 }
 
-// GetRandomStartTime returns a random time within business hours for starting activity
-func (s *Scheduler) GetRandomStartTime() time.Time {
-	now := time.Now().In(s.timezone)
-	
-	// Random hour within business hours
-	hour := s.businessHoursStart + s.rand.Intn(s.businessHoursEnd-s.businessHoursStart)
-	minute := s.rand.Intn(60)
+// Day planning parameters
+const (
+	minSlotGap      = 90 * time.Second // never plan two actions closer than this
+	maxSlotJitter   = 4 * time.Minute  // extra random spacing between actions in a session
+	lunchStartHour  = 12
+	lunchEndHour    = 13
+	lunchAcceptRate = 0.25 // chance a session start drawn inside lunch is kept
+)
+
+// PlanDay spreads actions over what is left of today's business-hours window.
+// Actions are clumped into 2-4 sessions, session starts avoid the lunch hour,
+// and consecutive slots are at least minSlotGap apart. When too little of the
+// window is left, fewer slots than actions are returned; nil once it is over
+// or on a quiet day.
+func (s *Scheduler) PlanDay(now time.Time, actions int) []time.Time {
+	now = now.In(s.timezone)
+	start := time.Date(now.Year(), now.Month(), now.Day(), s.businessHoursStart, 0, 0, 0, s.timezone)
+	end := s.ActiveWindowEnd(now)
+	if now.After(start) {
+		start = now
+	}
+	if actions <= 0 || !start.Before(end) || !s.isActiveDay(now.Weekday()) {
+		return nil
+	}
+
+	sessions := 2 + s.rand.Intn(3)
+	if sessions > actions {
+		sessions = actions
+	}
 
-	startTime := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, s.timezone)
+	// Share the actions out between sessions, remainder to random sessions
+	counts := make([]int, sessions)
+	for i := range counts {
+		counts[i] = actions / sessions
+	}
+	for i := 0; i < actions%sessions; i++ {
+		counts[s.rand.Intn(sessions)]++
+	}
 
-	// If the time has passed today, schedule for tomorrow
-	if startTime.Before(now) {
-		startTime = startTime.Add(24 * time.Hour)
+	// Each session starts somewhere in the first half of its share of the window
+	segment := end.Sub(start) / time.Duration(sessions)
+	slots := make([]time.Time, 0, actions)
+	for i, count := range counts {
+		at := s.sessionStart(start.Add(segment*time.Duration(i)), segment/2)
+		for j := 0; j < count; j++ {
+			slots = append(slots, at)
+			at = at.Add(minSlotGap + time.Duration(s.rand.Int63n(int64(maxSlotJitter))))
+		}
 	}
 
-	return startTime
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Before(slots[j]) })
+	return fitSlots(slots, start, end)
+}
+
+// sessionStart draws a session start in [from, from+span), rarely inside the lunch hour
+func (s *Scheduler) sessionStart(from time.Time, span time.Duration) time.Time {
+	if span <= 0 {
+		return from
+	}
+
+	var at time.Time
+	for attempt := 0; attempt < 10; attempt++ {
+		at = from.Add(time.Duration(s.rand.Int63n(int64(span))))
+		if at.Hour() < lunchStartHour || at.Hour() >= lunchEndHour || s.rand.Float64() < lunchAcceptRate {
+			return at
+		}
+	}
+	return at
+}
+
+// fitSlots enforces the minimum gap between sorted slots and pulls them back inside [start, end).
+// When the window is too short for every slot, only the ones that fit are kept, each in the
+// middle of an equal share of the window.
+func fitSlots(slots []time.Time, start, end time.Time) []time.Time {
+	n := len(slots)
+	gap := minSlotGap
+	window := end.Sub(start)
+	if fit := max(int(window/gap), 1); n > fit {
+		share := window / time.Duration(fit)
+		slots = slots[:fit]
+		for i := range slots {
+			slots[i] = start.Add(share*time.Duration(i) + share/2)
+		}
+		return slots
+	}
+
+	for i := 1; i < n; i++ {
+		if earliest := slots[i-1].Add(gap); slots[i].Before(earliest) {
+			slots[i] = earliest
+		}
+	}
+
+	last := end.Add(-time.Second)
+	for i := n - 1; i >= 0; i-- {
+		if slots[i].After(last) {
+			slots[i] = last
+		}
+		last = slots[i].Add(-gap)
+	}
+
+	return slots
 }
 
 // WaitUntil waits until a specific time
 func (s *Scheduler) WaitUntil(targetTime time.Time) {
-	duration := time.Until(targetTime)
+	duration := targetTime.Sub(s.clock.Now())
 	if duration > 0 {
-		time.Sleep(duration)
+		s.recordWait(ActivitySlotWait, duration)
+		s.pause()
+		s.sleep(duration)
+		s.resume()
+	}
+}
+
+// WaitForNextSession waits outside any session until the next one may start at t
+func (s *Scheduler) WaitForNextSession(t time.Time) {
+	if duration := t.Sub(s.clock.Now()); duration > 0 {
+		s.recordWait(ActivitySessionWait, duration)
+		s.sleep(duration)
 	}
 }
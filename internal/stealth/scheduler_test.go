@@ -0,0 +1,261 @@
+package stealth
+
+import (
+	"math/rand"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/clock"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger("error", "console", "stdout", logger.Rotation{})
+	os.Exit(m.Run())
+}
+
+// newTestScheduler returns a weekday 9-17 scheduler in New York on a fake clock set to now
+func newTestScheduler(t *testing.T, now time.Time) (*Scheduler, *clock.Fake) {
+	t.Helper()
+
+	s, err := NewScheduler(9, 17, "America/New_York", false, 5, 10, 0)
+	if err != nil {
+		t.Fatalf("NewScheduler: %v", err)
+	}
+	fake := clock.NewFake(now)
+	s.SetClock(fake)
+	return s, fake
+}
+
+func TestWaitForBusinessHours(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, ny)
+	}
+
+	for _, tt := range []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"within hours", at(2026, time.March, 10, 10), at(2026, time.March, 10, 10)},
+		{"before hours", at(2026, time.March, 10, 7), at(2026, time.March, 10, 9)},
+		{"weekend at month end", at(2026, time.January, 31, 10), at(2026, time.February, 2, 9)},
+		{"friday evening at month end", at(2026, time.July, 31, 18), at(2026, time.August, 3, 9)},
+		{"weekend at year end", at(2027, time.January, 2, 12), at(2027, time.January, 4, 9)},
+		{"over spring forward", at(2026, time.March, 6, 18), at(2026, time.March, 9, 9)},
+		{"over fall back", at(2026, time.October, 30, 18), at(2026, time.November, 2, 9)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, fake := newTestScheduler(t, tt.now)
+
+			s.WaitForBusinessHours()
+
+			if got := fake.Now(); !got.Equal(tt.want) {
+				t.Fatalf("woke at %s, want %s", got.In(ny), tt.want)
+			}
+			// No single wait may outlast a step, so Stop is noticed while waiting
+			for _, d := range fake.Slept() {
+				if d > waitStep {
+					t.Fatalf("slept %s in one go", d)
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForBusinessHoursStops(t *testing.T) {
+	s, fake := newTestScheduler(t, time.Date(2026, time.January, 31, 10, 0, 0, 0, time.UTC))
+	s.Stop()
+
+	s.WaitForBusinessHours()
+
+	if len(fake.Slept()) != 0 {
+		t.Fatalf("slept %v after Stop", fake.Slept())
+	}
+}
+
+func TestTakeBreak(t *testing.T) {
+	start := time.Date(2026, time.March, 10, 14, 0, 0, 0, time.UTC)
+	s, fake := newTestScheduler(t, start)
+
+	s.TakeBreak()
+
+	if took := fake.Now().Sub(start); took < 5*time.Minute || took > 10*time.Minute {
+		t.Fatalf("break took %s, want 5-10 minutes", took)
+	}
+}
+
+func TestWaitActionDelay(t *testing.T) {
+	fake := clock.NewFake(time.Now())
+	timing := NewTimingController(2, 4, 1, 2, 200)
+	timing.SetClock(fake)
+
+	for i := 0; i < 20; i++ {
+		timing.WaitActionDelay()
+	}
+
+	slept := fake.Slept()
+	if len(slept) != 20 {
+		t.Fatalf("slept %d times, want 20", len(slept))
+	}
+	for _, d := range slept {
+		if d < 2*time.Second || d > 4*time.Second {
+			t.Fatalf("action delay %s outside 2-4s", d)
+		}
+	}
+}
+
+func TestNextActiveStart(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		weekends bool
+		now      time.Time
+		want     time.Time
+		wait     time.Duration
+	}{
+		{
+			name: "last saturday of the month",
+			now:  time.Date(2026, time.October, 31, 11, 0, 0, 0, ny),
+			want: time.Date(2026, time.November, 2, 9, 0, 0, 0, ny),
+			// The clocks go back an hour on the Sunday in between
+			wait: 47 * time.Hour,
+		},
+		{
+			name: "new year's eve",
+			now:  time.Date(2026, time.December, 31, 18, 0, 0, 0, ny),
+			want: time.Date(2027, time.January, 1, 9, 0, 0, 0, ny),
+			wait: 15 * time.Hour,
+		},
+		{
+			name:     "spring forward night",
+			weekends: true,
+			now:      time.Date(2026, time.March, 8, 1, 30, 0, 0, ny),
+			want:     time.Date(2026, time.March, 8, 9, 0, 0, 0, ny),
+			// 2am is skipped, so 9am comes an hour sooner than the wall clock says
+			wait: 6*time.Hour + 30*time.Minute,
+		},
+		{
+			name: "friday before spring forward",
+			now:  time.Date(2026, time.March, 6, 17, 0, 0, 0, ny),
+			want: time.Date(2026, time.March, 9, 9, 0, 0, 0, ny),
+			wait: 63 * time.Hour,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewScheduler(9, 17, "America/New_York", tt.weekends, 5, 10, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := s.NextActiveStart(tt.now)
+			if !got.Equal(tt.want) || got.In(ny).Hour() != 9 {
+				t.Fatalf("NextActiveStart = %s, want %s", got.In(ny), tt.want)
+			}
+			if wait := got.Sub(tt.now); wait != tt.wait {
+				t.Fatalf("wait = %s, want %s", wait, tt.wait)
+			}
+		})
+	}
+}
+
+// checkSlots fails unless slots are in order, inside [start, end) and at least minSlotGap apart
+func checkSlots(t *testing.T, slots []time.Time, start, end time.Time) {
+	t.Helper()
+	for i, slot := range slots {
+		if slot.Before(start) || !slot.Before(end) {
+			t.Fatalf("slot %d at %s is outside %s-%s", i, slot, start, end)
+		}
+		if i > 0 {
+			if gap := slot.Sub(slots[i-1]); gap < minSlotGap {
+				t.Fatalf("slots %d and %d are %s apart", i-1, i, gap)
+			}
+		}
+	}
+}
+
+func TestPlanDay(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := func(hour, minute int) time.Time {
+		return time.Date(2026, time.March, 10, hour, minute, 0, 0, ny)
+	}
+
+	for _, tt := range []struct {
+		name    string
+		now     time.Time
+		actions int
+		want    int
+	}{
+		{"before hours", day(7, 0), 25, 25},
+		{"mid-afternoon", day(14, 30), 12, 12},
+		{"single action", day(9, 0), 1, 1},
+		{"ten minutes left", day(16, 50), 20, 6},
+		{"a minute left", day(16, 59), 5, 1},
+		{"after hours", day(17, 0), 5, 0},
+		{"nothing to do", day(10, 0), 0, 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := int64(1); seed <= 20; seed++ {
+				s, _ := newTestScheduler(t, tt.now)
+				s.rand = rand.New(rand.NewSource(seed))
+
+				slots := s.PlanDay(tt.now, tt.actions)
+				if len(slots) != tt.want {
+					t.Fatalf("seed %d: planned %d slots, want %d", seed, len(slots), tt.want)
+				}
+				start := s.ActiveWindowStart(tt.now)
+				if tt.now.After(start) {
+					start = tt.now
+				}
+				checkSlots(t, slots, start, s.ActiveWindowEnd(tt.now))
+			}
+		})
+	}
+}
+
+func TestPlanDayQuietDay(t *testing.T) {
+	saturday := time.Date(2026, time.March, 14, 8, 0, 0, 0, time.UTC)
+	s, _ := newTestScheduler(t, saturday)
+
+	if slots := s.PlanDay(saturday, 10); slots != nil {
+		t.Fatalf("planned %d slots on a weekend", len(slots))
+	}
+}
+
+func TestFitSlotsSpreadsWhatFits(t *testing.T) {
+	start := time.Date(2026, time.March, 10, 16, 50, 0, 0, time.UTC)
+	end := start.Add(10 * time.Minute)
+
+	// Twenty actions bunched at the start of a ten minute window
+	slots := make([]time.Time, 20)
+	for i := range slots {
+		slots[i] = start.Add(time.Duration(i) * time.Second)
+	}
+
+	slots = fitSlots(slots, start, end)
+	if len(slots) != 6 {
+		t.Fatalf("kept %d slots, want 6", len(slots))
+	}
+	checkSlots(t, slots, start, end)
+
+	// The kept slots cover the whole window rather than its start
+	share := 10 * time.Minute / 6
+	if first := slots[0].Sub(start); first != share/2 {
+		t.Fatalf("first slot %s into the window, want %s", first, share/2)
+	}
+	if last := end.Sub(slots[len(slots)-1]); last != share/2 {
+		t.Fatalf("last slot %s before the end, want %s", last, share/2)
+	}
+}
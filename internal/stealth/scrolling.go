@@ -1,9 +1,11 @@
 package stealth
 
 import (
+	"fmt"
 	"math/rand"
 	"time"
 
+	"github.com/Tanukumar01/linkedin-automation/internal/clock"
 	"github.com/go-rod/rod"
 )
 
@@ -14,6 +16,8 @@ type Scroller struct {
 	scrollBackProbability float64
 	pauseProbability      float64
 	rand                  *rand.Rand
+	metrics               *SessionMetrics
+	clock                 clock.Clock
 }
 
 // NewScroller creates a new scroller
@@ -24,60 +28,126 @@ func NewScroller(speedMin, speedMax int, scrollBackProb, pauseProb float64) *Scr
 		scrollBackProbability: scrollBackProb,
 		pauseProbability:      pauseProb,
 		rand:                  rand.New(rand.NewSource(time.Now().UnixNano())),
+		clock:                 clock.Real{},
 	}
 }
 
+// SetMetrics sets the recorder for realized scrolling behavior
+func (s *Scroller) SetMetrics(m *SessionMetrics) {
+	s.metrics = m
+}
+
+// SetClock sets the clock the scroller waits on and times its scrolls with
+func (s *Scroller) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// minChunk is the smallest scroll step in pixels; short distances take fewer chunks
+const minChunk = 40
+
+// maxScrollRounds bounds how often ScrollToBottom follows a page that keeps growing
+const maxScrollRounds = 10
+
 // ScrollDown scrolls down the page naturally
 func (s *Scroller) ScrollDown(page *rod.Page, distance int) error {
-	// Break scrolling into smaller chunks
-	chunks := 5 + s.rand.Intn(10)
-	chunkSize := distance / chunks
+	return s.scroll(page, distance, 1)
+}
+
+// scroll covers distance in the given direction (1 down, -1 up) in uneven chunks,
+// making up for any scroll-back so the total lands on the distance
+func (s *Scroller) scroll(page *rod.Page, distance, direction int) error {
+	plan := planScroll(distance, s.rand)
+
+	start := s.clock.Now()
+	scrolled, pauses := 0, 0
+	defer func() {
+		s.metrics.recordScroll(scrolled, s.clock.Now().Sub(start), pauses, len(plan))
+	}()
 
-	for i := 0; i < chunks; i++ {
-		// Calculate scroll amount with variation
-		scrollAmount := chunkSize + s.rand.Intn(chunkSize/2) - chunkSize/4
+	for i := 0; i < len(plan); i++ {
+		scrollAmount := plan[i]
 
-		// Scroll
-		err := page.Mouse.Scroll(0, float64(scrollAmount), chunks)
+		err := page.Mouse.Scroll(0, float64(direction*scrollAmount), len(plan))
 		if err != nil {
 			return err
 		}
+		scrolled += scrollAmount
 
 		// Variable delay between scrolls
-		speed := s.speedMin + s.rand.Intn(s.speedMax-s.speedMin+1)
-		time.Sleep(time.Duration(speed) * time.Millisecond)
+		speed := s.speedMin + s.rand.Intn(max(s.speedMax-s.speedMin, 0)+1)
+		s.clock.Sleep(time.Duration(speed) * time.Millisecond)
 
 		// Random pause
 		if s.rand.Float64() < s.pauseProbability {
+			pauses++
 			pauseDuration := time.Duration(500+s.rand.Intn(1500)) * time.Millisecond
-			time.Sleep(pauseDuration)
+			s.clock.Sleep(pauseDuration)
 		}
 
-		// Random scroll back
-		if s.rand.Float64() < s.scrollBackProbability {
-			scrollBack := s.rand.Intn(scrollAmount / 2)
-			page.Mouse.Scroll(0, float64(-scrollBack), 1)
-			time.Sleep(time.Duration(200+s.rand.Intn(300)) * time.Millisecond)
+		// Random scroll back when going down, won back by the next chunk
+		if direction > 0 && scrollAmount >= 2 && s.rand.Float64() < s.scrollBackProbability {
+			scrollBack := 1 + s.rand.Intn(scrollAmount/2)
+			if err := page.Mouse.Scroll(0, float64(-scrollBack), 1); err != nil {
+				return err
+			}
+			scrolled -= scrollBack
+			if i+1 < len(plan) {
+				plan[i+1] += scrollBack
+			} else {
+				plan = append(plan, scrollBack)
+			}
+			s.clock.Sleep(time.Duration(200+s.rand.Intn(300)) * time.Millisecond)
 		}
 	}
 
 	return nil
 }
 
+// planScroll splits distance into 5 to 14 chunks of uneven size, or fewer when the
+// distance is short, so that no chunk is under minChunk. The chunks add up to distance.
+func planScroll(distance int, rng *rand.Rand) []int {
+	if distance <= 0 {
+		return nil
+	}
+
+	chunks := min(5+rng.Intn(10), max(distance/minChunk, 1))
+	plan := make([]int, 0, chunks)
+
+	remaining := distance
+	for left := chunks; left > 1; left-- {
+		// An even share of what's left, give or take a quarter
+		share := remaining / left
+		jitter := share / 4
+		amount := share
+		if jitter > 0 {
+			amount += rng.Intn(2*jitter+1) - jitter
+		}
+		// Leave every later chunk at least its minimum
+		floor := min(minChunk, share)
+		amount = min(max(amount, floor), remaining-floor*(left-1))
+		plan = append(plan, amount)
+		remaining -= amount
+	}
+
+	return append(plan, remaining)
+}
+
 // ScrollToElement scrolls to make an element visible
 func (s *Scroller) ScrollToElement(page *rod.Page, element *rod.Element) error {
 	// Get element position using JS since Box() is not available
-	yVal := page.MustEval(`(el) => {
-		const rect = el.getBoundingClientRect();
-		return rect.top + window.pageYOffset;
-	}`, element)
-
-	// Get viewport height
-	viewport := page.MustEval(`() => window.innerHeight`).Int()
+	res, err := element.Eval(`function() {
+		const rect = this.getBoundingClientRect();
+		return [rect.top + window.pageYOffset, window.innerHeight, window.pageYOffset];
+	}`)
+	if err != nil {
+		return fmt.Errorf("failed to measure element: %w", err)
+	}
+	yVal := res.Value.Arr()[0].Int()
+	viewport := res.Value.Arr()[1].Int()
+	currentScroll := res.Value.Arr()[2].Int()
 
 	// Calculate scroll distance
-	currentScroll := page.MustEval(`() => window.pageYOffset`).Int()
-	targetScroll := yVal.Int() - viewport/2
+	targetScroll := yVal - viewport/2
 
 	distance := targetScroll - currentScroll
 
@@ -92,49 +162,44 @@ func (s *Scroller) ScrollToElement(page *rod.Page, element *rod.Element) error {
 
 // ScrollUp scrolls up the page naturally
 func (s *Scroller) ScrollUp(page *rod.Page, distance int) error {
-	// Break scrolling into smaller chunks
-	chunks := 5 + s.rand.Intn(10)
-	chunkSize := distance / chunks
-
-	for i := 0; i < chunks; i++ {
-		// Calculate scroll amount with variation
-		scrollAmount := chunkSize + s.rand.Intn(chunkSize/2) - chunkSize/4
+	return s.scroll(page, distance, -1)
+}
 
-		// Scroll up (negative value)
-		err := page.Mouse.Scroll(0, float64(-scrollAmount), chunks)
+// ScrollToBottom scrolls to the bottom of the page, following pages that load more
+// content as they are scrolled until the height stops growing
+func (s *Scroller) ScrollToBottom(page *rod.Page) error {
+	height := -1
+	for round := 0; round < maxScrollRounds; round++ {
+		res, err := page.Eval(`() => [document.body.scrollHeight, window.pageYOffset + window.innerHeight]`)
 		if err != nil {
-			return err
+			return fmt.Errorf("failed to measure page height: %w", err)
 		}
+		newHeight := res.Value.Arr()[0].Int()
+		bottom := res.Value.Arr()[1].Int()
 
-		// Variable delay between scrolls
-		speed := s.speedMin + s.rand.Intn(s.speedMax-s.speedMin+1)
-		time.Sleep(time.Duration(speed) * time.Millisecond)
+		if newHeight <= height {
+			return nil
+		}
+		height = newHeight
 
-		// Random pause
-		if s.rand.Float64() < s.pauseProbability {
-			pauseDuration := time.Duration(500+s.rand.Intn(1500)) * time.Millisecond
-			time.Sleep(pauseDuration)
+		if err := s.ScrollDown(page, newHeight-bottom); err != nil {
+			return err
 		}
+
+		// Give lazy-loaded content a moment to arrive
+		s.clock.Sleep(time.Duration(600+s.rand.Intn(600)) * time.Millisecond)
 	}
 
 	return nil
 }
 
-// ScrollToBottom scrolls to the bottom of the page
-func (s *Scroller) ScrollToBottom(page *rod.Page) error {
-	// Get page height
-	pageHeight := page.MustEval(`() => document.body.scrollHeight`).Int()
-	currentScroll := page.MustEval(`() => window.pageYOffset`).Int()
-
-	distance := pageHeight - currentScroll
-
-	return s.ScrollDown(page, distance)
-}
-
 // ScrollToTop scrolls to the top of the page
 func (s *Scroller) ScrollToTop(page *rod.Page) error {
-	currentScroll := page.MustEval(`() => window.pageYOffset`).Int()
-	return s.ScrollUp(page, currentScroll)
+	res, err := page.Eval(`() => window.pageYOffset`)
+	if err != nil {
+		return fmt.Errorf("failed to read scroll position: %w", err)
+	}
+	return s.ScrollUp(page, res.Value.Int())
 }
 
 // RandomScroll performs random scrolling behavior
@@ -0,0 +1,54 @@
+package stealth
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPlanScrollAddsUpToTheDistance(t *testing.T) {
+	for _, distance := range []int{1, 39, 40, 120, 500, 1337, 5000, 40000} {
+		for seed := int64(0); seed < 50; seed++ {
+			plan := planScroll(distance, rand.New(rand.NewSource(seed)))
+
+			total := 0
+			for _, chunk := range plan {
+				total += chunk
+			}
+			if total != distance {
+				t.Fatalf("%dpx, seed %d: chunks %v add up to %d", distance, seed, plan, total)
+			}
+
+			if want := min(14, max(distance/minChunk, 1)); len(plan) > want || (distance >= 5*minChunk && len(plan) < 5) {
+				t.Fatalf("%dpx, seed %d: %d chunks", distance, seed, len(plan))
+			}
+			if len(plan) > 1 {
+				for _, chunk := range plan {
+					if chunk < minChunk {
+						t.Fatalf("%dpx, seed %d: chunk of %dpx in %v", distance, seed, chunk, plan)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestPlanScrollIsUneven(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		plan := planScroll(3000, rng)
+		for _, chunk := range plan[1:] {
+			if chunk != plan[0] {
+				return
+			}
+		}
+	}
+	t.Fatal("every plan split the distance into equal chunks")
+}
+
+func TestPlanScrollNothingToScroll(t *testing.T) {
+	for _, distance := range []int{0, -200} {
+		if plan := planScroll(distance, rand.New(rand.NewSource(1))); len(plan) != 0 {
+			t.Fatalf("planScroll(%d) = %v, want no chunks", distance, plan)
+		}
+	}
+}
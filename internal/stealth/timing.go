@@ -3,6 +3,8 @@ package stealth
 import (
 	"math/rand"
 	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/clock"
 )
 
 // TimingController handles randomized timing patterns
@@ -13,6 +15,9 @@ type TimingController struct {
 	thinkTimeMax    int
 	readingSpeedWPM int
 	rand            *rand.Rand
+	clock           clock.Clock
+	fatigue         *Fatigue // stretches every delay as the session wears on; nil for none
+	pace            float64  // the session persona's factor for every delay
 }
 
 // NewTimingController creates a new timing controller
@@ -24,19 +29,41 @@ func NewTimingController(actionDelayMin, actionDelayMax, thinkTimeMin, thinkTime
 		thinkTimeMax:    thinkTimeMax,
 		readingSpeedWPM: readingSpeedWPM,
 		rand:            rand.New(rand.NewSource(time.Now().UnixNano())),
+		clock:           clock.Real{},
+		pace:            1,
 	}
 }
 
+// SetClock sets the clock the controller waits on
+func (t *TimingController) SetClock(c clock.Clock) {
+	t.clock = c
+}
+
+// SetFatigue sets the fatigue model that stretches the delays as the session wears on
+func (t *TimingController) SetFatigue(f *Fatigue) {
+	t.fatigue = f
+}
+
+// SetPace sets the factor every delay is scaled by, the session persona's Delays
+func (t *TimingController) SetPace(factor float64) {
+	t.pace = factor
+}
+
+// scale stretches d by the pace and the current fatigue
+func (t *TimingController) scale(d time.Duration) time.Duration {
+	return t.fatigue.scale(time.Duration(float64(d) * t.pace))
+}
+
 // ActionDelay returns a random delay between actions
 func (t *TimingController) ActionDelay() time.Duration {
 	delay := t.actionDelayMin + t.rand.Intn(t.actionDelayMax-t.actionDelayMin+1)
-	return time.Duration(delay) * time.Second
+	return t.scale(time.Duration(delay) * time.Second)
 }
 
 // ThinkTime returns a random "think time" before an action
 func (t *TimingController) ThinkTime() time.Duration {
 	delay := t.thinkTimeMin + t.rand.Intn(t.thinkTimeMax-t.thinkTimeMin+1)
-	return time.Duration(delay) * time.Second
+	return t.scale(time.Duration(delay) * time.Second)
 }
 
 // ReadingTime calculates reading time based on word count
@@ -53,25 +80,25 @@ func (t *TimingController) ReadingTime(wordCount int) time.Duration {
 	variation := 0.2
 	factor := 1 + (t.rand.Float64()*2-1)*variation
 
-	return time.Duration(seconds*factor) * time.Second
+	return t.scale(time.Duration(seconds*factor) * time.Second)
 }
 
 // ShortPause returns a short random pause
 func (t *TimingController) ShortPause() time.Duration {
 	delay := 300 + t.rand.Intn(700)
-	return time.Duration(delay) * time.Millisecond
+	return t.scale(time.Duration(delay) * time.Millisecond)
 }
 
 // MediumPause returns a medium random pause
 func (t *TimingController) MediumPause() time.Duration {
 	delay := 1000 + t.rand.Intn(2000)
-	return time.Duration(delay) * time.Millisecond
+	return t.scale(time.Duration(delay) * time.Millisecond)
 }
 
 // LongPause returns a long random pause
 func (t *TimingController) LongPause() time.Duration {
 	delay := 3000 + t.rand.Intn(5000)
-	return time.Duration(delay) * time.Millisecond
+	return t.scale(time.Duration(delay) * time.Millisecond)
 }
 
 // RandomPause returns a random pause of varying length
@@ -89,15 +116,15 @@ func (t *TimingController) RandomPause() time.Duration {
 
 // Wait waits for the specified duration
 func (t *TimingController) Wait(duration time.Duration) {
-	time.Sleep(duration)
+	t.clock.Sleep(duration)
 }
 
 // WaitActionDelay waits for a random action delay
 func (t *TimingController) WaitActionDelay() {
-	time.Sleep(t.ActionDelay())
+	t.clock.Sleep(t.ActionDelay())
 }
 
 // WaitThinkTime waits for a random think time
 func (t *TimingController) WaitThinkTime() {
-	time.Sleep(t.ThinkTime())
+	t.clock.Sleep(t.ThinkTime())
 }
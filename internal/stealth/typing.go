@@ -1,9 +1,11 @@
 package stealth
 
 import (
+	"fmt"
 	"math/rand"
 	"time"
 
+	"github.com/Tanukumar01/linkedin-automation/internal/clock"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/input"
 )
@@ -14,7 +16,11 @@ type Typer struct {
 	wpmMax           int
 	typoProbability  float64
 	pauseProbability float64
+	typos            TypoWeights
 	rand             *rand.Rand
+	metrics          *SessionMetrics
+	clock            clock.Clock
+	fatigue          *Fatigue // slows typing and adds typos as the session wears on; nil for none
 }
 
 // NewTyper creates a new typer
@@ -24,10 +30,69 @@ func NewTyper(wpmMin, wpmMax int, typoProbability, pauseProbability float64) *Ty
 		wpmMax:           wpmMax,
 		typoProbability:  typoProbability,
 		pauseProbability: pauseProbability,
+		typos:            DefaultTypoWeights,
 		rand:             rand.New(rand.NewSource(time.Now().UnixNano())),
+		clock:            clock.Real{},
 	}
 }
 
+// SetTypoWeights sets which kinds of typo are made and how often they are corrected late
+func (t *Typer) SetTypoWeights(w TypoWeights) {
+	t.typos = w
+}
+
+// SetFatigue sets the fatigue model that slows typing and adds typos as the session wears on
+func (t *Typer) SetFatigue(f *Fatigue) {
+	t.fatigue = f
+}
+
+// SetMetrics sets the recorder for realized typing behavior
+func (t *Typer) SetMetrics(m *SessionMetrics) {
+	t.metrics = m
+}
+
+// SetClock sets the clock the typer waits on and times its typing with
+func (t *Typer) SetClock(c clock.Clock) {
+	t.clock = c
+}
+
+// keyboard is the part of a page's input the typer drives
+type keyboard interface {
+	Press(key input.Key) error
+	Release(key input.Key) error
+	InsertText(text string) error
+}
+
+// rodKeyboard sends keystrokes and text insertion to a rod page
+type rodKeyboard struct {
+	page *rod.Page
+}
+
+func (k rodKeyboard) Press(key input.Key) error   { return k.page.Keyboard.Press(key) }
+func (k rodKeyboard) Release(key input.Key) error { return k.page.Keyboard.Release(key) }
+func (k rodKeyboard) InsertText(text string) error {
+	return k.page.InsertText(text)
+}
+
+// keystroke is the key that produces a character, and whether Shift is held for it
+type keystroke struct {
+	key   input.Key
+	shift bool
+}
+
+// keystrokes maps the characters on a US keyboard to their keys; anything else is inserted as text
+var keystrokes = func() map[rune]keystroke {
+	m := map[rune]keystroke{'\n': {input.Enter, false}}
+	for r := rune(' '); r <= '~'; r++ {
+		if shifted, ok := input.Key(r).Shift(); ok {
+			m[r] = keystroke{input.Key(r), false}
+			m[rune(shifted)] = keystroke{shifted, true}
+		}
+	}
+	m[' '] = keystroke{input.Space, false}
+	return m
+}()
+
 // TypeText types text with human-like behavior
 func (t *Typer) TypeText(page *rod.Page, element *rod.Element, text string) error {
 	// Focus on the element
@@ -35,55 +100,102 @@ func (t *Typer) TypeText(page *rod.Page, element *rod.Element, text string) erro
 		return err
 	}
 
+	return t.typeText(rodKeyboard{page: page}, text)
+}
+
+// typeText types text into whatever has focus, one character at a time
+func (t *Typer) typeText(kb keyboard, text string) error {
 	// Calculate typing speed (characters per minute to milliseconds per character)
 	wpm := t.wpmMin + t.rand.Intn(t.wpmMax-t.wpmMin+1)
 	cpm := wpm * 5 // Average word length is 5 characters
 	msPerChar := 60000 / cpm
 
-	for i, char := range text {
+	// A tired typist is slower and slips more often
+	tiredness := t.fatigue.Multiplier()
+	msPerChar = int(float64(msPerChar) * tiredness)
+	typoProbability := min(t.typoProbability*tiredness, 1)
+
+	start := t.clock.Now()
+	chars, pauses, typos := len([]rune(text)), 0, 0
+	defer func() {
+		t.metrics.recordTyping(chars, t.clock.Now().Sub(start), pauses, typos)
+	}()
+
+	inTypo := false
+	for _, key := range PlanTyping(text, typoProbability, t.typos, t.rand) {
+		if key.Typo && !inTypo {
+			typos++
+		}
+		inTypo = key.Typo
+
+		// Corrections come at a typing pace, a beat after noticing
+		if key.Backspace {
+			if err := typeKey(kb, keystroke{key: input.Backspace}); err != nil {
+				return err
+			}
+			t.clock.Sleep(time.Duration(msPerChar+t.rand.Intn(100)) * time.Millisecond)
+			continue
+		}
+
 		// Random pause before some characters
 		if t.rand.Float64() < t.pauseProbability {
+			pauses++
 			pauseDuration := time.Duration(200+t.rand.Intn(500)) * time.Millisecond
-			time.Sleep(pauseDuration)
+			t.clock.Sleep(pauseDuration)
 		}
 
-		// Simulate typo
-		if t.rand.Float64() < t.typoProbability && i > 0 {
-			// Type a wrong character
-			wrongChar := t.getRandomChar()
-			page.Keyboard.Type(input.Key(wrongChar))
-			time.Sleep(time.Duration(msPerChar+t.rand.Intn(100)) * time.Millisecond)
-
-			// Backspace to correct
-			page.Keyboard.Press(input.Backspace)
-			time.Sleep(time.Duration(msPerChar+t.rand.Intn(100)) * time.Millisecond)
+		if err := typeChar(kb, key.Char); err != nil {
+			return err
 		}
 
-		// Type the correct character
-		page.Keyboard.Type(input.Key(char))
-
 		// Variable delay between characters
 		delay := msPerChar + t.rand.Intn(msPerChar/2) - msPerChar/4
-		time.Sleep(time.Duration(delay) * time.Millisecond)
+		t.clock.Sleep(time.Duration(delay) * time.Millisecond)
 
 		// Longer pause after punctuation
-		if char == '.' || char == ',' || char == '!' || char == '?' {
-			time.Sleep(time.Duration(100+t.rand.Intn(300)) * time.Millisecond)
+		if char := key.Char; char == '.' || char == ',' || char == '!' || char == '?' {
+			t.clock.Sleep(time.Duration(100+t.rand.Intn(300)) * time.Millisecond)
 		}
 
 		// Pause between words
-		if char == ' ' {
-			time.Sleep(time.Duration(50+t.rand.Intn(150)) * time.Millisecond)
+		if key.Char == ' ' {
+			t.clock.Sleep(time.Duration(50+t.rand.Intn(150)) * time.Millisecond)
 		}
 	}
 
 	return nil
 }
 
-// getRandomChar returns a random character for typo simulation
-func (t *Typer) getRandomChar() rune {
-	chars := []rune("abcdefghijklmnopqrstuvwxyz")
-	return chars[t.rand.Intn(len(chars))]
+// typeChar types one character: with its key, holding Shift when needed, or by
+// inserting it as text when no key produces it (accented letters, dashes, curly quotes)
+func typeChar(kb keyboard, char rune) error {
+	stroke, ok := keystrokes[char]
+	if !ok {
+		if err := kb.InsertText(string(char)); err != nil {
+			return fmt.Errorf("failed to insert %q: %w", char, err)
+		}
+		return nil
+	}
+
+	if err := typeKey(kb, stroke); err != nil {
+		return fmt.Errorf("failed to type %q: %w", char, err)
+	}
+	return nil
+}
+
+// typeKey presses and releases a key, inside Shift when the keystroke needs it
+func typeKey(kb keyboard, stroke keystroke) error {
+	if stroke.shift {
+		if err := kb.Press(input.ShiftLeft); err != nil {
+			return err
+		}
+		defer kb.Release(input.ShiftLeft)
+	}
+
+	if err := kb.Press(stroke.key); err != nil {
+		return err
+	}
+	return kb.Release(stroke.key)
 }
 
 // ClearAndType clears an input field and types new text
@@ -97,10 +209,10 @@ func (t *Typer) ClearAndType(page *rod.Page, element *rod.Element, text string)
 	page.Keyboard.Press(input.ControlLeft)
 	page.Keyboard.Type(input.Key('a'))
 	page.Keyboard.Release(input.ControlLeft)
-	time.Sleep(time.Duration(50+t.rand.Intn(100)) * time.Millisecond)
+	t.clock.Sleep(time.Duration(50+t.rand.Intn(100)) * time.Millisecond)
 
 	page.Keyboard.Press(input.Backspace)
-	time.Sleep(time.Duration(100+t.rand.Intn(200)) * time.Millisecond)
+	t.clock.Sleep(time.Duration(100+t.rand.Intn(200)) * time.Millisecond)
 
 	// Type new text
 	return t.TypeText(page, element, text)
@@ -0,0 +1,125 @@
+package stealth
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/clock"
+	"github.com/go-rod/rod/lib/input"
+)
+
+// recordingKeyboard writes down every key event and the text it produces
+type recordingKeyboard struct {
+	events []string
+	shift  bool
+	text   strings.Builder
+	err    error
+}
+
+func (k *recordingKeyboard) Press(key input.Key) error {
+	if k.err != nil {
+		return k.err
+	}
+	k.events = append(k.events, "down "+keyName(key))
+	switch {
+	case key == input.ShiftLeft:
+		k.shift = true
+	case key == input.Enter:
+		k.text.WriteByte('\n')
+	case key == input.Space:
+		k.text.WriteByte(' ')
+	case key == input.Backspace:
+		s := []rune(k.text.String())
+		k.text.Reset()
+		k.text.WriteString(string(s[:len(s)-1]))
+	default:
+		k.text.WriteRune(rune(key))
+	}
+	return nil
+}
+
+func (k *recordingKeyboard) Release(key input.Key) error {
+	k.events = append(k.events, "up "+keyName(key))
+	if key == input.ShiftLeft {
+		k.shift = false
+	}
+	return nil
+}
+
+func (k *recordingKeyboard) InsertText(text string) error {
+	k.events = append(k.events, "insert "+text)
+	k.text.WriteString(text)
+	return nil
+}
+
+func keyName(key input.Key) string {
+	switch key {
+	case input.ShiftLeft:
+		return "Shift"
+	case input.Enter:
+		return "Enter"
+	case input.Space:
+		return "Space"
+	case input.Backspace:
+		return "Backspace"
+	}
+	return string(rune(key))
+}
+
+func newTestTyper(typos float64) *Typer {
+	typer := NewTyper(60, 80, typos, 0.1)
+	typer.rand = rand.New(rand.NewSource(1))
+	typer.SetClock(clock.NewFake(time.Now()))
+	return typer
+}
+
+func TestTypeCharKeys(t *testing.T) {
+	for _, tt := range []struct {
+		char rune
+		want string
+	}{
+		{'a', "down a,up a"},
+		{'A', "down Shift,down A,up A,up Shift"},
+		{'?', "down Shift,down ?,up ?,up Shift"},
+		{' ', "down Space,up Space"},
+		{'\n', "down Enter,up Enter"},
+		{'é', "insert é"},
+		{'—', "insert —"},
+		{'’', "insert ’"},
+	} {
+		kb := &recordingKeyboard{}
+		if err := typeChar(kb, tt.char); err != nil {
+			t.Fatalf("typeChar(%q): %v", tt.char, err)
+		}
+		if got := strings.Join(kb.events, ","); got != tt.want {
+			t.Errorf("typeChar(%q) = %s, want %s", tt.char, got, tt.want)
+		}
+	}
+}
+
+func TestTypeTextProducesTheText(t *testing.T) {
+	text := "Hi Zoë — loved your talk on \"Go & SQLite\"!\nCheers, Ada (she/her) 100%"
+
+	for _, typos := range []float64{0, 0.3} {
+		kb := &recordingKeyboard{}
+		if err := newTestTyper(typos).typeText(kb, text); err != nil {
+			t.Fatalf("typeText: %v", err)
+		}
+		if kb.text.String() != text {
+			t.Fatalf("typed %q, want %q", kb.text.String(), text)
+		}
+		if kb.shift {
+			t.Fatal("Shift is still held")
+		}
+	}
+}
+
+func TestTypeTextReturnsKeyErrors(t *testing.T) {
+	kb := &recordingKeyboard{err: errors.New("target closed")}
+	if err := newTestTyper(0).typeText(kb, "Hi"); err == nil || !strings.Contains(err.Error(), `failed to type 'H'`) {
+		t.Fatalf("typeText = %v, want the keystroke error", err)
+	}
+}
@@ -0,0 +1,124 @@
+package stealth
+
+import (
+	"math/rand"
+	"unicode"
+)
+
+// TypoWeights weighs the kinds of typo and sets how often one is noticed late
+type TypoWeights struct {
+	Adjacent       float64 // a neighboring key instead of the intended one
+	Doubled        float64 // the intended key twice
+	Transposed     float64 // the intended key and the next one swapped
+	LateCorrection float64 // chance of typing on a few characters before correcting
+}
+
+// DefaultTypoWeights favors hitting a neighboring key
+var DefaultTypoWeights = TypoWeights{Adjacent: 0.6, Doubled: 0.2, Transposed: 0.2, LateCorrection: 0.2}
+
+// TypedKey is one keystroke of a typing plan: a character, or a backspace
+type TypedKey struct {
+	Char      rune
+	Backspace bool
+	Typo      bool // part of a mistake that a later backspace removes
+}
+
+// maxLateKeys is how many characters at most are typed past a typo before it is noticed
+const maxLateKeys = 3
+
+// qwertyNeighbors lists the keys around each letter and digit on a QWERTY keyboard
+var qwertyNeighbors = map[rune]string{
+	'1': "2q", '2': "13qw", '3': "24we", '4': "35er", '5': "46rt",
+	'6': "57ty", '7': "68yu", '8': "79ui", '9': "80io", '0': "9op",
+	'q': "12wa", 'w': "23qeas", 'e': "34wrsd", 'r': "45etdf", 't': "56ryfg",
+	'y': "67tugh", 'u': "78yihj", 'i': "89uojk", 'o': "90ipkl", 'p': "0ol",
+	'a': "qwsz", 's': "weadzx", 'd': "erfsxc", 'f': "rtgdcv", 'g': "tyhfvb",
+	'h': "yujgbn", 'j': "uikhnm", 'k': "iolj,m", 'l': "opk.",
+	'z': "asx", 'x': "sdzc", 'c': "dfxv", 'v': "fgcb", 'b': "ghvn",
+	'n': "hjbm", 'm': "jkn,",
+}
+
+// PlanTyping turns text into the keystrokes a person might make typing it: mostly
+// the text itself, with a typo before a character with probability typoProbability.
+// Every typo is corrected, right away or after a few more characters, so the plan
+// always produces text.
+func PlanTyping(text string, typoProbability float64, weights TypoWeights, rng *rand.Rand) []TypedKey {
+	chars := []rune(text)
+	plan := make([]TypedKey, 0, len(chars))
+
+	for i := 0; i < len(chars); {
+		var wrong []rune
+		consumed := 1
+		if i > 0 && rng.Float64() < typoProbability {
+			wrong, consumed = typo(chars, i, weights, rng)
+		}
+
+		if wrong == nil {
+			plan = append(plan, TypedKey{Char: chars[i]})
+			i++
+			continue
+		}
+
+		for _, c := range wrong {
+			plan = append(plan, TypedKey{Char: c, Typo: true})
+		}
+
+		// Sometimes the mistake only registers a few characters on
+		late := 0
+		if rng.Float64() < weights.LateCorrection {
+			late = 1 + rng.Intn(maxLateKeys)
+			for j := 0; j < late; j++ {
+				next := i + consumed + j
+				if next >= len(chars) || chars[next] == '\n' {
+					late = j
+					break
+				}
+				plan = append(plan, TypedKey{Char: chars[next], Typo: true})
+			}
+		}
+
+		for j := 0; j < len(wrong)+late; j++ {
+			plan = append(plan, TypedKey{Backspace: true})
+		}
+		for _, c := range chars[i : i+consumed+late] {
+			plan = append(plan, TypedKey{Char: c})
+		}
+		i += consumed + late
+	}
+
+	return plan
+}
+
+// typo picks a kind of typo for chars[i] and returns the wrong keys typed and how many
+// intended characters they stand for; wrong is nil when no typo fits the character
+func typo(chars []rune, i int, weights TypoWeights, rng *rand.Rand) ([]rune, int) {
+	c := chars[i]
+	if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+		return nil, 1
+	}
+
+	total := weights.Adjacent + weights.Doubled + weights.Transposed
+	if total <= 0 {
+		return nil, 1
+	}
+
+	switch n := rng.Float64() * total; {
+	case n < weights.Doubled:
+		return []rune{c, c}, 1
+	case n < weights.Doubled+weights.Transposed:
+		if i+1 < len(chars) && chars[i+1] != c && unicode.IsLetter(chars[i+1]) {
+			return []rune{chars[i+1], c}, 2
+		}
+	}
+
+	// Adjacent, and the fallback when a transposition doesn't fit
+	neighbors, ok := qwertyNeighbors[unicode.ToLower(c)]
+	if !ok {
+		return nil, 1
+	}
+	wrong := []rune(neighbors)[rng.Intn(len(neighbors))]
+	if unicode.IsUpper(c) {
+		wrong = unicode.ToUpper(wrong)
+	}
+	return []rune{wrong}, 1
+}
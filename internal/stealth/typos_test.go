@@ -0,0 +1,114 @@
+package stealth
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// replay applies a typing plan and returns the text it leaves
+func replay(plan []TypedKey) string {
+	var typed []rune
+	for _, key := range plan {
+		if key.Backspace {
+			typed = typed[:len(typed)-1]
+			continue
+		}
+		typed = append(typed, key.Char)
+	}
+	return string(typed)
+}
+
+func TestPlanTypingProducesTheText(t *testing.T) {
+	texts := []string{
+		"Hi Ada, I enjoyed your post on distributed systems.",
+		"Short",
+		"Zoë — naïve café owner\nLine two",
+		"aa bb 1234 ??",
+		"",
+	}
+	for seed := int64(0); seed < 100; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		for _, text := range texts {
+			if got := replay(PlanTyping(text, 0.3, DefaultTypoWeights, rng)); got != text {
+				t.Fatalf("seed %d: plan for %q types %q", seed, text, got)
+			}
+		}
+	}
+}
+
+func TestPlanTypingWithoutTypos(t *testing.T) {
+	plan := PlanTyping("Hello there", 0, DefaultTypoWeights, rand.New(rand.NewSource(1)))
+	if len(plan) != len("Hello there") {
+		t.Fatalf("plan has %d keys, want one per character", len(plan))
+	}
+	for _, key := range plan {
+		if key.Typo || key.Backspace {
+			t.Fatalf("plan has a mistake: %+v", key)
+		}
+	}
+}
+
+// firstTypo returns the wrong keys of the first mistake in a plan of text with a typo before every character
+func firstTypo(text string, weights TypoWeights, seed int64) string {
+	var wrong []rune
+	for _, key := range PlanTyping(text, 1, weights, rand.New(rand.NewSource(seed))) {
+		if !key.Typo {
+			if len(wrong) > 0 {
+				break
+			}
+			continue
+		}
+		wrong = append(wrong, key.Char)
+	}
+	return string(wrong)
+}
+
+func TestTypoKinds(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		weights TypoWeights
+		check   func(wrong string) bool
+	}{
+		{"adjacent", TypoWeights{Adjacent: 1}, func(wrong string) bool {
+			return len(wrong) == 1 && strings.ContainsRune(qwertyNeighbors['b'], rune(wrong[0]))
+		}},
+		{"doubled", TypoWeights{Doubled: 1}, func(wrong string) bool { return wrong == "bb" }},
+		{"transposed", TypoWeights{Transposed: 1}, func(wrong string) bool { return wrong == "cb" }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := int64(0); seed < 20; seed++ {
+				// The first character is never mistyped, so the typo lands on the "b"
+				if wrong := firstTypo("abc", tt.weights, seed); !tt.check(wrong) {
+					t.Fatalf("seed %d: typo %q", seed, wrong)
+				}
+			}
+		})
+	}
+}
+
+func TestTypoKeepsCase(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		wrong := firstTypo("aB", TypoWeights{Adjacent: 1}, seed)
+		if len(wrong) != 1 || !unicode.IsUpper(rune(wrong[0])) {
+			t.Fatalf("seed %d: typo for B is %q, want an uppercase neighbor", seed, wrong)
+		}
+	}
+}
+
+func TestLateCorrectionStopsAtLineEnd(t *testing.T) {
+	weights := TypoWeights{Doubled: 1, LateCorrection: 1}
+
+	for seed := int64(0); seed < 50; seed++ {
+		plan := PlanTyping("ab\ncd", 1, weights, rand.New(rand.NewSource(seed)))
+		if got := replay(plan); got != "ab\ncd" {
+			t.Fatalf("seed %d: plan types %q", seed, got)
+		}
+		for _, key := range plan {
+			if key.Typo && key.Char == '\n' {
+				t.Fatalf("seed %d: typed past the end of the line before correcting: %+v", seed, plan)
+			}
+		}
+	}
+}
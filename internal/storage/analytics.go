@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"time"
+)
+
+// GetAcceptanceRate returns the percentage of connection requests sent since the given time that were accepted
+func (db *DB) GetAcceptanceRate(since time.Time) (float64, error) {
+	var sent, accepted int
+	query := `SELECT COUNT(*), COALESCE(SUM(CASE WHEN status IN ('accepted', 'replied', 'removed') THEN 1 ELSE 0 END), 0)
+			  FROM connection_requests WHERE status NOT IN ('skipped', 'failed') AND sent_at >= ?`
+
+	if err := db.conn.QueryRow(query, since).Scan(&sent, &accepted); err != nil {
+		return 0, err
+	}
+
+	return Rate(accepted, sent), nil
+}
+
+// GetFunnelStats returns found → contacted → accepted → replied counts since the given time;
+// profiles only a monitor found aren't prospects and are left out
+func (db *DB) GetFunnelStats(since time.Time) (*FunnelStats, error) {
+	stats := &FunnelStats{}
+
+	err := db.conn.QueryRow(`SELECT COUNT(*) FROM search_results WHERE found_at >= ? AND monitor_only = 0`, since).Scan(&stats.Found)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT COUNT(*),
+				COALESCE(SUM(CASE WHEN status IN ('accepted', 'replied', 'removed') THEN 1 ELSE 0 END), 0),
+				COALESCE(SUM(CASE WHEN status = 'replied' THEN 1 ELSE 0 END), 0)
+			  FROM connection_requests WHERE status NOT IN ('skipped', 'failed') AND sent_at >= ?`
+
+	if err := db.conn.QueryRow(query, since).Scan(&stats.Contacted, &stats.Accepted, &stats.Replied); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// GetStatsByTemplate returns sent/accepted/replied counts per note template since the given time
+func (db *DB) GetStatsByTemplate(since time.Time) ([]TemplateStats, error) {
+	query := `SELECT COALESCE(template_id, ''), COUNT(*),
+				COALESCE(SUM(CASE WHEN status IN ('accepted', 'replied', 'removed') THEN 1 ELSE 0 END), 0),
+				COALESCE(SUM(CASE WHEN status = 'replied' THEN 1 ELSE 0 END), 0)
+			  FROM connection_requests WHERE status NOT IN ('skipped', 'failed') AND sent_at >= ?
+			  GROUP BY template_id ORDER BY COUNT(*) DESC`
+
+	rows, err := db.conn.Query(query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []TemplateStats
+	for rows.Next() {
+		var ts TemplateStats
+		if err := rows.Scan(&ts.TemplateID, &ts.Sent, &ts.Accepted, &ts.Replied); err != nil {
+			return nil, err
+		}
+		stats = append(stats, ts)
+	}
+
+	return stats, rows.Err()
+}
+
+// GetMessageStatsByTemplate returns sent and replied counts per message template since the given time.
+// A message counts as replied when its recipient's connection request is marked replied.
+func (db *DB) GetMessageStatsByTemplate(since time.Time) ([]TemplateStats, error) {
+	query := `SELECT COALESCE(m.template_id, ''), COUNT(*),
+				COALESCE(SUM(CASE WHEN c.status = 'replied' THEN 1 ELSE 0 END), 0)
+			  FROM messages m LEFT JOIN connection_requests c ON c.profile_url = m.profile_url
+			  WHERE m.sent_at >= ?
+			  GROUP BY m.template_id ORDER BY COUNT(*) DESC`
+
+	rows, err := db.conn.Query(query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []TemplateStats
+	for rows.Next() {
+		var ts TemplateStats
+		if err := rows.Scan(&ts.TemplateID, &ts.Sent, &ts.Replied); err != nil {
+			return nil, err
+		}
+		stats = append(stats, ts)
+	}
+
+	return stats, rows.Err()
+}
+
+// GetStatsByCampaign returns found/contacted/accepted/replied counts per campaign since the given time
+func (db *DB) GetStatsByCampaign(since time.Time) ([]CampaignStats, error) {
+	query := `SELECT campaign, SUM(found), SUM(contacted), SUM(accepted), SUM(replied) FROM (
+				SELECT COALESCE(campaign, 'default') AS campaign, 1 AS found, 0 AS contacted, 0 AS accepted, 0 AS replied
+				FROM search_results WHERE found_at >= ? AND monitor_only = 0
+				UNION ALL
+				SELECT COALESCE(campaign, 'default'), 0, 1,
+					CASE WHEN status IN ('accepted', 'replied', 'removed') THEN 1 ELSE 0 END,
+					CASE WHEN status = 'replied' THEN 1 ELSE 0 END
+				FROM connection_requests WHERE status NOT IN ('skipped', 'failed') AND sent_at >= ?
+			  ) GROUP BY campaign ORDER BY campaign`
+
+	rows, err := db.conn.Query(query, since, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []CampaignStats
+	for rows.Next() {
+		var cs CampaignStats
+		if err := rows.Scan(&cs.Campaign, &cs.Found, &cs.Contacted, &cs.Accepted, &cs.Replied); err != nil {
+			return nil, err
+		}
+		stats = append(stats, cs)
+	}
+
+	return stats, rows.Err()
+}
+
+// GetOutreachRecords returns every known prospect with its current status.
+// An empty status or campaign matches all records; limit <= 0 means no limit.
+// Incoming invites don't belong to a campaign and are left out when filtering by one.
+// Profiles only a monitor found aren't prospects and are left out.
+func (db *DB) GetOutreachRecords(status, campaign string, limit int) ([]OutreachRecord, error) {
+	query := `SELECT profile_url, profile_name, job_title, company, source, campaign, status, messaged FROM (
+				SELECT s.profile_url, COALESCE(s.profile_name, '') AS profile_name, COALESCE(s.job_title, '') AS job_title,
+					COALESCE(s.company, '') AS company, 'search' AS source, COALESCE(s.campaign, 'default') AS campaign,
+					COALESCE(c.status, 'found') AS status,
+					EXISTS(SELECT 1 FROM messages m WHERE m.profile_url = s.profile_url) AS messaged,
+					s.found_at AS seen_at
+				FROM search_results s LEFT JOIN connection_requests c ON c.normalized_url = s.normalized_url
+				WHERE s.monitor_only = 0
+				UNION ALL
+				SELECT i.profile_url, COALESCE(i.inviter_name, ''), COALESCE(i.headline, ''), '', 'incoming_invite', '',
+					i.decision,
+					EXISTS(SELECT 1 FROM messages m WHERE m.profile_url = i.profile_url),
+					i.decided_at
+				FROM incoming_invites i
+			  ) WHERE (? = '' OR status = ?) AND (? = '' OR campaign = ?) ORDER BY seen_at DESC`
+
+	args := []interface{}{status, status, campaign, campaign}
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []OutreachRecord
+	for rows.Next() {
+		var r OutreachRecord
+		if err := rows.Scan(&r.ProfileURL, &r.ProfileName, &r.JobTitle, &r.Company, &r.Source, &r.Campaign, &r.Status, &r.Messaged); err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+
+	return records, rows.Err()
+}
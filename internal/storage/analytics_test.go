@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// openMemoryDB opens a fresh in-memory database shared by the pool's connections
+func openMemoryDB(t *testing.T) *DB {
+	t.Helper()
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := NewDB(fmt.Sprintf("file:%s?mode=memory&cache=shared", name))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// seedOutreach stores prospects and the requests sent to them: one per status given,
+// with template and campaign, sent at sent
+func seedOutreach(t *testing.T, db *DB, campaign, template string, sent time.Time, statuses ...string) {
+	t.Helper()
+	for i, status := range statuses {
+		url := fmt.Sprintf("https://www.linkedin.com/in/%s-%s-%s-%d/", campaign, template, sent.Format("0102"), i)
+		if _, err := db.SaveSearchResult(&SearchResult{ProfileURL: url, ProfileName: "Ada Lovelace", Campaign: campaign, FoundAt: sent}); err != nil {
+			t.Fatalf("SaveSearchResult: %v", err)
+		}
+		err := db.SaveConnectionRequest(&ConnectionRequest{ProfileURL: url, ProfileName: "Ada Lovelace", Status: status,
+			TemplateID: template, Campaign: campaign, SentAt: sent, UpdatedAt: sent})
+		if err != nil {
+			t.Fatalf("SaveConnectionRequest: %v", err)
+		}
+	}
+}
+
+func TestAcceptanceRateAndFunnel(t *testing.T) {
+	db := openMemoryDB(t)
+	now := time.Now()
+	since := now.AddDate(0, 0, -30)
+
+	// Skipped and failed requests never reached anyone; the old one is outside the window
+	seedOutreach(t, db, "founders", "intro", now.Add(-time.Hour), "pending", "pending", "accepted", "replied", "removed", "skipped", "failed")
+	seedOutreach(t, db, "founders", "intro", now.AddDate(0, 0, -45), "accepted")
+
+	rate, err := db.GetAcceptanceRate(since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate != 60 {
+		t.Fatalf("acceptance rate = %.1f%%, want 60%% (3 of 5)", rate)
+	}
+
+	funnel, err := db.GetFunnelStats(since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (FunnelStats{Found: 7, Contacted: 5, Accepted: 3, Replied: 1}); *funnel != want {
+		t.Fatalf("funnel = %+v, want %+v", *funnel, want)
+	}
+
+	if rate, err := db.GetAcceptanceRate(now.Add(time.Hour)); err != nil || rate != 0 {
+		t.Fatalf("acceptance rate of an empty window = %.1f, %v; want 0", rate, err)
+	}
+}
+
+func TestStatsByTemplateAndCampaign(t *testing.T) {
+	db := openMemoryDB(t)
+	now := time.Now()
+	since := now.AddDate(0, 0, -7)
+
+	seedOutreach(t, db, "founders", "intro", now, "accepted", "replied", "pending", "pending")
+	seedOutreach(t, db, "founders", "short", now, "accepted")
+	seedOutreach(t, db, "hiring", "intro", now, "pending", "skipped")
+
+	templates, err := db.GetStatsByTemplate(since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantTemplates := []TemplateStats{
+		{TemplateID: "intro", Sent: 5, Accepted: 2, Replied: 1},
+		{TemplateID: "short", Sent: 1, Accepted: 1},
+	}
+	if fmt.Sprint(templates) != fmt.Sprint(wantTemplates) {
+		t.Fatalf("template stats = %+v, want %+v", templates, wantTemplates)
+	}
+	if got := Rate(templates[0].Accepted, templates[0].Sent); got != 40 {
+		t.Fatalf("intro acceptance = %.1f%%, want 40%%", got)
+	}
+
+	campaigns, err := db.GetStatsByCampaign(since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantCampaigns := []CampaignStats{
+		{Campaign: "founders", FunnelStats: FunnelStats{Found: 5, Contacted: 5, Accepted: 3, Replied: 1}},
+		{Campaign: "hiring", FunnelStats: FunnelStats{Found: 2, Contacted: 1}},
+	}
+	if fmt.Sprint(campaigns) != fmt.Sprint(wantCampaigns) {
+		t.Fatalf("campaign stats = %+v, want %+v", campaigns, wantCampaigns)
+	}
+}
+
+func TestRate(t *testing.T) {
+	for _, tt := range []struct {
+		part, whole int
+		want        float64
+	}{
+		{0, 0, 0},
+		{1, 4, 25},
+		{3, 3, 100},
+	} {
+		if got := Rate(tt.part, tt.whole); got != tt.want {
+			t.Errorf("Rate(%d, %d) = %v, want %v", tt.part, tt.whole, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Audit results
+const (
+	AuditSuccess = "success"
+	AuditFailed  = "failed"
+	AuditSkipped = "skipped"
+	AuditTimeout = "timeout" // abandoned once its time budget ran out
+)
+
+// AuditEntry is one action the bot took, as the activity log records it
+type AuditEntry struct {
+	Action     string
+	ProfileURL string // the profile acted on; empty for actions on no one profile
+	Details    string
+	Result     string // one of the Audit results
+	Err        error  // why the action failed
+	PageURL    string // where the page was when the action ended
+	Started    time.Time
+}
+
+// Audit logs an action with its profile, result and timing to the activity log
+func (db *DB) Audit(entry AuditEntry) error {
+	now := time.Now()
+
+	var duration interface{}
+	if !entry.Started.IsZero() {
+		duration = now.Sub(entry.Started).Milliseconds()
+	}
+	var errText string
+	if entry.Err != nil {
+		errText = entry.Err.Error()
+	}
+
+	query := `INSERT INTO activity_logs (action, details, run_id, timestamp, profile_url, normalized_url, duration_ms, result, error, page_url)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := db.conn.Exec(query, entry.Action, entry.Details, db.runID, now, entry.ProfileURL, normalizedURL(entry.ProfileURL),
+		duration, entry.Result, errText, entry.PageURL)
+	if err != nil {
+		return fmt.Errorf("failed to audit %s: %w", entry.Action, err)
+	}
+	return nil
+}
+
+// ProfileHistory returns everything logged about a profile, oldest first. Activity from
+// before the audit columns is matched by the profile's URL in its details.
+func (db *DB) ProfileHistory(profileURL string) ([]ActivityLog, error) {
+	// URLs typed without a scheme, such as linkedin.com/in/jane-doe
+	if !strings.Contains(profileURL, "://") && !strings.HasPrefix(profileURL, "/") {
+		profileURL = "https://" + profileURL
+	}
+	normalized := normalizedURL(profileURL)
+	if !strings.HasPrefix(normalized.String, "https://www.linkedin.com/in/") {
+		return nil, fmt.Errorf("%q is not a profile URL", profileURL)
+	}
+
+	// The path alone, followed by nothing, a slash, a space or a closing bracket, so that
+	// /in/jane doesn't also match /in/jane-doe
+	path := "%" + strings.TrimPrefix(normalized.String, "https://www.linkedin.com")
+	query := activityColumns + ` WHERE normalized_url = ?
+			  OR (normalized_url IS NULL AND (details LIKE ? OR details LIKE ? OR details LIKE ? OR details LIKE ?))
+			  ORDER BY timestamp, id`
+	return db.queryActivities(query, normalized, path, path+"/%", path+" %", path+")%")
+}
+
+// activityColumns selects an ActivityLog
+const activityColumns = `SELECT id, action, COALESCE(details, ''), COALESCE(run_id, ''), timestamp, COALESCE(profile_url, ''),
+			  duration_ms, COALESCE(result, ''), COALESCE(error, ''), COALESCE(page_url, '') FROM activity_logs`
+
+// queryActivities runs a query selecting activityColumns
+func (db *DB) queryActivities(query string, args ...interface{}) ([]ActivityLog, error) {
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query activity: %w", err)
+	}
+	defer rows.Close()
+
+	var activities []ActivityLog
+	for rows.Next() {
+		var a ActivityLog
+		var durationMs *int64
+		if err := rows.Scan(&a.ID, &a.Action, &a.Details, &a.RunID, &a.Timestamp, &a.ProfileURL,
+			&durationMs, &a.Result, &a.Error, &a.PageURL); err != nil {
+			return nil, fmt.Errorf("failed to scan activity: %w", err)
+		}
+		if durationMs != nil {
+			a.Duration = time.Duration(*durationMs) * time.Millisecond
+		}
+		activities = append(activities, a)
+	}
+
+	return activities, rows.Err()
+}
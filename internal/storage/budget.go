@@ -0,0 +1,51 @@
+package storage
+
+import "fmt"
+
+// ActionBudgetUsed returns how much of date's action budget (YYYY-MM-DD) each action type
+// has used
+func (db *DB) ActionBudgetUsed(date string) (map[string]int, error) {
+	rows, err := db.conn.Query(`SELECT action, used FROM action_budget WHERE date = ?`, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get action budget: %w", err)
+	}
+	defer rows.Close()
+
+	used := make(map[string]int)
+	for rows.Next() {
+		var action string
+		var n int
+		if err := rows.Scan(&action, &n); err != nil {
+			return nil, fmt.Errorf("failed to read action budget: %w", err)
+		}
+		used[action] = n
+	}
+	return used, rows.Err()
+}
+
+// TakeActionBudget spends one action of date's budget on action in a single statement, so
+// a run that restarts, or another one alongside it, can't spend it twice. It spends nothing
+// and reports false once action has used limit or the day has used total.
+func (db *DB) TakeActionBudget(date, action string, limit, total int) (bool, error) {
+	query := `INSERT INTO action_budget (date, action, used)
+			  SELECT ?, ?, 1 WHERE ? > 0 AND (SELECT COALESCE(SUM(used), 0) FROM action_budget WHERE date = ?) < ?
+			  ON CONFLICT(date, action) DO UPDATE SET used = used + 1 WHERE used < ?`
+	res, err := db.conn.Exec(query, date, action, limit, date, total, limit)
+	if err != nil {
+		return false, fmt.Errorf("failed to take action budget: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to take action budget: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// RefundActionBudget gives back one action of date's budget spent on action
+func (db *DB) RefundActionBudget(date, action string) error {
+	query := `UPDATE action_budget SET used = used - 1 WHERE date = ? AND action = ? AND used > 0`
+	if _, err := db.conn.Exec(query, date, action); err != nil {
+		return fmt.Errorf("failed to refund action budget: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrUnknownProfile is returned when curating a profile that no search has found
+var ErrUnknownProfile = errors.New("profile not found in search results")
+
+// CuratedProfile is a found profile with the tags and note it was given by hand
+type CuratedProfile struct {
+	ProfileURL  string
+	ProfileName string
+	Campaign    string
+	Tags        []string
+	Note        string
+}
+
+// NormalizeTag returns a tag as it is stored: trimmed and lower-case, so "Hot" and "hot" are one tag
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// AddProfileTag tags a found profile, reporting false when it already had the tag
+func (db *DB) AddProfileTag(profileURL, tag string) (bool, error) {
+	tag = NormalizeTag(tag)
+	if tag == "" || strings.Contains(tag, ",") {
+		return false, fmt.Errorf("invalid tag %q: tags are non-empty and have no commas", tag)
+	}
+	if err := db.requireSearchResult(profileURL); err != nil {
+		return false, err
+	}
+
+	res, err := db.conn.Exec(`INSERT INTO profile_tags (normalized_url, profile_url, tag, created_at) VALUES (?, ?, ?, ?)
+			  ON CONFLICT(normalized_url, tag) DO NOTHING`, normalizedURL(profileURL), profileURL, tag, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("failed to tag profile: %w", err)
+	}
+	added, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to tag profile: %w", err)
+	}
+	return added > 0, nil
+}
+
+// RemoveProfileTag removes a tag from a profile, reporting false when it didn't have it
+func (db *DB) RemoveProfileTag(profileURL, tag string) (bool, error) {
+	res, err := db.conn.Exec(`DELETE FROM profile_tags WHERE normalized_url = ? AND tag = ?`, normalizedURL(profileURL), NormalizeTag(tag))
+	if err != nil {
+		return false, fmt.Errorf("failed to untag profile: %w", err)
+	}
+	removed, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to untag profile: %w", err)
+	}
+	return removed > 0, nil
+}
+
+// ProfileTags returns a profile's tags in alphabetical order
+func (db *DB) ProfileTags(profileURL string) ([]string, error) {
+	rows, err := db.conn.Query(`SELECT tag FROM profile_tags WHERE normalized_url = ? ORDER BY tag`, normalizedURL(profileURL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("failed to read profile tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// SetCurationNote sets the note on a found profile that templates can reference as
+// {{.CurationNote}}; an empty note clears it
+func (db *DB) SetCurationNote(profileURL, note string) error {
+	res, err := db.conn.Exec(`UPDATE search_results SET curation_note = ? WHERE normalized_url = ?`, strings.TrimSpace(note), normalizedURL(profileURL))
+	if err != nil {
+		return fmt.Errorf("failed to set curation note: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%w: %s", ErrUnknownProfile, profileURL)
+	}
+	return nil
+}
+
+// GetCurationNote returns the note on a profile, empty when it has none or wasn't found
+func (db *DB) GetCurationNote(profileURL string) (string, error) {
+	var note string
+	err := db.conn.QueryRow(`SELECT curation_note FROM search_results WHERE normalized_url = ? AND curation_note != '' ORDER BY id DESC LIMIT 1`,
+		normalizedURL(profileURL)).Scan(&note)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get curation note: %w", err)
+	}
+	return note, nil
+}
+
+// ListCuratedProfiles returns the found profiles that have tag, or every profile with a tag
+// or note when tag is empty, most recently found first
+func (db *DB) ListCuratedProfiles(tag string) ([]CuratedProfile, error) {
+	where := "s.curation_note != '' OR EXISTS (SELECT 1 FROM profile_tags t WHERE t.normalized_url = s.normalized_url)"
+	args := []interface{}{}
+	if tag != "" {
+		where = "EXISTS (SELECT 1 FROM profile_tags t WHERE t.normalized_url = s.normalized_url AND t.tag = ?)"
+		args = append(args, NormalizeTag(tag))
+	}
+
+	// A profile found by several searches is listed once, as most recently found
+	query := fmt.Sprintf(`SELECT s.profile_url, s.profile_name, s.campaign, s.curation_note,
+			  COALESCE((SELECT GROUP_CONCAT(tag, ',') FROM (SELECT tag FROM profile_tags t WHERE t.normalized_url = s.normalized_url ORDER BY tag)), '')
+			  FROM search_results s
+			  WHERE s.id IN (SELECT MAX(id) FROM search_results GROUP BY normalized_url) AND (%s)
+			  ORDER BY s.id DESC`, where)
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list curated profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var profiles []CuratedProfile
+	for rows.Next() {
+		var p CuratedProfile
+		var tags string
+		if err := rows.Scan(&p.ProfileURL, &p.ProfileName, &p.Campaign, &p.Note, &tags); err != nil {
+			return nil, fmt.Errorf("failed to read curated profile: %w", err)
+		}
+		p.Tags = []string{}
+		if tags != "" {
+			p.Tags = strings.Split(tags, ",")
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles, rows.Err()
+}
+
+// requireSearchResult returns ErrUnknownProfile unless a search found the profile
+func (db *DB) requireSearchResult(profileURL string) error {
+	var found bool
+	err := db.conn.QueryRow(`SELECT EXISTS (SELECT 1 FROM search_results WHERE normalized_url = ?)`, normalizedURL(profileURL)).Scan(&found)
+	if err != nil {
+		return fmt.Errorf("failed to look up profile: %w", err)
+	}
+	if !found {
+		return fmt.Errorf("%w: %s", ErrUnknownProfile, profileURL)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// seedFound stores search results for the given profile names, in order
+func seedFound(t *testing.T, db *DB, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		url := "https://www.linkedin.com/in/" + name + "/"
+		if _, err := db.SaveSearchResults([]*SearchResult{{ProfileURL: url, ProfileName: name, Campaign: "founders", FoundAt: time.Now()}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestProfileTags(t *testing.T) {
+	db := openTestDB(t)
+	seedFound(t, db, "ada")
+	ada := "https://www.linkedin.com/in/ada/"
+
+	if _, err := db.AddProfileTag("https://www.linkedin.com/in/nobody/", "hot"); !errors.Is(err, ErrUnknownProfile) {
+		t.Fatalf("tagging an unknown profile: %v, want ErrUnknownProfile", err)
+	}
+	for _, tag := range []string{"", "  ", "hot,warm"} {
+		if _, err := db.AddProfileTag(ada, tag); err == nil {
+			t.Fatalf("tag %q accepted", tag)
+		}
+	}
+
+	for _, tt := range []struct {
+		tag   string
+		added bool
+	}{
+		{" Hot ", true},
+		{"hot", false},
+		{"investor", true},
+	} {
+		added, err := db.AddProfileTag(ada, tt.tag)
+		if err != nil {
+			t.Fatalf("AddProfileTag(%q): %v", tt.tag, err)
+		}
+		if added != tt.added {
+			t.Fatalf("AddProfileTag(%q) added %v, want %v", tt.tag, added, tt.added)
+		}
+	}
+
+	// The URL is matched however it is written
+	tags, err := db.ProfileTags("https://linkedin.com/in/ada")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(tags, ",") != "hot,investor" {
+		t.Fatalf("tags = %v", tags)
+	}
+
+	if removed, err := db.RemoveProfileTag(ada, "HOT"); err != nil || !removed {
+		t.Fatalf("RemoveProfileTag: removed %v, err %v", removed, err)
+	}
+	if removed, err := db.RemoveProfileTag(ada, "hot"); err != nil || removed {
+		t.Fatalf("removing a missing tag: removed %v, err %v", removed, err)
+	}
+	if tags, _ := db.ProfileTags(ada); strings.Join(tags, ",") != "investor" {
+		t.Fatalf("tags after removal = %v", tags)
+	}
+}
+
+func TestCurationNote(t *testing.T) {
+	db := openTestDB(t)
+	seedFound(t, db, "ada")
+	ada := "https://www.linkedin.com/in/ada/"
+
+	if err := db.SetCurationNote("https://www.linkedin.com/in/nobody/", "met at PyCon"); !errors.Is(err, ErrUnknownProfile) {
+		t.Fatalf("noting an unknown profile: %v, want ErrUnknownProfile", err)
+	}
+
+	if err := db.SetCurationNote(ada, "  met at PyCon  "); err != nil {
+		t.Fatalf("SetCurationNote: %v", err)
+	}
+	if note, err := db.GetCurationNote(ada); err != nil || note != "met at PyCon" {
+		t.Fatalf("note = %q, err %v", note, err)
+	}
+
+	if err := db.SetCurationNote(ada, ""); err != nil {
+		t.Fatal(err)
+	}
+	if note, err := db.GetCurationNote(ada); err != nil || note != "" {
+		t.Fatalf("cleared note = %q, err %v", note, err)
+	}
+	if note, err := db.GetCurationNote("https://www.linkedin.com/in/nobody/"); err != nil || note != "" {
+		t.Fatalf("unknown profile's note = %q, err %v", note, err)
+	}
+}
+
+func TestListCuratedProfiles(t *testing.T) {
+	db := openTestDB(t)
+	seedFound(t, db, "ada", "grace", "charles")
+
+	db.AddProfileTag("https://www.linkedin.com/in/ada/", "hot")
+	db.AddProfileTag("https://www.linkedin.com/in/ada/", "investor")
+	db.AddProfileTag("https://www.linkedin.com/in/charles/", "investor")
+	db.SetCurationNote("https://www.linkedin.com/in/grace/", "asked for a demo")
+
+	for _, tt := range []struct {
+		tag  string
+		want string
+	}{
+		{"", "charles[investor], grace[] asked for a demo, ada[hot investor]"},
+		{"Investor", "charles[investor], ada[hot investor]"},
+		{"cold", ""},
+	} {
+		profiles, err := db.ListCuratedProfiles(tt.tag)
+		if err != nil {
+			t.Fatalf("ListCuratedProfiles(%q): %v", tt.tag, err)
+		}
+		var got []string
+		for _, p := range profiles {
+			got = append(got, strings.TrimSpace(p.ProfileName+"["+strings.Join(p.Tags, " ")+"] "+p.Note))
+		}
+		if strings.Join(got, ", ") != tt.want {
+			t.Errorf("ListCuratedProfiles(%q) = %s, want %s", tt.tag, strings.Join(got, ", "), tt.want)
+		}
+	}
+}
+
+func TestUncontactedProfilesBoostTags(t *testing.T) {
+	db := openTestDB(t)
+	seedFound(t, db, "ada", "grace", "charles")
+	db.AddProfileTag("https://www.linkedin.com/in/grace/", "hot")
+
+	for _, tt := range []struct {
+		name   string
+		policy ProspectPolicy
+		want   string
+	}{
+		{"no boost", ProspectPolicy{Order: ProspectOrderOldest}, "ada,grace,charles"},
+		{"tagged first", ProspectPolicy{Order: ProspectOrderOldest, BoostTags: []string{"cold", "HOT"}}, "grace,ada,charles"},
+		{"newest with the boost", ProspectPolicy{BoostTags: []string{"hot"}}, "grace,charles,ada"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			profiles, err := db.GetUncontactedProfiles("founders", 10, tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, p := range profiles {
+				names = append(names, p.ProfileName)
+			}
+			if got := strings.Join(names, ","); got != tt.want {
+				t.Fatalf("order = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,19 +3,38 @@ package storage
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	_ "modernc.org/sqlite"
 )
 
+// busyTimeout is how long a write waits for another connection's write to finish
+// before failing with "database is locked"
+const busyTimeout = 10 * time.Second
+
 // DB represents the database connection
 type DB struct {
-	conn *sql.DB
+	conn  querier // the database, or the transaction within WithTx
+	sqlDB *sql.DB
+	inTx  bool
+	runID string // stamped on activity rows; empty outside a run
+
+	reinviteCooldownDays int // 0 never invites a profile twice
+
+	migrated []string // migrations applied when the database was opened
+}
+
+// querier is satisfied by both *sql.DB and *sql.Tx
+type querier interface {
+	execer
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
 }
 
 // NewDB creates a new database connection
 func NewDB(dbPath string) (*DB, error) {
-	conn, err := sql.Open("sqlite", dbPath)
+	conn, err := sql.Open("sqlite", dsn(dbPath))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -25,7 +44,7 @@ func NewDB(dbPath string) (*DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	db := &DB{conn: conn}
+	db := &DB{conn: conn, sqlDB: conn}
 
 	// Run migrations
 	if err := db.migrate(); err != nil {
@@ -35,86 +54,179 @@ func NewDB(dbPath string) (*DB, error) {
 	return db, nil
 }
 
+// dsn adds the connection settings every pooled connection needs to the database
+// path. WAL lets readers run alongside a writer, the busy timeout makes concurrent
+// writers queue instead of failing, and transactions take the write lock when they
+// begin, since upgrading a read transaction can't wait for a busy database.
+func dsn(dbPath string) string {
+	sep := "?"
+	if strings.Contains(dbPath, "?") {
+		sep = "&"
+	}
+	return fmt.Sprintf("%s%s_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)&_txlock=immediate",
+		dbPath, sep, busyTimeout.Milliseconds())
+}
+
+// SetRunID stamps the activity logged from now on with the run that logged it
+func (db *DB) SetRunID(runID string) {
+	db.runID = runID
+}
+
 // Close closes the database connection
 func (db *DB) Close() error {
-	return db.conn.Close()
-}
-
-// migrate runs database migrations
-func (db *DB) migrate() error {
-	migrations := []string{
-		`CREATE TABLE IF NOT EXISTS connection_requests (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			profile_url TEXT NOT NULL UNIQUE,
-			profile_name TEXT,
-			job_title TEXT,
-			company TEXT,
-			note TEXT,
-			status TEXT DEFAULT 'pending',
-			sent_at DATETIME NOT NULL,
-			updated_at DATETIME NOT NULL
-		)`,
-		`CREATE TABLE IF NOT EXISTS messages (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			profile_url TEXT NOT NULL,
-			profile_name TEXT,
-			content TEXT NOT NULL,
-			sent_at DATETIME NOT NULL
-		)`,
-		`CREATE TABLE IF NOT EXISTS search_results (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			profile_url TEXT NOT NULL UNIQUE,
-			profile_name TEXT,
-			job_title TEXT,
-			company TEXT,
-			location TEXT,
-			found_at DATETIME NOT NULL,
-			contacted BOOLEAN DEFAULT 0
-		)`,
-		`CREATE TABLE IF NOT EXISTS activity_logs (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			action TEXT NOT NULL,
-			details TEXT,
-			timestamp DATETIME NOT NULL
-		)`,
-		`CREATE INDEX IF NOT EXISTS idx_connection_requests_status ON connection_requests(status)`,
-		`CREATE INDEX IF NOT EXISTS idx_connection_requests_sent_at ON connection_requests(sent_at)`,
-		`CREATE INDEX IF NOT EXISTS idx_messages_sent_at ON messages(sent_at)`,
-		`CREATE INDEX IF NOT EXISTS idx_search_results_contacted ON search_results(contacted)`,
-	}
-
-	for _, migration := range migrations {
-		if _, err := db.conn.Exec(migration); err != nil {
-			return fmt.Errorf("migration failed: %w", err)
-		}
+	return db.sqlDB.Close()
+}
+
+// WithTx runs fn in a single transaction, committing when fn returns nil and rolling
+// back otherwise. fn must make its calls on the DB it is given, which runs every method
+// in the transaction; a WithTx within fn joins the outer transaction.
+func (db *DB) WithTx(fn func(tx *DB) error) error {
+	if db.inTx {
+		return fn(db)
+	}
+
+	tx, err := db.sqlDB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(&DB{conn: tx, sqlDB: db.sqlDB, inTx: true, runID: db.runID, reinviteCooldownDays: db.reinviteCooldownDays}); err != nil {
+		tx.Rollback()
+		return err
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return nil
 }
 
-// SaveConnectionRequest saves a connection request to the database
-func (db *DB) SaveConnectionRequest(req *ConnectionRequest) error {
-	query := `INSERT INTO connection_requests (profile_url, profile_name, job_title, company, note, status, sent_at, updated_at)
-			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
-
-	result, err := db.conn.Exec(query, req.ProfileURL, req.ProfileName, req.JobTitle, req.Company, req.Note, req.Status, req.SentAt, req.UpdatedAt)
+// addColumnIfMissing adds a column to an existing table unless it is already present
+func (db *DB) addColumnIfMissing(table, column, definition string) error {
+	rows, err := db.conn.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
 	if err != nil {
-		return fmt.Errorf("failed to save connection request: %w", err)
+		return err
 	}
+	defer rows.Close()
 
-	id, err := result.LastInsertId()
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			colType    string
+			notNull    int
+			defaultVal sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultVal, &primaryKey); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = db.conn.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
+// MaxRequestAttempts is how many times a profile is tried before a failed request counts as contacted
+const MaxRequestAttempts = 3
+
+// SaveConnectionRequest saves a connection request to the database. A request that
+// failed earlier for the same profile is replaced and its attempt counted, as is one
+// withdrawn or left pending past the re-invite cooldown. Without a member ID of its own,
+// the request takes the one stored with the search result.
+func (db *DB) SaveConnectionRequest(req *ConnectionRequest) error {
+	query := `INSERT INTO connection_requests (profile_url, normalized_url, member_id, profile_name, job_title, company, note, note_used, status, name_resolution, template_id, flow_variant, note_status, campaign, sent_at, updated_at)
+			  VALUES (?, ?, COALESCE(?, (SELECT member_id FROM search_results WHERE normalized_url = ?)), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			  ON CONFLICT(normalized_url) DO UPDATE SET
+				member_id = COALESCE(excluded.member_id, connection_requests.member_id), profile_name = excluded.profile_name, job_title = excluded.job_title, company = excluded.company,
+				note = excluded.note, note_used = excluded.note_used, status = excluded.status, name_resolution = excluded.name_resolution,
+				template_id = excluded.template_id, flow_variant = excluded.flow_variant, note_status = excluded.note_status,
+				campaign = excluded.campaign, sent_at = excluded.sent_at, updated_at = excluded.updated_at,
+				failure_reason = '', attempts = connection_requests.attempts + 1
+			  WHERE connection_requests.status = 'failed'
+				OR (? AND connection_requests.status IN ` + reinviteStatuses + ` AND connection_requests.updated_at <= ?)
+			  RETURNING id`
+
+	normalized := normalizedURL(req.ProfileURL)
+	reinvite, cutoff := db.reinviteCutoff()
+	err := db.conn.QueryRow(query, req.ProfileURL, normalized, nullMemberID(req.MemberID), normalized, req.ProfileName, req.JobTitle, req.Company, req.Note, req.NoteUsed, req.Status, req.NameResolution, req.TemplateID, req.FlowVariant, req.NoteStatus, req.Campaign, req.SentAt, req.UpdatedAt,
+		reinvite, cutoff).Scan(&req.ID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("failed to save connection request: %s is already recorded", req.ProfileURL)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
+		return fmt.Errorf("failed to save connection request: %w", err)
 	}
 
-	req.ID = id
 	return nil
 }
 
+// SetConnectionRequestFailed marks a request LinkedIn didn't confirm as failed, keeping the reason
+func (db *DB) SetConnectionRequestFailed(id int64, reason string) error {
+	query := `UPDATE connection_requests SET status = 'failed', failure_reason = ?, updated_at = ? WHERE id = ?`
+	_, err := db.conn.Exec(query, reason, time.Now(), id)
+	return err
+}
+
+// HasNoteStatusSince reports whether any connection request since t was recorded with the given note status
+func (db *DB) HasNoteStatusSince(status string, t time.Time) (bool, error) {
+	var count int
+	err := db.conn.QueryRow(`SELECT COUNT(*) FROM connection_requests WHERE note_status = ? AND sent_at >= ?`, status, t).Scan(&count)
+	return count > 0, err
+}
+
+// GetNotesCountSince returns how many connection requests since t went out with a note;
+// failed requests, which LinkedIn never confirmed, don't count
+func (db *DB) GetNotesCountSince(t time.Time) (int, error) {
+	var count int
+	err := db.conn.QueryRow(`SELECT COUNT(*) FROM connection_requests WHERE note_used = 1 AND status != 'failed' AND sent_at >= ?`, t).Scan(&count)
+	return count, err
+}
+
 // UpdateConnectionStatus updates the status of a connection request
 func (db *DB) UpdateConnectionStatus(profileURL, status string) error {
-	query := `UPDATE connection_requests SET status = ?, updated_at = ? WHERE profile_url = ?`
-	_, err := db.conn.Exec(query, status, time.Now(), profileURL)
+	query := `UPDATE connection_requests SET status = ?, updated_at = ? WHERE normalized_url = ?`
+	_, err := db.conn.Exec(query, status, time.Now(), normalizedURL(profileURL))
+	return err
+}
+
+// AcceptPendingRequest marks a profile's pending request accepted, reporting false
+// when the profile has no pending request
+func (db *DB) AcceptPendingRequest(profileURL string) (bool, error) {
+	res, err := db.conn.Exec(`UPDATE connection_requests SET status = 'accepted', updated_at = ? WHERE normalized_url = ? AND status = 'pending'`,
+		time.Now(), normalizedURL(profileURL))
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// HasConnectionRequest reports whether a request to the profile is recorded; failed
+// requests don't count, they are retried
+func (db *DB) HasConnectionRequest(profileURL string) (bool, error) {
+	var count int
+	err := db.conn.QueryRow(`SELECT COUNT(*) FROM connection_requests WHERE normalized_url = ? AND status != 'failed'`,
+		normalizedURL(profileURL)).Scan(&count)
+	return count > 0, err
+}
+
+// SetConnectionRequestStatus updates the status of a single connection request row
+func (db *DB) SetConnectionRequestStatus(id int64, status string) error {
+	query := `UPDATE connection_requests SET status = ?, updated_at = ? WHERE id = ?`
+	_, err := db.conn.Exec(query, status, time.Now(), id)
+	return err
+}
+
+// DeleteConnectionRequest removes a connection request row, e.g. a write-ahead row for a request that was never sent
+func (db *DB) DeleteConnectionRequest(id int64) error {
+	_, err := db.conn.Exec(`DELETE FROM connection_requests WHERE id = ?`, id)
 	return err
 }
 
@@ -123,7 +235,7 @@ func (db *DB) GetConnectionRequestsByDate(date time.Time) ([]ConnectionRequest,
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 	endOfDay := startOfDay.Add(24 * time.Hour)
 
-	query := `SELECT id, profile_url, profile_name, job_title, company, note, status, sent_at, updated_at
+	query := `SELECT id, profile_url, profile_name, job_title, company, note, note_used, status, name_resolution, template_id, flow_variant, note_status, campaign, sent_at, updated_at
 			  FROM connection_requests WHERE sent_at >= ? AND sent_at < ?`
 
 	rows, err := db.conn.Query(query, startOfDay, endOfDay)
@@ -135,7 +247,7 @@ func (db *DB) GetConnectionRequestsByDate(date time.Time) ([]ConnectionRequest,
 	var requests []ConnectionRequest
 	for rows.Next() {
 		var req ConnectionRequest
-		if err := rows.Scan(&req.ID, &req.ProfileURL, &req.ProfileName, &req.JobTitle, &req.Company, &req.Note, &req.Status, &req.SentAt, &req.UpdatedAt); err != nil {
+		if err := rows.Scan(&req.ID, &req.ProfileURL, &req.ProfileName, &req.JobTitle, &req.Company, &req.Note, &req.NoteUsed, &req.Status, &req.NameResolution, &req.TemplateID, &req.FlowVariant, &req.NoteStatus, &req.Campaign, &req.SentAt, &req.UpdatedAt); err != nil {
 			return nil, err
 		}
 		requests = append(requests, req)
@@ -144,33 +256,134 @@ func (db *DB) GetConnectionRequestsByDate(date time.Time) ([]ConnectionRequest,
 	return requests, nil
 }
 
-// GetConnectionRequestsCountByDate returns the count of connection requests sent on a specific date
+// GetConnectionRequestsCountByDate returns the count of connection requests sent on a specific date.
+// Skipped profiles are recorded in the same table but do not count towards the limit.
 func (db *DB) GetConnectionRequestsCountByDate(date time.Time) (int, error) {
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 	endOfDay := startOfDay.Add(24 * time.Hour)
 
-	query := `SELECT COUNT(*) FROM connection_requests WHERE sent_at >= ? AND sent_at < ?`
+	query := `SELECT COUNT(*) FROM connection_requests WHERE status NOT IN ('skipped', 'failed') AND sent_at >= ? AND sent_at < ?`
 
 	var count int
 	err := db.conn.QueryRow(query, startOfDay, endOfDay).Scan(&count)
 	return count, err
 }
 
-// IsProfileContacted checks if a profile has already been contacted
-func (db *DB) IsProfileContacted(profileURL string) (bool, error) {
-	query := `SELECT COUNT(*) FROM connection_requests WHERE profile_url = ?`
+// GetConnectionRequestsCountSince returns the count of connection requests sent since t, skipped and failed requests excluded
+func (db *DB) GetConnectionRequestsCountSince(t time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM connection_requests WHERE status NOT IN ('skipped', 'failed') AND sent_at >= ?`
+
+	var count int
+	err := db.conn.QueryRow(query, t).Scan(&count)
+	return count, err
+}
+
+// GetCampaignRequestsCountByDate returns the count of connection requests a campaign sent on a specific date
+func (db *DB) GetCampaignRequestsCountByDate(campaign string, date time.Time) (int, error) {
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	query := `SELECT COUNT(*) FROM connection_requests WHERE campaign = ? AND status NOT IN ('skipped', 'failed') AND sent_at >= ? AND sent_at < ?`
+
+	var count int
+	err := db.conn.QueryRow(query, campaign, startOfDay, endOfDay).Scan(&count)
+	return count, err
+}
+
+// GetConnectionRequestsByStatus returns connection requests with the given status, oldest first
+func (db *DB) GetConnectionRequestsByStatus(status string) ([]ConnectionRequest, error) {
+	query := `SELECT id, profile_url, profile_name, job_title, company, note, note_used, status, name_resolution, template_id, flow_variant, note_status, campaign, sent_at, updated_at
+			  FROM connection_requests WHERE status = ? ORDER BY sent_at`
+
+	rows, err := db.conn.Query(query, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []ConnectionRequest
+	for rows.Next() {
+		var req ConnectionRequest
+		if err := rows.Scan(&req.ID, &req.ProfileURL, &req.ProfileName, &req.JobTitle, &req.Company, &req.Note, &req.NoteUsed, &req.Status, &req.NameResolution, &req.TemplateID, &req.FlowVariant, &req.NoteStatus, &req.Campaign, &req.SentAt, &req.UpdatedAt); err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+
+	return requests, rows.Err()
+}
+
+// GetStaleConnections returns up to limit connections accepted before t that never
+// replied, the longest-standing first
+func (db *DB) GetStaleConnections(before time.Time, limit int) ([]ConnectionRequest, error) {
+	query := `SELECT id, profile_url, profile_name, job_title, company, note, note_used, status, name_resolution, template_id, flow_variant, note_status, campaign, sent_at, updated_at
+			  FROM connection_requests WHERE status = 'accepted' AND updated_at < ? ORDER BY updated_at LIMIT ?`
+
+	rows, err := db.conn.Query(query, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []ConnectionRequest
+	for rows.Next() {
+		var req ConnectionRequest
+		if err := rows.Scan(&req.ID, &req.ProfileURL, &req.ProfileName, &req.JobTitle, &req.Company, &req.Note, &req.NoteUsed, &req.Status, &req.NameResolution, &req.TemplateID, &req.FlowVariant, &req.NoteStatus, &req.Campaign, &req.SentAt, &req.UpdatedAt); err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+
+	return requests, rows.Err()
+}
+
+// IsProfileContacted checks if a profile has already been contacted, under this URL or
+// under another one of the same member: memberID, when known, or any member ID stored
+// for the URL. A failed request leaves the profile open for another attempt until
+// MaxRequestAttempts is reached, and one withdrawn or left pending leaves it open once
+// the re-invite cooldown has passed.
+func (db *DB) IsProfileContacted(profileURL, memberID string) (bool, error) {
+	query := `SELECT COUNT(*) FROM connection_requests
+			  WHERE (normalized_url = ? OR member_id = ? OR member_id IN (
+				SELECT member_id FROM search_results WHERE normalized_url = ? AND member_id IS NOT NULL
+				UNION SELECT member_id FROM connection_requests WHERE normalized_url = ? AND member_id IS NOT NULL))
+			  AND (status != 'failed' OR attempts >= ?)
+			  AND NOT (? AND status IN ` + reinviteStatuses + ` AND updated_at <= ?)`
 
+	normalized := normalizedURL(profileURL)
+	reinvite, cutoff := db.reinviteCutoff()
 	var count int
-	err := db.conn.QueryRow(query, profileURL).Scan(&count)
+	err := db.conn.QueryRow(query, normalized, nullMemberID(memberID), normalized, normalized, MaxRequestAttempts, reinvite, cutoff).Scan(&count)
 	return count > 0, err
 }
 
+// SetProfileMemberID records the member ID read from a profile on the rows stored for its
+// URL, so rows saved before it was known are recognized under the member's other URLs
+func (db *DB) SetProfileMemberID(profileURL, id string) error {
+	if id == "" {
+		return nil
+	}
+	normalized := normalizedURL(profileURL)
+	for _, table := range []string{"connection_requests", "search_results"} {
+		query := fmt.Sprintf(`UPDATE %s SET member_id = ? WHERE normalized_url = ? AND member_id IS NOT ?`, table)
+		if _, err := db.conn.Exec(query, id, normalized, id); err != nil {
+			return fmt.Errorf("failed to store member ID in %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
 // SaveMessage saves a message to the database
 func (db *DB) SaveMessage(msg *Message) error {
-	query := `INSERT INTO messages (profile_url, profile_name, content, sent_at)
-			  VALUES (?, ?, ?, ?)`
+	query := `INSERT INTO messages (profile_url, profile_name, channel, subject, content, template_id, sent_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	channel := msg.Channel
+	if channel == "" {
+		channel = ChannelMessage
+	}
 
-	result, err := db.conn.Exec(query, msg.ProfileURL, msg.ProfileName, msg.Content, msg.SentAt)
+	result, err := db.conn.Exec(query, msg.ProfileURL, msg.ProfileName, channel, msg.Subject, msg.Content, msg.TemplateID, msg.SentAt)
 	if err != nil {
 		return fmt.Errorf("failed to save message: %w", err)
 	}
@@ -184,6 +397,14 @@ func (db *DB) SaveMessage(msg *Message) error {
 	return nil
 }
 
+// CountMessagesWithContent returns how many messages with exactly this content were
+// recorded as sent to the profile
+func (db *DB) CountMessagesWithContent(profileURL, content string) (int, error) {
+	var count int
+	err := db.conn.QueryRow(`SELECT COUNT(*) FROM messages WHERE profile_url = ? AND content = ?`, profileURL, content).Scan(&count)
+	return count, err
+}
+
 // GetMessagesCountByDate returns the count of messages sent on a specific date
 func (db *DB) GetMessagesCountByDate(date time.Time) (int, error) {
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
@@ -196,30 +417,211 @@ func (db *DB) GetMessagesCountByDate(date time.Time) (int, error) {
 	return count, err
 }
 
-// SaveSearchResult saves a search result to the database
-func (db *DB) SaveSearchResult(result *SearchResult) error {
-	query := `INSERT OR IGNORE INTO search_results (profile_url, profile_name, job_title, company, location, found_at, contacted)
-			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+// GetInMailCountByDate returns the count of InMails sent on a specific date
+func (db *DB) GetInMailCountByDate(date time.Time) (int, error) {
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	query := `SELECT COUNT(*) FROM messages WHERE channel = 'inmail' AND sent_at >= ? AND sent_at < ?`
+
+	var count int
+	err := db.conn.QueryRow(query, startOfDay, endOfDay).Scan(&count)
+	return count, err
+}
+
+// GetMessagesCountSince returns the count of messages sent at or after t
+func (db *DB) GetMessagesCountSince(t time.Time) (int, error) {
+	var count int
+	err := db.conn.QueryRow(`SELECT COUNT(*) FROM messages WHERE sent_at >= ?`, t).Scan(&count)
+	return count, err
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// SaveSearchResult saves a search result to the database.
+// It reports whether the profile was new; profiles already stored are left untouched,
+// except that one only a monitor had found is handed over to the result's campaign.
+func (db *DB) SaveSearchResult(result *SearchResult) (bool, error) {
+	return saveSearchResult(db.conn, result)
+}
+
+// SaveSearchResults saves one page of search results in a single transaction
+// and returns how many of them were new
+func (db *DB) SaveSearchResults(results []*SearchResult) (int, error) {
+	inserted := 0
+	err := db.WithTx(func(tx *DB) error {
+		for _, result := range results {
+			isNew, err := saveSearchResult(tx.conn, result)
+			if err != nil {
+				return err
+			}
+			if isNew {
+				inserted++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return inserted, nil
+}
+
+// saveSearchResult inserts a search result unless its profile is already stored, under
+// the same URL or, when its member ID is known, under another one. Monitor-only results
+// aren't queued for outreach.
+func saveSearchResult(q querier, result *SearchResult) (bool, error) {
+	query := `INSERT OR IGNORE INTO search_results (profile_url, normalized_url, member_id, profile_name, job_title, company, primary_title, primary_company, location, campaign, found_at, contacted,
+			  summary, mutual_connections, premium, open_to_work, lead_url, source, filtered, filter_reason, degree, monitor_only)
+			  SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+			  WHERE NOT EXISTS (SELECT 1 FROM search_results WHERE member_id = ?)`
+
+	member := nullMemberID(result.MemberID)
+	res, err := q.Exec(query, result.ProfileURL, normalizedURL(result.ProfileURL), member, result.ProfileName, result.JobTitle, result.Company, result.PrimaryTitle, result.PrimaryCompany, result.Location, result.Campaign, result.FoundAt, result.Contacted,
+		result.Summary, result.MutualConnections, result.Premium, result.OpenToWork, result.LeadURL, result.Source, result.Filtered, result.FilterReason, result.Degree, result.MonitorOnly, member)
+	if err != nil {
+		return false, fmt.Errorf("failed to save search result: %w", err)
+	}
 
-	res, err := db.conn.Exec(query, result.ProfileURL, result.ProfileName, result.JobTitle, result.Company, result.Location, result.FoundAt, result.Contacted)
+	affected, err := res.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("failed to save search result: %w", err)
+		return false, nil
+	}
+	if affected == 0 {
+		if result.MonitorOnly {
+			return false, nil
+		}
+		return adoptMonitored(q, result)
 	}
 
 	id, err := res.LastInsertId()
-	if err == nil {
-		result.ID = id
+	if err != nil {
+		return true, nil
 	}
+	result.ID = id
 
-	return nil
+	if result.MonitorOnly {
+		return true, nil
+	}
+	return true, enqueueSearchResult(q, result)
 }
 
-// GetUncontactedProfiles returns profiles that haven't been contacted yet
-func (db *DB) GetUncontactedProfiles(limit int) ([]SearchResult, error) {
-	query := `SELECT id, profile_url, profile_name, job_title, company, location, found_at, contacted
-			  FROM search_results WHERE contacted = 0 LIMIT ?`
+// adoptMonitored hands a profile only a monitor had found over to the campaign whose
+// search found it too, queueing it for outreach as if it were new. It reports whether
+// there was such a profile.
+func adoptMonitored(q querier, result *SearchResult) (bool, error) {
+	member := nullMemberID(result.MemberID)
+	err := q.QueryRow(`SELECT id FROM search_results WHERE monitor_only = 1 AND (normalized_url = ? OR member_id = ?)`,
+		normalizedURL(result.ProfileURL), member).Scan(&result.ID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to look up monitored profile: %w", err)
+	}
 
-	rows, err := db.conn.Query(query, limit)
+	query := `UPDATE search_results SET monitor_only = 0, campaign = ?, source = ?, contacted = ?, filtered = ?, filter_reason = ? WHERE id = ?`
+	if _, err := q.Exec(query, result.Campaign, result.Source, result.Contacted, result.Filtered, result.FilterReason, result.ID); err != nil {
+		return false, fmt.Errorf("failed to adopt monitored profile: %w", err)
+	}
+	return true, enqueueSearchResult(q, result)
+}
+
+// GetUncontactedProfiles returns profiles found by a campaign whose connect queue item is
+// due, leaving out those the result filters rejected, higher queue priorities first. An
+// empty campaign matches profiles from every campaign. Profiles skipped today are
+// left for a later day, and the more often a profile was skipped the further back it queues.
+// Within that, policy sets the order and which profiles are left out.
+//
+// With a re-invite cooldown set, a profile whose invite was withdrawn or left pending is
+// handed out again once the cooldown has passed, and not before, even if queued by hand.
+func (db *DB) GetUncontactedProfiles(campaign string, limit int, policy ProspectPolicy) ([]SearchResult, error) {
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	where := []string{
+		"q.kind = ?",
+		"s.filtered = 0",
+		"s.status != ?",
+		"(? = '' OR s.campaign = ?)",
+		"(s.last_skipped_at IS NULL OR s.last_skipped_at < ?)",
+	}
+	args := []interface{}{QueueConnect, ProfileUnavailable, campaign, campaign, startOfDay}
+
+	due := "q.state IN (?, ?) AND (q.scheduled_for IS NULL OR q.scheduled_for <= ?)"
+	if reinvite, cutoff := db.reinviteCutoff(); reinvite {
+		where = append(where,
+			"(("+due+") OR (q.state IN (?, ?) AND c.status IN "+reinviteStatuses+"))",
+			"(c.status IS NULL OR c.status NOT IN "+reinviteStatuses+" OR c.updated_at <= ?)")
+		args = append(args, QueueQueued, QueueScheduled, now, QueueDone, QueueSkipped, cutoff)
+	} else {
+		where = append(where, due)
+		args = append(args, QueueQueued, QueueScheduled, now)
+	}
+
+	if policy.SkipOpenToWork {
+		where = append(where, "s.open_to_work = 0")
+	}
+	if !policy.OrganicSkippedBefore.IsZero() {
+		where = append(where, "(s.organic_skipped_at IS NULL OR s.organic_skipped_at < ?)")
+		args = append(args, policy.OrganicSkippedBefore)
+	}
+	if policy.Unengaged {
+		where = append(where, "e.id IS NULL")
+	}
+	if !policy.EngagedBefore.IsZero() {
+		// A like has to settle first; profiles with nothing to like go straight on
+		where = append(where, "e.id IS NOT NULL AND (e.outcome != ? OR e.engaged_at < ?)")
+		args = append(args, EngagementLiked, policy.EngagedBefore)
+	}
+
+	if !policy.FoundAfter.IsZero() {
+		where = append(where, "s.found_at >= ?")
+		args = append(args, policy.FoundAfter)
+	}
+	// LinkedIn doesn't always honor the network filter of a search
+	if policy.MaxDegree > 0 {
+		where = append(where, "(s.degree = 0 OR s.degree <= ?)")
+		args = append(args, policy.MaxDegree)
+	}
+
+	// Tagged profiles go first; the placeholders come before the others in the query
+	boost := "0"
+	if len(policy.BoostTags) > 0 {
+		boost = "EXISTS (SELECT 1 FROM profile_tags t WHERE t.normalized_url = s.normalized_url AND t.tag IN (?" + strings.Repeat(", ?", len(policy.BoostTags)-1) + "))"
+		tags := make([]interface{}, len(policy.BoostTags))
+		for i, tag := range policy.BoostTags {
+			tags[i] = NormalizeTag(tag)
+		}
+		args = append(tags, args...)
+	}
+
+	var order string
+	switch policy.Order {
+	case ProspectOrderOldest:
+		order = "q.priority DESC, boosted DESC, s.skip_count, s.id"
+	case ProspectOrderRandom:
+		order = "q.priority DESC, boosted DESC, s.skip_count, RANDOM()"
+	case ProspectOrderPriority:
+		order = "q.priority DESC, boosted DESC, s.skip_count, s.mutual_connections DESC, s.id DESC"
+	default:
+		order = "q.priority DESC, boosted DESC, s.skip_count, s.id DESC"
+	}
+
+	query := fmt.Sprintf(`SELECT s.id, q.id, s.profile_url, s.profile_name, s.job_title, s.company, s.primary_title, s.primary_company, s.location, s.campaign, s.found_at, s.contacted, s.skip_count,
+			  s.summary, s.mutual_connections, s.premium, s.open_to_work, s.lead_url, s.degree, %s AS boosted
+			  FROM search_results s JOIN outreach_queue q ON q.search_result_id = s.id
+			  LEFT JOIN engagements e ON e.normalized_url = s.normalized_url
+			  LEFT JOIN connection_requests c ON c.normalized_url = s.normalized_url
+			  WHERE %s
+			  ORDER BY %s LIMIT ?`, boost, strings.Join(where, " AND "), order)
+	args = append(args, limit)
+
+	rows, err := db.conn.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -228,7 +630,9 @@ func (db *DB) GetUncontactedProfiles(limit int) ([]SearchResult, error) {
 	var results []SearchResult
 	for rows.Next() {
 		var result SearchResult
-		if err := rows.Scan(&result.ID, &result.ProfileURL, &result.ProfileName, &result.JobTitle, &result.Company, &result.Location, &result.FoundAt, &result.Contacted); err != nil {
+		var boosted bool
+		if err := rows.Scan(&result.ID, &result.QueueID, &result.ProfileURL, &result.ProfileName, &result.JobTitle, &result.Company, &result.PrimaryTitle, &result.PrimaryCompany, &result.Location, &result.Campaign, &result.FoundAt, &result.Contacted, &result.SkipCount,
+			&result.Summary, &result.MutualConnections, &result.Premium, &result.OpenToWork, &result.LeadURL, &result.Degree, &boosted); err != nil {
 			return nil, err
 		}
 		results = append(results, result)
@@ -237,20 +641,415 @@ func (db *DB) GetUncontactedProfiles(limit int) ([]SearchResult, error) {
 	return results, nil
 }
 
-// MarkProfileContacted marks a profile as contacted
+// ResolveLeadURL replaces the lead URL a search result was stored under with its public
+// profile URL. It reports false, dropping the lead row, when the public URL is already
+// stored as another search result.
+func (db *DB) ResolveLeadURL(id int64, publicURL string) (bool, error) {
+	res, err := db.conn.Exec(`UPDATE OR IGNORE search_results SET profile_url = ?, normalized_url = ? WHERE id = ?`,
+		publicURL, normalizedURL(publicURL), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve lead URL: %w", err)
+	}
+
+	if affected, err := res.RowsAffected(); err == nil && affected > 0 {
+		return true, nil
+	}
+
+	if _, err := db.conn.Exec(`DELETE FROM search_results WHERE id = ?`, id); err != nil {
+		return false, fmt.Errorf("failed to drop duplicate lead: %w", err)
+	}
+	if _, err := db.conn.Exec(`DELETE FROM outreach_queue WHERE search_result_id = ?`, id); err != nil {
+		return false, fmt.Errorf("failed to drop the duplicate lead's queue item: %w", err)
+	}
+	return false, nil
+}
+
+// RecordProspectSkip counts a deliberate pass over a prospect; it stays uncontacted
+func (db *DB) RecordProspectSkip(profileURL string) error {
+	query := `UPDATE search_results SET skip_count = skip_count + 1, last_skipped_at = ? WHERE normalized_url = ?`
+	_, err := db.conn.Exec(query, time.Now(), normalizedURL(profileURL))
+	return err
+}
+
+// RecordOrganicSkip records that a prospect's profile was visited and left without
+// connecting; it stays uncontacted and comes back once the cool-down has passed
+func (db *DB) RecordOrganicSkip(profileURL string) error {
+	query := `UPDATE search_results SET organic_skipped_at = ? WHERE normalized_url = ?`
+	_, err := db.conn.Exec(query, time.Now(), normalizedURL(profileURL))
+	return err
+}
+
+// MarkProfileContacted marks a profile as contacted, settling its connect queue item as done
 func (db *DB) MarkProfileContacted(profileURL string) error {
-	query := `UPDATE search_results SET contacted = 1 WHERE profile_url = ?`
+	normalized := normalizedURL(profileURL)
+	if _, err := db.conn.Exec(`UPDATE search_results SET contacted = 1 WHERE normalized_url = ?`, normalized); err != nil {
+		return err
+	}
+
+	query := `UPDATE outreach_queue SET state = ?, updated_at = ?
+			  WHERE kind = ? AND state != ? AND search_result_id IN (SELECT id FROM search_results WHERE normalized_url = ?)`
+	_, err := db.conn.Exec(query, QueueDone, time.Now(), QueueConnect, QueueDone, normalized)
+	return err
+}
+
+// ProfileUnavailable is the status of a search result whose profile was deleted or doesn't
+// exist; it is never selected for outreach again
+const ProfileUnavailable = "unavailable"
+
+// MarkProfileUnavailable records that a profile was deleted or doesn't exist, settling its
+// connect queue item as skipped so it is never retried
+func (db *DB) MarkProfileUnavailable(profileURL string) error {
+	normalized := normalizedURL(profileURL)
+	if _, err := db.conn.Exec(`UPDATE search_results SET status = ? WHERE normalized_url = ?`, ProfileUnavailable, normalized); err != nil {
+		return fmt.Errorf("failed to mark profile unavailable: %w", err)
+	}
+
+	query := `UPDATE outreach_queue SET state = ?, last_error = ?, updated_at = ?
+			  WHERE kind = ? AND search_result_id IN (SELECT id FROM search_results WHERE normalized_url = ?)`
+	if _, err := db.conn.Exec(query, QueueSkipped, ProfileUnavailable, time.Now(), QueueConnect, normalized); err != nil {
+		return fmt.Errorf("failed to skip the unavailable profile's queue item: %w", err)
+	}
+	return nil
+}
+
+// SaveIncomingInvite records the decision made on an incoming invitation
+func (db *DB) SaveIncomingInvite(invite *IncomingInvite) error {
+	query := `INSERT INTO incoming_invites (profile_url, inviter_name, headline, mutual_connections, decision, reason, decided_at, welcomed)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			  ON CONFLICT(profile_url) DO UPDATE SET decision = excluded.decision, reason = excluded.reason, decided_at = excluded.decided_at`
+
+	result, err := db.conn.Exec(query, invite.ProfileURL, invite.InviterName, invite.Headline, invite.MutualConnections, invite.Decision, invite.Reason, invite.DecidedAt, invite.Welcomed)
+	if err != nil {
+		return fmt.Errorf("failed to save incoming invite: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err == nil {
+		invite.ID = id
+	}
+
+	return nil
+}
+
+// GetAcceptedInvitesCountByDate returns the number of incoming invites accepted on a specific date
+func (db *DB) GetAcceptedInvitesCountByDate(date time.Time) (int, error) {
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	query := `SELECT COUNT(*) FROM incoming_invites WHERE decision = 'accepted' AND decided_at >= ? AND decided_at < ?`
+
+	var count int
+	err := db.conn.QueryRow(query, startOfDay, endOfDay).Scan(&count)
+	return count, err
+}
+
+// GetInvitesAwaitingWelcome returns accepted incoming invites that haven't been welcomed yet
+func (db *DB) GetInvitesAwaitingWelcome(limit int) ([]IncomingInvite, error) {
+	query := `SELECT id, profile_url, inviter_name, headline, mutual_connections, decision, reason, decided_at, welcomed
+			  FROM incoming_invites WHERE decision = 'accepted' AND welcomed = 0 ORDER BY decided_at LIMIT ?`
+
+	rows, err := db.conn.Query(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invites []IncomingInvite
+	for rows.Next() {
+		var invite IncomingInvite
+		if err := rows.Scan(&invite.ID, &invite.ProfileURL, &invite.InviterName, &invite.Headline, &invite.MutualConnections, &invite.Decision, &invite.Reason, &invite.DecidedAt, &invite.Welcomed); err != nil {
+			return nil, err
+		}
+		invites = append(invites, invite)
+	}
+
+	return invites, nil
+}
+
+// MarkInviteWelcomed marks an accepted invite as having received its welcome message
+func (db *DB) MarkInviteWelcomed(profileURL string) error {
+	query := `UPDATE incoming_invites SET welcomed = 1 WHERE profile_url = ?`
 	_, err := db.conn.Exec(query, profileURL)
 	return err
 }
 
+// ScheduleMessage queues a message unless one of the same kind is already queued for the profile.
+// It reports whether a new message was queued.
+func (db *DB) ScheduleMessage(msg *ScheduledMessage) (bool, error) {
+	query := `INSERT OR IGNORE INTO scheduled_messages (profile_url, profile_name, job_title, company, kind, due_at, status, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, 'pending', ?)`
+
+	res, err := db.conn.Exec(query, msg.ProfileURL, msg.ProfileName, msg.JobTitle, msg.Company, msg.Kind, msg.DueAt, msg.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to schedule message: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, nil
+	}
+
+	if id, err := res.LastInsertId(); err == nil {
+		msg.ID = id
+	}
+
+	return true, nil
+}
+
+// GetDueScheduledMessages returns pending scheduled messages due at or before the given time
+func (db *DB) GetDueScheduledMessages(now time.Time) ([]ScheduledMessage, error) {
+	query := `SELECT id, profile_url, profile_name, job_title, company, kind, due_at, status, created_at
+			  FROM scheduled_messages WHERE status = 'pending' AND due_at <= ? ORDER BY due_at`
+
+	rows, err := db.conn.Query(query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []ScheduledMessage
+	for rows.Next() {
+		var msg ScheduledMessage
+		if err := rows.Scan(&msg.ID, &msg.ProfileURL, &msg.ProfileName, &msg.JobTitle, &msg.Company, &msg.Kind, &msg.DueAt, &msg.Status, &msg.CreatedAt); err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+
+	return messages, rows.Err()
+}
+
+// UpdateScheduledMessageStatus updates the status of a scheduled message
+func (db *DB) UpdateScheduledMessageStatus(id int64, status string) error {
+	_, err := db.conn.Exec(`UPDATE scheduled_messages SET status = ? WHERE id = ?`, status, id)
+	return err
+}
+
+// GetUnmessagedAcceptedCount returns how many accepted connections haven't received a message yet
+func (db *DB) GetUnmessagedAcceptedCount() (int, error) {
+	query := `SELECT COUNT(*) FROM connection_requests c
+			  WHERE c.status = 'accepted' AND NOT EXISTS (SELECT 1 FROM messages m WHERE m.profile_url = c.profile_url)`
+
+	var count int
+	err := db.conn.QueryRow(query).Scan(&count)
+	return count, err
+}
+
+// SavePlannerDecision stores the planner's decision for a day, replacing an earlier one
+func (db *DB) SavePlannerDecision(decision *PlannerDecision) error {
+	query := `INSERT INTO planner_decisions (date, backlog, connect_budget, reduced, reason, decided_at)
+			  VALUES (?, ?, ?, ?, ?, ?)
+			  ON CONFLICT(date) DO UPDATE SET backlog = excluded.backlog, connect_budget = excluded.connect_budget,
+				reduced = excluded.reduced, reason = excluded.reason, decided_at = excluded.decided_at`
+
+	_, err := db.conn.Exec(query, decision.Date, decision.Backlog, decision.ConnectBudget, decision.Reduced, decision.Reason, decision.DecidedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save planner decision: %w", err)
+	}
+	return nil
+}
+
+// GetPlannerDecisionsBefore returns up to limit planner decisions for days before date, newest first
+func (db *DB) GetPlannerDecisionsBefore(date string, limit int) ([]PlannerDecision, error) {
+	query := `SELECT date, backlog, connect_budget, reduced, reason, decided_at
+			  FROM planner_decisions WHERE date < ? ORDER BY date DESC LIMIT ?`
+
+	rows, err := db.conn.Query(query, date, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var decisions []PlannerDecision
+	for rows.Next() {
+		var d PlannerDecision
+		if err := rows.Scan(&d.Date, &d.Backlog, &d.ConnectBudget, &d.Reduced, &d.Reason, &d.DecidedAt); err != nil {
+			return nil, err
+		}
+		decisions = append(decisions, d)
+	}
+
+	return decisions, rows.Err()
+}
+
+// SaveActionSlots stores the planned action slots for a day in a single transaction
+func (db *DB) SaveActionSlots(date string, slots []time.Time) error {
+	return db.WithTx(func(tx *DB) error {
+		for _, slot := range slots {
+			if _, err := tx.conn.Exec(`INSERT INTO action_slots (date, slot_at, status) VALUES (?, ?, 'pending')`, date, slot); err != nil {
+				return fmt.Errorf("failed to save action slot: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetActionSlots returns every slot planned for a day, earliest first
+func (db *DB) GetActionSlots(date string) ([]ActionSlot, error) {
+	query := `SELECT id, date, slot_at, status FROM action_slots WHERE date = ? ORDER BY slot_at`
+
+	rows, err := db.conn.Query(query, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var slots []ActionSlot
+	for rows.Next() {
+		var slot ActionSlot
+		if err := rows.Scan(&slot.ID, &slot.Date, &slot.SlotAt, &slot.Status); err != nil {
+			return nil, err
+		}
+		slots = append(slots, slot)
+	}
+
+	return slots, rows.Err()
+}
+
+// UpdateActionSlotStatus updates the status of a planned action slot
+func (db *DB) UpdateActionSlotStatus(id int64, status string) error {
+	_, err := db.conn.Exec(`UPDATE action_slots SET status = ? WHERE id = ?`, status, id)
+	return err
+}
+
+// SaveConfigSnapshot stores a snapshot under its hash; a snapshot already stored is kept as is
+func (db *DB) SaveConfigSnapshot(hash string, snapshot []byte) error {
+	query := `INSERT OR IGNORE INTO config_snapshots (hash, snapshot, created_at) VALUES (?, ?, ?)`
+
+	if _, err := db.conn.Exec(query, hash, string(snapshot), time.Now()); err != nil {
+		return fmt.Errorf("failed to save config snapshot: %w", err)
+	}
+	return nil
+}
+
+// GetConfigSnapshot returns the snapshot stored under hash
+func (db *DB) GetConfigSnapshot(hash string) ([]byte, error) {
+	var snapshot string
+	if err := db.conn.QueryRow(`SELECT snapshot FROM config_snapshots WHERE hash = ?`, hash).Scan(&snapshot); err != nil {
+		return nil, err
+	}
+	return []byte(snapshot), nil
+}
+
+// AddActiveTime adds d to the active time recorded for date (YYYY-MM-DD)
+func (db *DB) AddActiveTime(date string, d time.Duration) error {
+	query := `INSERT INTO active_time (date, active_ms) VALUES (?, ?)
+			  ON CONFLICT(date) DO UPDATE SET active_ms = active_ms + excluded.active_ms`
+
+	if _, err := db.conn.Exec(query, date, d.Milliseconds()); err != nil {
+		return fmt.Errorf("failed to add active time: %w", err)
+	}
+	return nil
+}
+
+// GetActiveTime returns the active time recorded for date (YYYY-MM-DD)
+func (db *DB) GetActiveTime(date string) (time.Duration, error) {
+	var ms int64
+	err := db.conn.QueryRow(`SELECT COALESCE(SUM(active_ms), 0) FROM active_time WHERE date = ?`, date).Scan(&ms)
+	return time.Duration(ms) * time.Millisecond, err
+}
+
+// AddNavigation counts a page load on date (YYYY-MM-DD)
+func (db *DB) AddNavigation(date string) error {
+	query := `INSERT INTO navigations (date, count) VALUES (?, 1)
+			  ON CONFLICT(date) DO UPDATE SET count = count + 1`
+
+	if _, err := db.conn.Exec(query, date); err != nil {
+		return fmt.Errorf("failed to add navigation: %w", err)
+	}
+	return nil
+}
+
+// GetNavigationCount returns the page loads recorded for date (YYYY-MM-DD)
+func (db *DB) GetNavigationCount(date string) (int, error) {
+	var count int
+	err := db.conn.QueryRow(`SELECT COALESCE(SUM(count), 0) FROM navigations WHERE date = ?`, date).Scan(&count)
+	return count, err
+}
+
+// SaveEngagement records an engagement with a profile. There is at most one per
+// profile; it reports false when the profile already had one.
+func (db *DB) SaveEngagement(e *Engagement) (bool, error) {
+	query := `INSERT OR IGNORE INTO engagements (profile_url, normalized_url, action, outcome, post_url, engaged_at)
+			  VALUES (?, ?, ?, ?, ?, ?)`
+
+	res, err := db.conn.Exec(query, e.ProfileURL, normalizedURL(e.ProfileURL), e.Action, e.Outcome, e.PostURL, e.EngagedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to save engagement: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, nil
+	}
+	if id, err := res.LastInsertId(); err == nil {
+		e.ID = id
+	}
+	return true, nil
+}
+
+// GetEngagement returns the engagement with a profile, or nil when there is none
+func (db *DB) GetEngagement(profileURL string) (*Engagement, error) {
+	query := `SELECT id, profile_url, action, outcome, post_url, engaged_at FROM engagements WHERE normalized_url = ?`
+
+	var e Engagement
+	err := db.conn.QueryRow(query, normalizedURL(profileURL)).Scan(&e.ID, &e.ProfileURL, &e.Action, &e.Outcome, &e.PostURL, &e.EngagedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get engagement: %w", err)
+	}
+	return &e, nil
+}
+
+// GetLikesCountByDate returns how many posts were liked on a specific date
+func (db *DB) GetLikesCountByDate(date time.Time) (int, error) {
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	query := `SELECT COUNT(*) FROM engagements WHERE outcome = ? AND engaged_at >= ? AND engaged_at < ?`
+
+	var count int
+	err := db.conn.QueryRow(query, EngagementLiked, startOfDay, endOfDay).Scan(&count)
+	return count, err
+}
+
+// SaveCaptchaSolve records one CAPTCHA solver invocation
+func (db *DB) SaveCaptchaSolve(solve *CaptchaSolve) error {
+	query := `INSERT INTO captcha_solves (challenge_type, outcome, latency_ms, cost, error, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?)`
+
+	_, err := db.conn.Exec(query, solve.ChallengeType, solve.Outcome, solve.LatencyMS, solve.Cost, solve.Error, solve.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save captcha solve: %w", err)
+	}
+	return nil
+}
+
+// GetCaptchaCostByDate returns what CAPTCHA solving has cost on a given date
+func (db *DB) GetCaptchaCostByDate(date time.Time) (float64, error) {
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	var cost float64
+	err := db.conn.QueryRow(`SELECT COALESCE(SUM(cost), 0) FROM captcha_solves WHERE created_at >= ? AND created_at < ?`,
+		startOfDay, endOfDay).Scan(&cost)
+	return cost, err
+}
+
 // LogActivity logs an activity to the database
 func (db *DB) LogActivity(action, details string) error {
-	query := `INSERT INTO activity_logs (action, details, timestamp) VALUES (?, ?, ?)`
-	_, err := db.conn.Exec(query, action, details, time.Now())
+	query := `INSERT INTO activity_logs (action, details, run_id, timestamp) VALUES (?, ?, ?, ?)`
+	_, err := db.conn.Exec(query, action, details, db.runID, time.Now())
 	return err
 }
 
+// GetActivitiesBetween returns the activity logged in [start, end), oldest first
+func (db *DB) GetActivitiesBetween(start, end time.Time) ([]ActivityLog, error) {
+	return db.queryActivities(activityColumns+` WHERE timestamp >= ? AND timestamp < ? ORDER BY timestamp, id`, start, end)
+}
+
 // GetDailyStats returns statistics for a specific date
 func (db *DB) GetDailyStats(date time.Time) (*DailyStats, error) {
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
@@ -261,7 +1060,7 @@ func (db *DB) GetDailyStats(date time.Time) (*DailyStats, error) {
 	}
 
 	// Count connections sent
-	err := db.conn.QueryRow(`SELECT COUNT(*) FROM connection_requests WHERE sent_at >= ? AND sent_at < ?`, startOfDay, endOfDay).Scan(&stats.ConnectionsSent)
+	err := db.conn.QueryRow(`SELECT COUNT(*) FROM connection_requests WHERE status NOT IN ('skipped', 'failed') AND sent_at >= ? AND sent_at < ?`, startOfDay, endOfDay).Scan(&stats.ConnectionsSent)
 	if err != nil {
 		return nil, err
 	}
@@ -272,6 +1071,12 @@ func (db *DB) GetDailyStats(date time.Time) (*DailyStats, error) {
 		return nil, err
 	}
 
+	// Count replies detected
+	err = db.conn.QueryRow(`SELECT COUNT(*) FROM connection_requests WHERE status = 'replied' AND updated_at >= ? AND updated_at < ?`, startOfDay, endOfDay).Scan(&stats.RepliesReceived)
+	if err != nil {
+		return nil, err
+	}
+
 	// Count messages sent
 	err = db.conn.QueryRow(`SELECT COUNT(*) FROM messages WHERE sent_at >= ? AND sent_at < ?`, startOfDay, endOfDay).Scan(&stats.MessagesSent)
 	if err != nil {
@@ -0,0 +1,271 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/profileurl"
+)
+
+func TestUncontactedProfilesPolicy(t *testing.T) {
+	db := openTestDB(t)
+	found := time.Now().Add(-time.Hour)
+
+	results := []*SearchResult{
+		{ProfileURL: "https://www.linkedin.com/in/first/", ProfileName: "First", Campaign: "founders", FoundAt: found, MutualConnections: 2},
+		{ProfileURL: "https://www.linkedin.com/in/looking/", ProfileName: "Looking", Campaign: "founders", FoundAt: found, MutualConnections: 30,
+			OpenToWork: true, Premium: true, Summary: "Current: Staff Engineer at Acme"},
+		{ProfileURL: "https://www.linkedin.com/in/well-connected/", ProfileName: "Well Connected", Campaign: "founders", FoundAt: found, MutualConnections: 12},
+	}
+	if _, err := db.SaveSearchResults(results); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		policy ProspectPolicy
+		want   []string
+	}{
+		{"found order", ProspectPolicy{Order: ProspectOrderOldest}, []string{"First", "Looking", "Well Connected"}},
+		{"mutual connections first", ProspectPolicy{Order: ProspectOrderPriority}, []string{"Looking", "Well Connected", "First"}},
+		{"open to work left out", ProspectPolicy{Order: ProspectOrderPriority, SkipOpenToWork: true}, []string{"Well Connected", "First"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			profiles, err := db.GetUncontactedProfiles("founders", 10, tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, p := range profiles {
+				names = append(names, p.ProfileName)
+			}
+			if len(names) != len(tt.want) {
+				t.Fatalf("got %v, want %v", names, tt.want)
+			}
+			for i := range names {
+				if names[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", names, tt.want)
+				}
+			}
+		})
+	}
+
+	// The insight columns read back as stored
+	profiles, err := db.GetUncontactedProfiles("founders", 10, ProspectPolicy{Order: ProspectOrderPriority})
+	if err != nil {
+		t.Fatal(err)
+	}
+	looking := profiles[0]
+	if looking.MutualConnections != 30 || !looking.OpenToWork || !looking.Premium || looking.Summary != "Current: Staff Engineer at Acme" {
+		t.Fatalf("stored profile = %+v", looking)
+	}
+}
+
+func TestUncontactedProfilesNewestAndStale(t *testing.T) {
+	db := openTestDB(t)
+	now := time.Now()
+
+	// Saved in the order they were found
+	for _, p := range []struct {
+		name string
+		age  time.Duration
+	}{
+		{"Stale", 90 * 24 * time.Hour},
+		{"Last Month", 20 * 24 * time.Hour},
+		{"Yesterday", 24 * time.Hour},
+	} {
+		url := "https://www.linkedin.com/in/" + strings.ToLower(strings.ReplaceAll(p.name, " ", "-")) + "/"
+		if _, err := db.SaveSearchResults([]*SearchResult{{ProfileURL: url, ProfileName: p.name, FoundAt: now.Add(-p.age)}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names := func(policy ProspectPolicy) string {
+		t.Helper()
+		profiles, err := db.GetUncontactedProfiles("", 10, policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, p := range profiles {
+			names = append(names, p.ProfileName)
+		}
+		return strings.Join(names, ", ")
+	}
+
+	if got, want := names(ProspectPolicy{}), "Yesterday, Last Month, Stale"; got != want {
+		t.Errorf("default order = %s, want newest first: %s", got, want)
+	}
+	if got, want := names(ProspectPolicy{Order: ProspectOrderNewest, FoundAfter: now.AddDate(0, 0, -60)}), "Yesterday, Last Month"; got != want {
+		t.Errorf("without stale results = %s, want %s", got, want)
+	}
+
+	// Random order hands out every profile, shuffled
+	orders := map[string]bool{}
+	for i := 0; i < 30; i++ {
+		got := names(ProspectPolicy{Order: ProspectOrderRandom})
+		if len(strings.Split(got, ", ")) != 3 {
+			t.Fatalf("random order = %s", got)
+		}
+		orders[got] = true
+	}
+	if len(orders) < 2 {
+		t.Errorf("random order was always %v", orders)
+	}
+}
+
+func TestConcurrentWrites(t *testing.T) {
+	db := openTestDB(t)
+	const workers, each = 20, 25
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*each*3)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < each; i++ {
+				url := fmt.Sprintf("https://www.linkedin.com/in/worker-%d-%d/", w, i)
+				if _, err := db.SaveSearchResult(&SearchResult{ProfileURL: url, ProfileName: "Ada Lovelace", FoundAt: time.Now()}); err != nil {
+					errs <- err
+				}
+				if err := db.LogActivity("stress", url); err != nil {
+					errs <- err
+				}
+				// Transactions queue behind the other writers too
+				err := db.WithTx(func(tx *DB) error {
+					if err := tx.SaveConnectionRequest(&ConnectionRequest{ProfileURL: url, Status: "pending", SentAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
+						return err
+					}
+					return tx.MarkProfileContacted(url)
+				})
+				if err != nil {
+					errs <- err
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("concurrent write failed: %v", err)
+	}
+	for table, query := range map[string]string{
+		"search results":      `SELECT COUNT(*) FROM search_results WHERE contacted = 1`,
+		"activity":            `SELECT COUNT(*) FROM activity_logs WHERE action = 'stress'`,
+		"connection requests": `SELECT COUNT(*) FROM connection_requests`,
+	} {
+		var count int
+		if err := db.sqlDB.QueryRow(query).Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		if count != workers*each {
+			t.Errorf("%d %s stored, want %d", count, table, workers*each)
+		}
+	}
+}
+
+func TestContactedUnderAnotherURL(t *testing.T) {
+	const (
+		member = "ACoAAB1xYz2"
+		vanity = "https://www.linkedin.com/in/ada-lovelace/"
+		idURL  = "https://www.linkedin.com/in/" + member
+	)
+
+	for _, tt := range []struct {
+		name  string
+		setup func(t *testing.T, db *DB)
+	}{
+		{"request with the member ID", func(t *testing.T, db *DB) {
+			err := db.SaveConnectionRequest(&ConnectionRequest{ProfileURL: vanity, MemberID: member, Status: "pending"})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}},
+		{"member ID from the search result", func(t *testing.T, db *DB) {
+			if _, err := db.SaveSearchResults([]*SearchResult{{ProfileURL: vanity, MemberID: member, Campaign: "default"}}); err != nil {
+				t.Fatal(err)
+			}
+			saveRequest(t, db, vanity, "pending", time.Now())
+		}},
+		{"member ID backfilled from the profile", func(t *testing.T, db *DB) {
+			saveRequest(t, db, vanity, "pending", time.Now())
+			if err := db.SetProfileMemberID(vanity, member); err != nil {
+				t.Fatal(err)
+			}
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openMemoryDB(t)
+			tt.setup(t, db)
+
+			for _, url := range []string{vanity, idURL} {
+				contacted, err := db.IsProfileContacted(url, profileurl.MemberID(url))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !contacted {
+					t.Errorf("%s isn't contacted", url)
+				}
+			}
+			if contacted, _ := db.IsProfileContacted("https://www.linkedin.com/in/someone-else/", ""); contacted {
+				t.Error("another member is contacted")
+			}
+		})
+	}
+}
+
+func TestSearchResultsKeepOneRowPerMember(t *testing.T) {
+	db := openMemoryDB(t)
+	results := []*SearchResult{
+		{ProfileURL: "https://www.linkedin.com/in/ada-lovelace/", MemberID: "ACoAAB1xYz2", Campaign: "default"},
+		{ProfileURL: "https://www.linkedin.com/in/ACoAAB1xYz2", MemberID: "ACoAAB1xYz2", Campaign: "default"},
+		{ProfileURL: "https://www.linkedin.com/in/grace-hopper/", Campaign: "default"},
+	}
+	inserted, err := db.SaveSearchResults(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inserted != 2 {
+		t.Fatalf("inserted %d results, want one per member", inserted)
+	}
+}
+
+func TestUncontactedProfilesMaxDegree(t *testing.T) {
+	db := openMemoryDB(t)
+	found := time.Now().Add(-time.Hour)
+
+	var results []*SearchResult
+	for degree, name := range []string{"No Badge", "First", "Second", "Third"} {
+		results = append(results, &SearchResult{ProfileURL: "https://www.linkedin.com/in/" + strings.ReplaceAll(strings.ToLower(name), " ", "-") + "/",
+			ProfileName: name, Campaign: "founders", FoundAt: found, Degree: degree})
+	}
+	if _, err := db.SaveSearchResults(results); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		maxDegree int
+		want      string
+	}{
+		{0, "No Badge,First,Second,Third"},
+		{2, "No Badge,First,Second"},
+		{1, "No Badge,First"},
+	} {
+		profiles, err := db.GetUncontactedProfiles("founders", 10, ProspectPolicy{Order: ProspectOrderOldest, MaxDegree: tt.maxDegree})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, p := range profiles {
+			names = append(names, p.ProfileName)
+		}
+		if got := strings.Join(names, ","); got != tt.want {
+			t.Errorf("max degree %d: got %s, want %s", tt.maxDegree, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// Diagnostic kinds
+const (
+	DiagnosticHTTP      = "http"      // a call to LinkedIn's API that failed with a 4xx, 5xx or 999
+	DiagnosticConsole   = "console"   // console.error in the page
+	DiagnosticException = "exception" // an uncaught exception in the page
+)
+
+// SaveDiagnostic records a browser-side error, keeping only the latest maxRows; 0 keeps them all
+func (db *DB) SaveDiagnostic(d *Diagnostic, maxRows int) error {
+	query := `INSERT INTO diagnostics (kind, status, url, message, page_url, run_id, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	res, err := db.conn.Exec(query, d.Kind, d.Status, d.URL, d.Message, d.PageURL, db.runID, d.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save diagnostic: %w", err)
+	}
+	if maxRows <= 0 {
+		return nil
+	}
+
+	// Ids only grow, so the oldest rows are those more than maxRows below the newest
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil
+	}
+	if _, err := db.conn.Exec(`DELETE FROM diagnostics WHERE id <= ?`, id-int64(maxRows)); err != nil {
+		return fmt.Errorf("failed to trim diagnostics: %w", err)
+	}
+	return nil
+}
+
+// GetDiagnostics returns the browser-side errors recorded since t, oldest first
+func (db *DB) GetDiagnostics(since time.Time) ([]Diagnostic, error) {
+	query := `SELECT id, kind, status, COALESCE(url, ''), COALESCE(message, ''), COALESCE(page_url, ''), COALESCE(run_id, ''), created_at
+			  FROM diagnostics WHERE created_at >= ? ORDER BY id`
+
+	rows, err := db.conn.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diagnostics: %w", err)
+	}
+	defer rows.Close()
+
+	var diagnostics []Diagnostic
+	for rows.Next() {
+		var d Diagnostic
+		if err := rows.Scan(&d.ID, &d.Kind, &d.Status, &d.URL, &d.Message, &d.PageURL, &d.RunID, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		diagnostics = append(diagnostics, d)
+	}
+	return diagnostics, rows.Err()
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GetFingerprint returns the fingerprint an account presents, or nil before one was chosen
+func (db *DB) GetFingerprint(account string) (*Fingerprint, error) {
+	query := `SELECT account, user_agent, viewport_label, viewport_width, viewport_height, device_scale_factor, timezone, languages, platform, created_at, updated_at,
+			  hardware_platform, webgl_vendor, webgl_renderer, hardware_concurrency, device_memory, canvas_seed
+			  FROM fingerprints WHERE account = ?`
+
+	var fp Fingerprint
+	var languages string
+	err := db.conn.QueryRow(query, accountKey(account)).Scan(&fp.Account, &fp.UserAgent, &fp.ViewportLabel, &fp.ViewportWidth, &fp.ViewportHeight,
+		&fp.DeviceScaleFactor, &fp.Timezone, &languages, &fp.Platform, &fp.CreatedAt, &fp.UpdatedAt,
+		&fp.HardwarePlatform, &fp.WebGLVendor, &fp.WebGLRenderer, &fp.HardwareConcurrency, &fp.DeviceMemory, &fp.CanvasSeed)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fingerprint: %w", err)
+	}
+
+	if languages != "" {
+		fp.Languages = strings.Split(languages, ",")
+	}
+	return &fp, nil
+}
+
+// SaveFingerprint records the fingerprint an account presents, replacing an earlier one
+func (db *DB) SaveFingerprint(fp *Fingerprint) error {
+	query := `INSERT INTO fingerprints (account, user_agent, viewport_label, viewport_width, viewport_height, device_scale_factor, timezone, languages, platform, created_at, updated_at,
+				hardware_platform, webgl_vendor, webgl_renderer, hardware_concurrency, device_memory, canvas_seed)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			  ON CONFLICT(account) DO UPDATE SET user_agent = excluded.user_agent, viewport_label = excluded.viewport_label,
+				viewport_width = excluded.viewport_width, viewport_height = excluded.viewport_height,
+				device_scale_factor = excluded.device_scale_factor, timezone = excluded.timezone,
+				languages = excluded.languages, platform = excluded.platform, updated_at = excluded.updated_at,
+				hardware_platform = excluded.hardware_platform, webgl_vendor = excluded.webgl_vendor,
+				webgl_renderer = excluded.webgl_renderer, hardware_concurrency = excluded.hardware_concurrency,
+				device_memory = excluded.device_memory, canvas_seed = excluded.canvas_seed`
+
+	now := time.Now()
+	_, err := db.conn.Exec(query, accountKey(fp.Account), fp.UserAgent, fp.ViewportLabel, fp.ViewportWidth, fp.ViewportHeight, fp.DeviceScaleFactor,
+		fp.Timezone, strings.Join(fp.Languages, ","), fp.Platform, now, now,
+		fp.HardwarePlatform, fp.WebGLVendor, fp.WebGLRenderer, fp.HardwareConcurrency, fp.DeviceMemory, fp.CanvasSeed)
+	if err != nil {
+		return fmt.Errorf("failed to save fingerprint: %w", err)
+	}
+	return nil
+}
+
+// accountKey keys an account by its login, which LinkedIn matches case-insensitively
+func accountKey(account string) string {
+	return strings.ToLower(strings.TrimSpace(account))
+}
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Limit names of the limits LinkedIn reports
+const (
+	LimitWeeklyInvites = "weekly_invites" // invitations left this week
+)
+
+// SaveLimitState records how many actions LinkedIn reported left under a limit, replacing
+// the earlier report
+func (db *DB) SaveLimitState(name string, remaining int, observedAt time.Time) error {
+	query := `INSERT INTO limits_state (name, remaining, observed_at) VALUES (?, ?, ?)
+			  ON CONFLICT(name) DO UPDATE SET remaining = excluded.remaining, observed_at = excluded.observed_at`
+	if _, err := db.conn.Exec(query, name, remaining, observedAt); err != nil {
+		return fmt.Errorf("failed to save limit state: %w", err)
+	}
+	return nil
+}
+
+// GetLimitState returns what LinkedIn last reported of a limit, or nil when it never did
+func (db *DB) GetLimitState(name string) (*LimitState, error) {
+	state := LimitState{Name: name}
+	err := db.conn.QueryRow(`SELECT remaining, observed_at FROM limits_state WHERE name = ?`, name).
+		Scan(&state.Remaining, &state.ObservedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get limit state: %w", err)
+	}
+	return &state, nil
+}
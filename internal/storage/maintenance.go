@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// PrunePolicy says which rows Prune deletes. Each retention is in days; 0 or less keeps
+// those rows however old they are.
+type PrunePolicy struct {
+	SearchResultDays int // search results never contacted, by when they were found
+	ActivityLogDays  int
+	DiagnosticDays   int
+	DryRun           bool // only count what would go
+}
+
+// PruneResult counts the rows Prune deleted, or would delete on a dry run
+type PruneResult struct {
+	SearchResults int64
+	QueueItems    int64 // the pruned search results' outreach queue items
+	ActivityLogs  int64
+	Diagnostics   int64
+	FreedBytes    int64 // by the VACUUM that followed; 0 on a dry run
+}
+
+// Total returns how many rows were pruned
+func (r *PruneResult) Total() int64 {
+	return r.SearchResults + r.QueueItems + r.ActivityLogs + r.Diagnostics
+}
+
+// prunable is one kind of row Prune deletes: those of table matching where, which takes
+// the cutoff time as its one argument
+type prunable struct {
+	table string
+	where string
+	days  int
+	count *int64
+}
+
+// Prune deletes the rows older than policy's retention windows that will never be used
+// again, then runs a VACUUM to hand the space back. Search results are only deleted while
+// nobody was contacted under their URL, and never when only a monitor found them: they
+// are what keeps it from reporting the profiles again.
+func (db *DB) Prune(policy PrunePolicy) (*PruneResult, error) {
+	result := &PruneResult{}
+	steps := []prunable{
+		{"search_results", `contacted = 0 AND monitor_only = 0 AND found_at < ?
+			AND normalized_url NOT IN (SELECT normalized_url FROM connection_requests WHERE normalized_url IS NOT NULL)
+			AND id NOT IN (SELECT search_result_id FROM outreach_queue WHERE state = '` + QueueInProgress + `')`,
+			policy.SearchResultDays, &result.SearchResults},
+		{"activity_logs", `timestamp < ?`, policy.ActivityLogDays, &result.ActivityLogs},
+		{"diagnostics", `created_at < ?`, policy.DiagnosticDays, &result.Diagnostics},
+	}
+
+	now := time.Now()
+	err := db.WithTx(func(tx *DB) error {
+		for _, step := range steps {
+			if step.days <= 0 {
+				continue
+			}
+			cutoff := now.AddDate(0, 0, -step.days)
+
+			if policy.DryRun {
+				query := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s`, step.table, step.where)
+				if err := tx.conn.QueryRow(query, cutoff).Scan(step.count); err != nil {
+					return fmt.Errorf("failed to count %s to prune: %w", step.table, err)
+				}
+				continue
+			}
+
+			res, err := tx.conn.Exec(fmt.Sprintf(`DELETE FROM %s WHERE %s`, step.table, step.where), cutoff)
+			if err != nil {
+				return fmt.Errorf("failed to prune %s: %w", step.table, err)
+			}
+			if *step.count, err = res.RowsAffected(); err != nil {
+				return fmt.Errorf("failed to prune %s: %w", step.table, err)
+			}
+		}
+
+		// The queue items of the search results go with them
+		if policy.DryRun {
+			if policy.SearchResultDays <= 0 {
+				return nil
+			}
+			query := `SELECT COUNT(*) FROM outreach_queue WHERE search_result_id IN (SELECT id FROM search_results WHERE ` + steps[0].where + `)`
+			if err := tx.conn.QueryRow(query, now.AddDate(0, 0, -policy.SearchResultDays)).Scan(&result.QueueItems); err != nil {
+				return fmt.Errorf("failed to count queue items to prune: %w", err)
+			}
+			return nil
+		}
+		res, err := tx.conn.Exec(`DELETE FROM outreach_queue WHERE search_result_id NOT IN (SELECT id FROM search_results)`)
+		if err != nil {
+			return fmt.Errorf("failed to prune queue items: %w", err)
+		}
+		result.QueueItems, err = res.RowsAffected()
+		return err
+	})
+	if err != nil || policy.DryRun {
+		return result, err
+	}
+
+	before, err := db.fileSize()
+	if err != nil {
+		return result, err
+	}
+	if _, err := db.conn.Exec(`VACUUM`); err != nil {
+		return result, fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	after, err := db.fileSize()
+	if err != nil {
+		return result, err
+	}
+	result.FreedBytes = max(before-after, 0)
+	return result, nil
+}
+
+// TableSize is one table's row count and the bytes it takes, its indexes included
+type TableSize struct {
+	Name  string
+	Rows  int64
+	Bytes int64
+}
+
+// SizeStats returns every table's size, largest first, and the database's size in bytes
+func (db *DB) SizeStats() ([]TableSize, int64, error) {
+	rows, err := db.conn.Query(`SELECT m.tbl_name, COALESCE(SUM(d.pgsize), 0) FROM sqlite_master m LEFT JOIN dbstat d ON d.name = m.name
+		WHERE m.tbl_name NOT LIKE 'sqlite_%' AND m.type IN ('table', 'index')
+		GROUP BY m.tbl_name ORDER BY 2 DESC, 1`)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get table sizes: %w", err)
+	}
+	var tables []TableSize
+	for rows.Next() {
+		var t TableSize
+		if err := rows.Scan(&t.Name, &t.Bytes); err != nil {
+			rows.Close()
+			return nil, 0, fmt.Errorf("failed to get table sizes: %w", err)
+		}
+		tables = append(tables, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to get table sizes: %w", err)
+	}
+
+	for i := range tables {
+		if err := db.conn.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM "%s"`, tables[i].Name)).Scan(&tables[i].Rows); err != nil {
+			return nil, 0, fmt.Errorf("failed to count %s rows: %w", tables[i].Name, err)
+		}
+	}
+
+	total, err := db.fileSize()
+	if err != nil {
+		return nil, 0, err
+	}
+	return tables, total, nil
+}
+
+// fileSize returns the size of the database in bytes, free pages included
+func (db *DB) fileSize() (int64, error) {
+	var pages, pageSize int64
+	if err := db.conn.QueryRow(`PRAGMA page_count`).Scan(&pages); err != nil {
+		return 0, fmt.Errorf("failed to get database size: %w", err)
+	}
+	if err := db.conn.QueryRow(`PRAGMA page_size`).Scan(&pageSize); err != nil {
+		return 0, fmt.Errorf("failed to get database size: %w", err)
+	}
+	return pages * pageSize, nil
+}
@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// seedPrunable stores rows on either side of 30-day retention windows: three prunable
+// ones, and as many that must stay
+func seedPrunable(t *testing.T, db *DB) {
+	t.Helper()
+	old, recent := time.Now().AddDate(0, 0, -90), time.Now().AddDate(0, 0, -1)
+
+	save := func(name string, found time.Time, monitorOnly bool) *SearchResult {
+		result := &SearchResult{ProfileURL: "https://www.linkedin.com/in/" + name + "/", ProfileName: name,
+			Campaign: "default", FoundAt: found, MonitorOnly: monitorOnly}
+		if _, err := db.SaveSearchResult(result); err != nil {
+			t.Fatalf("SaveSearchResult: %v", err)
+		}
+		return result
+	}
+
+	save("never-contacted", old, false)
+	save("found-recently", recent, false)
+	save("monitored", old, true)
+	save("marked-contacted", old, false)
+	if err := db.MarkProfileContacted("https://www.linkedin.com/in/marked-contacted/"); err != nil {
+		t.Fatal(err)
+	}
+	save("requested", old, false)
+	saveRequest(t, db, "https://www.linkedin.com/in/requested/", "failed", old)
+	claimed := save("being-worked-on", old, false)
+	if _, err := db.sqlDB.Exec(`UPDATE outreach_queue SET state = ? WHERE search_result_id = ?`, QueueInProgress, claimed.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, at := range []time.Time{old, recent} {
+		if _, err := db.sqlDB.Exec(`INSERT INTO activity_logs (action, details, timestamp) VALUES ('search', '', ?)`, at); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.SaveDiagnostic(&Diagnostic{Kind: DiagnosticConsole, Message: "boom", CreatedAt: at}, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// count returns the rows of table
+func count(t *testing.T, db *DB, table string) int {
+	t.Helper()
+	var n int
+	if err := db.sqlDB.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM %s`, table)).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestPrune(t *testing.T) {
+	db := openTestDB(t)
+	seedPrunable(t, db)
+	policy := PrunePolicy{SearchResultDays: 30, ActivityLogDays: 30, DiagnosticDays: 30}
+	want := PruneResult{SearchResults: 1, QueueItems: 1, ActivityLogs: 1, Diagnostics: 1}
+
+	dryRun := policy
+	dryRun.DryRun = true
+	counted, err := db.Prune(dryRun)
+	if err != nil {
+		t.Fatalf("Prune dry run: %v", err)
+	}
+	if *counted != want {
+		t.Fatalf("dry run counted %+v, want %+v", *counted, want)
+	}
+	if n := count(t, db, "search_results"); n != 6 {
+		t.Fatalf("the dry run left %d search results, want all 6", n)
+	}
+
+	pruned, err := db.Prune(policy)
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	freed := pruned.FreedBytes
+	pruned.FreedBytes = 0
+	if *pruned != want || freed < 0 {
+		t.Fatalf("pruned %+v, freed %d bytes; want %+v", *pruned, freed, want)
+	}
+
+	for table, left := range map[string]int{"search_results": 5, "outreach_queue": 4, "activity_logs": 1, "diagnostics": 1} {
+		if n := count(t, db, table); n != left {
+			t.Errorf("%d rows left in %s, want %d", n, table, left)
+		}
+	}
+	if contacted, _ := db.IsProfileContacted("https://www.linkedin.com/in/never-contacted/", ""); contacted {
+		t.Error("the pruned profile counts as contacted")
+	}
+}
+
+func TestPruneKeepsWithoutRetention(t *testing.T) {
+	db := openTestDB(t)
+	seedPrunable(t, db)
+
+	pruned, err := db.Prune(PrunePolicy{})
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if pruned.Total() != 0 {
+		t.Fatalf("pruned %+v with no retention set", *pruned)
+	}
+}
+
+func TestSizeStats(t *testing.T) {
+	db := openTestDB(t)
+	seedPrunable(t, db)
+
+	tables, total, err := db.SizeStats()
+	if err != nil {
+		t.Fatalf("SizeStats: %v", err)
+	}
+	if total <= 0 {
+		t.Fatalf("database size %d", total)
+	}
+
+	var sum int64
+	rows := map[string]int64{}
+	for i, table := range tables {
+		sum += table.Bytes
+		rows[table.Name] = table.Rows
+		if i > 0 && table.Bytes > tables[i-1].Bytes {
+			t.Fatalf("%s is listed after the smaller %s", table.Name, tables[i-1].Name)
+		}
+	}
+	if rows["search_results"] != 6 || rows["diagnostics"] != 2 {
+		t.Fatalf("row counts %v", rows)
+	}
+	if sum > total {
+		t.Fatalf("tables take %d bytes of a %d byte database", sum, total)
+	}
+}
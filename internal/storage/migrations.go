@@ -0,0 +1,553 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrSchemaTooNew is returned when the database was migrated by a newer build
+// than this one; opening it could lose or misread data
+var ErrSchemaTooNew = errors.New("database schema is newer than this build supports")
+
+// migration is one versioned schema step. Steps run in order, each in its own
+// transaction together with its schema_version row; a step is either SQL
+// statements or a Go function for data fixes.
+type migration struct {
+	version     int
+	description string
+	statements  []string
+	apply       func(tx *DB) error
+}
+
+// migrations lists every schema step in order. Append new steps with the next
+// version; never edit or reorder a step that has shipped.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "initial schema",
+		statements: []string{
+			`CREATE TABLE IF NOT EXISTS connection_requests (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				profile_url TEXT NOT NULL UNIQUE,
+				profile_name TEXT,
+				job_title TEXT,
+				company TEXT,
+				note TEXT,
+				status TEXT DEFAULT 'pending',
+				sent_at DATETIME NOT NULL,
+				updated_at DATETIME NOT NULL
+			)`,
+			`CREATE TABLE IF NOT EXISTS messages (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				profile_url TEXT NOT NULL,
+				profile_name TEXT,
+				content TEXT NOT NULL,
+				sent_at DATETIME NOT NULL
+			)`,
+			`CREATE TABLE IF NOT EXISTS search_results (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				profile_url TEXT NOT NULL UNIQUE,
+				profile_name TEXT,
+				job_title TEXT,
+				company TEXT,
+				location TEXT,
+				found_at DATETIME NOT NULL,
+				contacted BOOLEAN DEFAULT 0
+			)`,
+			`CREATE TABLE IF NOT EXISTS activity_logs (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				action TEXT NOT NULL,
+				details TEXT,
+				timestamp DATETIME NOT NULL
+			)`,
+			`CREATE TABLE IF NOT EXISTS incoming_invites (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				profile_url TEXT NOT NULL UNIQUE,
+				inviter_name TEXT,
+				headline TEXT,
+				mutual_connections INTEGER DEFAULT 0,
+				decision TEXT NOT NULL,
+				reason TEXT,
+				decided_at DATETIME NOT NULL,
+				welcomed BOOLEAN DEFAULT 0
+			)`,
+			`CREATE TABLE IF NOT EXISTS scheduled_messages (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				profile_url TEXT NOT NULL,
+				profile_name TEXT,
+				job_title TEXT,
+				company TEXT,
+				kind TEXT NOT NULL,
+				due_at DATETIME NOT NULL,
+				status TEXT DEFAULT 'pending',
+				created_at DATETIME NOT NULL,
+				UNIQUE(profile_url, kind)
+			)`,
+			`CREATE TABLE IF NOT EXISTS planner_decisions (
+				date TEXT PRIMARY KEY,
+				backlog INTEGER NOT NULL,
+				connect_budget INTEGER NOT NULL,
+				reduced BOOLEAN DEFAULT 0,
+				reason TEXT,
+				decided_at DATETIME NOT NULL
+			)`,
+			`CREATE TABLE IF NOT EXISTS captcha_solves (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				challenge_type TEXT NOT NULL,
+				outcome TEXT NOT NULL,
+				latency_ms INTEGER NOT NULL,
+				cost REAL DEFAULT 0,
+				error TEXT,
+				created_at DATETIME NOT NULL
+			)`,
+			`CREATE TABLE IF NOT EXISTS action_slots (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				date TEXT NOT NULL,
+				slot_at DATETIME NOT NULL,
+				status TEXT NOT NULL DEFAULT 'pending'
+			)`,
+			`CREATE TABLE IF NOT EXISTS config_snapshots (
+				hash TEXT PRIMARY KEY,
+				snapshot TEXT NOT NULL,
+				created_at DATETIME NOT NULL
+			)`,
+			`CREATE TABLE IF NOT EXISTS active_time (
+				date TEXT PRIMARY KEY,
+				active_ms INTEGER NOT NULL DEFAULT 0
+			)`,
+			`CREATE TABLE IF NOT EXISTS navigations (
+				date TEXT PRIMARY KEY,
+				count INTEGER NOT NULL DEFAULT 0
+			)`,
+			`CREATE TABLE IF NOT EXISTS engagements (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				profile_url TEXT NOT NULL,
+				normalized_url TEXT NOT NULL UNIQUE,
+				action TEXT NOT NULL,
+				outcome TEXT NOT NULL,
+				post_url TEXT DEFAULT '',
+				engaged_at DATETIME NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_connection_requests_status ON connection_requests(status)`,
+			`CREATE INDEX IF NOT EXISTS idx_connection_requests_sent_at ON connection_requests(sent_at)`,
+			`CREATE INDEX IF NOT EXISTS idx_messages_sent_at ON messages(sent_at)`,
+			`CREATE INDEX IF NOT EXISTS idx_search_results_contacted ON search_results(contacted)`,
+			`CREATE INDEX IF NOT EXISTS idx_incoming_invites_decided_at ON incoming_invites(decided_at)`,
+			`CREATE INDEX IF NOT EXISTS idx_scheduled_messages_due_at ON scheduled_messages(status, due_at)`,
+			`CREATE INDEX IF NOT EXISTS idx_action_slots_date ON action_slots(date, slot_at)`,
+			`CREATE INDEX IF NOT EXISTS idx_engagements_engaged_at ON engagements(engaged_at)`,
+		},
+	},
+	{
+		// Columns that were added with addColumnIfMissing before the schema was versioned
+		version:     2,
+		description: "columns added before versioning",
+		apply: func(tx *DB) error {
+			columns := []struct {
+				table      string
+				column     string
+				definition string
+			}{
+				{"connection_requests", "name_resolution", "TEXT DEFAULT ''"},
+				{"connection_requests", "template_id", "TEXT DEFAULT ''"},
+				{"messages", "template_id", "TEXT DEFAULT ''"},
+				{"connection_requests", "campaign", "TEXT DEFAULT 'default'"},
+				{"search_results", "campaign", "TEXT DEFAULT 'default'"},
+				{"connection_requests", "flow_variant", "TEXT DEFAULT ''"},
+				{"connection_requests", "note_status", "TEXT DEFAULT ''"},
+				{"search_results", "skip_count", "INTEGER DEFAULT 0"},
+				{"search_results", "last_skipped_at", "DATETIME"},
+				{"search_results", "primary_title", "TEXT DEFAULT ''"},
+				{"search_results", "primary_company", "TEXT DEFAULT ''"},
+				{"connection_requests", "failure_reason", "TEXT DEFAULT ''"},
+				{"connection_requests", "attempts", "INTEGER DEFAULT 1"},
+				{"connection_requests", "normalized_url", "TEXT"},
+				{"search_results", "normalized_url", "TEXT"},
+				{"search_results", "summary", "TEXT DEFAULT ''"},
+				{"search_results", "mutual_connections", "INTEGER DEFAULT 0"},
+				{"search_results", "premium", "BOOLEAN DEFAULT 0"},
+				{"search_results", "open_to_work", "BOOLEAN DEFAULT 0"},
+				{"search_results", "lead_url", "TEXT DEFAULT ''"},
+				{"search_results", "source", "TEXT DEFAULT 'search'"},
+			}
+
+			for _, c := range columns {
+				if err := tx.addColumnIfMissing(c.table, c.column, c.definition); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		version:     3,
+		description: "activity run IDs",
+		statements: []string{
+			`ALTER TABLE activity_logs ADD COLUMN run_id TEXT DEFAULT ''`,
+			`CREATE INDEX IF NOT EXISTS idx_activity_logs_run_id ON activity_logs(run_id)`,
+		},
+	},
+	{
+		version:     4,
+		description: "connection request note usage",
+		statements: []string{
+			`ALTER TABLE connection_requests ADD COLUMN note_used BOOLEAN DEFAULT 0`,
+			// Before this, a request carried a note whenever one was recorded
+			`UPDATE connection_requests SET note_used = 1 WHERE note IS NOT NULL AND note != ''`,
+		},
+	},
+	{
+		version:     5,
+		description: "message sequences",
+		statements: []string{
+			`CREATE TABLE IF NOT EXISTS sequence_state (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				profile_url TEXT NOT NULL UNIQUE,
+				profile_name TEXT,
+				job_title TEXT,
+				company TEXT,
+				sequence TEXT NOT NULL,
+				step INTEGER NOT NULL DEFAULT 0,
+				status TEXT NOT NULL DEFAULT 'active',
+				next_due_at DATETIME NOT NULL,
+				pending_content TEXT DEFAULT '',
+				created_at DATETIME NOT NULL,
+				updated_at DATETIME NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_sequence_state_due ON sequence_state(status, next_due_at)`,
+		},
+	},
+	{
+		version:     6,
+		description: "message channels",
+		statements: []string{
+			`ALTER TABLE messages ADD COLUMN channel TEXT DEFAULT 'message'`,
+			`ALTER TABLE messages ADD COLUMN subject TEXT DEFAULT ''`,
+		},
+	},
+	{
+		version:     7,
+		description: "enriched profiles",
+		statements: []string{
+			`CREATE TABLE IF NOT EXISTS profiles (
+				normalized_url TEXT PRIMARY KEY,
+				profile_url TEXT NOT NULL,
+				name TEXT,
+				headline TEXT,
+				about TEXT,
+				location TEXT,
+				experience TEXT DEFAULT '[]',
+				education TEXT DEFAULT '',
+				followers INTEGER DEFAULT 0,
+				scraped_at DATETIME NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_profiles_scraped_at ON profiles(scraped_at)`,
+		},
+	},
+	{
+		version:     8,
+		description: "search result filters",
+		statements: []string{
+			`ALTER TABLE search_results ADD COLUMN filtered BOOLEAN DEFAULT 0`,
+			`ALTER TABLE search_results ADD COLUMN filter_reason TEXT DEFAULT ''`,
+		},
+	},
+	{
+		version:     9,
+		description: "organic prospect skips",
+		statements: []string{
+			`ALTER TABLE search_results ADD COLUMN organic_skipped_at DATETIME`,
+		},
+	},
+	{
+		version:     10,
+		description: "account viewports",
+		statements: []string{
+			`CREATE TABLE IF NOT EXISTS account_viewports (
+				account TEXT PRIMARY KEY,
+				label TEXT,
+				width INTEGER NOT NULL,
+				height INTEGER NOT NULL,
+				device_scale_factor REAL NOT NULL DEFAULT 1,
+				chosen_at DATETIME NOT NULL
+			)`,
+		},
+	},
+	{
+		version:     11,
+		description: "account fingerprints",
+		statements: []string{
+			`CREATE TABLE IF NOT EXISTS fingerprints (
+				account TEXT PRIMARY KEY,
+				user_agent TEXT DEFAULT '',
+				viewport_label TEXT DEFAULT '',
+				viewport_width INTEGER DEFAULT 0,
+				viewport_height INTEGER DEFAULT 0,
+				device_scale_factor REAL DEFAULT 1,
+				timezone TEXT DEFAULT '',
+				languages TEXT DEFAULT '',
+				platform TEXT DEFAULT '',
+				created_at DATETIME NOT NULL,
+				updated_at DATETIME NOT NULL
+			)`,
+			// Accounts keep the screen they were given; the rest is filled in on their next session
+			`INSERT OR IGNORE INTO fingerprints (account, viewport_label, viewport_width, viewport_height, device_scale_factor, created_at, updated_at)
+			 SELECT account, label, width, height, device_scale_factor, chosen_at, chosen_at FROM account_viewports`,
+			`DROP TABLE account_viewports`,
+		},
+	},
+	{
+		version:     12,
+		description: "fingerprint hardware and canvas seed",
+		statements: []string{
+			`ALTER TABLE fingerprints ADD COLUMN hardware_platform TEXT DEFAULT ''`,
+			`ALTER TABLE fingerprints ADD COLUMN webgl_vendor TEXT DEFAULT ''`,
+			`ALTER TABLE fingerprints ADD COLUMN webgl_renderer TEXT DEFAULT ''`,
+			`ALTER TABLE fingerprints ADD COLUMN hardware_concurrency INTEGER DEFAULT 0`,
+			`ALTER TABLE fingerprints ADD COLUMN device_memory REAL DEFAULT 0`,
+			`ALTER TABLE fingerprints ADD COLUMN canvas_seed INTEGER DEFAULT 0`,
+		},
+	},
+	{
+		version:     13,
+		description: "structured activity audit columns",
+		statements: []string{
+			`ALTER TABLE activity_logs ADD COLUMN profile_url TEXT`,
+			`ALTER TABLE activity_logs ADD COLUMN normalized_url TEXT`,
+			`ALTER TABLE activity_logs ADD COLUMN duration_ms INTEGER`,
+			`ALTER TABLE activity_logs ADD COLUMN result TEXT`,
+			`ALTER TABLE activity_logs ADD COLUMN error TEXT`,
+			`ALTER TABLE activity_logs ADD COLUMN page_url TEXT`,
+			`CREATE INDEX IF NOT EXISTS idx_activity_logs_normalized_url ON activity_logs(normalized_url)`,
+		},
+	},
+	{
+		version:     14,
+		description: "safe mode flag",
+		statements: []string{
+			`CREATE TABLE IF NOT EXISTS safe_mode (
+				id INTEGER PRIMARY KEY CHECK (id = 1),
+				reason TEXT NOT NULL,
+				run_id TEXT DEFAULT '',
+				entered_at DATETIME NOT NULL
+			)`,
+		},
+	},
+	{
+		version:     15,
+		description: "member IDs",
+		statements: []string{
+			`ALTER TABLE search_results ADD COLUMN member_id TEXT`,
+			`ALTER TABLE connection_requests ADD COLUMN member_id TEXT`,
+			`CREATE INDEX IF NOT EXISTS idx_search_results_member_id ON search_results(member_id)`,
+			`CREATE INDEX IF NOT EXISTS idx_connection_requests_member_id ON connection_requests(member_id)`,
+		},
+	},
+	{
+		version:     16,
+		description: "limits reported by LinkedIn",
+		statements: []string{
+			`CREATE TABLE IF NOT EXISTS limits_state (
+				name TEXT PRIMARY KEY,
+				remaining INTEGER NOT NULL,
+				observed_at DATETIME NOT NULL
+			)`,
+		},
+	},
+	{
+		version:     17,
+		description: "outreach queue",
+		statements: []string{
+			`CREATE TABLE IF NOT EXISTS outreach_queue (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				search_result_id INTEGER NOT NULL,
+				kind TEXT NOT NULL,
+				state TEXT NOT NULL,
+				priority INTEGER NOT NULL DEFAULT 0,
+				scheduled_for DATETIME,
+				attempts INTEGER NOT NULL DEFAULT 0,
+				last_error TEXT DEFAULT '',
+				created_at DATETIME NOT NULL,
+				updated_at DATETIME NOT NULL,
+				UNIQUE(search_result_id, kind)
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_outreach_queue_state ON outreach_queue(kind, state, scheduled_for)`,
+		},
+	},
+	{
+		version:     18,
+		description: "browser diagnostics",
+		statements: []string{
+			`CREATE TABLE IF NOT EXISTS diagnostics (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				kind TEXT NOT NULL,
+				status INTEGER NOT NULL DEFAULT 0,
+				url TEXT DEFAULT '',
+				message TEXT DEFAULT '',
+				page_url TEXT DEFAULT '',
+				run_id TEXT DEFAULT '',
+				created_at DATETIME NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_diagnostics_created_at ON diagnostics(created_at)`,
+		},
+	},
+	{
+		version:     19,
+		description: "search result degree of connection",
+		statements: []string{
+			`ALTER TABLE search_results ADD COLUMN degree INTEGER NOT NULL DEFAULT 0`,
+		},
+	},
+	{
+		version:     20,
+		description: "monitor-only search results and monitor runs",
+		statements: []string{
+			`ALTER TABLE search_results ADD COLUMN monitor_only INTEGER NOT NULL DEFAULT 0`,
+			`CREATE TABLE IF NOT EXISTS monitor_runs (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				monitor TEXT NOT NULL,
+				ran_at DATETIME NOT NULL,
+				seen INTEGER NOT NULL DEFAULT 0,
+				new INTEGER NOT NULL DEFAULT 0
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_monitor_runs_monitor ON monitor_runs(monitor, ran_at)`,
+		},
+	},
+	{
+		version:     21,
+		description: "pacing persona of each run",
+		statements: []string{
+			`CREATE TABLE IF NOT EXISTS run_personas (
+				run_id TEXT PRIMARY KEY,
+				name TEXT NOT NULL,
+				tempo REAL NOT NULL,
+				care REAL NOT NULL,
+				delays REAL NOT NULL,
+				typing REAL NOT NULL,
+				typos REAL NOT NULL,
+				scroll REAL NOT NULL,
+				started_at DATETIME NOT NULL
+			)`,
+		},
+	},
+	{
+		version:     22,
+		description: "search result status",
+		statements: []string{
+			`ALTER TABLE search_results ADD COLUMN status TEXT NOT NULL DEFAULT ''`,
+		},
+	},
+	{
+		version:     23,
+		description: "daily action budget",
+		statements: []string{
+			`CREATE TABLE IF NOT EXISTS action_budget (
+				date TEXT NOT NULL,
+				action TEXT NOT NULL,
+				used INTEGER NOT NULL DEFAULT 0,
+				PRIMARY KEY (date, action)
+			)`,
+		},
+	},
+	{
+		version:     24,
+		description: "profile tags and curation notes",
+		statements: []string{
+			`CREATE TABLE IF NOT EXISTS profile_tags (
+				normalized_url TEXT NOT NULL,
+				profile_url TEXT NOT NULL,
+				tag TEXT NOT NULL,
+				created_at DATETIME NOT NULL,
+				PRIMARY KEY (normalized_url, tag)
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_profile_tags_tag ON profile_tags(tag)`,
+			`ALTER TABLE search_results ADD COLUMN curation_note TEXT NOT NULL DEFAULT ''`,
+		},
+	},
+	{
+		// URLs that aren't LinkedIn profiles used to be keyed lowercased
+		version:     25,
+		description: "exact keys for non-profile URLs",
+		apply: func(tx *DB) error {
+			return tx.rekeyNonProfileURLs()
+		},
+	},
+}
+
+// SchemaVersion is the schema version this build migrates databases to
+func SchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// migrate brings the database up to SchemaVersion, applying each step it hasn't run
+// yet. Databases created before the schema was versioned start at version 0; the
+// first steps are idempotent so they adopt them as they are.
+func (db *DB) migrate() error {
+	if _, err := db.conn.Exec(`CREATE TABLE IF NOT EXISTS schema_version (
+		version INTEGER PRIMARY KEY,
+		description TEXT NOT NULL,
+		applied_at DATETIME NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_version: %w", err)
+	}
+
+	current, err := db.schemaVersion()
+	if err != nil {
+		return err
+	}
+	if current > SchemaVersion() {
+		return fmt.Errorf("%w: database is at version %d, this build knows up to %d", ErrSchemaTooNew, current, SchemaVersion())
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+
+		err := db.WithTx(func(tx *DB) error {
+			for _, statement := range m.statements {
+				if _, err := tx.conn.Exec(statement); err != nil {
+					return err
+				}
+			}
+			if m.apply != nil {
+				if err := m.apply(tx); err != nil {
+					return err
+				}
+			}
+			_, err := tx.conn.Exec(`INSERT INTO schema_version (version, description, applied_at) VALUES (?, ?, ?)`,
+				m.version, m.description, time.Now())
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.description, err)
+		}
+		db.migrated = append(db.migrated, fmt.Sprintf("%d (%s)", m.version, m.description))
+	}
+
+	// Rows may be written by other tools at any time, so this runs on every start
+	if err := db.normalizeProfileURLs(); err != nil {
+		return fmt.Errorf("migration failed: %w", err)
+	}
+	if err := db.syncQueue(); err != nil {
+		return fmt.Errorf("migration failed: %w", err)
+	}
+
+	return nil
+}
+
+// schemaVersion returns the highest version applied to the database, 0 for none
+func (db *DB) schemaVersion() (int, error) {
+	var version int
+	if err := db.conn.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_version`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// Migrated returns the migrations this connection applied when it opened the
+// database, as "version (description)"
+func (db *DB) Migrated() []string {
+	return db.migrated
+}
@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// openV1 creates a database at path as version 1 of the schema left it, with a request on record
+func openV1(t *testing.T, path string) {
+	t.Helper()
+	conn, err := sql.Open("sqlite", dsn(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	statements := append([]string{
+		`CREATE TABLE schema_version (version INTEGER PRIMARY KEY, description TEXT NOT NULL, applied_at DATETIME NOT NULL)`,
+	}, migrations[0].statements...)
+	for _, statement := range statements {
+		if _, err := conn.Exec(statement); err != nil {
+			t.Fatalf("%s: %v", statement, err)
+		}
+	}
+	if _, err := conn.Exec(`INSERT INTO schema_version VALUES (1, 'initial schema', ?)`, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	_, err = conn.Exec(`INSERT INTO connection_requests (profile_url, profile_name, note, status, sent_at, updated_at)
+		VALUES ('https://www.linkedin.com/in/ada-lovelace/', 'Ada Lovelace', 'Hi Ada', 'pending', ?, ?)`, time.Now(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// schema describes every table's columns and every index, in a stable order
+func schema(t *testing.T, db *DB) string {
+	t.Helper()
+	rows, err := db.sqlDB.Query(`SELECT type, name, tbl_name FROM sqlite_master WHERE name NOT LIKE 'sqlite_%' ORDER BY type, name`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type object struct{ kind, name, table string }
+	var objects []object
+	for rows.Next() {
+		var o object
+		if err := rows.Scan(&o.kind, &o.name, &o.table); err != nil {
+			t.Fatal(err)
+		}
+		objects = append(objects, o)
+	}
+	rows.Close()
+
+	var b strings.Builder
+	for _, o := range objects {
+		fmt.Fprintf(&b, "%s %s on %s\n", o.kind, o.name, o.table)
+		if o.kind != "table" {
+			continue
+		}
+		columns, err := db.sqlDB.Query(fmt.Sprintf("PRAGMA table_info(%s)", o.name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for columns.Next() {
+			var (
+				cid, notNull, pk int
+				name, colType    string
+				defaultVal       sql.NullString
+			)
+			if err := columns.Scan(&cid, &name, &colType, &notNull, &defaultVal, &pk); err != nil {
+				t.Fatal(err)
+			}
+			fmt.Fprintf(&b, "\t%s %s notnull=%d default=%s pk=%d\n", name, colType, notNull, defaultVal.String, pk)
+		}
+		columns.Close()
+	}
+	return b.String()
+}
+
+func TestMigrateV1MatchesFreshInstall(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "v1.db")
+	openV1(t, path)
+
+	upgraded, err := NewDB(path)
+	if err != nil {
+		t.Fatalf("NewDB on a v1 database: %v", err)
+	}
+	defer upgraded.Close()
+
+	if got := len(upgraded.Migrated()); got != SchemaVersion()-1 {
+		t.Fatalf("applied %d migrations, want %d: %v", got, SchemaVersion()-1, upgraded.Migrated())
+	}
+	if version, _ := upgraded.schemaVersion(); version != SchemaVersion() {
+		t.Fatalf("schema version = %d, want %d", version, SchemaVersion())
+	}
+
+	fresh := openTestDB(t)
+	if got, want := schema(t, upgraded), schema(t, fresh); got != want {
+		t.Fatalf("upgraded schema differs from a fresh install\nupgraded:\n%s\nfresh:\n%s", got, want)
+	}
+
+	// The data fixes ran on the rows already there
+	var noteUsed bool
+	var normalized sql.NullString
+	err = upgraded.sqlDB.QueryRow(`SELECT note_used, normalized_url FROM connection_requests`).Scan(&noteUsed, &normalized)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !noteUsed || normalized.String == "" {
+		t.Fatalf("note_used = %v, normalized_url = %q; want the request's note and URL carried over", noteUsed, normalized.String)
+	}
+
+	// Opening it again has nothing left to do
+	again, err := NewDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer again.Close()
+	if len(again.Migrated()) != 0 {
+		t.Fatalf("reopening applied %v", again.Migrated())
+	}
+}
+
+func TestMigrateRefusesNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "newer.db")
+	db, err := NewDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.sqlDB.Exec(`INSERT INTO schema_version VALUES (?, 'from a newer build', ?)`, SchemaVersion()+1, time.Now())
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if db, err := NewDB(path); !errors.Is(err, ErrSchemaTooNew) {
+		if db != nil {
+			db.Close()
+		}
+		t.Fatalf("NewDB = %v, want ErrSchemaTooNew", err)
+	}
+}
@@ -6,15 +6,22 @@ import (
 
 // ConnectionRequest represents a sent connection request
 type ConnectionRequest struct {
-	ID          int64
-	ProfileURL  string
-	ProfileName string
-	JobTitle    string
-	Company     string
-	Note        string
-	Status      string // pending, accepted, rejected, withdrawn
-	SentAt      time.Time
-	UpdatedAt   time.Time
+	ID             int64
+	ProfileURL     string
+	MemberID       string // LinkedIn's entity ID for the member, when known; the same under every URL
+	ProfileName    string
+	JobTitle       string
+	Company        string
+	Note           string
+	NoteUsed       bool   // the note was typed into the invite; counts towards the monthly note budget
+	Status         string // sending, pending, accepted, replied, rejected, withdrawn, skipped, failed, removed
+	NameResolution string // parsed, rescraped, fallback, skipped
+	TemplateID     string
+	FlowVariant    string // modal, bottom_sheet
+	NoteStatus     string // empty, or why the request went without a note (note_denied_upsell, note_opted_out, note_budget_used)
+	Campaign       string
+	SentAt         time.Time
+	UpdatedAt      time.Time
 }
 
 // Message represents a sent message
@@ -22,20 +29,214 @@ type Message struct {
 	ID          int64
 	ProfileURL  string
 	ProfileName string
+	Channel     string // message, or inmail for InMail to people outside the network
+	Subject     string // InMail subject line
 	Content     string
+	TemplateID  string
 	SentAt      time.Time
 }
 
+// Message channels
+const (
+	ChannelMessage = "message"
+	ChannelInMail  = "inmail"
+)
+
 // SearchResult represents a cached search result
 type SearchResult struct {
 	ID          int64
+	QueueID     int64 // its outreach queue item, as handed out by GetUncontactedProfiles
 	ProfileURL  string
+	MemberID    string // LinkedIn's entity ID for the member, when known
 	ProfileName string
 	JobTitle    string
 	Company     string
 	Location    string
+	Campaign    string
 	FoundAt     time.Time
 	Contacted   bool
+	SkipCount   int // times the prospect was passed over at selection
+
+	PrimaryTitle   string // role parsed from the headline in JobTitle
+	PrimaryCompany string // organization parsed from the headline
+
+	LeadURL           string // Sales Navigator lead URL; ProfileURL holds it too until the public URL is resolved
+	Summary           string // snippet shown under the headline, if any
+	MutualConnections int
+	Premium           bool
+	OpenToWork        bool
+	Source            string // search, group:<id> / event:<id> for harvested members, or company_page for company employees
+
+	Degree int // of connection when found; 0 when unknown
+
+	Filtered     bool   // rejected by the result filters; never selected for outreach
+	FilterReason string // the rule that rejected it
+
+	MonitorOnly bool // found by a monitor, which never contacts anyone; not queued for outreach
+}
+
+// Prospect orders for GetUncontactedProfiles
+const (
+	ProspectOrderNewest   = "newest"   // most recently found first
+	ProspectOrderOldest   = "oldest"   // in the order they were found
+	ProspectOrderRandom   = "random"   // shuffled
+	ProspectOrderPriority = "priority" // most mutual connections first
+)
+
+// ProspectPolicy decides which uncontacted profiles are handed out first
+type ProspectPolicy struct {
+	Order          string // one of the ProspectOrder constants; empty means newest
+	SkipOpenToWork bool
+
+	FoundAfter time.Time // when set, results found before it are left out, since people move on
+
+	Unengaged     bool      // only profiles not engaged with yet
+	EngagedBefore time.Time // when set, only profiles engaged with before it, or found to have nothing to engage with
+
+	OrganicSkippedBefore time.Time // when set, profiles skipped organically since then are left out
+
+	MaxDegree int // when set, profiles found further out in the network are left out; unknown degrees pass
+
+	BoostTags []string // profiles tagged with any of these go first, after the queue priority
+}
+
+// TemplateFields returns the job title and company to address the prospect by:
+// the ones parsed from the headline, falling back to the raw values
+func (r *SearchResult) TemplateFields() (string, string) {
+	title, company := r.PrimaryTitle, r.PrimaryCompany
+	if title == "" {
+		title = r.JobTitle
+	}
+	if company == "" {
+		company = r.Company
+	}
+	return title, company
+}
+
+// IncomingInvite represents a decision made on a received connection request
+type IncomingInvite struct {
+	ID                int64
+	ProfileURL        string
+	InviterName       string
+	Headline          string
+	MutualConnections int
+	Decision          string // accepted, ignored, left
+	Reason            string
+	DecidedAt         time.Time
+	Welcomed          bool
+}
+
+// ScheduledMessage represents a message queued to be sent at a later time
+type ScheduledMessage struct {
+	ID          int64
+	ProfileURL  string
+	ProfileName string
+	JobTitle    string
+	Company     string
+	Kind        string // acceptance
+	DueAt       time.Time
+	Status      string // pending, sent, cancelled
+	CreatedAt   time.Time
+}
+
+// SequenceState is an accepted connection's progress through a message sequence
+type SequenceState struct {
+	ID             int64
+	ProfileURL     string
+	ProfileName    string
+	JobTitle       string
+	Company        string
+	Sequence       string
+	Step           int    // index of the next step to send
+	Status         string // active, sending, completed, replied, cancelled
+	NextDueAt      time.Time
+	PendingContent string // the message whose Send click is in flight while sending
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// OutreachRecord is a prospect with its outreach state, used for reporting
+type OutreachRecord struct {
+	ProfileURL  string
+	ProfileName string
+	JobTitle    string
+	Company     string
+	Source      string // search, incoming_invite
+	Campaign    string // empty for incoming invites
+	Status      string // found, or the connection request status
+	Messaged    bool
+}
+
+// Engagement outcomes
+const (
+	EngagementLiked        = "liked"
+	EngagementAlreadyLiked = "already_liked"
+	EngagementNoActivity   = "no_activity"
+	EngagementFailed       = "failed"
+)
+
+// Engagement records the one interaction with a prospect before inviting them
+type Engagement struct {
+	ID         int64
+	ProfileURL string
+	Action     string // like
+	Outcome    string // one of the Engagement outcomes
+	PostURL    string
+	EngagedAt  time.Time
+}
+
+// Profile holds the details scraped from a profile page to qualify and address a prospect
+type Profile struct {
+	ProfileURL string
+	Name       string
+	Headline   string
+	About      string
+	Location   string
+	Experience []Position // most recent first, as listed on the profile
+	Education  []string   // school names
+	Followers  int
+	ScrapedAt  time.Time
+}
+
+// Position is one entry of a profile's Experience list
+type Position struct {
+	Title   string    `json:"title"`
+	Company string    `json:"company"`
+	Start   time.Time `json:"start"` // first of the start month; zero when not shown
+	Current bool      `json:"current"`
+}
+
+// CurrentPosition returns the first position still held, or nil
+func (p *Profile) CurrentPosition() *Position {
+	for i := range p.Experience {
+		if p.Experience[i].Current {
+			return &p.Experience[i]
+		}
+	}
+	return nil
+}
+
+// Fingerprint is the browser identity an account presents in every session
+type Fingerprint struct {
+	Account           string // the login email
+	UserAgent         string
+	ViewportLabel     string
+	ViewportWidth     int
+	ViewportHeight    int
+	DeviceScaleFactor float64
+	Timezone          string
+	Languages         []string
+	Platform          string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+
+	// Hardware the account's browser reports; zero until its next session fills it in
+	HardwarePlatform    string
+	WebGLVendor         string
+	WebGLRenderer       string
+	HardwareConcurrency int
+	DeviceMemory        float64
+	CanvasSeed          int64
 }
 
 // ActivityLog represents a logged activity
@@ -43,14 +244,147 @@ type ActivityLog struct {
 	ID        int64
 	Action    string // login, search, connect, message, etc.
 	Details   string
+	RunID     string // the run that logged it; empty for activity from before run IDs
 	Timestamp time.Time
+
+	// Set for audited actions; see DB.Audit
+	ProfileURL string
+	Duration   time.Duration // zero when not timed
+	Result     string
+	Error      string
+	PageURL    string
+}
+
+// SafeMode records why the bot stopped itself; no run starts while it is set
+type SafeMode struct {
+	Reason    string
+	RunID     string // the run that entered it
+	EnteredAt time.Time
+}
+
+// QueueItem is one piece of outreach work on a profile found by search
+type QueueItem struct {
+	ID           int64
+	Kind         string // one of the Queue kinds
+	State        string // one of the Queue states
+	Priority     int    // higher goes first
+	ScheduledFor time.Time
+	Attempts     int
+	LastError    string // why the last attempt failed, or why the item was skipped
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+
+	ProfileURL  string
+	ProfileName string
+	Campaign    string
+}
+
+// LimitState is what LinkedIn last reported of one of its limits
+type LimitState struct {
+	Name       string // one of the Limit names
+	Remaining  int    // actions LinkedIn said were left
+	ObservedAt time.Time
+}
+
+// Diagnostic is a browser-side error: a failed call to LinkedIn's API or a console error
+type Diagnostic struct {
+	ID        int64
+	Kind      string // one of the Diagnostic kinds
+	Status    int    // HTTP status of a failed call; 0 for console errors
+	URL       string // the failed call, or the script that logged the error
+	Message   string
+	PageURL   string // the page open at the time
+	RunID     string
+	CreatedAt time.Time
+}
+
+// MonitorRun records one run of a monitor's saved search
+type MonitorRun struct {
+	ID      int64
+	Monitor string
+	RanAt   time.Time
+	Seen    int // profiles its pages showed
+	New     int // of them never stored before, which the run reported
+}
+
+// RunPersona records the pace a run worked at, to compare against how its invites fared
+type RunPersona struct {
+	RunID     string
+	Name      string
+	Tempo     float64 // -1 quickest to 1 slowest
+	Care      float64 // -1 sloppiest to 1 most careful
+	Delays    float64 // factors the configured timing, typing and scrolling were scaled by
+	Typing    float64
+	Typos     float64
+	Scroll    float64
+	StartedAt time.Time
+}
+
+// PlannerDecision records how the day's connect budget was set
+type PlannerDecision struct {
+	Date          string // 2006-01-02
+	Backlog       int    // accepted connections awaiting a message
+	ConnectBudget int
+	Reduced       bool
+	Reason        string
+	DecidedAt     time.Time
+}
+
+// CaptchaSolve records one call to the CAPTCHA solving service
+type CaptchaSolve struct {
+	ID            int64
+	ChallengeType string
+	Outcome       string // solved, failed, timeout, budget_exhausted
+	LatencyMS     int64
+	Cost          float64
+	Error         string
+	CreatedAt     time.Time
+}
+
+// ActionSlot is a planned time for one connection request
+type ActionSlot struct {
+	ID     int64
+	Date   string // 2006-01-02
+	SlotAt time.Time
+	Status string // pending, used, missed
 }
 
 // DailyStats represents daily activity statistics
 type DailyStats struct {
-	Date              string
-	ConnectionsSent   int
+	Date                string
+	ConnectionsSent     int
 	ConnectionsAccepted int
-	MessagesSent      int
-	SearchesPerformed int
+	MessagesSent        int
+	RepliesReceived     int
+	SearchesPerformed   int
+}
+
+// FunnelStats counts profiles at each stage of the outreach funnel
+type FunnelStats struct {
+	Found     int
+	Contacted int
+	Accepted  int
+	Replied   int
+}
+
+// CampaignStats counts funnel stages for a single campaign
+type CampaignStats struct {
+	Campaign string
+	FunnelStats
+}
+
+// TemplateStats summarizes outcomes for a single note or message template
+type TemplateStats struct {
+	TemplateID string
+	Sent       int
+	Accepted   int
+	Replied    int
+}
+
+// Rate returns part as a percentage of whole, or 0 when whole is 0
+func Rate(part, whole int) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return float64(part) / float64(whole) * 100
 }
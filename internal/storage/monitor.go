@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// SaveMonitorRun records a run of a monitor
+func (db *DB) SaveMonitorRun(run *MonitorRun) error {
+	res, err := db.conn.Exec(`INSERT INTO monitor_runs (monitor, ran_at, seen, new) VALUES (?, ?, ?, ?)`, run.Monitor, run.RanAt, run.Seen, run.New)
+	if err != nil {
+		return fmt.Errorf("failed to save monitor run: %w", err)
+	}
+	run.ID, _ = res.LastInsertId()
+	return nil
+}
+
+// GetLastMonitorRun returns the latest run of a monitor, or nil if it never ran
+func (db *DB) GetLastMonitorRun(monitor string) (*MonitorRun, error) {
+	var run MonitorRun
+	err := db.conn.QueryRow(`SELECT id, monitor, ran_at, seen, new FROM monitor_runs WHERE monitor = ? ORDER BY ran_at DESC, id DESC LIMIT 1`, monitor).
+		Scan(&run.ID, &run.Monitor, &run.RanAt, &run.Seen, &run.New)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last monitor run: %w", err)
+	}
+	return &run, nil
+}
@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/profileurl"
+)
+
+// normalizedTables lists the tables keyed by profile URL with a normalized_url column.
+// rank orders the rows of one profile best first; when LinkedIn profile URLs that
+// differ only in spelling collide, the best row is kept and the rest are dropped.
+var normalizedTables = []struct {
+	table string
+	rank  string
+}{
+	{
+		table: "connection_requests",
+		rank: `CASE status WHEN 'replied' THEN 7 WHEN 'accepted' THEN 6 WHEN 'removed' THEN 6 WHEN 'pending' THEN 5
+				WHEN 'withdrawn' THEN 4 WHEN 'rejected' THEN 4 WHEN 'sending' THEN 3
+				WHEN 'failed' THEN 2 WHEN 'skipped' THEN 1 ELSE 0 END DESC, sent_at, id`,
+	},
+	{
+		table: "search_results",
+		rank:  `contacted DESC, id`,
+	},
+}
+
+// normalizeProfileURLs fills normalized_url for rows that lack it, such as rows written
+// before the column existed or inserted by other tools, merging rows that turn out to
+// be the same profile, and then enforces one row per normalized URL
+func (db *DB) normalizeProfileURLs() error {
+	for _, t := range normalizedTables {
+		var missing int
+		if err := db.conn.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE normalized_url IS NULL`, t.table)).Scan(&missing); err != nil {
+			return fmt.Errorf("failed to count unnormalized %s: %w", t.table, err)
+		}
+
+		if missing > 0 {
+			if err := db.normalizeTable(t.table, t.rank); err != nil {
+				return err
+			}
+		}
+
+		index := fmt.Sprintf(`CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_normalized_url ON %s(normalized_url)`, t.table, t.table)
+		if _, err := db.conn.Exec(index); err != nil {
+			return fmt.Errorf("failed to index normalized URLs of %s: %w", t.table, err)
+		}
+	}
+
+	return nil
+}
+
+// normalizeTable recomputes normalized_url for every row of table in one transaction,
+// keeping only the best-ranked row of each LinkedIn profile. Rows whose URL isn't a
+// LinkedIn profile are keyed by the URL as stored and never merged.
+func (db *DB) normalizeTable(table, rank string) error {
+	tx, err := db.sqlDB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin normalizing %s: %w", table, err)
+	}
+	defer tx.Rollback()
+
+	// Clear first so rows can move onto each other's normalized URL without tripping the index
+	if _, err := tx.Exec(fmt.Sprintf(`UPDATE %s SET normalized_url = NULL`, table)); err != nil {
+		return fmt.Errorf("failed to reset normalized URLs of %s: %w", table, err)
+	}
+
+	rows, err := tx.Query(fmt.Sprintf(`SELECT id, profile_url FROM %s ORDER BY %s`, table, rank))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", table, err)
+	}
+
+	type row struct {
+		id  int64
+		url string
+	}
+	var all []row
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.id, &r.url); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan %s: %w", table, err)
+		}
+		all = append(all, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", table, err)
+	}
+
+	kept := make(map[string]bool)
+	merged := 0
+	for _, r := range all {
+		normalized := profileKey(r.url)
+		if kept[normalized] {
+			if _, err := tx.Exec(fmt.Sprintf(`DELETE FROM %s WHERE id = ?`, table), r.id); err != nil {
+				return fmt.Errorf("failed to merge duplicate in %s: %w", table, err)
+			}
+			merged++
+			continue
+		}
+
+		kept[normalized] = true
+		if _, err := tx.Exec(fmt.Sprintf(`UPDATE %s SET normalized_url = ? WHERE id = ?`, table), normalized, r.id); err != nil {
+			return fmt.Errorf("failed to normalize URL in %s: %w", table, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit normalized URLs of %s: %w", table, err)
+	}
+
+	if merged > 0 {
+		db.LogActivity("profile_urls_merged", fmt.Sprintf("%s: merged %d duplicate rows", table, merged))
+	}
+	return nil
+}
+
+// keyedTables lists every table with a normalized_url column alongside its profile_url
+var keyedTables = []string{"connection_requests", "search_results", "activity_logs", "engagements", "profiles", "profile_tags"}
+
+// rekeyNonProfileURLs sets normalized_url to the URL as stored on rows whose URL isn't a
+// LinkedIn profile, which were keyed lowercased before profileKey
+func (db *DB) rekeyNonProfileURLs() error {
+	for _, table := range keyedTables {
+		rows, err := db.conn.Query(fmt.Sprintf(`SELECT rowid, profile_url, normalized_url FROM %s WHERE normalized_url IS NOT NULL`, table))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", table, err)
+		}
+
+		type rekey struct {
+			rowid int64
+			key   string
+		}
+		var changed []rekey
+		for rows.Next() {
+			var (
+				rowid      int64
+				url        sql.NullString
+				normalized string
+			)
+			if err := rows.Scan(&rowid, &url, &normalized); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan %s: %w", table, err)
+			}
+			if key := profileKey(url.String); url.Valid && key != "" && key != normalized {
+				changed = append(changed, rekey{rowid, key})
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to read %s: %w", table, err)
+		}
+
+		for _, r := range changed {
+			if _, err := db.conn.Exec(fmt.Sprintf(`UPDATE %s SET normalized_url = ? WHERE rowid = ?`, table), r.key, r.rowid); err != nil {
+				return fmt.Errorf("failed to rekey %s: %w", table, err)
+			}
+		}
+	}
+	return nil
+}
+
+// normalizedURL returns the lookup key for a profile URL, NULL when there is none
+func normalizedURL(profileURL string) sql.NullString {
+	normalized := profileKey(profileURL)
+	return sql.NullString{String: normalized, Valid: normalized != ""}
+}
+
+// profileKey returns the normalized form of a LinkedIn profile URL. Any other URL is
+// only trimmed: it may be case-sensitive, and two that merely look alike may be
+// different people.
+func profileKey(profileURL string) string {
+	if profileurl.IsProfile(profileURL) {
+		return profileurl.Normalize(profileURL)
+	}
+	return strings.TrimSpace(profileURL)
+}
+
+// nullMemberID returns a member ID for storage, NULL when it isn't known
+func nullMemberID(id string) sql.NullString {
+	return sql.NullString{String: id, Valid: id != ""}
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// requestKeys returns each connection request's URL mapped to its status and normalized URL
+func requestKeys(t *testing.T, db *DB) map[string][2]string {
+	t.Helper()
+	rows, err := db.sqlDB.Query(`SELECT profile_url, status, normalized_url FROM connection_requests`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	keys := make(map[string][2]string)
+	for rows.Next() {
+		var url, status, normalized string
+		if err := rows.Scan(&url, &status, &normalized); err != nil {
+			t.Fatal(err)
+		}
+		keys[url] = [2]string{status, normalized}
+	}
+	return keys
+}
+
+func TestNormalizeMergesOnlyLinkedInProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := NewDB(path)
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+
+	// Rows written by another tool, without a normalized URL
+	for _, r := range []struct{ url, status string }{
+		{"https://www.linkedin.com/in/grace-hopper/", "pending"},
+		{"https://uk.linkedin.com/in/Grace-Hopper?trk=people", "accepted"},
+		{"https://example.com/Team/Ada", "pending"},
+		{"https://example.com/team/ada", "accepted"},
+	} {
+		_, err := db.sqlDB.Exec(`INSERT INTO connection_requests (profile_url, profile_name, status, sent_at, updated_at) VALUES (?, '', ?, ?, ?)`,
+			r.url, r.status, time.Now(), time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.Close()
+
+	reopened, err := NewDB(path)
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	defer reopened.Close()
+
+	got := requestKeys(t, reopened)
+	want := map[string][2]string{
+		// One profile spelled two ways: the accepted request is kept
+		"https://uk.linkedin.com/in/Grace-Hopper?trk=people": {"accepted", "https://www.linkedin.com/in/grace-hopper"},
+		// Not LinkedIn profiles: both kept, each under its own URL
+		"https://example.com/Team/Ada": {"pending", "https://example.com/Team/Ada"},
+		"https://example.com/team/ada": {"accepted", "https://example.com/team/ada"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("requests = %v, want %v", got, want)
+	}
+	for url, w := range want {
+		if got[url] != w {
+			t.Fatalf("request %s = %v, want %v", url, got[url], w)
+		}
+	}
+}
+
+func TestRekeyNonProfileURLs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := NewDB(path)
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+
+	// As the last version left them: every URL keyed lowercased
+	for _, url := range []string{"https://www.linkedin.com/in/grace-hopper/", "https://www.linkedin.com/sales/lead/ACwAAB1xYz2,NAME_SEARCH"} {
+		if err := db.SaveConnectionRequest(&ConnectionRequest{ProfileURL: url, Status: "pending", SentAt: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.sqlDB.Exec(`UPDATE connection_requests SET normalized_url = LOWER(normalized_url)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.sqlDB.Exec(`DELETE FROM schema_version WHERE version = 25`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	reopened, err := NewDB(path)
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	defer reopened.Close()
+
+	got := requestKeys(t, reopened)
+	if key := got["https://www.linkedin.com/in/grace-hopper/"][1]; key != "https://www.linkedin.com/in/grace-hopper" {
+		t.Fatalf("profile key = %q, want it unchanged", key)
+	}
+	lead := "https://www.linkedin.com/sales/lead/ACwAAB1xYz2,NAME_SEARCH"
+	if key := got[lead][1]; key != lead {
+		t.Fatalf("lead key = %q, want the URL as stored", key)
+	}
+	if recorded, err := reopened.HasConnectionRequest(lead); err != nil || !recorded {
+		t.Fatalf("HasConnectionRequest(lead) = %v, %v, want it found", recorded, err)
+	}
+}
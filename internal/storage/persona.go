@@ -0,0 +1,13 @@
+package storage
+
+import "fmt"
+
+// SaveRunPersona records the persona a run was given
+func (db *DB) SaveRunPersona(p *RunPersona) error {
+	query := `INSERT OR REPLACE INTO run_personas (run_id, name, tempo, care, delays, typing, typos, scroll, started_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	if _, err := db.conn.Exec(query, p.RunID, p.Name, p.Tempo, p.Care, p.Delays, p.Typing, p.Typos, p.Scroll, p.StartedAt); err != nil {
+		return fmt.Errorf("failed to save run persona: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SaveProfile stores a scraped profile, replacing an earlier scrape of it
+func (db *DB) SaveProfile(p *Profile) error {
+	experience, err := json.Marshal(p.Experience)
+	if err != nil {
+		return fmt.Errorf("failed to encode experience: %w", err)
+	}
+
+	query := `INSERT INTO profiles (normalized_url, profile_url, name, headline, about, location, experience, education, followers, scraped_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			  ON CONFLICT(normalized_url) DO UPDATE SET profile_url = excluded.profile_url, name = excluded.name,
+				headline = excluded.headline, about = excluded.about, location = excluded.location,
+				experience = excluded.experience, education = excluded.education, followers = excluded.followers,
+				scraped_at = excluded.scraped_at`
+
+	_, err = db.conn.Exec(query, normalizedURL(p.ProfileURL), p.ProfileURL, p.Name, p.Headline, p.About, p.Location,
+		string(experience), strings.Join(p.Education, "\n"), p.Followers, p.ScrapedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save profile: %w", err)
+	}
+	return nil
+}
+
+// GetProfile returns the scraped details of a profile, or nil when it was never scraped
+func (db *DB) GetProfile(profileURL string) (*Profile, error) {
+	query := `SELECT profile_url, name, headline, about, location, experience, education, followers, scraped_at
+			  FROM profiles WHERE normalized_url = ?`
+
+	var p Profile
+	var experience, education string
+	err := db.conn.QueryRow(query, normalizedURL(profileURL)).Scan(&p.ProfileURL, &p.Name, &p.Headline, &p.About, &p.Location,
+		&experience, &education, &p.Followers, &p.ScrapedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(experience), &p.Experience); err != nil {
+		return nil, fmt.Errorf("failed to decode experience: %w", err)
+	}
+	if education != "" {
+		p.Education = strings.Split(education, "\n")
+	}
+	return &p, nil
+}
+
+// GetProfilesScrapedCountByDate returns how many profiles were scraped on a specific date
+func (db *DB) GetProfilesScrapedCountByDate(date time.Time) (int, error) {
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	var count int
+	err := db.conn.QueryRow(`SELECT COUNT(*) FROM profiles WHERE scraped_at >= ? AND scraped_at < ?`, startOfDay, endOfDay).Scan(&count)
+	return count, err
+}
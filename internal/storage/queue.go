@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Outreach queue states. An item is queued once its profile is found, in progress while
+// a worker acts on it, and done, skipped or failed once that is settled; a failure that
+// will be retried leaves it scheduled for later.
+const (
+	QueueQueued     = "queued"
+	QueueScheduled  = "scheduled"
+	QueueInProgress = "in_progress"
+	QueueDone       = "done"
+	QueueFailed     = "failed" // dead-lettered after the last attempt; requeue it by hand
+	QueueSkipped    = "skipped"
+)
+
+// QueueStates lists the queue states in the order an item moves through them
+var QueueStates = []string{QueueQueued, QueueScheduled, QueueInProgress, QueueDone, QueueFailed, QueueSkipped}
+
+// Queue kinds, one per worker
+const (
+	QueueConnect = "connect" // send the prospect a connection request
+)
+
+// RetryPolicy says how a failed queue item is retried
+type RetryPolicy struct {
+	MaxAttempts int           // attempts before the item is dead-lettered
+	Delay       time.Duration // before the first retry; it doubles with each further one
+}
+
+// syncQueue queues every search result that has no queue item yet, such as results saved
+// before the queue existed or inserted by other tools, and drops items whose search
+// result is gone
+func (db *DB) syncQueue() error {
+	now := time.Now()
+	query := `INSERT OR IGNORE INTO outreach_queue (search_result_id, kind, state, last_error, created_at, updated_at)
+			  SELECT s.id, ?, CASE WHEN s.contacted = 1 THEN ? WHEN s.filtered = 1 OR s.status = ? THEN ? ELSE ? END,
+			  CASE WHEN s.status = ? THEN ? ELSE COALESCE(s.filter_reason, '') END, ?, ?
+			  FROM search_results s
+			  WHERE s.monitor_only = 0 AND NOT EXISTS (SELECT 1 FROM outreach_queue q WHERE q.search_result_id = s.id AND q.kind = ?)`
+	if _, err := db.conn.Exec(query, QueueConnect, QueueDone, ProfileUnavailable, QueueSkipped, QueueQueued,
+		ProfileUnavailable, ProfileUnavailable, now, now, QueueConnect); err != nil {
+		return fmt.Errorf("failed to queue search results: %w", err)
+	}
+
+	if _, err := db.conn.Exec(`DELETE FROM outreach_queue WHERE search_result_id NOT IN (SELECT id FROM search_results)`); err != nil {
+		return fmt.Errorf("failed to drop orphaned queue items: %w", err)
+	}
+	return nil
+}
+
+// enqueueSearchResult queues a newly saved search result for a connection request;
+// results the filters rejected are queued as skipped
+func enqueueSearchResult(ex execer, result *SearchResult) error {
+	state, reason := QueueQueued, ""
+	if result.Contacted {
+		state = QueueDone
+	} else if result.Filtered {
+		state, reason = QueueSkipped, result.FilterReason
+	}
+
+	now := time.Now()
+	query := `INSERT OR IGNORE INTO outreach_queue (search_result_id, kind, state, last_error, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`
+	if _, err := ex.Exec(query, result.ID, QueueConnect, state, reason, now, now); err != nil {
+		return fmt.Errorf("failed to queue search result: %w", err)
+	}
+	return nil
+}
+
+// ClaimQueueItem marks a queue item in progress while a worker acts on it
+func (db *DB) ClaimQueueItem(id int64) error {
+	return db.setQueueState(id, QueueInProgress, nil)
+}
+
+// CompleteQueueItem settles a queue item as done or skipped, with the reason it was skipped
+func (db *DB) CompleteQueueItem(id int64, state, reason string) error {
+	return db.setQueueState(id, state, &reason)
+}
+
+// ReleaseQueueItem puts an item back in line untouched, e.g. when a limit stopped the
+// worker before it acted on it
+func (db *DB) ReleaseQueueItem(id int64) error {
+	return db.setQueueState(id, QueueQueued, nil)
+}
+
+// setQueueState moves a queue item to state, replacing its last error when reason is set
+func (db *DB) setQueueState(id int64, state string, reason *string) error {
+	query := `UPDATE outreach_queue SET state = ?, last_error = COALESCE(?, last_error), updated_at = ? WHERE id = ?`
+	if _, err := db.conn.Exec(query, state, reason, time.Now(), id); err != nil {
+		return fmt.Errorf("failed to update queue item: %w", err)
+	}
+	return nil
+}
+
+// FailQueueItem records a failed attempt at a queue item. It is scheduled for a retry
+// under policy, or dead-lettered as failed once its attempts are used up. It reports
+// whether the item will be retried.
+func (db *DB) FailQueueItem(id int64, cause error, policy RetryPolicy) (bool, error) {
+	var attempts int
+	if err := db.conn.QueryRow(`SELECT attempts FROM outreach_queue WHERE id = ?`, id).Scan(&attempts); err != nil {
+		return false, fmt.Errorf("failed to get queue item: %w", err)
+	}
+	attempts++
+
+	now := time.Now()
+	retry := attempts < policy.MaxAttempts
+	state, scheduledFor := QueueFailed, sql.NullTime{}
+	if retry {
+		state = QueueScheduled
+		scheduledFor = sql.NullTime{Time: now.Add(policy.Delay << (attempts - 1)), Valid: true}
+	}
+
+	query := `UPDATE outreach_queue SET state = ?, attempts = ?, scheduled_for = ?, last_error = ?, updated_at = ? WHERE id = ?`
+	if _, err := db.conn.Exec(query, state, attempts, scheduledFor, cause.Error(), now, id); err != nil {
+		return false, fmt.Errorf("failed to record queue item failure: %w", err)
+	}
+	return retry, nil
+}
+
+// RequeueInProgress puts items left in progress by an interrupted run back in line and
+// returns how many there were
+func (db *DB) RequeueInProgress() (int, error) {
+	res, err := db.conn.Exec(`UPDATE outreach_queue SET state = ?, updated_at = ? WHERE state = ?`, QueueQueued, time.Now(), QueueInProgress)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue items in progress: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// Requeue puts a queue item back in line with a fresh set of attempts and the given
+// priority. It reports false when there is no such item.
+func (db *DB) Requeue(id int64, priority int) (bool, error) {
+	query := `UPDATE outreach_queue SET state = ?, priority = ?, attempts = 0, scheduled_for = NULL, last_error = '', updated_at = ? WHERE id = ?`
+	res, err := db.conn.Exec(query, QueueQueued, priority, time.Now(), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to requeue item: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ListQueue returns up to limit queue items in state, or in any state when it is empty,
+// most recently updated first
+func (db *DB) ListQueue(state string, limit int) ([]QueueItem, error) {
+	query := `SELECT q.id, q.kind, q.state, q.priority, q.scheduled_for, q.attempts, COALESCE(q.last_error, ''), q.created_at, q.updated_at,
+			  s.profile_url, COALESCE(s.profile_name, ''), COALESCE(s.campaign, '')
+			  FROM outreach_queue q JOIN search_results s ON s.id = q.search_result_id
+			  WHERE (? = '' OR q.state = ?)
+			  ORDER BY q.updated_at DESC, q.id DESC LIMIT ?`
+
+	rows, err := db.conn.Query(query, state, state, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []QueueItem
+	for rows.Next() {
+		var item QueueItem
+		var scheduledFor sql.NullTime
+		if err := rows.Scan(&item.ID, &item.Kind, &item.State, &item.Priority, &scheduledFor, &item.Attempts, &item.LastError, &item.CreatedAt, &item.UpdatedAt,
+			&item.ProfileURL, &item.ProfileName, &item.Campaign); err != nil {
+			return nil, err
+		}
+		item.ScheduledFor = scheduledFor.Time
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// CountQueue returns the number of queue items in each state
+func (db *DB) CountQueue() (map[string]int, error) {
+	rows, err := db.conn.Query(`SELECT state, COUNT(*) FROM outreach_queue GROUP BY state`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var state string
+		var n int
+		if err := rows.Scan(&state, &n); err != nil {
+			return nil, err
+		}
+		counts[state] = n
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// reinviteStatuses are the request statuses a profile may be invited again from: an
+// invite withdrawn, or left pending. The cooldown runs from the request's updated_at.
+const reinviteStatuses = `('pending', 'withdrawn')`
+
+// SetReinviteCooldown lets a profile whose invite was withdrawn, or has sat pending, be
+// invited again once days have passed since; 0 never invites a profile twice
+func (db *DB) SetReinviteCooldown(days int) {
+	db.reinviteCooldownDays = days
+}
+
+// reinviteCutoff returns whether profiles are invited again at all, and the time a
+// withdrawn or pending request must date from at the latest to allow it
+func (db *DB) reinviteCutoff() (bool, time.Time) {
+	if db.reinviteCooldownDays <= 0 {
+		return false, time.Time{}
+	}
+	return true, time.Now().AddDate(0, 0, -db.reinviteCooldownDays)
+}
+
+// ReinviteEligibleAt returns when a profile whose request was withdrawn or left pending
+// may be invited again; ok is false when it has no such request or re-invites are off
+func (db *DB) ReinviteEligibleAt(profileURL string) (at time.Time, ok bool, err error) {
+	if db.reinviteCooldownDays <= 0 {
+		return time.Time{}, false, nil
+	}
+
+	var updatedAt time.Time
+	query := fmt.Sprintf(`SELECT updated_at FROM connection_requests WHERE normalized_url = ? AND status IN %s`, reinviteStatuses)
+	err = db.conn.QueryRow(query, normalizedURL(profileURL)).Scan(&updatedAt)
+	if err == sql.ErrNoRows {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to get connection request: %w", err)
+	}
+	return updatedAt.AddDate(0, 0, db.reinviteCooldownDays), true, nil
+}
+
+// MarkStillPending records that a profile's request still shows as pending on LinkedIn,
+// so its re-invite cooldown starts over
+func (db *DB) MarkStillPending(profileURL string) error {
+	query := fmt.Sprintf(`UPDATE connection_requests SET status = 'pending', updated_at = ? WHERE normalized_url = ? AND status IN %s`, reinviteStatuses)
+	_, err := db.conn.Exec(query, time.Now(), normalizedURL(profileURL))
+	return err
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// openTestDB opens a fresh database in a temporary directory
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+	db, err := NewDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// saveRequest stores a request for url in status, last updated at updated
+func saveRequest(t *testing.T, db *DB, url, status string, updated time.Time) {
+	t.Helper()
+	err := db.SaveConnectionRequest(&ConnectionRequest{ProfileURL: url, ProfileName: "Ada Lovelace", Status: status,
+		SentAt: updated, UpdatedAt: updated})
+	if err != nil {
+		t.Fatalf("SaveConnectionRequest: %v", err)
+	}
+}
+
+func TestReinviteCooldownBoundary(t *testing.T) {
+	const cooldown = 21
+	now := time.Now()
+
+	for _, tt := range []struct {
+		name      string
+		status    string
+		age       time.Duration // since the request was last updated, past the cooldown's days
+		cooldown  int
+		contacted bool
+	}{
+		{"withdrawn a day short", "withdrawn", -24 * time.Hour, cooldown, true},
+		{"withdrawn a minute short", "withdrawn", -time.Minute, cooldown, true},
+		{"withdrawn a minute past", "withdrawn", time.Minute, cooldown, false},
+		{"withdrawn a day past", "withdrawn", 24 * time.Hour, cooldown, false},
+		{"pending a minute short", "pending", -time.Minute, cooldown, true},
+		{"pending a minute past", "pending", time.Minute, cooldown, false},
+		{"accepted long ago", "accepted", 365 * 24 * time.Hour, cooldown, true},
+		{"re-invites off", "withdrawn", 365 * 24 * time.Hour, 0, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			db.SetReinviteCooldown(tt.cooldown)
+
+			url := "https://www.linkedin.com/in/ada-lovelace/"
+			updated := now.AddDate(0, 0, -cooldown).Add(-tt.age)
+			saveRequest(t, db, url, tt.status, updated)
+
+			contacted, err := db.IsProfileContacted(url, "")
+			if err != nil {
+				t.Fatalf("IsProfileContacted: %v", err)
+			}
+			if contacted != tt.contacted {
+				t.Fatalf("IsProfileContacted = %v, want %v", contacted, tt.contacted)
+			}
+
+			// A profile open for a re-invite takes a new request in place of the old one,
+			// and one still cooling down doesn't
+			err = db.SaveConnectionRequest(&ConnectionRequest{ProfileURL: url, ProfileName: "Ada Lovelace", Status: "sending",
+				SentAt: now, UpdatedAt: now})
+			if replaced := err == nil; replaced == tt.contacted {
+				t.Fatalf("saving a new request: %v, want it replaced %v", err, !tt.contacted)
+			}
+		})
+	}
+}
+
+func TestReinviteEligibleAt(t *testing.T) {
+	db := openTestDB(t)
+	db.SetReinviteCooldown(21)
+
+	// Across the end of a month: withdrawn on January 20th, eligible on February 10th
+	withdrawn := time.Date(2025, time.January, 20, 9, 30, 0, 0, time.Local)
+	saveRequest(t, db, "https://www.linkedin.com/in/ada-lovelace/", "withdrawn", withdrawn)
+	saveRequest(t, db, "https://www.linkedin.com/in/grace-hopper/", "accepted", withdrawn)
+
+	at, ok, err := db.ReinviteEligibleAt("https://www.linkedin.com/in/ada-lovelace")
+	if err != nil || !ok {
+		t.Fatalf("ReinviteEligibleAt = %v, %v", ok, err)
+	}
+	if want := time.Date(2025, time.February, 10, 9, 30, 0, 0, time.Local); !at.Equal(want) {
+		t.Fatalf("eligible at %s, want %s", at, want)
+	}
+
+	if _, ok, _ := db.ReinviteEligibleAt("https://www.linkedin.com/in/grace-hopper/"); ok {
+		t.Fatal("an accepted request is eligible for a re-invite")
+	}
+
+	// Finding the invite still pending starts the cooldown over
+	if err := db.MarkStillPending("https://www.linkedin.com/in/ada-lovelace/"); err != nil {
+		t.Fatalf("MarkStillPending: %v", err)
+	}
+	at, _, _ = db.ReinviteEligibleAt("https://www.linkedin.com/in/ada-lovelace/")
+	if want := time.Now().AddDate(0, 0, 21); at.Before(want.Add(-time.Minute)) || at.After(want) {
+		t.Fatalf("eligible at %s after the restart, want about %s", at, want)
+	}
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// EnterSafeMode sets the safe mode flag with its reason, replacing an earlier one
+func (db *DB) EnterSafeMode(reason string) error {
+	query := `INSERT INTO safe_mode (id, reason, run_id, entered_at) VALUES (1, ?, ?, ?)
+			  ON CONFLICT(id) DO UPDATE SET reason = excluded.reason, run_id = excluded.run_id, entered_at = excluded.entered_at`
+	if _, err := db.conn.Exec(query, reason, db.runID, time.Now()); err != nil {
+		return fmt.Errorf("failed to enter safe mode: %w", err)
+	}
+	db.Audit(AuditEntry{Action: "safe_mode_entered", Details: reason, Result: AuditSuccess})
+	return nil
+}
+
+// GetSafeMode returns the safe mode flag, or nil when it isn't set
+func (db *DB) GetSafeMode() (*SafeMode, error) {
+	var mode SafeMode
+	err := db.conn.QueryRow(`SELECT reason, COALESCE(run_id, ''), entered_at FROM safe_mode WHERE id = 1`).
+		Scan(&mode.Reason, &mode.RunID, &mode.EnteredAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get safe mode: %w", err)
+	}
+	return &mode, nil
+}
+
+// ClearSafeMode clears the safe mode flag and returns what it was, or nil when it wasn't set
+func (db *DB) ClearSafeMode() (*SafeMode, error) {
+	mode, err := db.GetSafeMode()
+	if err != nil || mode == nil {
+		return nil, err
+	}
+	if _, err := db.conn.Exec(`DELETE FROM safe_mode WHERE id = 1`); err != nil {
+		return nil, fmt.Errorf("failed to clear safe mode: %w", err)
+	}
+	db.Audit(AuditEntry{Action: "safe_mode_cleared", Details: mode.Reason, Result: AuditSuccess})
+	return mode, nil
+}
@@ -0,0 +1,37 @@
+package storage
+
+import "testing"
+
+func TestSafeModeFlag(t *testing.T) {
+	db := openTestDB(t)
+	db.SetRunID("run-1")
+
+	if mode, err := db.GetSafeMode(); err != nil || mode != nil {
+		t.Fatalf("GetSafeMode on a new database = %+v, %v", mode, err)
+	}
+
+	if err := db.EnterSafeMode("5 profile actions failed in a row"); err != nil {
+		t.Fatal(err)
+	}
+	db.SetRunID("run-2")
+	if err := db.EnterSafeMode("8 of the last 10 profile actions failed"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The latest reason replaces the earlier one
+	mode, err := db.GetSafeMode()
+	if err != nil || mode == nil || mode.Reason != "8 of the last 10 profile actions failed" || mode.RunID != "run-2" {
+		t.Fatalf("GetSafeMode = %+v, %v", mode, err)
+	}
+
+	cleared, err := db.ClearSafeMode()
+	if err != nil || cleared == nil || cleared.Reason != mode.Reason {
+		t.Fatalf("ClearSafeMode = %+v, %v", cleared, err)
+	}
+	if mode, _ := db.GetSafeMode(); mode != nil {
+		t.Fatalf("still in safe mode: %+v", mode)
+	}
+	if cleared, err := db.ClearSafeMode(); err != nil || cleared != nil {
+		t.Fatalf("clearing twice = %+v, %v", cleared, err)
+	}
+}
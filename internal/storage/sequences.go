@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// Sequence statuses
+const (
+	SequenceActive    = "active"
+	SequenceSending   = "sending" // write-ahead: a step's Send click is in flight
+	SequenceCompleted = "completed"
+	SequenceReplied   = "replied"   // the contact replied, no more steps go out
+	SequenceCancelled = "cancelled" // the contact can no longer be messaged
+)
+
+// StartSequence enrolls a profile in a sequence unless it was enrolled in one before.
+// It reports whether the profile was enrolled.
+func (db *DB) StartSequence(state *SequenceState) (bool, error) {
+	query := `INSERT OR IGNORE INTO sequence_state (profile_url, profile_name, job_title, company, sequence, step, status, next_due_at, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, 0, 'active', ?, ?, ?)`
+
+	now := time.Now()
+	res, err := db.conn.Exec(query, state.ProfileURL, state.ProfileName, state.JobTitle, state.Company, state.Sequence, state.NextDueAt, now, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to start sequence: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, nil
+	}
+
+	if id, err := res.LastInsertId(); err == nil {
+		state.ID = id
+	}
+	state.Status = SequenceActive
+	return true, nil
+}
+
+// GetDueSequenceStates returns active sequences whose next step is due at or before now
+func (db *DB) GetDueSequenceStates(now time.Time) ([]SequenceState, error) {
+	return db.getSequenceStates(`WHERE status = 'active' AND next_due_at <= ? ORDER BY next_due_at`, now)
+}
+
+// GetSequenceStatesByStatus returns the sequences in the given status
+func (db *DB) GetSequenceStatesByStatus(status string) ([]SequenceState, error) {
+	return db.getSequenceStates(`WHERE status = ? ORDER BY updated_at`, status)
+}
+
+// getSequenceStates returns the sequence rows matching the where clause
+func (db *DB) getSequenceStates(where string, args ...interface{}) ([]SequenceState, error) {
+	query := `SELECT id, profile_url, profile_name, job_title, company, sequence, step, status, next_due_at, pending_content, created_at, updated_at
+			  FROM sequence_state ` + where
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var states []SequenceState
+	for rows.Next() {
+		var s SequenceState
+		if err := rows.Scan(&s.ID, &s.ProfileURL, &s.ProfileName, &s.JobTitle, &s.Company, &s.Sequence, &s.Step, &s.Status,
+			&s.NextDueAt, &s.PendingContent, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		states = append(states, s)
+	}
+
+	return states, rows.Err()
+}
+
+// MarkSequenceSending records the message about to be sent for the sequence's current
+// step, so a crash after the Send click can be reconciled instead of sending it again
+func (db *DB) MarkSequenceSending(id int64, content string) error {
+	_, err := db.conn.Exec(`UPDATE sequence_state SET status = 'sending', pending_content = ?, updated_at = ? WHERE id = ?`,
+		content, time.Now(), id)
+	return err
+}
+
+// AdvanceSequence moves a sequence past a sent step: to the given step, due at nextDue,
+// or to completed when done
+func (db *DB) AdvanceSequence(id int64, step int, nextDue time.Time, done bool) error {
+	status := SequenceActive
+	if done {
+		status = SequenceCompleted
+	}
+	_, err := db.conn.Exec(`UPDATE sequence_state SET step = ?, status = ?, next_due_at = ?, pending_content = '', updated_at = ? WHERE id = ?`,
+		step, status, nextDue, time.Now(), id)
+	return err
+}
+
+// SetSequenceStatus updates the status of a sequence, clearing any in-flight message
+func (db *DB) SetSequenceStatus(id int64, status string) error {
+	_, err := db.conn.Exec(`UPDATE sequence_state SET status = ?, pending_content = '', updated_at = ? WHERE id = ?`,
+		status, time.Now(), id)
+	return err
+}
+
+// MarkConnectionReplied marks a profile's accepted request replied, reporting false when
+// the profile has no accepted request
+func (db *DB) MarkConnectionReplied(profileURL string) (bool, error) {
+	res, err := db.conn.Exec(`UPDATE connection_requests SET status = 'replied', updated_at = ? WHERE normalized_url = ? AND status = 'accepted'`,
+		time.Now(), normalizedURL(profileURL))
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -0,0 +1,46 @@
+package templates
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"math/rand"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+)
+
+// ID returns a short stable identifier for a template's text.
+// Hashing the text keeps IDs valid when templates are reordered in the config.
+func ID(template string) string {
+	if template == "" {
+		return ""
+	}
+
+	sum := sha1.Sum([]byte(template))
+	return hex.EncodeToString(sum[:])[:8]
+}
+
+// Pick selects a template, either uniformly or in proportion to its weight
+func Pick(r *rand.Rand, list []config.Template, weighted bool) config.Template {
+	if !weighted {
+		return list[r.Intn(len(list))]
+	}
+
+	total := 0.0
+	for _, t := range list {
+		total += t.Weight
+	}
+
+	if total <= 0 {
+		return list[r.Intn(len(list))]
+	}
+
+	target := r.Float64() * total
+	for _, t := range list {
+		target -= t.Weight
+		if target < 0 {
+			return t
+		}
+	}
+
+	return list[len(list)-1]
+}
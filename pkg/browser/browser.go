@@ -3,10 +3,14 @@ package browser
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/launcher/flags"
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/go-rod/stealth"
 )
@@ -14,19 +18,60 @@ import (
 // Browser wraps Rod browser with additional functionality
 type Browser struct {
 	browser *rod.Browser
-	page    *rod.Page
+	page    *rod.Page // the first page opened
 	timeout time.Duration
+
+	pages   []*openPage     // every page opened, the first one included
+	traffic *TrafficOptions // set by ShapeTraffic, which pages opened later follow too
+}
+
+// openPage is a page the browser opened, with its traffic meter
+type openPage struct {
+	page   *rod.Page
+	meter  *trafficMeter
+	router *rod.HijackRouter // aborts blocked requests; nil when nothing is blocked
+}
+
+// LaunchOptions make the launched browser agree with the fingerprint the pages present
+type LaunchOptions struct {
+	UserAgent    string
+	WindowWidth  int
+	WindowHeight int
+	Languages    []string // most preferred first
+	ExtraArgs    []string // --flag or --flag=value, applied last
 }
 
 // NewBrowser creates a new browser instance
-func NewBrowser(headless bool, userDataDir string, timeoutSeconds int) (*Browser, error) {
-	// Launch browser
+func NewBrowser(headless bool, userDataDir string, timeoutSeconds int, opts LaunchOptions) (*Browser, error) {
+	// Launch browser; new headless mode is the full browser, not the separate headless shell
 	l := launcher.New().
-		Headless(headless).
+		HeadlessNew(headless).
 		UserDataDir(userDataDir).
 		Leakless(false).
 		NoSandbox(true).
-		Set("disable-gpu")
+		Set("disable-gpu").
+		Delete("enable-automation").
+		Set("disable-blink-features", "AutomationControlled")
+
+	// Set the user agent at launch too, so nothing goes out with the HeadlessChrome one
+	if opts.UserAgent != "" {
+		l.Set("user-agent", opts.UserAgent)
+	}
+	if opts.WindowWidth > 0 && opts.WindowHeight > 0 {
+		l.Set("window-size", fmt.Sprintf("%d,%d", opts.WindowWidth, opts.WindowHeight))
+	}
+	if len(opts.Languages) > 0 {
+		l.Set("lang", opts.Languages[0])
+		l.Set("accept-lang", opts.Languages...)
+	}
+	for _, arg := range opts.ExtraArgs {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if hasValue {
+			l.Set(flags.Flag(name), value)
+		} else {
+			l.Set(flags.Flag(name))
+		}
+	}
 
 	// Print browser info for debugging
 	if path, exists := launcher.LookPath(); exists {
@@ -34,6 +79,10 @@ func NewBrowser(headless bool, userDataDir string, timeoutSeconds int) (*Browser
 		l.Bin(path)
 	}
 
+	args := l.FormatArgs()
+	slices.Sort(args)
+	logger.Debugf("Browser flags: %s", strings.Join(args, " "))
+
 	url, err := l.Launch()
 	if err != nil {
 		return nil, fmt.Errorf("failed to launch browser: %w", err)
@@ -54,7 +103,8 @@ func NewBrowser(headless bool, userDataDir string, timeoutSeconds int) (*Browser
 	}, nil
 }
 
-// NewPage creates a new page with stealth settings
+// NewPage creates a new page with stealth settings. The first page becomes the current one;
+// each page after it is another tab of the same browser.
 func (b *Browser) NewPage(userAgent string) (*rod.Page, error) {
 	page, err := stealth.Page(b.browser)
 	if err != nil {
@@ -71,7 +121,16 @@ func (b *Browser) NewPage(userAgent string) (*rod.Page, error) {
 	// Set timeout (disabled globally to avoid 'context deadline exceeded' on the whole page)
 	// page = page.Timeout(b.timeout)
 
-	b.page = page
+	opened := &openPage{page: page, meter: meterTraffic(page)}
+	b.pages = append(b.pages, opened)
+	if b.page == nil {
+		b.page = page
+	}
+	if b.traffic != nil {
+		if err := opened.shape(*b.traffic); err != nil {
+			logger.Warnf("Failed to shape network traffic of the new page: %v", err)
+		}
+	}
 	return page, nil
 }
 
@@ -112,10 +171,18 @@ func (b *Browser) Screenshot(path string) error {
 	return os.WriteFile(path, data, 0644)
 }
 
-// Close closes the browser
+// Close closes every page, then the browser
 func (b *Browser) Close() error {
-	if b.page != nil {
-		b.page.Close()
+	meters := make([]*trafficMeter, 0, len(b.pages))
+	for _, p := range b.pages {
+		if p.router != nil {
+			p.router.Stop()
+		}
+		meters = append(meters, p.meter)
+	}
+	logTraffic(meters)
+	for _, p := range b.pages {
+		p.page.Close()
 	}
 	if b.browser != nil {
 		return b.browser.Close()
@@ -168,8 +235,7 @@ func (b *Browser) Click(selector string) error {
 		return err
 	}
 
-	element.MustClick()
-	return nil
+	return element.Click(proto.InputMouseButtonLeft, 1)
 }
 
 // Type types text into an element
@@ -183,11 +249,14 @@ func (b *Browser) Type(selector, text string) error {
 }
 
 // GetCurrentURL returns the current page URL
-func (b *Browser) GetCurrentURL() string {
+func (b *Browser) GetCurrentURL() (string, error) {
 	if b.page == nil {
-		return ""
+		return "", nil
 	}
 
-	info := b.page.MustInfo()
-	return info.URL
+	info, err := b.page.Info()
+	if err != nil {
+		return "", fmt.Errorf("failed to get page info: %w", err)
+	}
+	return info.URL, nil
 }
@@ -0,0 +1,80 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/stealth"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/devices"
+)
+
+// closedClient is a CDP connection that attached to a page and then went away:
+// every call after that fails as a dropped websocket does
+type closedClient struct{}
+
+func (closedClient) Event() <-chan *cdp.Event { return nil }
+
+func (closedClient) Call(ctx context.Context, sessionID, method string, params interface{}) ([]byte, error) {
+	switch method {
+	case "Target.setDiscoverTargets", "Page.enable":
+		return []byte(`{}`), nil
+	case "Target.attachToTarget":
+		return []byte(`{"sessionId":"closed"}`), nil
+	}
+	return nil, &net.OpError{Op: "write", Net: "tcp", Err: net.ErrClosed}
+}
+
+// closedPage returns a page whose browser connection has dropped
+func closedPage(t *testing.T) *rod.Page {
+	t.Helper()
+	b := rod.New().Client(closedClient{}).DefaultDevice(devices.Clear)
+	if err := b.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	page, err := b.PageFromTarget("closed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return page
+}
+
+func TestClosedPageReturnsErrors(t *testing.T) {
+	page := closedPage(t)
+	b := &Browser{page: page}
+	scroller := stealth.NewScroller(1, 2, 0, 0)
+	mouse := stealth.NewMouseMover(page, 4, 0.3, 0, 0)
+
+	for name, call := range map[string]func() error{
+		"Browser.Click":                 func() error { return b.Click("button") },
+		"Browser.Type":                  func() error { return b.Type("input", "hi") },
+		"Browser.GetCurrentURL":         func() error { _, err := b.GetCurrentURL(); return err },
+		"Browser.GetText":               func() error { _, err := b.GetText("h1"); return err },
+		"Scroller.ScrollToTop":          func() error { return scroller.ScrollToTop(page) },
+		"Scroller.ScrollToBottom":       func() error { return scroller.ScrollToBottom(page) },
+		"Scroller.ScrollDown":           func() error { return scroller.ScrollDown(page, 300) },
+		"MouseMover.RandomIdleMovement": func() error { return mouse.RandomIdleMovement() },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("panicked on a closed page: %v", r)
+				}
+			}()
+			err := call()
+			if err == nil {
+				t.Fatal("succeeded on a closed page")
+			}
+			if !errors.Is(err, net.ErrClosed) || !NeedsRelaunch(err) {
+				t.Fatalf("error = %v, want the closed connection asking for a relaunch", err)
+			}
+		})
+	}
+
+	if b.HasElement("button") {
+		t.Fatal("a closed page has elements")
+	}
+}
@@ -0,0 +1,159 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
+)
+
+// ErrorKind names what went wrong in a rod/CDP call
+type ErrorKind string
+
+// Error kinds
+const (
+	KindTimeout             ErrorKind = "timeout"
+	KindNodeDetached        ErrorKind = "node_detached"
+	KindNavigationCanceled  ErrorKind = "navigation_canceled"
+	KindConnectionLost      ErrorKind = "connection_lost"
+	KindEvaluationException ErrorKind = "evaluation_exception"
+	KindElementNotFound     ErrorKind = "element_not_found"
+	KindUnknown             ErrorKind = "unknown"
+)
+
+// Handling says what a caller should do about an error
+type Handling string
+
+// Handlings
+const (
+	Retryable        Handling = "retryable"         // trying the same action again may succeed
+	Fatal            Handling = "fatal"             // the action failed; retrying won't help
+	RelaunchRequired Handling = "relaunch_required" // the browser or its connection is gone
+)
+
+// Classification is the kind of an error and how to handle it
+type Classification struct {
+	Kind     ErrorKind
+	Handling Handling
+}
+
+// Messages CDP returns for nodes that left the document between lookup and use
+var detachedMessages = []string{
+	"no node with given id",
+	"node with given id does not belong to the document",
+	"node is detached",
+	"could not find node with given id",
+	"could not find object with given id",
+	"cannot find context with specified id",
+	"execution context was destroyed",
+	"no node found at given location",
+}
+
+// Messages for a browser whose websocket or target went away
+var connectionMessages = []string{
+	"use of closed network connection",
+	"websocket: close",
+	"connection reset by peer",
+	"connection refused",
+	"broken pipe",
+	"session with given id not found",
+	"not attached to an active page",
+	"target closed",
+}
+
+// Classify maps an error from rod or the CDP connection to its kind and handling.
+// Errors that don't come from the browser are classified as unknown and fatal.
+func Classify(err error) Classification {
+	if err == nil {
+		return Classification{Kind: KindUnknown, Handling: Fatal}
+	}
+
+	kind := classifyKind(err)
+	return Classification{Kind: kind, Handling: handlingFor(kind)}
+}
+
+// NeedsRelaunch reports whether err means the browser has to be restarted
+func NeedsRelaunch(err error) bool {
+	return err != nil && Classify(err).Handling == RelaunchRequired
+}
+
+// classifyKind checks typed errors first and falls back to the CDP message text
+func classifyKind(err error) ErrorKind {
+	var (
+		navErr      *rod.NavigationError
+		evalErr     *rod.EvalError
+		notFoundErr *rod.ElementNotFoundError
+		objErr      *rod.ObjectNotFoundError
+		pageErr     *rod.PageNotFoundError
+		netErr      net.Error
+	)
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return KindTimeout
+	case errors.As(err, &pageErr),
+		errors.Is(err, io.EOF),
+		errors.Is(err, net.ErrClosed),
+		errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.EPIPE):
+		return KindConnectionLost
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return KindTimeout
+	case errors.As(err, &objErr):
+		return KindNodeDetached
+	case errors.As(err, &navErr):
+		return KindNavigationCanceled
+	case errors.As(err, &evalErr):
+		return KindEvaluationException
+	case errors.As(err, &notFoundErr):
+		return KindElementNotFound
+	}
+
+	// CDP errors compare by value, including Data, so match on the message instead
+	var cdpErr *cdp.Error
+	msg := strings.ToLower(err.Error())
+	if errors.As(err, &cdpErr) {
+		msg = strings.ToLower(cdpErr.Message)
+	}
+
+	switch {
+	case containsAny(msg, detachedMessages):
+		return KindNodeDetached
+	case containsAny(msg, connectionMessages):
+		return KindConnectionLost
+	case strings.Contains(msg, "net::err_aborted"), strings.Contains(msg, "navigation canceled"):
+		return KindNavigationCanceled
+	case strings.Contains(msg, "context deadline exceeded"):
+		return KindTimeout
+	}
+
+	return KindUnknown
+}
+
+// handlingFor returns how errors of kind should be handled
+func handlingFor(kind ErrorKind) Handling {
+	switch kind {
+	case KindTimeout, KindNodeDetached, KindNavigationCanceled:
+		return Retryable
+	case KindConnectionLost:
+		return RelaunchRequired
+	default:
+		return Fatal
+	}
+}
+
+// containsAny reports whether s contains any of subs
+func containsAny(s string, subs []string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func TestClassify(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		err  error
+		want Classification
+	}{
+		{"deadline", fmt.Errorf("failed to click: %w", context.DeadlineExceeded), Classification{KindTimeout, Retryable}},
+		{"network timeout", &net.OpError{Op: "read", Err: timeoutErr{}}, Classification{KindTimeout, Retryable}},
+		{"detached node", &cdp.Error{Code: -32000, Message: "No node with given id found"}, Classification{KindNodeDetached, Retryable}},
+		{"destroyed context", errors.New("{-32000 Execution context was destroyed. }"), Classification{KindNodeDetached, Retryable}},
+		{"object gone", &rod.ObjectNotFoundError{}, Classification{KindNodeDetached, Retryable}},
+		{"aborted navigation", &rod.NavigationError{Reason: "net::ERR_ABORTED"}, Classification{KindNavigationCanceled, Retryable}},
+		{"aborted navigation text", errors.New("navigation failed: net::ERR_ABORTED"), Classification{KindNavigationCanceled, Retryable}},
+		{"closed websocket", fmt.Errorf("failed to navigate: %w", io.EOF), Classification{KindConnectionLost, RelaunchRequired}},
+		{"reset connection", &net.OpError{Op: "read", Err: syscall.ECONNRESET}, Classification{KindConnectionLost, RelaunchRequired}},
+		{"closed target", &cdp.Error{Code: -32001, Message: "Session with given id not found."}, Classification{KindConnectionLost, RelaunchRequired}},
+		{"page gone", &rod.PageNotFoundError{}, Classification{KindConnectionLost, RelaunchRequired}},
+		{"script exception", &rod.EvalError{RuntimeExceptionDetails: &proto.RuntimeExceptionDetails{Text: "Uncaught", Exception: &proto.RuntimeRemoteObject{}}}, Classification{KindEvaluationException, Fatal}},
+		{"missing element", &rod.ElementNotFoundError{}, Classification{KindElementNotFound, Fatal}},
+		{"not from the browser", errors.New("daily limit reached"), Classification{KindUnknown, Fatal}},
+		{"nil", nil, Classification{KindUnknown, Fatal}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.err); got != tt.want {
+				t.Fatalf("Classify(%v) = %+v, want %+v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNeedsRelaunch(t *testing.T) {
+	if NeedsRelaunch(nil) {
+		t.Fatal("no error needs a relaunch")
+	}
+	if !NeedsRelaunch(fmt.Errorf("failed to search: %w", net.ErrClosed)) {
+		t.Fatal("a closed connection doesn't ask for a relaunch")
+	}
+	if NeedsRelaunch(context.DeadlineExceeded) {
+		t.Fatal("a timeout asks for a relaunch")
+	}
+}
+
+// timeoutErr is a network error that timed out
+type timeoutErr struct{}
+
+func (timeoutErr) Error() string   { return "i/o timeout" }
+func (timeoutErr) Timeout() bool   { return true }
+func (timeoutErr) Temporary() bool { return true }
@@ -0,0 +1,236 @@
+package browser
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// TrafficOptions shape the page's network traffic
+type TrafficOptions struct {
+	BlockResources []string // image, font, media and/or analytics
+	AllowHosts     []string // never blocked; a host also allows its subdomains
+	DownloadKbps   int      // 0 leaves the bandwidth alone
+	UploadKbps     int
+	LatencyMs      int
+}
+
+// blockableTypes maps the block_resources names to the resource types they abort
+var blockableTypes = map[string]proto.NetworkResourceType{
+	"image": proto.NetworkResourceTypeImage,
+	"font":  proto.NetworkResourceTypeFont,
+	"media": proto.NetworkResourceTypeMedia,
+}
+
+// analyticsHosts are the third-party trackers "analytics" blocks. LinkedIn's own
+// tracking is left alone: a session that never reports back would stand out.
+var analyticsHosts = []string{
+	"google-analytics.com",
+	"googletagmanager.com",
+	"doubleclick.net",
+	"bat.bing.com",
+	"scorecardresearch.com",
+	"facebook.net",
+	"hotjar.com",
+}
+
+// ShapeTraffic aborts the configured kinds of requests and emulates the configured
+// connection on every page, the pages opened later included
+func (b *Browser) ShapeTraffic(opts TrafficOptions) error {
+	b.traffic = &opts
+	for _, p := range b.pages {
+		if err := p.shape(opts); err != nil {
+			return err
+		}
+	}
+
+	if len(opts.BlockResources) > 0 {
+		logger.Infof("Blocking %s requests", strings.Join(opts.BlockResources, ", "))
+	}
+	if opts.DownloadKbps > 0 || opts.UploadKbps > 0 || opts.LatencyMs > 0 {
+		logger.Infof("Emulating a %d/%d kbps connection with %dms latency", opts.DownloadKbps, opts.UploadKbps, opts.LatencyMs)
+	}
+	return nil
+}
+
+// shape applies opts to the page
+func (p *openPage) shape(opts TrafficOptions) error {
+	if len(opts.BlockResources) > 0 {
+		router, err := blockRouter(p.page, opts, p.meter)
+		if err != nil {
+			return err
+		}
+		go router.Run()
+		p.router = router
+	}
+
+	if opts.DownloadKbps > 0 || opts.UploadKbps > 0 || opts.LatencyMs > 0 {
+		conditions := proto.NetworkEmulateNetworkConditions{
+			Latency:            float64(opts.LatencyMs),
+			DownloadThroughput: kbpsToBytes(opts.DownloadKbps),
+			UploadThroughput:   kbpsToBytes(opts.UploadKbps),
+		}
+		if err := (proto.NetworkEnable{}).Call(p.page); err != nil {
+			return fmt.Errorf("failed to enable network domain: %w", err)
+		}
+		if err := conditions.Call(p.page); err != nil {
+			return fmt.Errorf("failed to emulate network conditions: %w", err)
+		}
+	}
+	return nil
+}
+
+// blockRouter routes the requests to block to a handler that aborts them. Only those
+// requests are paused, so the rest of the traffic doesn't pass through here.
+func blockRouter(page *rod.Page, opts TrafficOptions, meter *trafficMeter) (*rod.HijackRouter, error) {
+	router := page.HijackRequests()
+	block := func(ctx *rod.Hijack) {
+		if allowedHost(ctx.Request.URL().Hostname(), opts.AllowHosts) {
+			ctx.ContinueRequest(&proto.FetchContinueRequest{})
+			return
+		}
+		meter.blocked()
+		ctx.Response.Fail(proto.NetworkErrorReasonBlockedByClient)
+	}
+
+	for _, kind := range opts.BlockResources {
+		if kind == "analytics" {
+			for _, host := range analyticsHosts {
+				err := router.Add("*"+host+"*", "", func(ctx *rod.Hijack) {
+					// The pattern matches anywhere in the URL; only the host counts
+					if !hostWithin(ctx.Request.URL().Hostname(), host) {
+						ctx.ContinueRequest(&proto.FetchContinueRequest{})
+						return
+					}
+					block(ctx)
+				})
+				if err != nil {
+					return nil, fmt.Errorf("failed to block %s: %w", host, err)
+				}
+			}
+			continue
+		}
+
+		resourceType, ok := blockableTypes[kind]
+		if !ok {
+			return nil, fmt.Errorf("unknown resource kind %q", kind)
+		}
+		if err := router.Add("*", resourceType, block); err != nil {
+			return nil, fmt.Errorf("failed to block %s requests: %w", kind, err)
+		}
+	}
+	return router, nil
+}
+
+// allowedHost reports whether host is one of allowHosts or a subdomain of one
+func allowedHost(host string, allowHosts []string) bool {
+	for _, allowed := range allowHosts {
+		if hostWithin(host, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// hostWithin reports whether host is domain or a subdomain of it
+func hostWithin(host, domain string) bool {
+	host, domain = strings.ToLower(host), strings.ToLower(domain)
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// kbpsToBytes converts kilobits per second to the bytes per second CDP takes; 0 is no limit
+func kbpsToBytes(kbps int) float64 {
+	if kbps <= 0 {
+		return -1
+	}
+	return float64(kbps) * 1000 / 8
+}
+
+// trafficMeter counts the bytes each page load transferred and the requests blocked
+type trafficMeter struct {
+	mu           sync.Mutex
+	url          string // the page being counted
+	pageBytes    float64
+	pageBlocked  int
+	pages        int
+	totalBytes   float64
+	totalBlocked int
+}
+
+// meterTraffic counts the page's traffic until the page closes
+func meterTraffic(page *rod.Page) *trafficMeter {
+	m := &trafficMeter{}
+	go page.EachEvent(func(e *proto.NetworkLoadingFinished) {
+		m.mu.Lock()
+		m.pageBytes += e.EncodedDataLength
+		m.mu.Unlock()
+	}, func(e *proto.PageFrameNavigated) {
+		if e.Frame.ParentID == "" {
+			m.finishPage(e.Frame.URL)
+		}
+	})()
+	return m
+}
+
+// blocked counts a blocked request
+func (m *trafficMeter) blocked() {
+	m.mu.Lock()
+	m.pageBlocked++
+	m.mu.Unlock()
+}
+
+// finishPage logs what the page being counted transferred and starts counting next
+func (m *trafficMeter) finishPage(next string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.url != "" {
+		logger.Debugf("Page %s transferred %s, %d requests blocked", pagePath(m.url), formatBytes(m.pageBytes), m.pageBlocked)
+		m.pages++
+		m.totalBytes += m.pageBytes
+		m.totalBlocked += m.pageBlocked
+	}
+	m.url, m.pageBytes, m.pageBlocked = next, 0, 0
+}
+
+// logTraffic logs the traffic of the whole session, across the meters of every tab
+func logTraffic(meters []*trafficMeter) {
+	var pages, blocked int
+	var bytes float64
+	for _, m := range meters {
+		m.finishPage("")
+
+		m.mu.Lock()
+		pages += m.pages
+		bytes += m.totalBytes
+		blocked += m.totalBlocked
+		m.mu.Unlock()
+	}
+	if pages == 0 {
+		return
+	}
+	logger.Infof("Network traffic: %d pages, %s transferred (%s per page), %d requests blocked",
+		pages, formatBytes(bytes), formatBytes(bytes/float64(pages)), blocked)
+}
+
+// pagePath drops the query from a page URL, which only makes the log line longer
+func pagePath(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return u.Host + u.Path
+}
+
+// formatBytes renders a byte count in KB or MB
+func formatBytes(n float64) string {
+	if n >= 1<<20 {
+		return fmt.Sprintf("%.1f MB", n/(1<<20))
+	}
+	return fmt.Sprintf("%.0f KB", n/(1<<10))
+}
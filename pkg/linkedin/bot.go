@@ -0,0 +1,45 @@
+package linkedin
+
+import (
+	"github.com/Tanukumar01/linkedin-automation/internal/auth"
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/instance"
+	"github.com/Tanukumar01/linkedin-automation/internal/session"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// The options and methods below take and return the bot's internal types, so only the
+// bot's own command, which runs its full workflow on a Client, can make use of them.
+
+// WithConfig runs the client with the bot's whole configuration instead of the shipped
+// defaults; WithStealthProfile and WithHeadless are then ignored
+func WithConfig(cfg *config.Config) Option {
+	return func(o *options) { o.config = cfg }
+}
+
+// WithDB has the client use an open database instead of the one at the storage path.
+// Close leaves it open.
+func WithDB(db *storage.DB) Option {
+	return func(o *options) { o.db = db }
+}
+
+// WithSessionOptions opens the browser with opts, e.g. to keep the account's fingerprint
+func WithSessionOptions(opts session.Options) Option {
+	return func(o *options) { o.session = opts }
+}
+
+// WithInstanceLock tells the client the caller already holds the account's instance
+// lock, so Login doesn't take it again
+func WithInstanceLock(lock *instance.Lock) Option {
+	return func(o *options) { o.lock = lock }
+}
+
+// Session returns the client's browser session and its tabs
+func (c *Client) Session() *session.Session {
+	return c.session
+}
+
+// Authenticator returns what signs the client in, to set up before Login
+func (c *Client) Authenticator() *auth.Authenticator {
+	return c.auth
+}
@@ -0,0 +1,318 @@
+// Package linkedin drives LinkedIn the way the bot does, for programs that embed it: a
+// Client opens one browser session with the bot's stealth behavior, safety limits and
+// storage, configured in code rather than by the YAML config file.
+package linkedin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/auth"
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/connections"
+	"github.com/Tanukumar01/linkedin-automation/internal/instance"
+	"github.com/Tanukumar01/linkedin-automation/internal/logger"
+	"github.com/Tanukumar01/linkedin-automation/internal/messaging"
+	"github.com/Tanukumar01/linkedin-automation/internal/pageops"
+	"github.com/Tanukumar01/linkedin-automation/internal/search"
+	"github.com/Tanukumar01/linkedin-automation/internal/session"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// Client is a browser session on one LinkedIn account. Its methods drive the tabs of one
+// browser with shared stealth components, so they must not be called concurrently.
+type Client struct {
+	cfg     *config.Config
+	db      *storage.DB
+	closeDB func() error // closes the database unless the caller opened it
+	session *session.Session
+	auth    *auth.Authenticator
+
+	// One client per account at a time: Login takes the lock kept next to the database,
+	// unless the caller already holds it
+	lockPath string
+	lock     *instance.Lock
+	ownsLock bool
+
+	onCheckpoint func(url string)
+	loggedIn     bool
+}
+
+// ErrLocked is returned by Login while another client, or the bot, works the account
+var ErrLocked = instance.ErrLocked
+
+// Profile is a LinkedIn member
+type Profile struct {
+	URL      string // the /in/ profile URL
+	Name     string
+	JobTitle string // the headline as shown
+	Company  string
+	Location string
+}
+
+// New opens the database and launches the browser. Close the client once done.
+func New(opts ...Option) (*Client, error) {
+	o := options{storagePath: DefaultStoragePath, cookieFile: DefaultCookieFile}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	switch {
+	case o.logger != nil:
+		logger.SetLogger(o.logger)
+	case logger.Log == nil:
+		logger.SetLogger(zap.NewNop())
+	}
+
+	cfg := o.config
+	if cfg == nil {
+		var err error
+		if cfg, err = config.Default(); err != nil {
+			return nil, err
+		}
+		cfg.Browser.Headless = o.headless
+		if o.stealth != nil {
+			applyStealth(cfg, *o.stealth)
+			if err := cfg.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid stealth profile: %w", err)
+			}
+		}
+	}
+
+	db := o.db
+	if db == nil {
+		if err := os.MkdirAll(filepath.Dir(o.storagePath), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create data directory: %w", err)
+		}
+		var err error
+		if db, err = storage.NewDB(o.storagePath); err != nil {
+			return nil, fmt.Errorf("failed to open storage: %w", err)
+		}
+		db.SetReinviteCooldown(cfg.Connections.ReinviteCooldownDays)
+	}
+	closeDB := func() error {
+		if o.db != nil {
+			return nil
+		}
+		return db.Close()
+	}
+
+	sessionOpts := o.session
+	if sessionOpts.Navigations == nil {
+		sessionOpts.Navigations = db
+	}
+	sess, err := session.Open(cfg, sessionOpts)
+	if err != nil {
+		closeDB()
+		return nil, err
+	}
+
+	authenticator := auth.NewAuthenticator(sess.Pages, sess.Typer, sess.Clicker, sess.Timing, o.cookieFile)
+	authenticator.SetAuditLog(db)
+
+	return &Client{
+		cfg:          cfg,
+		db:           db,
+		closeDB:      closeDB,
+		session:      sess,
+		auth:         authenticator,
+		lockPath:     o.storagePath,
+		lock:         o.lock,
+		onCheckpoint: o.onCheckpoint,
+	}, nil
+}
+
+// applyStealth sets the stealth settings of cfg to profile
+func applyStealth(cfg *config.Config, profile StealthProfile) {
+	timing := &cfg.Stealth.Timing
+	timing.ActionDelayMin = int(profile.ActionDelayMin.Seconds())
+	timing.ActionDelayMax = int(profile.ActionDelayMax.Seconds())
+	timing.ThinkTimeMin = int(profile.ThinkTimeMin.Seconds())
+	timing.ThinkTimeMax = int(profile.ThinkTimeMax.Seconds())
+
+	typing := &cfg.Stealth.Typing
+	typing.WPMMin = profile.TypingWPMMin
+	typing.WPMMax = profile.TypingWPMMax
+	typing.TypoProbability = profile.TypoProbability
+}
+
+// Close closes the browser and the database and lets another client sign in to the account
+func (c *Client) Close() error {
+	c.session.Close()
+	if c.ownsLock {
+		c.lock.Release()
+	}
+	return c.closeDB()
+}
+
+// Login signs in, reusing the saved session while it lasts. It must succeed before the
+// other methods are used.
+func (c *Client) Login(ctx context.Context, email, password string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if c.lock == nil {
+		lock, err := instance.Acquire(instance.Path(c.lockPath, email), email)
+		if err != nil {
+			return err
+		}
+		c.lock, c.ownsLock = lock, true
+	}
+
+	release := pageops.WithContext(c.session.Pages, ctx)
+	err := c.auth.Login(email, password)
+	release()
+	if err != nil {
+		return fmt.Errorf("failed to login: %w", err)
+	}
+
+	c.session.SetCheckpointGuard(pageops.NewCheckpointGuard(c.onCheckpoint, pageops.DefaultCheckpointTimeout))
+	c.session.Prepare(c.cfg.LinkedIn.UILanguage)
+	c.loggedIn = true
+	return nil
+}
+
+// ready reports why the client can't act now, if it can't
+func (c *Client) ready(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if !c.loggedIn {
+		return fmt.Errorf("not logged in")
+	}
+	return nil
+}
+
+// Filters select the people a search finds
+type Filters struct {
+	Keywords   []string
+	JobTitles  []string
+	Companies  []string
+	Locations  []string
+	MaxResults int // 0 for the shipped default
+}
+
+// Search runs a people search and returns the profiles found, which are also stored as
+// prospects. Cancelling ctx stops it between result pages with what was found so far.
+func (c *Client) Search(ctx context.Context, filters Filters) ([]Profile, error) {
+	if err := c.ready(ctx); err != nil {
+		return nil, err
+	}
+
+	searchCfg := c.cfg.Search
+	searchCfg.Sources = nil
+	searchCfg.Filters = config.Filters{
+		Keywords:  filters.Keywords,
+		JobTitles: filters.JobTitles,
+		Companies: filters.Companies,
+		Locations: filters.Locations,
+	}
+	if filters.MaxResults > 0 {
+		searchCfg.MaxResults = filters.MaxResults
+	}
+
+	s := c.session
+	tab := s.Tab(session.TabSearch)
+	searcher := search.NewSearcher(tab.Pages, &searchCfg, c.db, s.Timing, tab.Scroller, tab.Clicker)
+	searcher.SetCheckpoint(func() bool { return ctx.Err() != nil })
+
+	// Cancelling ctx also cuts short the result page in hand
+	release := pageops.WithContext(tab.Pages, ctx)
+	summary, err := searcher.Search()
+	release()
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]Profile, 0, len(summary.Results))
+	for _, r := range summary.Results {
+		company := r.Company
+		if company == "" {
+			company = r.Headline.Company
+		}
+		profiles = append(profiles, Profile{
+			URL:      r.URL,
+			Name:     r.Name,
+			JobTitle: r.JobTitle,
+			Company:  company,
+			Location: r.Location,
+		})
+	}
+	return profiles, ctx.Err()
+}
+
+// ConnectOptions adjust a connection request
+type ConnectOptions struct {
+	// Note goes with the request; it may use the placeholders of the bot's note templates,
+	// such as {{firstName}} and {{company}}. Empty sends the request without one.
+	Note string
+	// Campaign the request counts towards in the bot's statistics; empty for the default one
+	Campaign string
+}
+
+// ConnectResult says what became of a connection request
+type ConnectResult struct {
+	Sent   bool
+	Reason string // why the profile was skipped, when it wasn't sent
+	Note   string // the note as sent
+}
+
+// Connect sends profile a connection request, within the bot's daily and weekly limits
+func (c *Client) Connect(ctx context.Context, profile Profile, opts ConnectOptions) (*ConnectResult, error) {
+	if err := c.ready(ctx); err != nil {
+		return nil, err
+	}
+
+	connCfg := c.cfg.Connections
+	connCfg.OrganicSkipProbability = -1 // the caller chose the profile
+	connCfg.NoteMode = config.NoteModeNever
+	if opts.Note != "" {
+		connCfg.NoteMode = config.NoteModeAlways
+		connCfg.NoteTemplates = []config.Template{{Text: opts.Note, Weight: 1}}
+	}
+
+	s := c.session
+	tab := s.Tab(session.TabProfile)
+	manager := connections.NewConnectionManager(tab.Pages, &connCfg, c.db, s.Timing, tab.Typer, tab.Clicker, tab.Scroller)
+	manager.SetDailyLimitFunc(c.cfg.DailyConnectLimit)
+	manager.SetProfileReading(c.cfg.Stealth.ProfileReading)
+	if opts.Campaign != "" {
+		manager.SetCampaign(opts.Campaign)
+	}
+
+	// Cancelling ctx fails the next step of the invite flow, so a request Send wasn't
+	// clicked for is never sent
+	release := pageops.WithContext(tab.Pages, ctx)
+	result, err := manager.SendConnectionRequest(profile.URL, profile.Name, profile.JobTitle, profile.Company, false)
+	release()
+	if err != nil {
+		return nil, err
+	}
+	return &ConnectResult{
+		Sent:   result.Outcome == connections.OutcomeSent,
+		Reason: result.Reason,
+		Note:   result.Note,
+	}, nil
+}
+
+// Message sends text as it is to profile, who must be a connection, within the bot's
+// daily and hourly message limits
+func (c *Client) Message(ctx context.Context, profile Profile, text string) error {
+	if err := c.ready(ctx); err != nil {
+		return err
+	}
+
+	s := c.session
+	tab := s.Tab(session.TabMessaging)
+	manager := messaging.NewMessageManager(tab.Pages, &c.cfg.Messaging, c.db, s.Timing, tab.Typer, tab.Clicker, tab.Scroller)
+	manager.SetProfileTimeout(c.cfg.Connections.ProfileTimeout())
+
+	release := pageops.WithContext(tab.Pages, ctx)
+	defer release()
+	_, err := manager.SendText(profile.URL, profile.Name, text)
+	return err
+}
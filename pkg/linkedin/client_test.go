@@ -0,0 +1,46 @@
+package linkedin
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/instance"
+)
+
+func TestLoginRefusesLockedAccount(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "linkedin_bot.db")
+	lock, err := instance.Acquire(instance.Path(dbPath, "ada@example.com"), "ada@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lock.Release()
+
+	// The lock is checked before the browser is touched
+	c := &Client{lockPath: dbPath}
+	if err := c.Login(context.Background(), "ada@example.com", "hunter2"); !errors.Is(err, ErrLocked) {
+		t.Fatalf("Login = %v, want ErrLocked", err)
+	}
+	if c.lock != nil || c.loggedIn {
+		t.Fatal("a refused login holds the lock or counts as logged in")
+	}
+}
+
+func TestActionsNeedLogin(t *testing.T) {
+	c := &Client{}
+	ctx := context.Background()
+
+	if _, err := c.Search(ctx, Filters{Keywords: []string{"analyst"}}); err == nil {
+		t.Error("Search before Login succeeded")
+	}
+	if _, err := c.Connect(ctx, Profile{URL: "https://www.linkedin.com/in/ada-lovelace/"}, ConnectOptions{}); err == nil {
+		t.Error("Connect before Login succeeded")
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := c.Message(cancelled, Profile{}, "hi"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Message with a cancelled context = %v, want context.Canceled", err)
+	}
+}
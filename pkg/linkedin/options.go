@@ -0,0 +1,98 @@
+package linkedin
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Tanukumar01/linkedin-automation/internal/config"
+	"github.com/Tanukumar01/linkedin-automation/internal/instance"
+	"github.com/Tanukumar01/linkedin-automation/internal/session"
+	"github.com/Tanukumar01/linkedin-automation/internal/storage"
+)
+
+// DefaultStoragePath is where a client keeps its database unless WithStorage says otherwise.
+// It is the bot's own, so the two share daily limits and never contact a profile twice.
+const DefaultStoragePath = "data/linkedin_bot.db"
+
+// DefaultCookieFile is where a client saves the login session unless WithCookieFile says otherwise
+const DefaultCookieFile = "cookies.json"
+
+// StealthProfile sets the pace of the client's human-like behavior
+type StealthProfile struct {
+	ActionDelayMin, ActionDelayMax time.Duration // between actions, in whole seconds
+	ThinkTimeMin, ThinkTimeMax     time.Duration // before decisions such as a click, in whole seconds
+	TypingWPMMin, TypingWPMMax     int
+	TypoProbability                float64
+}
+
+// Stealth profiles to start from
+var (
+	// StealthDefault is the pace of the shipped configuration
+	StealthDefault = StealthProfile{
+		ActionDelayMin: 2 * time.Second, ActionDelayMax: 5 * time.Second,
+		ThinkTimeMin: 1 * time.Second, ThinkTimeMax: 3 * time.Second,
+		TypingWPMMin: 40, TypingWPMMax: 80,
+		TypoProbability: 0.05,
+	}
+
+	// StealthCautious takes about twice as long over everything
+	StealthCautious = StealthProfile{
+		ActionDelayMin: 4 * time.Second, ActionDelayMax: 10 * time.Second,
+		ThinkTimeMin: 2 * time.Second, ThinkTimeMax: 6 * time.Second,
+		TypingWPMMin: 25, TypingWPMMax: 50,
+		TypoProbability: 0.05,
+	}
+)
+
+// options collects what the Options passed to New set
+type options struct {
+	storagePath string
+	cookieFile  string
+	stealth     *StealthProfile
+	logger      *zap.Logger
+	headless    bool
+
+	onCheckpoint func(url string)
+
+	// Set by the bot's own command, which shares its configuration, database and lock
+	config  *config.Config
+	db      *storage.DB
+	session session.Options
+	lock    *instance.Lock
+}
+
+// Option configures a Client
+type Option func(*options)
+
+// WithStorage keeps prospects, sent requests and messages in the SQLite database at path
+func WithStorage(path string) Option {
+	return func(o *options) { o.storagePath = path }
+}
+
+// WithStealthProfile paces the client's delays and typing as profile says
+func WithStealthProfile(profile StealthProfile) Option {
+	return func(o *options) { o.stealth = &profile }
+}
+
+// WithLogger sends the client's logs to l. Without it, a client logs nothing unless the
+// program already set up the bot's logger.
+func WithLogger(l *zap.Logger) Option {
+	return func(o *options) { o.logger = l }
+}
+
+// WithHeadless runs the browser without a window
+func WithHeadless(headless bool) Option {
+	return func(o *options) { o.headless = headless }
+}
+
+// WithCookieFile saves the login session to path, so later clients skip the login form
+func WithCookieFile(path string) Option {
+	return func(o *options) { o.cookieFile = path }
+}
+
+// WithCheckpointHandler has fn told the URL of any security checkpoint LinkedIn shows after
+// login. The client waits for it to be resolved in the browser either way.
+func WithCheckpointHandler(fn func(url string)) Option {
+	return func(o *options) { o.onCheckpoint = fn }
+}